@@ -31,6 +31,15 @@ var logger = loggo.GetLogger("juju.api")
 // will run. It's a variable so it can be changed in tests.
 var PingPeriod = 1 * time.Minute
 
+// RateLimitRetries is the number of times a call refused by the rate
+// limits of the API server is retried before its error is returned, and
+// RateLimitMaxDelay is the longest the client waits before a retry.
+// They are variables so they can be changed in tests.
+var (
+	RateLimitRetries  = 5
+	RateLimitMaxDelay = 30 * time.Second
+)
+
 type State struct {
 	client *rpc.Conn
 	conn   *websocket.Conn
@@ -83,6 +92,10 @@ type State struct {
 	// certPool holds the cert pool that is used to authenticate the tls
 	// connections to the API.
 	certPool *x509.CertPool
+
+	// traceId holds the trace id sent with the requests made on the
+	// connection, if any.
+	traceId string
 }
 
 // Info encapsulates information about a server holding juju state and
@@ -351,14 +364,45 @@ func (s *State) Ping() error {
 // This fills out the rpc.Request on the given facade, version for a given
 // object id, and the specific RPC method. It marshalls the Arguments, and will
 // unmarshall the result into the response object that is supplied.
+//
+// Calls refused by the rate limits of the API server are retried, after
+// waiting as long as the server asks.
 func (s *State) APICall(facade string, version int, id, method string, args, response interface{}) error {
-	err := s.client.Call(rpc.Request{
+	req := rpc.Request{
 		Type:    facade,
 		Version: version,
 		Id:      id,
 		Action:  method,
-	}, args, response)
-	return params.ClientError(err)
+	}
+	for attempt := 0; ; attempt++ {
+		err := s.client.Call(req, args, response)
+		rerr, ok := err.(*rpc.RequestError)
+		if !ok || rerr.Code != rpc.CodeRateLimited || attempt >= RateLimitRetries {
+			return params.ClientError(err)
+		}
+		delay := rateLimitDelay(rerr.RetryAfter, attempt)
+		logger.Debugf("%s.%s call rate limited, retrying in %v", facade, method, delay)
+		select {
+		case <-time.After(delay):
+		case <-s.closed:
+			return params.ClientError(err)
+		}
+	}
+}
+
+// rateLimitDelay returns how long to wait before retrying a rate
+// limited call, given how long the server asked the client to wait and
+// the number of retries made so far. If the server did not say, the
+// delay doubles with each retry.
+func rateLimitDelay(retryAfter time.Duration, attempt int) time.Duration {
+	delay := retryAfter
+	if delay <= 0 {
+		delay = time.Second << uint(attempt)
+	}
+	if delay > RateLimitMaxDelay {
+		delay = RateLimitMaxDelay
+	}
+	return delay
 }
 
 func (s *State) Close() error {
@@ -389,6 +433,22 @@ func (s *State) Addr() string {
 	return s.addr
 }
 
+// TraceId returns the trace id sent with the requests made on the
+// connection, or the empty string if none is. The API server echoes
+// it in the errors it returns and logs it with the requests, so that
+// "juju debug-log --trace" can find the request and reply messages.
+func (s *State) TraceId() string {
+	return s.traceId
+}
+
+// SetTraceId sets the trace id sent with the requests made on the
+// connection from now on. Only connections made by commands are
+// traced, as the API server logs failed traced requests at WARNING.
+func (s *State) SetTraceId(traceId string) {
+	s.traceId = traceId
+	s.client.SetTraceId(traceId)
+}
+
 // EnvironTag returns the tag of the environment we are connected to.
 func (s *State) EnvironTag() (names.EnvironTag, error) {
 	return names.ParseEnvironTag(s.environTag)
@@ -9,6 +9,7 @@ import (
 	"io"
 	"net"
 	"strconv"
+	"time"
 
 	"golang.org/x/net/websocket"
 
@@ -21,6 +22,7 @@ import (
 	"github.com/juju/juju/api"
 	"github.com/juju/juju/apiserver/params"
 	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/rpc"
 	"github.com/juju/juju/version"
 )
 
@@ -222,6 +224,58 @@ func (s *apiclientSuite) TestServerRoot(c *gc.C) {
 	c.Assert(url, gc.Matches, "https://localhost:[0-9]+")
 }
 
+func (s *apiclientSuite) TestAPICallRateLimited(c *gc.C) {
+	s.PatchValue(&api.RateLimitRetries, 2)
+	s.PatchValue(&api.RateLimitMaxDelay, 10*time.Millisecond)
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{
+		"api-rate-limits": "Client.FullStatus=1",
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	st, err := api.Open(s.APIInfo(c), api.DialOpts{})
+	c.Assert(err, jc.ErrorIsNil)
+	defer st.Close()
+
+	_, err = st.Client().Status(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	// The next call is refused; it is retried, and the error of the
+	// last retry returned.
+	_, err = st.Client().Status(nil)
+	c.Assert(err, jc.Satisfies, params.IsCodeRateLimited)
+	c.Assert(err, gc.ErrorMatches, `rate limit exceeded for Client.FullStatus, retry after .*`)
+	// Other methods are not limited.
+	_, err = st.Client().EnvironmentGet()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *apiclientSuite) TestTraceId(c *gc.C) {
+	st, err := api.Open(s.APIInfo(c), api.DialOpts{})
+	c.Assert(err, jc.ErrorIsNil)
+	defer st.Close()
+	c.Assert(st.TraceId(), gc.Equals, "")
+
+	// Connections are not traced unless asked to be.
+	req := rpc.Request{Type: "Client", Version: 0, Action: "ServiceGet"}
+	args := params.ServiceGet{ServiceName: "no-such-service"}
+	err = st.RPCClient().Call(req, args, nil)
+	c.Assert(err, gc.FitsTypeOf, &rpc.RequestError{})
+	c.Assert(err.(*rpc.RequestError).TraceId, gc.Equals, "")
+
+	// The server echoes the trace id of the connection in errors.
+	st.SetTraceId("a-trace")
+	c.Assert(st.TraceId(), gc.Equals, "a-trace")
+	err = st.RPCClient().Call(req, args, nil)
+	c.Assert(err, gc.FitsTypeOf, &rpc.RequestError{})
+	c.Assert(err.(*rpc.RequestError).TraceId, gc.Equals, "a-trace")
+}
+
+func (s *apiclientSuite) TestRateLimitDelay(c *gc.C) {
+	c.Assert(api.RateLimitDelay(5*time.Second, 0), gc.Equals, 5*time.Second)
+	c.Assert(api.RateLimitDelay(0, 0), gc.Equals, time.Second)
+	c.Assert(api.RateLimitDelay(0, 3), gc.Equals, 8*time.Second)
+	c.Assert(api.RateLimitDelay(0, 10), gc.Equals, api.RateLimitMaxDelay)
+	c.Assert(api.RateLimitDelay(time.Hour, 0), gc.Equals, api.RateLimitMaxDelay)
+}
+
 func (s *apiclientSuite) TestDialWebsocketStopped(c *gc.C) {
 	stopped := make(chan struct{})
 	f := api.NewWebsocketDialer(nil, api.DialOpts{})
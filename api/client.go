@@ -89,16 +89,17 @@ type MachineStatus struct {
 
 // ServiceStatus holds status info about a service.
 type ServiceStatus struct {
-	Err           error
-	Charm         string
-	Exposed       bool
-	Life          string
-	Relations     map[string][]string
-	Networks      NetworksSpecification
-	CanUpgradeTo  string
-	SubordinateTo []string
-	Units         map[string]UnitStatus
-	Status        AgentStatus
+	Err                 error
+	Charm               string
+	Exposed             bool
+	Life                string
+	Relations           map[string][]string
+	Networks            NetworksSpecification
+	CanUpgradeTo        string
+	SubordinateTo       []string
+	Units               map[string]UnitStatus
+	Status              AgentStatus
+	PlacementViolations []string
 }
 
 // UnitStatusHistory holds a slice of statuses.
@@ -165,6 +166,18 @@ type Status struct {
 	Services        map[string]ServiceStatus
 	Networks        map[string]NetworkStatus
 	Relations       []RelationStatus
+	RollingUpgrade  *RollingUpgradeStatus
+}
+
+// RollingUpgradeStatus holds status info about the rolling agent
+// upgrade in progress in an environment.
+type RollingUpgradeStatus struct {
+	From      string
+	To        string
+	BatchSize int
+	Machines  []string
+	Paused    bool
+	Reason    string
 }
 
 // Status returns the status of the juju environment.
@@ -196,6 +209,24 @@ func (c *Client) UnitStatusHistory(kind params.HistoryKind, unitName string, siz
 	return &results, nil
 }
 
+// HookStats retrieves the last <size> hook, action and command
+// executions recorded for <unitName>, most recent first.
+func (c *Client) HookStats(unitName string, size int) ([]params.HookExecution, error) {
+	var result params.HookStatsResult
+	args := params.HookStats{
+		Name: unitName,
+		Size: size,
+	}
+	err := c.facade.FacadeCall("HookStats", args, &result)
+	if err != nil {
+		if params.IsCodeNotImplemented(err) {
+			return nil, errors.NotImplementedf("HookStats")
+		}
+		return nil, errors.Trace(err)
+	}
+	return result.Executions, nil
+}
+
 // LegacyMachineStatus holds just the instance-id of a machine.
 type LegacyMachineStatus struct {
 	InstanceId string // Not type instance.Id just to match original api.
@@ -302,12 +333,38 @@ func (c *Client) AddRelation(endpoints ...string) (*params.AddRelationResults, e
 	return &addRelRes, err
 }
 
+// ApplyChanges validates the given ordered list of changes to the
+// environment and, if they are all valid, applies them in order,
+// returning the result of each change. No change is applied if any is
+// invalid. If a change fails, the changes after it are not applied
+// and those before it are undone as far as possible.
+func (c *Client) ApplyChanges(changes ...params.EnvironmentChange) ([]params.ApplyChangeResult, error) {
+	args := params.ApplyChanges{Changes: changes}
+	var results params.ApplyChangesResults
+	err := c.facade.FacadeCall("ApplyChanges", args, &results)
+	return results.Results, err
+}
+
 // DestroyRelation removes the relation between the specified endpoints.
 func (c *Client) DestroyRelation(endpoints ...string) error {
 	params := params.DestroyRelation{Endpoints: endpoints}
 	return c.facade.FacadeCall("DestroyRelation", params, nil)
 }
 
+// OpenDebugHooksSession opens a streaming debug-hooks session on a
+// unit, or renews the unit's session. The session expires unless it
+// is renewed at least once a minute.
+func (c *Client) OpenDebugHooksSession(session params.DebugHooksSession) error {
+	return c.facade.FacadeCall("OpenDebugHooksSession", session, nil)
+}
+
+// CloseDebugHooksSession closes the debug-hooks session of the
+// named unit.
+func (c *Client) CloseDebugHooksSession(unitName string) error {
+	args := params.DebugHooksSession{UnitName: unitName}
+	return c.facade.FacadeCall("CloseDebugHooksSession", args, nil)
+}
+
 // ServiceCharmRelations returns the service's charms relation names.
 func (c *Client) ServiceCharmRelations(service string) ([]string, error) {
 	var results params.ServiceCharmRelationsResults
@@ -363,6 +420,25 @@ func (c *Client) ForceDestroyMachines(machines ...string) error {
 	return c.facade.FacadeCall("DestroyMachines", params, nil)
 }
 
+// MachinePools returns the environment's spare machine pools.
+func (c *Client) MachinePools() ([]params.MachinePool, error) {
+	var result params.MachinePoolsResult
+	err := c.facade.FacadeCall("MachinePools", nil, &result)
+	return result.Pools, err
+}
+
+// SetMachinePool creates a spare machine pool, or changes the size,
+// series and constraints of an existing one.
+func (c *Client) SetMachinePool(pool params.MachinePool) error {
+	return c.facade.FacadeCall("SetMachinePool", pool, nil)
+}
+
+// RemoveMachinePool removes the named spare machine pool.
+func (c *Client) RemoveMachinePool(name string) error {
+	args := params.RemoveMachinePool{Name: name}
+	return c.facade.FacadeCall("RemoveMachinePool", args, nil)
+}
+
 // ServiceExpose changes the juju-managed firewall to expose any ports that
 // were also explicitly marked by units as open.
 func (c *Client) ServiceExpose(service string) error {
@@ -433,6 +509,34 @@ func (c *Client) ServiceSetCharm(serviceName string, charmUrl string, force bool
 	return c.facade.FacadeCall("ServiceSetCharm", args, nil)
 }
 
+// ServiceSetCharmInBatches sets the charm for a given service,
+// upgrading only canary units at first; the remaining units are
+// upgraded batchSize at a time once the upgraded units are active.
+func (c *Client) ServiceSetCharmInBatches(serviceName string, charmUrl string, force bool, canary, batchSize int) error {
+	args := params.ServiceSetCharm{
+		ServiceName: serviceName,
+		CharmUrl:    charmUrl,
+		Force:       force,
+		Canary:      canary,
+		BatchSize:   batchSize,
+	}
+	return c.facade.FacadeCall("ServiceSetCharm", args, nil)
+}
+
+// ServiceRollbackCharm changes the charm of a service back to the one
+// it ran before its last upgrade.
+func (c *Client) ServiceRollbackCharm(serviceName string) error {
+	args := params.ServiceCharmRollout{ServiceName: serviceName}
+	return c.facade.FacadeCall("ServiceRollbackCharm", args, nil)
+}
+
+// ServiceResumeCharmRollout resumes the paused charm rollout of a
+// service.
+func (c *Client) ServiceResumeCharmRollout(serviceName string) error {
+	args := params.ServiceCharmRollout{ServiceName: serviceName}
+	return c.facade.FacadeCall("ServiceResumeCharmRollout", args, nil)
+}
+
 // ServiceGetCharmURL returns the charm URL the given service is
 // running at present.
 func (c *Client) ServiceGetCharmURL(serviceName string) (*charm.URL, error) {
@@ -640,8 +744,18 @@ type WatchAll struct {
 // WatchAll returns an AllWatcher, from which you can request the Next
 // collection of Deltas.
 func (c *Client) WatchAll() (*AllWatcher, error) {
+	return c.WatchAllFiltered(multiwatcher.Filter{})
+}
+
+// WatchAllFiltered returns an AllWatcher like WatchAll, but whose
+// Deltas are restricted by the API server to the entities and fields
+// selected by the given filter. Filtering by service does not restrict
+// machines, blocks, or the annotations of machines and of the
+// environment; see multiwatcher.Filter.
+func (c *Client) WatchAllFiltered(filter multiwatcher.Filter) (*AllWatcher, error) {
 	info := new(WatchAll)
-	if err := c.facade.FacadeCall("WatchAll", nil, info); err != nil {
+	args := params.WatchAll{Filter: filter}
+	if err := c.facade.FacadeCall("WatchAll", args, info); err != nil {
 		return nil, err
 	}
 	return newAllWatcher(c.st, &info.AllWatcherId), nil
@@ -701,6 +815,24 @@ func (c *Client) SetEnvironAgentVersion(version version.Number) error {
 	return c.facade.FacadeCall("SetEnvironAgentVersion", args, nil)
 }
 
+// SetEnvironAgentVersionRolling sets the environment agent-version
+// setting to the given value, upgrading the agents of machines that
+// are not state servers batchSize machines at a time.
+func (c *Client) SetEnvironAgentVersionRolling(version version.Number, batchSize int) error {
+	args := params.SetEnvironAgentVersion{Version: version, BatchSize: batchSize}
+	return c.facade.FacadeCall("SetEnvironAgentVersion", args, nil)
+}
+
+// PauseRollingUpgrade pauses the rolling agent upgrade in progress.
+func (c *Client) PauseRollingUpgrade() error {
+	return c.facade.FacadeCall("PauseRollingUpgrade", nil, nil)
+}
+
+// ResumeRollingUpgrade resumes the paused rolling agent upgrade.
+func (c *Client) ResumeRollingUpgrade() error {
+	return c.facade.FacadeCall("ResumeRollingUpgrade", nil, nil)
+}
+
 // AbortCurrentUpgrade aborts and archives the current upgrade
 // synchronisation record, if any.
 func (c *Client) AbortCurrentUpgrade() error {
@@ -1075,6 +1207,9 @@ type DebugLogParams struct {
 	// Replay tells the server to start at the start of the log file rather
 	// than the end. If replay is true, backlog is ignored.
 	Replay bool
+	// Trace, if set, restricts the response to the log messages that
+	// hold the given trace id of API requests.
+	Trace string
 }
 
 // WatchDebugLog returns a ReadCloser that the caller can read the log
@@ -1107,6 +1242,9 @@ func (c *Client) WatchDebugLog(args DebugLogParams) (io.ReadCloser, error) {
 	if args.Level != loggo.UNSPECIFIED {
 		attrs.Set("level", fmt.Sprint(args.Level))
 	}
+	if args.Trace != "" {
+		attrs.Set("trace", args.Trace)
+	}
 	attrs["includeEntity"] = args.IncludeEntity
 	attrs["includeModule"] = args.IncludeModule
 	attrs["excludeEntity"] = args.ExcludeEntity
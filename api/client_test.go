@@ -440,6 +440,7 @@ func (s *clientSuite) TestParamsEncoded(c *gc.C) {
 		Backlog:       200,
 		Level:         loggo.ERROR,
 		Replay:        true,
+		Trace:         "a-trace",
 	}
 
 	client := s.APIState.Client()
@@ -457,6 +458,7 @@ func (s *clientSuite) TestParamsEncoded(c *gc.C) {
 		"backlog":       {"200"},
 		"level":         {"ERROR"},
 		"replay":        {"true"},
+		"trace":         {"a-trace"},
 	})
 }
 
@@ -17,6 +17,7 @@ var (
 	BestVersion           = bestVersion
 	FacadeVersions        = &facadeVersions
 	NewHTTPClient         = &newHTTPClient
+	RateLimitDelay        = rateLimitDelay
 )
 
 // SetServerAddress allows changing the URL to the internal API server
@@ -47,12 +47,13 @@ var facadeVersions = map[string]int{
 	"Resumer":                      1,
 	"Rsyslog":                      0,
 	"Service":                      1,
+	"Spaces":                       1,
 	"Storage":                      1,
 	"StorageProvisioner":           1,
 	"StringsWatcher":               0,
 	"SystemManager":                1,
 	"Upgrader":                     0,
-	"Uniter":                       2,
+	"Uniter":                       3,
 	"UserManager":                  0,
 	"VolumeAttachmentsWatcher":     1,
 }
@@ -128,6 +128,13 @@ func (st *State) MachinesWithTransientErrors() ([]*Machine, []params.StatusResul
 	return machines, results.Results, nil
 }
 
+// EnsureMachinePools asks the API server to add or remove spare
+// machines so that each of the environment's machine pools holds as
+// many spares as its size.
+func (st *State) EnsureMachinePools() error {
+	return st.facade.FacadeCall("EnsureMachinePools", nil, nil)
+}
+
 // FindTools returns al ist of tools matching the specified version number and
 // series, and, if non-empty, arch.
 func (st *State) FindTools(v version.Number, series string, arch *string) (tools.List, error) {
@@ -169,6 +169,17 @@ func (s *provisionerSuite) TestMachinesWithTransientErrors(c *gc.C) {
 	})
 }
 
+func (s *provisionerSuite) TestEnsureMachinePools(c *gc.C) {
+	err := s.State.SetMachinePool(state.MachinePool{Name: "web", Size: 1, Series: "quantal"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.provisioner.EnsureMachinePools()
+	c.Assert(err, jc.ErrorIsNil)
+	spares, err := s.State.SpareMachines("web")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(spares, gc.HasLen, 1)
+	c.Assert(spares[0].Pool(), gc.Equals, "web")
+}
+
 func (s *provisionerSuite) TestEnsureDeadAndRemove(c *gc.C) {
 	// Create a fresh machine to test the complete scenario.
 	otherMachine, err := s.State.AddMachine("quantal", state.JobHostUnits)
@@ -63,7 +63,8 @@ func (c *Client) EnvironmentUUID() string {
 // requested networks that must be present on the machines where the
 // service is deployed. Another way to specify networks to include/exclude
 // is using constraints. Placement directives, if provided, specify the
-// machine on which the charm is deployed.
+// machine on which the charm is deployed. Endpoint bindings, if provided,
+// specify the spaces the charm's endpoints are bound to.
 func (c *Client) ServiceDeploy(
 	charmURL string,
 	serviceName string,
@@ -74,6 +75,7 @@ func (c *Client) ServiceDeploy(
 	placement []*instance.Placement,
 	networks []string,
 	storage map[string]storage.Constraints,
+	endpointBindings map[string]string,
 ) error {
 	args := params.ServicesDeploy{
 		Services: []params.ServiceDeploy{{
@@ -86,6 +88,8 @@ func (c *Client) ServiceDeploy(
 			Placement:     placement,
 			Networks:      networks,
 			Storage:       storage,
+
+			EndpointBindings: endpointBindings,
 		}},
 	}
 	var results params.ErrorResults
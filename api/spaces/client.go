@@ -0,0 +1,56 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package spaces
+
+import (
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client provides access to the spaces facade, used to manage the
+// network spaces of an environment.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient returns a new spaces client.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "Spaces")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// CreateSpace creates a new space containing the subnets with the
+// given CIDRs.
+func (c *Client) CreateSpace(name string, cidrs []string) error {
+	p := params.SpacesSubnets{
+		Spaces: []params.SpaceSubnets{{Name: name, CIDRs: cidrs}},
+	}
+	results := new(params.ErrorResults)
+	if err := c.facade.FacadeCall("CreateSpaces", p, results); err != nil {
+		return err
+	}
+	return results.OneError()
+}
+
+// ListSpaces returns all the spaces in the environment, with the CIDRs
+// of their subnets.
+func (c *Client) ListSpaces() ([]params.SpaceSubnets, error) {
+	var result params.ListSpacesResults
+	err := c.facade.FacadeCall("ListSpaces", nil, &result)
+	return result.Results, err
+}
+
+// AddSubnets adds the subnets with the given CIDRs to an existing
+// space.
+func (c *Client) AddSubnets(name string, cidrs []string) error {
+	p := params.SpacesSubnets{
+		Spaces: []params.SpaceSubnets{{Name: name, CIDRs: cidrs}},
+	}
+	results := new(params.ErrorResults)
+	if err := c.facade.FacadeCall("AddSubnetsToSpace", p, results); err != nil {
+		return err
+	}
+	return results.OneError()
+}
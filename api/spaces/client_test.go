@@ -0,0 +1,98 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package spaces_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/spaces"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type spacesSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&spacesSuite{})
+
+func (s *spacesSuite) TestCreateSpace(c *gc.C) {
+	var callCount int
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "Spaces")
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "CreateSpaces")
+		c.Check(arg, gc.DeepEquals, params.SpacesSubnets{
+			Spaces: []params.SpaceSubnets{{
+				Name:  "internal",
+				CIDRs: []string{"10.0.0.0/24"},
+			}},
+		})
+		c.Assert(result, gc.FitsTypeOf, &params.ErrorResults{})
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{
+				Error: &params.Error{Message: "boom"},
+			}},
+		}
+		callCount++
+		return nil
+	})
+
+	client := spaces.NewClient(apiCaller)
+	err := client.CreateSpace("internal", []string{"10.0.0.0/24"})
+	c.Check(err, gc.ErrorMatches, "boom")
+	c.Check(callCount, gc.Equals, 1)
+}
+
+func (s *spacesSuite) TestListSpaces(c *gc.C) {
+	var callCount int
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "Spaces")
+		c.Check(request, gc.Equals, "ListSpaces")
+		c.Assert(result, gc.FitsTypeOf, &params.ListSpacesResults{})
+		*(result.(*params.ListSpacesResults)) = params.ListSpacesResults{
+			Results: []params.SpaceSubnets{{
+				Name:  "internal",
+				CIDRs: []string{"10.0.0.0/24"},
+			}},
+		}
+		callCount++
+		return nil
+	})
+
+	client := spaces.NewClient(apiCaller)
+	result, err := client.ListSpaces()
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(callCount, gc.Equals, 1)
+	c.Check(result, jc.DeepEquals, []params.SpaceSubnets{{
+		Name:  "internal",
+		CIDRs: []string{"10.0.0.0/24"},
+	}})
+}
+
+func (s *spacesSuite) TestAddSubnets(c *gc.C) {
+	var callCount int
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "Spaces")
+		c.Check(request, gc.Equals, "AddSubnetsToSpace")
+		c.Check(arg, gc.DeepEquals, params.SpacesSubnets{
+			Spaces: []params.SpaceSubnets{{
+				Name:  "dmz",
+				CIDRs: []string{"192.168.0.0/24", "192.168.1.0/24"},
+			}},
+		})
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{}},
+		}
+		callCount++
+		return nil
+	})
+
+	client := spaces.NewClient(apiCaller)
+	err := client.AddSubnets("dmz", []string{"192.168.0.0/24", "192.168.1.0/24"})
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(callCount, gc.Equals, 1)
+}
@@ -5,6 +5,7 @@ package uniter
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/names"
@@ -163,6 +164,50 @@ func (u *Unit) AddMetricBatches(batches []params.MetricBatch) (map[string]error,
 	return batchResults, nil
 }
 
+// RecordHookExecutions records the timings and outcomes of hooks,
+// actions and commands executed by the unit.
+func (u *Unit) RecordHookExecutions(executions []params.HookExecution) error {
+	if u.st.facade.BestAPIVersion() < 3 {
+		return errors.NotImplementedf("RecordHookExecutions")
+	}
+	var result params.ErrorResults
+	args := params.RecordHookExecutions{
+		Units: []params.UnitHookExecutions{{
+			Tag:        u.tag.String(),
+			Executions: executions,
+		}},
+	}
+	err := u.st.facade.FacadeCall("RecordHookExecutions", args, &result)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return result.OneError()
+}
+
+// HookRetryPolicy returns the policy the unit should follow when
+// retrying failed hooks.
+func (u *Unit) HookRetryPolicy() (params.HookRetryPolicy, error) {
+	if u.st.facade.BestAPIVersion() < 3 {
+		return params.HookRetryPolicy{}, errors.NotImplementedf("HookRetryPolicy")
+	}
+	var results params.HookRetryPolicyResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: u.tag.String()}},
+	}
+	err := u.st.facade.FacadeCall("HookRetryPolicy", args, &results)
+	if err != nil {
+		return params.HookRetryPolicy{}, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return params.HookRetryPolicy{}, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return params.HookRetryPolicy{}, result.Error
+	}
+	return result.Result, nil
+}
+
 // EnsureDead sets the unit lifecycle to Dead if it is Alive or
 // Dying. It does nothing otherwise.
 func (u *Unit) EnsureDead() error {
@@ -729,6 +774,172 @@ func (u *Unit) WatchMeterStatus() (watcher.NotifyWatcher, error) {
 	return w, nil
 }
 
+// ScheduleEvent schedules a deferred event for the unit, replacing
+// any existing event with the same name. If cron is not empty, the
+// event is rescheduled according to it each time it fires.
+func (u *Unit) ScheduleEvent(name string, due time.Time, cron string) error {
+	if u.st.facade.BestAPIVersion() < 3 {
+		return errors.NotImplementedf("ScheduleEvent")
+	}
+	var result params.ErrorResults
+	args := params.ScheduleEvents{
+		Events: []params.UnitScheduledEvent{{
+			Tag: u.tag.String(),
+			Event: params.ScheduledEvent{
+				Name: name,
+				Due:  due,
+				Cron: cron,
+			},
+		}},
+	}
+	err := u.st.facade.FacadeCall("ScheduleEvents", args, &result)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return result.OneError()
+}
+
+// FireScheduledEvent records that the named scheduled event, due at
+// the given time, has been delivered to the unit. The event is left
+// alone if it has been rescheduled since.
+func (u *Unit) FireScheduledEvent(name string, due time.Time) error {
+	if u.st.facade.BestAPIVersion() < 3 {
+		return errors.NotImplementedf("FireScheduledEvent")
+	}
+	var result params.ErrorResults
+	args := params.ScheduleEvents{
+		Events: []params.UnitScheduledEvent{{
+			Tag: u.tag.String(),
+			Event: params.ScheduledEvent{
+				Name: name,
+				Due:  due,
+			},
+		}},
+	}
+	err := u.st.facade.FacadeCall("FireScheduledEvents", args, &result)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return result.OneError()
+}
+
+// CancelScheduledEvent cancels the named event scheduled by the unit.
+func (u *Unit) CancelScheduledEvent(name string) error {
+	if u.st.facade.BestAPIVersion() < 3 {
+		return errors.NotImplementedf("CancelScheduledEvent")
+	}
+	var result params.ErrorResults
+	args := params.UnitScheduledEventNames{
+		Names: []params.UnitScheduledEventName{{
+			Tag:  u.tag.String(),
+			Name: name,
+		}},
+	}
+	err := u.st.facade.FacadeCall("CancelScheduledEvents", args, &result)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return result.OneError()
+}
+
+// ScheduledEvents returns the deferred events scheduled by the unit,
+// ordered by due time.
+func (u *Unit) ScheduledEvents() ([]params.ScheduledEvent, error) {
+	if u.st.facade.BestAPIVersion() < 3 {
+		return nil, errors.NotImplementedf("ScheduledEvents")
+	}
+	var results params.ScheduledEventsResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: u.tag.String()}},
+	}
+	err := u.st.facade.FacadeCall("ScheduledEvents", args, &results)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return result.Events, nil
+}
+
+// DebugHooksSession returns the debug-hooks session opened on the
+// unit through the API, or nil if it has none.
+func (u *Unit) DebugHooksSession() (*params.UnitDebugHooksSession, error) {
+	if u.st.facade.BestAPIVersion() < 3 {
+		return nil, errors.NotImplementedf("DebugHooksSession")
+	}
+	var results params.DebugHooksSessionResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: u.tag.String()}},
+	}
+	err := u.st.facade.FacadeCall("DebugHooksSessions", args, &results)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return result.Session, nil
+}
+
+// WatchScheduledEvents returns a watcher for observing changes to the
+// unit's scheduled events.
+func (u *Unit) WatchScheduledEvents() (watcher.NotifyWatcher, error) {
+	if u.st.facade.BestAPIVersion() < 3 {
+		return nil, errors.NotImplementedf("WatchScheduledEvents")
+	}
+	var results params.NotifyWatchResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: u.tag.String()}},
+	}
+	err := u.st.facade.FacadeCall("WatchScheduledEvents", args, &results)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	w := watcher.NewNotifyWatcher(u.st.facade.RawAPICaller(), result)
+	return w, nil
+}
+
+// NetworkInfo returns the network interfaces and addresses the unit
+// uses for the given endpoint of its service. An empty endpoint name
+// refers to the service's default network.
+func (u *Unit) NetworkInfo(endpoint string) ([]params.NetworkInfo, error) {
+	if u.st.facade.BestAPIVersion() < 3 {
+		return nil, errors.NotImplementedf("NetworkInfo")
+	}
+	var results params.NetworkInfoResults
+	args := params.UnitEndpoints{
+		Endpoints: []params.UnitEndpoint{{Tag: u.tag.String(), Endpoint: endpoint}},
+	}
+	err := u.st.facade.FacadeCall("NetworkInfo", args, &results)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return result.Info, nil
+}
+
 // WatchStorage returns a watcher for observing changes to the
 // unit's storage attachments.
 func (u *Unit) WatchStorage() (watcher.StringsWatcher, error) {
@@ -121,6 +121,64 @@ func (s *unitSuite) TestSetUnitStatus(c *gc.C) {
 	c.Assert(agentStatusInfo.Data, gc.HasLen, 0)
 }
 
+func (s *unitSuite) TestRecordHookExecutions(c *gc.C) {
+	started := time.Date(2015, 7, 1, 12, 0, 0, 0, time.UTC)
+	err := s.apiUnit.RecordHookExecutions([]params.HookExecution{{
+		Kind:      "action",
+		Name:      "backup",
+		Started:   started,
+		Finished:  started.Add(time.Minute),
+		ExitCode:  2,
+		ToolCalls: 5,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+
+	executions, err := s.wordpressUnit.HookExecutions(10)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(executions, jc.DeepEquals, []state.HookExecution{{
+		Kind:      state.HookExecutionAction,
+		Name:      "backup",
+		Started:   started,
+		Finished:  started.Add(time.Minute),
+		ExitCode:  2,
+		ToolCalls: 5,
+	}})
+}
+
+func (s *unitSuite) TestRecordHookExecutionsOldServer(c *gc.C) {
+	s.patchNewState(c, uniter.NewStateV2)
+
+	err := s.apiUnit.RecordHookExecutions(nil)
+	c.Assert(err, jc.Satisfies, errors.IsNotImplemented)
+	c.Assert(err.Error(), gc.Equals, "RecordHookExecutions not implemented")
+}
+
+func (s *unitSuite) TestHookRetryPolicy(c *gc.C) {
+	policy, err := s.apiUnit.HookRetryPolicy()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(policy, gc.Equals, params.HookRetryPolicy{})
+
+	err = s.wordpressService.SetHookRetryPolicy(state.HookRetryPolicy{
+		MaxAttempts: 2,
+		Delay:       time.Second,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	policy, err = s.apiUnit.HookRetryPolicy()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(policy, gc.Equals, params.HookRetryPolicy{
+		MaxAttempts: 2,
+		Delay:       time.Second,
+	})
+}
+
+func (s *unitSuite) TestHookRetryPolicyOldServer(c *gc.C) {
+	s.patchNewState(c, uniter.NewStateV2)
+
+	_, err := s.apiUnit.HookRetryPolicy()
+	c.Assert(err, jc.Satisfies, errors.IsNotImplemented)
+	c.Assert(err.Error(), gc.Equals, "HookRetryPolicy not implemented")
+}
+
 func (s *unitSuite) TestSetUnitStatusOldServer(c *gc.C) {
 	s.patchNewState(c, uniter.NewStateV1)
 
@@ -774,6 +832,100 @@ func (s *unitSuite) TestMeterStatusResultError(c *gc.C) {
 	c.Assert(statusInfo, gc.Equals, "")
 }
 
+func (s *unitSuite) TestScheduledEvents(c *gc.C) {
+	due := time.Date(2015, 7, 1, 12, 0, 0, 0, time.UTC)
+	err := s.apiUnit.ScheduleEvent("rotate-certs", due, "")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.apiUnit.ScheduleEvent("nightly", due.Add(time.Hour), "0 3 * * *")
+	c.Assert(err, jc.ErrorIsNil)
+
+	events, err := s.apiUnit.ScheduledEvents()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(events, jc.DeepEquals, []params.ScheduledEvent{
+		{Name: "rotate-certs", Due: due},
+		{Name: "nightly", Due: due.Add(time.Hour), Cron: "0 3 * * *"},
+	})
+
+	// An event rescheduled since it became due is not fired.
+	err = s.apiUnit.FireScheduledEvent("rotate-certs", due.Add(-time.Hour))
+	c.Assert(err, jc.ErrorIsNil)
+	stateEvents, err := s.wordpressUnit.ScheduledEvents()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stateEvents, gc.HasLen, 2)
+
+	err = s.apiUnit.FireScheduledEvent("rotate-certs", due)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.apiUnit.CancelScheduledEvent("nightly")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.apiUnit.CancelScheduledEvent("nightly")
+	c.Assert(err, jc.Satisfies, params.IsCodeNotFound)
+
+	stateEvents, err = s.wordpressUnit.ScheduledEvents()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stateEvents, gc.HasLen, 0)
+}
+
+func (s *unitSuite) TestScheduledEventsOldServer(c *gc.C) {
+	s.patchNewState(c, uniter.NewStateV2)
+
+	err := s.apiUnit.ScheduleEvent("later", time.Now(), "")
+	c.Assert(err, jc.Satisfies, errors.IsNotImplemented)
+	err = s.apiUnit.CancelScheduledEvent("later")
+	c.Assert(err, jc.Satisfies, errors.IsNotImplemented)
+	err = s.apiUnit.FireScheduledEvent("later", time.Now())
+	c.Assert(err, jc.Satisfies, errors.IsNotImplemented)
+	_, err = s.apiUnit.ScheduledEvents()
+	c.Assert(err, jc.Satisfies, errors.IsNotImplemented)
+	_, err = s.apiUnit.WatchScheduledEvents()
+	c.Assert(err, jc.Satisfies, errors.IsNotImplemented)
+}
+
+func (s *unitSuite) TestNetworkInfo(c *gc.C) {
+	err := s.wordpressMachine.SetProviderAddresses(
+		network.NewScopedAddress("10.0.0.5", network.ScopeCloudLocal),
+		network.NewScopedAddress("127.0.0.1", network.ScopeMachineLocal),
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddSubnet(state.SubnetInfo{CIDR: "10.0.0.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	info, err := s.apiUnit.NetworkInfo("db")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info, jc.DeepEquals, []params.NetworkInfo{{
+		Addresses: []params.NetworkInfoAddress{{
+			Address: "10.0.0.5",
+			CIDR:    "10.0.0.0/24",
+		}},
+	}})
+
+	_, err = s.apiUnit.NetworkInfo("missing")
+	c.Assert(err, jc.Satisfies, params.IsCodeNotFound)
+}
+
+func (s *unitSuite) TestNetworkInfoOldServer(c *gc.C) {
+	s.patchNewState(c, uniter.NewStateV2)
+
+	_, err := s.apiUnit.NetworkInfo("db")
+	c.Assert(err, jc.Satisfies, errors.IsNotImplemented)
+}
+
+func (s *unitSuite) TestWatchScheduledEvents(c *gc.C) {
+	w, err := s.apiUnit.WatchScheduledEvents()
+	c.Assert(err, jc.ErrorIsNil)
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewNotifyWatcherC(c, s.BackingState, w)
+
+	// Initial event.
+	wc.AssertOneChange()
+
+	err = s.wordpressUnit.ScheduleEvent(state.ScheduledEvent{Name: "later", Due: time.Now()})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	statetesting.AssertStop(c, w)
+	wc.AssertClosed()
+}
+
 func (s *unitSuite) TestWatchMeterStatus(c *gc.C) {
 	w, err := s.apiUnit.WatchMeterStatus()
 	defer statetesting.AssertStop(c, w)
@@ -6,6 +6,7 @@ package usermanager
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
@@ -89,6 +90,12 @@ func (c *Client) EnableUser(username string) error {
 	return c.userCall(username, "EnableUser")
 }
 
+// ClearLockout ends the lockout of a user after too many failed logins.
+// If the user is not locked out, the action is considered a success.
+func (c *Client) ClearLockout(username string) error {
+	return c.userCall(username, "ClearLockout")
+}
+
 // IncludeDisabled is a type alias to avoid bare true/false values
 // in calls to the client method.
 type IncludeDisabled bool
@@ -166,3 +173,148 @@ func (c *Client) SetPassword(username, password string) error {
 	}
 	return results.OneError()
 }
+
+// CreateToken issues a new API token to the specified user, expiring at
+// the given time and restricted to the given scope of facades and
+// methods if it is not empty. It returns the token and the credentials
+// to log in with, which cannot be retrieved again later.
+func (c *Client) CreateToken(username, description string, expires time.Time, scope []string) (params.UserToken, string, error) {
+	if !names.IsValidUserName(username) {
+		return params.UserToken{}, "", errors.Errorf("%q is not a valid username", username)
+	}
+	args := params.CreateUserTokens{
+		Tokens: []params.CreateUserToken{{
+			Tag:         names.NewLocalUserTag(username).String(),
+			Description: description,
+			Expires:     expires,
+			Scope:       scope,
+		}},
+	}
+	var results params.CreateUserTokenResults
+	if err := c.facade.FacadeCall("CreateTokens", args, &results); err != nil {
+		return params.UserToken{}, "", errors.Trace(err)
+	}
+	if count := len(results.Results); count != 1 {
+		return params.UserToken{}, "", errors.Errorf("expected 1 result, got %d", count)
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return params.UserToken{}, "", errors.Trace(result.Error)
+	}
+	if result.Token == nil {
+		return params.UserToken{}, "", errors.New("no token returned")
+	}
+	return *result.Token, result.Credentials, nil
+}
+
+// UserTokens returns the API tokens issued to the specified user.
+func (c *Client) UserTokens(username string) ([]params.UserToken, error) {
+	if !names.IsValidUserName(username) {
+		return nil, errors.Errorf("%q is not a valid username", username)
+	}
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: names.NewLocalUserTag(username).String()}},
+	}
+	var results params.UserTokensResults
+	if err := c.facade.FacadeCall("UserTokens", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if count := len(results.Results); count != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", count)
+	}
+	if err := results.Results[0].Error; err != nil {
+		return nil, errors.Trace(err)
+	}
+	return results.Results[0].Tokens, nil
+}
+
+// RevokeToken revokes the specified user's API token with the given id.
+func (c *Client) RevokeToken(username, id string) error {
+	if !names.IsValidUserName(username) {
+		return errors.Errorf("%q is not a valid username", username)
+	}
+	args := params.RevokeUserTokens{
+		Tokens: []params.RevokeUserToken{{
+			Tag: names.NewLocalUserTag(username).String(),
+			Id:  id,
+		}},
+	}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("RevokeTokens", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+// UserPermission returns the permission of the specified user to use a
+// role, or a facade or facade method, optionally restricted to the
+// entities of a service.
+func UserPermission(username, operation, service string) params.PermissionGrant {
+	return params.PermissionGrant{
+		UserTag:   names.NewLocalUserTag(username).String(),
+		Operation: operation,
+		Service:   service,
+	}
+}
+
+// GroupPermission returns the permission of the members of the
+// specified group to use a role, or a facade or facade method,
+// optionally restricted to the entities of a service.
+func GroupPermission(group, operation, service string) params.PermissionGrant {
+	return params.PermissionGrant{
+		Group:     group,
+		Operation: operation,
+		Service:   service,
+	}
+}
+
+// GrantPermission grants a permission in the environment. Once granted
+// a permission, users are restricted to the operations of the
+// permissions granted to them and their groups, even after they are
+// all revoked.
+func (c *Client) GrantPermission(permission params.PermissionGrant) error {
+	return c.permissionCall("GrantPermissions", permission)
+}
+
+// RevokePermission revokes a permission granted by GrantPermission.
+func (c *Client) RevokePermission(permission params.PermissionGrant) error {
+	return c.permissionCall("RevokePermissions", permission)
+}
+
+func (c *Client) permissionCall(method string, permission params.PermissionGrant) error {
+	args := params.PermissionGrants{
+		Grants: []params.PermissionGrant{permission},
+	}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall(method, args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+// CheckPermission reports whether the specified user may call the given
+// facade method, as "Facade.Method", in the environment; on the
+// entities of the given service if it is not empty.
+func (c *Client) CheckPermission(username, operation, service string) (bool, error) {
+	if !names.IsValidUserName(username) {
+		return false, errors.Errorf("%q is not a valid username", username)
+	}
+	args := params.PermissionChecks{
+		Checks: []params.PermissionCheck{{
+			UserTag:   names.NewLocalUserTag(username).String(),
+			Operation: operation,
+			Service:   service,
+		}},
+	}
+	var results params.BoolResults
+	if err := c.facade.FacadeCall("CheckPermissions", args, &results); err != nil {
+		return false, errors.Trace(err)
+	}
+	if count := len(results.Results); count != 1 {
+		return false, errors.Errorf("expected 1 result, got %d", count)
+	}
+	if err := results.Results[0].Error; err != nil {
+		return false, errors.Trace(err)
+	}
+	return results.Results[0].Result, nil
+}
@@ -4,6 +4,8 @@
 package usermanager_test
 
 import (
+	"time"
+
 	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
@@ -102,6 +104,45 @@ func (s *usermanagerSuite) TestEnableUserBadName(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `"not@home" is not a valid username`)
 }
 
+func (s *usermanagerSuite) TestClearLockout(c *gc.C) {
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{"login-lockout-attempts": 1}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "foobar"})
+	err = user.RecordFailedLogin()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(user.IsLockedOut(), jc.IsTrue)
+
+	err = s.usermanager.ClearLockout(user.Name())
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = user.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(user.IsLockedOut(), jc.IsFalse)
+}
+
+func (s *usermanagerSuite) TestPermissions(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "foobar"})
+	err := s.usermanager.GrantPermission(usermanager.UserPermission("foobar", "actions", "wordpress"))
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.usermanager.GrantPermission(usermanager.GroupPermission("ops", "read", ""))
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.usermanager.GrantPermission(usermanager.GroupPermission("ops", "read", ""))
+	c.Assert(err, gc.ErrorMatches, "permission read for group:ops already exists")
+
+	allowed, err := s.usermanager.CheckPermission(user.Name(), "Action.Enqueue", "wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(allowed, jc.IsTrue)
+	allowed, err = s.usermanager.CheckPermission(user.Name(), "Action.Enqueue", "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(allowed, jc.IsFalse)
+
+	err = s.usermanager.RevokePermission(usermanager.UserPermission("foobar", "actions", "wordpress"))
+	c.Assert(err, jc.ErrorIsNil)
+	allowed, err = s.usermanager.CheckPermission(user.Name(), "Action.Enqueue", "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(allowed, jc.IsTrue)
+}
+
 func (s *usermanagerSuite) TestCantRemoveAdminUser(c *gc.C) {
 	err := s.usermanager.DisableUser(s.AdminUserTag(c).Name())
 	c.Assert(err, gc.ErrorMatches, "failed to disable user: cannot disable state server environment owner")
@@ -177,3 +218,37 @@ func (s *usermanagerSuite) TestSetUserPasswordBadName(c *gc.C) {
 	err := s.usermanager.SetPassword("not@home", "new-password")
 	c.Assert(err, gc.ErrorMatches, `"not@home" is not a valid username`)
 }
+
+func (s *usermanagerSuite) TestTokens(c *gc.C) {
+	s.Factory.MakeUser(c, &factory.UserParams{Name: "foobar"})
+	expires := time.Now().Add(time.Hour).Round(time.Second).UTC()
+
+	token, credentials, err := s.usermanager.CreateToken("foobar", "ci", expires, []string{"Client"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(token.Description, gc.Equals, "ci")
+	c.Assert(token.Expires, gc.DeepEquals, expires)
+	c.Assert(token.Scope, jc.DeepEquals, []string{"Client"})
+	c.Assert(credentials, jc.HasPrefix, "token:"+token.Id+":")
+
+	tokens, err := s.usermanager.UserTokens("foobar")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(tokens, jc.DeepEquals, []params.UserToken{token})
+
+	err = s.usermanager.RevokeToken("foobar", token.Id)
+	c.Assert(err, jc.ErrorIsNil)
+	tokens, err = s.usermanager.UserTokens("foobar")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(tokens, gc.HasLen, 0)
+
+	err = s.usermanager.RevokeToken("foobar", token.Id)
+	c.Assert(err, gc.ErrorMatches, `token ".*" for user "foobar" not found`)
+}
+
+func (s *usermanagerSuite) TestTokensBadName(c *gc.C) {
+	_, _, err := s.usermanager.CreateToken("not!good", "", time.Now(), nil)
+	c.Assert(err, gc.ErrorMatches, `"not!good" is not a valid username`)
+	_, err = s.usermanager.UserTokens("not!good")
+	c.Assert(err, gc.ErrorMatches, `"not!good" is not a valid username`)
+	err = s.usermanager.RevokeToken("not!good", "abc")
+	c.Assert(err, gc.ErrorMatches, `"not!good" is not a valid username`)
+}
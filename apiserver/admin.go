@@ -4,6 +4,7 @@
 package apiserver
 
 import (
+	"strings"
 	"sync"
 	"time"
 
@@ -80,6 +81,14 @@ func (a *admin) doLogin(req params.LoginRequest, loginVersion int) (params.Login
 		defer a.srv.limiter.Release()
 	} else {
 		isUser = true
+		allowed, err := a.allowUserLogin()
+		if err != nil {
+			return fail, errors.Trace(err)
+		}
+		if !allowed {
+			logger.Debugf("rate limiting login for user %s", req.AuthTag)
+			return fail, common.ErrTryAgain
+		}
 	}
 
 	serverOnlyLogin := loginVersion > 1 && a.root.envUUID == ""
@@ -104,10 +113,12 @@ func (a *admin) doLogin(req params.LoginRequest, loginVersion int) (params.Login
 		// can then check the credentials against the state server environment
 		// machine.
 		if kind != names.MachineTagKind {
+			a.srv.metrics.loginFailed()
 			return fail, err
 		}
 		entity, err = a.checkCredsOfStateServerMachine(req)
 		if err != nil {
+			a.srv.metrics.loginFailed()
 			return fail, err
 		}
 		// If we are here, then the entity will refer to a state server
@@ -118,6 +129,29 @@ func (a *admin) doLogin(req params.LoginRequest, loginVersion int) (params.Login
 	}
 	a.root.entity = entity
 
+	if isUser && isTokenLogin(req.Credentials) {
+		id, _, _ := state.ParseUserTokenCredentials(req.Credentials)
+		token, err := a.root.state.UserToken(id)
+		if err != nil {
+			return fail, errors.Trace(err)
+		}
+		authedApi = newTokenRoot(authedApi, token.Scope(), userTokenCheck(a.root.state, id))
+	}
+	if user, ok := entity.(*state.User); ok {
+		// Permissions are checked on every call, as they may be
+		// granted or revoked while the user is connected.
+		authedApi = newPermissionRoot(authedApi, userPermissions(a.root.state, user.UserTag()))
+	}
+	if user, ok := entity.(*state.User); ok && !isTokenLogin(req.Credentials) {
+		expired, err := user.PasswordExpired()
+		if err != nil {
+			return fail, errors.Trace(err)
+		}
+		if expired {
+			authedApi = newPasswordExpiredRoot(authedApi)
+		}
+	}
+
 	if a.reqNotifier != nil {
 		a.reqNotifier.login(entity.Tag().String())
 	}
@@ -179,6 +213,17 @@ func (a *admin) doLogin(req params.LoginRequest, loginVersion int) (params.Login
 		loginResult.Facades = facades
 	}
 
+	if isUser {
+		limits, err := a.srv.state.APIRateLimits()
+		if err != nil {
+			return fail, errors.Trace(err)
+		}
+		if len(limits) > 0 {
+			limiter := a.srv.apiRateLimiter.forUser(entity.Tag().String(), limits)
+			a.root.rpcConn.SetRateLimiter(limiter)
+		}
+	}
+
 	a.root.rpcConn.ServeFinder(authedApi, serverError)
 
 	return loginResult, nil
@@ -208,6 +253,19 @@ func (a *admin) checkCredsOfStateServerMachine(req params.LoginRequest) (state.E
 	return nil, common.ErrBadCreds
 }
 
+// allowUserLogin reports whether a user login from the address of the
+// connection is within the login rate limit of the state server.
+func (a *admin) allowUserLogin() (bool, error) {
+	if a.reqNotifier == nil {
+		return true, nil
+	}
+	policy, err := a.srv.state.LoginPolicy()
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	return a.srv.loginThrottle.allow(a.reqNotifier.remoteHost(), policy.RateLimit, time.Now()), nil
+}
+
 func (a *admin) maintenanceInProgress() bool {
 	if a.srv.validator == nil {
 		return false
@@ -240,25 +298,58 @@ func checkCreds(st *state.State, req params.LoginRequest, lookForEnvUser bool) (
 		return nil, nil, err
 	}
 	entity, err := st.FindEntity(tag)
-	if errors.IsNotFound(err) {
-		// We return the same error when an entity does not exist as for a bad
-		// password, so that we don't allow unauthenticated users to find
-		// information about existing entities.
-		logger.Debugf("entity %q not found", tag)
-		return nil, nil, common.ErrBadCreds
-	}
-	if err != nil {
-		return nil, nil, errors.Trace(err)
-	}
+	userTag, isUser := tag.(names.UserTag)
+	switch {
+	case isUser && isTokenLogin(req.Credentials):
+		entity, err = checkTokenCreds(st, userTag, req.Credentials)
+		if err != nil {
+			return nil, nil, err
+		}
+	case isUser && isExternalLogin(entity, err):
+		// Users without a password stored here are authenticated
+		// by the external identity provider, if there is one.
+		entity, err = checkExternalCreds(st, userTag, req.Credentials, lookForEnvUser)
+		if err != nil {
+			return nil, nil, err
+		}
+	default:
+		if errors.IsNotFound(err) {
+			// We return the same error when an entity does not exist as for a bad
+			// password, so that we don't allow unauthenticated users to find
+			// information about existing entities.
+			logger.Debugf("entity %q not found", tag)
+			return nil, nil, common.ErrBadCreds
+		}
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
 
-	authenticator, err := authentication.FindEntityAuthenticator(entity)
-	if err != nil {
-		return nil, nil, err
-	}
+		authenticator, err := authentication.FindEntityAuthenticator(entity)
+		if err != nil {
+			return nil, nil, err
+		}
 
-	if err = authenticator.Authenticate(entity, req.Credentials, req.Nonce); err != nil {
-		logger.Debugf("bad credentials")
-		return nil, nil, err
+		// Users that failed to log in too many times are locked out,
+		// whatever password they give.
+		user, isLocalUser := entity.(*state.User)
+		if isLocalUser && user.IsLockedOut() {
+			logger.Debugf("user %q locked out until %v", user.Name(), user.LockedUntil())
+			return nil, nil, common.ErrBadCreds
+		}
+		if err = authenticator.Authenticate(entity, req.Credentials, req.Nonce); err != nil {
+			logger.Debugf("bad credentials")
+			if isLocalUser && err == common.ErrBadCreds {
+				if err := user.RecordFailedLogin(); err != nil {
+					logger.Warningf("cannot record failed login: %v", err)
+				}
+			}
+			return nil, nil, err
+		}
+		if isLocalUser {
+			if err := user.ResetFailedLogins(); err != nil {
+				logger.Warningf("cannot reset failed logins: %v", err)
+			}
+		}
 	}
 
 	// For user logins, update the last login time.
@@ -288,6 +379,87 @@ func checkCreds(st *state.State, req params.LoginRequest, lookForEnvUser bool) (
 	return entity, lastLogin, nil
 }
 
+// isTokenLogin reports whether a user login is made with an API token
+// rather than a password.
+func isTokenLogin(credentials string) bool {
+	_, _, ok := state.ParseUserTokenCredentials(credentials)
+	return ok
+}
+
+// checkTokenCreds authenticates a user logging in with an API token.
+func checkTokenCreds(st *state.State, tag names.UserTag, credentials string) (state.Entity, error) {
+	id, secret, _ := state.ParseUserTokenCredentials(credentials)
+	token, err := st.UserToken(id)
+	if errors.IsNotFound(err) {
+		logger.Debugf("token %q not found", id)
+		return nil, common.ErrBadCreds
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if token.UserTag().Name() != strings.ToLower(tag.Name()) || !token.SecretValid(secret) {
+		logger.Debugf("bad credentials")
+		return nil, common.ErrBadCreds
+	}
+	if !token.Expires().After(time.Now()) {
+		logger.Debugf("token %q expired at %v", id, token.Expires())
+		return nil, common.ErrBadCreds
+	}
+	user, err := st.User(tag)
+	if errors.IsNotFound(err) {
+		return nil, common.ErrBadCreds
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if user.IsDisabled() {
+		return nil, common.ErrBadCreds
+	}
+	return user, nil
+}
+
+// tokenScope returns the scope of the API token in the given
+// credentials, or nil if there is no token or it is not restricted.
+func tokenScope(st *state.State, credentials string) ([]string, error) {
+	id, _, ok := state.ParseUserTokenCredentials(credentials)
+	if !ok {
+		return nil, nil
+	}
+	token, err := st.UserToken(id)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return token.Scope(), nil
+}
+
+// isExternalLogin reports whether a user login is authenticated by the
+// external identity provider, given the result of looking up the user.
+func isExternalLogin(entity state.Entity, err error) bool {
+	if errors.IsNotFound(err) {
+		return true
+	}
+	user, ok := entity.(*state.User)
+	return err == nil && ok && user.IsExternal()
+}
+
+// checkExternalCreds authenticates the user with the external identity
+// provider configured for the state server, adding the user to state
+// on first login.
+func checkExternalCreds(st *state.State, tag names.UserTag, password string, lookForEnvUser bool) (state.Entity, error) {
+	authenticator, err := authentication.NewExternalAuthenticator(st)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if authenticator == nil || !tag.IsLocal() {
+		logger.Debugf("entity %q not found", tag)
+		return nil, common.ErrBadCreds
+	}
+	user, err := authenticator.Authenticate(st, tag, password, lookForEnvUser)
+	if err != nil {
+		logger.Debugf("bad credentials")
+		return nil, err
+	}
+	return user, nil
+}
+
 func checkForValidMachineAgent(entity state.Entity, req params.LoginRequest) error {
 	// If this is a machine agent connecting, we need to check the
 	// nonce matches, otherwise the wrong agent might be trying to
@@ -18,7 +18,9 @@ import (
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/api"
+	"github.com/juju/juju/api/usermanager"
 	"github.com/juju/juju/apiserver"
+	authenticationtesting "github.com/juju/juju/apiserver/authentication/testing"
 	"github.com/juju/juju/apiserver/params"
 	jujutesting "github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/network"
@@ -956,3 +958,246 @@ func (s *loginSuite) TestLoginUpdatesLastLoginAndConnection(c *gc.C) {
 	c.Assert(envUser.LastConnection(), gc.NotNil)
 	c.Assert(envUser.LastConnection().After(startTime), jc.IsTrue)
 }
+
+func (s *loginSuite) TestExternalLogin(c *gc.C) {
+	_, cleanup := s.setupServerWithValidator(c, nil)
+	defer cleanup()
+
+	directory, err := authenticationtesting.NewLDAPDirectory()
+	c.Assert(err, jc.ErrorIsNil)
+	defer directory.Close()
+	for name, group := range map[string]string{
+		"bob":   "admins",
+		"carol": "staff",
+		"dave":  "visitors",
+	} {
+		directory.AddEntry("uid="+name+",ou=people,dc=example,dc=com", authenticationtesting.LDAPEntry{
+			Password: name + "-secret",
+			Attributes: map[string][]string{
+				"displayName": {name},
+				"memberOf":    {"cn=" + group + ",ou=groups,dc=example,dc=com"},
+			},
+		})
+	}
+	err = s.State.UpdateEnvironConfig(map[string]interface{}{
+		"identity-provider":     "ldap",
+		"identity-url":          directory.URL(),
+		"ldap-user-dn":          "uid=%s,ou=people,dc=example,dc=com",
+		"identity-group-access": "admins=environment staff=login",
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	login := func(name, password string) error {
+		info := s.APIInfo(c)
+		info.Tag = names.NewUserTag(name)
+		info.Password = password
+		st, err := api.Open(info, fastDialOpts)
+		if err == nil {
+			st.Close()
+		}
+		return err
+	}
+
+	// The user is added on first login, and given access to the
+	// environment.
+	err = login("bob", "bob-secret")
+	c.Assert(err, jc.ErrorIsNil)
+	user, err := s.State.User(names.NewLocalUserTag("bob"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(user.IsExternal(), jc.IsTrue)
+	c.Assert(user.DisplayName(), gc.Equals, "bob")
+	_, err = s.State.EnvironmentUser(user.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	err = login("bob", "bob-secret")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = login("bob", "wrong")
+	c.Assert(err, gc.ErrorMatches, "invalid entity name or password")
+
+	// Login access only does not give access to the environment.
+	err = login("carol", "carol-secret")
+	c.Assert(err, gc.ErrorMatches, "invalid entity name or password")
+	_, err = s.State.User(names.NewLocalUserTag("carol"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Users in no group with access cannot log in at all.
+	err = login("dave", "dave-secret")
+	c.Assert(err, gc.ErrorMatches, "invalid entity name or password")
+	_, err = s.State.User(names.NewLocalUserTag("dave"))
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *loginSuite) TestTokenLogin(c *gc.C) {
+	_, cleanup := s.setupServerWithValidator(c, nil)
+	defer cleanup()
+
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob", Password: "password"})
+	_, credentials, err := user.AddToken("ci", time.Now().Add(time.Hour), []string{"Client.FullStatus"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	info := s.APIInfo(c)
+	info.Tag = user.Tag()
+	info.Password = credentials
+	st, err := api.Open(info, fastDialOpts)
+	c.Assert(err, jc.ErrorIsNil)
+	defer st.Close()
+
+	// The token is restricted to the methods in its scope.
+	_, err = st.Client().Status(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = st.Client().EnvironmentGet()
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	c.Assert(params.ErrCode(err), gc.Equals, params.CodeUnauthorized)
+}
+
+func (s *loginSuite) TestTokenLoginFails(c *gc.C) {
+	_, cleanup := s.setupServerWithValidator(c, nil)
+	defer cleanup()
+
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob", Password: "password"})
+	other := s.Factory.MakeUser(c, &factory.UserParams{Name: "carol", Password: "password"})
+	token, credentials, err := user.AddToken("ci", time.Now().Add(time.Hour), nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, expiredCredentials, err := user.AddToken("old", time.Now().Add(-time.Minute), nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	login := func(tag names.Tag, password string) error {
+		info := s.APIInfo(c)
+		info.Tag = tag
+		info.Password = password
+		st, err := api.Open(info, fastDialOpts)
+		if err == nil {
+			st.Close()
+		}
+		return err
+	}
+
+	err = login(user.Tag(), credentials)
+	c.Assert(err, jc.ErrorIsNil)
+	err = login(other.Tag(), credentials)
+	c.Assert(err, gc.ErrorMatches, "invalid entity name or password")
+	err = login(user.Tag(), credentials+"x")
+	c.Assert(err, gc.ErrorMatches, "invalid entity name or password")
+	err = login(user.Tag(), expiredCredentials)
+	c.Assert(err, gc.ErrorMatches, "invalid entity name or password")
+
+	err = user.RevokeToken(token.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	err = login(user.Tag(), credentials)
+	c.Assert(err, gc.ErrorMatches, "invalid entity name or password")
+}
+
+func (s *loginSuite) TestLoginLockout(c *gc.C) {
+	_, cleanup := s.setupServerWithValidator(c, nil)
+	defer cleanup()
+
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{"login-lockout-attempts": 2}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob", Password: "password"})
+
+	login := func(password string) error {
+		info := s.APIInfo(c)
+		info.Tag = user.Tag()
+		info.Password = password
+		st, err := api.Open(info, fastDialOpts)
+		if err == nil {
+			st.Close()
+		}
+		return err
+	}
+
+	// A successful login resets the count of failures.
+	err = login("wrong")
+	c.Assert(err, gc.ErrorMatches, "invalid entity name or password")
+	err = login("password")
+	c.Assert(err, jc.ErrorIsNil)
+	err = login("wrong")
+	c.Assert(err, gc.ErrorMatches, "invalid entity name or password")
+	err = user.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(user.IsLockedOut(), jc.IsFalse)
+
+	// Once locked out, even the right password is refused.
+	err = login("wrong")
+	c.Assert(err, gc.ErrorMatches, "invalid entity name or password")
+	err = user.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(user.IsLockedOut(), jc.IsTrue)
+	err = login("password")
+	c.Assert(err, gc.ErrorMatches, "invalid entity name or password")
+
+	err = user.ClearLockout()
+	c.Assert(err, jc.ErrorIsNil)
+	err = login("password")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *loginSuite) TestLoginPasswordExpired(c *gc.C) {
+	_, cleanup := s.setupServerWithValidator(c, nil)
+	defer cleanup()
+
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{"password-max-age": 30}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob", Password: "password"})
+	state.SetUserPasswordChanged(c, user, time.Now().Add(-31*24*time.Hour))
+
+	info := s.APIInfo(c)
+	info.Tag = user.Tag()
+	info.Password = "password"
+	st, err := api.Open(info, fastDialOpts)
+	c.Assert(err, jc.ErrorIsNil)
+	defer st.Close()
+
+	// Only the password can be changed.
+	_, err = st.Client().Status(nil)
+	c.Assert(err, gc.ErrorMatches, `password expired - change it with "juju user change-password"`)
+	err = usermanager.NewClient(st).SetPassword("bob", "new-password")
+	c.Assert(err, jc.ErrorIsNil)
+
+	info.Password = "new-password"
+	st2, err := api.Open(info, fastDialOpts)
+	c.Assert(err, jc.ErrorIsNil)
+	defer st2.Close()
+	_, err = st2.Client().Status(nil)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *loginSuite) TestLoginWithPermissions(c *gc.C) {
+	_, cleanup := s.setupServerWithValidator(c, nil)
+	defer cleanup()
+
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob", Password: "password"})
+	_, err := s.State.GrantPermission(state.UserPermissionSubject(user.UserTag()), "read", "")
+	c.Assert(err, jc.ErrorIsNil)
+
+	info := s.APIInfo(c)
+	info.Tag = user.Tag()
+	info.Password = "password"
+	st, err := api.Open(info, fastDialOpts)
+	c.Assert(err, jc.ErrorIsNil)
+	defer st.Close()
+
+	_, err = st.Client().Status(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = st.Client().EnvironmentSet(map[string]interface{}{"default-series": "trusty"})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *loginSuite) TestUserLoginRateLimit(c *gc.C) {
+	info, cleanup := s.setupServerWithValidator(c, nil)
+	defer cleanup()
+
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{"login-rate-limit": 2}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob", Password: "password"})
+
+	info.Tag = user.Tag()
+	info.Password = "password"
+	for i := 0; i < 2; i++ {
+		st, err := api.Open(info, fastDialOpts)
+		c.Assert(err, jc.ErrorIsNil)
+		st.Close()
+	}
+	_, err = api.Open(info, fastDialOpts)
+	c.Assert(err, jc.Satisfies, params.IsCodeTryAgain)
+}
@@ -6,6 +6,7 @@ package apiserver
 import (
 	"crypto/tls"
 	"crypto/x509"
+	"fmt"
 	"net"
 	"net/http"
 	"strings"
@@ -45,6 +46,9 @@ type Server struct {
 	dataDir           string
 	logDir            string
 	limiter           utils.Limiter
+	loginThrottle     *loginThrottle
+	apiRateLimiter    *apiRateLimiter
+	metrics           *serverMetrics
 	validator         LoginValidator
 	adminApiFactories map[int]adminApiFactory
 
@@ -166,13 +170,16 @@ func NewServer(s *state.State, lis net.Listener, cfg ServerConfig) (*Server, err
 func newServer(s *state.State, lis *net.TCPListener, cfg ServerConfig) (*Server, error) {
 	logger.Infof("listening on %q", lis.Addr())
 	srv := &Server{
-		state:     s,
-		addr:      lis.Addr().(*net.TCPAddr), // cannot fail
-		tag:       cfg.Tag,
-		dataDir:   cfg.DataDir,
-		logDir:    cfg.LogDir,
-		limiter:   utils.NewLimiter(loginRateLimit),
-		validator: cfg.Validator,
+		state:          s,
+		addr:           lis.Addr().(*net.TCPAddr), // cannot fail
+		tag:            cfg.Tag,
+		dataDir:        cfg.DataDir,
+		logDir:         cfg.LogDir,
+		limiter:        utils.NewLimiter(loginRateLimit),
+		loginThrottle:  newLoginThrottle(),
+		apiRateLimiter: newAPIRateLimiter(),
+		metrics:        newServerMetrics(),
+		validator:      cfg.Validator,
 		adminApiFactories: map[int]adminApiFactory{
 			0: newAdminApiV0,
 			1: newAdminApiV1,
@@ -219,8 +226,9 @@ type requestNotifier struct {
 	id    int64
 	start time.Time
 
-	mu   sync.Mutex
-	tag_ string
+	mu         sync.Mutex
+	tag_       string
+	remoteAddr string
 }
 
 var globalCounter int64
@@ -246,6 +254,17 @@ func (n *requestNotifier) tag() (tag string) {
 	return
 }
 
+// prefix returns the prefix of the log messages about a request with
+// the given trace id: the connection id, the tag of the entity logged
+// in and, if set, the trace id, so that "juju debug-log --trace" shows
+// them.
+func (n *requestNotifier) prefix(traceId string) string {
+	if traceId == "" {
+		return fmt.Sprintf("[%X] %s", n.id, n.tag())
+	}
+	return fmt.Sprintf("[%X] %s trace %s", n.id, n.tag(), traceId)
+}
+
 func (n *requestNotifier) ServerRequest(hdr *rpc.Header, body interface{}) {
 	if hdr.Request.Type == "Pinger" && hdr.Request.Action == "Ping" {
 		return
@@ -254,9 +273,9 @@ func (n *requestNotifier) ServerRequest(hdr *rpc.Header, body interface{}) {
 	// Until secrets are removed, we only log the body of the requests at trace level
 	// which is below the default level of debug.
 	if logger.IsTraceEnabled() {
-		logger.Tracef("<- [%X] %s %s", n.id, n.tag(), jsoncodec.DumpRequest(hdr, body))
+		logger.Tracef("<- %s %s", n.prefix(hdr.TraceId), jsoncodec.DumpRequest(hdr, body))
 	} else {
-		logger.Debugf("<- [%X] %s %s", n.id, n.tag(), jsoncodec.DumpRequest(hdr, "'params redacted'"))
+		logger.Debugf("<- %s %s", n.prefix(hdr.TraceId), jsoncodec.DumpRequest(hdr, "'params redacted'"))
 	}
 }
 
@@ -264,20 +283,41 @@ func (n *requestNotifier) ServerReply(req rpc.Request, hdr *rpc.Header, body int
 	if req.Type == "Pinger" && req.Action == "Ping" {
 		return
 	}
+	if hdr.Error != "" && hdr.TraceId != "" {
+		// Failed requests are logged at a level shown by the default
+		// logging config, so that the trace id reported by the client
+		// can always be looked up.
+		logger.Warningf("-> %s %s[%q].%s failed: %s", n.prefix(hdr.TraceId), req.Type, req.Id, req.Action, hdr.Error)
+		return
+	}
 	// TODO(rog) 2013-10-11 remove secrets from some responses.
 	// Until secrets are removed, we only log the body of the requests at trace level
 	// which is below the default level of debug.
 	if logger.IsTraceEnabled() {
-		logger.Tracef("-> [%X] %s %s", n.id, n.tag(), jsoncodec.DumpRequest(hdr, body))
+		logger.Tracef("-> %s %s", n.prefix(hdr.TraceId), jsoncodec.DumpRequest(hdr, body))
 	} else {
-		logger.Debugf("-> [%X] %s %s %s %s[%q].%s", n.id, n.tag(), timeSpent, jsoncodec.DumpRequest(hdr, "'body redacted'"), req.Type, req.Id, req.Action)
+		logger.Debugf("-> %s %s %s %s[%q].%s", n.prefix(hdr.TraceId), timeSpent, jsoncodec.DumpRequest(hdr, "'body redacted'"), req.Type, req.Id, req.Action)
 	}
 }
 
 func (n *requestNotifier) join(req *http.Request) {
+	n.mu.Lock()
+	n.remoteAddr = req.RemoteAddr
+	n.mu.Unlock()
 	logger.Infof("[%X] API connection from %s", n.id, req.RemoteAddr)
 }
 
+// remoteHost returns the host the API connection was made from.
+func (n *requestNotifier) remoteHost() string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	host, _, err := net.SplitHostPort(n.remoteAddr)
+	if err != nil {
+		return n.remoteAddr
+	}
+	return host
+}
+
 func (n *requestNotifier) leave() {
 	logger.Infof("[%X] %s API connection terminated after %v", n.id, n.tag(), time.Since(n.start))
 }
@@ -377,6 +417,15 @@ func (srv *Server) run(lis net.Listener) {
 			httpHandler{ssState: srv.state},
 		}},
 	)
+	handleAll(mux, "/metrics",
+		&metricsHandler{
+			httpHandler: httpHandler{
+				ssState:            srv.state,
+				stateServerEnvOnly: true,
+			},
+			metrics: srv.metrics,
+		},
+	)
 	handleAll(mux, "/", http.HandlerFunc(srv.apiHandler))
 
 	go func() {
@@ -430,6 +479,7 @@ func (srv *Server) serveConn(wsConn *websocket.Conn, reqNotifier *requestNotifie
 		notifier = reqNotifier
 	}
 	conn := rpc.NewConn(codec, notifier)
+	conn.SetMetrics(srv.metrics.requests)
 
 	var h *apiHandler
 	st, _, err := validateEnvironUUID(validateArgs{st: srv.state, envUUID: envUUID})
@@ -445,6 +495,8 @@ func (srv *Server) serveConn(wsConn *websocket.Conn, reqNotifier *requestNotifie
 		}
 		conn.ServeFinder(newAnonRoot(h, adminApis), serverError)
 	}
+	srv.metrics.connectionOpened(h)
+	defer srv.metrics.connectionClosed(h)
 	conn.Start()
 	select {
 	case <-conn.Dead():
@@ -0,0 +1,38 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// This is an internal package test.
+
+package apiserver
+
+import (
+	"github.com/juju/loggo"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/testing"
+)
+
+type requestNotifierSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&requestNotifierSuite{})
+
+func (s *requestNotifierSuite) TestServerReplyTraced(c *gc.C) {
+	var tw loggo.TestWriter
+	c.Assert(loggo.RegisterWriter("request-notifier-tests", &tw, loggo.WARNING), jc.ErrorIsNil)
+	defer loggo.RemoveWriter("request-notifier-tests")
+
+	n := newRequestNotifier()
+	n.login("user-bob")
+	req := rpc.Request{Type: "Client", Action: "FullStatus"}
+	n.ServerReply(req, &rpc.Header{RequestId: 1, TraceId: "a-trace"}, nil, 0)
+	n.ServerReply(req, &rpc.Header{RequestId: 2, Error: "boom"}, nil, 0)
+	n.ServerReply(req, &rpc.Header{RequestId: 3, Error: "boom", TraceId: "a-trace"}, nil, 0)
+
+	c.Check(tw.Log(), jc.LogMatches, []jc.SimpleMessage{
+		{loggo.WARNING, `-> \[[0-9A-F]+\] user-bob trace a-trace Client\[""\]\.FullStatus failed: boom`},
+	})
+}
@@ -0,0 +1,184 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package authentication
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/names"
+	"github.com/juju/utils/set"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/state"
+)
+
+var logger = loggo.GetLogger("juju.apiserver.authentication")
+
+// ExternalIdentity describes a user authenticated by an external
+// identity provider.
+type ExternalIdentity struct {
+	Name        string
+	DisplayName string
+	Groups      []string
+}
+
+// ExternalIdentityProvider authenticates users against an external
+// identity provider.
+type ExternalIdentityProvider interface {
+	// Authenticate checks the password of the named user, and
+	// returns the user's identity. It returns common.ErrBadCreds if
+	// the password is not valid.
+	Authenticate(name, password string) (*ExternalIdentity, error)
+}
+
+// NewExternalIdentityProvider returns the external identity provider
+// described by the given settings.
+func NewExternalIdentityProvider(opts config.ExternalIdentityOpts) (ExternalIdentityProvider, error) {
+	switch opts.Provider {
+	case config.LDAPIdentityProvider:
+		return &ldapIdentityProvider{url: opts.URL, userDN: opts.LDAPUserDN}, nil
+	case config.OIDCIdentityProvider:
+		return &oidcIdentityProvider{
+			issuer:       opts.URL,
+			clientId:     opts.OIDCClientID,
+			clientSecret: opts.OIDCClientSecret,
+		}, nil
+	}
+	return nil, errors.NotValidf("identity provider %q", opts.Provider)
+}
+
+// ExternalAuthenticator authenticates local users that have no password
+// stored in Juju against an external identity provider, adding them to
+// state on their first login.
+type ExternalAuthenticator struct {
+	Provider ExternalIdentityProvider
+
+	// GroupAccess maps the groups of the users to the access they
+	// are given; see config.ExternalIdentityOpts.
+	GroupAccess map[string]string
+}
+
+// NewExternalAuthenticator returns an authenticator for the external
+// identity provider configured in the state server environment, or nil
+// if there is none.
+func NewExternalAuthenticator(st *state.State) (*ExternalAuthenticator, error) {
+	env, err := st.StateServerEnvironment()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	cfg, err := env.Config()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	opts := cfg.ExternalIdentity()
+	if opts.Provider == "" {
+		return nil, nil
+	}
+	provider, err := NewExternalIdentityProvider(opts)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &ExternalAuthenticator{
+		Provider:    provider,
+		GroupAccess: opts.GroupAccess,
+	}, nil
+}
+
+// Access returns the access the identity is given through its groups,
+// or "" if none of its groups give it access.
+func (a *ExternalAuthenticator) Access(identity *ExternalIdentity) string {
+	var access string
+	for _, group := range identity.Groups {
+		switch a.GroupAccess[group] {
+		case config.ExternalAccessEnvironment:
+			return config.ExternalAccessEnvironment
+		case config.ExternalAccessLogin:
+			access = config.ExternalAccessLogin
+		}
+	}
+	return access
+}
+
+// Authenticate authenticates the user with the given tag, returning the
+// user from the given state, which is added if it does not exist yet.
+// If forEnviron is true and the user's groups give access to
+// environments, the user is also given access to the environment of the
+// state if needed.
+func (a *ExternalAuthenticator) Authenticate(st *state.State, tag names.UserTag, password string, forEnviron bool) (*state.User, error) {
+	identity, err := a.Provider.Authenticate(tag.Name(), password)
+	if err != nil {
+		return nil, err
+	}
+	access := a.Access(identity)
+	if access == "" {
+		logger.Infof("external user %q is not in any group with access", tag.Name())
+		return nil, common.ErrBadCreds
+	}
+	user, err := st.User(tag)
+	if errors.IsNotFound(err) {
+		user, err = a.addUser(st, tag, identity)
+	}
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !user.IsExternal() || user.IsDisabled() {
+		return nil, common.ErrBadCreds
+	}
+	if err := syncGroups(user, identity.Groups); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if forEnviron && access == config.ExternalAccessEnvironment {
+		if err := a.addEnvironmentUser(st, tag, identity); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return user, nil
+}
+
+func (a *ExternalAuthenticator) addUser(st *state.State, tag names.UserTag, identity *ExternalIdentity) (*state.User, error) {
+	env, err := st.StateServerEnvironment()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	logger.Infof("adding external user %q", tag.Name())
+	user, err := st.AddExternalUser(tag.Name(), identity.DisplayName, env.Owner().Name())
+	if errors.IsAlreadyExists(err) {
+		// The user logged in concurrently.
+		return st.User(tag)
+	}
+	return user, err
+}
+
+func (a *ExternalAuthenticator) addEnvironmentUser(st *state.State, tag names.UserTag, identity *ExternalIdentity) error {
+	if _, err := st.EnvironmentUser(tag); !errors.IsNotFound(err) {
+		return err
+	}
+	env, err := st.Environment()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	logger.Infof("giving external user %q access to environment %q", tag.Name(), env.Name())
+	_, err = st.AddEnvironmentUser(tag, env.Owner(), identity.DisplayName)
+	if errors.IsAlreadyExists(err) {
+		return nil
+	}
+	return err
+}
+
+// syncGroups records the groups of the identity on the user, so that
+// permissions granted to the groups apply to the user.
+func syncGroups(user *state.User, groups []string) error {
+	var valid []string
+	for _, group := range groups {
+		if state.IsValidPermissionGroup(group) {
+			valid = append(valid, group)
+		}
+	}
+	current := set.NewStrings(user.Groups()...)
+	if current.Size() == len(valid) && current.Difference(set.NewStrings(valid...)).IsEmpty() {
+		return nil
+	}
+	return user.SetGroups(valid)
+}
@@ -0,0 +1,250 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package authentication_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/authentication"
+	authenticationtesting "github.com/juju/juju/apiserver/authentication/testing"
+	"github.com/juju/juju/environs/config"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/testing/factory"
+)
+
+type externalAuthenticatorSuite struct {
+	jujutesting.JujuConnSuite
+}
+
+var _ = gc.Suite(&externalAuthenticatorSuite{})
+
+func (s *externalAuthenticatorSuite) TestNoProvider(c *gc.C) {
+	authenticator, err := authentication.NewExternalAuthenticator(s.State)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(authenticator, gc.IsNil)
+}
+
+func (s *externalAuthenticatorSuite) TestAccess(c *gc.C) {
+	authenticator := &authentication.ExternalAuthenticator{
+		GroupAccess: map[string]string{
+			"admins": config.ExternalAccessEnvironment,
+			"staff":  config.ExternalAccessLogin,
+		},
+	}
+	for i, test := range []struct {
+		groups []string
+		access string
+	}{{
+		groups: nil,
+		access: "",
+	}, {
+		groups: []string{"visitors"},
+		access: "",
+	}, {
+		groups: []string{"visitors", "staff"},
+		access: "login",
+	}, {
+		groups: []string{"staff", "admins"},
+		access: "environment",
+	}} {
+		c.Logf("test %d: %v", i, test.groups)
+		access := authenticator.Access(&authentication.ExternalIdentity{Groups: test.groups})
+		c.Check(access, gc.Equals, test.access)
+	}
+}
+
+func (s *externalAuthenticatorSuite) TestLDAP(c *gc.C) {
+	directory, err := authenticationtesting.NewLDAPDirectory()
+	c.Assert(err, jc.ErrorIsNil)
+	defer directory.Close()
+	directory.AddEntry("uid=bob,ou=people,dc=example,dc=com", authenticationtesting.LDAPEntry{
+		Password: "sekrit",
+		Attributes: map[string][]string{
+			"displayName": {"Bob Brown"},
+			"memberOf": {
+				"cn=admins,ou=groups,dc=example,dc=com",
+				"cn=staff,ou=groups,dc=example,dc=com",
+			},
+		},
+	})
+	provider, err := authentication.NewExternalIdentityProvider(config.ExternalIdentityOpts{
+		Provider:   config.LDAPIdentityProvider,
+		URL:        directory.URL(),
+		LDAPUserDN: "uid=%s,ou=people,dc=example,dc=com",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	identity, err := provider.Authenticate("bob", "sekrit")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(identity, jc.DeepEquals, &authentication.ExternalIdentity{
+		Name:        "bob",
+		DisplayName: "Bob Brown",
+		Groups:      []string{"admins", "staff"},
+	})
+
+	_, err = provider.Authenticate("bob", "wrong")
+	c.Assert(err, gc.ErrorMatches, "invalid entity name or password")
+	_, err = provider.Authenticate("bob", "")
+	c.Assert(err, gc.ErrorMatches, "invalid entity name or password")
+	_, err = provider.Authenticate("nobody", "sekrit")
+	c.Assert(err, gc.ErrorMatches, "invalid entity name or password")
+}
+
+func (s *externalAuthenticatorSuite) TestLDAPEscapesUserName(c *gc.C) {
+	directory, err := authenticationtesting.NewLDAPDirectory()
+	c.Assert(err, jc.ErrorIsNil)
+	defer directory.Close()
+	directory.AddEntry("uid=bob,ou=people,dc=example,dc=com", authenticationtesting.LDAPEntry{
+		Password: "sekrit",
+	})
+	directory.AddEntry(`uid=\ bob\+ops\,dc\=x\;\\\"\<\>\ ,ou=people,dc=example,dc=com`, authenticationtesting.LDAPEntry{
+		Password: "sekrit",
+	})
+	directory.AddEntry(`uid=\#bob#,ou=people,dc=example,dc=com`, authenticationtesting.LDAPEntry{
+		Password: "sekrit",
+	})
+	provider, err := authentication.NewExternalIdentityProvider(config.ExternalIdentityOpts{
+		Provider:   config.LDAPIdentityProvider,
+		URL:        directory.URL(),
+		LDAPUserDN: "uid=%s,ou=people,dc=example,dc=com",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	for _, name := range []string{` bob+ops,dc=x;\"<> `, "#bob#"} {
+		identity, err := provider.Authenticate(name, "sekrit")
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(identity, jc.DeepEquals, &authentication.ExternalIdentity{Name: name})
+	}
+
+	// A user name cannot change the structure of the user's DN.
+	_, err = provider.Authenticate("bob,ou=people,dc=example,dc=com", "sekrit")
+	c.Assert(err, gc.ErrorMatches, "invalid entity name or password")
+}
+
+// newOIDCServer returns a server standing in for an OpenID Connect
+// provider that knows the single user bob.
+func newOIDCServer() *httptest.Server {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	writeJSON := func(w http.ResponseWriter, v interface{}) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(v)
+	}
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, req *http.Request) {
+		writeJSON(w, map[string]string{
+			"issuer":            server.URL,
+			"token_endpoint":    server.URL + "/token",
+			"userinfo_endpoint": server.URL + "/userinfo",
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, req *http.Request) {
+		if req.FormValue("grant_type") != "password" ||
+			req.FormValue("username") != "bob" ||
+			req.FormValue("password") != "sekrit" {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+		writeJSON(w, map[string]interface{}{
+			"access_token": "bob-token",
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+		})
+	})
+	mux.HandleFunc("/userinfo", func(w http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Authorization") != "Bearer bob-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		writeJSON(w, map[string]interface{}{
+			"preferred_username": "bob",
+			"name":               "Bob Brown",
+			"groups":             []string{"admins"},
+		})
+	})
+	return server
+}
+
+func (s *externalAuthenticatorSuite) TestOIDC(c *gc.C) {
+	server := newOIDCServer()
+	defer server.Close()
+	provider, err := authentication.NewExternalIdentityProvider(config.ExternalIdentityOpts{
+		Provider:         config.OIDCIdentityProvider,
+		URL:              server.URL,
+		OIDCClientID:     "juju",
+		OIDCClientSecret: "juju-secret",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	identity, err := provider.Authenticate("bob", "sekrit")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(identity, jc.DeepEquals, &authentication.ExternalIdentity{
+		Name:        "bob",
+		DisplayName: "Bob Brown",
+		Groups:      []string{"admins"},
+	})
+
+	_, err = provider.Authenticate("bob", "wrong")
+	c.Assert(err, gc.ErrorMatches, "invalid entity name or password")
+	_, err = provider.Authenticate("carol", "sekrit")
+	c.Assert(err, gc.ErrorMatches, "invalid entity name or password")
+}
+
+func (s *externalAuthenticatorSuite) TestAuthenticateAddsUser(c *gc.C) {
+	server := newOIDCServer()
+	defer server.Close()
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{
+		"identity-provider":     "oidc",
+		"identity-url":          server.URL,
+		"oidc-client-id":        "juju",
+		"identity-group-access": "admins=environment",
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	authenticator, err := authentication.NewExternalAuthenticator(s.State)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(authenticator, gc.NotNil)
+
+	tag := names.NewLocalUserTag("bob")
+	user, err := authenticator.Authenticate(s.State, tag, "sekrit", false)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(user.Name(), gc.Equals, "bob")
+	c.Assert(user.DisplayName(), gc.Equals, "Bob Brown")
+	c.Assert(user.IsExternal(), jc.IsTrue)
+	c.Assert(user.Groups(), jc.DeepEquals, []string{"admins"})
+	_, err = s.State.EnvironmentUser(tag)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	user, err = authenticator.Authenticate(s.State, tag, "sekrit", true)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.EnvironmentUser(tag)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = user.Disable()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = authenticator.Authenticate(s.State, tag, "sekrit", true)
+	c.Assert(err, gc.ErrorMatches, "invalid entity name or password")
+}
+
+func (s *externalAuthenticatorSuite) TestAuthenticateLocalUser(c *gc.C) {
+	server := newOIDCServer()
+	defer server.Close()
+	s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	authenticator := &authentication.ExternalAuthenticator{
+		GroupAccess: map[string]string{"admins": config.ExternalAccessEnvironment},
+	}
+	authenticator.Provider, _ = authentication.NewExternalIdentityProvider(config.ExternalIdentityOpts{
+		Provider: config.OIDCIdentityProvider,
+		URL:      server.URL,
+	})
+	_, err := authenticator.Authenticate(s.State, names.NewLocalUserTag("bob"), "sekrit", true)
+	c.Assert(err, gc.ErrorMatches, "invalid entity name or password")
+}
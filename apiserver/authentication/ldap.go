@@ -0,0 +1,136 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package authentication
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/ldap.v2"
+
+	"github.com/juju/juju/apiserver/common"
+)
+
+// ldapTimeout is the time allowed for a connection to an LDAP server,
+// and for each request made on it.
+var ldapTimeout = 30 * time.Second
+
+// ldapIdentityProvider authenticates users by binding to an LDAP
+// server as the users' entries. The groups of a user are taken from
+// the memberOf attribute of the user's entry.
+type ldapIdentityProvider struct {
+	url    string
+	userDN string
+}
+
+// Authenticate implements ExternalIdentityProvider.
+func (p *ldapIdentityProvider) Authenticate(name, password string) (*ExternalIdentity, error) {
+	// An empty password is refused rather than sent, as LDAP servers
+	// treat it as an anonymous bind that always succeeds.
+	if password == "" {
+		return nil, common.ErrBadCreds
+	}
+	conn, err := dialLDAP(p.url)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot connect to LDAP server")
+	}
+	defer conn.Close()
+	dn := fmt.Sprintf(p.userDN, escapeDNValue(name))
+	if err := conn.Bind(dn, password); ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+		return nil, common.ErrBadCreds
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot bind to LDAP server as %q", dn)
+	}
+	result, err := conn.Search(ldap.NewSearchRequest(
+		dn, ldap.ScopeBaseObject, ldap.NeverDerefAliases, 0, 0, false,
+		"(objectClass=*)", []string{"displayName", "memberOf"}, nil,
+	))
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot read LDAP entry %q", dn)
+	}
+	identity := &ExternalIdentity{Name: name}
+	for _, entry := range result.Entries {
+		for _, attr := range entry.Attributes {
+			switch {
+			case strings.EqualFold(attr.Name, "displayName") && len(attr.Values) > 0:
+				identity.DisplayName = attr.Values[0]
+			case strings.EqualFold(attr.Name, "memberOf"):
+				for _, groupDN := range attr.Values {
+					identity.Groups = append(identity.Groups, groupName(groupDN))
+				}
+			}
+		}
+	}
+	return identity, nil
+}
+
+// dialLDAP connects to the LDAP server with the given ldap:// or
+// ldaps:// URL.
+func dialLDAP(rawurl string) (*ldap.Conn, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	dialer := &net.Dialer{Timeout: ldapTimeout}
+	var conn net.Conn
+	switch u.Scheme {
+	case "ldap":
+		conn, err = dialer.Dial("tcp", withPort(u.Host, "389"))
+	case "ldaps":
+		conn, err = tls.DialWithDialer(dialer, "tcp", withPort(u.Host, "636"), nil)
+	default:
+		return nil, errors.NotValidf("LDAP URL %q", rawurl)
+	}
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ldapConn := ldap.NewConn(conn, u.Scheme == "ldaps")
+	ldapConn.SetTimeout(ldapTimeout)
+	ldapConn.Start()
+	return ldapConn, nil
+}
+
+func withPort(host, port string) string {
+	if _, _, err := net.SplitHostPort(host); err == nil {
+		return host
+	}
+	return net.JoinHostPort(host, port)
+}
+
+// escapeDNValue escapes s as an attribute value of a distinguished
+// name, as described in RFC 4514, so that a user name holding special
+// characters such as "," or "+" cannot change the structure of the
+// name it is substituted into.
+func escapeDNValue(s string) string {
+	var buf []byte
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == 0:
+			buf = append(buf, `\00`...)
+		case strings.IndexByte(`"+,;<=>\`, c) >= 0,
+			(c == ' ' || c == '#') && i == 0,
+			c == ' ' && i == len(s)-1:
+			buf = append(buf, '\\', c)
+		default:
+			buf = append(buf, c)
+		}
+	}
+	return string(buf)
+}
+
+// groupName returns the name of the group with the given distinguished
+// name: the value of its first relative distinguished name, so that
+// "cn=admins,ou=groups,dc=example,dc=com" is named "admins".
+func groupName(dn string) string {
+	rdn := strings.SplitN(dn, ",", 2)[0]
+	if i := strings.Index(rdn, "="); i >= 0 {
+		return strings.TrimSpace(rdn[i+1:])
+	}
+	return rdn
+}
@@ -0,0 +1,103 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package authentication
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"golang.org/x/net/context"
+	"golang.org/x/oauth2"
+
+	"github.com/juju/juju/apiserver/common"
+)
+
+// oidcTimeout is the time allowed for each request to an OpenID
+// Connect provider.
+var oidcTimeout = 30 * time.Second
+
+// oidcIdentityProvider authenticates users with an OpenID Connect
+// provider, exchanging their passwords for an access token with the
+// resource owner password credentials grant. The identity of a user is
+// taken from the provider's userinfo endpoint; the groups from the
+// "groups" claim.
+type oidcIdentityProvider struct {
+	issuer       string
+	clientId     string
+	clientSecret string
+}
+
+// oidcConfiguration holds the parts of the provider configuration
+// document that are used.
+type oidcConfiguration struct {
+	TokenEndpoint    string `json:"token_endpoint"`
+	UserinfoEndpoint string `json:"userinfo_endpoint"`
+}
+
+// oidcUserInfo holds the claims about a user that are used.
+type oidcUserInfo struct {
+	PreferredUsername string   `json:"preferred_username"`
+	Name              string   `json:"name"`
+	Groups            []string `json:"groups"`
+}
+
+// Authenticate implements ExternalIdentityProvider.
+func (p *oidcIdentityProvider) Authenticate(name, password string) (*ExternalIdentity, error) {
+	if password == "" {
+		return nil, common.ErrBadCreds
+	}
+	client := &http.Client{Timeout: oidcTimeout}
+	var discovered oidcConfiguration
+	configURL := strings.TrimSuffix(p.issuer, "/") + "/.well-known/openid-configuration"
+	if err := getJSON(client, configURL, &discovered); err != nil {
+		return nil, errors.Annotate(err, "cannot get OpenID Connect provider configuration")
+	}
+	cfg := &oauth2.Config{
+		ClientID:     p.clientId,
+		ClientSecret: p.clientSecret,
+		Endpoint:     oauth2.Endpoint{TokenURL: discovered.TokenEndpoint},
+		Scopes:       []string{"openid", "profile", "groups"},
+	}
+	ctx := context.WithValue(oauth2.NoContext, oauth2.HTTPClient, client)
+	token, err := cfg.PasswordCredentialsToken(ctx, name, password)
+	if err != nil {
+		// The provider refuses a token for invalid credentials, and
+		// does not tell them apart from other failures.
+		logger.Debugf("cannot get OpenID Connect token for %q: %v", name, err)
+		return nil, common.ErrBadCreds
+	}
+	tokenClient := cfg.Client(ctx, token)
+	tokenClient.Timeout = oidcTimeout
+	var info oidcUserInfo
+	if err := getJSON(tokenClient, discovered.UserinfoEndpoint, &info); err != nil {
+		return nil, errors.Annotate(err, "cannot get OpenID Connect user info")
+	}
+	if info.PreferredUsername != "" && info.PreferredUsername != name {
+		logger.Warningf("OpenID Connect token for %q issued to %q", name, info.PreferredUsername)
+		return nil, common.ErrBadCreds
+	}
+	return &ExternalIdentity{
+		Name:        name,
+		DisplayName: info.Name,
+		Groups:      info.Groups,
+	}, nil
+}
+
+func getJSON(client *http.Client, url string, v interface{}) error {
+	resp, err := client.Get(url)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("%s: %s", url, resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return errors.Annotatef(err, "cannot decode %s", url)
+	}
+	return nil
+}
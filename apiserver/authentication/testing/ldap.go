@@ -0,0 +1,173 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package testing
+
+import (
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/juju/errors"
+	"gopkg.in/asn1-ber.v1"
+	"gopkg.in/ldap.v2"
+)
+
+// LDAPEntry holds an entry of an LDAPDirectory.
+type LDAPEntry struct {
+	// Password holds the password the entry binds with.
+	Password string
+
+	// Attributes holds the values of the attributes of the entry.
+	Attributes map[string][]string
+}
+
+// LDAPDirectory is a minimal in-memory LDAP server, supporting the
+// simple binds and base object searches made by the LDAP identity
+// provider. It stands in for a real directory in tests.
+type LDAPDirectory struct {
+	listener net.Listener
+	wg       sync.WaitGroup
+
+	mu      sync.Mutex
+	entries map[string]LDAPEntry
+}
+
+// NewLDAPDirectory returns a new directory listening on the loopback
+// interface.
+func NewLDAPDirectory() (*LDAPDirectory, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	d := &LDAPDirectory{
+		listener: listener,
+		entries:  make(map[string]LDAPEntry),
+	}
+	d.wg.Add(1)
+	go d.serve()
+	return d, nil
+}
+
+// URL returns the ldap:// URL of the directory.
+func (d *LDAPDirectory) URL() string {
+	return "ldap://" + d.listener.Addr().String()
+}
+
+// AddEntry adds the entry with the given distinguished name to the
+// directory, replacing any existing entry. The name is compared with
+// the names sent by clients as it is, so any special characters in it
+// must be escaped as the clients escape them.
+func (d *LDAPDirectory) AddEntry(dn string, entry LDAPEntry) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.entries[strings.ToLower(dn)] = entry
+}
+
+// Close stops the directory listening; connections in progress are
+// served until their clients close them.
+func (d *LDAPDirectory) Close() error {
+	err := d.listener.Close()
+	d.wg.Wait()
+	return err
+}
+
+func (d *LDAPDirectory) entry(dn string) (LDAPEntry, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	entry, ok := d.entries[strings.ToLower(dn)]
+	return entry, ok
+}
+
+func (d *LDAPDirectory) serve() {
+	defer d.wg.Done()
+	for {
+		conn, err := d.listener.Accept()
+		if err != nil {
+			return
+		}
+		go d.serveConn(conn)
+	}
+}
+
+func (d *LDAPDirectory) serveConn(conn net.Conn) {
+	defer conn.Close()
+	for {
+		msg, err := ber.ReadPacket(conn)
+		if err != nil || len(msg.Children) < 2 {
+			return
+		}
+		var replies []*ber.Packet
+		switch op := msg.Children[1]; op.Tag {
+		case ldap.ApplicationBindRequest:
+			replies = d.bind(op)
+		case ldap.ApplicationSearchRequest:
+			replies = d.search(op)
+		default:
+			// Unbind requests, and any others, end the connection.
+			return
+		}
+		for _, reply := range replies {
+			response := ber.NewSequence("LDAP Response")
+			response.AppendChild(msg.Children[0])
+			response.AppendChild(reply)
+			if _, err := conn.Write(response.Bytes()); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func ldapResult(tag ber.Tag, code int, message string) *ber.Packet {
+	result := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "LDAP Result")
+	result.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, code, "Result Code"))
+	result.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Matched DN"))
+	result.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, message, "Error Message"))
+	return result
+}
+
+func (d *LDAPDirectory) bind(op *ber.Packet) []*ber.Packet {
+	if len(op.Children) != 3 || op.Children[2].ClassType != ber.ClassContext || op.Children[2].Tag != 0 {
+		return []*ber.Packet{ldapResult(ldap.ApplicationBindResponse, ldap.LDAPResultProtocolError, "malformed bind request")}
+	}
+	entry, ok := d.entry(op.Children[1].Data.String())
+	password := op.Children[2].Data.String()
+	if !ok || password == "" || entry.Password != password {
+		return []*ber.Packet{ldapResult(ldap.ApplicationBindResponse, ldap.LDAPResultInvalidCredentials, "invalid credentials")}
+	}
+	return []*ber.Packet{ldapResult(ldap.ApplicationBindResponse, ldap.LDAPResultSuccess, "")}
+}
+
+func (d *LDAPDirectory) search(op *ber.Packet) []*ber.Packet {
+	if len(op.Children) != 8 {
+		return []*ber.Packet{ldapResult(ldap.ApplicationSearchResultDone, ldap.LDAPResultProtocolError, "malformed search request")}
+	}
+	dn := op.Children[0].Data.String()
+	entry, ok := d.entry(dn)
+	if !ok {
+		return []*ber.Packet{ldapResult(ldap.ApplicationSearchResultDone, ldap.LDAPResultNoSuchObject, "no such object")}
+	}
+	result := ber.Encode(ber.ClassApplication, ber.TypeConstructed, ldap.ApplicationSearchResultEntry, nil, "Search Result Entry")
+	result.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, dn, "Object Name"))
+	attributes := ber.NewSequence("Attributes")
+	for _, name := range op.Children[7].Children {
+		for key, values := range entry.Attributes {
+			if !strings.EqualFold(key, name.Data.String()) {
+				continue
+			}
+			attribute := ber.NewSequence("Attribute")
+			attribute.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, key, "Type"))
+			set := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "Values")
+			for _, value := range values {
+				set.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, value, "Value"))
+			}
+			attribute.AppendChild(set)
+			attributes.AppendChild(attribute)
+		}
+	}
+	result.AppendChild(attributes)
+	return []*ber.Packet{
+		result,
+		ldapResult(ldap.ApplicationSearchResultDone, ldap.LDAPResultSuccess, ""),
+	}
+}
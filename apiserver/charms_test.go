@@ -87,6 +87,22 @@ func (s *charmsSuite) TestAuthRequiresUser(c *gc.C) {
 	s.assertErrorResponse(c, resp, http.StatusBadRequest, "expected series=URL argument")
 }
 
+func (s *charmsSuite) TestAuthRefusesRestrictedUser(c *gc.C) {
+	subject := state.UserPermissionSubject(s.userTag)
+	_, err := s.State.GrantPermission(subject, "read", "")
+	c.Assert(err, jc.ErrorIsNil)
+	resp, err := s.authRequest(c, "POST", s.charmsURI(c, ""), "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertErrorResponse(c, resp, http.StatusUnauthorized, "unauthorized")
+
+	// Users granted all operations are allowed.
+	_, err = s.State.GrantPermission(subject, "admin", "")
+	c.Assert(err, jc.ErrorIsNil)
+	resp, err = s.authRequest(c, "POST", s.charmsURI(c, ""), "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertErrorResponse(c, resp, http.StatusBadRequest, "expected series=URL argument")
+}
+
 func (s *charmsSuite) TestUploadRequiresSeries(c *gc.C) {
 	resp, err := s.authRequest(c, "POST", s.charmsURI(c, ""), "", nil)
 	c.Assert(err, jc.ErrorIsNil)
@@ -0,0 +1,566 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"github.com/juju/utils/set"
+	"gopkg.in/juju/charm.v5"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/apiserver/service"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/state"
+)
+
+// ApplyChanges validates the given ordered list of changes to the
+// environment and, if they are all valid, applies them in order. A
+// config change of a service immediately following its deployment or
+// another config change of the service is folded into it, so that
+// they are written together. A result is returned for each change: if
+// any change is invalid, none is applied. If a change fails to apply,
+// the changes after it are not applied and those before it are undone
+// as far as possible: deployed services, added units, the machines
+// added for them and added relations are destroyed, and config and
+// exposure are restored.
+func (c *Client) ApplyChanges(args params.ApplyChanges) (params.ApplyChangesResults, error) {
+	if err := c.check.ChangeAllowed(); err != nil {
+		return params.ApplyChangesResults{}, errors.Trace(err)
+	}
+	results := params.ApplyChangesResults{
+		Results: make([]params.ApplyChangeResult, len(args.Changes)),
+	}
+	plan := newChangesPlan(c.api.state, c.api.auth.GetAuthTag().String())
+	invalid := -1
+	for i, change := range args.Changes {
+		if err := plan.add(i, change); err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			if invalid < 0 {
+				invalid = i
+			}
+		}
+	}
+	if invalid >= 0 {
+		for i := range results.Results {
+			if results.Results[i].Error == nil {
+				results.Results[i].Error = common.ServerError(
+					fmt.Errorf("not applied: change %d is invalid", invalid),
+				)
+			}
+		}
+		return results, nil
+	}
+	plan.apply(results.Results)
+	return results, nil
+}
+
+// changeStep is a single write to state applying one or more changes.
+type changeStep struct {
+	// changes holds the indices of the changes applied by the step,
+	// the first of which reports the step's units and endpoints.
+	changes []int
+	run     func() (params.ApplyChangeResult, error)
+	// undo reverts the step after it has run, when a later step
+	// fails.
+	undo func() error
+}
+
+// changesPlan validates a list of changes against the services in
+// state and those deployed by earlier changes, and records the steps
+// applying them.
+type changesPlan struct {
+	st    *state.State
+	owner string
+	steps []*changeStep
+
+	// charms holds the charm of each service known to the plan.
+	charms map[string]*state.Charm
+	// deploys holds the services deployed by the plan.
+	deploys map[string]*deployStep
+	// configs holds the options set by the last config step of each
+	// service in state, and the step writing them.
+	configs map[string]*configStep
+	// machines holds the ids of the machines that existed before the
+	// plan was applied.
+	machines set.Strings
+}
+
+// deployStep holds the parameters of a service deployed by the plan,
+// and the step deploying it. Its config changes are folded into the
+// parameters unless its config is given as YAML.
+type deployStep struct {
+	step *changeStep
+	args *params.ServiceDeploy
+}
+
+type configStep struct {
+	step    *changeStep
+	options map[string]string
+}
+
+func newChangesPlan(st *state.State, owner string) *changesPlan {
+	return &changesPlan{
+		st:      st,
+		owner:   owner,
+		charms:  make(map[string]*state.Charm),
+		deploys: make(map[string]*deployStep),
+		configs: make(map[string]*configStep),
+	}
+}
+
+// add validates the change with the given index and records the step
+// applying it.
+func (p *changesPlan) add(index int, change params.EnvironmentChange) error {
+	switch change.Kind {
+	case params.ChangeAddService:
+		if change.AddService == nil {
+			break
+		}
+		return p.addService(index, *change.AddService)
+	case params.ChangeAddUnits:
+		if change.AddUnits == nil {
+			break
+		}
+		return p.addUnits(index, *change.AddUnits)
+	case params.ChangeAddRelation:
+		if change.AddRelation == nil {
+			break
+		}
+		return p.addRelation(index, change.AddRelation.Endpoints)
+	case params.ChangeSetConfig:
+		if change.SetConfig == nil {
+			break
+		}
+		return p.setConfig(index, *change.SetConfig)
+	case params.ChangeExpose:
+		if change.Expose == nil {
+			break
+		}
+		return p.expose(index, change.Expose.ServiceName)
+	default:
+		return errors.NotValidf("change kind %q", change.Kind)
+	}
+	return errors.Errorf("no parameters for %s change", change.Kind)
+}
+
+// charm returns the charm of the named service, which must either be
+// deployed by an earlier change or exist in state.
+func (p *changesPlan) charm(serviceName string) (*state.Charm, error) {
+	if ch, ok := p.charms[serviceName]; ok {
+		return ch, nil
+	}
+	svc, err := p.st.Service(serviceName)
+	if err != nil {
+		return nil, err
+	}
+	ch, _, err := svc.Charm()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	p.charms[serviceName] = ch
+	return ch, nil
+}
+
+func (p *changesPlan) addService(index int, args params.ServiceDeploy) error {
+	if !names.IsValidService(args.ServiceName) {
+		return errors.NotValidf("service name %q", args.ServiceName)
+	}
+	if _, err := p.charm(args.ServiceName); err == nil {
+		return errors.AlreadyExistsf("service %q", args.ServiceName)
+	} else if !errors.IsNotFound(err) {
+		return errors.Trace(err)
+	}
+	curl, err := charm.ParseURL(args.CharmUrl)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if curl.Revision < 0 {
+		return errors.Errorf("charm url must include revision")
+	}
+	ch, err := p.st.Charm(curl)
+	if err != nil {
+		return errors.Annotate(err, "charm must be added before it is deployed")
+	}
+	if len(args.ConfigYAML) > 0 {
+		_, err = ch.Config().ParseSettingsYAML([]byte(args.ConfigYAML), args.ServiceName)
+	} else {
+		_, err = service.ParseSettingsCompatible(ch, args.Config)
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if args.NumUnits > 0 {
+		machineIds, err := p.targetMachines(args.ToMachineSpec, args.Placement)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		for _, machineId := range machineIds {
+			if _, err := p.st.Machine(machineId); err != nil {
+				return errors.Annotatef(err, `cannot deploy "%v" to machine %v`, args.ServiceName, machineId)
+			}
+		}
+	}
+	if len(args.ConfigYAML) == 0 {
+		// Copy the options, as later config changes are merged in.
+		options := make(map[string]string)
+		for name, value := range args.Config {
+			options[name] = value
+		}
+		args.Config = options
+	}
+	run := func() (params.ApplyChangeResult, error) {
+		if err := service.DeployService(p.st, p.owner, args); err != nil {
+			return params.ApplyChangeResult{}, err
+		}
+		svc, err := p.st.Service(args.ServiceName)
+		if err != nil {
+			return params.ApplyChangeResult{}, err
+		}
+		units, err := svc.AllUnits()
+		if err != nil {
+			return params.ApplyChangeResult{}, err
+		}
+		return params.ApplyChangeResult{Units: unitNames(units)}, nil
+	}
+	undo := func() error {
+		svc, err := p.st.Service(args.ServiceName)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		units, err := svc.AllUnits()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if err := p.destroyUnits(unitNames(units)); err != nil {
+			return errors.Trace(err)
+		}
+		return svc.Destroy()
+	}
+	step := p.addStep(index, run, undo)
+	p.charms[args.ServiceName] = ch
+	p.deploys[args.ServiceName] = &deployStep{step: step, args: &args}
+	return nil
+}
+
+func (p *changesPlan) addUnits(index int, args params.AddServiceUnits) error {
+	if _, err := p.charm(args.ServiceName); err != nil {
+		return errors.Trace(err)
+	}
+	if args.NumUnits < 1 {
+		return errors.Errorf("must add at least one unit")
+	}
+	if len(args.Placement) == 0 && args.NumUnits > 1 && args.ToMachineSpec != "" {
+		return errors.Errorf("cannot use NumUnits with ToMachineSpec")
+	}
+	machineIds, err := p.targetMachines(args.ToMachineSpec, args.Placement)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, machineId := range machineIds {
+		if _, err := p.st.Machine(machineId); err != nil {
+			return errors.Annotatef(err, `cannot add units for service "%v" to machine %v`, args.ServiceName, machineId)
+		}
+	}
+	var added []string
+	p.addStep(index, func() (params.ApplyChangeResult, error) {
+		units, err := addServiceUnits(p.st, args)
+		if err != nil {
+			return params.ApplyChangeResult{}, err
+		}
+		added = unitNames(units)
+		return params.ApplyChangeResult{Units: added}, nil
+	}, func() error {
+		return p.destroyUnits(added)
+	})
+	return nil
+}
+
+// targetMachines returns the ids of the existing machines units are
+// to be added to, given either as a machine spec or as placement
+// directives. Directives with an unknown scope are invalid.
+func (p *changesPlan) targetMachines(toMachineSpec string, placement []*instance.Placement) ([]string, error) {
+	var machineIds []string
+	if len(placement) == 0 && toMachineSpec != "" {
+		// The machine spec is either a machine id or a new
+		// container on a machine, e.g. "lxc:1".
+		machineId := toMachineSpec
+		if parts := strings.SplitN(toMachineSpec, ":", 2); len(parts) == 2 {
+			if _, err := instance.ParseContainerType(parts[0]); err == nil {
+				machineId = parts[1]
+			}
+		}
+		if names.IsValidMachine(machineId) {
+			machineIds = append(machineIds, machineId)
+		}
+	}
+	for _, placement := range placement {
+		if _, err := instance.ParseContainerType(placement.Scope); err != nil {
+			switch placement.Scope {
+			case p.st.EnvironUUID():
+				continue
+			case instance.MachineScope:
+			default:
+				return nil, errors.Errorf("invalid environment UUID %q", placement.Scope)
+			}
+		}
+		if placement.Directive != "" {
+			machineIds = append(machineIds, placement.Directive)
+		}
+	}
+	return machineIds, nil
+}
+
+func (p *changesPlan) addRelation(index int, endpoints []string) error {
+	if len(endpoints) != 1 && len(endpoints) != 2 {
+		return errors.Errorf("cannot relate %d endpoints", len(endpoints))
+	}
+	inState := true
+	for _, endpoint := range endpoints {
+		serviceName, relationName := endpoint, ""
+		if i := strings.Index(endpoint, ":"); i != -1 {
+			serviceName, relationName = endpoint[:i], endpoint[i+1:]
+		}
+		ch, err := p.charm(serviceName)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if _, ok := p.deploys[serviceName]; ok {
+			inState = false
+		}
+		if relationName != "" && !hasRelation(ch.Meta(), relationName) {
+			return errors.Errorf("service %q has no %q relation", serviceName, relationName)
+		}
+	}
+	if inState {
+		// The endpoints can be fully checked now, rather than
+		// when the services are deployed.
+		if _, err := p.st.InferEndpoints(endpoints...); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	p.addStep(index, func() (params.ApplyChangeResult, error) {
+		results, err := addRelation(p.st, endpoints)
+		if err != nil {
+			return params.ApplyChangeResult{}, err
+		}
+		return params.ApplyChangeResult{Endpoints: results.Endpoints}, nil
+	}, func() error {
+		eps, err := p.st.InferEndpoints(endpoints...)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		rel, err := p.st.EndpointsRelation(eps...)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		return rel.Destroy()
+	})
+	return nil
+}
+
+func (p *changesPlan) setConfig(index int, args params.ServiceSet) error {
+	ch, err := p.charm(args.ServiceName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if _, err := service.ParseSettingsCompatible(ch, args.Options); err != nil {
+		return errors.Trace(err)
+	}
+	// Only config changes directly following the step they are folded
+	// into are folded, so that no write is moved past another change.
+	last := p.lastStep()
+	if deploy, ok := p.deploys[args.ServiceName]; ok && len(deploy.args.ConfigYAML) == 0 && deploy.step == last {
+		for name, value := range args.Options {
+			deploy.args.Config[name] = value
+		}
+		deploy.step.changes = append(deploy.step.changes, index)
+		return nil
+	}
+	if config, ok := p.configs[args.ServiceName]; ok && config.step == last {
+		for name, value := range args.Options {
+			config.options[name] = value
+		}
+		config.step.changes = append(config.step.changes, index)
+		return nil
+	}
+	config := &configStep{options: make(map[string]string)}
+	for name, value := range args.Options {
+		config.options[name] = value
+	}
+	// previous holds the settings replaced by the step.
+	var previous charm.Settings
+	config.step = p.addStep(index, func() (params.ApplyChangeResult, error) {
+		svc, err := p.st.Service(args.ServiceName)
+		if err != nil {
+			return params.ApplyChangeResult{}, err
+		}
+		settings, err := svc.ConfigSettings()
+		if err != nil {
+			return params.ApplyChangeResult{}, err
+		}
+		previous = make(charm.Settings)
+		for name := range config.options {
+			// A nil value removes the setting when restored.
+			previous[name] = settings[name]
+		}
+		return params.ApplyChangeResult{}, service.ServiceSetSettingsStrings(svc, config.options)
+	}, func() error {
+		svc, err := p.st.Service(args.ServiceName)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		return svc.UpdateConfigSettings(previous)
+	})
+	p.configs[args.ServiceName] = config
+	return nil
+}
+
+func (p *changesPlan) expose(index int, serviceName string) error {
+	if _, err := p.charm(serviceName); err != nil {
+		return errors.Trace(err)
+	}
+	var wasExposed bool
+	p.addStep(index, func() (params.ApplyChangeResult, error) {
+		svc, err := p.st.Service(serviceName)
+		if err != nil {
+			return params.ApplyChangeResult{}, err
+		}
+		wasExposed = svc.IsExposed()
+		return params.ApplyChangeResult{}, svc.SetExposed()
+	}, func() error {
+		if wasExposed {
+			return nil
+		}
+		svc, err := p.st.Service(serviceName)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		return svc.ClearExposed()
+	})
+	return nil
+}
+
+func (p *changesPlan) addStep(index int, run func() (params.ApplyChangeResult, error), undo func() error) *changeStep {
+	step := &changeStep{changes: []int{index}, run: run, undo: undo}
+	p.steps = append(p.steps, step)
+	return step
+}
+
+// lastStep returns the last step recorded, if any.
+func (p *changesPlan) lastStep() *changeStep {
+	if len(p.steps) == 0 {
+		return nil
+	}
+	return p.steps[len(p.steps)-1]
+}
+
+// apply runs the steps of the plan in order, filling in the result of
+// each change. At the first step that fails, it stops and undoes the
+// steps before it, latest first.
+func (p *changesPlan) apply(results []params.ApplyChangeResult) {
+	machines, err := p.st.AllMachines()
+	if err != nil {
+		for i := range results {
+			results[i].Error = common.ServerError(errors.Annotate(err, "not applied"))
+		}
+		return
+	}
+	p.machines = set.NewStrings()
+	for _, machine := range machines {
+		p.machines.Add(machine.Id())
+	}
+	for n, step := range p.steps {
+		result, err := step.run()
+		if err == nil {
+			results[step.changes[0]] = result
+			continue
+		}
+		failed := step.changes[0]
+		logger.Warningf("cannot apply change %d: %v", failed, err)
+		for _, i := range step.changes {
+			results[i].Error = common.ServerError(err)
+		}
+		for _, later := range p.steps[n+1:] {
+			for _, i := range later.changes {
+				results[i].Error = common.ServerError(
+					fmt.Errorf("not applied: change %d failed", failed),
+				)
+			}
+		}
+		for k := n - 1; k >= 0; k-- {
+			earlier := p.steps[k]
+			if err := earlier.undo(); err != nil {
+				// The change stays applied, and its result
+				// says so.
+				logger.Warningf("cannot undo change %d: %v", earlier.changes[0], err)
+				continue
+			}
+			for _, i := range earlier.changes {
+				results[i] = params.ApplyChangeResult{Error: common.ServerError(
+					fmt.Errorf("undone: change %d failed", failed),
+				)}
+			}
+		}
+		return
+	}
+}
+
+// destroyUnits destroys the named units and the machines added by the
+// plan to host them.
+func (p *changesPlan) destroyUnits(units []string) error {
+	for _, name := range units {
+		unit, err := p.st.Unit(name)
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+		machineId, err := unit.AssignedMachineId()
+		if err != nil && !errors.IsNotAssigned(err) {
+			return errors.Trace(err)
+		}
+		if err := unit.Destroy(); err != nil {
+			return errors.Trace(err)
+		}
+		// Destroy the outermost machine added by the plan, with
+		// any containers on it.
+		var added *state.Machine
+		for machineId != "" && !p.machines.Contains(machineId) {
+			machine, err := p.st.Machine(machineId)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			added = machine
+			machineId, _ = machine.ParentId()
+		}
+		if added != nil {
+			if err := added.ForceDestroy(); err != nil {
+				return errors.Trace(err)
+			}
+		}
+	}
+	return nil
+}
+
+func hasRelation(meta *charm.Meta, name string) bool {
+	for _, relations := range []map[string]charm.Relation{meta.Provides, meta.Requires, meta.Peers} {
+		if _, ok := relations[name]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func unitNames(units []*state.Unit) []string {
+	unitNames := make([]string, len(units))
+	for i, unit := range units {
+		unitNames[i] = unit.Name()
+	}
+	return unitNames
+}
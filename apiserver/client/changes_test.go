@@ -0,0 +1,284 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/set"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v5"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/state"
+)
+
+type changesSuite struct {
+	baseSuite
+}
+
+var _ = gc.Suite(&changesSuite{})
+
+func (s *changesSuite) TestApplyChanges(c *gc.C) {
+	wordpress := s.AddTestingCharm(c, "wordpress")
+	s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+
+	results, err := s.APIState.Client().ApplyChanges(params.EnvironmentChange{
+		Kind: params.ChangeAddService,
+		AddService: &params.ServiceDeploy{
+			ServiceName: "wordpress",
+			CharmUrl:    wordpress.URL().String(),
+			NumUnits:    1,
+		},
+	}, params.EnvironmentChange{
+		Kind:     params.ChangeAddUnits,
+		AddUnits: &params.AddServiceUnits{ServiceName: "wordpress", NumUnits: 2},
+	}, params.EnvironmentChange{
+		Kind:        params.ChangeAddRelation,
+		AddRelation: &params.AddRelation{Endpoints: []string{"wordpress", "mysql"}},
+	}, params.EnvironmentChange{
+		Kind:      params.ChangeSetConfig,
+		SetConfig: &params.ServiceSet{ServiceName: "wordpress", Options: map[string]string{"blog-title": "foo"}},
+	}, params.EnvironmentChange{
+		Kind:   params.ChangeExpose,
+		Expose: &params.ServiceExpose{ServiceName: "wordpress"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []params.ApplyChangeResult{
+		{Units: []string{"wordpress/0"}},
+		{Units: []string{"wordpress/1", "wordpress/2"}},
+		{Endpoints: map[string]charm.Relation{
+			"wordpress": {Name: "db", Role: charm.RoleRequirer, Interface: "mysql", Limit: 1, Scope: charm.ScopeGlobal},
+			"mysql":     {Name: "server", Role: charm.RoleProvider, Interface: "mysql", Scope: charm.ScopeGlobal},
+		}},
+		{},
+		{},
+	})
+
+	svc, err := s.State.Service("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(svc.IsExposed(), jc.IsTrue)
+	settings, err := svc.ConfigSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, gc.DeepEquals, charm.Settings{"blog-title": "foo"})
+	_, err = s.State.KeyRelation("wordpress:db mysql:server")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *changesSuite) TestApplyChangesValidatesAllChanges(c *gc.C) {
+	wordpress := s.AddTestingCharm(c, "wordpress")
+
+	results, err := s.APIState.Client().ApplyChanges(params.EnvironmentChange{
+		Kind: params.ChangeAddService,
+		AddService: &params.ServiceDeploy{
+			ServiceName: "wordpress",
+			CharmUrl:    wordpress.URL().String(),
+		},
+	}, params.EnvironmentChange{
+		Kind:      params.ChangeSetConfig,
+		SetConfig: &params.ServiceSet{ServiceName: "wordpress", Options: map[string]string{"no-such": "foo"}},
+	}, params.EnvironmentChange{
+		Kind:        params.ChangeAddRelation,
+		AddRelation: &params.AddRelation{Endpoints: []string{"wordpress", "mysql"}},
+	}, params.EnvironmentChange{
+		Kind: "destroy",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 4)
+	c.Check(results[0].Error, gc.ErrorMatches, "not applied: change 1 is invalid")
+	c.Check(results[1].Error, gc.ErrorMatches, `unknown option "no-such"`)
+	c.Check(results[2].Error, gc.ErrorMatches, `service "mysql" not found`)
+	c.Check(results[3].Error, gc.ErrorMatches, `change kind "destroy" not valid`)
+
+	_, err = s.State.Service("wordpress")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *changesSuite) TestApplyChangesStopsAtFailure(c *gc.C) {
+	wordpress := s.AddTestingCharm(c, "wordpress")
+	s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+
+	results, err := s.APIState.Client().ApplyChanges(params.EnvironmentChange{
+		Kind:   params.ChangeExpose,
+		Expose: &params.ServiceExpose{ServiceName: "mysql"},
+	}, params.EnvironmentChange{
+		Kind: params.ChangeAddService,
+		AddService: &params.ServiceDeploy{
+			ServiceName: "wordpress",
+			CharmUrl:    wordpress.URL().String(),
+			Networks:    []string{"net1"},
+		},
+	}, params.EnvironmentChange{
+		Kind:   params.ChangeExpose,
+		Expose: &params.ServiceExpose{ServiceName: "wordpress"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 3)
+	c.Check(results[0].Error, gc.ErrorMatches, "undone: change 1 failed")
+	c.Check(results[1].Error, gc.ErrorMatches, `"net1" is not a valid tag`)
+	c.Check(results[2].Error, gc.ErrorMatches, "not applied: change 1 failed")
+
+	mysql, err := s.State.Service("mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(mysql.IsExposed(), jc.IsFalse)
+}
+
+func (s *changesSuite) TestApplyChangesUndoesChangesBeforeFailure(c *gc.C) {
+	mysql := s.AddTestingCharm(c, "mysql")
+	wordpress := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	err := wordpress.UpdateConfigSettings(charm.Settings{"blog-title": "old"})
+	c.Assert(err, jc.ErrorIsNil)
+	machine, err := s.State.AddMachine("precise", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	existing := set.NewStrings()
+	machines, err := s.State.AllMachines()
+	c.Assert(err, jc.ErrorIsNil)
+	for _, m := range machines {
+		existing.Add(m.Id())
+	}
+
+	results, err := s.APIState.Client().ApplyChanges(params.EnvironmentChange{
+		Kind:      params.ChangeSetConfig,
+		SetConfig: &params.ServiceSet{ServiceName: "wordpress", Options: map[string]string{"blog-title": "new"}},
+	}, params.EnvironmentChange{
+		Kind:   params.ChangeExpose,
+		Expose: &params.ServiceExpose{ServiceName: "wordpress"},
+	}, params.EnvironmentChange{
+		Kind: params.ChangeAddService,
+		AddService: &params.ServiceDeploy{
+			ServiceName: "mysql",
+			CharmUrl:    mysql.URL().String(),
+			NumUnits:    1,
+		},
+	}, params.EnvironmentChange{
+		Kind:        params.ChangeAddRelation,
+		AddRelation: &params.AddRelation{Endpoints: []string{"wordpress", "mysql"}},
+	}, params.EnvironmentChange{
+		Kind:     params.ChangeAddUnits,
+		AddUnits: &params.AddServiceUnits{ServiceName: "wordpress", NumUnits: 1},
+	}, params.EnvironmentChange{
+		Kind:     params.ChangeAddUnits,
+		AddUnits: &params.AddServiceUnits{ServiceName: "wordpress", NumUnits: 1, ToMachineSpec: machine.Id()},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 6)
+	for i := 0; i < 5; i++ {
+		c.Check(results[i].Error, gc.ErrorMatches, "undone: change 5 failed")
+	}
+	c.Check(results[5].Error, gc.ErrorMatches, `.*series does not match`)
+
+	err = wordpress.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(wordpress.IsExposed(), jc.IsFalse)
+	settings, err := wordpress.ConfigSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, gc.DeepEquals, charm.Settings{"blog-title": "old"})
+	_, err = s.State.Service("mysql")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	_, err = s.State.KeyRelation("wordpress:db mysql:server")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	_, err = s.State.Unit("wordpress/0")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	// The machines added for the units are destroyed; the existing
+	// ones are left alone.
+	err = s.State.Cleanup()
+	c.Assert(err, jc.ErrorIsNil)
+	machines, err = s.State.AllMachines()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(len(machines) > existing.Size(), jc.IsTrue)
+	for _, m := range machines {
+		if existing.Contains(m.Id()) {
+			c.Check(m.Life(), gc.Equals, state.Alive)
+		} else {
+			c.Check(m.Life(), gc.Not(gc.Equals), state.Alive)
+		}
+	}
+}
+
+func (s *changesSuite) TestApplyChangesValidatesTargets(c *gc.C) {
+	wordpress := s.AddTestingCharm(c, "wordpress")
+	s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+
+	results, err := s.APIState.Client().ApplyChanges(params.EnvironmentChange{
+		Kind: params.ChangeAddService,
+		AddService: &params.ServiceDeploy{
+			ServiceName: "wordpress",
+			CharmUrl:    wordpress.URL().String(),
+			NumUnits:    1,
+			Placement:   []*instance.Placement{{Scope: "lxc", Directive: "42"}},
+		},
+	}, params.EnvironmentChange{
+		Kind:     params.ChangeAddUnits,
+		AddUnits: &params.AddServiceUnits{ServiceName: "mysql", NumUnits: 1, ToMachineSpec: "42"},
+	}, params.EnvironmentChange{
+		Kind:     params.ChangeAddUnits,
+		AddUnits: &params.AddServiceUnits{ServiceName: "mysql", NumUnits: 1, ToMachineSpec: "lxc:42"},
+	}, params.EnvironmentChange{
+		Kind: params.ChangeAddUnits,
+		AddUnits: &params.AddServiceUnits{
+			ServiceName: "mysql",
+			NumUnits:    1,
+			Placement:   []*instance.Placement{{Scope: instance.MachineScope, Directive: "42"}},
+		},
+	}, params.EnvironmentChange{
+		Kind: params.ChangeAddUnits,
+		AddUnits: &params.AddServiceUnits{
+			ServiceName: "mysql",
+			NumUnits:    1,
+			Placement:   []*instance.Placement{{Scope: "no-such", Directive: "zone=a"}},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 5)
+	c.Check(results[0].Error, gc.ErrorMatches, `cannot deploy "wordpress" to machine 42: machine 42 not found`)
+	c.Check(results[1].Error, gc.ErrorMatches, `cannot add units for service "mysql" to machine 42: machine 42 not found`)
+	c.Check(results[2].Error, gc.ErrorMatches, `cannot add units for service "mysql" to machine 42: machine 42 not found`)
+	c.Check(results[3].Error, gc.ErrorMatches, `cannot add units for service "mysql" to machine 42: machine 42 not found`)
+	c.Check(results[4].Error, gc.ErrorMatches, `invalid environment UUID "no-such"`)
+}
+
+func (s *changesSuite) TestApplyChangesFoldsContiguousConfigOnly(c *gc.C) {
+	wordpress := s.AddTestingCharm(c, "wordpress")
+	// Units of the quantal charm cannot be added to the machine,
+	// which is only found out when the change is applied.
+	machine, err := s.State.AddMachine("precise", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The config change is not folded into the deployment, as it
+	// would then be written although the change before it failed;
+	// it is reported as not applied, rather than undone.
+	results, err := s.APIState.Client().ApplyChanges(params.EnvironmentChange{
+		Kind: params.ChangeAddService,
+		AddService: &params.ServiceDeploy{
+			ServiceName: "wordpress",
+			CharmUrl:    wordpress.URL().String(),
+		},
+	}, params.EnvironmentChange{
+		Kind:     params.ChangeAddUnits,
+		AddUnits: &params.AddServiceUnits{ServiceName: "wordpress", NumUnits: 1, ToMachineSpec: machine.Id()},
+	}, params.EnvironmentChange{
+		Kind:      params.ChangeSetConfig,
+		SetConfig: &params.ServiceSet{ServiceName: "wordpress", Options: map[string]string{"blog-title": "foo"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 3)
+	c.Check(results[0].Error, gc.ErrorMatches, "undone: change 1 failed")
+	c.Check(results[1].Error, gc.ErrorMatches, `.*series does not match`)
+	c.Check(results[2].Error, gc.ErrorMatches, "not applied: change 1 failed")
+
+	_, err = s.State.Service("wordpress")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *changesSuite) TestBlockChangesApplyChanges(c *gc.C) {
+	s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	s.BlockAllChanges(c, "TestBlockChangesApplyChanges")
+	_, err := s.APIState.Client().ApplyChanges(params.EnvironmentChange{
+		Kind:   params.ChangeExpose,
+		Expose: &params.ServiceExpose{ServiceName: "mysql"},
+	})
+	s.AssertBlocked(c, err, "TestBlockChangesApplyChanges")
+}
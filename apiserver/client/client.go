@@ -65,8 +65,14 @@ func NewClient(st *state.State, resources *common.Resources, authorizer common.A
 		check: common.NewBlockChecker(st)}, nil
 }
 
-func (c *Client) WatchAll() (params.AllWatcherId, error) {
-	w := c.api.state.Watch()
+// WatchAll returns the id of a new AllWatcher sending the changes to
+// the environment selected by the given filter. Older clients send no
+// filter, and so watch all changes.
+func (c *Client) WatchAll(args params.WatchAll) (params.AllWatcherId, error) {
+	if err := args.Filter.Validate(); err != nil {
+		return params.AllWatcherId{}, errors.Trace(err)
+	}
+	w := c.api.state.WatchFiltered(args.Filter)
 	return params.AllWatcherId{
 		AllWatcherId: c.api.resources.Register(w),
 	}, nil
@@ -269,7 +275,7 @@ func (c *Client) ServiceDeployWithNetworks(args params.ServiceDeploy) error {
 }
 
 // ServiceUpdate updates the service attributes, including charm URL,
-// minimum number of units, settings and constraints.
+// minimum number of units, settings, hook retry policy and constraints.
 // All parameters in params.ServiceUpdate except the service name are optional.
 func (c *Client) ServiceUpdate(args params.ServiceUpdate) error {
 	if !args.ForceCharmUrl {
@@ -303,6 +309,43 @@ func (c *Client) ServiceUpdate(args params.ServiceUpdate) error {
 			return err
 		}
 	}
+	// Update service's hook retry policy.
+	if args.HookRetryPolicy != nil {
+		err = svc.SetHookRetryPolicy(state.HookRetryPolicy{
+			MaxAttempts: args.HookRetryPolicy.MaxAttempts,
+			Delay:       args.HookRetryPolicy.Delay,
+			MaxDelay:    args.HookRetryPolicy.MaxDelay,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	// Update service's placement policy.
+	if args.PlacementPolicy != nil {
+		err = svc.SetPlacementPolicy(state.PlacementPolicy{
+			AntiAffinity:    args.PlacementPolicy.AntiAffinity,
+			Affinity:        args.PlacementPolicy.Affinity,
+			MaxUnitsPerZone: args.PlacementPolicy.MaxUnitsPerZone,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	// Update service's scaling policy.
+	if args.ScalingPolicy != nil {
+		err = svc.SetScalingPolicy(state.ScalingPolicy{
+			Metric:         args.ScalingPolicy.Metric,
+			ScaleUpAbove:   args.ScalingPolicy.ScaleUpAbove,
+			ScaleDownBelow: args.ScalingPolicy.ScaleDownBelow,
+			Window:         args.ScalingPolicy.Window,
+			MinUnits:       args.ScalingPolicy.MinUnits,
+			MaxUnits:       args.ScalingPolicy.MaxUnits,
+			Cooldown:       args.ScalingPolicy.Cooldown,
+		})
+		if err != nil {
+			return err
+		}
+	}
 	// Update service's constraints.
 	if args.Constraints != nil {
 		return svc.SetConstraints(*args.Constraints)
@@ -398,9 +441,57 @@ func (c *Client) ServiceSetCharm(args params.ServiceSetCharm) error {
 	if err != nil {
 		return err
 	}
+	if args.Canary > 0 {
+		return c.serviceSetCharmInBatches(service, args)
+	}
 	return c.serviceSetCharm(service, args.CharmUrl, args.Force)
 }
 
+// serviceSetCharmInBatches sets the charm for the given service,
+// upgrading its canary units first.
+func (c *Client) serviceSetCharmInBatches(service *state.Service, args params.ServiceSetCharm) error {
+	curl, err := charm.ParseURL(args.CharmUrl)
+	if err != nil {
+		return err
+	}
+	ch, err := c.api.state.Charm(curl)
+	if err != nil {
+		return err
+	}
+	batchSize := args.BatchSize
+	if batchSize == 0 {
+		batchSize = 1
+	}
+	return service.SetCharmInBatches(ch, args.Force, args.Canary, batchSize)
+}
+
+// ServiceRollbackCharm changes the charm of a service back to the one
+// it ran before its last upgrade, abandoning any charm rollout in
+// progress.
+func (c *Client) ServiceRollbackCharm(args params.ServiceCharmRollout) error {
+	if err := c.check.ChangeAllowed(); err != nil {
+		return errors.Trace(err)
+	}
+	service, err := c.api.state.Service(args.ServiceName)
+	if err != nil {
+		return err
+	}
+	return service.RollbackCharm()
+}
+
+// ServiceResumeCharmRollout resumes the paused charm rollout of a
+// service.
+func (c *Client) ServiceResumeCharmRollout(args params.ServiceCharmRollout) error {
+	if err := c.check.ChangeAllowed(); err != nil {
+		return errors.Trace(err)
+	}
+	service, err := c.api.state.Service(args.ServiceName)
+	if err != nil {
+		return err
+	}
+	return service.ResumeCharmRollout()
+}
+
 // addServiceUnits adds a given number of units to a service.
 func addServiceUnits(state *state.State, args params.AddServiceUnits) ([]*state.Unit, error) {
 	service, err := state.Service(args.ServiceName)
@@ -536,11 +627,15 @@ func (c *Client) AddRelation(args params.AddRelation) (params.AddRelationResults
 	if err := c.check.ChangeAllowed(); err != nil {
 		return params.AddRelationResults{}, errors.Trace(err)
 	}
-	inEps, err := c.api.state.InferEndpoints(args.Endpoints...)
+	return addRelation(c.api.state, args.Endpoints)
+}
+
+func addRelation(st *state.State, endpoints []string) (params.AddRelationResults, error) {
+	inEps, err := st.InferEndpoints(endpoints...)
 	if err != nil {
 		return params.AddRelationResults{}, err
 	}
-	rel, err := c.api.state.AddRelation(inEps...)
+	rel, err := st.AddRelation(inEps...)
 	if err != nil {
 		return params.AddRelationResults{}, err
 	}
@@ -728,6 +823,67 @@ func (c *Client) DestroyMachines(args params.DestroyMachines) error {
 	return destroyErr("machines", args.MachineNames, errs)
 }
 
+// MachinePools returns the environment's spare machine pools, and the
+// spare machines in each.
+func (c *Client) MachinePools() (params.MachinePoolsResult, error) {
+	pools, err := c.api.state.MachinePools()
+	if err != nil {
+		return params.MachinePoolsResult{}, errors.Trace(err)
+	}
+	result := params.MachinePoolsResult{
+		Pools: make([]params.MachinePool, len(pools)),
+	}
+	for i, pool := range pools {
+		spares, err := c.api.state.SpareMachines(pool.Name)
+		if err != nil {
+			return params.MachinePoolsResult{}, errors.Trace(err)
+		}
+		ids := make([]string, len(spares))
+		for j, m := range spares {
+			ids[j] = m.Id()
+		}
+		result.Pools[i] = params.MachinePool{
+			Name:        pool.Name,
+			Size:        pool.Size,
+			Series:      pool.Series,
+			Constraints: pool.Constraints,
+			Spares:      ids,
+		}
+	}
+	return result, nil
+}
+
+// SetMachinePool creates a spare machine pool, or changes the size,
+// series and constraints of an existing one. The environment's default
+// series is used if none is specified.
+func (c *Client) SetMachinePool(args params.MachinePool) error {
+	if err := c.check.ChangeAllowed(); err != nil {
+		return errors.Trace(err)
+	}
+	if args.Series == "" {
+		conf, err := c.api.state.EnvironConfig()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		args.Series = config.PreferredSeries(conf)
+	}
+	return c.api.state.SetMachinePool(state.MachinePool{
+		Name:        args.Name,
+		Size:        args.Size,
+		Series:      args.Series,
+		Constraints: args.Constraints,
+	})
+}
+
+// RemoveMachinePool removes a spare machine pool, and destroys its
+// spare machines.
+func (c *Client) RemoveMachinePool(args params.RemoveMachinePool) error {
+	if err := c.check.RemoveAllowed(); err != nil {
+		return errors.Trace(err)
+	}
+	return c.api.state.RemoveMachinePool(args.Name)
+}
+
 // CharmInfo returns information about the requested charm.
 func (c *Client) CharmInfo(args params.CharmInfo) (api.CharmInfo, error) {
 	curl, err := charm.ParseURL(args.CharmURL)
@@ -956,9 +1112,28 @@ func (c *Client) SetEnvironAgentVersion(args params.SetEnvironAgentVersion) erro
 	if err := c.check.ChangeAllowed(); err != nil {
 		return errors.Trace(err)
 	}
+	if args.BatchSize > 0 {
+		return c.api.state.SetEnvironAgentVersionRolling(args.Version, args.BatchSize)
+	}
 	return c.api.state.SetEnvironAgentVersion(args.Version)
 }
 
+// PauseRollingUpgrade pauses the rolling agent upgrade in progress.
+func (c *Client) PauseRollingUpgrade() error {
+	if err := c.check.ChangeAllowed(); err != nil {
+		return errors.Trace(err)
+	}
+	return c.api.state.PauseRollingUpgrade("paused by user")
+}
+
+// ResumeRollingUpgrade resumes the paused rolling agent upgrade.
+func (c *Client) ResumeRollingUpgrade() error {
+	if err := c.check.ChangeAllowed(); err != nil {
+		return errors.Trace(err)
+	}
+	return c.api.state.ResumeRollingUpgrade()
+}
+
 // AbortCurrentUpgrade aborts and archives the current upgrade
 // synchronisation record, if any.
 func (c *Client) AbortCurrentUpgrade() error {
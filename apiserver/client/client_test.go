@@ -9,6 +9,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/names"
@@ -433,6 +434,32 @@ func (s *serverSuite) TestSetEnvironAgentVersion(c *gc.C) {
 	c.Assert(agentVersion, gc.Equals, "9.8.7")
 }
 
+func (s *serverSuite) TestSetEnvironAgentVersionRolling(c *gc.C) {
+	args := params.SetEnvironAgentVersion{
+		Version:   version.MustParse("9.8.7"),
+		BatchSize: 3,
+	}
+	err := s.client.SetEnvironAgentVersion(args)
+	c.Assert(err, jc.ErrorIsNil)
+	rolling, err := s.State.RollingUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rolling.To, gc.Equals, version.MustParse("9.8.7"))
+	c.Assert(rolling.BatchSize, gc.Equals, 3)
+
+	err = s.client.PauseRollingUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	rolling, err = s.State.RollingUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rolling.Paused, jc.IsTrue)
+	c.Assert(rolling.Reason, gc.Equals, "paused by user")
+
+	err = s.client.ResumeRollingUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	rolling, err = s.State.RollingUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rolling.Paused, jc.IsFalse)
+}
+
 func (s *serverSuite) assertSetEnvironAgentVersion(c *gc.C) {
 	args := params.SetEnvironAgentVersion{
 		Version: version.MustParse("9.8.7"),
@@ -2006,6 +2033,100 @@ func (s *clientSuite) TestClientServiceUpdateSetMinUnitsError(c *gc.C) {
 	c.Assert(service.MinUnits(), gc.Equals, 0)
 }
 
+func (s *clientSuite) TestClientServiceUpdateSetHookRetryPolicy(c *gc.C) {
+	service := s.AddTestingService(c, "dummy", s.AddTestingCharm(c, "dummy"))
+
+	// Set the hook retry policy for the service.
+	args := params.ServiceUpdate{
+		ServiceName: "dummy",
+		HookRetryPolicy: &params.HookRetryPolicy{
+			MaxAttempts: 5,
+			Delay:       30 * time.Second,
+			MaxDelay:    10 * time.Minute,
+		},
+	}
+	err := s.APIState.Client().ServiceUpdate(args)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Ensure the policy has been set.
+	c.Assert(service.Refresh(), gc.IsNil)
+	c.Assert(service.HookRetryPolicy(), gc.Equals, state.HookRetryPolicy{
+		MaxAttempts: 5,
+		Delay:       30 * time.Second,
+		MaxDelay:    10 * time.Minute,
+	})
+}
+
+func (s *clientSuite) TestClientServiceUpdateSetPlacementPolicy(c *gc.C) {
+	service := s.AddTestingService(c, "dummy", s.AddTestingCharm(c, "dummy"))
+
+	// Set the placement policy for the service.
+	args := params.ServiceUpdate{
+		ServiceName: "dummy",
+		PlacementPolicy: &params.PlacementPolicy{
+			AntiAffinity:    []string{"mysql"},
+			MaxUnitsPerZone: 2,
+		},
+	}
+	err := s.APIState.Client().ServiceUpdate(args)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Ensure the policy has been set.
+	c.Assert(service.Refresh(), gc.IsNil)
+	c.Assert(service.PlacementPolicy(), jc.DeepEquals, state.PlacementPolicy{
+		AntiAffinity:    []string{"mysql"},
+		MaxUnitsPerZone: 2,
+	})
+}
+
+func (s *clientSuite) TestClientServiceUpdateSetScalingPolicy(c *gc.C) {
+	service := s.AddTestingService(c, "metered", s.AddTestingCharm(c, "metered"))
+
+	// Set the scaling policy for the service.
+	args := params.ServiceUpdate{
+		ServiceName: "metered",
+		ScalingPolicy: &params.ScalingPolicy{
+			Metric:         "pings",
+			ScaleUpAbove:   80,
+			ScaleDownBelow: 20,
+			Window:         5 * time.Minute,
+			MinUnits:       1,
+			MaxUnits:       5,
+			Cooldown:       10 * time.Minute,
+		},
+	}
+	err := s.APIState.Client().ServiceUpdate(args)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Ensure the policy has been set.
+	c.Assert(service.Refresh(), gc.IsNil)
+	c.Assert(service.ScalingPolicy(), gc.Equals, state.ScalingPolicy{
+		Metric:         "pings",
+		ScaleUpAbove:   80,
+		ScaleDownBelow: 20,
+		Window:         5 * time.Minute,
+		MinUnits:       1,
+		MaxUnits:       5,
+		Cooldown:       10 * time.Minute,
+	})
+}
+
+func (s *clientSuite) TestClientServiceUpdateSetHookRetryPolicyError(c *gc.C) {
+	service := s.AddTestingService(c, "dummy", s.AddTestingCharm(c, "dummy"))
+
+	args := params.ServiceUpdate{
+		ServiceName:     "dummy",
+		HookRetryPolicy: &params.HookRetryPolicy{MaxAttempts: 5},
+	}
+	err := s.APIState.Client().ServiceUpdate(args)
+	c.Assert(err, gc.ErrorMatches,
+		`cannot set hook retry policy for service "dummy": hook retry policy without delay not valid`)
+
+	// Ensure the policy has not been set.
+	c.Assert(service.Refresh(), gc.IsNil)
+	c.Assert(service.HookRetryPolicy(), gc.Equals, state.HookRetryPolicy{})
+}
+
 func (s *clientSuite) TestClientServiceUpdateSetSettingsStrings(c *gc.C) {
 	service := s.AddTestingService(c, "dummy", s.AddTestingCharm(c, "dummy"))
 
@@ -2222,6 +2343,52 @@ func (s *clientRepoSuite) TestClientServiceSetCharmForce(c *gc.C) {
 	c.Assert(force, jc.IsTrue)
 }
 
+func (s *clientRepoSuite) TestClientServiceSetCharmInBatches(c *gc.C) {
+	s.setupServiceSetCharm(c)
+	err := service.AddCharmWithAuthorization(s.State, params.AddCharmWithAuthorization{URL: "cs:precise/wordpress-3"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.APIState.Client().ServiceSetCharmInBatches(
+		"service", "cs:precise/wordpress-3", false, 1, 2,
+	)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Ensure that only the canary unit is upgraded at first.
+	svc, err := s.State.Service("service")
+	c.Assert(err, jc.ErrorIsNil)
+	rollout, ok := svc.CharmRollout()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(rollout.To.String(), gc.Equals, "cs:precise/wordpress-3")
+	c.Assert(rollout.BatchSize, gc.Equals, 2)
+	c.Assert(rollout.Units, jc.DeepEquals, []string{"service/0"})
+
+	err = svc.PauseCharmRollout("unit service/0 failed")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.APIState.Client().ServiceResumeCharmRollout("service")
+	c.Assert(err, jc.ErrorIsNil)
+	err = svc.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	rollout, _ = svc.CharmRollout()
+	c.Assert(rollout.Paused, jc.IsFalse)
+}
+
+func (s *clientRepoSuite) TestClientServiceRollbackCharm(c *gc.C) {
+	s.setupServiceSetCharm(c)
+	err := s.APIState.Client().ServiceRollbackCharm("service")
+	c.Assert(err, gc.ErrorMatches, `cannot roll back charm of service "service": no previous charm to roll back to`)
+
+	s.assertServiceSetCharm(c, false)
+	err = s.APIState.Client().ServiceRollbackCharm("service")
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Ensure that the previous charm is forced on the units.
+	svc, err := s.State.Service("service")
+	c.Assert(err, jc.ErrorIsNil)
+	charm, force, err := svc.Charm()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(charm.URL().String(), gc.Equals, "cs:precise/dummy-0")
+	c.Assert(force, jc.IsTrue)
+}
+
 func (s *clientRepoSuite) TestBlockServiceSetCharmForce(c *gc.C) {
 	s.setupServiceSetCharm(c)
 
@@ -2474,6 +2641,38 @@ func (s *clientSuite) TestClientWatchAll(c *gc.C) {
 	}
 }
 
+func (s *clientSuite) TestClientWatchAllFiltered(c *gc.C) {
+	_, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	watcher, err := s.APIState.Client().WatchAllFiltered(multiwatcher.Filter{
+		Kinds:    []string{"service"},
+		Services: []string{"wordpress"},
+		Fields:   []string{"Life"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	defer func() {
+		err := watcher.Stop()
+		c.Assert(err, jc.ErrorIsNil)
+	}()
+	deltas, err := watcher.Next()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(deltas, jc.DeepEquals, []multiwatcher.Delta{{
+		Entity: &multiwatcher.ServiceInfo{
+			Name: "wordpress",
+			Life: multiwatcher.Life("alive"),
+		},
+	}})
+}
+
+func (s *clientSuite) TestClientWatchAllInvalidFilter(c *gc.C) {
+	_, err := s.APIState.Client().WatchAllFiltered(multiwatcher.Filter{
+		Kinds: []string{"services"},
+	})
+	c.Assert(err, gc.ErrorMatches, `unknown entity kind "services"`)
+}
+
 func (s *clientSuite) TestClientSetServiceConstraints(c *gc.C) {
 	service := s.AddTestingService(c, "dummy", s.AddTestingCharm(c, "dummy"))
 
@@ -3375,6 +3574,43 @@ func (s *clientSuite) TestRetryProvisioning(c *gc.C) {
 	c.Assert(statusInfo.Data["transient"], jc.IsTrue)
 }
 
+func (s *clientSuite) TestClientMachinePools(c *gc.C) {
+	client := s.APIState.Client()
+	err := client.SetMachinePool(params.MachinePool{
+		Name:        "web",
+		Size:        1,
+		Constraints: constraints.MustParse("mem=4G"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.EnsureMachinePools()
+	c.Assert(err, jc.ErrorIsNil)
+
+	pools, err := client.MachinePools()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pools, jc.DeepEquals, []params.MachinePool{{
+		Name:        "web",
+		Size:        1,
+		Series:      coretesting.FakeDefaultSeries,
+		Constraints: constraints.MustParse("mem=4G"),
+		Spares:      []string{"0"},
+	}})
+
+	err = client.RemoveMachinePool("web")
+	c.Assert(err, jc.ErrorIsNil)
+	pools, err = client.MachinePools()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pools, gc.HasLen, 0)
+
+	err = client.RemoveMachinePool("web")
+	c.Assert(err, gc.ErrorMatches, `cannot remove machine pool "web": machine pool "web" not found`)
+}
+
+func (s *clientSuite) TestBlockChangesSetMachinePool(c *gc.C) {
+	s.BlockAllChanges(c, "TestBlockChangesSetMachinePool")
+	err := s.APIState.Client().SetMachinePool(params.MachinePool{Name: "web", Size: 1})
+	s.AssertBlocked(c, err, "TestBlockChangesSetMachinePool")
+}
+
 func (s *clientSuite) setupRetryProvisioning(c *gc.C) *state.Machine {
 	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
 	c.Assert(err, jc.ErrorIsNil)
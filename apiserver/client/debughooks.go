@@ -0,0 +1,41 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client
+
+import (
+	"time"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+// DebugHooksSessionTimeout is the time a debug-hooks session opened
+// through the API lasts for, unless it is renewed.
+const DebugHooksSessionTimeout = time.Minute
+
+// OpenDebugHooksSession opens a streaming debug-hooks session on the
+// unit, or renews the unit's session. The selected hooks and actions
+// run as usual, with their output written to the debug log, until
+// the session is closed or expires.
+func (c *Client) OpenDebugHooksSession(args params.DebugHooksSession) error {
+	unit, err := c.api.state.Unit(args.UnitName)
+	if err != nil {
+		return err
+	}
+	return unit.SetDebugHooksSession(state.DebugHooksSession{
+		Hooks:       args.Hooks,
+		Actions:     args.Actions,
+		RelationIds: args.RelationIds,
+		Expires:     time.Now().Add(DebugHooksSessionTimeout),
+	})
+}
+
+// CloseDebugHooksSession closes the unit's debug-hooks session.
+func (c *Client) CloseDebugHooksSession(args params.DebugHooksSession) error {
+	unit, err := c.api.state.Unit(args.UnitName)
+	if err != nil {
+		return err
+	}
+	return unit.ClearDebugHooksSession()
+}
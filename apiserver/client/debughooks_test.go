@@ -0,0 +1,49 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+type debugHooksSuite struct {
+	baseSuite
+}
+
+var _ = gc.Suite(&debugHooksSuite{})
+
+func (s *debugHooksSuite) TestOpenAndCloseDebugHooksSession(c *gc.C) {
+	svc := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	unit, err := svc.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+
+	before := time.Now()
+	err = s.APIState.Client().OpenDebugHooksSession(params.DebugHooksSession{
+		UnitName:    "wordpress/0",
+		Hooks:       []string{"config-changed"},
+		RelationIds: []int{1},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	session, err := unit.DebugHooksSession()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(session.Hooks, jc.DeepEquals, []string{"config-changed"})
+	c.Assert(session.RelationIds, jc.DeepEquals, []int{1})
+	c.Assert(session.Expires.After(before), jc.IsTrue)
+
+	err = s.APIState.Client().CloseDebugHooksSession("wordpress/0")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = unit.DebugHooksSession()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *debugHooksSuite) TestOpenDebugHooksSessionUnknownUnit(c *gc.C) {
+	err := s.APIState.Client().OpenDebugHooksSession(params.DebugHooksSession{UnitName: "foo/0"})
+	c.Assert(err, gc.ErrorMatches, `unit "foo/0" not found`)
+}
@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/juju/errors"
+	"github.com/juju/names"
 	"github.com/juju/utils/set"
 	"gopkg.in/juju/charm.v5"
 	"gopkg.in/juju/charm.v5/hooks"
@@ -50,12 +51,19 @@ func (s sortableStatuses) Less(i, j int) bool {
 }
 
 // TODO(perrito666) this client method requires more testing, only its parts are unittested.
-// UnitStatusHistory returns a slice of past statuses for a given unit.
+// UnitStatusHistory returns a slice of past statuses for a given unit,
+// or for a given machine if args.Name is a machine id.
 func (c *Client) UnitStatusHistory(args params.StatusHistory) (api.UnitStatusHistory, error) {
 	size := args.Size - 1
 	if size < 1 {
 		return api.UnitStatusHistory{}, errors.Errorf("invalid history size: %d", args.Size)
 	}
+	if names.IsValidMachine(args.Name) {
+		return c.machineStatusHistory(args.Name, size)
+	}
+	if names.IsValidService(args.Name) {
+		return c.serviceStatusHistory(args.Name, size)
+	}
 	unit, err := c.api.state.Unit(args.Name)
 	if err != nil {
 		return api.UnitStatusHistory{}, errors.Trace(err)
@@ -106,6 +114,88 @@ func (c *Client) UnitStatusHistory(args params.StatusHistory) (api.UnitStatusHis
 	return statuses, nil
 }
 
+// machineStatusHistory returns the current status and at most size
+// past statuses of the given machine.
+func (c *Client) machineStatusHistory(machineId string, size int) (api.UnitStatusHistory, error) {
+	machine, err := c.api.state.Machine(machineId)
+	if err != nil {
+		return api.UnitStatusHistory{}, errors.Trace(err)
+	}
+	machineStatuses, err := machine.StatusHistory(size)
+	if err != nil {
+		return api.UnitStatusHistory{}, errors.Trace(err)
+	}
+	current, err := machine.Status()
+	if err != nil {
+		return api.UnitStatusHistory{}, errors.Trace(err)
+	}
+	machineStatuses = append(machineStatuses, current)
+
+	statuses := api.UnitStatusHistory{
+		Statuses: agentStatusFromStatusInfo(machineStatuses, params.KindMachine),
+	}
+	sort.Sort(sortableStatuses(statuses.Statuses))
+	return statuses, nil
+}
+
+// serviceStatusHistory returns the current status and at most size
+// past statuses of the given service, including its scaling decisions.
+func (c *Client) serviceStatusHistory(serviceName string, size int) (api.UnitStatusHistory, error) {
+	service, err := c.api.state.Service(serviceName)
+	if err != nil {
+		return api.UnitStatusHistory{}, errors.Trace(err)
+	}
+	serviceStatuses, err := service.StatusHistory(size)
+	if err != nil {
+		return api.UnitStatusHistory{}, errors.Trace(err)
+	}
+	current, err := service.Status()
+	if err != nil {
+		return api.UnitStatusHistory{}, errors.Trace(err)
+	}
+	// A service without units, whose status has never been set,
+	// has no current status to report.
+	if current.Since != nil {
+		serviceStatuses = append(serviceStatuses, current)
+	}
+
+	statuses := api.UnitStatusHistory{
+		Statuses: agentStatusFromStatusInfo(serviceStatuses, params.KindService),
+	}
+	sort.Sort(sortableStatuses(statuses.Statuses))
+	return statuses, nil
+}
+
+// HookStats returns the most recent hook, action and command
+// executions recorded for a given unit, most recent first.
+func (c *Client) HookStats(args params.HookStats) (params.HookStatsResult, error) {
+	if args.Size < 1 {
+		return params.HookStatsResult{}, errors.Errorf("invalid history size: %d", args.Size)
+	}
+	unit, err := c.api.state.Unit(args.Name)
+	if err != nil {
+		return params.HookStatsResult{}, errors.Trace(err)
+	}
+	executions, err := unit.HookExecutions(args.Size)
+	if err != nil {
+		return params.HookStatsResult{}, errors.Trace(err)
+	}
+	result := params.HookStatsResult{
+		Executions: make([]params.HookExecution, len(executions)),
+	}
+	for i, execution := range executions {
+		result.Executions[i] = params.HookExecution{
+			Kind:      string(execution.Kind),
+			Name:      execution.Name,
+			Started:   execution.Started,
+			Finished:  execution.Finished,
+			ExitCode:  execution.ExitCode,
+			ToolCalls: execution.ToolCalls,
+		}
+	}
+	return result, nil
+}
+
 // FullStatus gives the information needed for juju status over the api
 func (c *Client) FullStatus(args params.StatusParams) (api.Status, error) {
 	cfg, err := c.api.state.EnvironConfig()
@@ -198,12 +288,37 @@ func (c *Client) FullStatus(args params.StatusParams) (api.Status, error) {
 		}
 	}
 
+	rollingUpgrade, err := fetchRollingUpgrade(c.api.state)
+	if err != nil {
+		return noStatus, errors.Annotate(err, "could not fetch rolling upgrade")
+	}
+
 	return api.Status{
 		EnvironmentName: cfg.Name(),
 		Machines:        processMachines(context.machines),
 		Services:        context.processServices(),
 		Networks:        context.processNetworks(),
 		Relations:       context.processRelations(),
+		RollingUpgrade:  rollingUpgrade,
+	}, nil
+}
+
+// fetchRollingUpgrade returns the status of the rolling agent upgrade
+// in progress, or nil if there is none.
+func fetchRollingUpgrade(st *state.State) (*api.RollingUpgradeStatus, error) {
+	rolling, err := st.RollingUpgrade()
+	if errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return &api.RollingUpgradeStatus{
+		From:      rolling.From.String(),
+		To:        rolling.To.String(),
+		BatchSize: rolling.BatchSize,
+		Machines:  rolling.Machines,
+		Paused:    rolling.Paused,
+		Reason:    rolling.Reason,
 	}, nil
 }
 
@@ -599,6 +714,12 @@ func (context *statusContext) processService(service *state.Service) (status api
 		status.Status.Info = serviceStatus.Message
 		status.Status.Data = serviceStatus.Data
 		status.Status.Since = serviceStatus.Since
+
+		status.PlacementViolations, err = service.PlacementViolations()
+		if err != nil {
+			status.Err = err
+			return
+		}
 	}
 	return status
 }
@@ -4,10 +4,13 @@
 package client_test
 
 import (
+	"time"
+
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/apiserver/client"
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/testing/factory"
@@ -61,6 +64,77 @@ func (s *statusSuite) TestLegacyStatus(c *gc.C) {
 	c.Check(resultMachine.InstanceId, gc.Equals, instanceId)
 }
 
+func (s *statusSuite) TestHookStats(c *gc.C) {
+	unit := factory.NewFactory(s.State).MakeUnit(c, nil)
+	started := time.Date(2015, 7, 1, 12, 0, 0, 0, time.UTC)
+	err := unit.RecordHookExecution(state.HookExecution{
+		Kind:      state.HookExecutionHook,
+		Name:      "install",
+		Started:   started,
+		Finished:  started.Add(time.Minute),
+		ExitCode:  1,
+		ToolCalls: 4,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	executions, err := s.APIState.Client().HookStats(unit.Name(), 10)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(executions, jc.DeepEquals, []params.HookExecution{{
+		Kind:      "hook",
+		Name:      "install",
+		Started:   started,
+		Finished:  started.Add(time.Minute),
+		ExitCode:  1,
+		ToolCalls: 4,
+	}})
+
+	_, err = s.APIState.Client().HookStats(unit.Name(), 0)
+	c.Assert(err, gc.ErrorMatches, "invalid history size: 0")
+}
+
+func (s *statusSuite) TestMachineStatusHistory(c *gc.C) {
+	machine := s.addMachine(c)
+	err := machine.SetStatus(state.StatusError, "capacity exceeded", map[string]interface{}{"transient": true})
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.SetStatus(state.StatusPending, "retrying provisioning (attempt 2 of 6)", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	history, err := s.APIState.Client().UnitStatusHistory(params.KindCombined, machine.Id(), 10)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history.Statuses, gc.HasLen, 3)
+	infos := make(map[string]params.Status)
+	for _, status := range history.Statuses {
+		c.Check(status.Kind, gc.Equals, params.KindMachine)
+		infos[status.Info] = status.Status
+	}
+	c.Check(infos["capacity exceeded"], gc.Equals, params.StatusError)
+	c.Check(infos["retrying provisioning (attempt 2 of 6)"], gc.Equals, params.StatusPending)
+}
+
+func (s *statusSuite) TestServiceStatusHistory(c *gc.C) {
+	service := s.AddTestingService(c, "metered", s.AddTestingCharm(c, "metered"))
+	err := service.SetScalingPolicy(state.ScalingPolicy{
+		Metric:       "pings",
+		ScaleUpAbove: 80,
+		Window:       5 * time.Minute,
+		MinUnits:     1,
+		MaxUnits:     5,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = service.RecordScaling(1, 2, "average pings 90 above 80 over 5m0s")
+	c.Assert(err, jc.ErrorIsNil)
+
+	history, err := s.APIState.Client().UnitStatusHistory(params.KindCombined, "metered", 10)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history.Statuses, gc.HasLen, 1)
+	infos := make(map[string]bool)
+	for _, status := range history.Statuses {
+		c.Check(status.Kind, gc.Equals, params.KindService)
+		infos[status.Info] = true
+	}
+	c.Check(infos["scaled up from 1 to 2 units: average pings 90 above 80 over 5m0s"], jc.IsTrue)
+}
+
 var _ = gc.Suite(&statusUnitTestSuite{})
 
 type statusUnitTestSuite struct {
@@ -130,6 +130,20 @@ func (rs *Resources) Count() int {
 	return len(rs.resources)
 }
 
+// CountFunc returns the number of resources currently held for which
+// f returns true.
+func (rs *Resources) CountFunc(f func(Resource) bool) int {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	count := 0
+	for _, r := range rs.resources {
+		if f(r) {
+			count++
+		}
+	}
+	return count
+}
+
 // StringResource is just a regular 'string' that matches the Resource
 // interface.
 type StringResource string
@@ -149,6 +149,19 @@ func (resourceSuite) TestStopAll(c *gc.C) {
 	c.Assert(rs.Count(), gc.Equals, 0)
 }
 
+func (resourceSuite) TestCountFunc(c *gc.C) {
+	rs := common.NewResources()
+	defer rs.StopAll()
+	rs.Register(&fakeResource{})
+	rs.Register(&fakeResource{stopped: true})
+	rs.Register(common.StringResource("foo"))
+	isStopped := func(r common.Resource) bool {
+		fake, ok := r.(*fakeResource)
+		return ok && fake.stopped
+	}
+	c.Assert(rs.CountFunc(isStopped), gc.Equals, 1)
+}
+
 func (resourceSuite) TestStringResource(c *gc.C) {
 	rs := common.NewResources()
 	r1 := common.StringResource("foobar")
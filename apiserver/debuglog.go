@@ -69,6 +69,7 @@ func newDebugLogHandler(
 //      - has no meaning if 'replay' is true
 //   level -> string one of [TRACE, DEBUG, INFO, WARNING, ERROR]
 //   replay -> string - one of [true, false], if true, start the file from the start
+//   trace -> string - only show the lines holding this trace id of API requests
 func (h *debugLogHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	server := websocket.Server{
 		Handler: func(conn *websocket.Conn) {
@@ -166,6 +167,7 @@ type debugLogParams struct {
 	excludeEntity []string
 	includeModule []string
 	excludeModule []string
+	trace         string
 }
 
 func readDebugLogParams(queryMap url.Values) (*debugLogParams, error) {
@@ -209,6 +211,7 @@ func readDebugLogParams(queryMap url.Values) (*debugLogParams, error) {
 	params.excludeEntity = queryMap["excludeEntity"]
 	params.includeModule = queryMap["includeModule"]
 	params.excludeModule = queryMap["excludeModule"]
+	params.trace = queryMap.Get("trace")
 
 	return params, nil
 }
@@ -66,6 +66,7 @@ func makeLogTailerParams(reqParams *debugLogParams) *state.LogTailerParams {
 		ExcludeEntity: reqParams.excludeEntity,
 		IncludeModule: reqParams.includeModule,
 		ExcludeModule: reqParams.excludeModule,
+		Trace:         reqParams.trace,
 	}
 	if reqParams.fromTheStart {
 		params.InitialLines = 0
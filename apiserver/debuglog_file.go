@@ -168,7 +168,8 @@ func (stream *logFileStream) filterLine(line []byte) bool {
 	return stream.checkIncludeEntity(log) &&
 		stream.checkIncludeModule(log) &&
 		!stream.exclude(log) &&
-		stream.checkLevel(log)
+		stream.checkLevel(log) &&
+		stream.checkTrace(log)
 }
 
 // countedFilterLine checks the received line for one of the configured tags,
@@ -251,3 +252,7 @@ func (stream *logFileStream) exclude(line *logFileLine) bool {
 func (stream *logFileStream) checkLevel(line *logFileLine) bool {
 	return line.level >= stream.filterLevel
 }
+
+func (stream *logFileStream) checkTrace(line *logFileLine) bool {
+	return stream.trace == "" || strings.Contains(line.line, stream.trace)
+}
@@ -159,6 +159,16 @@ func (s *debugLogFileIntSuite) TestCheckExcludeModule(c *gc.C) {
 	c.Check(checkExcludeModule("unit.mysql/1", "juju", "unit"), jc.IsTrue)
 }
 
+func (s *debugLogFileIntSuite) TestCheckTrace(c *gc.C) {
+	check := func(trace, line string) bool {
+		stream := newLogFileStream(&debugLogParams{trace: trace})
+		return stream.checkTrace(parseLogLine(line))
+	}
+	c.Check(check("", "machine-0: date time DEBUG juju.apiserver no trace"), jc.IsTrue)
+	c.Check(check("a-trace", `machine-0: date time DEBUG juju.apiserver <- {"TraceId":"a-trace"}`), jc.IsTrue)
+	c.Check(check("a-trace", `machine-0: date time DEBUG juju.apiserver <- {"TraceId":"b-trace"}`), jc.IsFalse)
+}
+
 func (s *debugLogFileIntSuite) TestFilterLine(c *gc.C) {
 	stream := newLogFileStream(&debugLogParams{
 		filterLevel:   loggo.INFO,
@@ -97,6 +97,75 @@ func TestingRestrictedApiHandler(st *state.State) rpc.MethodFinder {
 	return newRestrictedRoot(r)
 }
 
+// TestingTokenRoot returns a srvRoot restricted to the given scope, as
+// if logged in with an API token whose validity is checked by check.
+func TestingTokenRoot(st *state.State, scope []string, check func() error) rpc.MethodFinder {
+	r := TestingApiRoot(st)
+	return newTokenRoot(r, scope, check)
+}
+
+// TestingPermissionRoot returns a srvRoot restricted by the given
+// permissions.
+func TestingPermissionRoot(st *state.State, permissions *state.UserPermissions) rpc.MethodFinder {
+	r := TestingApiRoot(st)
+	return newPermissionRoot(r, func() (*state.UserPermissions, error) {
+		return permissions, nil
+	})
+}
+
+// TestingUserPermissionRoot returns a srvRoot restricted by the
+// current permissions of the given user.
+func TestingUserPermissionRoot(st *state.State, user names.UserTag) rpc.MethodFinder {
+	r := TestingApiRoot(st)
+	return newPermissionRoot(r, userPermissions(st, user))
+}
+
+// CallServices returns the names of the services whose entities are
+// identified by the given API call arguments.
+func CallServices(arg interface{}) ([]string, bool) {
+	return callServices(reflect.ValueOf(arg))
+}
+
+// TestingPasswordExpiredRoot returns a srvRoot as if logged in as a
+// user whose password has expired.
+func TestingPasswordExpiredRoot(st *state.State) rpc.MethodFinder {
+	r := TestingApiRoot(st)
+	return newPasswordExpiredRoot(r)
+}
+
+// LoginThrottleAllows returns whether a login throttle with the given
+// limit allows logins from one host at each of the given times.
+func LoginThrottleAllows(limit int, times []time.Time) []bool {
+	t := newLoginThrottle()
+	allowed := make([]bool, len(times))
+	for i, when := range times {
+		allowed[i] = t.allow("10.0.0.1", limit, when)
+	}
+	return allowed
+}
+
+// APIRateLimiterRetryAfter returns, for calls by one user in one class
+// with the given limit at each of the given times, how long the API rate
+// limiter asks the user to wait, or -1 if the call is allowed.
+func APIRateLimiterRetryAfter(limit int, times []time.Time) []time.Duration {
+	l := newAPIRateLimiter()
+	retryAfter := make([]time.Duration, len(times))
+	for i, when := range times {
+		wait, ok := l.allow("user-bob@local", "Client.FullStatus", limit, when)
+		if ok {
+			wait = -1
+		}
+		retryAfter[i] = wait
+	}
+	return retryAfter
+}
+
+// MethodClass returns the most specific class of the given facade
+// method that has a limit, and its limit.
+func MethodClass(limits map[string]int, facade, method string) (string, int) {
+	return methodClass(limits, facade, method)
+}
+
 type preFacadeAdminApi struct{}
 
 func newPreFacadeAdminApi(srv *Server, root *apiHandler, reqNotifier *requestNotifier) interface{} {
@@ -92,12 +92,35 @@ func (h *httpStateWrapper) authenticate(r *http.Request) (names.Tag, error) {
 	if err != nil {
 		return nil, common.ErrBadCreds
 	}
-	_, _, err = checkCreds(h.state, params.LoginRequest{
+	entity, _, err := checkCreds(h.state, params.LoginRequest{
 		AuthTag:     tagPass[0],
 		Credentials: tagPass[1],
 		Nonce:       r.Header.Get("X-Juju-Nonce"),
 	}, true)
-	return tag, err
+	if err != nil {
+		return tag, err
+	}
+	// API tokens restricted to some facades do not give access to
+	// the HTTP endpoints.
+	scope, err := tokenScope(h.state, tagPass[1])
+	if err != nil {
+		return tag, errors.Trace(err)
+	}
+	if len(scope) > 0 {
+		return tag, common.ErrPerm
+	}
+	// Nor do the permissions of restricted users, unless they have
+	// been granted all operations.
+	if user, ok := entity.(*state.User); ok {
+		permissions, err := h.state.UserPermissions(user)
+		if err != nil {
+			return tag, errors.Trace(err)
+		}
+		if !permissions.AllowsAll() {
+			return tag, common.ErrPerm
+		}
+	}
+	return tag, nil
 }
 
 func (h *httpStateWrapper) authenticateUser(r *http.Request) error {
@@ -43,6 +43,7 @@ type KeyManagerAPI struct {
 	canRead    func(string) bool
 	canWrite   func(string) bool
 	check      *common.BlockChecker
+	ownerName  string
 }
 
 var _ KeyManager = (*KeyManagerAPI)(nil)
@@ -59,39 +60,77 @@ func NewKeyManagerAPI(st *state.State, resources *common.Resources, authorizer c
 	}
 	// For gccgo interface comparisons, we need a Tag.
 	owner := names.Tag(env.Owner())
-	// TODO(wallyworld) - replace stub with real canRead function
-	// For now, only admins can read authorised ssh keys.
-	canRead := func(user string) bool {
+	// Users can read and write their own keys, and the environment
+	// owner those of any user. Machine agents can read and write the
+	// juju-system-key.
+	canAccess := func(user string) bool {
 		// Are we a machine agent operating as the system identity?
 		if user == config.JujuSystemKey {
 			_, ismachinetag := authorizer.GetAuthTag().(names.MachineTag)
 			return ismachinetag
 		}
-		return authorizer.GetAuthTag() == owner
-	}
-	// TODO(wallyworld) - replace stub with real canWrite function
-	// For now, only admins can write authorised ssh keys for users.
-	// Machine agents can write the juju-system-key.
-	canWrite := func(user string) bool {
-		// Are we a machine agent writing the Juju system key.
-		if user == config.JujuSystemKey {
-			_, ismachinetag := authorizer.GetAuthTag().(names.MachineTag)
-			return ismachinetag
+		if authorizer.GetAuthTag() == owner {
+			return true
 		}
-		// No point looking to see if the user exists as we are not
-		// yet storing keys on the user.
-		return authorizer.GetAuthTag() == owner
+		userTag, ok := authorizer.GetAuthTag().(names.UserTag)
+		return ok && userTag.IsLocal() && strings.EqualFold(userTag.Name(), user)
 	}
 	return &KeyManagerAPI{
 		state:      st,
 		resources:  resources,
 		authorizer: authorizer,
-		canRead:    canRead,
-		canWrite:   canWrite,
+		canRead:    canAccess,
+		canWrite:   canAccess,
 		check:      common.NewBlockChecker(st),
+		ownerName:  env.Owner().Name(),
 	}, nil
 }
 
+// isEnvironUser reports whether the keys of the given user are those
+// stored in the environment config. The keys of the environment owner
+// and the juju-system-key are kept there; other users' keys are stored
+// on the users themselves.
+func (api *KeyManagerAPI) isEnvironUser(user string) bool {
+	return user == config.JujuSystemKey || strings.EqualFold(user, api.ownerName)
+}
+
+// user returns the local user with the given name. It returns
+// common.ErrPerm if there is no such user, so as not to reveal which
+// users exist.
+func (api *KeyManagerAPI) user(name string) (*state.User, error) {
+	if !names.IsValidUser(name) {
+		return nil, common.ErrPerm
+	}
+	tag := names.NewUserTag(name)
+	if !tag.IsLocal() {
+		return nil, common.ErrPerm
+	}
+	user, err := api.state.User(tag)
+	if errors.IsNotFound(err) {
+		return nil, common.ErrPerm
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return user, nil
+}
+
+// userKeys returns the ssh keys stored on the given user.
+func (api *KeyManagerAPI) userKeys(name string) ([]string, error) {
+	user, err := api.user(name)
+	if err != nil {
+		return nil, err
+	}
+	sshKeys, err := user.SSHKeys()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	keys := make([]string, len(sshKeys))
+	for i, sshKey := range sshKeys {
+		keys[i] = sshKey.Key()
+	}
+	return keys, nil
+}
+
 // ListKeys returns the authorised ssh keys for the specified users.
 func (api *KeyManagerAPI) ListKeys(arg params.ListSSHKeys) (params.StringsResults, error) {
 	if len(arg.Entities.Entities) == 0 {
@@ -99,12 +138,11 @@ func (api *KeyManagerAPI) ListKeys(arg params.ListSSHKeys) (params.StringsResult
 	}
 	results := make([]params.StringsResult, len(arg.Entities.Entities))
 
-	// For now, authorised keys are global, common to all users.
-	var keyInfo []string
+	var environKeyInfo []string
 	cfg, configErr := api.state.EnvironConfig()
 	if configErr == nil {
 		keys := ssh.SplitAuthorisedKeys(cfg.AuthorizedKeys())
-		keyInfo = parseKeys(keys, arg.Mode)
+		environKeyInfo = parseKeys(keys, arg.Mode)
 	}
 
 	for i, entity := range arg.Entities.Entities {
@@ -113,11 +151,19 @@ func (api *KeyManagerAPI) ListKeys(arg params.ListSSHKeys) (params.StringsResult
 			results[i].Error = common.ServerError(common.ErrPerm)
 			continue
 		}
-		// All keys are global, no need to look up the user.
-		if configErr == nil {
-			results[i].Result = keyInfo
+		if api.isEnvironUser(entity.Tag) {
+			if configErr == nil {
+				results[i].Result = environKeyInfo
+			}
+			results[i].Error = common.ServerError(configErr)
+			continue
 		}
-		results[i].Error = common.ServerError(configErr)
+		keys, err := api.userKeys(entity.Tag)
+		if err != nil {
+			results[i].Error = common.ServerError(err)
+			continue
+		}
+		results[i].Result = parseKeys(keys, arg.Mode)
 	}
 	return params.StringsResults{Results: results}, nil
 }
@@ -189,8 +235,10 @@ func (api *KeyManagerAPI) AddKeys(arg params.ModifyUserSSHKeys) (params.ErrorRes
 	if !api.canWrite(arg.User) {
 		return params.ErrorResults{}, common.ServerError(common.ErrPerm)
 	}
+	if !api.isEnvironUser(arg.User) {
+		return api.addUserKeys(arg.User, arg.Keys)
+	}
 
-	// For now, authorised keys are global, common to all users.
 	sshKeys, currentFingerprints, err := api.currentKeyDataForAdd()
 	if err != nil {
 		return params.ErrorResults{}, common.ServerError(fmt.Errorf("reading current key data: %v", err))
@@ -217,6 +265,37 @@ func (api *KeyManagerAPI) AddKeys(arg params.ModifyUserSSHKeys) (params.ErrorRes
 	return result, nil
 }
 
+// addUserKeys adds new ssh keys to the given user.
+func (api *KeyManagerAPI) addUserKeys(name string, keys []string) (params.ErrorResults, error) {
+	user, err := api.user(name)
+	if err != nil {
+		return params.ErrorResults{}, common.ServerError(err)
+	}
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(keys)),
+	}
+	for i, key := range keys {
+		if _, _, err := ssh.KeyFingerprint(key); err != nil {
+			result.Results[i].Error = common.ServerError(fmt.Errorf("invalid ssh key: %s", key))
+			continue
+		}
+		if err := addUserKey(user, key); err != nil {
+			result.Results[i].Error = common.ServerError(err)
+		}
+	}
+	return result, nil
+}
+
+// addUserKey adds an ssh key to the given user, reporting a duplicate
+// key the same way as for the keys in the environment config.
+func addUserKey(user *state.User, key string) error {
+	_, err := user.AddSSHKey(key)
+	if errors.IsAlreadyExists(err) {
+		return errors.Errorf("duplicate ssh key: %s", key)
+	}
+	return err
+}
+
 type importedSSHKey struct {
 	key         string
 	fingerprint string
@@ -281,10 +360,19 @@ func (api *KeyManagerAPI) ImportKeys(arg params.ModifyUserSSHKeys) (params.Error
 		return params.ErrorResults{}, common.ServerError(common.ErrPerm)
 	}
 
-	// For now, authorised keys are global, common to all users.
-	sshKeys, currentFingerprints, err := api.currentKeyDataForAdd()
-	if err != nil {
-		return params.ErrorResults{}, common.ServerError(fmt.Errorf("reading current key data: %v", err))
+	// The keys are either added to the environment config, or to the
+	// user if there is one.
+	var user *state.User
+	var sshKeys []string
+	var currentFingerprints set.Strings
+	var err error
+	if api.isEnvironUser(arg.User) {
+		sshKeys, currentFingerprints, err = api.currentKeyDataForAdd()
+		if err != nil {
+			return params.ErrorResults{}, common.ServerError(fmt.Errorf("reading current key data: %v", err))
+		}
+	} else if user, err = api.user(arg.User); err != nil {
+		return params.ErrorResults{}, common.ServerError(err)
 	}
 
 	importedKeyInfo := runSSHKeyImport(arg.Keys)
@@ -297,6 +385,12 @@ func (api *KeyManagerAPI) ImportKeys(arg params.ModifyUserSSHKeys) (params.Error
 				compoundErr += fmt.Sprintf("%v\n", keyInfo.err)
 				continue
 			}
+			if user != nil {
+				if err := addUserKey(user, keyInfo.key); err != nil {
+					compoundErr += fmt.Sprintf("%v\n", err)
+				}
+				continue
+			}
 			if currentFingerprints.Contains(keyInfo.fingerprint) {
 				compoundErr += fmt.Sprintf("%v\n", errors.Errorf("duplicate ssh key: %s", keyInfo.key))
 				continue
@@ -308,6 +402,9 @@ func (api *KeyManagerAPI) ImportKeys(arg params.ModifyUserSSHKeys) (params.Error
 		}
 
 	}
+	if user != nil {
+		return result, nil
+	}
 	err = api.writeSSHKeys(sshKeys)
 	if err != nil {
 		return params.ErrorResults{}, common.ServerError(err)
@@ -323,7 +420,7 @@ func (api *KeyManagerAPI) currentKeyDataForDelete() (
 	if err != nil {
 		return nil, nil, nil, fmt.Errorf("reading current key data: %v", err)
 	}
-	// For now, authorised keys are global, common to all users.
+	// The keys of the environment owner are stored in the environment config.
 	existingSSHKeys := ssh.SplitAuthorisedKeys(cfg.AuthorizedKeys())
 
 	// Build up a map of keys indexed by fingerprint, and fingerprints indexed by comment
@@ -361,6 +458,9 @@ func (api *KeyManagerAPI) DeleteKeys(arg params.ModifyUserSSHKeys) (params.Error
 	if !api.canWrite(arg.User) {
 		return params.ErrorResults{}, common.ServerError(common.ErrPerm)
 	}
+	if !api.isEnvironUser(arg.User) {
+		return api.deleteUserKeys(arg.User, arg.Keys)
+	}
 
 	sshKeys, invalidKeys, keyComments, err := api.currentKeyDataForDelete()
 	if err != nil {
@@ -398,3 +498,23 @@ func (api *KeyManagerAPI) DeleteKeys(arg params.ModifyUserSSHKeys) (params.Error
 	}
 	return result, nil
 }
+
+// deleteUserKeys deletes the ssh keys, identified by fingerprint or
+// comment, of the given user.
+func (api *KeyManagerAPI) deleteUserKeys(name string, keyIds []string) (params.ErrorResults, error) {
+	user, err := api.user(name)
+	if err != nil {
+		return params.ErrorResults{}, common.ServerError(err)
+	}
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(keyIds)),
+	}
+	for i, keyId := range keyIds {
+		err := user.RemoveSSHKey(keyId)
+		if errors.IsNotFound(err) {
+			err = fmt.Errorf("invalid ssh key: %s", keyId)
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
@@ -18,6 +18,8 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
 	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
 	"github.com/juju/juju/utils/ssh"
 	sshtesting "github.com/juju/juju/utils/ssh/testing"
 )
@@ -107,7 +109,7 @@ func (s *keyManagerSuite) TestListKeys(c *gc.C) {
 	c.Assert(results, gc.DeepEquals, params.StringsResults{
 		Results: []params.StringsResult{
 			{Result: []string{key1, key2, "Invalid key: bad key"}},
-			{Result: []string{key1, key2, "Invalid key: bad key"}},
+			{Error: apiservertesting.ErrUnauthorized},
 		},
 	})
 }
@@ -282,7 +284,6 @@ func (s *keyManagerSuite) assertInvalidUserOperation(c *gc.C, runTestLogic func(
 }
 
 func (s *keyManagerSuite) TestAddKeysInvalidUser(c *gc.C) {
-	c.Skip("no user validation done yet")
 	s.assertInvalidUserOperation(c, func(args params.ModifyUserSSHKeys) error {
 		_, err := s.keymanager.AddKeys(args)
 		return err
@@ -290,7 +291,6 @@ func (s *keyManagerSuite) TestAddKeysInvalidUser(c *gc.C) {
 }
 
 func (s *keyManagerSuite) TestDeleteKeysInvalidUser(c *gc.C) {
-	c.Skip("no user validation done yet")
 	s.assertInvalidUserOperation(c, func(args params.ModifyUserSSHKeys) error {
 		_, err := s.keymanager.DeleteKeys(args)
 		return err
@@ -369,3 +369,162 @@ func (s *keyManagerSuite) TestBlockImportKeys(c *gc.C) {
 	s.AssertBlocked(c, err, "TestBlockImportKeys")
 	s.assertEnvironKeys(c, initialKeys)
 }
+
+func (s *keyManagerSuite) userKeyManager(c *gc.C, user names.UserTag) *keymanager.KeyManagerAPI {
+	anAuthoriser := s.authoriser
+	anAuthoriser.Tag = user
+	api, err := keymanager.NewKeyManagerAPI(s.State, s.resources, anAuthoriser)
+	c.Assert(err, jc.ErrorIsNil)
+	return api
+}
+
+func (s *keyManagerSuite) assertUserKeys(c *gc.C, user *state.User, expected []string) {
+	sshKeys, err := user.SSHKeys()
+	c.Assert(err, jc.ErrorIsNil)
+	keys := make([]string, len(sshKeys))
+	for i, sshKey := range sshKeys {
+		keys[i] = sshKey.Key()
+	}
+	c.Assert(keys, jc.SameContents, expected)
+}
+
+func (s *keyManagerSuite) TestListUserKeys(c *gc.C) {
+	s.setAuthorisedKeys(c, sshtesting.ValidKeyOne.Key+" admin@host")
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	key := sshtesting.ValidKeyTwo.Key + " bob@host"
+	_, err := bob.AddSSHKey(key)
+	c.Assert(err, jc.ErrorIsNil)
+	s.Factory.MakeUser(c, &factory.UserParams{Name: "mary"})
+
+	args := params.ListSSHKeys{
+		Entities: params.Entities{[]params.Entity{
+			{Tag: "bob"},
+			{Tag: "mary"},
+			{Tag: s.AdminUserTag(c).Name()},
+		}},
+		Mode: ssh.Fingerprints,
+	}
+	// Users can list their own keys only.
+	results, err := s.userKeyManager(c, bob.UserTag()).ListKeys(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.DeepEquals, params.StringsResults{
+		Results: []params.StringsResult{
+			{Result: []string{sshtesting.ValidKeyTwo.Fingerprint + " (bob@host)"}},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	// The environment owner can list anyone's keys.
+	results, err = s.keymanager.ListKeys(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.DeepEquals, params.StringsResults{
+		Results: []params.StringsResult{
+			{Result: []string{sshtesting.ValidKeyTwo.Fingerprint + " (bob@host)"}},
+			{Result: nil},
+			{Result: []string{sshtesting.ValidKeyOne.Fingerprint + " (admin@host)"}},
+		},
+	})
+}
+
+func (s *keyManagerSuite) TestAddUserKeys(c *gc.C) {
+	initialKey := sshtesting.ValidKeyOne.Key + " admin@host"
+	s.setAuthorisedKeys(c, initialKey)
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	key1 := sshtesting.ValidKeyTwo.Key + " bob@host"
+	_, err := bob.AddSSHKey(key1)
+	c.Assert(err, jc.ErrorIsNil)
+
+	key2 := sshtesting.ValidKeyThree.Key + " bob@laptop"
+	args := params.ModifyUserSSHKeys{
+		User: "bob",
+		Keys: []string{key1, key2, "invalid-key"},
+	}
+	results, err := s.userKeyManager(c, bob.UserTag()).AddKeys(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{Error: apiservertesting.ServerError(fmt.Sprintf("duplicate ssh key: %s", key1))},
+			{Error: nil},
+			{Error: apiservertesting.ServerError("invalid ssh key: invalid-key")},
+		},
+	})
+	s.assertUserKeys(c, bob, []string{key1, key2})
+	// The environment's keys are unchanged.
+	s.assertEnvironKeys(c, []string{initialKey})
+}
+
+func (s *keyManagerSuite) TestUserCannotModifyOtherUsersKeys(c *gc.C) {
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	mary := s.Factory.MakeUser(c, &factory.UserParams{Name: "mary"})
+	key := sshtesting.ValidKeyTwo.Key + " mary@host"
+	_, err := mary.AddSSHKey(key)
+	c.Assert(err, jc.ErrorIsNil)
+
+	api := s.userKeyManager(c, bob.UserTag())
+	args := params.ModifyUserSSHKeys{
+		User: "mary",
+		Keys: []string{sshtesting.ValidKeyThree.Key},
+	}
+	_, err = api.AddKeys(args)
+	c.Assert(err, gc.DeepEquals, apiservertesting.ErrUnauthorized)
+	args.Keys = []string{"mary@host"}
+	_, err = api.DeleteKeys(args)
+	c.Assert(err, gc.DeepEquals, apiservertesting.ErrUnauthorized)
+	args.User = s.AdminUserTag(c).Name()
+	_, err = api.DeleteKeys(args)
+	c.Assert(err, gc.DeepEquals, apiservertesting.ErrUnauthorized)
+	s.assertUserKeys(c, mary, []string{key})
+}
+
+func (s *keyManagerSuite) TestDeleteUserKeys(c *gc.C) {
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	key1 := sshtesting.ValidKeyTwo.Key + " bob@host"
+	key2 := sshtesting.ValidKeyThree.Key + " bob@laptop"
+	for _, key := range []string{key1, key2} {
+		_, err := bob.AddSSHKey(key)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	// Unlike the environment's keys, all of a user's keys may be deleted.
+	args := params.ModifyUserSSHKeys{
+		User: "bob",
+		Keys: []string{sshtesting.ValidKeyTwo.Fingerprint, "bob@laptop", "invalid-key"},
+	}
+	results, err := s.keymanager.DeleteKeys(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{Error: nil},
+			{Error: nil},
+			{Error: apiservertesting.ServerError("invalid ssh key: invalid-key")},
+		},
+	})
+	s.assertUserKeys(c, bob, nil)
+}
+
+func (s *keyManagerSuite) TestImportUserKeys(c *gc.C) {
+	s.PatchValue(&keymanager.RunSSHImportId, keymanagertesting.FakeImport)
+	initialKey := sshtesting.ValidKeyOne.Key + " admin@host"
+	s.setAuthorisedKeys(c, initialKey)
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	key2 := sshtesting.ValidKeyTwo.Key
+	_, err := bob.AddSSHKey(key2)
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.ModifyUserSSHKeys{
+		User: "bob",
+		Keys: []string{"lp:existing", "lp:validuser", "invalid-key"},
+	}
+	results, err := s.userKeyManager(c, bob.UserTag()).ImportKeys(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{Error: apiservertesting.ServerError(fmt.Sprintf("duplicate ssh key: %s", key2))},
+			{Error: nil},
+			{Error: apiservertesting.ServerError("invalid ssh key id: invalid-key")},
+		},
+	})
+	s.assertUserKeys(c, bob, []string{key2, sshtesting.ValidKeyThree.Key})
+	s.assertEnvironKeys(c, []string{initialKey})
+}
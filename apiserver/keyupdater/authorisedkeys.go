@@ -11,7 +11,6 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/watcher"
-	"github.com/juju/juju/utils/ssh"
 )
 
 func init() {
@@ -53,9 +52,8 @@ func NewKeyUpdaterAPI(
 }
 
 // WatchAuthorisedKeys starts a watcher to track changes to the authorised ssh keys
-// for the specified machines.
-// The current implementation relies on global authorised keys being stored in the environment config.
-// This will change as new user management and authorisation functionality is added.
+// for the specified machines. The watcher fires when the keys in the environment
+// config change, and when the keys or access of the environment's users change.
 func (api *KeyUpdaterAPI) WatchAuthorisedKeys(arg params.Entities) (params.NotifyWatchResults, error) {
 	results := make([]params.NotifyWatchResult, len(arg.Entities))
 
@@ -84,7 +82,7 @@ func (api *KeyUpdaterAPI) WatchAuthorisedKeys(arg params.Entities) (params.Notif
 			continue
 		}
 		// 3. Watch for changes
-		watch := api.state.WatchForEnvironConfigChanges()
+		watch := api.state.WatchSSHKeys()
 		// Consume the initial event.
 		if _, ok := <-watch.Changes(); ok {
 			results[i].NotifyWatcherId = api.resources.Register(watch)
@@ -96,21 +94,17 @@ func (api *KeyUpdaterAPI) WatchAuthorisedKeys(arg params.Entities) (params.Notif
 	return params.NotifyWatchResults{Results: results}, nil
 }
 
-// AuthorisedKeys reports the authorised ssh keys for the specified machines.
-// The current implementation relies on global authorised keys being stored in the environment config.
-// This will change as new user management and authorisation functionality is added.
+// AuthorisedKeys reports the authorised ssh keys for the specified machines:
+// the keys in the environment config, and the keys of the enabled users with
+// access to the environment.
 func (api *KeyUpdaterAPI) AuthorisedKeys(arg params.Entities) (params.StringsResults, error) {
 	if len(arg.Entities) == 0 {
 		return params.StringsResults{}, nil
 	}
 	results := make([]params.StringsResult, len(arg.Entities))
 
-	// For now, authorised keys are global, common to all machines.
-	var keys []string
-	config, configErr := api.state.EnvironConfig()
-	if configErr == nil {
-		keys = ssh.SplitAuthorisedKeys(config.AuthorizedKeys())
-	}
+	// Authorised keys are common to all machines in the environment.
+	keys, keysErr := api.state.EnvironSSHKeys()
 
 	canRead, err := api.getCanRead()
 	if err != nil {
@@ -137,10 +131,10 @@ func (api *KeyUpdaterAPI) AuthorisedKeys(arg params.Entities) (params.StringsRes
 			continue
 		}
 		// 3. Get keys
-		if configErr == nil {
+		if keysErr == nil {
 			results[i].Result = keys
 		} else {
-			err = configErr
+			err = keysErr
 		}
 		results[i].Error = common.ServerError(err)
 	}
@@ -15,6 +15,8 @@ import (
 	jujutesting "github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/state"
 	statetesting "github.com/juju/juju/state/testing"
+	"github.com/juju/juju/testing/factory"
+	sshtesting "github.com/juju/juju/utils/ssh/testing"
 )
 
 type authorisedKeysSuite struct {
@@ -107,8 +109,14 @@ func (s *authorisedKeysSuite) TestWatchAuthorisedKeys(c *gc.C) {
 	wc.AssertNoChange()
 
 	s.setAuthorizedKeys(c, "key1\nkey2")
+	wc.AssertOneChange()
 
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	wc.AssertOneChange()
+	_, err = user.AddSSHKey(sshtesting.ValidKeyOne.Key + " bob@laptop")
+	c.Assert(err, jc.ErrorIsNil)
 	wc.AssertOneChange()
+
 	statetesting.AssertStop(c, w)
 	wc.AssertClosed()
 }
@@ -140,3 +148,35 @@ func (s *authorisedKeysSuite) TestAuthorisedKeys(c *gc.C) {
 		},
 	})
 }
+
+func (s *authorisedKeysSuite) TestAuthorisedKeysIncludesUserKeys(c *gc.C) {
+	s.setAuthorizedKeys(c, "key1\nkey2")
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	_, err := bob.AddSSHKey(sshtesting.ValidKeyOne.Key + " bob@laptop")
+	c.Assert(err, jc.ErrorIsNil)
+	mary := s.Factory.MakeUser(c, &factory.UserParams{Name: "mary", Disabled: true})
+	_, err = mary.AddSSHKey(sshtesting.ValidKeyTwo.Key + " mary@laptop")
+	c.Assert(err, gc.ErrorMatches, `cannot add ssh key for user "mary": user disabled`)
+
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: s.rawMachine.Tag().String()}},
+	}
+	results, err := s.keyupdater.AuthorisedKeys(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.DeepEquals, params.StringsResults{
+		Results: []params.StringsResult{
+			{Result: []string{"key1", "key2", sshtesting.ValidKeyOne.Key + " bob@laptop"}},
+		},
+	})
+
+	// The keys of disabled users are no longer authorised.
+	err = bob.Disable()
+	c.Assert(err, jc.ErrorIsNil)
+	results, err = s.keyupdater.AuthorisedKeys(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.DeepEquals, params.StringsResults{
+		Results: []params.StringsResult{
+			{Result: []string{"key1", "key2"}},
+		},
+	})
+}
@@ -0,0 +1,67 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"sync"
+	"time"
+)
+
+// loginThrottleWindow is the period over which logins from an address
+// are counted against the login rate limit.
+const loginThrottleWindow = time.Minute
+
+// loginThrottle limits the rate of user logins from each address.
+type loginThrottle struct {
+	mu        sync.Mutex
+	attempts  map[string][]time.Time
+	lastSweep time.Time
+}
+
+func newLoginThrottle() *loginThrottle {
+	return &loginThrottle{
+		attempts: make(map[string][]time.Time),
+	}
+}
+
+// allow records a login attempt from the given host at the given time,
+// and reports whether there have been no more than limit attempts from
+// the host in the preceding minute, including this one. A limit of zero
+// allows all attempts.
+func (t *loginThrottle) allow(host string, limit int, now time.Time) bool {
+	if limit <= 0 {
+		return true
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if now.Sub(t.lastSweep) > loginThrottleWindow {
+		// Forget the hosts that have not tried to log in lately,
+		// so that the map does not grow without bound.
+		for h, attempts := range t.attempts {
+			if len(recentAttempts(attempts, now)) == 0 {
+				delete(t.attempts, h)
+			}
+		}
+		t.lastSweep = now
+	}
+	attempts := recentAttempts(t.attempts[host], now)
+	if len(attempts) >= limit {
+		t.attempts[host] = attempts
+		return false
+	}
+	t.attempts[host] = append(attempts, now)
+	return true
+}
+
+// recentAttempts returns the attempts made within the throttle window
+// before now. The attempts must be in time order.
+func recentAttempts(attempts []time.Time, now time.Time) []time.Time {
+	cutoff := now.Add(-loginThrottleWindow)
+	for i, when := range attempts {
+		if when.After(cutoff) {
+			return attempts[i:]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver"
+	"github.com/juju/juju/testing"
+)
+
+type loginThrottleSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&loginThrottleSuite{})
+
+func (s *loginThrottleSuite) TestAllow(c *gc.C) {
+	start := time.Date(2015, 6, 1, 12, 0, 0, 0, time.UTC)
+	at := func(seconds ...int) []time.Time {
+		times := make([]time.Time, len(seconds))
+		for i, s := range seconds {
+			times[i] = start.Add(time.Duration(s) * time.Second)
+		}
+		return times
+	}
+	for i, test := range []struct {
+		limit   int
+		times   []time.Time
+		allowed []bool
+	}{{
+		limit:   0,
+		times:   at(0, 0, 0),
+		allowed: []bool{true, true, true},
+	}, {
+		limit:   2,
+		times:   at(0, 10, 20, 30),
+		allowed: []bool{true, true, false, false},
+	}, {
+		// Refused attempts do not count against the limit.
+		limit:   2,
+		times:   at(0, 30, 40, 61, 95),
+		allowed: []bool{true, true, false, true, true},
+	}} {
+		c.Logf("test %d", i)
+		c.Check(apiserver.LoginThrottleAllows(test.limit, test.times), jc.DeepEquals, test.allowed)
+	}
+}
@@ -0,0 +1,243 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"gopkg.in/mgo.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/state"
+)
+
+// serverMetrics holds the operational metrics of the API server, which
+// the /metrics endpoint reports in the Prometheus text exposition
+// format.
+type serverMetrics struct {
+	// requests records the API requests served on all connections.
+	requests *rpc.RequestMetrics
+
+	// connections and loginFailures must be accessed atomically.
+	connections   int64
+	loginFailures int64
+
+	mu sync.Mutex
+	// handlers holds the handlers of the connections being served,
+	// so that their watchers can be counted.
+	handlers map[*apiHandler]bool
+}
+
+func newServerMetrics() *serverMetrics {
+	// The mongo driver only keeps statistics once asked to.
+	mgo.SetStats(true)
+	return &serverMetrics{
+		requests: rpc.NewRequestMetrics(),
+		handlers: make(map[*apiHandler]bool),
+	}
+}
+
+// connectionOpened records that a connection is being served by the
+// given handler, which may be nil if the connection serves only an
+// error.
+func (m *serverMetrics) connectionOpened(h *apiHandler) {
+	atomic.AddInt64(&m.connections, 1)
+	if h != nil {
+		m.mu.Lock()
+		m.handlers[h] = true
+		m.mu.Unlock()
+	}
+}
+
+// connectionClosed records that a connection recorded by
+// connectionOpened has closed.
+func (m *serverMetrics) connectionClosed(h *apiHandler) {
+	atomic.AddInt64(&m.connections, -1)
+	if h != nil {
+		m.mu.Lock()
+		delete(m.handlers, h)
+		m.mu.Unlock()
+	}
+}
+
+// loginFailed records a login with invalid credentials.
+func (m *serverMetrics) loginFailed() {
+	atomic.AddInt64(&m.loginFailures, 1)
+}
+
+// watchers returns the number of watchers held by the connections
+// being served.
+func (m *serverMetrics) watchers() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	count := 0
+	for h := range m.handlers {
+		count += h.resources.CountFunc(isWatcher)
+	}
+	return count
+}
+
+func isWatcher(r common.Resource) bool {
+	switch r.(type) {
+	case state.Watcher, *state.Multiwatcher:
+		return true
+	}
+	return false
+}
+
+// write writes the metrics to w in the Prometheus text exposition
+// format.
+func (m *serverMetrics) write(w io.Writer) error {
+	out := &metricsWriter{w: bufio.NewWriter(w)}
+
+	requests := m.requests.Snapshot()
+	keys := make([]rpc.RequestKey, 0, len(requests))
+	for key := range requests {
+		keys = append(keys, key)
+	}
+	sort.Sort(requestKeys(keys))
+	out.header("juju_apiserver_requests_total", "counter", "Number of API requests served, by facade and method.")
+	for _, key := range keys {
+		out.sample("juju_apiserver_requests_total", requestLabels(key), requests[key].Count)
+	}
+	out.header("juju_apiserver_request_errors_total", "counter", "Number of API requests that returned an error, by facade and method.")
+	for _, key := range keys {
+		out.sample("juju_apiserver_request_errors_total", requestLabels(key), requests[key].Errors)
+	}
+	out.header("juju_apiserver_request_duration_seconds", "histogram", "Time spent serving API requests, by facade and method.")
+	for _, key := range keys {
+		stats := requests[key]
+		labels := requestLabels(key)
+		for i, bound := range rpc.DurationBuckets {
+			le := strconv.FormatFloat(bound.Seconds(), 'g', -1, 64)
+			out.sample("juju_apiserver_request_duration_seconds_bucket", labels+`,le="`+le+`"`, stats.Buckets[i])
+		}
+		out.sample("juju_apiserver_request_duration_seconds_bucket", labels+`,le="+Inf"`, stats.Count)
+		out.sample("juju_apiserver_request_duration_seconds_sum", labels, stats.Duration.Seconds())
+		out.sample("juju_apiserver_request_duration_seconds_count", labels, stats.Count)
+	}
+
+	out.metric("juju_apiserver_connections", "gauge", "Number of API connections being served.", atomic.LoadInt64(&m.connections))
+	out.metric("juju_apiserver_login_failures_total", "counter", "Number of logins with invalid credentials.", atomic.LoadInt64(&m.loginFailures))
+	out.metric("juju_apiserver_watchers", "gauge", "Number of watchers held by API connections.", m.watchers())
+
+	txns := state.GetTxnStats()
+	out.metric("juju_state_txns_total", "counter", "Number of transactions run, not counting retries.", txns.Runs)
+	out.metric("juju_state_txn_retries_total", "counter", "Number of times transactions were retried after their assertions failed.", txns.Retries)
+	out.metric("juju_state_txns_aborted_total", "counter", "Number of transactions that failed their assertions.", txns.Aborted)
+
+	mongo := mgo.GetStats()
+	out.metric("juju_mongo_clusters", "gauge", "Number of mongo clusters connected to.", mongo.Clusters)
+	out.metric("juju_mongo_master_connections", "gauge", "Number of connections to mongo masters.", mongo.MasterConns)
+	out.metric("juju_mongo_slave_connections", "gauge", "Number of connections to mongo slaves.", mongo.SlaveConns)
+	out.metric("juju_mongo_sockets_alive", "gauge", "Number of mongo sockets alive.", mongo.SocketsAlive)
+	out.metric("juju_mongo_sockets_in_use", "gauge", "Number of mongo sockets used by sessions.", mongo.SocketsInUse)
+	out.metric("juju_mongo_socket_refs", "gauge", "Number of references to mongo sockets held by sessions.", mongo.SocketRefs)
+	out.metric("juju_mongo_sent_ops_total", "counter", "Number of operations sent to mongo.", mongo.SentOps)
+	out.metric("juju_mongo_received_ops_total", "counter", "Number of operation replies received from mongo.", mongo.ReceivedOps)
+	out.metric("juju_mongo_received_docs_total", "counter", "Number of documents received from mongo.", mongo.ReceivedDocs)
+
+	if out.err != nil {
+		return out.err
+	}
+	return out.w.Flush()
+}
+
+// metricsWriter writes metrics in the Prometheus text exposition
+// format, remembering the first error.
+type metricsWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (out *metricsWriter) printf(format string, args ...interface{}) {
+	if out.err == nil {
+		_, out.err = fmt.Fprintf(out.w, format, args...)
+	}
+}
+
+func (out *metricsWriter) header(name, kind, help string) {
+	out.printf("# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+func (out *metricsWriter) sample(name, labels string, value interface{}) {
+	if f, ok := value.(float64); ok {
+		value = strconv.FormatFloat(f, 'g', -1, 64)
+	}
+	if labels == "" {
+		out.printf("%s %v\n", name, value)
+	} else {
+		out.printf("%s{%s} %v\n", name, labels, value)
+	}
+}
+
+func (out *metricsWriter) metric(name, kind, help string, value interface{}) {
+	out.header(name, kind, help)
+	out.sample(name, "", value)
+}
+
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func requestLabels(key rpc.RequestKey) string {
+	return fmt.Sprintf(`facade="%s",method="%s"`,
+		labelValueReplacer.Replace(key.Type),
+		labelValueReplacer.Replace(key.Action))
+}
+
+type requestKeys []rpc.RequestKey
+
+func (k requestKeys) Len() int      { return len(k) }
+func (k requestKeys) Swap(i, j int) { k[i], k[j] = k[j], k[i] }
+func (k requestKeys) Less(i, j int) bool {
+	if k[i].Type != k[j].Type {
+		return k[i].Type < k[j].Type
+	}
+	return k[i].Action < k[j].Action
+}
+
+// metricsHandler serves the metrics of the API server to the users of
+// the state server environment.
+type metricsHandler struct {
+	httpHandler
+	metrics *serverMetrics
+}
+
+func (h *metricsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	stateWrapper, err := h.validateEnvironUUID(r)
+	if err != nil {
+		h.sendError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	defer stateWrapper.cleanup()
+
+	if err := stateWrapper.authenticateUser(r); err != nil {
+		h.authError(w, h)
+		return
+	}
+	if r.Method != "GET" {
+		h.sendError(w, http.StatusMethodNotAllowed, fmt.Sprintf("unsupported method: %q", r.Method))
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	if err := h.metrics.write(w); err != nil {
+		logger.Errorf("cannot write metrics: %v", err)
+	}
+}
+
+// sendError sends an error as plain text, like the metrics themselves.
+func (h *metricsHandler) sendError(w http.ResponseWriter, statusCode int, message string) {
+	logger.Debugf("sending error: %v %v", statusCode, message)
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(statusCode)
+	fmt.Fprintln(w, message)
+}
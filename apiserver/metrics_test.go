@@ -0,0 +1,64 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	"net/http"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api"
+)
+
+type metricsSuite struct {
+	userAuthHttpSuite
+}
+
+var _ = gc.Suite(&metricsSuite{})
+
+func (s *metricsSuite) metricsURL(c *gc.C) string {
+	return s.makeURL(c, "https", "/metrics", nil).String()
+}
+
+func (s *metricsSuite) TestRequiresAuth(c *gc.C) {
+	resp, err := s.sendRequest(c, "", "", "GET", s.metricsURL(c), "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	body := assertResponse(c, resp, http.StatusUnauthorized, "text/plain")
+	c.Assert(string(body), gc.Equals, "unauthorized\n")
+}
+
+func (s *metricsSuite) TestInvalidHTTPMethod(c *gc.C) {
+	resp, err := s.authRequest(c, "POST", s.metricsURL(c), "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	body := assertResponse(c, resp, http.StatusMethodNotAllowed, "text/plain")
+	c.Assert(string(body), gc.Equals, `unsupported method: "POST"`+"\n")
+}
+
+func (s *metricsSuite) TestMetrics(c *gc.C) {
+	// Make a failed login, to be counted.
+	info := s.APIInfo(c)
+	info.Password = "wrong password"
+	_, err := api.Open(info, api.DialOpts{})
+	c.Assert(err, gc.ErrorMatches, "invalid entity name or password")
+
+	resp, err := s.authRequest(c, "GET", s.metricsURL(c), "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	body := string(assertResponse(c, resp, http.StatusOK, "text/plain; version=0.0.4"))
+
+	for _, pattern := range []string{
+		`# TYPE juju_apiserver_requests_total counter`,
+		`juju_apiserver_requests_total\{facade="Admin",method="Login"\} [1-9][0-9]*`,
+		`juju_apiserver_request_duration_seconds_bucket\{facade="Admin",method="Login",le="\+Inf"\} [1-9][0-9]*`,
+		`# TYPE juju_apiserver_connections gauge`,
+		`juju_apiserver_connections [1-9][0-9]*`,
+		`juju_apiserver_login_failures_total [1-9][0-9]*`,
+		`juju_apiserver_watchers [0-9]+`,
+		`juju_state_txns_total [1-9][0-9]*`,
+		`juju_state_txn_retries_total [0-9]+`,
+		`juju_mongo_sockets_alive [1-9][0-9]*`,
+	} {
+		c.Check(body, gc.Matches, `(?s)(.*\n)?`+pattern+`\n.*`)
+	}
+}
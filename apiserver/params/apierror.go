@@ -55,6 +55,7 @@ const (
 	CodeActionNotAvailable        = "action no longer available"
 	CodeOperationBlocked          = "operation is blocked"
 	CodeLeadershipClaimDenied     = "leadership claim denied"
+	CodeRateLimited               = rpc.CodeRateLimited
 )
 
 // ErrCode returns the error code associated with
@@ -173,3 +174,7 @@ func IsCodeOperationBlocked(err error) bool {
 func IsCodeLeadershipClaimDenied(err error) bool {
 	return ErrCode(err) == CodeLeadershipClaimDenied
 }
+
+func IsCodeRateLimited(err error) bool {
+	return ErrCode(err) == CodeRateLimited
+}
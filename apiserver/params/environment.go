@@ -51,6 +51,10 @@ type ModifyEnvironUser struct {
 // SetEnvironAgentVersion client API call.
 type SetEnvironAgentVersion struct {
 	Version version.Number
+
+	// BatchSize, if positive, requests a rolling upgrade that
+	// upgrades BatchSize machines at a time.
+	BatchSize int
 }
 
 // EnvUserInfo holds information on a user.
@@ -370,6 +370,8 @@ const (
 	KindCombined HistoryKind = "combined"
 	KindAgent    HistoryKind = "agent"
 	KindWorkload HistoryKind = "workload"
+	KindMachine  HistoryKind = "machine"
+	KindService  HistoryKind = "service"
 )
 
 // StatusHistory holds the parameters to filter a status history query.
@@ -379,6 +381,189 @@ type StatusHistory struct {
 	Name string
 }
 
+// HookStats holds the parameters to filter a hook execution query.
+type HookStats struct {
+	Name string
+	Size int
+}
+
+// HookExecution holds the timing and outcome of a hook, action
+// or juju-run command executed by a unit.
+type HookExecution struct {
+	Kind      string
+	Name      string
+	Started   time.Time
+	Finished  time.Time
+	ExitCode  int
+	ToolCalls int
+}
+
+// HookStatsResult holds the hook executions recorded
+// for a unit, most recent first.
+type HookStatsResult struct {
+	Executions []HookExecution
+}
+
+// UnitHookExecutions holds the hook executions to record for a unit.
+type UnitHookExecutions struct {
+	Tag        string
+	Executions []HookExecution
+}
+
+// RecordHookExecutions holds the arguments for making a
+// RecordHookExecutions API call.
+type RecordHookExecutions struct {
+	Units []UnitHookExecutions
+}
+
+// HookRetryPolicy describes how a service's units retry failed
+// hooks. A zero MaxAttempts disables automatic retries.
+type HookRetryPolicy struct {
+	MaxAttempts int
+	Delay       time.Duration
+	MaxDelay    time.Duration
+}
+
+// PlacementPolicy describes where a service's units may be placed
+// relative to the units of other services. The zero value places no
+// restrictions on the service's units.
+type PlacementPolicy struct {
+	AntiAffinity    []string
+	Affinity        []string
+	MaxUnitsPerZone int
+}
+
+// ScalingPolicy describes how the number of units of a service is
+// adjusted according to a metric reported by its units. The zero
+// value disables autoscaling.
+type ScalingPolicy struct {
+	Metric         string
+	ScaleUpAbove   float64
+	ScaleDownBelow float64
+	Window         time.Duration
+	MinUnits       int
+	MaxUnits       int
+	Cooldown       time.Duration
+}
+
+// HookRetryPolicyResult holds a hook retry policy or an error.
+type HookRetryPolicyResult struct {
+	Error  *Error
+	Result HookRetryPolicy
+}
+
+// HookRetryPolicyResults holds the results of a HookRetryPolicy
+// API call.
+type HookRetryPolicyResults struct {
+	Results []HookRetryPolicyResult
+}
+
+// ScheduledEvent holds a deferred event scheduled by a unit's charm.
+// Cron is empty for one-shot events.
+type ScheduledEvent struct {
+	Name string
+	Due  time.Time
+	Cron string
+}
+
+// UnitScheduledEvent holds an event to schedule for a unit.
+type UnitScheduledEvent struct {
+	Tag   string
+	Event ScheduledEvent
+}
+
+// ScheduleEvents holds the arguments for making the ScheduleEvents
+// and FireScheduledEvents API calls.
+type ScheduleEvents struct {
+	Events []UnitScheduledEvent
+}
+
+// UnitScheduledEventName identifies an event scheduled by a unit.
+type UnitScheduledEventName struct {
+	Tag  string
+	Name string
+}
+
+// UnitScheduledEventNames holds the arguments for making a
+// CancelScheduledEvents API call.
+type UnitScheduledEventNames struct {
+	Names []UnitScheduledEventName
+}
+
+// ScheduledEventsResult holds the events scheduled by a unit, or an
+// error.
+type ScheduledEventsResult struct {
+	Error  *Error
+	Events []ScheduledEvent
+}
+
+// ScheduledEventsResults holds the results of a ScheduledEvents
+// API call.
+type ScheduledEventsResults struct {
+	Results []ScheduledEventsResult
+}
+
+// UnitDebugHooksSession holds a debug-hooks session opened on a unit
+// through the API, streaming the output of the selected hooks and
+// actions until it expires.
+type UnitDebugHooksSession struct {
+	Hooks       []string
+	Actions     []string
+	RelationIds []int
+	Expires     time.Time
+}
+
+// DebugHooksSessionResult holds a unit's debug-hooks session, if it
+// has one, or an error.
+type DebugHooksSessionResult struct {
+	Error   *Error
+	Session *UnitDebugHooksSession
+}
+
+// DebugHooksSessionResults holds the results of a DebugHooksSessions
+// API call.
+type DebugHooksSessionResults struct {
+	Results []DebugHooksSessionResult
+}
+
+// UnitEndpoint identifies an endpoint of a unit's service. An empty
+// endpoint name refers to the service's default network.
+type UnitEndpoint struct {
+	Tag      string
+	Endpoint string
+}
+
+// UnitEndpoints holds the arguments for making a NetworkInfo API call.
+type UnitEndpoints struct {
+	Endpoints []UnitEndpoint
+}
+
+// NetworkInfoAddress holds an address and the CIDR of its subnet.
+type NetworkInfoAddress struct {
+	Address string
+	CIDR    string
+}
+
+// NetworkInfo holds a network interface of a unit's machine and the
+// addresses on it.
+type NetworkInfo struct {
+	InterfaceName string
+	MACAddress    string
+	Addresses     []NetworkInfoAddress
+}
+
+// NetworkInfoResult holds the network interfaces a unit uses for an
+// endpoint, or an error.
+type NetworkInfoResult struct {
+	Error *Error
+	Info  []NetworkInfo
+}
+
+// NetworkInfoResults holds the results of a NetworkInfo API call.
+type NetworkInfoResults struct {
+	Results []NetworkInfoResult
+}
+
 // StatusResult holds an entity status, extra information, or an
 // error.
 type StatusResult struct {
@@ -594,6 +779,16 @@ type ProvisioningInfo struct {
 	Jobs        []multiwatcher.MachineJob
 	Volumes     []VolumeParams
 	Tags        map[string]string
+
+	// SubnetsToZones maps the provider ids of the subnets the machine
+	// should be started in, as required by its spaces constraint, to
+	// the availability zones they are in.
+	SubnetsToZones map[string][]string
+
+	// ExcludeZones holds the availability zones the machine must not
+	// be started in, to honour the placement policies of the services
+	// with units assigned to it.
+	ExcludeZones []string
 }
 
 // ProvisioningInfoResult holds machine provisioning info or an error.
@@ -431,3 +431,22 @@ type APIHostPortsResult struct {
 func (r APIHostPortsResult) NetworkHostsPorts() [][]network.HostPort {
 	return NetworkHostsPorts(r.Servers)
 }
+
+// SpaceSubnets holds the name of a space and the CIDRs of subnets
+// in it.
+type SpaceSubnets struct {
+	Name  string   `json:"Name"`
+	CIDRs []string `json:"CIDRs"`
+}
+
+// SpacesSubnets holds the arguments of the Spaces.CreateSpaces and
+// Spaces.AddSubnetsToSpace API calls.
+type SpacesSubnets struct {
+	Spaces []SpaceSubnets `json:"Spaces"`
+}
+
+// ListSpacesResults holds the result of the Spaces.ListSpaces API
+// call.
+type ListSpacesResults struct {
+	Results []SpaceSubnets `json:"Results"`
+}
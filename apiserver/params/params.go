@@ -186,6 +186,29 @@ type DestroyMachines struct {
 	Force        bool
 }
 
+// MachinePool describes a pool of spare machines kept ready for
+// units to be assigned to.
+type MachinePool struct {
+	Name        string
+	Size        int
+	Series      string
+	Constraints constraints.Value
+
+	// Spares holds the ids of the pool's spare machines. It is
+	// ignored by SetMachinePool.
+	Spares []string
+}
+
+// MachinePoolsResult holds the result of a MachinePools call.
+type MachinePoolsResult struct {
+	Pools []MachinePool
+}
+
+// RemoveMachinePool holds the parameters for the RemoveMachinePool call.
+type RemoveMachinePool struct {
+	Name string
+}
+
 // ServicesDeploy holds the parameters for deploying one or more services.
 type ServicesDeploy struct {
 	Services []ServiceDeploy
@@ -203,6 +226,10 @@ type ServiceDeploy struct {
 	Placement     []*instance.Placement
 	Networks      []string
 	Storage       map[string]storage.Constraints
+
+	// EndpointBindings maps charm endpoint names to the spaces they
+	// are bound to; the empty endpoint name sets the default space.
+	EndpointBindings map[string]string
 }
 
 // ServiceUpdate holds the parameters for making the ServiceUpdate call.
@@ -214,13 +241,26 @@ type ServiceUpdate struct {
 	SettingsStrings map[string]string
 	SettingsYAML    string // Takes precedence over SettingsStrings if both are present.
 	Constraints     *constraints.Value
+	HookRetryPolicy *HookRetryPolicy
+	PlacementPolicy *PlacementPolicy
+	ScalingPolicy   *ScalingPolicy
 }
 
-// ServiceSetCharm sets the charm for a given service.
+// ServiceSetCharm sets the charm for a given service. When Canary is
+// positive, only that many units are upgraded at first, and the rest
+// follow BatchSize units at a time.
 type ServiceSetCharm struct {
 	ServiceName string
 	CharmUrl    string
 	Force       bool
+	Canary      int
+	BatchSize   int
+}
+
+// ServiceCharmRollout identifies the service whose charm rollout is
+// acted upon.
+type ServiceCharmRollout struct {
+	ServiceName string
 }
 
 // ServiceExpose holds the parameters for making the ServiceExpose call.
@@ -228,6 +268,17 @@ type ServiceExpose struct {
 	ServiceName string
 }
 
+// DebugHooksSession holds the parameters for making the
+// OpenDebugHooksSession and CloseDebugHooksSession calls. Hooks,
+// Actions and RelationIds select the hooks and actions streamed by
+// the session, as for the debug-hooks command.
+type DebugHooksSession struct {
+	UnitName    string
+	Hooks       []string
+	Actions     []string
+	RelationIds []int
+}
+
 // ServiceSet holds the parameters for a ServiceSet
 // command. Options contains the configuration data.
 type ServiceSet struct {
@@ -338,6 +389,47 @@ type AddServiceUnits struct {
 	Placement     []*instance.Placement
 }
 
+// Kinds of change accepted by the ApplyChanges call.
+const (
+	ChangeAddService  = "add-service"
+	ChangeAddUnits    = "add-units"
+	ChangeAddRelation = "add-relation"
+	ChangeSetConfig   = "set-config"
+	ChangeExpose      = "expose"
+)
+
+// EnvironmentChange holds a single operation of an ApplyChanges call.
+// Kind selects which of the other fields holds its parameters.
+type EnvironmentChange struct {
+	Kind        string
+	AddService  *ServiceDeploy
+	AddUnits    *AddServiceUnits
+	AddRelation *AddRelation
+	SetConfig   *ServiceSet
+	Expose      *ServiceExpose
+}
+
+// ApplyChanges holds the ordered operations of an ApplyChanges call.
+type ApplyChanges struct {
+	Changes []EnvironmentChange
+}
+
+// ApplyChangeResult holds the result of a single operation of an
+// ApplyChanges call: the units added by an add-service or add-units
+// change, the endpoints of an add-relation change, or the error that
+// prevented the change from being applied.
+type ApplyChangeResult struct {
+	Units     []string
+	Endpoints map[string]charm.Relation
+	Error     *Error
+}
+
+// ApplyChangesResults holds the results of an ApplyChanges call, one
+// for each change, in order.
+type ApplyChangesResults struct {
+	Results []ApplyChangeResult
+}
+
 // DestroyServiceUnits holds parameters for the DestroyUnits call.
 type DestroyServiceUnits struct {
 	UnitNames []string
@@ -418,6 +510,16 @@ type ResolveCharmResults struct {
 	URLs []ResolveCharmResult
 }
 
+// WatchAll holds the parameters for the WatchAll call. The filter
+// selects the deltas sent by the AllWatcher; the zero filter selects
+// all of them. Filtering by service does not restrict the entities
+// that belong to no service: all machines and blocks, and the
+// annotations of machines and of the environment, are still sent
+// unless they are excluded by kind.
+type WatchAll struct {
+	Filter multiwatcher.Filter
+}
+
 // AllWatcherId holds the id of an AllWatcher.
 type AllWatcherId struct {
 	AllWatcherId string
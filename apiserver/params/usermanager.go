@@ -15,6 +15,7 @@ type UserInfo struct {
 	DateCreated    time.Time  `json:"date-created"`
 	LastConnection *time.Time `json:"last-connection,omitempty"`
 	Disabled       bool       `json:"disabled"`
+	LockedUntil    *time.Time `json:"locked-until,omitempty"`
 }
 
 // UserInfoResult holds the result of a UserInfo call.
@@ -57,3 +58,95 @@ type AddUserResult struct {
 	Tag   string `json:"tag,omitempty"`
 	Error *Error `json:"error,omitempty"`
 }
+
+// UserToken holds information on an API token issued to a user. It
+// never holds the token's secret.
+type UserToken struct {
+	Id          string    `json:"id"`
+	Description string    `json:"description,omitempty"`
+	Created     time.Time `json:"created"`
+	Expires     time.Time `json:"expires"`
+	Scope       []string  `json:"scope,omitempty"`
+}
+
+// CreateUserTokens holds the parameters for issuing API tokens.
+type CreateUserTokens struct {
+	Tokens []CreateUserToken `json:"tokens"`
+}
+
+// CreateUserToken holds the parameters for issuing an API token to the
+// user with the given tag.
+type CreateUserToken struct {
+	Tag         string    `json:"tag"`
+	Description string    `json:"description,omitempty"`
+	Expires     time.Time `json:"expires"`
+	Scope       []string  `json:"scope,omitempty"`
+}
+
+// CreateUserTokenResults holds the results of the bulk CreateTokens API
+// call.
+type CreateUserTokenResults struct {
+	Results []CreateUserTokenResult `json:"results"`
+}
+
+// CreateUserTokenResult holds a newly issued API token and the
+// credentials to log in with it, or an error.
+type CreateUserTokenResult struct {
+	Token       *UserToken `json:"token,omitempty"`
+	Credentials string     `json:"credentials,omitempty"`
+	Error       *Error     `json:"error,omitempty"`
+}
+
+// UserTokensResults holds the results of the bulk UserTokens API call.
+type UserTokensResults struct {
+	Results []UserTokensResult `json:"results"`
+}
+
+// UserTokensResult holds the API tokens issued to a user, or an error.
+type UserTokensResult struct {
+	Tokens []UserToken `json:"tokens,omitempty"`
+	Error  *Error      `json:"error,omitempty"`
+}
+
+// RevokeUserTokens holds the parameters for revoking API tokens.
+type RevokeUserTokens struct {
+	Tokens []RevokeUserToken `json:"tokens"`
+}
+
+// RevokeUserToken identifies an API token of the user with the given
+// tag.
+type RevokeUserToken struct {
+	Tag string `json:"tag"`
+	Id  string `json:"id"`
+}
+
+// PermissionGrants holds the parameters for granting or revoking
+// permissions.
+type PermissionGrants struct {
+	Grants []PermissionGrant `json:"grants"`
+}
+
+// PermissionGrant identifies a permission of the user with the given
+// tag, or of the members of the given group: a role, or a facade or
+// facade method, optionally restricted to the entities of a service.
+type PermissionGrant struct {
+	UserTag   string `json:"user-tag,omitempty"`
+	Group     string `json:"group,omitempty"`
+	Operation string `json:"operation"`
+	Service   string `json:"service,omitempty"`
+}
+
+// PermissionChecks holds the parameters for checking whether users may
+// make API calls.
+type PermissionChecks struct {
+	Checks []PermissionCheck `json:"checks"`
+}
+
+// PermissionCheck identifies a call to a facade method, as
+// "Facade.Method", by the user with the given tag, optionally on the
+// entities of a service.
+type PermissionCheck struct {
+	UserTag   string `json:"user-tag"`
+	Operation string `json:"operation"`
+	Service   string `json:"service,omitempty"`
+}
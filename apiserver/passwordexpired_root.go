@@ -0,0 +1,37 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"errors"
+
+	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/rpc/rpcreflect"
+)
+
+// passwordExpiredRoot restricts API calls to those needed to change
+// the password of a user whose password has expired.
+type passwordExpiredRoot struct {
+	rpc.MethodFinder
+}
+
+// newPasswordExpiredRoot returns a new passwordExpiredRoot.
+func newPasswordExpiredRoot(finder rpc.MethodFinder) *passwordExpiredRoot {
+	return &passwordExpiredRoot{finder}
+}
+
+var passwordExpiredError = errors.New(`password expired - change it with "juju user change-password"`)
+
+// FindMethod returns passwordExpiredError for all API calls except
+// UserManager.SetPassword and those of the Pinger facade.
+func (r *passwordExpiredRoot) FindMethod(rootName string, version int, methodName string) (rpcreflect.MethodCaller, error) {
+	caller, err := r.MethodFinder.FindMethod(rootName, version, methodName)
+	if err != nil {
+		return nil, err
+	}
+	if rootName == "Pinger" || (rootName == "UserManager" && methodName == "SetPassword") {
+		return caller, nil
+	}
+	return nil, passwordExpiredError
+}
@@ -0,0 +1,56 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver"
+	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/testing"
+)
+
+type passwordExpiredRootSuite struct {
+	testing.BaseSuite
+
+	root rpc.MethodFinder
+}
+
+var _ = gc.Suite(&passwordExpiredRootSuite{})
+
+func (r *passwordExpiredRootSuite) SetUpTest(c *gc.C) {
+	r.BaseSuite.SetUpTest(c)
+	r.root = apiserver.TestingPasswordExpiredRoot(nil)
+}
+
+func (r *passwordExpiredRootSuite) TestFindAllowedMethod(c *gc.C) {
+	for _, method := range []struct {
+		rootName   string
+		methodName string
+	}{
+		{"UserManager", "SetPassword"},
+		{"Pinger", "Ping"},
+	} {
+		caller, err := r.root.FindMethod(method.rootName, 0, method.methodName)
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(caller, gc.NotNil)
+	}
+}
+
+func (r *passwordExpiredRootSuite) TestFindDisallowedMethod(c *gc.C) {
+	caller, err := r.root.FindMethod("Client", 0, "FullStatus")
+	c.Assert(err, gc.ErrorMatches, `password expired - change it with "juju user change-password"`)
+	c.Assert(caller, gc.IsNil)
+
+	caller, err = r.root.FindMethod("UserManager", 0, "AddUser")
+	c.Assert(err, gc.ErrorMatches, `password expired - .*`)
+	c.Assert(caller, gc.IsNil)
+}
+
+func (r *passwordExpiredRootSuite) TestFindNonExistentMethod(c *gc.C) {
+	caller, err := r.root.FindMethod("Client", 0, "Bar")
+	c.Assert(err, gc.ErrorMatches, `no such request - method Client\(0\).Bar is not implemented`)
+	c.Assert(caller, gc.IsNil)
+}
@@ -0,0 +1,209 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"github.com/juju/utils/set"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/rpc/rpcreflect"
+	"github.com/juju/juju/state"
+)
+
+// permissionRoot restricts API calls to those allowed by the
+// permissions granted to the user logged in.
+type permissionRoot struct {
+	rpc.MethodFinder
+	permissions func() (*state.UserPermissions, error)
+}
+
+// newPermissionRoot returns a new permissionRoot enforcing the
+// permissions returned by the given function, which is called before
+// each call so that permissions granted or revoked apply to open
+// connections.
+func newPermissionRoot(finder rpc.MethodFinder, permissions func() (*state.UserPermissions, error)) *permissionRoot {
+	return &permissionRoot{finder, permissions}
+}
+
+// userPermissions returns a function that returns the current
+// permissions of the user with the given tag.
+func userPermissions(st *state.State, tag names.UserTag) func() (*state.UserPermissions, error) {
+	return func() (*state.UserPermissions, error) {
+		user, err := st.User(tag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return st.UserPermissions(user)
+	}
+}
+
+// FindMethod returns a permission denied error if the user's
+// permissions do not allow the method. If the method is only allowed
+// on the entities of some services, the returned caller checks the
+// entities identified by the arguments of each call. As with API
+// tokens, the Pinger and watcher facades are always allowed; so is
+// UserManager.SetPassword.
+func (r *permissionRoot) FindMethod(rootName string, version int, methodName string) (rpcreflect.MethodCaller, error) {
+	caller, err := r.MethodFinder.FindMethod(rootName, version, methodName)
+	if err != nil {
+		return nil, err
+	}
+	if isAlwaysAllowedFacade(rootName) {
+		return caller, nil
+	}
+	if rootName == "UserManager" && methodName == "SetPassword" {
+		// Users can always change their own password.
+		return caller, nil
+	}
+	permissions, err := r.permissions()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if permissions.Allows(rootName, methodName, nil) {
+		return caller, nil
+	}
+	if permissions.AllowsForSomeService(rootName, methodName) {
+		return &permissionCaller{
+			MethodCaller: caller,
+			permissions:  permissions,
+			rootName:     rootName,
+			methodName:   methodName,
+		}, nil
+	}
+	logger.Debugf("%s.%s not permitted", rootName, methodName)
+	return nil, common.ErrPerm
+}
+
+// permissionCaller checks that the arguments of each call only identify
+// entities of services the user's permissions allow the method on.
+type permissionCaller struct {
+	rpcreflect.MethodCaller
+	permissions *state.UserPermissions
+	rootName    string
+	methodName  string
+}
+
+// Call implements rpcreflect.MethodCaller.
+func (c *permissionCaller) Call(objId string, arg reflect.Value) (reflect.Value, error) {
+	services, ok := callServices(arg)
+	if !ok || !c.permissions.Allows(c.rootName, c.methodName, services) {
+		logger.Debugf("%s.%s not permitted on services %v", c.rootName, c.methodName, services)
+		return reflect.Value{}, common.ErrPerm
+	}
+	return c.MethodCaller.Call(objId, arg)
+}
+
+// callServices returns the names of the services whose entities are
+// identified by the arguments of an API call: by "ServiceName",
+// "UnitName", "UnitNames" and "Endpoints" fields, and by "Tag" and
+// "Receiver" fields holding service or unit tags. It returns false if
+// the arguments identify no entities, or entities other than services
+// and units, including machines named by set machine or placement
+// fields, such as "ToMachineSpec", "MachineTag" or "Placement".
+func callServices(arg reflect.Value) ([]string, bool) {
+	services := make(set.Strings)
+	if !collectServices(arg, "", services) || services.IsEmpty() {
+		return nil, false
+	}
+	return services.SortedValues(), true
+}
+
+func collectServices(v reflect.Value, field string, services set.Strings) bool {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return true
+		}
+		return collectServices(v.Elem(), field, services)
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < v.NumField(); i++ {
+			if t.Field(i).PkgPath != "" {
+				// Unexported field.
+				continue
+			}
+			if isMachineField(t.Field(i).Name) {
+				if !isEmptyValue(v.Field(i)) {
+					return false
+				}
+				continue
+			}
+			if !collectServices(v.Field(i), t.Field(i).Name, services) {
+				return false
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if !collectServices(v.Index(i), field, services) {
+				return false
+			}
+		}
+	case reflect.String:
+		return addService(field, v.String(), services)
+	}
+	return true
+}
+
+// isMachineField reports whether the named argument field identifies
+// machines, either directly or as placement directives.
+func isMachineField(field string) bool {
+	return strings.Contains(field, "Machine") || strings.Contains(field, "Placement")
+}
+
+// isEmptyValue reports whether v is the zero value, or an empty slice
+// or map.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+	}
+	return reflect.DeepEqual(v.Interface(), reflect.Zero(v.Type()).Interface())
+}
+
+func addService(field, value string, services set.Strings) bool {
+	if value == "" {
+		// Optional fields, like the tag of an action yet to be
+		// enqueued, identify nothing.
+		return true
+	}
+	switch field {
+	case "ServiceName":
+		services.Add(value)
+	case "Endpoints":
+		// Relation endpoints are given as "service[:relation]".
+		services.Add(strings.SplitN(value, ":", 2)[0])
+	case "UnitName", "UnitNames":
+		service, err := names.UnitService(value)
+		if err != nil {
+			return false
+		}
+		services.Add(service)
+	case "Tag", "Receiver":
+		tag, err := names.ParseTag(value)
+		if err != nil {
+			return false
+		}
+		switch tag := tag.(type) {
+		case names.ServiceTag:
+			services.Add(tag.Id())
+		case names.UnitTag:
+			service, err := names.UnitService(tag.Id())
+			if err != nil {
+				return false
+			}
+			services.Add(service)
+		default:
+			return false
+		}
+	}
+	return true
+}
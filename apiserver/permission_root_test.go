@@ -0,0 +1,171 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	"reflect"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/instance"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
+)
+
+type permissionRootSuite struct {
+	jujutesting.JujuConnSuite
+
+	root rpc.MethodFinder
+}
+
+var _ = gc.Suite(&permissionRootSuite{})
+
+func (s *permissionRootSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	subject := state.UserPermissionSubject(user.UserTag())
+	_, err := s.State.GrantPermission(subject, "read", "")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.GrantPermission(subject, "actions", "wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	permissions, err := s.State.UserPermissions(user)
+	c.Assert(err, jc.ErrorIsNil)
+	s.root = apiserver.TestingPermissionRoot(s.State, permissions)
+}
+
+func (s *permissionRootSuite) TestFindAllowedMethod(c *gc.C) {
+	for _, method := range []struct {
+		rootName   string
+		version    int
+		methodName string
+	}{
+		{"Client", 0, "FullStatus"},
+		{"Action", 0, "ListAll"},
+		{"Action", 0, "Enqueue"},
+		{"Pinger", 0, "Ping"},
+		{"AllWatcher", 0, "Next"},
+		{"UserManager", 0, "SetPassword"},
+	} {
+		caller, err := s.root.FindMethod(method.rootName, method.version, method.methodName)
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(caller, gc.NotNil)
+	}
+}
+
+func (s *permissionRootSuite) TestFindDisallowedMethod(c *gc.C) {
+	caller, err := s.root.FindMethod("Client", 0, "ServiceDestroy")
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	c.Assert(caller, gc.IsNil)
+
+	caller, err = s.root.FindMethod("UserManager", 0, "AddUser")
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	c.Assert(caller, gc.IsNil)
+}
+
+func (s *permissionRootSuite) TestPermissionsChangedWhileConnected(c *gc.C) {
+	alice := s.Factory.MakeUser(c, &factory.UserParams{Name: "alice"})
+	subject := state.UserPermissionSubject(alice.UserTag())
+	root := apiserver.TestingUserPermissionRoot(s.State, alice.UserTag())
+	_, err := root.FindMethod("Client", 0, "ServiceDestroy")
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.State.GrantPermission(subject, "read", "")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = root.FindMethod("Client", 0, "ServiceDestroy")
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	_, err = root.FindMethod("Client", 0, "FullStatus")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.RevokePermission(subject, "read", "")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = root.FindMethod("Client", 0, "FullStatus")
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *permissionRootSuite) TestCallOnService(c *gc.C) {
+	caller, err := s.root.FindMethod("Action", 0, "Enqueue")
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.Actions{Actions: []params.Action{{
+		Receiver: "unit-mysql-0",
+		Name:     "backup",
+	}}}
+	_, err = caller.Call("", reflect.ValueOf(args))
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+
+	args = params.Actions{}
+	_, err = caller.Call("", reflect.ValueOf(args))
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *permissionRootSuite) TestCallServices(c *gc.C) {
+	for i, test := range []struct {
+		arg      interface{}
+		services []string
+		ok       bool
+	}{{
+		arg: params.Actions{Actions: []params.Action{
+			{Receiver: "unit-wordpress-0"},
+			{Receiver: "unit-wordpress-1"},
+		}},
+		services: []string{"wordpress"},
+		ok:       true,
+	}, {
+		arg:      params.ServiceDestroy{ServiceName: "mysql"},
+		services: []string{"mysql"},
+		ok:       true,
+	}, {
+		arg:      params.DestroyServiceUnits{UnitNames: []string{"mysql/0", "wordpress/1"}},
+		services: []string{"mysql", "wordpress"},
+		ok:       true,
+	}, {
+		arg: params.ApplyChanges{Changes: []params.EnvironmentChange{{
+			Kind:        params.ChangeAddRelation,
+			AddRelation: &params.AddRelation{Endpoints: []string{"wordpress:db", "mysql"}},
+		}, {
+			Kind:   params.ChangeExpose,
+			Expose: &params.ServiceExpose{ServiceName: "wordpress"},
+		}}},
+		services: []string{"mysql", "wordpress"},
+		ok:       true,
+	}, {
+		arg:      params.AddServiceUnits{ServiceName: "mysql", NumUnits: 1},
+		services: []string{"mysql"},
+		ok:       true,
+	}, {
+		arg: params.AddServiceUnits{ServiceName: "mysql", NumUnits: 1, ToMachineSpec: "0"},
+	}, {
+		arg: params.AddServiceUnits{
+			ServiceName: "mysql",
+			NumUnits:    1,
+			Placement:   []*instance.Placement{{Scope: "lxc", Directive: "0"}},
+		},
+	}, {
+		arg: params.ApplyChanges{Changes: []params.EnvironmentChange{{
+			Kind:     params.ChangeAddUnits,
+			AddUnits: &params.AddServiceUnits{ServiceName: "wordpress", NumUnits: 1, ToMachineSpec: "1"},
+		}}},
+	}, {
+		arg: params.Entities{Entities: []params.Entity{
+			{Tag: "service-mysql"},
+			{Tag: "machine-0"},
+		}},
+	}, {
+		arg: params.Entities{},
+	}, {
+		arg: params.Actions{Actions: []params.Action{
+			{Receiver: "invalid"},
+		}},
+	}} {
+		c.Logf("test %d: %#v", i, test.arg)
+		services, ok := apiserver.CallServices(test.arg)
+		c.Check(ok, gc.Equals, test.ok)
+		c.Check(services, jc.DeepEquals, test.services)
+	}
+}
@@ -356,6 +356,17 @@ func (p *ProvisionerAPI) MachinesWithTransientErrors() (params.StatusResults, er
 	return results, nil
 }
 
+// EnsureMachinePools adds spare machines to the environment's machine
+// pools, and destroys surplus spares, so that each pool holds as many
+// spare machines as its size. Only the environment manager may call it.
+func (p *ProvisionerAPI) EnsureMachinePools() error {
+	if !p.authorizer.AuthEnvironManager() {
+		return common.ErrPerm
+	}
+	_, err := p.st.EnsureMachinePools()
+	return errors.Trace(err)
+}
+
 // Series returns the deployed series for each given machine entity.
 func (p *ProvisionerAPI) Series(args params.Entities) (params.StringResults, error) {
 	result := params.StringResults{
@@ -431,14 +442,24 @@ func (p *ProvisionerAPI) getProvisioningInfo(m *state.Machine) (*params.Provisio
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	subnetsToZones, err := p.machineSubnetsToZones(cons)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	excludeZones, err := m.PlacementExcludedZones()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 	return &params.ProvisioningInfo{
-		Constraints: cons,
-		Series:      m.Series(),
-		Placement:   m.Placement(),
-		Networks:    networks,
-		Jobs:        jobs,
-		Volumes:     volumes,
-		Tags:        tags,
+		Constraints:    cons,
+		Series:         m.Series(),
+		Placement:      m.Placement(),
+		Networks:       networks,
+		Jobs:           jobs,
+		Volumes:        volumes,
+		Tags:           tags,
+		SubnetsToZones: subnetsToZones,
+		ExcludeZones:   excludeZones,
 	}, nil
 }
 
@@ -1319,6 +1340,61 @@ func (p *ProvisionerAPI) createOrFetchStateSubnet(subnetInfo network.SubnetInfo)
 	return subnet, nil
 }
 
+// machineSubnetsToZones returns the subnets of the spaces included in
+// the given constraints or, if only excluded spaces are given, every
+// subnet not in an excluded space (including subnets in no space),
+// keyed by provider id (or CIDR, if the subnet has no provider id),
+// with the availability zones each subnet is in. The provider starts
+// the instance in one of those subnets.
+func (p *ProvisionerAPI) machineSubnetsToZones(cons constraints.Value) (map[string][]string, error) {
+	var subnets []*state.Subnet
+	if spaceNames := cons.IncludeSpaces(); len(spaceNames) > 0 {
+		for _, spaceName := range spaceNames {
+			space, err := p.st.Space(spaceName)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			spaceSubnets, err := space.Subnets()
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			if len(spaceSubnets) == 0 {
+				return nil, errors.Errorf("cannot use space %q as deployment target: no subnets", spaceName)
+			}
+			subnets = append(subnets, spaceSubnets...)
+		}
+	} else if excluded := cons.ExcludeSpaces(); len(excluded) > 0 {
+		allSubnets, err := p.st.AllSubnets()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		excludedSet := set.NewStrings(excluded...)
+		for _, subnet := range allSubnets {
+			if !excludedSet.Contains(subnet.SpaceName()) {
+				subnets = append(subnets, subnet)
+			}
+		}
+		if len(subnets) == 0 {
+			return nil, errors.Errorf("cannot exclude spaces %v: no subnets outside them", excluded)
+		}
+	} else {
+		return nil, nil
+	}
+	subnetsToZones := make(map[string][]string)
+	for _, subnet := range subnets {
+		id := subnet.ProviderId()
+		if id == "" {
+			id = subnet.CIDR()
+		}
+		var zones []string
+		if zone := subnet.AvailabilityZone(); zone != "" {
+			zones = []string{zone}
+		}
+		subnetsToZones[id] = zones
+	}
+	return subnetsToZones, nil
+}
+
 // machineTags returns machine-specific tags to set on the instance.
 func (p *ProvisionerAPI) machineTags(m *state.Machine, jobs []multiwatcher.MachineJob) (map[string]string, error) {
 	// Names of all units deployed to the machine.
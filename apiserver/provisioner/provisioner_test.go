@@ -414,6 +414,28 @@ func (s *withoutStateServerSuite) TestMachinesWithTransientErrorsPermission(c *g
 	})
 }
 
+func (s *withoutStateServerSuite) TestEnsureMachinePools(c *gc.C) {
+	err := s.State.SetMachinePool(state.MachinePool{Name: "web", Size: 2, Series: "quantal"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.provisioner.EnsureMachinePools()
+	c.Assert(err, jc.ErrorIsNil)
+	spares, err := s.State.SpareMachines("web")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(spares, gc.HasLen, 2)
+}
+
+func (s *withoutStateServerSuite) TestEnsureMachinePoolsPermission(c *gc.C) {
+	anAuthorizer := s.authorizer
+	anAuthorizer.EnvironManager = false
+	anAuthorizer.Tag = names.NewMachineTag("1")
+	aProvisioner, err := provisioner.NewProvisionerAPI(s.State, s.resources, anAuthorizer)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = aProvisioner.EnsureMachinePools()
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
 func (s *withoutStateServerSuite) TestEnsureDead(c *gc.C) {
 	err := s.machines[1].EnsureDead()
 	c.Assert(err, jc.ErrorIsNil)
@@ -893,6 +915,103 @@ func (s *withoutStateServerSuite) TestStorageProviderFallbackToType(c *gc.C) {
 	})
 }
 
+func (s *withoutStateServerSuite) TestProvisioningInfoWithSpaces(c *gc.C) {
+	_, err := s.State.AddSubnet(state.SubnetInfo{
+		CIDR:             "10.0.1.0/24",
+		ProviderId:       "subnet-1",
+		AvailabilityZone: "zone1",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddSpace("internal", []string{"10.0.1.0/24", "10.0.2.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddSpace("empty", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	template := state.MachineTemplate{
+		Series:      "quantal",
+		Jobs:        []state.MachineJob{state.JobHostUnits},
+		Constraints: constraints.MustParse("spaces=internal,^dmz"),
+	}
+	machine, err := s.State.AddOneMachine(template)
+	c.Assert(err, jc.ErrorIsNil)
+	template.Constraints = constraints.MustParse("spaces=empty")
+	emptySpaceMachine, err := s.State.AddOneMachine(template)
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: machine.Tag().String()},
+		{Tag: emptySpaceMachine.Tag().String()},
+	}}
+	result, err := s.provisioner.ProvisioningInfo(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 2)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[0].Result.SubnetsToZones, jc.DeepEquals, map[string][]string{
+		"subnet-1":    {"zone1"},
+		"10.0.2.0/24": nil,
+	})
+	c.Assert(result.Results[1].Error, gc.ErrorMatches, `cannot use space "empty" as deployment target: no subnets`)
+}
+
+func (s *withoutStateServerSuite) TestProvisioningInfoWithExcludedSpaces(c *gc.C) {
+	_, err := s.State.AddSubnet(state.SubnetInfo{
+		CIDR:             "10.0.1.0/24",
+		ProviderId:       "subnet-1",
+		AvailabilityZone: "zone1",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddSubnet(state.SubnetInfo{
+		CIDR:             "10.0.2.0/24",
+		ProviderId:       "subnet-2",
+		AvailabilityZone: "zone2",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddSpace("internal", []string{"10.0.1.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddSpace("dmz", []string{"10.0.2.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	template := state.MachineTemplate{
+		Series:      "quantal",
+		Jobs:        []state.MachineJob{state.JobHostUnits},
+		Constraints: constraints.MustParse("spaces=^dmz"),
+	}
+	machine, err := s.State.AddOneMachine(template)
+	c.Assert(err, jc.ErrorIsNil)
+	template.Constraints = constraints.MustParse("spaces=^dmz,^internal")
+	excludedMachine, err := s.State.AddOneMachine(template)
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: machine.Tag().String()},
+		{Tag: excludedMachine.Tag().String()},
+	}}
+	result, err := s.provisioner.ProvisioningInfo(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 2)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[0].Result.SubnetsToZones, jc.DeepEquals, map[string][]string{
+		"subnet-1": {"zone1"},
+	})
+	c.Assert(result.Results[1].Error, gc.ErrorMatches, `cannot exclude spaces \[dmz internal\]: no subnets outside them`)
+
+	// Subnets in no space are not excluded.
+	_, err = s.State.AddSubnet(state.SubnetInfo{CIDR: "10.0.3.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+	result, err = s.provisioner.ProvisioningInfo(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 2)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[0].Result.SubnetsToZones, jc.DeepEquals, map[string][]string{
+		"subnet-1":    {"zone1"},
+		"10.0.3.0/24": nil,
+	})
+	c.Assert(result.Results[1].Error, gc.IsNil)
+	c.Assert(result.Results[1].Result.SubnetsToZones, jc.DeepEquals, map[string][]string{
+		"10.0.3.0/24": nil,
+	})
+}
+
 func (s *withoutStateServerSuite) TestProvisioningInfoPermissions(c *gc.C) {
 	// Login as a machine agent for machine 0.
 	anAuthorizer := s.authorizer
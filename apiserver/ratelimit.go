@@ -0,0 +1,112 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"sync"
+	"time"
+
+	"github.com/juju/juju/rpc"
+)
+
+// apiRateWindow is the period the API rate limits are given for; a
+// limit of n allows n calls each window.
+const apiRateWindow = time.Minute
+
+// apiRateLimiter limits the rate of the API calls made by each user in
+// each class of facade methods, across all of the user's connections.
+// With a limit of n, calls are spaced a minute/n apart on average, but
+// bursts of up to n calls are allowed after a quiet period.
+type apiRateLimiter struct {
+	mu sync.Mutex
+	// next holds, for each user and class, the time from which the
+	// full burst of calls is available again.
+	next      map[rateKey]time.Time
+	lastSweep time.Time
+}
+
+type rateKey struct {
+	user  string
+	class string
+}
+
+func newAPIRateLimiter() *apiRateLimiter {
+	return &apiRateLimiter{
+		next: make(map[rateKey]time.Time),
+	}
+}
+
+// allow records a call by the given user in the given class at the
+// given time, and reports whether the call is within the limit of the
+// class. If it is not, allow also returns how long the user should wait
+// before the next call in the class is allowed.
+func (l *apiRateLimiter) allow(user, class string, limit int, now time.Time) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if now.Sub(l.lastSweep) > apiRateWindow {
+		// Forget the users and classes whose calls have all been
+		// paid for, so that the map does not grow without bound.
+		for key, next := range l.next {
+			if next.Before(now) {
+				delete(l.next, key)
+			}
+		}
+		l.lastSweep = now
+	}
+	interval := apiRateWindow / time.Duration(limit)
+	key := rateKey{user, class}
+	next := l.next[key]
+	if next.Before(now) {
+		next = now
+	}
+	if wait := next.Sub(now) - (apiRateWindow - interval); wait > 0 {
+		return wait, false
+	}
+	l.next[key] = next.Add(interval)
+	return 0, true
+}
+
+// forUser returns an rpc.RateLimiter that limits the calls of the given
+// user to the given limits, keyed by method class as returned by
+// state.APIRateLimits.
+func (l *apiRateLimiter) forUser(user string, limits map[string]int) rpc.RateLimiter {
+	return &userRateLimiter{
+		limiter: l,
+		user:    user,
+		limits:  limits,
+	}
+}
+
+// userRateLimiter implements rpc.RateLimiter for the connections of a
+// user.
+type userRateLimiter struct {
+	limiter *apiRateLimiter
+	user    string
+	limits  map[string]int
+}
+
+// Allow implements rpc.RateLimiter. As they keep connections alive,
+// calls to the Pinger and watcher facades are never limited.
+func (u *userRateLimiter) Allow(req rpc.Request) (time.Duration, bool) {
+	if isAlwaysAllowedFacade(req.Type) {
+		return 0, true
+	}
+	class, limit := methodClass(u.limits, req.Type, req.Action)
+	if limit == 0 {
+		return 0, true
+	}
+	return u.limiter.allow(u.user, class, limit, time.Now())
+}
+
+// methodClass returns the most specific class of the given facade
+// method that has a limit, "Facade.Method", "Facade" or "*", and its
+// limit. A limit of zero means calls in the class are not limited.
+func methodClass(limits map[string]int, facade, method string) (string, int) {
+	for _, class := range []string{facade + "." + method, facade, "*"} {
+		if limit, ok := limits[class]; ok {
+			return class, limit
+		}
+	}
+	return "", 0
+}
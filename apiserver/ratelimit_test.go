@@ -0,0 +1,82 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver"
+	"github.com/juju/juju/testing"
+)
+
+type apiRateLimiterSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&apiRateLimiterSuite{})
+
+func (s *apiRateLimiterSuite) TestAllow(c *gc.C) {
+	start := time.Date(2015, 6, 1, 12, 0, 0, 0, time.UTC)
+	at := func(seconds ...int) []time.Time {
+		times := make([]time.Time, len(seconds))
+		for i, s := range seconds {
+			times[i] = start.Add(time.Duration(s) * time.Second)
+		}
+		return times
+	}
+	const allowed = -1
+	for i, test := range []struct {
+		limit      int
+		times      []time.Time
+		retryAfter []time.Duration
+	}{{
+		limit:      2,
+		times:      at(0, 0, 0),
+		retryAfter: []time.Duration{allowed, allowed, 30 * time.Second},
+	}, {
+		// Calls are refilled at the limit each minute.
+		limit:      2,
+		times:      at(0, 0, 10, 30, 40, 60),
+		retryAfter: []time.Duration{allowed, allowed, 20 * time.Second, allowed, 20 * time.Second, allowed},
+	}, {
+		// Unused calls accumulate up to the limit.
+		limit:      1,
+		times:      at(0, 600, 600),
+		retryAfter: []time.Duration{allowed, allowed, time.Minute},
+	}} {
+		c.Logf("test %d", i)
+		c.Check(apiserver.APIRateLimiterRetryAfter(test.limit, test.times), jc.DeepEquals, test.retryAfter)
+	}
+}
+
+func (s *apiRateLimiterSuite) TestMethodClass(c *gc.C) {
+	limits := map[string]int{
+		"Client.FullStatus": 30,
+		"Client.Status":     0,
+		"Action":            120,
+		"*":                 600,
+	}
+	for i, test := range []struct {
+		facade string
+		method string
+		class  string
+		limit  int
+	}{
+		{"Client", "FullStatus", "Client.FullStatus", 30},
+		{"Client", "Status", "Client.Status", 0},
+		{"Client", "ServiceDeploy", "*", 600},
+		{"Action", "Enqueue", "Action", 120},
+	} {
+		c.Logf("test %d: %s.%s", i, test.facade, test.method)
+		class, limit := apiserver.MethodClass(limits, test.facade, test.method)
+		c.Check(class, gc.Equals, test.class)
+		c.Check(limit, gc.Equals, test.limit)
+	}
+	class, limit := apiserver.MethodClass(map[string]int{"Client": 10}, "Action", "Enqueue")
+	c.Check(class, gc.Equals, "")
+	c.Check(limit, gc.Equals, 0)
+}
@@ -4,6 +4,5 @@
 package service
 
 var (
-	ParseSettingsCompatible = parseSettingsCompatible
-	NewStateStorage         = &newStateStorage
+	NewStateStorage = &newStateStorage
 )
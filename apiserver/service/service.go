@@ -145,7 +145,7 @@ func DeployService(st *state.State, owner string, args params.ServiceDeploy) err
 		settings, err = ch.Config().ParseSettingsYAML([]byte(args.ConfigYAML), args.ServiceName)
 	} else if len(args.Config) > 0 {
 		// Parse config in a compatible way (see function comment).
-		settings, err = parseSettingsCompatible(ch, args.Config)
+		settings, err = ParseSettingsCompatible(ch, args.Config)
 	}
 	if err != nil {
 		return errors.Trace(err)
@@ -169,6 +169,8 @@ func DeployService(st *state.State, owner string, args params.ServiceDeploy) err
 			Placement:      args.Placement,
 			Networks:       requestedNetworks,
 			Storage:        args.Storage,
+
+			EndpointBindings: args.EndpointBindings,
 		})
 	return err
 }
@@ -181,7 +183,7 @@ func ServiceSetSettingsStrings(service *state.Service, settings map[string]strin
 		return err
 	}
 	// Parse config in a compatible way (see function comment).
-	changes, err := parseSettingsCompatible(ch, settings)
+	changes, err := ParseSettingsCompatible(ch, settings)
 	if err != nil {
 		return err
 	}
@@ -200,13 +202,13 @@ func networkTagsToNames(tags []string) ([]string, error) {
 	return netNames, nil
 }
 
-// parseSettingsCompatible parses setting strings in a way that is
+// ParseSettingsCompatible parses setting strings in a way that is
 // compatible with the behavior before this CL based on the issue
 // http://pad.lv/1194945. Until then setting an option to an empty
 // string caused it to reset to the default value. We now allow
 // empty strings as actual values, but we want to preserve the API
 // behavior.
-func parseSettingsCompatible(ch *state.Charm, settings map[string]string) (charm.Settings, error) {
+func ParseSettingsCompatible(ch *state.Charm, settings map[string]string) (charm.Settings, error) {
 	setSettings := map[string]string{}
 	unsetSettings := charm.Settings{}
 	// Split settings into those which set and those which unset a value.
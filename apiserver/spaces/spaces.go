@@ -0,0 +1,102 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package spaces
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+func init() {
+	common.RegisterStandardFacade("Spaces", 1, NewSpacesAPI)
+}
+
+// Spaces defines the methods on the spaces API end point.
+type Spaces interface {
+	CreateSpaces(args params.SpacesSubnets) (params.ErrorResults, error)
+	ListSpaces() (params.ListSpacesResults, error)
+	AddSubnetsToSpace(args params.SpacesSubnets) (params.ErrorResults, error)
+}
+
+// SpacesAPI implements the Spaces interface and is the concrete
+// implementation of the api end point.
+type SpacesAPI struct {
+	st         *state.State
+	authorizer common.Authorizer
+	check      *common.BlockChecker
+}
+
+var _ Spaces = (*SpacesAPI)(nil)
+
+// NewSpacesAPI creates a new server-side spaces API end point.
+func NewSpacesAPI(st *state.State, resources *common.Resources, authorizer common.Authorizer) (*SpacesAPI, error) {
+	// Only clients can access the spaces service.
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	return &SpacesAPI{
+		st:         st,
+		authorizer: authorizer,
+		check:      common.NewBlockChecker(st),
+	}, nil
+}
+
+// CreateSpaces creates the given spaces, adding the given subnets to
+// each of them.
+func (api *SpacesAPI) CreateSpaces(args params.SpacesSubnets) (params.ErrorResults, error) {
+	if err := api.check.ChangeAllowed(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Spaces)),
+	}
+	for i, arg := range args.Spaces {
+		_, err := api.st.AddSpace(arg.Name, arg.CIDRs)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+// ListSpaces returns all the spaces in the environment, with the
+// subnets in each of them.
+func (api *SpacesAPI) ListSpaces() (params.ListSpacesResults, error) {
+	var results params.ListSpacesResults
+	spaces, err := api.st.AllSpaces()
+	if err != nil {
+		return results, errors.Trace(err)
+	}
+	results.Results = make([]params.SpaceSubnets, len(spaces))
+	for i, space := range spaces {
+		cidrs, err := space.CIDRs()
+		if err != nil {
+			return params.ListSpacesResults{}, errors.Trace(err)
+		}
+		results.Results[i] = params.SpaceSubnets{
+			Name:  space.Name(),
+			CIDRs: cidrs,
+		}
+	}
+	return results, nil
+}
+
+// AddSubnetsToSpace adds the given subnets to existing spaces.
+func (api *SpacesAPI) AddSubnetsToSpace(args params.SpacesSubnets) (params.ErrorResults, error) {
+	if err := api.check.ChangeAllowed(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Spaces)),
+	}
+	for i, arg := range args.Spaces {
+		space, err := api.st.Space(arg.Name)
+		if err == nil {
+			err = space.AddSubnets(arg.CIDRs)
+		}
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
@@ -0,0 +1,96 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package spaces_test
+
+import (
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	commontesting "github.com/juju/juju/apiserver/common/testing"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/apiserver/spaces"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	jujutesting "github.com/juju/juju/juju/testing"
+)
+
+type spacesSuite struct {
+	jujutesting.JujuConnSuite
+
+	spaces     *spaces.SpacesAPI
+	resources  *common.Resources
+	authoriser apiservertesting.FakeAuthorizer
+
+	commontesting.BlockHelper
+}
+
+var _ = gc.Suite(&spacesSuite{})
+
+func (s *spacesSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.resources = common.NewResources()
+	s.AddCleanup(func(_ *gc.C) { s.resources.StopAll() })
+
+	s.authoriser = apiservertesting.FakeAuthorizer{
+		Tag: s.AdminUserTag(c),
+	}
+	var err error
+	s.spaces, err = spaces.NewSpacesAPI(s.State, s.resources, s.authoriser)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.BlockHelper = commontesting.NewBlockHelper(s.APIState)
+	s.AddCleanup(func(*gc.C) { s.BlockHelper.Close() })
+}
+
+func (s *spacesSuite) TestNewSpacesAPIRefusesNonClient(c *gc.C) {
+	anAuthoriser := s.authoriser
+	anAuthoriser.Tag = names.NewUnitTag("mysql/0")
+	endPoint, err := spaces.NewSpacesAPI(s.State, s.resources, anAuthoriser)
+	c.Assert(endPoint, gc.IsNil)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *spacesSuite) TestCreateListAndAddSubnets(c *gc.C) {
+	results, err := s.spaces.CreateSpaces(params.SpacesSubnets{
+		Spaces: []params.SpaceSubnets{
+			{Name: "internal", CIDRs: []string{"10.0.0.0/24"}},
+			{Name: "dmz"},
+			{Name: "Bad"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 3)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.IsNil)
+	c.Assert(results.Results[2].Error, gc.ErrorMatches, `cannot add space "Bad": space name "Bad" not valid`)
+
+	results, err = s.spaces.AddSubnetsToSpace(params.SpacesSubnets{
+		Spaces: []params.SpaceSubnets{
+			{Name: "dmz", CIDRs: []string{"192.168.0.0/24"}},
+			{Name: "dmz", CIDRs: []string{"10.0.0.0/24"}},
+			{Name: "missing", CIDRs: []string{"10.0.1.0/24"}},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 3)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, `.*subnet "10.0.0.0/24" already in space "internal"`)
+	c.Assert(results.Results[2].Error, gc.ErrorMatches, `space "missing" not found`)
+
+	list, err := s.spaces.ListSpaces()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(list.Results, jc.DeepEquals, []params.SpaceSubnets{
+		{Name: "dmz", CIDRs: []string{"192.168.0.0/24"}},
+		{Name: "internal", CIDRs: []string{"10.0.0.0/24"}},
+	})
+}
+
+func (s *spacesSuite) TestBlockCreateSpaces(c *gc.C) {
+	s.BlockAllChanges(c, "TestBlockCreateSpaces")
+	_, err := s.spaces.CreateSpaces(params.SpacesSubnets{
+		Spaces: []params.SpaceSubnets{{Name: "internal"}},
+	})
+	s.AssertBlocked(c, err, "TestBlockCreateSpaces")
+}
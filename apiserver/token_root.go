@@ -0,0 +1,90 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/set"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/rpc/rpcreflect"
+	"github.com/juju/juju/state"
+)
+
+// errTokenInvalid is returned for calls made on a connection logged in
+// with an API token that has since expired or been revoked.
+var errTokenInvalid = errors.New("API token expired or revoked")
+
+// tokenRoot restricts API calls to the facades and methods in the scope
+// of the API token a user logged in with, for as long as the token
+// remains valid.
+type tokenRoot struct {
+	rpc.MethodFinder
+	scope set.Strings
+	check func() error
+}
+
+// newTokenRoot returns a new tokenRoot allowing calls to the given
+// scope, which holds "Facade" or "Facade.Method" entries; an empty
+// scope allows all calls. Before each call, check is called to
+// ensure the token is still valid.
+func newTokenRoot(finder rpc.MethodFinder, scope []string, check func() error) *tokenRoot {
+	return &tokenRoot{finder, set.NewStrings(scope...), check}
+}
+
+// userTokenCheck returns a function that returns errTokenInvalid if
+// the API token with the given id has expired or been revoked.
+func userTokenCheck(st *state.State, id string) func() error {
+	return func() error {
+		token, err := st.UserToken(id)
+		if errors.IsNotFound(err) {
+			return errTokenInvalid
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+		if !token.Expires().After(time.Now()) {
+			return errTokenInvalid
+		}
+		return nil
+	}
+}
+
+// isAlwaysAllowedFacade reports whether calls to the named facade are
+// exempt from API token scopes, user permissions and rate limits: the
+// Pinger facade, needed to keep connections alive, and the watcher
+// facades, which can only be used with watchers started by calls that
+// were themselves allowed.
+func isAlwaysAllowedFacade(rootName string) bool {
+	return rootName == "Pinger" || strings.HasSuffix(rootName, "Watcher")
+}
+
+// FindMethod returns a permission denied error if the method is not in
+// the scope of the token; see isAlwaysAllowedFacade for the facades
+// that are always allowed. No
+// token may be used to create further tokens, which could outlive it.
+func (r *tokenRoot) FindMethod(rootName string, version int, methodName string) (rpcreflect.MethodCaller, error) {
+	caller, err := r.MethodFinder.FindMethod(rootName, version, methodName)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.check(); err != nil {
+		return nil, err
+	}
+	if rootName == "UserManager" && methodName == "CreateTokens" {
+		logger.Debugf("%s.%s not allowed with an API token", rootName, methodName)
+		return nil, common.ErrPerm
+	}
+	if isAlwaysAllowedFacade(rootName) {
+		return caller, nil
+	}
+	if r.scope.IsEmpty() || r.scope.Contains(rootName) || r.scope.Contains(rootName+"."+methodName) {
+		return caller, nil
+	}
+	logger.Debugf("%s.%s not in token scope %v", rootName, methodName, r.scope.SortedValues())
+	return nil, common.ErrPerm
+}
@@ -0,0 +1,85 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	"errors"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver"
+	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/testing"
+)
+
+type tokenRootSuite struct {
+	testing.BaseSuite
+
+	root rpc.MethodFinder
+}
+
+var _ = gc.Suite(&tokenRootSuite{})
+
+func (r *tokenRootSuite) SetUpTest(c *gc.C) {
+	r.BaseSuite.SetUpTest(c)
+	r.root = apiserver.TestingTokenRoot(nil, []string{"Client.FullStatus", "Charms"}, validToken)
+}
+
+func validToken() error {
+	return nil
+}
+
+func (r *tokenRootSuite) TestFindAllowedMethod(c *gc.C) {
+	for _, method := range []struct {
+		rootName   string
+		version    int
+		methodName string
+	}{
+		{"Client", 0, "FullStatus"},
+		{"Charms", 1, "List"},
+		{"Pinger", 0, "Ping"},
+		{"AllWatcher", 0, "Next"},
+	} {
+		caller, err := r.root.FindMethod(method.rootName, method.version, method.methodName)
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(caller, gc.NotNil)
+	}
+}
+
+func (r *tokenRootSuite) TestFindDisallowedMethod(c *gc.C) {
+	caller, err := r.root.FindMethod("Client", 0, "DestroyEnvironment")
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	c.Assert(caller, gc.IsNil)
+
+	caller, err = r.root.FindMethod("UserManager", 0, "AddUser")
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	c.Assert(caller, gc.IsNil)
+}
+
+func (r *tokenRootSuite) TestFindCreateTokensUnscoped(c *gc.C) {
+	root := apiserver.TestingTokenRoot(nil, nil, validToken)
+	caller, err := root.FindMethod("Client", 0, "DestroyEnvironment")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(caller, gc.NotNil)
+
+	caller, err = root.FindMethod("UserManager", 0, "CreateTokens")
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	c.Assert(caller, gc.IsNil)
+}
+
+func (r *tokenRootSuite) TestFindMethodTokenInvalid(c *gc.C) {
+	root := apiserver.TestingTokenRoot(nil, nil, func() error {
+		return errors.New("API token expired or revoked")
+	})
+	caller, err := root.FindMethod("Pinger", 0, "Ping")
+	c.Assert(err, gc.ErrorMatches, "API token expired or revoked")
+	c.Assert(caller, gc.IsNil)
+}
+
+func (r *tokenRootSuite) TestFindNonExistentMethod(c *gc.C) {
+	caller, err := r.root.FindMethod("Client", 0, "Bar")
+	c.Assert(err, gc.ErrorMatches, `no such request - method Client\(0\).Bar is not implemented`)
+	c.Assert(caller, gc.IsNil)
+}
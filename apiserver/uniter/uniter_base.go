@@ -404,7 +404,8 @@ func (u *uniterBaseAPI) HasSubordinates(args params.Entities) (params.BoolResult
 	return result, nil
 }
 
-// CharmURL returns the charm URL for all given units or services.
+// CharmURL returns the charm URL for all given units or services. For a
+// service, the charm URL is the one the authenticated unit should run.
 func (u *uniterBaseAPI) CharmURL(args params.Entities) (params.StringBoolResults, error) {
 	result := params.StringBoolResults{
 		Results: make([]params.StringBoolResult, len(args.Entities)),
@@ -429,6 +430,14 @@ func (u *uniterBaseAPI) CharmURL(args params.Entities) (params.StringBoolResults
 					CharmURL() (*charm.URL, bool)
 				})
 				curl, ok := charmURLer.CharmURL()
+				// While a charm rollout is in progress, a unit is
+				// told the charm it should run, rather than the
+				// service's charm.
+				service, isService := unitOrService.(*state.Service)
+				unitTag, isUnit := u.auth.GetAuthTag().(names.UnitTag)
+				if isService && isUnit {
+					curl, ok = service.UnitCharmURL(unitTag.Id())
+				}
 				if curl != nil {
 					result.Results[i].Result = curl.String()
 					result.Results[i].Ok = ok
@@ -0,0 +1,326 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// The uniter package implements the API interface used by the uniter
+// worker. This file contains the API facade version 3.
+
+package uniter
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/watcher"
+)
+
+func init() {
+	common.RegisterStandardFacade("Uniter", 3, NewUniterAPIV3)
+}
+
+// UniterAPIV3 implements the API version 3, used by the uniter worker.
+type UniterAPIV3 struct {
+	UniterAPIV2
+}
+
+// NewUniterAPIV3 creates a new instance of the Uniter API, version 3.
+func NewUniterAPIV3(st *state.State, resources *common.Resources, authorizer common.Authorizer) (*UniterAPIV3, error) {
+	baseAPI, err := NewUniterAPIV2(st, resources, authorizer)
+	if err != nil {
+		return nil, err
+	}
+	return &UniterAPIV3{
+		UniterAPIV2: *baseAPI,
+	}, nil
+}
+
+// RecordHookExecutions records the timings and outcomes of hooks,
+// actions and commands executed by the specified units.
+func (u *UniterAPIV3) RecordHookExecutions(args params.RecordHookExecutions) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Units)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	for i, arg := range args.Units {
+		tag, err := names.ParseUnitTag(arg.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		if !canAccess(tag) {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		unit, err := u.getUnit(tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		for _, execution := range arg.Executions {
+			err = unit.RecordHookExecution(state.HookExecution{
+				Kind:      state.HookExecutionKind(execution.Kind),
+				Name:      execution.Name,
+				Started:   execution.Started,
+				Finished:  execution.Finished,
+				ExitCode:  execution.ExitCode,
+				ToolCalls: execution.ToolCalls,
+			})
+			if err != nil {
+				break
+			}
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// HookRetryPolicy returns the policy used to retry failed hooks for
+// each given unit, as set on the unit's service.
+func (u *UniterAPIV3) HookRetryPolicy(args params.Entities) (params.HookRetryPolicyResults, error) {
+	result := params.HookRetryPolicyResults{
+		Results: make([]params.HookRetryPolicyResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.HookRetryPolicyResults{}, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseUnitTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		if !canAccess(tag) {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		unit, err := u.getUnit(tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		service, err := unit.Service()
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		policy := service.HookRetryPolicy()
+		result.Results[i].Result = params.HookRetryPolicy{
+			MaxAttempts: policy.MaxAttempts,
+			Delay:       policy.Delay,
+			MaxDelay:    policy.MaxDelay,
+		}
+	}
+	return result, nil
+}
+
+// ScheduleEvents schedules deferred events for the specified units.
+func (u *UniterAPIV3) ScheduleEvents(args params.ScheduleEvents) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Events)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	for i, arg := range args.Events {
+		unit, err := u.accessibleUnit(canAccess, arg.Tag)
+		if err == nil {
+			err = unit.ScheduleEvent(state.ScheduledEvent{
+				Name: arg.Event.Name,
+				Due:  arg.Event.Due,
+				Cron: arg.Event.Cron,
+			})
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// CancelScheduledEvents cancels the specified units' scheduled events.
+func (u *UniterAPIV3) CancelScheduledEvents(args params.UnitScheduledEventNames) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Names)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	for i, arg := range args.Names {
+		unit, err := u.accessibleUnit(canAccess, arg.Tag)
+		if err == nil {
+			err = unit.CancelScheduledEvent(arg.Name)
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// FireScheduledEvents records that the specified scheduled events,
+// due at the given times, have been delivered to their units. Events
+// rescheduled since are left alone.
+func (u *UniterAPIV3) FireScheduledEvents(args params.ScheduleEvents) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Events)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	for i, arg := range args.Events {
+		unit, err := u.accessibleUnit(canAccess, arg.Tag)
+		if err == nil {
+			err = unit.FireScheduledEvent(arg.Event.Name, arg.Event.Due)
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// ScheduledEvents returns the deferred events scheduled by each
+// given unit, ordered by due time.
+func (u *UniterAPIV3) ScheduledEvents(args params.Entities) (params.ScheduledEventsResults, error) {
+	result := params.ScheduledEventsResults{
+		Results: make([]params.ScheduledEventsResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.ScheduledEventsResults{}, err
+	}
+	for i, entity := range args.Entities {
+		unit, err := u.accessibleUnit(canAccess, entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		events, err := unit.ScheduledEvents()
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		for _, event := range events {
+			result.Results[i].Events = append(result.Results[i].Events, params.ScheduledEvent{
+				Name: event.Name,
+				Due:  event.Due,
+				Cron: event.Cron,
+			})
+		}
+	}
+	return result, nil
+}
+
+// WatchScheduledEvents returns a NotifyWatcher for observing changes
+// to each given unit's scheduled events.
+func (u *UniterAPIV3) WatchScheduledEvents(args params.Entities) (params.NotifyWatchResults, error) {
+	result := params.NotifyWatchResults{
+		Results: make([]params.NotifyWatchResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.NotifyWatchResults{}, err
+	}
+	for i, entity := range args.Entities {
+		unit, err := u.accessibleUnit(canAccess, entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		watch := unit.WatchScheduledEvents()
+		if _, ok := <-watch.Changes(); ok {
+			result.Results[i].NotifyWatcherId = u.resources.Register(watch)
+		} else {
+			result.Results[i].Error = common.ServerError(watcher.EnsureErr(watch))
+		}
+	}
+	return result, nil
+}
+
+// DebugHooksSessions returns the debug-hooks session opened on each
+// given unit through the API, if it has one.
+func (u *UniterAPIV3) DebugHooksSessions(args params.Entities) (params.DebugHooksSessionResults, error) {
+	result := params.DebugHooksSessionResults{
+		Results: make([]params.DebugHooksSessionResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.DebugHooksSessionResults{}, err
+	}
+	for i, entity := range args.Entities {
+		unit, err := u.accessibleUnit(canAccess, entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		session, err := unit.DebugHooksSession()
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		result.Results[i].Session = &params.UnitDebugHooksSession{
+			Hooks:       session.Hooks,
+			Actions:     session.Actions,
+			RelationIds: session.RelationIds,
+			Expires:     session.Expires,
+		}
+	}
+	return result, nil
+}
+
+// NetworkInfo returns the network interfaces and addresses each given
+// unit uses for the given endpoint.
+func (u *UniterAPIV3) NetworkInfo(args params.UnitEndpoints) (params.NetworkInfoResults, error) {
+	result := params.NetworkInfoResults{
+		Results: make([]params.NetworkInfoResult, len(args.Endpoints)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.NetworkInfoResults{}, err
+	}
+	for i, arg := range args.Endpoints {
+		unit, err := u.accessibleUnit(canAccess, arg.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		interfaces, err := unit.NetworkInfo(arg.Endpoint)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		info := make([]params.NetworkInfo, len(interfaces))
+		for j, iface := range interfaces {
+			info[j] = params.NetworkInfo{
+				InterfaceName: iface.InterfaceName,
+				MACAddress:    iface.MACAddress,
+			}
+			for _, addr := range iface.Addresses {
+				info[j].Addresses = append(info[j].Addresses, params.NetworkInfoAddress{
+					Address: addr.Value,
+					CIDR:    addr.CIDR,
+				})
+			}
+		}
+		result.Results[i].Info = info
+	}
+	return result, nil
+}
+
+// accessibleUnit returns the unit with the given tag, or ErrPerm if
+// the tag is not a unit tag or the unit cannot be accessed.
+func (u *UniterAPIV3) accessibleUnit(canAccess common.AuthFunc, tagString string) (*state.Unit, error) {
+	tag, err := names.ParseUnitTag(tagString)
+	if err != nil {
+		return nil, common.ErrPerm
+	}
+	if !canAccess(tag) {
+		return nil, common.ErrPerm
+	}
+	return u.getUnit(tag)
+}
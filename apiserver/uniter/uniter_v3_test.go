@@ -0,0 +1,340 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uniter_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/apiserver/uniter"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+	jujuFactory "github.com/juju/juju/testing/factory"
+)
+
+type uniterV3Suite struct {
+	uniterBaseSuite
+	uniter *uniter.UniterAPIV3
+}
+
+var _ = gc.Suite(&uniterV3Suite{})
+
+func (s *uniterV3Suite) SetUpTest(c *gc.C) {
+	s.uniterBaseSuite.setUpTest(c)
+
+	uniterAPIV3, err := uniter.NewUniterAPIV3(
+		s.State,
+		s.resources,
+		s.authorizer,
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	s.uniter = uniterAPIV3
+}
+
+func (s *uniterV3Suite) TestRecordHookExecutions(c *gc.C) {
+	started := time.Date(2015, 7, 1, 12, 0, 0, 0, time.UTC)
+	execution := params.HookExecution{
+		Kind:      "hook",
+		Name:      "install",
+		Started:   started,
+		Finished:  started.Add(time.Second),
+		ExitCode:  0,
+		ToolCalls: 2,
+	}
+	args := params.RecordHookExecutions{
+		Units: []params.UnitHookExecutions{
+			{Tag: "unit-mysql-0", Executions: []params.HookExecution{execution}},
+			{Tag: "unit-wordpress-0", Executions: []params.HookExecution{execution}},
+			{Tag: "unit-wordpress-0", Executions: []params.HookExecution{{Kind: "bogus"}}},
+			{Tag: "service-wordpress", Executions: []params.HookExecution{execution}},
+		},
+	}
+	result, err := s.uniter.RecordHookExecutions(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{apiservertesting.ErrUnauthorized},
+			{nil},
+			{&params.Error{
+				Message: `cannot record hook execution for unit "wordpress/0": hook execution kind "bogus" not valid`,
+			}},
+			{apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	executions, err := s.wordpressUnit.HookExecutions(10)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(executions, jc.DeepEquals, []state.HookExecution{{
+		Kind:      state.HookExecutionHook,
+		Name:      "install",
+		Started:   started,
+		Finished:  started.Add(time.Second),
+		ExitCode:  0,
+		ToolCalls: 2,
+	}})
+	executions, err = s.mysqlUnit.HookExecutions(10)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(executions, gc.HasLen, 0)
+}
+
+func (s *uniterV3Suite) TestHookRetryPolicy(c *gc.C) {
+	policy := state.HookRetryPolicy{
+		MaxAttempts: 3,
+		Delay:       time.Second,
+		MaxDelay:    time.Minute,
+	}
+	err := s.wordpress.SetHookRetryPolicy(policy)
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: "unit-mysql-0"},
+		{Tag: "unit-wordpress-0"},
+		{Tag: "service-wordpress"},
+	}}
+	result, err := s.uniter.HookRetryPolicy(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.HookRetryPolicyResults{
+		Results: []params.HookRetryPolicyResult{
+			{Error: apiservertesting.ErrUnauthorized},
+			{Result: params.HookRetryPolicy{
+				MaxAttempts: 3,
+				Delay:       time.Second,
+				MaxDelay:    time.Minute,
+			}},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+}
+
+func (s *uniterV3Suite) TestCharmURLDuringRollout(c *gc.C) {
+	otherUnit := s.Factory.MakeUnit(c, &jujuFactory.UnitParams{
+		Service: s.wordpress,
+		Machine: s.machine1,
+	})
+	newCharm := s.Factory.MakeCharm(c, &jujuFactory.CharmParams{
+		Name: "wordpress",
+		URL:  "cs:quantal/wordpress-4",
+	})
+	// Only wordpress/0 is upgraded at first.
+	err := s.wordpress.SetCharmInBatches(newCharm, false, 1, 1)
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.Entities{Entities: []params.Entity{{Tag: "service-wordpress"}}}
+	result, err := s.uniter.CharmURL(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.DeepEquals, []params.StringBoolResult{
+		{Result: newCharm.String()},
+	})
+
+	authorizer := s.authorizer
+	authorizer.Tag = otherUnit.Tag()
+	otherUniter, err := uniter.NewUniterAPIV3(s.State, s.resources, authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	result, err = otherUniter.CharmURL(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.DeepEquals, []params.StringBoolResult{
+		{Result: s.wpCharm.String()},
+	})
+}
+
+func (s *uniterV3Suite) TestScheduleEvents(c *gc.C) {
+	due := time.Date(2015, 7, 1, 12, 0, 0, 0, time.UTC)
+	event := params.ScheduledEvent{Name: "nightly", Due: due, Cron: "0 3 * * *"}
+	args := params.ScheduleEvents{
+		Events: []params.UnitScheduledEvent{
+			{Tag: "unit-mysql-0", Event: event},
+			{Tag: "unit-wordpress-0", Event: event},
+			{Tag: "unit-wordpress-0", Event: params.ScheduledEvent{Name: "Bad", Due: due}},
+			{Tag: "service-wordpress", Event: event},
+		},
+	}
+	result, err := s.uniter.ScheduleEvents(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{apiservertesting.ErrUnauthorized},
+			{nil},
+			{&params.Error{
+				Message: `cannot schedule event for unit "wordpress/0": scheduled event name "Bad" not valid`,
+			}},
+			{apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	events, err := s.wordpressUnit.ScheduledEvents()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(events, jc.DeepEquals, []state.ScheduledEvent{{
+		Name: "nightly",
+		Due:  due,
+		Cron: "0 3 * * *",
+	}})
+
+	eventsResult, err := s.uniter.ScheduledEvents(params.Entities{Entities: []params.Entity{
+		{Tag: "unit-mysql-0"},
+		{Tag: "unit-wordpress-0"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(eventsResult, jc.DeepEquals, params.ScheduledEventsResults{
+		Results: []params.ScheduledEventsResult{
+			{Error: apiservertesting.ErrUnauthorized},
+			{Events: []params.ScheduledEvent{event}},
+		},
+	})
+}
+
+func (s *uniterV3Suite) TestCancelAndFireScheduledEvents(c *gc.C) {
+	due := time.Date(2015, 7, 1, 12, 0, 0, 0, time.UTC)
+	for _, name := range []string{"first", "second"} {
+		err := s.wordpressUnit.ScheduleEvent(state.ScheduledEvent{Name: name, Due: due})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	args := params.UnitScheduledEventNames{
+		Names: []params.UnitScheduledEventName{
+			{Tag: "unit-mysql-0", Name: "first"},
+			{Tag: "unit-wordpress-0", Name: "first"},
+			{Tag: "unit-wordpress-0", Name: "missing"},
+		},
+	}
+	result, err := s.uniter.CancelScheduledEvents(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{apiservertesting.ErrUnauthorized},
+			{nil},
+			{&params.Error{
+				Message: `cannot cancel scheduled event "missing" for unit "wordpress/0": scheduled event "missing" not found`,
+				Code:    params.CodeNotFound,
+			}},
+		},
+	})
+
+	err = s.wordpressUnit.ScheduleEvent(state.ScheduledEvent{Name: "third", Due: due.Add(time.Hour)})
+	c.Assert(err, jc.ErrorIsNil)
+	fireArgs := params.ScheduleEvents{
+		Events: []params.UnitScheduledEvent{
+			{Tag: "unit-mysql-0", Event: params.ScheduledEvent{Name: "second", Due: due}},
+			{Tag: "unit-wordpress-0", Event: params.ScheduledEvent{Name: "second", Due: due}},
+			{Tag: "unit-wordpress-0", Event: params.ScheduledEvent{Name: "third", Due: due}},
+			{Tag: "unit-wordpress-0", Event: params.ScheduledEvent{Name: "missing", Due: due}},
+		},
+	}
+	result, err = s.uniter.FireScheduledEvents(fireArgs)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results[0].Error, gc.DeepEquals, apiservertesting.ErrUnauthorized)
+	c.Assert(result.Results[1].Error, gc.IsNil)
+	c.Assert(result.Results[2].Error, gc.IsNil)
+	c.Assert(result.Results[3].Error, jc.Satisfies, params.IsCodeNotFound)
+
+	// The third event was due at a different time, so it is kept.
+	events, err := s.wordpressUnit.ScheduledEvents()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(events, gc.HasLen, 1)
+	c.Assert(events[0].Name, gc.Equals, "third")
+}
+
+func (s *uniterV3Suite) TestWatchScheduledEvents(c *gc.C) {
+	c.Assert(s.resources.Count(), gc.Equals, 0)
+
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: "unit-mysql-0"},
+		{Tag: "unit-wordpress-0"},
+		{Tag: "unit-foo-42"},
+	}}
+	result, err := s.uniter.WatchScheduledEvents(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.NotifyWatchResults{
+		Results: []params.NotifyWatchResult{
+			{Error: apiservertesting.ErrUnauthorized},
+			{NotifyWatcherId: "1"},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	// Verify the resource was registered and stop when done
+	c.Assert(s.resources.Count(), gc.Equals, 1)
+	resource := s.resources.Get("1")
+	defer statetesting.AssertStop(c, resource)
+
+	// Check that the Watch has consumed the initial event ("returned" in
+	// the Watch call)
+	wc := statetesting.NewNotifyWatcherC(c, s.State, resource.(state.NotifyWatcher))
+	wc.AssertNoChange()
+
+	err = s.wordpressUnit.ScheduleEvent(state.ScheduledEvent{Name: "later", Due: time.Now()})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+}
+
+func (s *uniterV3Suite) TestDebugHooksSessions(c *gc.C) {
+	expires := time.Now().Add(time.Minute).Round(time.Second).UTC()
+	err := s.wordpressUnit.SetDebugHooksSession(state.DebugHooksSession{
+		Hooks:   []string{"install"},
+		Expires: expires,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := s.uniter.DebugHooksSessions(params.Entities{Entities: []params.Entity{
+		{Tag: "unit-mysql-0"},
+		{Tag: "unit-wordpress-0"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.DebugHooksSessionResults{
+		Results: []params.DebugHooksSessionResult{
+			{Error: apiservertesting.ErrUnauthorized},
+			{Session: &params.UnitDebugHooksSession{
+				Hooks:   []string{"install"},
+				Expires: expires,
+			}},
+		},
+	})
+
+	// A unit without a session has no session and no error.
+	err = s.wordpressUnit.ClearDebugHooksSession()
+	c.Assert(err, jc.ErrorIsNil)
+	result, err = s.uniter.DebugHooksSessions(params.Entities{Entities: []params.Entity{
+		{Tag: "unit-wordpress-0"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.DebugHooksSessionResults{
+		Results: []params.DebugHooksSessionResult{{}},
+	})
+}
+
+func (s *uniterV3Suite) TestNetworkInfo(c *gc.C) {
+	_, err := s.State.AddSpace("internal", []string{"192.168.0.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.wordpress.SetEndpointBindings(map[string]string{"db": "internal"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine0.SetProviderAddresses(
+		network.NewScopedAddress("10.0.0.5", network.ScopeCloudLocal),
+		network.NewScopedAddress("192.168.0.5", network.ScopeCloudLocal),
+	)
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.UnitEndpoints{Endpoints: []params.UnitEndpoint{
+		{Tag: "unit-mysql-0", Endpoint: "server"},
+		{Tag: "unit-wordpress-0", Endpoint: "db"},
+		{Tag: "unit-wordpress-0", Endpoint: "missing"},
+	}}
+	result, err := s.uniter.NetworkInfo(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.NetworkInfoResults{
+		Results: []params.NetworkInfoResult{
+			{Error: apiservertesting.ErrUnauthorized},
+			{Info: []params.NetworkInfo{{
+				Addresses: []params.NetworkInfoAddress{{
+					Address: "192.168.0.5",
+					CIDR:    "192.168.0.0/24",
+				}},
+			}}},
+			{Error: apiservertesting.NotFoundError(`endpoint "missing" of service "wordpress"`)},
+		},
+	})
+}
@@ -91,7 +91,8 @@ func NewUpgraderAPI(
 }
 
 // WatchAPIVersion starts a watcher to track if there is a new version
-// of the API that we want to upgrade to
+// of the API that we want to upgrade to, including changes to the
+// rolling upgrade in progress.
 func (u *UpgraderAPI) WatchAPIVersion(args params.Entities) (params.NotifyWatchResults, error) {
 	result := params.NotifyWatchResults{
 		Results: make([]params.NotifyWatchResult, len(args.Entities)),
@@ -103,7 +104,7 @@ func (u *UpgraderAPI) WatchAPIVersion(args params.Entities) (params.NotifyWatchR
 		}
 		err = common.ErrPerm
 		if u.authorizer.AuthOwner(tag) {
-			watch := u.st.WatchForEnvironConfigChanges()
+			watch := u.st.WatchForAgentVersionChanges()
 			// Consume the initial event. Technically, API
 			// calls to Watch 'transmit' the initial event
 			// in the Watch response. But NotifyWatchers
@@ -149,6 +150,26 @@ func (u *UpgraderAPI) entityIsManager(tag names.Tag) bool {
 	}
 }
 
+// releasedToRollingUpgrade reports whether the agent with the given
+// tag may run the version the rolling upgrade upgrades to: its machine
+// has been released to the upgrade, or the agent already runs the new
+// version, as the agents of machines provisioned during the upgrade do.
+func (u *UpgraderAPI) releasedToRollingUpgrade(tag names.Tag, rolling state.RollingUpgrade) bool {
+	if rolling.Released(tag.Id()) {
+		return true
+	}
+	entity, err := u.st.FindEntity(tag)
+	if err != nil {
+		return false
+	}
+	tooler, ok := entity.(state.AgentTooler)
+	if !ok {
+		return false
+	}
+	tools, err := tooler.AgentTools()
+	return err == nil && tools.Version.Number == rolling.To
+}
+
 // DesiredVersion reports the Agent Version that we want that agent to be running
 func (u *UpgraderAPI) DesiredVersion(args params.Entities) (params.VersionResults, error) {
 	results := make([]params.VersionResult, len(args.Entities))
@@ -161,6 +182,12 @@ func (u *UpgraderAPI) DesiredVersion(args params.Entities) (params.VersionResult
 	}
 	// Is the desired version greater than the current API server version?
 	isNewerVersion := agentVersion.Compare(version.Current.Number) > 0
+	rolling, err := u.st.RollingUpgrade()
+	if errors.IsNotFound(err) {
+		rolling = state.RollingUpgrade{}
+	} else if err != nil {
+		return params.VersionResults{}, common.ServerError(err)
+	}
 	for i, entity := range args.Entities {
 		tag, err := names.ParseTag(entity.Tag)
 		if err != nil {
@@ -179,9 +206,18 @@ func (u *UpgraderAPI) DesiredVersion(args params.Entities) (params.VersionResult
 			// first - once they have restarted and are running the
 			// new version other agents will start to see the new
 			// agent version.
-			if !isNewerVersion || u.entityIsManager(tag) {
+			//
+			// During a rolling upgrade, machines that are not
+			// manager nodes keep the version the environment was
+			// upgraded from until they are released to the upgrade.
+			isManager := u.entityIsManager(tag)
+			switch {
+			case !isManager && rolling.To == agentVersion && !u.releasedToRollingUpgrade(tag, rolling):
+				logger.Debugf("desired version is %s, but %s is not yet released to the rolling upgrade", agentVersion, tag)
+				results[i].Version = &rolling.From
+			case !isNewerVersion || isManager:
 				results[i].Version = &agentVersion
-			} else {
+			default:
 				logger.Debugf("desired version is %s, but current version is %s and agent is not a manager node", agentVersion, version.Current.Number)
 				results[i].Version = &version.Current.Number
 			}
@@ -326,3 +326,45 @@ func (s *upgraderSuite) TestDesiredVersionRestrictedForNonAPIAgents(c *gc.C) {
 	c.Assert(agentVersion, gc.NotNil)
 	c.Check(*agentVersion, gc.DeepEquals, version.Current.Number)
 }
+
+func (s *upgraderSuite) TestDesiredVersionDuringRollingUpgrade(c *gc.C) {
+	s.apiMachine.SetAgentVersion(version.Current)
+	s.rawMachine.SetAgentVersion(version.Current)
+	oldVersion := version.Current.Number
+	newer := version.Current
+	newer.Patch++
+	err := s.State.SetEnvironAgentVersionRolling(newer.Number, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	// Pretend the API server has already been upgraded.
+	s.PatchValue(&version.Current, newer)
+
+	args := params.Entities{Entities: []params.Entity{{Tag: s.rawMachine.Tag().String()}}}
+	results, err := s.upgrader.DesiredVersion(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Check(*results.Results[0].Version, gc.Equals, oldVersion)
+
+	err = s.State.ReleaseRollingUpgradeMachines([]string{s.rawMachine.Id()})
+	c.Assert(err, jc.ErrorIsNil)
+	results, err = s.upgrader.DesiredVersion(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Check(*results.Results[0].Version, gc.Equals, newer.Number)
+}
+
+func (s *upgraderSuite) TestDesiredVersionDuringRollingUpgradeAlreadyUpgraded(c *gc.C) {
+	newer := version.Current
+	newer.Patch++
+	err := s.State.SetEnvironAgentVersionRolling(newer.Number, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	s.PatchValue(&version.Current, newer)
+
+	// A machine provisioned during the upgrade starts with the new
+	// version, and keeps it without being released.
+	s.rawMachine.SetAgentVersion(newer)
+	args := params.Entities{Entities: []params.Entity{{Tag: s.rawMachine.Tag().String()}}}
+	results, err := s.upgrader.DesiredVersion(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Check(*results.Results[0].Version, gc.Equals, newer.Number)
+}
@@ -4,6 +4,9 @@
 package usermanager
 
 import (
+	"strings"
+	"time"
+
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/names"
@@ -22,10 +25,17 @@ func init() {
 // UserManager defines the methods on the usermanager API end point.
 type UserManager interface {
 	AddUser(args params.AddUsers) (params.AddUserResults, error)
+	CheckPermissions(args params.PermissionChecks) (params.BoolResults, error)
+	ClearLockout(args params.Entities) (params.ErrorResults, error)
+	CreateTokens(args params.CreateUserTokens) (params.CreateUserTokenResults, error)
 	DisableUser(args params.Entities) (params.ErrorResults, error)
 	EnableUser(args params.Entities) (params.ErrorResults, error)
+	GrantPermissions(args params.PermissionGrants) (params.ErrorResults, error)
+	RevokePermissions(args params.PermissionGrants) (params.ErrorResults, error)
+	RevokeTokens(args params.RevokeUserTokens) (params.ErrorResults, error)
 	SetPassword(args params.EntityPasswords) (params.ErrorResults, error)
 	UserInfo(args params.UserInfoRequest) (params.UserInfoResults, error)
+	UserTokens(args params.Entities) (params.UserTokensResults, error)
 }
 
 // UserManagerAPI implements the user manager interface and is the concrete
@@ -90,7 +100,16 @@ func (api *UserManagerAPI) AddUser(args params.AddUsers) (params.AddUserResults,
 	if err := api.permissionCheck(loggedInUser); err != nil {
 		return result, errors.Trace(err)
 	}
+	policy, err := api.state.PasswordPolicy()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
 	for i, arg := range args.Users {
+		if err := policy.Check(arg.Password); err != nil {
+			err = errors.Annotate(err, "failed to create user")
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
 		user, err := api.state.AddUser(arg.Username, arg.DisplayName, arg.Password, loggedInUser.Id())
 		if err != nil {
 			err = errors.Annotate(err, "failed to create user")
@@ -132,6 +151,15 @@ func (api *UserManagerAPI) DisableUser(users params.Entities) (params.ErrorResul
 	return api.enableUserImpl(users, "disable", (*state.User).Disable)
 }
 
+// ClearLockout ends the lockouts of one or more users after too many
+// failed logins. Users that are not locked out are left as they are.
+func (api *UserManagerAPI) ClearLockout(users params.Entities) (params.ErrorResults, error) {
+	if err := api.check.ChangeAllowed(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	return api.enableUserImpl(users, "clear lockout of", (*state.User).ClearLockout)
+}
+
 func (api *UserManagerAPI) enableUserImpl(args params.Entities, action string, method func(*state.User) error) (params.ErrorResults, error) {
 	result := params.ErrorResults{
 		Results: make([]params.ErrorResult, len(args.Entities)),
@@ -175,6 +203,7 @@ func (api *UserManagerAPI) UserInfo(request params.UserInfoRequest) (params.User
 				DateCreated:    user.DateCreated(),
 				LastConnection: user.LastLogin(),
 				Disabled:       user.IsDisabled(),
+				LockedUntil:    user.LockedUntil(),
 			},
 		}
 	}
@@ -216,6 +245,13 @@ func (api *UserManagerAPI) setPassword(loggedInUser names.UserTag, arg params.En
 	if arg.Password == "" {
 		return errors.New("can not use an empty password")
 	}
+	policy, err := api.state.PasswordPolicy()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := policy.Check(arg.Password); err != nil {
+		return errors.Trace(err)
+	}
 	err = user.SetPassword(arg.Password)
 	if err != nil {
 		return errors.Annotate(err, "failed to set password")
@@ -256,3 +292,251 @@ func (api *UserManagerAPI) getLoggedInUser() (names.UserTag, error) {
 		return names.UserTag{}, errors.New("authorizer not a user")
 	}
 }
+
+// getTokenUser returns the user with the given tag, if the logged in
+// user may manage its API tokens: users manage their own tokens, and
+// the administrator manages everyone's.
+func (api *UserManagerAPI) getTokenUser(loggedInUser names.UserTag, adminUser bool, tag string) (*state.User, error) {
+	user, err := api.getUser(tag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if loggedInUser != user.UserTag() && !adminUser {
+		return nil, errors.Trace(common.ErrPerm)
+	}
+	return user, nil
+}
+
+func userTokenParams(token *state.UserToken) params.UserToken {
+	return params.UserToken{
+		Id:          token.Id(),
+		Description: token.Description(),
+		Created:     token.Created(),
+		Expires:     token.Expires(),
+		Scope:       token.Scope(),
+	}
+}
+
+// CreateTokens issues new API tokens to the specified users. Tokens
+// expire no more than state.MaxUserTokenLifetime after they are
+// created, and cannot themselves be used to call CreateTokens.
+func (api *UserManagerAPI) CreateTokens(args params.CreateUserTokens) (params.CreateUserTokenResults, error) {
+	result := params.CreateUserTokenResults{
+		Results: make([]params.CreateUserTokenResult, len(args.Tokens)),
+	}
+	if err := api.check.ChangeAllowed(); err != nil {
+		return result, errors.Trace(err)
+	}
+	if len(args.Tokens) == 0 {
+		return result, nil
+	}
+	loggedInUser, err := api.getLoggedInUser()
+	if err != nil {
+		return result, errors.Wrap(err, common.ErrPerm)
+	}
+	adminUser := api.permissionCheck(loggedInUser) == nil
+	now := time.Now()
+	for i, arg := range args.Tokens {
+		user, err := api.getTokenUser(loggedInUser, adminUser, arg.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		if !arg.Expires.After(now) {
+			result.Results[i].Error = common.ServerError(errors.New("token expiry time must be in the future"))
+			continue
+		}
+		token, credentials, err := user.AddToken(arg.Description, arg.Expires, arg.Scope)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(errors.Annotate(err, "failed to create token"))
+			continue
+		}
+		tokenParams := userTokenParams(token)
+		result.Results[i].Token = &tokenParams
+		result.Results[i].Credentials = credentials
+	}
+	return result, nil
+}
+
+// UserTokens returns the API tokens issued to the specified users.
+func (api *UserManagerAPI) UserTokens(args params.Entities) (params.UserTokensResults, error) {
+	result := params.UserTokensResults{
+		Results: make([]params.UserTokensResult, len(args.Entities)),
+	}
+	if len(args.Entities) == 0 {
+		return result, nil
+	}
+	loggedInUser, err := api.getLoggedInUser()
+	if err != nil {
+		return result, errors.Wrap(err, common.ErrPerm)
+	}
+	adminUser := api.permissionCheck(loggedInUser) == nil
+	for i, arg := range args.Entities {
+		user, err := api.getTokenUser(loggedInUser, adminUser, arg.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		tokens, err := user.Tokens()
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		for _, token := range tokens {
+			result.Results[i].Tokens = append(result.Results[i].Tokens, userTokenParams(token))
+		}
+	}
+	return result, nil
+}
+
+// RevokeTokens revokes the specified API tokens, so that they can no
+// longer be used to log in.
+func (api *UserManagerAPI) RevokeTokens(args params.RevokeUserTokens) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Tokens)),
+	}
+	if err := api.check.ChangeAllowed(); err != nil {
+		return result, errors.Trace(err)
+	}
+	if len(args.Tokens) == 0 {
+		return result, nil
+	}
+	loggedInUser, err := api.getLoggedInUser()
+	if err != nil {
+		return result, errors.Wrap(err, common.ErrPerm)
+	}
+	adminUser := api.permissionCheck(loggedInUser) == nil
+	for i, arg := range args.Tokens {
+		user, err := api.getTokenUser(loggedInUser, adminUser, arg.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		if err := user.RevokeToken(arg.Id); err != nil {
+			result.Results[i].Error = common.ServerError(err)
+		}
+	}
+	return result, nil
+}
+
+// permissionAdminCheck returns an error unless the logged in user may
+// grant and revoke permissions in the environment: the owner of the
+// environment and the administrator may.
+func (api *UserManagerAPI) permissionAdminCheck() error {
+	loggedInUser, err := api.getLoggedInUser()
+	if err != nil {
+		return errors.Wrap(err, common.ErrPerm)
+	}
+	env, err := api.state.Environment()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if loggedInUser == env.Owner() {
+		return nil
+	}
+	return api.permissionCheck(loggedInUser)
+}
+
+// permissionSubject returns the subject of the permission grant.
+func permissionSubject(arg params.PermissionGrant) (string, error) {
+	switch {
+	case arg.UserTag != "" && arg.Group != "":
+		return "", errors.New("permission granted to both a user and a group")
+	case arg.Group != "":
+		return state.GroupPermissionSubject(arg.Group), nil
+	}
+	userTag, err := names.ParseUserTag(arg.UserTag)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return state.UserPermissionSubject(userTag), nil
+}
+
+// GrantPermissions grants permissions in the environment to users and
+// groups. Once granted a permission, users are restricted to the
+// operations of the permissions granted to them and their groups.
+func (api *UserManagerAPI) GrantPermissions(args params.PermissionGrants) (params.ErrorResults, error) {
+	return api.changePermissions(args, func(subject, operation, service string) error {
+		_, err := api.state.GrantPermission(subject, operation, service)
+		return err
+	})
+}
+
+// RevokePermissions revokes permissions granted by GrantPermissions.
+func (api *UserManagerAPI) RevokePermissions(args params.PermissionGrants) (params.ErrorResults, error) {
+	return api.changePermissions(args, api.state.RevokePermission)
+}
+
+func (api *UserManagerAPI) changePermissions(args params.PermissionGrants, change func(subject, operation, service string) error) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Grants)),
+	}
+	if err := api.check.ChangeAllowed(); err != nil {
+		return result, errors.Trace(err)
+	}
+	if len(args.Grants) == 0 {
+		return result, nil
+	}
+	if err := api.permissionAdminCheck(); err != nil {
+		return result, errors.Trace(err)
+	}
+	for i, arg := range args.Grants {
+		subject, err := permissionSubject(arg)
+		if err == nil {
+			err = change(subject, arg.Operation, arg.Service)
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// CheckPermissions reports whether users may make the given API calls
+// in the environment, without making them.
+func (api *UserManagerAPI) CheckPermissions(args params.PermissionChecks) (params.BoolResults, error) {
+	result := params.BoolResults{
+		Results: make([]params.BoolResult, len(args.Checks)),
+	}
+	if len(args.Checks) == 0 {
+		return result, nil
+	}
+	if err := api.permissionAdminCheck(); err != nil {
+		return result, errors.Trace(err)
+	}
+	for i, arg := range args.Checks {
+		allowed, err := api.checkPermission(arg)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		result.Results[i].Result = allowed
+	}
+	return result, nil
+}
+
+func (api *UserManagerAPI) checkPermission(arg params.PermissionCheck) (bool, error) {
+	user, err := api.getUser(arg.UserTag)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	parts := strings.Split(arg.Operation, ".")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return false, errors.NotValidf("operation %q", arg.Operation)
+	}
+	if user.IsDisabled() {
+		return false, nil
+	}
+	if _, err := api.state.EnvironmentUser(user.UserTag()); errors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, errors.Trace(err)
+	}
+	permissions, err := api.state.UserPermissions(user)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	var services []string
+	if arg.Service != "" {
+		services = []string{arg.Service}
+	}
+	return permissions.Allows(parts[0], parts[1], services), nil
+}
@@ -4,6 +4,8 @@
 package usermanager_test
 
 import (
+	"time"
+
 	"github.com/juju/errors"
 	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
@@ -14,6 +16,7 @@ import (
 	apiservertesting "github.com/juju/juju/apiserver/testing"
 	"github.com/juju/juju/apiserver/usermanager"
 	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
 	"github.com/juju/juju/testing/factory"
 )
 
@@ -76,6 +79,42 @@ func (s *userManagerSuite) TestAddUser(c *gc.C) {
 	c.Assert(user.DisplayName(), gc.Equals, "Foo Bar")
 }
 
+func (s *userManagerSuite) TestAddUserPasswordPolicy(c *gc.C) {
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{
+		"password-min-length":  10,
+		"password-min-classes": 3,
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.AddUsers{
+		Users: []params.AddUser{{
+			Username: "short",
+			Password: "Pa55",
+		}, {
+			Username: "simple",
+			Password: "passwordpassword",
+		}, {
+			Username: "strong",
+			Password: "Passw0rdPassw0rd",
+		}}}
+	result, err := s.usermanager.AddUser(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.AddUserResults{
+		Results: []params.AddUserResult{{
+			Error: &params.Error{
+				Message: "failed to create user: password must be at least 10 characters long",
+			},
+		}, {
+			Error: &params.Error{
+				Message: "failed to create user: password must contain 3 of lower case letters, upper case letters, digits and other characters",
+			},
+		}, {
+			Tag: names.NewLocalUserTag("strong").String(),
+		}}})
+	_, err = s.State.User(names.NewLocalUserTag("short"))
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
 func (s *userManagerSuite) TestBlockAddUser(c *gc.C) {
 	args := params.AddUsers{
 		Users: []params.AddUser{{
@@ -287,6 +326,63 @@ func (s *userManagerSuite) TestEnableUserAsNormalUser(c *gc.C) {
 	c.Assert(barb.IsDisabled(), jc.IsTrue)
 }
 
+func (s *userManagerSuite) TestClearLockout(c *gc.C) {
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{"login-lockout-attempts": 1}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	alex := s.Factory.MakeUser(c, &factory.UserParams{Name: "alex"})
+	err = alex.RecordFailedLogin()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(alex.IsLockedOut(), jc.IsTrue)
+
+	args := params.Entities{
+		Entities: []params.Entity{
+			{alex.Tag().String()},
+			{names.NewLocalUserTag("ellie").String()},
+		}}
+	result, err := s.usermanager.ClearLockout(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{Error: nil},
+			{Error: &params.Error{
+				Message: "permission denied",
+				Code:    params.CodeUnauthorized,
+			}},
+		}})
+	err = alex.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(alex.IsLockedOut(), jc.IsFalse)
+}
+
+func (s *userManagerSuite) TestClearLockoutAsNormalUser(c *gc.C) {
+	alex := s.Factory.MakeUser(c, &factory.UserParams{Name: "alex"})
+	usermanager, err := usermanager.NewUserManagerAPI(
+		s.State, nil, apiservertesting.FakeAuthorizer{Tag: alex.Tag()})
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.Entities{
+		Entities: []params.Entity{{alex.Tag().String()}},
+	}
+	_, err = usermanager.ClearLockout(args)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *userManagerSuite) TestUserInfoLockedOut(c *gc.C) {
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{"login-lockout-attempts": 1}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	alex := s.Factory.MakeUser(c, &factory.UserParams{Name: "alex"})
+	err = alex.RecordFailedLogin()
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.usermanager.UserInfo(params.UserInfoRequest{
+		Entities: []params.Entity{{Tag: alex.Tag().String()}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Result, gc.NotNil)
+	c.Assert(results.Results[0].Result.LockedUntil, jc.DeepEquals, alex.LockedUntil())
+}
+
 func (s *userManagerSuite) TestUserInfo(c *gc.C) {
 	userFoo := s.Factory.MakeUser(c, &factory.UserParams{Name: "foobar", DisplayName: "Foo Bar"})
 	userBar := s.Factory.MakeUser(c, &factory.UserParams{Name: "barfoo", DisplayName: "Bar Foo", Disabled: true})
@@ -413,6 +509,26 @@ func (s *userManagerSuite) TestSetPassword(c *gc.C) {
 	c.Assert(alex.PasswordValid("new-password"), jc.IsTrue)
 }
 
+func (s *userManagerSuite) TestSetPasswordPolicy(c *gc.C) {
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{"password-min-length": 20}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	alex := s.Factory.MakeUser(c, &factory.UserParams{Name: "alex"})
+
+	args := params.EntityPasswords{
+		Changes: []params.EntityPassword{{
+			Tag:      alex.Tag().String(),
+			Password: "new-password",
+		}}}
+	results, err := s.usermanager.SetPassword(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.ErrorMatches, "password must be at least 20 characters long")
+
+	err = alex.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(alex.PasswordValid("new-password"), jc.IsFalse)
+}
+
 func (s *userManagerSuite) TestBlockSetPassword(c *gc.C) {
 	alex := s.Factory.MakeUser(c, &factory.UserParams{Name: "alex"})
 
@@ -481,3 +597,174 @@ func (s *userManagerSuite) TestSetPasswordForOther(c *gc.C) {
 
 	c.Assert(barb.PasswordValid("new-password"), jc.IsFalse)
 }
+
+func (s *userManagerSuite) TestCreateListRevokeTokens(c *gc.C) {
+	alex := s.Factory.MakeUser(c, &factory.UserParams{Name: "alex"})
+	usermanager, err := usermanager.NewUserManagerAPI(
+		s.State, nil, apiservertesting.FakeAuthorizer{Tag: alex.Tag()})
+	c.Assert(err, jc.ErrorIsNil)
+
+	expires := time.Now().Add(time.Hour).Round(time.Second).UTC()
+	results, err := usermanager.CreateTokens(params.CreateUserTokens{
+		Tokens: []params.CreateUserToken{{
+			Tag:         alex.Tag().String(),
+			Description: "ci",
+			Expires:     expires,
+			Scope:       []string{"Client"},
+		}, {
+			Tag:     alex.Tag().String(),
+			Expires: time.Now().Add(-time.Hour),
+		}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	token := results.Results[0].Token
+	c.Assert(token.Description, gc.Equals, "ci")
+	c.Assert(token.Expires, gc.DeepEquals, expires)
+	c.Assert(token.Scope, jc.DeepEquals, []string{"Client"})
+	c.Assert(results.Results[0].Credentials, jc.HasPrefix, "token:"+token.Id+":")
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, "token expiry time must be in the future")
+
+	listResults, err := usermanager.UserTokens(params.Entities{
+		Entities: []params.Entity{{Tag: alex.Tag().String()}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(listResults.Results, gc.HasLen, 1)
+	c.Assert(listResults.Results[0].Error, gc.IsNil)
+	c.Assert(listResults.Results[0].Tokens, gc.HasLen, 1)
+	c.Assert(listResults.Results[0].Tokens[0].Id, gc.Equals, token.Id)
+
+	revokeResults, err := usermanager.RevokeTokens(params.RevokeUserTokens{
+		Tokens: []params.RevokeUserToken{{
+			Tag: alex.Tag().String(),
+			Id:  token.Id,
+		}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(revokeResults.OneError(), jc.ErrorIsNil)
+	tokens, err := alex.Tokens()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(tokens, gc.HasLen, 0)
+}
+
+func (s *userManagerSuite) TestTokensForOther(c *gc.C) {
+	alex := s.Factory.MakeUser(c, &factory.UserParams{Name: "alex"})
+	barb := s.Factory.MakeUser(c, &factory.UserParams{Name: "barb"})
+	_, _, err := barb.AddToken("", time.Now().Add(time.Hour), nil)
+	c.Assert(err, jc.ErrorIsNil)
+	usermanager, err := usermanager.NewUserManagerAPI(
+		s.State, nil, apiservertesting.FakeAuthorizer{Tag: alex.Tag()})
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := usermanager.CreateTokens(params.CreateUserTokens{
+		Tokens: []params.CreateUserToken{{
+			Tag:     barb.Tag().String(),
+			Expires: time.Now().Add(time.Hour),
+		}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results[0].Error, gc.ErrorMatches, "permission denied")
+
+	listResults, err := usermanager.UserTokens(params.Entities{
+		Entities: []params.Entity{{Tag: barb.Tag().String()}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(listResults.Results[0].Error, gc.ErrorMatches, "permission denied")
+
+	// The administrator manages everyone's tokens.
+	listResults, err = s.usermanager.UserTokens(params.Entities{
+		Entities: []params.Entity{{Tag: barb.Tag().String()}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(listResults.Results[0].Error, gc.IsNil)
+	c.Assert(listResults.Results[0].Tokens, gc.HasLen, 1)
+}
+
+func (s *userManagerSuite) TestGrantRevokePermissions(c *gc.C) {
+	alex := s.Factory.MakeUser(c, &factory.UserParams{Name: "alex"})
+	args := params.PermissionGrants{
+		Grants: []params.PermissionGrant{
+			{UserTag: alex.Tag().String(), Operation: "actions", Service: "wordpress"},
+			{Group: "ops", Operation: "read"},
+			{UserTag: alex.Tag().String(), Operation: "Client.Full.Status"},
+			{UserTag: alex.Tag().String(), Group: "ops", Operation: "read"},
+		},
+	}
+	result, err := s.usermanager.GrantPermissions(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{Error: nil},
+			{Error: nil},
+			{Error: &params.Error{Message: `operation "Client.Full.Status" not valid`}},
+			{Error: &params.Error{Message: "permission granted to both a user and a group"}},
+		}})
+	permissions, err := s.State.Permissions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(permissions, gc.HasLen, 2)
+
+	args.Grants = args.Grants[:1]
+	result, err = s.usermanager.RevokePermissions(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{{Error: nil}},
+	})
+	permissions, err = s.State.Permissions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(permissions, gc.HasLen, 1)
+	c.Assert(permissions[0].Subject(), gc.Equals, "group:ops")
+}
+
+func (s *userManagerSuite) TestBlockGrantPermissions(c *gc.C) {
+	args := params.PermissionGrants{
+		Grants: []params.PermissionGrant{{Group: "ops", Operation: "read"}},
+	}
+	s.BlockAllChanges(c, "TestBlockGrantPermissions")
+	_, err := s.usermanager.GrantPermissions(args)
+	s.AssertBlocked(c, err, "TestBlockGrantPermissions")
+}
+
+func (s *userManagerSuite) TestGrantPermissionsAsNormalUser(c *gc.C) {
+	alex := s.Factory.MakeUser(c, &factory.UserParams{Name: "alex"})
+	usermanager, err := usermanager.NewUserManagerAPI(
+		s.State, nil, apiservertesting.FakeAuthorizer{Tag: alex.Tag()})
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.PermissionGrants{
+		Grants: []params.PermissionGrant{{UserTag: alex.Tag().String(), Operation: "admin"}},
+	}
+	_, err = usermanager.GrantPermissions(args)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	_, err = usermanager.CheckPermissions(params.PermissionChecks{
+		Checks: []params.PermissionCheck{{UserTag: alex.Tag().String(), Operation: "Client.FullStatus"}},
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *userManagerSuite) TestCheckPermissions(c *gc.C) {
+	alex := s.Factory.MakeUser(c, &factory.UserParams{Name: "alex"})
+	barb := s.Factory.MakeUser(c, &factory.UserParams{Name: "barb"})
+	carl := s.Factory.MakeUser(c, &factory.UserParams{Name: "carl", NoEnvUser: true})
+	_, err := s.State.GrantPermission(state.UserPermissionSubject(alex.UserTag()), "actions", "wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.PermissionChecks{
+		Checks: []params.PermissionCheck{
+			{UserTag: alex.Tag().String(), Operation: "Action.Enqueue", Service: "wordpress"},
+			{UserTag: alex.Tag().String(), Operation: "Action.Enqueue", Service: "mysql"},
+			{UserTag: alex.Tag().String(), Operation: "Client.ServiceDestroy", Service: "wordpress"},
+			{UserTag: barb.Tag().String(), Operation: "Client.ServiceDestroy", Service: "wordpress"},
+			{UserTag: carl.Tag().String(), Operation: "Client.FullStatus"},
+			{UserTag: alex.Tag().String(), Operation: "Action"},
+		},
+	}
+	result, err := s.usermanager.CheckPermissions(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.BoolResults{
+		Results: []params.BoolResult{
+			{Result: true},
+			{Result: false},
+			{Result: false},
+			{Result: true},
+			{Result: false},
+			{Error: &params.Error{Message: `operation "Action" not valid`}},
+		}})
+}
@@ -11,9 +11,11 @@ import (
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
+	"github.com/juju/utils"
 	"launchpad.net/gnuflag"
 
 	"github.com/juju/juju/api"
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/environs/configstore"
@@ -68,6 +70,15 @@ type EnvironCommand interface {
 	// with the active environment name. The environment name is guaranteed
 	// to be non-empty at entry of Init.
 	SetEnvName(envName string)
+
+	// SetTraceId is called prior to the wrapped command's Init method
+	// with the trace id to send with API requests, which is empty if
+	// none was given.
+	SetTraceId(traceId string)
+
+	// TraceId returns the trace id sent with the API requests made by
+	// the command, if any.
+	TraceId() string
 }
 
 // EnvCommandBase is a convenience type for embedding in commands
@@ -79,6 +90,11 @@ type EnvCommandBase struct {
 	// a file on disk based on the EnvName or the environemnts.yaml file.
 	envName string
 
+	// traceId holds the trace id sent with the requests made on the
+	// API connections opened by NewAPIRoot. If it is not set, one is
+	// generated when the first connection is opened.
+	traceId string
+
 	// compatVersion defines the minimum CLI version
 	// that this command should be compatible with.
 	compatVerson *int
@@ -88,6 +104,16 @@ func (c *EnvCommandBase) SetEnvName(envName string) {
 	c.envName = envName
 }
 
+// SetTraceId implements EnvironCommand.SetTraceId.
+func (c *EnvCommandBase) SetTraceId(traceId string) {
+	c.traceId = traceId
+}
+
+// TraceId implements EnvironCommand.TraceId.
+func (c *EnvCommandBase) TraceId() string {
+	return c.traceId
+}
+
 func (c *EnvCommandBase) NewAPIClient() (*api.Client, error) {
 	root, err := c.NewAPIRoot()
 	if err != nil {
@@ -103,7 +129,20 @@ func (c *EnvCommandBase) NewAPIRoot() (*api.State, error) {
 	if c.envName == "" {
 		return nil, errors.Trace(ErrNoEnvironmentSpecified)
 	}
-	return juju.NewAPIFromName(c.envName)
+	st, err := juju.NewAPIFromName(c.envName)
+	if err != nil {
+		return nil, err
+	}
+	if c.traceId == "" {
+		traceId, err := utils.NewUUID()
+		if err != nil {
+			st.Close()
+			return nil, errors.Trace(err)
+		}
+		c.traceId = traceId.String()
+	}
+	st.SetTraceId(c.traceId)
+	return st, nil
 }
 
 func (c *EnvCommandBase) Config(store configstore.Storage) (*config.Config, error) {
@@ -252,11 +291,13 @@ func Wrap(c EnvironCommand) cmd.Command {
 type environCommandWrapper struct {
 	EnvironCommand
 	envName string
+	traceId string
 }
 
 func (w *environCommandWrapper) SetFlags(f *gnuflag.FlagSet) {
 	f.StringVar(&w.envName, "e", "", "juju environment to operate in")
 	f.StringVar(&w.envName, "environment", "", "")
+	f.StringVar(&w.traceId, "trace-id", "", "trace id to send with API requests, for finding their request and reply log messages with debug-log --trace")
 	w.EnvironCommand.SetFlags(f)
 }
 
@@ -270,9 +311,21 @@ func (w *environCommandWrapper) Init(args []string) error {
 		w.envName = defaultEnv
 	}
 	w.SetEnvName(w.envName)
+	w.SetTraceId(w.traceId)
 	return w.EnvironCommand.Init(args)
 }
 
+// Run runs the wrapped command. If the command fails in an API call,
+// the trace id of the call is reported, so that the request and reply
+// log messages of the call can be found with "juju debug-log --trace".
+func (w *environCommandWrapper) Run(ctx *cmd.Context) error {
+	err := w.EnvironCommand.Run(ctx)
+	if _, ok := errors.Cause(err).(*params.Error); ok && w.TraceId() != "" {
+		ctx.Infof("trace id: %s", w.TraceId())
+	}
+	return err
+}
+
 type bootstrapContext struct {
 	*cmd.Context
 	verifyCredentials bool
@@ -14,6 +14,7 @@ import (
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/environs/configstore"
 	"github.com/juju/juju/juju/osenv"
@@ -147,6 +148,36 @@ func (s *EnvironmentCommandSuite) TestCompatVersionInvalid(c *gc.C) {
 	c.Assert(cmd.CompatVersion(), gc.Equals, 1)
 }
 
+func (s *EnvironmentCommandSuite) TestTraceIdFlag(c *gc.C) {
+	cmd, err := initTestCommand(c, "--trace-id", "a-trace")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmd.TraceId(), gc.Equals, "a-trace")
+
+	cmd, err = initTestCommand(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmd.TraceId(), gc.Equals, "")
+}
+
+func (s *EnvironmentCommandSuite) TestRunReportsTraceId(c *gc.C) {
+	apiErr := errors.Annotate(&params.Error{Message: "boom"}, "cannot fail")
+	wrapped := envcmd.Wrap(&failingCommand{err: apiErr})
+	ctx, err := testing.RunCommand(c, wrapped, "--trace-id", "a-trace")
+	c.Assert(err, gc.ErrorMatches, "cannot fail: boom")
+	c.Assert(testing.Stderr(ctx), gc.Equals, "trace id: a-trace\n")
+
+	// Errors that are not from API calls are not traced.
+	wrapped = envcmd.Wrap(&failingCommand{err: errors.New("boom")})
+	ctx, err = testing.RunCommand(c, wrapped, "--trace-id", "a-trace")
+	c.Assert(err, gc.ErrorMatches, "boom")
+	c.Assert(testing.Stderr(ctx), gc.Equals, "")
+
+	// Nor are commands that made no API calls.
+	wrapped = envcmd.Wrap(&failingCommand{err: apiErr})
+	ctx, err = testing.RunCommand(c, wrapped)
+	c.Assert(err, gc.ErrorMatches, "cannot fail: boom")
+	c.Assert(testing.Stderr(ctx), gc.Equals, "")
+}
+
 type testCommand struct {
 	envcmd.EnvCommandBase
 }
@@ -159,6 +190,19 @@ func (c *testCommand) Run(ctx *cmd.Context) error {
 	panic("should not be called")
 }
 
+type failingCommand struct {
+	envcmd.EnvCommandBase
+	err error
+}
+
+func (c *failingCommand) Info() *cmd.Info {
+	return &cmd.Info{Name: "fail"}
+}
+
+func (c *failingCommand) Run(ctx *cmd.Context) error {
+	return c.err
+}
+
 func initTestCommand(c *gc.C, args ...string) (*testCommand, error) {
 	cmd := new(testCommand)
 	wrapped := envcmd.Wrap(cmd)
@@ -15,6 +15,13 @@ var authKeysDoc = `
 "juju authorized-keys" is used to manage the ssh keys allowed to log on to
 nodes in the Juju environment.
 
+The keys of the environment owner are stored in the environment; other
+users' keys are stored on the users themselves, and are allowed to log on
+to the nodes of every environment the user has access to. The keys of a
+user are no longer allowed once the user is disabled or loses access to
+the environment. Users may manage their own keys; the environment owner
+may manage those of any user.
+
 `
 
 type AuthorizedKeysCommand struct {
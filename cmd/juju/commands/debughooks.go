@@ -6,23 +6,60 @@ package commands
 import (
 	"encoding/base64"
 	"fmt"
+	"io"
+	"os"
 	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/juju/cmd"
 	"github.com/juju/names"
+	"gopkg.in/juju/charm.v5"
 	"gopkg.in/juju/charm.v5/hooks"
+	"launchpad.net/gnuflag"
 
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/api/action"
+	"github.com/juju/juju/apiserver/params"
 	unitdebug "github.com/juju/juju/worker/uniter/runner/debug"
 )
 
 // DebugHooksCommand is responsible for launching a ssh shell on a given unit or machine.
 type DebugHooksCommand struct {
 	SSHCommand
-	hooks []string
+	hooks       []string
+	actions     []string
+	relations   []string
+	relationIds []int
+	noTmux      bool
 }
 
 const debugHooksDoc = `
 Interactively debug a hook remotely on a service unit.
+
+Hooks and actions matching the given names are intercepted and run in a
+tmux session on the unit, where they can be executed manually. Actions
+are selected with --action; "*" selects all hooks or all actions.
+
+With --relation-id, only relation hooks executing for the given
+relations are intercepted. Relations may be given by id, or in the
+"<name>:<id>" form output by relation-ids.
+
+With --no-tmux, the intercepted hooks and actions are not paused, but
+run as usual, with their output streamed to the client through the API
+server's debug log; no SSH connection to the unit is needed. The session
+ends when the command is interrupted.
+
+Examples:
+
+Debug the next backup action run on the first mysql unit:
+
+    juju debug-hooks mysql/0 --action backup
+
+Follow the db-relation-changed hook of relation 3, without tmux:
+
+    juju debug-hooks mysql/0 db-relation-changed --relation-id db:3 --no-tmux
 `
 
 func (c *DebugHooksCommand) Info() *cmd.Info {
@@ -34,6 +71,13 @@ func (c *DebugHooksCommand) Info() *cmd.Info {
 	}
 }
 
+func (c *DebugHooksCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.SSHCommand.SetFlags(f)
+	f.Var(cmd.NewAppendStringsValue(&c.actions), "action", "debug the named action")
+	f.Var(cmd.NewAppendStringsValue(&c.relations), "relation-id", "only debug relation hooks for this relation")
+	f.BoolVar(&c.noTmux, "no-tmux", false, "stream hook output instead of starting tmux")
+}
+
 func (c *DebugHooksCommand) Init(args []string) error {
 	if len(args) < 1 {
 		return fmt.Errorf("no unit name specified")
@@ -51,9 +95,38 @@ func (c *DebugHooksCommand) Init(args []string) error {
 			break
 		}
 	}
+	for _, a := range c.actions {
+		if a == "*" {
+			c.actions = []string{"*"}
+			break
+		}
+	}
+
+	c.relationIds = nil
+	for _, relation := range c.relations {
+		id, err := parseRelationId(relation)
+		if err != nil {
+			return err
+		}
+		c.relationIds = append(c.relationIds, id)
+	}
 	return nil
 }
 
+// parseRelationId parses a relation id given either as an
+// integer, or in the "<name>:<id>" form used by hook tools.
+func parseRelationId(value string) (int, error) {
+	idString := value
+	if i := strings.LastIndex(value, ":"); i != -1 {
+		idString = value[i+1:]
+	}
+	id, err := strconv.Atoi(idString)
+	if err != nil || id < 0 {
+		return 0, fmt.Errorf("invalid relation id %q", value)
+	}
+	return id, nil
+}
+
 func (c *DebugHooksCommand) validateHooks() error {
 	if len(c.hooks) == 0 {
 		return nil
@@ -91,9 +164,68 @@ func (c *DebugHooksCommand) validateHooks() error {
 	return nil
 }
 
+// debugHooksActionsAPI exposes the actions defined by a service's charm.
+type debugHooksActionsAPI interface {
+	ServiceCharmActions(params.Entity) (*charm.Actions, error)
+	Close() error
+}
+
+var getDebugHooksActionsAPI = func(c *DebugHooksCommand) (debugHooksActionsAPI, error) {
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, err
+	}
+	return action.NewClient(root), nil
+}
+
+func (c *DebugHooksCommand) validateActions() error {
+	if len(c.actions) == 0 || c.actions[0] == "*" {
+		return nil
+	}
+	service, err := names.UnitService(c.Target)
+	if err != nil {
+		return err
+	}
+	client, err := getDebugHooksActionsAPI(c)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	actions, err := client.ServiceCharmActions(params.Entity{
+		Tag: names.NewServiceTag(service).String(),
+	})
+	if err != nil {
+		return err
+	}
+	for _, name := range c.actions {
+		if _, ok := actions.ActionSpecs[name]; !ok {
+			return fmt.Errorf("unit %q does not contain action %q", c.Target, name)
+		}
+	}
+	return nil
+}
+
+// debugHooksStreamAPI opens streaming debug-hooks sessions, and follows
+// the debug log output of the hooks and actions they select.
+type debugHooksStreamAPI interface {
+	DebugLogAPI
+	OpenDebugHooksSession(params.DebugHooksSession) error
+	CloseDebugHooksSession(unitName string) error
+}
+
+var getDebugHooksStreamAPI = func(c *DebugHooksCommand) (debugHooksStreamAPI, error) {
+	return c.NewAPIClient()
+}
+
+// debugHooksRenewInterval is the interval at which a streaming session
+// is renewed; it must be well within the minute after which the API
+// server lets the session expire.
+var debugHooksRenewInterval = 20 * time.Second
+
 // Run ensures c.Target is a unit, and resolves its address,
 // and connects to it via SSH to execute the debug-hooks
-// script.
+// script. With --no-tmux, it streams the session through
+// the API instead.
 func (c *DebugHooksCommand) Run(ctx *cmd.Context) error {
 	var err error
 	c.apiClient, err = c.initAPIClient()
@@ -105,10 +237,81 @@ func (c *DebugHooksCommand) Run(ctx *cmd.Context) error {
 	if err != nil {
 		return err
 	}
+	err = c.validateActions()
+	if err != nil {
+		return err
+	}
+	if c.noTmux {
+		return c.runStream(ctx)
+	}
 	debugctx := unitdebug.NewHooksContext(c.Target)
-	script := base64.StdEncoding.EncodeToString([]byte(unitdebug.ClientScript(debugctx, c.hooks)))
+	clientScript := unitdebug.ClientScriptWithArgs(debugctx, unitdebug.ClientArgs{
+		Hooks:       c.hooks,
+		Actions:     c.actions,
+		RelationIds: c.relationIds,
+	})
+	script := base64.StdEncoding.EncodeToString([]byte(clientScript))
 	innercmd := fmt.Sprintf(`F=$(mktemp); echo %s | base64 -d > $F; . $F`, script)
 	args := []string{fmt.Sprintf("sudo /bin/bash -c '%s'", innercmd)}
 	c.Args = args
 	return c.SSHCommand.Run(ctx)
 }
+
+// runStream opens a streaming debug-hooks session on the unit through
+// the API, and copies the debug log output of the unit's hooks to
+// ctx.Stdout, renewing the session until the command is interrupted.
+func (c *DebugHooksCommand) runStream(ctx *cmd.Context) error {
+	client, err := getDebugHooksStreamAPI(c)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	debugLog, err := client.WatchDebugLog(api.DebugLogParams{
+		IncludeEntity: []string{names.NewUnitTag(c.Target).String()},
+		IncludeModule: []string{"unit." + c.Target},
+	})
+	if err != nil {
+		return err
+	}
+	defer debugLog.Close()
+
+	session := params.DebugHooksSession{
+		UnitName:    c.Target,
+		Hooks:       c.hooks,
+		Actions:     c.actions,
+		RelationIds: c.relationIds,
+	}
+	if err := client.OpenDebugHooksSession(session); err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.CloseDebugHooksSession(c.Target); err != nil {
+			logger.Warningf("cannot close debug-hooks session: %v", err)
+		}
+	}()
+	ctx.Infof("Streaming debug-hooks session for %s started", c.Target)
+
+	interrupted := make(chan os.Signal, 1)
+	ctx.InterruptNotify(interrupted)
+	defer ctx.StopInterruptNotify(interrupted)
+
+	logDone := make(chan error, 1)
+	go func() {
+		_, err := io.Copy(ctx.Stdout, debugLog)
+		logDone <- err
+	}()
+	renew := time.NewTicker(debugHooksRenewInterval)
+	defer renew.Stop()
+	for {
+		select {
+		case <-interrupted:
+			return nil
+		case err := <-logDone:
+			return err
+		case <-renew.C:
+			if err := client.OpenDebugHooksSession(session); err != nil {
+				return err
+			}
+		}
+	}
+}
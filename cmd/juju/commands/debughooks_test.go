@@ -67,6 +67,22 @@ var debugHooksTests = []struct {
 	info:  `invalid hook`,
 	args:  []string{"mysql/0", "invalid-hook"},
 	error: `unit "mysql/0" does not contain hook "invalid-hook"`,
+}, {
+	info:   `actions may be specified`,
+	args:   []string{"mysql/0", "--action", "snapshot"},
+	result: ".*\n",
+}, {
+	info:  `invalid action`,
+	args:  []string{"mysql/0", "--action", "invalid-action"},
+	error: `unit "mysql/0" does not contain action "invalid-action"`,
+}, {
+	info:   `relations may be specified by id or by name and id`,
+	args:   []string{"mysql/0", "--relation-id", "3", "--relation-id", "db:4"},
+	result: ".*\n",
+}, {
+	info:  `invalid relation id`,
+	args:  []string{"mysql/0", "--relation-id", "db:foo"},
+	error: `invalid relation id "db:foo"`,
 }}
 
 func (s *DebugHooksSuite) TestDebugHooksCommand(c *gc.C) {
@@ -89,7 +105,7 @@ func (s *DebugHooksSuite) TestDebugHooksCommand(c *gc.C) {
 
 		debugHooksCmd := &DebugHooksCommand{}
 		debugHooksCmd.proxy = true
-		err := envcmd.Wrap(debugHooksCmd).Init(t.args)
+		err := coretesting.InitCommand(envcmd.Wrap(debugHooksCmd), t.args)
 		if err == nil {
 			err = debugHooksCmd.Run(ctx)
 		}
@@ -31,6 +31,14 @@ const defaultLineCount = 10
 const debuglogDoc = `
 Stream the consolidated debug log file. This file contains the log messages
 from all nodes in the environment.
+
+When a command fails in an API call, it reports the trace id of the call.
+With --trace, only the API server's request and reply log messages of the
+command's API calls are shown; the messages logged while the requests are
+served do not carry the trace id. The API server logs failed requests with
+a trace id at the WARNING level, so they are shown with the default logging
+config, and other requests at the DEBUG level. The trace id sent by a
+command can be chosen with its --trace-id option.
 `
 
 func (c *DebugLogCommand) Info() *cmd.Info {
@@ -56,6 +64,7 @@ func (c *DebugLogCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.UintVar(&c.params.Backlog, "lines", defaultLineCount, "")
 	f.UintVar(&c.params.Limit, "limit", 0, "show at most this many lines")
 	f.BoolVar(&c.params.Replay, "replay", false, "start filtering from the start")
+	f.StringVar(&c.params.Trace, "trace", "", "only show the API request and reply log messages with this trace id")
 }
 
 func (c *DebugLogCommand) Init(args []string) error {
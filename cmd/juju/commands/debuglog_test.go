@@ -85,6 +85,12 @@ func (s *DebugLogSuite) TestArgParsing(c *gc.C) {
 				Backlog: 10,
 				Limit:   100,
 			},
+		}, {
+			args: []string{"--trace", "a-trace"},
+			expected: api.DebugLogParams{
+				Backlog: 10,
+				Trace:   "a-trace",
+			},
 		},
 	} {
 		c.Logf("test %v", i)
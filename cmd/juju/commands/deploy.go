@@ -22,6 +22,7 @@ import (
 	"github.com/juju/juju/cmd/juju/service"
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/juju/osenv"
+	"github.com/juju/juju/network"
 	"github.com/juju/juju/storage"
 )
 
@@ -43,6 +44,12 @@ type DeployCommand struct {
 	// Storage is a map of storage constraints, keyed on the storage name
 	// defined in charm storage metadata.
 	Storage map[string]storage.Constraints
+
+	// BindToSpaces holds the raw value of the --bind flag, and Bindings
+	// the endpoint bindings parsed from it. The empty endpoint name
+	// holds the service's default space.
+	BindToSpaces string
+	Bindings     map[string]string
 }
 
 const deployDoc = `
@@ -73,7 +80,9 @@ flag.  When used with deploy, service-specific constraints are set so that later
 machines provisioned with add-unit will use the same constraints (unless changed
 by set-constraints).
 
-Charms can be deployed to a specific machine using the --to argument.
+Charms can be deployed to a specific machine using the --to argument. It
+takes a comma-separated list of placement directives, applied to successive
+units; units without a directive are deployed to new machines.
 If the destination is an LXC container the default is to use lxc-clone
 to create the container where possible. For Ubuntu deployments, lxc-clone
 is supported for the trusty OS series and later. A 'template' container is
@@ -96,6 +105,10 @@ Examples:
    juju deploy mysql --to 23       (deploy to machine 23)
    juju deploy mysql --to 24/lxc/3 (deploy to lxc container 3 on host machine 24)
    juju deploy mysql --to lxc:25   (deploy to a new lxc container on host machine 25)
+   juju deploy mysql -n 3 --to lxc:3,zone=us-east-1a,kvm:new
+   (deploy one unit to a new lxc container on machine 3, one to a new
+    machine in availability zone us-east-1a and one to a new kvm container
+    on a new machine)
 
    juju deploy mysql -n 5 --constraints mem=8G
    (deploy 5 instances of mysql with at least 8 GB of RAM each)
@@ -113,10 +126,20 @@ to configure on it. The --networks argument instructs juju to add all the
 networks specified with it to all new machines deployed to host units of
 the service. Not supported on all providers.
 
+The --bind argument binds the service's endpoints to network spaces. It
+takes a space-delimited list of <endpoint>=<space> pairs; a space name on
+its own sets the default space for all endpoints not listed explicitly,
+which is also the space of the units' private address. For example:
+
+   juju deploy wordpress --bind "db=internal public"
+   (bind the "db" endpoint to the "internal" space, and all other
+    endpoints to the "public" space)
+
 See Also:
    juju help constraints
    juju help set-constraints
    juju help get-constraints
+   juju help space
 `
 
 func (c *DeployCommand) Info() *cmd.Info {
@@ -138,6 +161,7 @@ func (c *DeployCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.StringVar(&c.Networks, "networks", "", "bind the service to specific networks")
 	f.StringVar(&c.RepoPath, "repository", os.Getenv(osenv.JujuRepositoryEnvKey), "local charm repository")
 	f.Var(storageFlag{&c.Storage}, "storage", "charm storage constraints")
+	f.StringVar(&c.BindToSpaces, "bind", "", "bind service endpoints to network spaces")
 }
 
 func (c *DeployCommand) Init(args []string) error {
@@ -158,6 +182,11 @@ func (c *DeployCommand) Init(args []string) error {
 	default:
 		return cmd.CheckEmpty(args[2:])
 	}
+	bindings, err := parseBindings(c.BindToSpaces)
+	if err != nil {
+		return err
+	}
+	c.Bindings = bindings
 	return c.UnitCommandBase.Init(args)
 }
 
@@ -248,9 +277,9 @@ func (c *DeployCommand) Run(ctx *cmd.Context) error {
 		}
 	}
 
-	// If storage or placement is specified, we attempt to use a new API on the service facade.
-	if len(c.Storage) > 0 || len(c.Placement) > 0 {
-		notSupported := errors.New("cannot deploy charms with storage or placement: not supported by the API server")
+	// If storage, placement or bindings are specified, we attempt to use a new API on the service facade.
+	if len(c.Storage) > 0 || len(c.Placement) > 0 || len(c.Bindings) > 0 {
+		notSupported := errors.New("cannot deploy charms with storage, placement or bindings: not supported by the API server")
 		serviceClient, err := c.newServiceAPIClient()
 		if err != nil {
 			return notSupported
@@ -272,6 +301,7 @@ func (c *DeployCommand) Run(ctx *cmd.Context) error {
 			c.Placement,
 			requestedNetworks,
 			c.Storage,
+			c.Bindings,
 		)
 		if params.IsCodeNotImplemented(err) {
 			return notSupported
@@ -331,6 +361,38 @@ func parseNetworks(networksValue string) []string {
 	return networks
 }
 
+// parseBindings returns the endpoint bindings described by the
+// space-delimited value of the --bind argument. Each item is either
+// <endpoint>=<space>, or a lone space name setting the default space,
+// which is keyed by the empty endpoint name.
+func parseBindings(bindValue string) (map[string]string, error) {
+	fields := strings.Fields(bindValue)
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	bindings := make(map[string]string, len(fields))
+	for _, field := range fields {
+		endpoint, space := "", field
+		if i := strings.Index(field, "="); i >= 0 {
+			endpoint, space = field[:i], field[i+1:]
+			if endpoint == "" {
+				return nil, fmt.Errorf("invalid --bind value %q: missing endpoint name", field)
+			}
+		}
+		if !network.IsValidSpaceName(space) {
+			return nil, fmt.Errorf("invalid --bind value %q: %q is not a valid space name", field, space)
+		}
+		if _, ok := bindings[endpoint]; ok {
+			if endpoint == "" {
+				return nil, fmt.Errorf("invalid --bind value %q: default space specified more than once", field)
+			}
+			return nil, fmt.Errorf("invalid --bind value %q: endpoint %q bound more than once", field, endpoint)
+		}
+		bindings[endpoint] = space
+	}
+	return bindings, nil
+}
+
 // networkNamesToTags returns the given network names converted to
 // tags, or an error.
 func networkNamesToTags(networks []string) ([]string, error) {
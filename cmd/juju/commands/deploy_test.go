@@ -83,6 +83,18 @@ var initErrorTests = []struct {
 	}, {
 		args: []string{"craziness", "burble1", "--constraints", "gibber=plop"},
 		err:  `invalid value "gibber=plop" for flag --constraints: unknown constraint "gibber"`,
+	}, {
+		args: []string{"craziness", "burble1", "--bind", "db=Bad"},
+		err:  `invalid --bind value "db=Bad": "Bad" is not a valid space name`,
+	}, {
+		args: []string{"craziness", "burble1", "--bind", "=internal"},
+		err:  `invalid --bind value "=internal": missing endpoint name`,
+	}, {
+		args: []string{"craziness", "burble1", "--bind", "db=internal db=public"},
+		err:  `invalid --bind value "db=public": endpoint "db" bound more than once`,
+	}, {
+		args: []string{"craziness", "burble1", "--bind", "internal public"},
+		err:  `invalid --bind value "public": default space specified more than once`,
 	},
 }
 
@@ -202,6 +214,22 @@ func (s *DeploySuite) TestConstraints(c *gc.C) {
 	c.Assert(cons, jc.DeepEquals, constraints.MustParse("mem=2G cpu-cores=2 networks=net1,^net2"))
 }
 
+func (s *DeploySuite) TestBindings(c *gc.C) {
+	_, err := s.State.AddSpace("internal", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddSpace("public", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	testcharms.Repo.CharmArchivePath(s.SeriesPath, "wordpress")
+	err = runDeploy(c, "local:wordpress", "--bind", "db=internal public")
+	c.Assert(err, jc.ErrorIsNil)
+	service, err := s.State.Service("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(service.EndpointBindings(), jc.DeepEquals, map[string]string{
+		"db": "internal",
+		"":   "public",
+	})
+}
+
 func (s *DeploySuite) TestNetworks(c *gc.C) {
 	testcharms.Repo.CharmArchivePath(s.SeriesPath, "dummy")
 	err := runDeploy(c, "local:dummy", "--networks", ", net1, net2 , ", "--constraints", "mem=2G cpu-cores=2 networks=net1,net0,^net3,^net4")
@@ -87,3 +87,12 @@ func (s *GetSuite) TestGetConfig(c *gc.C) {
 		c.Assert(actual, gc.DeepEquals, expected)
 	}
 }
+
+func (s *GetSuite) TestGetReportsTraceId(c *gc.C) {
+	// Commands trace their API requests, so that the log messages
+	// of a failed request can be found.
+	ctx := coretesting.Context(c)
+	code := cmd.Main(envcmd.Wrap(&GetCommand{}), ctx, []string{"no-such-service"})
+	c.Check(code, gc.Equals, 1)
+	c.Assert(ctx.Stderr.(*bytes.Buffer).String(), gc.Matches, `(?s)trace id: [0-9a-f-]{36}\n.*service "no-such-service" not found.*`)
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/juju/juju/cmd/juju/helptopics"
 	"github.com/juju/juju/cmd/juju/machine"
 	"github.com/juju/juju/cmd/juju/service"
+	"github.com/juju/juju/cmd/juju/space"
 	"github.com/juju/juju/cmd/juju/storage"
 	"github.com/juju/juju/cmd/juju/system"
 	"github.com/juju/juju/cmd/juju/user"
@@ -209,6 +210,9 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	// Manage storage
 	r.Register(storage.NewSuperCommand())
 
+	// Manage network spaces
+	r.Register(space.NewSuperCommand())
+
 	// Manage systems
 	if featureflag.Enabled(feature.JES) {
 		r.Register(system.NewSuperCommand())
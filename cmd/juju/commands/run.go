@@ -6,14 +6,17 @@ package commands
 import (
 	"encoding/base64"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 	"unicode/utf8"
 
 	"github.com/juju/cmd"
+	"github.com/juju/errors"
 	"github.com/juju/names"
 	"launchpad.net/gnuflag"
 
+	"github.com/juju/juju/api"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/cmd/juju/block"
@@ -22,13 +25,16 @@ import (
 // RunCommand is responsible for running arbitrary commands on remote machines.
 type RunCommand struct {
 	envcmd.EnvCommandBase
-	out      cmd.Output
-	all      bool
-	timeout  time.Duration
-	machines []string
-	services []string
-	units    []string
-	commands string
+	out           cmd.Output
+	all           bool
+	timeout       time.Duration
+	parallel      int
+	stopOnFailure bool
+	stream        bool
+	machines      []string
+	services      []string
+	units         []string
+	commands      string
 }
 
 const runDoc = `
@@ -57,6 +63,33 @@ the unit.
 in the environment.  If you specify --all you cannot provide additional
 targets.
 
+The --timeout applies to each target individually: a target on which the
+commands have not completed within the timeout is reported as failed.
+
+By default the commands are run on all targets at once, and the results
+are written when every target has finished. For rolling operations the
+following options change this behaviour:
+
+--parallel limits the number of targets the commands run on at a time;
+each remaining target is started as soon as another finishes.
+
+--stop-on-failure stops starting the commands on further targets once
+they have failed on any target, either with an error or a non-zero
+return code. Targets on which the commands were not run are reported
+as skipped.
+
+--stream runs the commands on each target individually, without
+limiting their number.
+
+When any of these options are used, each target's result is written as
+soon as the commands finish on the target, as a separate document: YAML
+documents are separated by "---" lines, and JSON documents are written one
+per line. The output of the commands is part of the result, so it is not
+written while the commands are still running.
+The results are followed by a summary of the targets that succeeded,
+failed and were skipped, including the return code of each failed
+target, and the command fails if the commands failed on any target.
+
 `
 
 func (c *RunCommand) Info() *cmd.Info {
@@ -72,6 +105,9 @@ func (c *RunCommand) SetFlags(f *gnuflag.FlagSet) {
 	c.out.AddFlags(f, "smart", cmd.DefaultFormatters)
 	f.BoolVar(&c.all, "all", false, "run the commands on all the machines")
 	f.DurationVar(&c.timeout, "timeout", 5*time.Minute, "how long to wait before the remote command is considered to have failed")
+	f.IntVar(&c.parallel, "parallel", 0, "run the commands on at most this many targets at a time (0 means no limit)")
+	f.BoolVar(&c.stopOnFailure, "stop-on-failure", false, "do not run the commands on further targets after a failure")
+	f.BoolVar(&c.stream, "stream", false, "run the commands on each target individually, writing each result as soon as it finishes")
 	f.Var(cmd.NewStringsValue(nil, &c.machines), "machine", "one or more machine ids")
 	f.Var(cmd.NewStringsValue(nil, &c.services), "service", "one or more service names")
 	f.Var(cmd.NewStringsValue(nil, &c.units), "unit", "one or more unit ids")
@@ -119,6 +155,9 @@ func (c *RunCommand) Init(args []string) error {
 		return fmt.Errorf("The following run targets are not valid:\n%s",
 			strings.Join(nameErrors, "\n"))
 	}
+	if c.parallel < 0 {
+		return fmt.Errorf("--parallel must not be negative")
+	}
 
 	return cmd.CheckEmpty(args)
 }
@@ -148,30 +187,34 @@ func ConvertRunResults(runResults []params.RunResult) interface{} {
 	var results = make([]interface{}, len(runResults))
 
 	for i, result := range runResults {
-		// We always want to have a string for stdout, but only show stderr,
-		// code and error if they are there.
-		values := make(map[string]interface{})
-		values["MachineId"] = result.MachineId
-		if result.UnitId != "" {
-			values["UnitId"] = result.UnitId
-
-		}
-		storeOutput(values, "Stdout", result.Stdout)
-		if len(result.Stderr) > 0 {
-			storeOutput(values, "Stderr", result.Stderr)
-		}
-		if result.Code != 0 {
-			values["ReturnCode"] = result.Code
-		}
-		if result.Error != "" {
-			values["Error"] = result.Error
-		}
-		results[i] = values
+		results[i] = convertRunResult(result)
 	}
 
 	return results
 }
 
+func convertRunResult(result params.RunResult) map[string]interface{} {
+	// We always want to have a string for stdout, but only show stderr,
+	// code and error if they are there.
+	values := make(map[string]interface{})
+	values["MachineId"] = result.MachineId
+	if result.UnitId != "" {
+		values["UnitId"] = result.UnitId
+
+	}
+	storeOutput(values, "Stdout", result.Stdout)
+	if len(result.Stderr) > 0 {
+		storeOutput(values, "Stderr", result.Stderr)
+	}
+	if result.Code != 0 {
+		values["ReturnCode"] = result.Code
+	}
+	if result.Error != "" {
+		values["Error"] = result.Error
+	}
+	return values
+}
+
 func (c *RunCommand) Run(ctx *cmd.Context) error {
 	client, err := getRunAPIClient(c)
 	if err != nil {
@@ -179,6 +222,10 @@ func (c *RunCommand) Run(ctx *cmd.Context) error {
 	}
 	defer client.Close()
 
+	if c.rolling() {
+		return c.runRolling(ctx, client)
+	}
+
 	var runResults []params.RunResult
 	if c.all {
 		runResults, err = client.RunOnAllMachines(c.commands, c.timeout)
@@ -217,6 +264,239 @@ func (c *RunCommand) Run(ctx *cmd.Context) error {
 	return nil
 }
 
+// rolling reports whether the commands should be run on each target
+// individually, rather than on all targets with a single API call.
+func (c *RunCommand) rolling() bool {
+	return c.parallel > 0 || c.stopOnFailure || c.stream
+}
+
+// runTarget identifies a single machine or unit to run the commands on.
+type runTarget struct {
+	machineId string
+	unitId    string
+}
+
+func (t runTarget) String() string {
+	if t.unitId != "" {
+		return t.unitId
+	}
+	return t.machineId
+}
+
+// targets returns the individual machines and units to run the commands
+// on, expanding --all and --service using the environment status.
+func (c *RunCommand) targets(client RunClient) ([]runTarget, error) {
+	var status *api.Status
+	if c.all || len(c.services) > 0 {
+		var err error
+		if status, err = client.Status(nil); err != nil {
+			return nil, errors.Annotate(err, "cannot get environment status")
+		}
+	}
+	var targets []runTarget
+	if c.all {
+		var machineIds []string
+		var addMachines func(map[string]api.MachineStatus)
+		addMachines = func(machines map[string]api.MachineStatus) {
+			for id, machine := range machines {
+				machineIds = append(machineIds, id)
+				addMachines(machine.Containers)
+			}
+		}
+		addMachines(status.Machines)
+		sort.Strings(machineIds)
+		for _, id := range machineIds {
+			targets = append(targets, runTarget{machineId: id})
+		}
+		return targets, nil
+	}
+	unitIds := make(map[string]bool)
+	for _, unitId := range c.units {
+		unitIds[unitId] = true
+	}
+	for _, serviceName := range c.services {
+		service, ok := status.Services[serviceName]
+		if !ok {
+			return nil, errors.NotFoundf("service %q", serviceName)
+		}
+		for unitId := range service.Units {
+			unitIds[unitId] = true
+		}
+		// Subordinate units are only reported with their principals.
+		for _, other := range status.Services {
+			for _, unit := range other.Units {
+				for unitId := range unit.Subordinates {
+					if strings.HasPrefix(unitId, serviceName+"/") {
+						unitIds[unitId] = true
+					}
+				}
+			}
+		}
+	}
+	sortedUnitIds := make([]string, 0, len(unitIds))
+	for unitId := range unitIds {
+		sortedUnitIds = append(sortedUnitIds, unitId)
+	}
+	sort.Strings(sortedUnitIds)
+	for _, unitId := range sortedUnitIds {
+		targets = append(targets, runTarget{unitId: unitId})
+	}
+	for _, machineId := range c.machines {
+		targets = append(targets, runTarget{machineId: machineId})
+	}
+	return targets, nil
+}
+
+// runOn runs the commands on the single target.
+func (c *RunCommand) runOn(client RunClient, target runTarget) (params.RunResult, error) {
+	args := params.RunParams{
+		Commands: c.commands,
+		Timeout:  c.timeout,
+	}
+	if target.unitId != "" {
+		args.Units = []string{target.unitId}
+	} else {
+		args.Machines = []string{target.machineId}
+	}
+	results, err := client.Run(args)
+	if err != nil {
+		return params.RunResult{}, err
+	}
+	if len(results) != 1 {
+		return params.RunResult{}, errors.Errorf("expected 1 result, got %d", len(results))
+	}
+	return results[0], nil
+}
+
+// runRolling runs the commands on each target individually, at most
+// c.parallel at a time, writing each target's result as soon as it
+// finishes, followed by a summary of the outcomes.
+func (c *RunCommand) runRolling(ctx *cmd.Context, client RunClient) error {
+	targets, err := c.targets(client)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	limit := c.parallel
+	if limit == 0 || limit > len(targets) {
+		limit = len(targets)
+	}
+
+	type outcome struct {
+		index  int
+		result params.RunResult
+		err    error
+	}
+	// The channel has room for every outcome, so that no goroutine
+	// is left blocked if we stop reading before all have finished.
+	done := make(chan outcome, len(targets))
+	results := make([]*params.RunResult, len(targets))
+	var blockedErr, writeErr error
+	next, running, stopped := 0, 0, false
+	for {
+		for !stopped && running < limit && next < len(targets) {
+			go func(index int, target runTarget) {
+				result, err := c.runOn(client, target)
+				done <- outcome{index, result, err}
+			}(next, targets[next])
+			next++
+			running++
+		}
+		if running == 0 {
+			break
+		}
+		// Once stopped, we wait for the running targets to finish,
+		// so that their results are reported.
+		o := <-done
+		running--
+		if params.IsCodeOperationBlocked(o.err) {
+			blockedErr = o.err
+			stopped = true
+			continue
+		}
+		if o.err != nil {
+			// Report API failures against the target, in the same
+			// way as failures to run the commands.
+			target := targets[o.index]
+			o.result = params.RunResult{
+				MachineId: target.machineId,
+				UnitId:    target.unitId,
+				Error:     o.err.Error(),
+			}
+		}
+		results[o.index] = &o.result
+		if writeErr == nil {
+			if writeErr = c.writeDocument(ctx, convertRunResult(o.result)); writeErr != nil {
+				stopped = true
+			}
+		}
+		if runFailed(o.result) && c.stopOnFailure {
+			stopped = true
+		}
+	}
+	if writeErr != nil {
+		return errors.Trace(writeErr)
+	}
+	if blockedErr != nil {
+		return block.ProcessBlockedError(blockedErr, block.BlockChange)
+	}
+
+	summary, failed := summarizeRunResults(targets, results)
+	if err := c.writeDocument(ctx, map[string]interface{}{"Summary": summary}); err != nil {
+		return errors.Trace(err)
+	}
+	if failed > 0 {
+		return errors.Errorf("commands failed on %d of %d targets", failed, len(targets))
+	}
+	return nil
+}
+
+// writeDocument writes a single streamed document in the chosen format.
+func (c *RunCommand) writeDocument(ctx *cmd.Context, value interface{}) error {
+	if c.out.Name() != "json" {
+		fmt.Fprintln(ctx.Stdout, "---")
+	}
+	return c.out.Write(ctx, value)
+}
+
+// runFailed reports whether the result records a failure to run the
+// commands, or a non-zero return code.
+func runFailed(result params.RunResult) bool {
+	return result.Error != "" || result.Code != 0
+}
+
+// summarizeRunResults returns a summary of the outcome on each target,
+// suitable for format conversion to YAML or JSON, and the number of
+// targets on which the commands failed. A nil result indicates that
+// the target was skipped.
+func summarizeRunResults(targets []runTarget, results []*params.RunResult) (map[string]interface{}, int) {
+	succeeded := []string{}
+	failed := []interface{}{}
+	skipped := []string{}
+	for i, result := range results {
+		target := targets[i].String()
+		switch {
+		case result == nil:
+			skipped = append(skipped, target)
+		case runFailed(*result):
+			values := map[string]interface{}{
+				"Target":     target,
+				"ReturnCode": result.Code,
+			}
+			if result.Error != "" {
+				values["Error"] = result.Error
+			}
+			failed = append(failed, values)
+		default:
+			succeeded = append(succeeded, target)
+		}
+	}
+	return map[string]interface{}{
+		"Succeeded": succeeded,
+		"Failed":    failed,
+		"Skipped":   skipped,
+	}, len(failed)
+}
+
 // In order to be able to easily mock out the API side for testing,
 // the API client is got using a function.
 
@@ -224,6 +504,7 @@ type RunClient interface {
 	Close() error
 	RunOnAllMachines(commands string, timeout time.Duration) ([]params.RunResult, error)
 	Run(run params.RunParams) ([]params.RunResult, error)
+	Status(patterns []string) (*api.Status, error)
 }
 
 // Here we need the signature to be correct for the interface.
@@ -4,9 +4,11 @@
 package commands
 
 import (
+	"bytes"
 	"fmt"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/juju/cmd"
@@ -14,6 +16,7 @@ import (
 	"github.com/juju/utils/exec"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/api"
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/envcmd"
@@ -107,6 +110,10 @@ func (*RunSuite) TestTargetArgParsing(c *gc.C) {
 		machines: []string{"0"},
 		services: []string{"mysql"},
 		units:    []string{"wordpress/0", "wordpress/1"},
+	}, {
+		message:  "negative parallel",
+		args:     []string{"--parallel=-1", "--all", "sudo reboot"},
+		errMatch: "--parallel must not be negative",
 	}} {
 		c.Log(fmt.Sprintf("%v: %s", i, test.message))
 		runCmd := &RunCommand{}
@@ -375,6 +382,172 @@ func (s *RunSuite) TestSingleResponse(c *gc.C) {
 	}
 }
 
+func (s *RunSuite) TestRollingStreamStopOnFailure(c *gc.C) {
+	mock := s.setupMockAPI()
+	mock.status = &api.Status{
+		Services: map[string]api.ServiceStatus{
+			"mysql": {Units: map[string]api.UnitStatus{
+				"mysql/0": {},
+				"mysql/1": {},
+				"mysql/2": {},
+			}},
+		},
+	}
+	response0 := mockResponse{stdout: "ok\n", machineId: "1", unitId: "mysql/0"}
+	response1 := mockResponse{stderr: "oops\n", code: 2, machineId: "2", unitId: "mysql/1"}
+	mock.setResponse("mysql/0", response0)
+	mock.setResponse("mysql/1", response1)
+	mock.setResponse("mysql/2", mockResponse{machineId: "3", unitId: "mysql/2"})
+
+	context, err := testing.RunCommand(c, envcmd.Wrap(&RunCommand{}),
+		"--format=json", "--stream", "--parallel=1", "--stop-on-failure", "--service=mysql", "hostname",
+	)
+	c.Assert(err, gc.ErrorMatches, "commands failed on 1 of 3 targets")
+
+	var expected []string
+	for _, value := range []interface{}{
+		ConvertRunResults([]params.RunResult{makeRunResult(response0)}).([]interface{})[0],
+		ConvertRunResults([]params.RunResult{makeRunResult(response1)}).([]interface{})[0],
+		map[string]interface{}{"Summary": map[string]interface{}{
+			"Succeeded": []string{"mysql/0"},
+			"Failed": []interface{}{
+				map[string]interface{}{"Target": "mysql/1", "ReturnCode": 2},
+			},
+			"Skipped": []string{"mysql/2"},
+		}},
+	} {
+		formatted, err := cmd.FormatJson(value)
+		c.Assert(err, jc.ErrorIsNil)
+		expected = append(expected, string(formatted)+"\n")
+	}
+	c.Check(testing.Stdout(context), gc.Equals, strings.Join(expected, ""))
+	c.Check(mock.runCalls, gc.DeepEquals, []params.RunParams{
+		{Commands: "hostname", Timeout: 5 * time.Minute, Units: []string{"mysql/0"}},
+		{Commands: "hostname", Timeout: 5 * time.Minute, Units: []string{"mysql/1"}},
+	})
+}
+
+func (s *RunSuite) TestRollingSummary(c *gc.C) {
+	mock := s.setupMockAPI()
+	response0 := mockResponse{stdout: "megatron\n", machineId: "0"}
+	mock.setResponse("0", response0)
+
+	context, err := testing.RunCommand(c, envcmd.Wrap(&RunCommand{}),
+		"--format=json", "--parallel=1", "--machine=0,1", "hostname",
+	)
+	c.Assert(err, gc.ErrorMatches, "commands failed on 1 of 2 targets")
+
+	var expected []string
+	for _, value := range []interface{}{
+		ConvertRunResults([]params.RunResult{makeRunResult(response0)}).([]interface{})[0],
+		ConvertRunResults([]params.RunResult{
+			makeRunResult(mockResponse{machineId: "1", error: "expected 1 result, got 0"}),
+		}).([]interface{})[0],
+		map[string]interface{}{"Summary": map[string]interface{}{
+			"Succeeded": []string{"0"},
+			"Failed": []interface{}{
+				map[string]interface{}{"Target": "1", "ReturnCode": 0, "Error": "expected 1 result, got 0"},
+			},
+			"Skipped": []string{},
+		}},
+	} {
+		formatted, err := cmd.FormatJson(value)
+		c.Assert(err, jc.ErrorIsNil)
+		expected = append(expected, string(formatted)+"\n")
+	}
+	c.Check(testing.Stdout(context), gc.Equals, strings.Join(expected, ""))
+}
+
+func (s *RunSuite) TestRollingWritesResultsAsTargetsFinish(c *gc.C) {
+	mock := s.setupMockAPI()
+	response0 := mockResponse{stdout: "megatron\n", machineId: "0"}
+	response1 := mockResponse{stdout: "optimus\n", machineId: "1"}
+	mock.setResponse("0", response0)
+	mock.setResponse("1", response1)
+
+	// The commands only finish on machine 1 once something has been
+	// written, which must be the result of machine 0.
+	stdout := &notifyWriter{written: make(chan struct{}, 1)}
+	mock.wait = map[string]<-chan struct{}{"1": stdout.written}
+
+	com := envcmd.Wrap(&RunCommand{})
+	err := testing.InitCommand(com, []string{"--format=json", "--stream", "--machine=0,1", "hostname"})
+	c.Assert(err, jc.ErrorIsNil)
+	ctx := testing.Context(c)
+	ctx.Stdout = stdout
+	err = com.Run(ctx)
+	c.Assert(err, jc.ErrorIsNil)
+
+	var expected []string
+	for _, value := range []interface{}{
+		ConvertRunResults([]params.RunResult{makeRunResult(response0)}).([]interface{})[0],
+		ConvertRunResults([]params.RunResult{makeRunResult(response1)}).([]interface{})[0],
+		map[string]interface{}{"Summary": map[string]interface{}{
+			"Succeeded": []string{"0", "1"},
+			"Failed":    []interface{}{},
+			"Skipped":   []string{},
+		}},
+	} {
+		formatted, err := cmd.FormatJson(value)
+		c.Assert(err, jc.ErrorIsNil)
+		expected = append(expected, string(formatted)+"\n")
+	}
+	c.Check(stdout.String(), gc.Equals, strings.Join(expected, ""))
+}
+
+// notifyWriter is a writer that signals on its written channel when
+// it is written to.
+type notifyWriter struct {
+	mu      sync.Mutex
+	buf     bytes.Buffer
+	written chan struct{}
+}
+
+func (w *notifyWriter) Write(data []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	select {
+	case w.written <- struct{}{}:
+	default:
+	}
+	return w.buf.Write(data)
+}
+
+func (w *notifyWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.String()
+}
+
+func (s *RunSuite) TestRollingAllMachines(c *gc.C) {
+	mock := s.setupMockAPI()
+	mock.status = &api.Status{
+		Machines: map[string]api.MachineStatus{
+			"0": {},
+			"1": {Containers: map[string]api.MachineStatus{"1/lxc/0": {}}},
+		},
+	}
+	for _, id := range []string{"0", "1", "1/lxc/0"} {
+		mock.setResponse(id, mockResponse{machineId: id})
+	}
+
+	_, err := testing.RunCommand(c, envcmd.Wrap(&RunCommand{}), "--parallel=1", "--all", "hostname")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(mock.runCalls, gc.HasLen, 3)
+	for i, id := range []string{"0", "1", "1/lxc/0"} {
+		c.Check(mock.runCalls[i].Machines, gc.DeepEquals, []string{id})
+	}
+}
+
+func (s *RunSuite) TestBlockRolling(c *gc.C) {
+	mock := s.setupMockAPI()
+	mock.block = true
+	_, err := testing.RunCommand(c, envcmd.Wrap(&RunCommand{}), "--stream", "--machine=0", "hostname")
+	c.Assert(err, gc.ErrorMatches, cmd.ErrSilent.Error())
+	stripped := strings.Replace(c.GetTestLog(), "\n", "", -1)
+	c.Check(stripped, gc.Matches, ".*To unblock changes.*")
+}
+
 func (s *RunSuite) setupMockAPI() *mockRunAPI {
 	mock := &mockRunAPI{}
 	s.PatchValue(&getRunAPIClient, func(_ *RunCommand) (RunClient, error) {
@@ -391,6 +564,13 @@ type mockRunAPI struct {
 	machines  map[string]bool
 	responses map[string]params.RunResult
 	block     bool
+	status    *api.Status
+	// wait holds, for machine ids, channels that the commands
+	// wait on before finishing on those machines.
+	wait map[string]<-chan struct{}
+
+	mu       sync.Mutex
+	runCalls []params.RunParams
 }
 
 type mockResponse struct {
@@ -461,14 +641,28 @@ func (m *mockRunAPI) RunOnAllMachines(commands string, timeout time.Duration) ([
 	return result, nil
 }
 
+func (m *mockRunAPI) Status(patterns []string) (*api.Status, error) {
+	return m.status, nil
+}
+
 func (m *mockRunAPI) Run(runParams params.RunParams) ([]params.RunResult, error) {
 	var result []params.RunResult
+	m.mu.Lock()
+	m.runCalls = append(m.runCalls, runParams)
+	m.mu.Unlock()
 
 	if m.block {
 		return result, common.ErrOperationBlocked("The operation has been blocked.")
 	}
 	// Just add in ids that match in order.
 	for _, id := range runParams.Machines {
+		if wait := m.wait[id]; wait != nil {
+			select {
+			case <-wait:
+			case <-time.After(testing.LongWait):
+				return nil, fmt.Errorf("timed out waiting to finish on machine %s", id)
+			}
+		}
 		response, found := m.responses[id]
 		if found {
 			result = append(result, response)
@@ -149,6 +149,16 @@ type formattedStatus struct {
 	Machines    map[string]machineStatus `json:"machines"`
 	Services    map[string]serviceStatus `json:"services"`
 	Networks    map[string]networkStatus `json:"networks,omitempty" yaml:",omitempty"`
+	Rolling     *rollingUpgradeStatus    `json:"rolling-upgrade,omitempty" yaml:"rolling-upgrade,omitempty"`
+}
+
+type rollingUpgradeStatus struct {
+	From      string   `json:"from" yaml:"from"`
+	To        string   `json:"to" yaml:"to"`
+	BatchSize int      `json:"batch-size" yaml:"batch-size"`
+	Released  []string `json:"released-machines" yaml:"released-machines"`
+	Paused    bool     `json:"paused,omitempty" yaml:"paused,omitempty"`
+	Reason    string   `json:"reason,omitempty" yaml:"reason,omitempty"`
 }
 
 type errorStatus struct {
@@ -205,6 +215,8 @@ type serviceStatus struct {
 	Networks      map[string][]string   `json:"networks,omitempty" yaml:"networks,omitempty"`
 	SubordinateTo []string              `json:"subordinate-to,omitempty" yaml:"subordinate-to,omitempty"`
 	Units         map[string]unitStatus `json:"units,omitempty" yaml:"units,omitempty"`
+
+	PlacementViolations []string `json:"placement-violations,omitempty" yaml:"placement-violations,omitempty"`
 }
 
 type serviceStatusNoMarshal serviceStatus
@@ -352,6 +364,16 @@ func (sf *statusFormatter) format() formattedStatus {
 		}
 		out.Networks[k] = sf.formatNetwork(n)
 	}
+	if rolling := sf.status.RollingUpgrade; rolling != nil {
+		out.Rolling = &rollingUpgradeStatus{
+			From:      rolling.From,
+			To:        rolling.To,
+			BatchSize: rolling.BatchSize,
+			Released:  rolling.Machines,
+			Paused:    rolling.Paused,
+			Reason:    rolling.Reason,
+		}
+	}
 	return out
 }
 
@@ -409,16 +431,17 @@ func (sf *statusFormatter) formatMachine(machine api.MachineStatus) machineStatu
 
 func (sf *statusFormatter) formatService(name string, service api.ServiceStatus) serviceStatus {
 	out := serviceStatus{
-		Err:           service.Err,
-		Charm:         service.Charm,
-		Exposed:       service.Exposed,
-		Life:          service.Life,
-		Relations:     service.Relations,
-		Networks:      make(map[string][]string),
-		CanUpgradeTo:  service.CanUpgradeTo,
-		SubordinateTo: service.SubordinateTo,
-		Units:         make(map[string]unitStatus),
-		StatusInfo:    sf.getServiceStatusInfo(service),
+		Err:                 service.Err,
+		Charm:               service.Charm,
+		Exposed:             service.Exposed,
+		Life:                service.Life,
+		Relations:           service.Relations,
+		Networks:            make(map[string][]string),
+		CanUpgradeTo:        service.CanUpgradeTo,
+		SubordinateTo:       service.SubordinateTo,
+		Units:               make(map[string]unitStatus),
+		StatusInfo:          sf.getServiceStatusInfo(service),
+		PlacementViolations: service.PlacementViolations,
 	}
 	if len(service.Networks.Enabled) > 0 {
 		out.Networks["enabled"] = service.Networks.Enabled
@@ -3117,6 +3117,29 @@ func (s *StatusSuite) TestFormatTabularHookActionName(c *gc.C) {
 	)
 }
 
+func (s *StatusSuite) TestFormatRollingUpgrade(c *gc.C) {
+	status := &api.Status{
+		EnvironmentName: "dummyenv",
+		RollingUpgrade: &api.RollingUpgradeStatus{
+			From:      "1.2.3",
+			To:        "1.2.4",
+			BatchSize: 2,
+			Machines:  []string{"1", "2"},
+			Paused:    true,
+			Reason:    "machine 2 failed: disk full",
+		},
+	}
+	out := newStatusFormatter(status, 0, false).format()
+	c.Assert(out.Rolling, jc.DeepEquals, &rollingUpgradeStatus{
+		From:      "1.2.3",
+		To:        "1.2.4",
+		BatchSize: 2,
+		Released:  []string{"1", "2"},
+		Paused:    true,
+		Reason:    "machine 2 failed: disk full",
+	})
+}
+
 func (s *StatusSuite) TestStatusWithNilStatusApi(c *gc.C) {
 	ctx := s.newContext(c)
 	defer s.resetContext(c, ctx)
@@ -7,9 +7,11 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"text/tabwriter"
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
+	"github.com/juju/names"
 
 	"github.com/juju/juju/api"
 	"github.com/juju/juju/apiserver/params"
@@ -24,25 +26,36 @@ type StatusHistoryCommand struct {
 	outputContent string
 	backlogSize   int
 	isoTime       bool
+	hooks         bool
 	unitName      string
+	machineId     string
+	serviceName   string
 }
 
 var statusHistoryDoc = `
 This command will report the history of status changes for
-a given unit.
+a given unit, machine or service.
 The statuses for the unit workload and/or agent are available.
 -type supports:
     agent: will show statuses for the unit's agent
     workload: will show statuses for the unit's workload
     combined: will show agent and workload statuses combined
  and sorted by time of occurence.
+--hooks reports the hooks, actions and commands most recently
+executed by the unit instead, with their start times, durations,
+exit codes and numbers of hook tool calls.
+For a machine, the history includes each attempt made by the
+provisioner to start its instance, and why it failed; -type and
+--hooks do not apply.
+For a service, the history includes each scaling decision taken
+under its scaling policy; -type and --hooks do not apply.
 `
 
 func (c *StatusHistoryCommand) Info() *cmd.Info {
 	return &cmd.Info{
 		Name:    "status-history",
-		Args:    "[-n N] <unit>",
-		Purpose: "output past statuses for a unit",
+		Args:    "[-n N] [--hooks] <unit>|<machine>|<service>",
+		Purpose: "output past statuses for a unit, machine or service",
 		Doc:     statusHistoryDoc,
 	}
 }
@@ -51,6 +64,7 @@ func (c *StatusHistoryCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.StringVar(&c.outputContent, "type", "combined", "type of statuses to be displayed [agent|workload|combined].")
 	f.IntVar(&c.backlogSize, "n", 20, "size of logs backlog.")
 	f.BoolVar(&c.isoTime, "utc", false, "display time as UTC in RFC3339 format")
+	f.BoolVar(&c.hooks, "hooks", false, "show hook execution timings instead of statuses")
 }
 
 func (c *StatusHistoryCommand) Init(args []string) error {
@@ -59,6 +73,16 @@ func (c *StatusHistoryCommand) Init(args []string) error {
 		return errors.Errorf("unexpected arguments after unit name.")
 	case len(args) == 0:
 		return errors.Errorf("unit name is missing.")
+	case names.IsValidMachine(args[0]):
+		if c.hooks {
+			return errors.Errorf("--hooks is not valid for a machine")
+		}
+		c.machineId = args[0]
+	case names.IsValidService(args[0]):
+		if c.hooks {
+			return errors.Errorf("--hooks is not valid for a service")
+		}
+		c.serviceName = args[0]
 	default:
 		c.unitName = args[0]
 	}
@@ -88,9 +112,18 @@ func (c *StatusHistoryCommand) Run(ctx *cmd.Context) error {
 		return fmt.Errorf(connectionError, c.ConnectionName(), err)
 	}
 	defer apiclient.Close()
+	if c.hooks {
+		return c.runHooks(ctx, apiclient)
+	}
 	var statuses *api.UnitStatusHistory
 	kind := params.HistoryKind(c.outputContent)
-	statuses, err = apiclient.UnitStatusHistory(kind, c.unitName, c.backlogSize)
+	if c.machineId != "" {
+		statuses, err = apiclient.UnitStatusHistory(params.KindMachine, c.machineId, c.backlogSize)
+	} else if c.serviceName != "" {
+		statuses, err = apiclient.UnitStatusHistory(params.KindService, c.serviceName, c.backlogSize)
+	} else {
+		statuses, err = apiclient.UnitStatusHistory(kind, c.unitName, c.backlogSize)
+	}
 	if err != nil {
 		if len(statuses.Statuses) == 0 {
 			return errors.Trace(err)
@@ -117,3 +150,31 @@ func (c *StatusHistoryCommand) Run(ctx *cmd.Context) error {
 	}
 	return nil
 }
+
+// runHooks displays the hook executions recorded for the unit.
+func (c *StatusHistoryCommand) runHooks(ctx *cmd.Context, apiclient *api.Client) error {
+	executions, err := apiclient.HookStats(c.unitName, c.backlogSize)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(executions) == 0 {
+		return errors.Errorf("no hook history available")
+	}
+	tw := tabwriter.NewWriter(ctx.Stdout, 0, 1, 1, ' ', 0)
+	fmt.Fprintln(tw, "STARTED\tKIND\tNAME\tDURATION\tEXIT\tTOOL-CALLS")
+	// Executions are returned most recent first; display them in
+	// the order in which they occurred.
+	for i := len(executions) - 1; i >= 0; i-- {
+		e := executions[i]
+		started := e.Started
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%d\t%d\n",
+			formatStatusTime(&started, c.isoTime),
+			e.Kind,
+			e.Name,
+			e.Finished.Sub(e.Started),
+			e.ExitCode,
+			e.ToolCalls,
+		)
+	}
+	return tw.Flush()
+}
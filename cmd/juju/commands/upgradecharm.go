@@ -26,6 +26,10 @@ type UpgradeCharmCommand struct {
 	RepoPath    string // defaults to JUJU_REPOSITORY
 	SwitchURL   string
 	Revision    int // defaults to -1 (latest)
+	Canary      int // defaults to 0 (upgrade all units at once)
+	BatchSize   int
+	Rollback    bool
+	Resume      bool
 }
 
 const upgradeCharmDoc = `
@@ -64,6 +68,13 @@ would specify revision number 5 of the wordpress charm.
 Use of the --force flag is not generally recommended; units upgraded while in an
 error state will not have upgrade-charm hooks executed, and may cause unexpected
 behavior.
+
+The --canary flag upgrades only the given number of units at first. Once all
+upgraded units are active, the remaining units are upgraded --batch-size at a
+time. If an upgraded unit fails, no further units are upgraded until either
+--resume is used to continue the upgrade, or --rollback is used to revert the
+service, including the units whose upgrade failed, to the charm it ran before
+the upgrade.
 `
 
 func (c *UpgradeCharmCommand) Info() *cmd.Info {
@@ -80,6 +91,10 @@ func (c *UpgradeCharmCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.StringVar(&c.RepoPath, "repository", os.Getenv("JUJU_REPOSITORY"), "local charm repository path")
 	f.StringVar(&c.SwitchURL, "switch", "", "crossgrade to a different charm")
 	f.IntVar(&c.Revision, "revision", -1, "explicit revision of current charm")
+	f.IntVar(&c.Canary, "canary", 0, "number of units to upgrade first (0 for all units)")
+	f.IntVar(&c.BatchSize, "batch-size", 1, "number of units to upgrade at a time after the canary units")
+	f.BoolVar(&c.Rollback, "rollback", false, "revert to the charm the service ran before its last upgrade")
+	f.BoolVar(&c.Resume, "resume", false, "resume a paused canary upgrade")
 }
 
 func (c *UpgradeCharmCommand) Init(args []string) error {
@@ -97,6 +112,18 @@ func (c *UpgradeCharmCommand) Init(args []string) error {
 	if c.SwitchURL != "" && c.Revision != -1 {
 		return fmt.Errorf("--switch and --revision are mutually exclusive")
 	}
+	if c.Rollback && c.Resume {
+		return fmt.Errorf("--rollback and --resume are mutually exclusive")
+	}
+	if (c.Rollback || c.Resume) && (c.SwitchURL != "" || c.Revision != -1 || c.Canary != 0) {
+		return fmt.Errorf("--rollback and --resume cannot be used with --switch, --revision or --canary")
+	}
+	if c.Canary < 0 {
+		return fmt.Errorf("--canary must not be negative")
+	}
+	if c.BatchSize < 1 {
+		return fmt.Errorf("--batch-size must be positive")
+	}
 	return nil
 }
 
@@ -108,6 +135,12 @@ func (c *UpgradeCharmCommand) Run(ctx *cmd.Context) error {
 		return err
 	}
 	defer client.Close()
+	switch {
+	case c.Rollback:
+		return block.ProcessBlockedError(client.ServiceRollbackCharm(c.ServiceName), block.BlockChange)
+	case c.Resume:
+		return block.ProcessBlockedError(client.ServiceResumeCharmRollout(c.ServiceName), block.BlockChange)
+	}
 	oldURL, err := client.ServiceGetCharmURL(c.ServiceName)
 	if err != nil {
 		return err
@@ -158,5 +191,9 @@ func (c *UpgradeCharmCommand) Run(ctx *cmd.Context) error {
 		return block.ProcessBlockedError(err, block.BlockChange)
 	}
 
+	if c.Canary > 0 {
+		err = client.ServiceSetCharmInBatches(c.ServiceName, addedURL.String(), c.Force, c.Canary, c.BatchSize)
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
 	return block.ProcessBlockedError(client.ServiceSetCharm(c.ServiceName, addedURL.String(), c.Force), block.BlockChange)
 }
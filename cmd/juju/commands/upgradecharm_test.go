@@ -108,6 +108,33 @@ func (s *UpgradeCharmErrorsSuite) TestInvalidRevision(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `invalid value "blah" for flag --revision: strconv.ParseInt: parsing "blah": invalid syntax`)
 }
 
+func (s *UpgradeCharmErrorsSuite) TestInvalidCanaryFlags(c *gc.C) {
+	s.deployService(c)
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		args: []string{"--rollback", "--resume"},
+		err:  "--rollback and --resume are mutually exclusive",
+	}, {
+		args: []string{"--rollback", "--canary=1"},
+		err:  "--rollback and --resume cannot be used with --switch, --revision or --canary",
+	}, {
+		args: []string{"--resume", "--revision=2"},
+		err:  "--rollback and --resume cannot be used with --switch, --revision or --canary",
+	}, {
+		args: []string{"--canary=-1"},
+		err:  "--canary must not be negative",
+	}, {
+		args: []string{"--canary=1", "--batch-size=0"},
+		err:  "--batch-size must be positive",
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		err := runUpgradeCharm(c, append([]string{"riak"}, test.args...)...)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
 type UpgradeCharmSuccessSuite struct {
 	jujutesting.RepoSuite
 	CmdBlockHelper
@@ -231,6 +258,33 @@ func (s *UpgradeCharmSuccessSuite) TestBlockForcedUpgrade(c *gc.C) {
 	s.assertLocalRevision(c, 7, s.path)
 }
 
+func (s *UpgradeCharmSuccessSuite) TestCanaryUpgradeAndRollback(c *gc.C) {
+	_, err := s.riak.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = runUpgradeCharm(c, "riak", "--canary=1", "--batch-size=2")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertUpgraded(c, 8, false)
+	rollout, ok := s.riak.CharmRollout()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(rollout.BatchSize, gc.Equals, 2)
+	c.Assert(rollout.Units, jc.DeepEquals, []string{"riak/0"})
+
+	err = s.riak.PauseCharmRollout("unit riak/0 failed")
+	c.Assert(err, jc.ErrorIsNil)
+	err = runUpgradeCharm(c, "riak", "--resume")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.riak.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	rollout, _ = s.riak.CharmRollout()
+	c.Assert(rollout.Paused, jc.IsFalse)
+
+	err = runUpgradeCharm(c, "riak", "--rollback")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertUpgraded(c, 7, true)
+	_, ok = s.riak.CharmRollout()
+	c.Assert(ok, jc.IsFalse)
+}
+
 var myriakMeta = []byte(`
 name: myriak
 summary: "K/V storage engine"
@@ -35,6 +35,9 @@ type UpgradeJujuCommand struct {
 	ResetPrevious bool
 	AssumeYes     bool
 	Series        []string
+	BatchSize     int
+	Pause         bool
+	Resume        bool
 }
 
 var upgradeJujuDoc = `
@@ -74,7 +77,22 @@ progress. It will also abort if a previous upgrade was partially
 completed - this can happen if one of the state servers in a high
 availability environment failed to upgrade. If a failed upgrade has
 been resolved, the --reset-previous-upgrade flag can be used to reset
-the environment's upgrade tracking state, allowing further upgrades.`
+the environment's upgrade tracking state, allowing further upgrades.
+
+By default the agents of all machines upgrade at the same time, once the
+state servers have been upgraded. The --batch-size flag starts a rolling
+upgrade instead: machines that are not state servers are upgraded the
+given number of machines at a time, and each batch is only started once
+the agents of the state servers and of the previous batches are running
+the new version. If a machine of a batch fails, the rolling upgrade is
+paused. The progress of a rolling upgrade is shown by "juju status".
+
+A rolling upgrade can be paused with the --pause flag, and resumed with
+the --resume flag once any failed machines have been dealt with:
+
+    juju upgrade-juju --batch-size 5
+    juju upgrade-juju --pause
+    juju upgrade-juju --resume`
 
 func (c *UpgradeJujuCommand) Info() *cmd.Info {
 	return &cmd.Info{
@@ -92,6 +110,9 @@ func (c *UpgradeJujuCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.BoolVar(&c.AssumeYes, "y", false, "answer 'yes' to confirmation prompts")
 	f.BoolVar(&c.AssumeYes, "yes", false, "")
 	f.Var(newSeriesValue(nil, &c.Series), "series", "upload tools for supplied comma-separated series list (OBSOLETE)")
+	f.IntVar(&c.BatchSize, "batch-size", 0, "upgrade machines this many at a time, after the state servers")
+	f.BoolVar(&c.Pause, "pause", false, "pause the rolling upgrade in progress")
+	f.BoolVar(&c.Resume, "resume", false, "resume the paused rolling upgrade")
 }
 
 func (c *UpgradeJujuCommand) Init(args []string) error {
@@ -117,6 +138,17 @@ func (c *UpgradeJujuCommand) Init(args []string) error {
 	if len(c.Series) > 0 && !c.UploadTools {
 		return fmt.Errorf("--series requires --upload-tools")
 	}
+	if c.BatchSize < 0 {
+		return fmt.Errorf("--batch-size must not be negative")
+	}
+	if c.Pause || c.Resume {
+		if c.Pause && c.Resume {
+			return fmt.Errorf("--pause and --resume are mutually exclusive")
+		}
+		if c.vers != "" || c.UploadTools || c.DryRun || c.ResetPrevious || c.BatchSize > 0 {
+			return fmt.Errorf("--pause and --resume cannot be used with other upgrade flags")
+		}
+	}
 	return cmd.CheckEmpty(args)
 }
 
@@ -136,6 +168,9 @@ type upgradeJujuAPI interface {
 	UploadTools(r io.Reader, vers version.Binary, additionalSeries ...string) (*coretools.Tools, error)
 	AbortCurrentUpgrade() error
 	SetEnvironAgentVersion(version version.Number) error
+	SetEnvironAgentVersionRolling(version version.Number, batchSize int) error
+	PauseRollingUpgrade() error
+	ResumeRollingUpgrade() error
 	Close() error
 }
 
@@ -154,6 +189,12 @@ func (c *UpgradeJujuCommand) Run(ctx *cmd.Context) (err error) {
 		return err
 	}
 	defer client.Close()
+	if c.Pause {
+		return block.ProcessBlockedError(client.PauseRollingUpgrade(), block.BlockChange)
+	}
+	if c.Resume {
+		return block.ProcessBlockedError(client.ResumeRollingUpgrade(), block.BlockChange)
+	}
 	defer func() {
 		if err == errUpToDate {
 			ctx.Infof(err.Error())
@@ -200,7 +241,7 @@ func (c *UpgradeJujuCommand) Run(ctx *cmd.Context) (err error) {
 				return block.ProcessBlockedError(err, block.BlockChange)
 			}
 		}
-		if err := client.SetEnvironAgentVersion(context.chosen); err != nil {
+		if err := c.setEnvironAgentVersion(client, context.chosen); err != nil {
 			if params.IsCodeUpgradeInProgress(err) {
 				return errors.Errorf("%s\n\n"+
 					"Please wait for the upgrade to complete or if there was a problem with\n"+
@@ -216,6 +257,15 @@ func (c *UpgradeJujuCommand) Run(ctx *cmd.Context) (err error) {
 	return nil
 }
 
+// setEnvironAgentVersion starts the upgrade to the chosen version,
+// rolling it out in batches if a batch size was given.
+func (c *UpgradeJujuCommand) setEnvironAgentVersion(client upgradeJujuAPI, chosen version.Number) error {
+	if c.BatchSize > 0 {
+		return client.SetEnvironAgentVersionRolling(chosen, c.BatchSize)
+	}
+	return client.SetEnvironAgentVersion(chosen)
+}
+
 const resetPreviousUpgradeMessage = `
 WARNING! using --reset-previous-upgrade when an upgrade is in progress
 will cause the upgrade to fail. Only use this option to clear an
@@ -118,6 +118,21 @@ var upgradeJujuTests = []struct {
 	currentVersion: "3.2.7-quantal-amd64",
 	args:           []string{"--upload-tools", "--version", "3.2.8.4"},
 	expectInitErr:  "cannot specify build number when uploading tools",
+}, {
+	about:          "negative --batch-size",
+	currentVersion: "4.2.0-quantal-amd64",
+	args:           []string{"--batch-size", "-1"},
+	expectInitErr:  "--batch-size must not be negative",
+}, {
+	about:          "--pause with --resume",
+	currentVersion: "4.2.0-quantal-amd64",
+	args:           []string{"--pause", "--resume"},
+	expectInitErr:  "--pause and --resume are mutually exclusive",
+}, {
+	about:          "--resume with --version",
+	currentVersion: "4.2.0-quantal-amd64",
+	args:           []string{"--resume", "--version", "4.2.1"},
+	expectInitErr:  "--pause and --resume cannot be used with other upgrade flags",
 }, {
 	about:          "latest supported stable release",
 	tools:          []string{"2.1.0-quantal-amd64", "2.1.2-quantal-i386", "2.1.3-quantal-amd64", "2.1-dev1-quantal-amd64"},
@@ -677,6 +692,37 @@ func (s *UpgradeJujuSuite) TestResetPreviousUpgrade(c *gc.C) {
 	}
 }
 
+func (s *UpgradeJujuSuite) TestRollingUpgrade(c *gc.C) {
+	fakeAPI := NewFakeUpgradeJujuAPI(c, s.State)
+	fakeAPI.patch(s)
+
+	cmd := &UpgradeJujuCommand{}
+	err := coretesting.InitCommand(envcmd.Wrap(cmd), []string{"--batch-size", "3"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = cmd.Run(coretesting.Context(c))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(fakeAPI.setVersionCalledWith, gc.Equals, fakeAPI.nextVersion.Number)
+	c.Assert(fakeAPI.setVersionBatchSize, gc.Equals, 3)
+
+	fakeAPI.reset()
+	cmd = &UpgradeJujuCommand{}
+	err = coretesting.InitCommand(envcmd.Wrap(cmd), []string{"--pause"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = cmd.Run(coretesting.Context(c))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(fakeAPI.pauseCalled, jc.IsTrue)
+	c.Assert(fakeAPI.findToolsCalled, jc.IsFalse)
+	c.Assert(fakeAPI.setVersionCalledWith, gc.Equals, version.Number{})
+
+	fakeAPI.reset()
+	cmd = &UpgradeJujuCommand{}
+	err = coretesting.InitCommand(envcmd.Wrap(cmd), []string{"--resume"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = cmd.Run(coretesting.Context(c))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(fakeAPI.resumeCalled, jc.IsTrue)
+}
+
 func NewFakeUpgradeJujuAPI(c *gc.C, st *state.State) *fakeUpgradeJujuAPI {
 	nextVersion := version.Current
 	nextVersion.Minor++
@@ -694,6 +740,9 @@ type fakeUpgradeJujuAPI struct {
 	setVersionErr             error
 	abortCurrentUpgradeCalled bool
 	setVersionCalledWith      version.Number
+	setVersionBatchSize       int
+	pauseCalled               bool
+	resumeCalled              bool
 	tools                     []string
 	findToolsCalled           bool
 }
@@ -702,6 +751,9 @@ func (a *fakeUpgradeJujuAPI) reset() {
 	a.setVersionErr = nil
 	a.abortCurrentUpgradeCalled = false
 	a.setVersionCalledWith = version.Number{}
+	a.setVersionBatchSize = 0
+	a.pauseCalled = false
+	a.resumeCalled = false
 	a.tools = []string{}
 	a.findToolsCalled = false
 }
@@ -754,6 +806,22 @@ func (a *fakeUpgradeJujuAPI) SetEnvironAgentVersion(v version.Number) error {
 	return a.setVersionErr
 }
 
+func (a *fakeUpgradeJujuAPI) SetEnvironAgentVersionRolling(v version.Number, batchSize int) error {
+	a.setVersionCalledWith = v
+	a.setVersionBatchSize = batchSize
+	return a.setVersionErr
+}
+
+func (a *fakeUpgradeJujuAPI) PauseRollingUpgrade() error {
+	a.pauseCalled = true
+	return nil
+}
+
+func (a *fakeUpgradeJujuAPI) ResumeRollingUpgrade() error {
+	a.resumeCalled = true
+	return nil
+}
+
 func (a *fakeUpgradeJujuAPI) Close() error {
 	return nil
 }
@@ -93,12 +93,43 @@ networks
    network. Positive network constraints do not imply the networks will be enabled,
    use the --networks argument for that, just that they could be enabled.
 
+spaces
+   Spaces defines the list of network spaces (see "juju help space") that the
+   machine must have an address in, or must not, for names with a "^" prefix.
+   Multiple spaces must be delimited by a comma. The machine is started in one
+   of the subnets of the required spaces. Example: spaces=internal,^dmz
+
 instance-type
    Instance-type is the provider-specific name of a type of machine to deploy,
    for example m1.small on EC2 or A4 on Azure.  Specifying this constraint may
    conflict with other constraints depending on the provider (since the instance
    type my determine things like memory size etc.)
 
+virt-type
+   Virt-type is the type of virtualisation the machine must use, for example
+   hvm or pv on EC2.  Instance types and images whose virtualisation type is
+   not known are assumed to match.  Currently only supported by the Amazon EC2
+   and GCE environments; all GCE machines use kvm.
+
+local-disks
+   Local-disks is a whole number that defines the minimum number of local
+   (instance store) disks the machine must have in addition to its root disk.
+   Currently only supported by the Amazon EC2 environment.  GCE local SSDs are
+   attached to a machine separately from its machine type, so they cannot be
+   selected by this constraint.
+
+accelerators
+   Accelerators is a whole number that defines the minimum number of GPU or
+   other accelerator devices the machine must have.  Currently only supported
+   by the Amazon EC2 environment.  GCE GPUs are attached to a machine
+   separately from its machine type, so they cannot be selected by this
+   constraint.
+
+accelerator-type
+   Accelerator-type is the provider-specific name of the type of accelerator
+   the machine must have, for example grid-k520 on EC2.  It implies at least
+   one accelerator, so may not be combined with accelerators=0.
+
 Example:
 
    juju add-machine --constraints "arch=amd64 mem=8G tags=foo,^bar"
@@ -24,6 +24,27 @@ func NewRemoveCommand(api RemoveMachineAPI) *RemoveCommand {
 	}
 }
 
+// NewPoolSetCommand returns a PoolSetCommand with the api provided as specified.
+func NewPoolSetCommand(api PoolAPI) *PoolSetCommand {
+	return &PoolSetCommand{
+		api: api,
+	}
+}
+
+// NewPoolListCommand returns a PoolListCommand with the api provided as specified.
+func NewPoolListCommand(api PoolAPI) *PoolListCommand {
+	return &PoolListCommand{
+		api: api,
+	}
+}
+
+// NewPoolRemoveCommand returns a PoolRemoveCommand with the api provided as specified.
+func NewPoolRemoveCommand(api PoolAPI) *PoolRemoveCommand {
+	return &PoolRemoveCommand{
+		api: api,
+	}
+}
+
 func NewDisksFlag(disks *[]storage.Constraints) *disksFlag {
 	return &disksFlag{disks}
 }
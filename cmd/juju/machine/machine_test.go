@@ -22,6 +22,7 @@ var _ = gc.Suite(&MachineCommandSuite{})
 var expectedCommmandNames = []string{
 	"add",
 	"help",
+	"pool",
 	"remove",
 }
 
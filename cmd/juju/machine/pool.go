@@ -0,0 +1,165 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine
+
+import (
+	"strconv"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/constraints"
+)
+
+const poolCommandDoc = `
+"juju machine pool" manages pools of spare machines. The provisioner keeps
+each pool topped up with clean machines, started with the pool's constraints
+and series, that have no units. When a unit is assigned to a machine, a spare
+machine that satisfies the unit's constraints is preferred to any other, so
+the unit does not have to wait for a new instance to boot. A spare machine
+leaves its pool once a unit is assigned to it, and is then replaced.
+`
+
+const poolCommandPurpose = "manage spare machine pools"
+
+// NewPoolSuperCommand creates the machine pool super subcommand and
+// registers the subcommands that it supports.
+func NewPoolSuperCommand() cmd.Command {
+	poolCmd := jujucmd.NewSubSuperCommand(cmd.SuperCommandParams{
+		Name:        "pool",
+		Doc:         poolCommandDoc,
+		UsagePrefix: "juju machine",
+		Purpose:     poolCommandPurpose,
+	})
+	poolCmd.Register(envcmd.Wrap(&PoolSetCommand{}))
+	poolCmd.Register(envcmd.Wrap(&PoolListCommand{}))
+	poolCmd.Register(envcmd.Wrap(&PoolRemoveCommand{}))
+	return poolCmd
+}
+
+const poolSetDoc = `
+Creates a pool of spare machines, or changes the size, series and constraints
+of an existing pool. Spare machines already in the pool keep the series and
+constraints they were started with; surplus spares are removed when the size
+is reduced. If no series is specified, the environment's default series is
+used.
+
+Examples:
+    juju machine pool set web 3 --constraints "mem=4G cpu-cores=2"
+    juju machine pool set web 0
+
+See Also:
+   juju help constraints
+   juju machine pool remove
+`
+
+// PoolSetCommand creates or changes a spare machine pool.
+type PoolSetCommand struct {
+	envcmd.EnvCommandBase
+	api  PoolAPI
+	Pool params.MachinePool
+}
+
+func (c *PoolSetCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "set",
+		Args:    "<pool> <size>",
+		Purpose: "create or resize a spare machine pool",
+		Doc:     poolSetDoc,
+	}
+}
+
+func (c *PoolSetCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.Pool.Series, "series", "", "the series of the pool's machines")
+	f.Var(constraints.ConstraintsValue{Target: &c.Pool.Constraints}, "constraints", "constraints of the pool's machines")
+}
+
+func (c *PoolSetCommand) Init(args []string) error {
+	switch len(args) {
+	case 0:
+		return errors.New("no pool name specified")
+	case 1:
+		return errors.New("no pool size specified")
+	}
+	c.Pool.Name = args[0]
+	size, err := strconv.Atoi(args[1])
+	if err != nil || size < 0 {
+		return errors.Errorf("invalid pool size %q", args[1])
+	}
+	c.Pool.Size = size
+	return cmd.CheckEmpty(args[2:])
+}
+
+// PoolAPI defines the methods on the client API that the machine pool
+// commands call.
+type PoolAPI interface {
+	Close() error
+	MachinePools() ([]params.MachinePool, error)
+	SetMachinePool(pool params.MachinePool) error
+	RemoveMachinePool(name string) error
+}
+
+func getPoolAPI(api PoolAPI, c *envcmd.EnvCommandBase) (PoolAPI, error) {
+	if api != nil {
+		return api, nil
+	}
+	return c.NewAPIClient()
+}
+
+// Run creates or changes the pool.
+func (c *PoolSetCommand) Run(_ *cmd.Context) error {
+	api, err := getPoolAPI(c.api, &c.EnvCommandBase)
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+	return block.ProcessBlockedError(api.SetMachinePool(c.Pool), block.BlockChange)
+}
+
+const poolRemoveDoc = `
+Removes a pool of spare machines, and the pool's remaining spare machines.
+Machines that units have been assigned to are not affected.
+
+Examples:
+    juju machine pool remove web
+`
+
+// PoolRemoveCommand removes a spare machine pool.
+type PoolRemoveCommand struct {
+	envcmd.EnvCommandBase
+	api  PoolAPI
+	Name string
+}
+
+func (c *PoolRemoveCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "remove",
+		Args:    "<pool>",
+		Purpose: "remove a spare machine pool",
+		Doc:     poolRemoveDoc,
+	}
+}
+
+func (c *PoolRemoveCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no pool name specified")
+	}
+	c.Name = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+// Run removes the pool.
+func (c *PoolRemoveCommand) Run(_ *cmd.Context) error {
+	api, err := getPoolAPI(c.api, &c.EnvCommandBase)
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+	return block.ProcessBlockedError(api.RemoveMachinePool(c.Name), block.BlockRemove)
+}
@@ -0,0 +1,172 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine_test
+
+import (
+	"strings"
+
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/machine"
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/testing"
+)
+
+type PoolSuite struct {
+	testing.FakeJujuHomeSuite
+	fake *fakePoolAPI
+}
+
+var _ = gc.Suite(&PoolSuite{})
+
+func (s *PoolSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.fake = &fakePoolAPI{}
+}
+
+func (s *PoolSuite) TestSetInit(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		pool params.MachinePool
+		err  string
+	}{{
+		err: "no pool name specified",
+	}, {
+		args: []string{"web"},
+		err:  "no pool size specified",
+	}, {
+		args: []string{"web", "many"},
+		err:  `invalid pool size "many"`,
+	}, {
+		args: []string{"web", "-1"},
+		err:  `invalid pool size "-1"`,
+	}, {
+		args: []string{"web", "2", "3"},
+		err:  `unrecognized args: \["3"\]`,
+	}, {
+		args: []string{"web", "2"},
+		pool: params.MachinePool{Name: "web", Size: 2},
+	}, {
+		args: []string{"web", "0", "--series", "trusty", "--constraints", "mem=4G"},
+		pool: params.MachinePool{
+			Name:        "web",
+			Series:      "trusty",
+			Constraints: constraints.MustParse("mem=4G"),
+		},
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		setCmd := &machine.PoolSetCommand{}
+		err := testing.InitCommand(setCmd, test.args)
+		if test.err == "" {
+			c.Check(err, jc.ErrorIsNil)
+			c.Check(setCmd.Pool, jc.DeepEquals, test.pool)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.err)
+		}
+	}
+}
+
+func (s *PoolSuite) TestSet(c *gc.C) {
+	setCmd := machine.NewPoolSetCommand(s.fake)
+	_, err := testing.RunCommand(c, envcmd.Wrap(setCmd), "web", "2", "--constraints", "mem=4G")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.set, jc.DeepEquals, params.MachinePool{
+		Name:        "web",
+		Size:        2,
+		Constraints: constraints.MustParse("mem=4G"),
+	})
+}
+
+func (s *PoolSuite) TestSetBlocked(c *gc.C) {
+	s.fake.err = common.ErrOperationBlocked("TestSetBlocked")
+	setCmd := machine.NewPoolSetCommand(s.fake)
+	_, err := testing.RunCommand(c, envcmd.Wrap(setCmd), "web", "2")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+	// msg is logged
+	stripped := strings.Replace(c.GetTestLog(), "\n", "", -1)
+	c.Assert(stripped, gc.Matches, ".*TestSetBlocked.*")
+}
+
+func (s *PoolSuite) TestRemove(c *gc.C) {
+	removeCmd := machine.NewPoolRemoveCommand(s.fake)
+	_, err := testing.RunCommand(c, envcmd.Wrap(removeCmd), "web")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.removed, gc.Equals, "web")
+
+	_, err = testing.RunCommand(c, envcmd.Wrap(machine.NewPoolRemoveCommand(s.fake)))
+	c.Assert(err, gc.ErrorMatches, "no pool name specified")
+}
+
+func (s *PoolSuite) TestList(c *gc.C) {
+	s.fake.pools = []params.MachinePool{{
+		Name:        "web",
+		Size:        2,
+		Series:      "trusty",
+		Constraints: constraints.MustParse("mem=4G"),
+		Spares:      []string{"3", "4"},
+	}, {
+		Name:        "db",
+		Size:        1,
+		Series:      "precise",
+		Constraints: constraints.MustParse("cpu-cores=2"),
+		Spares:      []string{"5"},
+	}}
+	listCmd := machine.NewPoolListCommand(s.fake)
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(listCmd))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, `
+db:
+  size: 1
+  series: precise
+  constraints: cpu-cores=2
+  spares:
+  - "5"
+web:
+  size: 2
+  series: trusty
+  constraints: mem=4096M
+  spares:
+  - "3"
+  - "4"
+`[1:])
+
+	listCmd = machine.NewPoolListCommand(s.fake)
+	ctx, err = testing.RunCommand(c, envcmd.Wrap(listCmd), "--format", "tabular")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, `
+NAME  SIZE  SERIES   SPARES  CONSTRAINTS
+db    1     precise  5       cpu-cores=2
+web   2     trusty   3,4     mem=4096M
+`[1:])
+}
+
+type fakePoolAPI struct {
+	pools   []params.MachinePool
+	set     params.MachinePool
+	removed string
+	err     error
+}
+
+func (f *fakePoolAPI) Close() error {
+	return nil
+}
+
+func (f *fakePoolAPI) MachinePools() ([]params.MachinePool, error) {
+	return f.pools, f.err
+}
+
+func (f *fakePoolAPI) SetMachinePool(pool params.MachinePool) error {
+	f.set = pool
+	return f.err
+}
+
+func (f *fakePoolAPI) RemoveMachinePool(name string) error {
+	f.removed = name
+	return f.err
+}
@@ -0,0 +1,122 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+const poolListDoc = `
+Lists the pools of spare machines, with the size, series and constraints of
+each, and the ids of the spare machines currently in it.
+`
+
+// PoolListCommand lists the spare machine pools.
+type PoolListCommand struct {
+	envcmd.EnvCommandBase
+	api PoolAPI
+	out cmd.Output
+}
+
+// PoolInfo defines the serialization behaviour of spare machine pools.
+type PoolInfo struct {
+	Size        int      `yaml:"size" json:"size"`
+	Series      string   `yaml:"series" json:"series"`
+	Constraints string   `yaml:"constraints,omitempty" json:"constraints,omitempty"`
+	Spares      []string `yaml:"spares,omitempty" json:"spares,omitempty"`
+}
+
+func (c *PoolListCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "list",
+		Purpose: "list spare machine pools",
+		Doc:     poolListDoc,
+	}
+}
+
+func (c *PoolListCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatPoolListTabular,
+	})
+}
+
+func (c *PoolListCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+// Run lists the pools.
+func (c *PoolListCommand) Run(ctx *cmd.Context) error {
+	api, err := getPoolAPI(c.api, &c.EnvCommandBase)
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	pools, err := api.MachinePools()
+	if err != nil {
+		return err
+	}
+	if len(pools) == 0 {
+		return nil
+	}
+	output := make(map[string]PoolInfo)
+	for _, pool := range pools {
+		output[pool.Name] = PoolInfo{
+			Size:        pool.Size,
+			Series:      pool.Series,
+			Constraints: pool.Constraints.String(),
+			Spares:      pool.Spares,
+		}
+	}
+	return c.out.Write(ctx, output)
+}
+
+// formatPoolListTabular returns a tabular summary of spare machine
+// pools, or errors out if value is not a map of PoolInfo.
+func formatPoolListTabular(value interface{}) ([]byte, error) {
+	pools, ok := value.(map[string]PoolInfo)
+	if !ok {
+		return nil, errors.Errorf("expected value of type %T, got %T", pools, value)
+	}
+	var out bytes.Buffer
+	const (
+		// To format things into columns.
+		minwidth = 0
+		tabwidth = 1
+		padding  = 2
+		padchar  = ' '
+		flags    = 0
+	)
+	tw := tabwriter.NewWriter(&out, minwidth, tabwidth, padding, padchar, flags)
+	print := func(values ...string) {
+		fmt.Fprintln(tw, strings.Join(values, "\t"))
+	}
+
+	print("NAME", "SIZE", "SERIES", "SPARES", "CONSTRAINTS")
+	poolNames := make([]string, 0, len(pools))
+	for name := range pools {
+		poolNames = append(poolNames, name)
+	}
+	sort.Strings(poolNames)
+	for _, name := range poolNames {
+		pool := pools[name]
+		print(name, fmt.Sprint(pool.Size), pool.Series, strings.Join(pool.Spares, ","), pool.Constraints)
+	}
+	tw.Flush()
+
+	return out.Bytes(), nil
+}
@@ -32,7 +32,7 @@ type UnitCommandBase struct {
 
 func (c *UnitCommandBase) SetFlags(f *gnuflag.FlagSet) {
 	f.IntVar(&c.NumUnits, "num-units", 1, "")
-	f.StringVar(&c.PlacementSpec, "to", "", "the machine, container or placement directive to deploy the unit in, bypasses constraints")
+	f.StringVar(&c.PlacementSpec, "to", "", "comma-separated machines, containers or placement directives to deploy successive units in, bypasses constraints")
 }
 
 func (c *UnitCommandBase) Init(args []string) error {
@@ -48,6 +48,10 @@ func (c *UnitCommandBase) Init(args []string) error {
 		placementSpecs := strings.Split(c.PlacementSpec, ",")
 		c.Placement = make([]*instance.Placement, len(placementSpecs))
 		for i, spec := range placementSpecs {
+			spec = strings.TrimSpace(spec)
+			if spec == "" {
+				return errors.Errorf("invalid --to parameter %q: empty placement directive", c.PlacementSpec)
+			}
 			placement, err := parsePlacement(spec)
 			if err != nil {
 				return errors.Errorf("invalid --to parameter %q", spec)
@@ -123,6 +127,18 @@ Examples:
  juju service add-unit mysql --to 23       (Add a mysql unit to machine 23)
  juju service add-unit mysql --to 24/lxc/3 (Add unit to lxc container 3 on host machine 24)
  juju service add-unit mysql --to lxc:25   (Add unit to a new lxc container on host machine 25)
+ juju service add-unit mysql --to zone=us-east-1a
+                                          (Add unit to a new machine in availability zone us-east-1a)
+ juju service add-unit mysql -n 3 --to lxc:3,zone=us-east-1a,kvm:new
+                                          (Add a unit to a new lxc container on machine 3,
+                                           a unit to a new machine in zone us-east-1a and
+                                           a unit to a new kvm container on a new machine)
+
+The --to argument takes a comma-separated list of placement directives, which
+are applied to successive units; units without a directive are placed on new
+machines. "<container>:new" places a unit in a new container on a new machine,
+and "zone=<zone>" places it on a new machine in the named availability zone,
+for providers that support availability zones.
 `
 
 func (c *AddUnitCommand) Info() *cmd.Info {
@@ -103,6 +103,9 @@ var initAddUnitErrorTests = []struct {
 	}, {
 		args: []string{"some-service-name", "--to", "1,#:foo"},
 		err:  `invalid --to parameter "#:foo"`,
+	}, {
+		args: []string{"some-service-name", "--to", "1,,lxc:2"},
+		err:  `invalid --to parameter "1,,lxc:2": empty placement directive`,
 	},
 }
 
@@ -164,6 +167,18 @@ func (s *AddUnitSuite) TestAddUnitWithPlacement(c *gc.C) {
 	})
 }
 
+func (s *AddUnitSuite) TestAddUnitWithMultipleTargets(c *gc.C) {
+	s.fake.newAPI = true
+	err := s.runAddUnit(c, "-n", "3", "--to", "lxc:3, zone=us-east-1a,kvm:new", "some-service-name")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.numUnits, gc.Equals, 4)
+	c.Assert(s.fake.placement, jc.DeepEquals, []*instance.Placement{
+		{"lxc", "3"},
+		{"fake-uuid", "zone=us-east-1a"},
+		{"kvm", ""},
+	})
+}
+
 func (s *AddUnitSuite) TestBlockAddUnit(c *gc.C) {
 	// Block operation
 	s.fake.err = common.ErrOperationBlocked("TestBlockAddUnit")
@@ -30,3 +30,27 @@ func NewAddUnitCommand(api ServiceAddUnitAPI) *AddUnitCommand {
 		api: api,
 	}
 }
+
+// NewSetHookRetryPolicyCommand returns a SetHookRetryPolicyCommand with
+// the api provided as specified.
+func NewSetHookRetryPolicyCommand(api SetHookRetryPolicyAPI) *SetHookRetryPolicyCommand {
+	return &SetHookRetryPolicyCommand{
+		api: api,
+	}
+}
+
+// NewSetPlacementPolicyCommand returns a SetPlacementPolicyCommand with
+// the api provided as specified.
+func NewSetPlacementPolicyCommand(api SetPlacementPolicyAPI) *SetPlacementPolicyCommand {
+	return &SetPlacementPolicyCommand{
+		api: api,
+	}
+}
+
+// NewSetScalingCommand returns a SetScalingCommand with the api
+// provided as specified.
+func NewSetScalingCommand(api SetScalingAPI) *SetScalingCommand {
+	return &SetScalingCommand{
+		api: api,
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package service
+
+import (
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/block"
+)
+
+const setHookRetryPolicyDoc = `
+Sets how the units of a service retry failed hooks. By default a unit whose
+hook fails waits in an error state until "juju resolved" is run; with a retry
+policy the failed hook is retried up to --max-attempts times before the unit
+waits to be resolved.
+
+The first retry happens after --delay, and the delay doubles after each failed
+attempt up to --max-delay. Each retry is recorded in the unit's status history.
+Setting --max-attempts to 0 disables automatic retries.
+
+Units read the policy again before each retry, so a changed policy applies to
+hooks that are already failing. Retries already made count against the new
+--max-attempts; "juju resolved --retry" starts counting again.
+
+Examples:
+    juju service set-hook-retry-policy mysql --max-attempts 5 --delay 30s
+    juju service set-hook-retry-policy mysql --max-attempts 3 --delay 10s --max-delay 1m
+    juju service set-hook-retry-policy mysql --max-attempts 0
+
+See Also:
+   juju help resolved
+   juju help status-history
+`
+
+// SetHookRetryPolicyCommand sets the hook retry policy of a service.
+type SetHookRetryPolicyCommand struct {
+	envcmd.EnvCommandBase
+	ServiceName string
+	Policy      params.HookRetryPolicy
+	api         SetHookRetryPolicyAPI
+}
+
+func (c *SetHookRetryPolicyCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "set-hook-retry-policy",
+		Args:    "<service>",
+		Purpose: "set how a service's units retry failed hooks",
+		Doc:     setHookRetryPolicyDoc,
+	}
+}
+
+func (c *SetHookRetryPolicyCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.IntVar(&c.Policy.MaxAttempts, "max-attempts", 3, "maximum number of times a failed hook is retried")
+	f.DurationVar(&c.Policy.Delay, "delay", 30*time.Second, "time to wait before the first retry")
+	f.DurationVar(&c.Policy.MaxDelay, "max-delay", 0, "maximum time to wait between retries (0 for no limit)")
+}
+
+func (c *SetHookRetryPolicyCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no service name specified")
+	}
+	if !names.IsValidService(args[0]) {
+		return errors.Errorf("invalid service name %q", args[0])
+	}
+	c.ServiceName = args[0]
+	if c.Policy.MaxAttempts < 0 {
+		return errors.New("--max-attempts must not be negative")
+	}
+	if c.Policy.MaxAttempts == 0 {
+		// Disable retries altogether.
+		c.Policy = params.HookRetryPolicy{}
+	}
+	return cmd.CheckEmpty(args[1:])
+}
+
+// SetHookRetryPolicyAPI defines the methods on the client API
+// that the service set-hook-retry-policy command calls.
+type SetHookRetryPolicyAPI interface {
+	Close() error
+	ServiceUpdate(args params.ServiceUpdate) error
+}
+
+func (c *SetHookRetryPolicyCommand) getAPI() (SetHookRetryPolicyAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	return c.NewAPIClient()
+}
+
+// Run sets the hook retry policy of the service.
+func (c *SetHookRetryPolicyCommand) Run(_ *cmd.Context) error {
+	api, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	policy := c.Policy
+	err = api.ServiceUpdate(params.ServiceUpdate{
+		ServiceName:     c.ServiceName,
+		HookRetryPolicy: &policy,
+	})
+	return block.ProcessBlockedError(err, block.BlockChange)
+}
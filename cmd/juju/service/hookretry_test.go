@@ -0,0 +1,107 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package service_test
+
+import (
+	"strings"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/service"
+	"github.com/juju/juju/testing"
+)
+
+type SetHookRetryPolicySuite struct {
+	testing.FakeJujuHomeSuite
+	fake *fakeServiceUpdateAPI
+}
+
+var _ = gc.Suite(&SetHookRetryPolicySuite{})
+
+type fakeServiceUpdateAPI struct {
+	args params.ServiceUpdate
+	err  error
+}
+
+func (f *fakeServiceUpdateAPI) Close() error {
+	return nil
+}
+
+func (f *fakeServiceUpdateAPI) ServiceUpdate(args params.ServiceUpdate) error {
+	f.args = args
+	return f.err
+}
+
+func (s *SetHookRetryPolicySuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.fake = &fakeServiceUpdateAPI{}
+}
+
+func (s *SetHookRetryPolicySuite) TestInitErrors(c *gc.C) {
+	for i, t := range []struct {
+		args []string
+		err  string
+	}{{
+		args: []string{},
+		err:  "no service name specified",
+	}, {
+		args: []string{"Mysql"},
+		err:  `invalid service name "Mysql"`,
+	}, {
+		args: []string{"mysql", "--max-attempts", "-1"},
+		err:  "--max-attempts must not be negative",
+	}, {
+		args: []string{"mysql", "extra"},
+		err:  `unrecognized args: \["extra"\]`,
+	}} {
+		c.Logf("test %d: %v", i, t.args)
+		err := testing.InitCommand(envcmd.Wrap(service.NewSetHookRetryPolicyCommand(s.fake)), t.args)
+		c.Check(err, gc.ErrorMatches, t.err)
+	}
+}
+
+func (s *SetHookRetryPolicySuite) TestSetHookRetryPolicy(c *gc.C) {
+	for i, t := range []struct {
+		args   []string
+		policy params.HookRetryPolicy
+	}{{
+		args: []string{"mysql"},
+		policy: params.HookRetryPolicy{
+			MaxAttempts: 3,
+			Delay:       30 * time.Second,
+		},
+	}, {
+		args: []string{"mysql", "--max-attempts", "5", "--delay", "10s", "--max-delay", "2m"},
+		policy: params.HookRetryPolicy{
+			MaxAttempts: 5,
+			Delay:       10 * time.Second,
+			MaxDelay:    2 * time.Minute,
+		},
+	}, {
+		args:   []string{"mysql", "--max-attempts", "0", "--delay", "10s"},
+		policy: params.HookRetryPolicy{},
+	}} {
+		c.Logf("test %d: %v", i, t.args)
+		_, err := testing.RunCommand(c, envcmd.Wrap(service.NewSetHookRetryPolicyCommand(s.fake)), t.args...)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(s.fake.args, jc.DeepEquals, params.ServiceUpdate{
+			ServiceName:     "mysql",
+			HookRetryPolicy: &t.policy,
+		})
+	}
+}
+
+func (s *SetHookRetryPolicySuite) TestBlockSetHookRetryPolicy(c *gc.C) {
+	s.fake.err = common.ErrOperationBlocked("TestBlockSetHookRetryPolicy")
+	testing.RunCommand(c, envcmd.Wrap(service.NewSetHookRetryPolicyCommand(s.fake)), "mysql")
+
+	// msg is logged
+	stripped := strings.Replace(c.GetTestLog(), "\n", "", -1)
+	c.Check(stripped, gc.Matches, ".*TestBlockSetHookRetryPolicy.*")
+}
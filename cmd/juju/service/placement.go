@@ -0,0 +1,113 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package service
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/block"
+)
+
+const setPlacementPolicyDoc = `
+Sets where the units of a service may be placed relative to the units of other
+services. The policy is evaluated whenever a unit of the service is assigned to
+a machine, and by the provisioner when choosing the availability zone of a new
+machine.
+
+--anti-affinity names services whose units must never share a machine with the
+service's units. --affinity names services that must have a unit on every
+machine hosting the service's units; new units are placed on such machines
+where possible. --max-units-per-zone limits the number of the service's units
+in any one availability zone.
+
+Units placed before the policy was set are not moved; any placement that
+violates the policy is reported under "placement-violations" in "juju status".
+Running the command with no policy flags removes all restrictions.
+
+Examples:
+    juju service set-placement-policy mysql --anti-affinity mongodb,cassandra
+    juju service set-placement-policy wordpress --affinity memcached
+    juju service set-placement-policy mysql --max-units-per-zone 1
+    juju service set-placement-policy mysql
+
+See Also:
+   juju help status
+`
+
+// SetPlacementPolicyCommand sets the placement policy of a service.
+type SetPlacementPolicyCommand struct {
+	envcmd.EnvCommandBase
+	ServiceName string
+	Policy      params.PlacementPolicy
+	api         SetPlacementPolicyAPI
+}
+
+func (c *SetPlacementPolicyCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "set-placement-policy",
+		Args:    "<service>",
+		Purpose: "set where a service's units may be placed",
+		Doc:     setPlacementPolicyDoc,
+	}
+}
+
+func (c *SetPlacementPolicyCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.Var(cmd.NewStringsValue(nil, &c.Policy.AntiAffinity), "anti-affinity", "services whose units must not share a machine with the service's units")
+	f.Var(cmd.NewStringsValue(nil, &c.Policy.Affinity), "affinity", "services whose units must share a machine with the service's units")
+	f.IntVar(&c.Policy.MaxUnitsPerZone, "max-units-per-zone", 0, "maximum number of the service's units in an availability zone (0 for no limit)")
+}
+
+func (c *SetPlacementPolicyCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no service name specified")
+	}
+	if !names.IsValidService(args[0]) {
+		return errors.Errorf("invalid service name %q", args[0])
+	}
+	c.ServiceName = args[0]
+	for _, name := range append(c.Policy.AntiAffinity, c.Policy.Affinity...) {
+		if !names.IsValidService(name) {
+			return errors.Errorf("invalid service name %q", name)
+		}
+	}
+	if c.Policy.MaxUnitsPerZone < 0 {
+		return errors.New("--max-units-per-zone must not be negative")
+	}
+	return cmd.CheckEmpty(args[1:])
+}
+
+// SetPlacementPolicyAPI defines the methods on the client API
+// that the service set-placement-policy command calls.
+type SetPlacementPolicyAPI interface {
+	Close() error
+	ServiceUpdate(args params.ServiceUpdate) error
+}
+
+func (c *SetPlacementPolicyCommand) getAPI() (SetPlacementPolicyAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	return c.NewAPIClient()
+}
+
+// Run sets the placement policy of the service.
+func (c *SetPlacementPolicyCommand) Run(_ *cmd.Context) error {
+	api, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	policy := c.Policy
+	err = api.ServiceUpdate(params.ServiceUpdate{
+		ServiceName:     c.ServiceName,
+		PlacementPolicy: &policy,
+	})
+	return block.ProcessBlockedError(err, block.BlockChange)
+}
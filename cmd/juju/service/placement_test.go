@@ -0,0 +1,93 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package service_test
+
+import (
+	"strings"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/service"
+	"github.com/juju/juju/testing"
+)
+
+type SetPlacementPolicySuite struct {
+	testing.FakeJujuHomeSuite
+	fake *fakeServiceUpdateAPI
+}
+
+var _ = gc.Suite(&SetPlacementPolicySuite{})
+
+func (s *SetPlacementPolicySuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.fake = &fakeServiceUpdateAPI{}
+}
+
+func (s *SetPlacementPolicySuite) TestInitErrors(c *gc.C) {
+	for i, t := range []struct {
+		args []string
+		err  string
+	}{{
+		args: []string{},
+		err:  "no service name specified",
+	}, {
+		args: []string{"Mysql"},
+		err:  `invalid service name "Mysql"`,
+	}, {
+		args: []string{"mysql", "--anti-affinity", "mongodb,Cassandra"},
+		err:  `invalid service name "Cassandra"`,
+	}, {
+		args: []string{"mysql", "--max-units-per-zone", "-1"},
+		err:  "--max-units-per-zone must not be negative",
+	}, {
+		args: []string{"mysql", "extra"},
+		err:  `unrecognized args: \["extra"\]`,
+	}} {
+		c.Logf("test %d: %v", i, t.args)
+		err := testing.InitCommand(envcmd.Wrap(service.NewSetPlacementPolicyCommand(s.fake)), t.args)
+		c.Check(err, gc.ErrorMatches, t.err)
+	}
+}
+
+func (s *SetPlacementPolicySuite) TestSetPlacementPolicy(c *gc.C) {
+	for i, t := range []struct {
+		args   []string
+		policy params.PlacementPolicy
+	}{{
+		args:   []string{"mysql"},
+		policy: params.PlacementPolicy{},
+	}, {
+		args: []string{"mysql", "--anti-affinity", "mongodb,cassandra", "--affinity", "memcached"},
+		policy: params.PlacementPolicy{
+			AntiAffinity: []string{"mongodb", "cassandra"},
+			Affinity:     []string{"memcached"},
+		},
+	}, {
+		args: []string{"mysql", "--max-units-per-zone", "2"},
+		policy: params.PlacementPolicy{
+			MaxUnitsPerZone: 2,
+		},
+	}} {
+		c.Logf("test %d: %v", i, t.args)
+		_, err := testing.RunCommand(c, envcmd.Wrap(service.NewSetPlacementPolicyCommand(s.fake)), t.args...)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(s.fake.args, jc.DeepEquals, params.ServiceUpdate{
+			ServiceName:     "mysql",
+			PlacementPolicy: &t.policy,
+		})
+	}
+}
+
+func (s *SetPlacementPolicySuite) TestBlockSetPlacementPolicy(c *gc.C) {
+	s.fake.err = common.ErrOperationBlocked("TestBlockSetPlacementPolicy")
+	testing.RunCommand(c, envcmd.Wrap(service.NewSetPlacementPolicyCommand(s.fake)), "mysql")
+
+	// msg is logged
+	stripped := strings.Replace(c.GetTestLog(), "\n", "", -1)
+	c.Check(stripped, gc.Matches, ".*TestBlockSetPlacementPolicy.*")
+}
@@ -0,0 +1,122 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package service
+
+import (
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/block"
+)
+
+const setScalingDoc = `
+Sets how a service is scaled according to a metric reported by its units. The
+metric must be declared in the metrics.yaml of the service's charm.
+
+Every minute, the values of --metric reported over the last --window are
+averaged. If the average is above --scale-up-above a unit is added to the
+service, and if it is below --scale-down-below the most recently added unit is
+destroyed. The service is never scaled beyond --min-units and --max-units, and
+after each scaling decision no further decision is taken for --cooldown.
+Each scaling decision is recorded in the service's status history.
+
+Setting --metric to "" disables autoscaling.
+
+Examples:
+    juju service set-scaling wordpress --metric requests --scale-up-above 100 --scale-down-below 20 --max-units 10
+    juju service set-scaling wordpress --metric requests --scale-up-above 100 --window 10m --cooldown 15m
+    juju service set-scaling wordpress --metric ""
+
+See Also:
+   juju help add-unit
+   juju help status-history
+`
+
+// SetScalingCommand sets the scaling policy of a service.
+type SetScalingCommand struct {
+	envcmd.EnvCommandBase
+	ServiceName string
+	Policy      params.ScalingPolicy
+	api         SetScalingAPI
+}
+
+func (c *SetScalingCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "set-scaling",
+		Args:    "<service>",
+		Purpose: "set how a service is scaled according to a metric",
+		Doc:     setScalingDoc,
+	}
+}
+
+func (c *SetScalingCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.Policy.Metric, "metric", "", "charm metric that drives scaling")
+	f.Float64Var(&c.Policy.ScaleUpAbove, "scale-up-above", 0, "average metric value above which a unit is added")
+	f.Float64Var(&c.Policy.ScaleDownBelow, "scale-down-below", 0, "average metric value below which a unit is removed")
+	f.DurationVar(&c.Policy.Window, "window", 5*time.Minute, "period over which the metric is averaged")
+	f.IntVar(&c.Policy.MinUnits, "min-units", 1, "minimum number of units")
+	f.IntVar(&c.Policy.MaxUnits, "max-units", 5, "maximum number of units")
+	f.DurationVar(&c.Policy.Cooldown, "cooldown", 5*time.Minute, "minimum time between scaling decisions")
+}
+
+func (c *SetScalingCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no service name specified")
+	}
+	if !names.IsValidService(args[0]) {
+		return errors.Errorf("invalid service name %q", args[0])
+	}
+	c.ServiceName = args[0]
+	if c.Policy.Metric == "" {
+		// Disable autoscaling altogether.
+		c.Policy = params.ScalingPolicy{}
+		return cmd.CheckEmpty(args[1:])
+	}
+	if c.Policy.ScaleDownBelow >= c.Policy.ScaleUpAbove {
+		return errors.New("--scale-down-below must be less than --scale-up-above")
+	}
+	if c.Policy.MinUnits < 0 {
+		return errors.New("--min-units must not be negative")
+	}
+	if c.Policy.MaxUnits < 1 || c.Policy.MaxUnits < c.Policy.MinUnits {
+		return errors.New("--max-units must be positive and not less than --min-units")
+	}
+	return cmd.CheckEmpty(args[1:])
+}
+
+// SetScalingAPI defines the methods on the client API
+// that the service set-scaling command calls.
+type SetScalingAPI interface {
+	Close() error
+	ServiceUpdate(args params.ServiceUpdate) error
+}
+
+func (c *SetScalingCommand) getAPI() (SetScalingAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	return c.NewAPIClient()
+}
+
+// Run sets the scaling policy of the service.
+func (c *SetScalingCommand) Run(_ *cmd.Context) error {
+	api, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	policy := c.Policy
+	err = api.ServiceUpdate(params.ServiceUpdate{
+		ServiceName:   c.ServiceName,
+		ScalingPolicy: &policy,
+	})
+	return block.ProcessBlockedError(err, block.BlockChange)
+}
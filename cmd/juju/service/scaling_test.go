@@ -0,0 +1,110 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package service_test
+
+import (
+	"strings"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/service"
+	"github.com/juju/juju/testing"
+)
+
+type SetScalingSuite struct {
+	testing.FakeJujuHomeSuite
+	fake *fakeServiceUpdateAPI
+}
+
+var _ = gc.Suite(&SetScalingSuite{})
+
+func (s *SetScalingSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.fake = &fakeServiceUpdateAPI{}
+}
+
+func (s *SetScalingSuite) TestInitErrors(c *gc.C) {
+	for i, t := range []struct {
+		args []string
+		err  string
+	}{{
+		args: []string{},
+		err:  "no service name specified",
+	}, {
+		args: []string{"Wordpress"},
+		err:  `invalid service name "Wordpress"`,
+	}, {
+		args: []string{"wordpress", "--metric", "requests"},
+		err:  "--scale-down-below must be less than --scale-up-above",
+	}, {
+		args: []string{"wordpress", "--metric", "requests", "--scale-up-above", "10", "--min-units", "-1"},
+		err:  "--min-units must not be negative",
+	}, {
+		args: []string{"wordpress", "--metric", "requests", "--scale-up-above", "10", "--min-units", "3", "--max-units", "2"},
+		err:  "--max-units must be positive and not less than --min-units",
+	}, {
+		args: []string{"wordpress", "extra"},
+		err:  `unrecognized args: \["extra"\]`,
+	}} {
+		c.Logf("test %d: %v", i, t.args)
+		err := testing.InitCommand(envcmd.Wrap(service.NewSetScalingCommand(s.fake)), t.args)
+		c.Check(err, gc.ErrorMatches, t.err)
+	}
+}
+
+func (s *SetScalingSuite) TestSetScaling(c *gc.C) {
+	for i, t := range []struct {
+		args   []string
+		policy params.ScalingPolicy
+	}{{
+		args: []string{"wordpress", "--metric", "requests", "--scale-up-above", "100"},
+		policy: params.ScalingPolicy{
+			Metric:       "requests",
+			ScaleUpAbove: 100,
+			Window:       5 * time.Minute,
+			MinUnits:     1,
+			MaxUnits:     5,
+			Cooldown:     5 * time.Minute,
+		},
+	}, {
+		args: []string{
+			"wordpress", "--metric", "requests",
+			"--scale-up-above", "100", "--scale-down-below", "20.5",
+			"--window", "10m", "--min-units", "2", "--max-units", "10", "--cooldown", "0",
+		},
+		policy: params.ScalingPolicy{
+			Metric:         "requests",
+			ScaleUpAbove:   100,
+			ScaleDownBelow: 20.5,
+			Window:         10 * time.Minute,
+			MinUnits:       2,
+			MaxUnits:       10,
+		},
+	}, {
+		args:   []string{"wordpress", "--metric", "", "--scale-up-above", "100"},
+		policy: params.ScalingPolicy{},
+	}} {
+		c.Logf("test %d: %v", i, t.args)
+		_, err := testing.RunCommand(c, envcmd.Wrap(service.NewSetScalingCommand(s.fake)), t.args...)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(s.fake.args, jc.DeepEquals, params.ServiceUpdate{
+			ServiceName:   "wordpress",
+			ScalingPolicy: &t.policy,
+		})
+	}
+}
+
+func (s *SetScalingSuite) TestBlockSetScaling(c *gc.C) {
+	s.fake.err = common.ErrOperationBlocked("TestBlockSetScaling")
+	testing.RunCommand(c, envcmd.Wrap(service.NewSetScalingCommand(s.fake)), "wordpress")
+
+	// msg is logged
+	stripped := strings.Replace(c.GetTestLog(), "\n", "", -1)
+	c.Check(stripped, gc.Matches, ".*TestBlockSetScaling.*")
+}
@@ -32,6 +32,9 @@ func NewSuperCommand() cmd.Command {
 	environmentCmd.Register(envcmd.Wrap(&GetCommand{}))
 	environmentCmd.Register(envcmd.Wrap(&SetCommand{}))
 	environmentCmd.Register(envcmd.Wrap(&UnsetCommand{}))
+	environmentCmd.Register(envcmd.Wrap(&SetHookRetryPolicyCommand{}))
+	environmentCmd.Register(envcmd.Wrap(&SetPlacementPolicyCommand{}))
+	environmentCmd.Register(envcmd.Wrap(&SetScalingCommand{}))
 
 	return environmentCmd
 }
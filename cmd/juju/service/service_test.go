@@ -26,6 +26,9 @@ var expectedCommmandNames = []string{
 	"help",
 	"set",
 	"set-constraints",
+	"set-hook-retry-policy",
+	"set-placement-policy",
+	"set-scaling",
 	"unset",
 }
 
@@ -0,0 +1,67 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package space
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/cmd/juju/block"
+)
+
+const addSubnetCommandDoc = `
+Add subnets to an existing network space.
+
+Subnets are specified by CIDR; subnets not yet known to Juju are added.
+A subnet already in another space cannot be added.
+
+Examples:
+
+  # Add a subnet to the "internal" space.
+  juju space add-subnet internal 10.0.2.0/24
+`
+
+// AddSubnetCommand adds subnets to an existing network space.
+type AddSubnetCommand struct {
+	SpaceCommandBase
+	Name  string
+	CIDRs []string
+}
+
+// Info implements Command.Info.
+func (c *AddSubnetCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "add-subnet",
+		Args:    "<name> <CIDR> [<CIDR> ...]",
+		Purpose: "add subnets to a network space",
+		Doc:     addSubnetCommandDoc,
+	}
+}
+
+// Init implements Command.Init.
+func (c *AddSubnetCommand) Init(args []string) error {
+	switch len(args) {
+	case 0:
+		return errors.New("no space name specified")
+	case 1:
+		return errors.New("no subnets specified")
+	}
+	c.Name = args[0]
+	c.CIDRs = args[1:]
+	return validateCIDRs(c.CIDRs)
+}
+
+// Run implements Command.Run.
+func (c *AddSubnetCommand) Run(ctx *cmd.Context) error {
+	client, err := getSpaceAPI(&c.SpaceCommandBase)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if err := client.AddSubnets(c.Name, c.CIDRs); err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package space
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/network"
+)
+
+const createCommandDoc = `
+Create a new network space, optionally containing the given subnets.
+
+Subnets are specified by CIDR; subnets not yet known to Juju are added.
+A subnet can only be in a single space.
+
+Examples:
+
+  # Create the "internal" space with two subnets.
+  juju space create internal 10.0.0.0/24 10.0.1.0/24
+`
+
+// CreateCommand creates a new network space.
+type CreateCommand struct {
+	SpaceCommandBase
+	Name  string
+	CIDRs []string
+}
+
+// Info implements Command.Info.
+func (c *CreateCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "create",
+		Args:    "<name> [<CIDR> ...]",
+		Purpose: "create a new network space",
+		Doc:     createCommandDoc,
+	}
+}
+
+// Init implements Command.Init.
+func (c *CreateCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no space name specified")
+	}
+	c.Name = args[0]
+	if !network.IsValidSpaceName(c.Name) {
+		return errors.Errorf("%q is not a valid space name", c.Name)
+	}
+	c.CIDRs = args[1:]
+	return validateCIDRs(c.CIDRs)
+}
+
+// Run implements Command.Run.
+func (c *CreateCommand) Run(ctx *cmd.Context) error {
+	client, err := getSpaceAPI(&c.SpaceCommandBase)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if err := client.CreateSpace(c.Name, c.CIDRs); err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	ctx.Infof("created space %q", c.Name)
+	return nil
+}
@@ -0,0 +1,77 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package space
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+	"launchpad.net/gnuflag"
+)
+
+const listCommandDoc = `
+List the network spaces in the Juju environment, with the CIDRs of the
+subnets in each of them.
+`
+
+// ListCommand shows the network spaces in the environment.
+type ListCommand struct {
+	SpaceCommandBase
+	out cmd.Output
+}
+
+// Info implements Command.Info.
+func (c *ListCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "list",
+		Purpose: "list network spaces",
+		Doc:     listCommandDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *ListCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.SpaceCommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
+}
+
+// Init implements Command.Init.
+func (c *ListCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+// SpaceInfo defines the serialization behaviour of a space.
+type SpaceInfo struct {
+	Name    string   `yaml:"name" json:"name"`
+	Subnets []string `yaml:"subnets,omitempty" json:"subnets,omitempty"`
+}
+
+// Run implements Command.Run.
+func (c *ListCommand) Run(ctx *cmd.Context) error {
+	client, err := getSpaceAPI(&c.SpaceCommandBase)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	results, err := client.ListSpaces()
+	if err != nil {
+		return err
+	}
+	if len(results) == 0 {
+		fmt.Fprintf(ctx.Stdout, "no spaces to display\n")
+		return nil
+	}
+	spaces := make([]SpaceInfo, len(results))
+	for i, result := range results {
+		spaces[i] = SpaceInfo{
+			Name:    result.Name,
+			Subnets: result.CIDRs,
+		}
+	}
+	return c.out.Write(ctx, spaces)
+}
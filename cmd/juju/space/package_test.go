@@ -0,0 +1,51 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package space_test
+
+import (
+	"testing"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+func TestPackage(t *testing.T) {
+	gc.TestingT(t)
+}
+
+type fakeSpaceAPI struct {
+	spaces map[string][]string
+	err    error
+}
+
+func (*fakeSpaceAPI) Close() error {
+	return nil
+}
+
+func (f *fakeSpaceAPI) CreateSpace(name string, cidrs []string) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.spaces[name] = cidrs
+	return nil
+}
+
+func (f *fakeSpaceAPI) ListSpaces() ([]params.SpaceSubnets, error) {
+	var result []params.SpaceSubnets
+	for _, name := range []string{"dmz", "internal"} {
+		if cidrs, ok := f.spaces[name]; ok {
+			result = append(result, params.SpaceSubnets{Name: name, CIDRs: cidrs})
+		}
+	}
+	return result, f.err
+}
+
+func (f *fakeSpaceAPI) AddSubnets(name string, cidrs []string) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.spaces[name] = append(f.spaces[name], cidrs...)
+	return nil
+}
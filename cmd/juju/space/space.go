@@ -0,0 +1,73 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package space
+
+import (
+	"net"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/spaces"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+const spaceCommandDoc = `
+"juju space" is used to manage the network spaces in the Juju environment.
+
+A space is a named group of subnets. Machines can be constrained to have
+addresses in particular spaces with the "spaces" constraint, and service
+endpoints can be bound to spaces with "juju deploy --bind".
+`
+
+const spaceCommandPurpose = "manage network spaces"
+
+// NewSuperCommand creates the space supercommand and registers the
+// subcommands that it supports.
+func NewSuperCommand() cmd.Command {
+	spacecmd := cmd.NewSuperCommand(cmd.SuperCommandParams{
+		Name:        "space",
+		Doc:         spaceCommandDoc,
+		UsagePrefix: "juju",
+		Purpose:     spaceCommandPurpose,
+	})
+	spacecmd.Register(envcmd.Wrap(&CreateCommand{}))
+	spacecmd.Register(envcmd.Wrap(&ListCommand{}))
+	spacecmd.Register(envcmd.Wrap(&AddSubnetCommand{}))
+	return spacecmd
+}
+
+// SpaceAPI defines the spaces API methods that the space subcommands
+// use.
+type SpaceAPI interface {
+	CreateSpace(name string, cidrs []string) error
+	ListSpaces() ([]params.SpaceSubnets, error)
+	AddSubnets(name string, cidrs []string) error
+	Close() error
+}
+
+// SpaceCommandBase is a helper base structure that has a method to get
+// the spaces client.
+type SpaceCommandBase struct {
+	envcmd.EnvCommandBase
+}
+
+var getSpaceAPI = func(c *SpaceCommandBase) (SpaceAPI, error) {
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, err
+	}
+	return spaces.NewClient(root), nil
+}
+
+// validateCIDRs checks that all the given values are valid CIDRs.
+func validateCIDRs(values []string) error {
+	for _, value := range values {
+		if _, _, err := net.ParseCIDR(value); err != nil {
+			return errors.Errorf("%q is not a valid CIDR", value)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package space_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/space"
+	"github.com/juju/juju/testing"
+)
+
+type spaceCommandSuite struct {
+	testing.FakeJujuHomeSuite
+	mockAPI *fakeSpaceAPI
+}
+
+var _ = gc.Suite(&spaceCommandSuite{})
+
+func (s *spaceCommandSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.mockAPI = &fakeSpaceAPI{spaces: make(map[string][]string)}
+	s.PatchValue(space.GetSpaceAPI, func(*space.SpaceCommandBase) (space.SpaceAPI, error) {
+		return s.mockAPI, nil
+	})
+}
+
+func (s *spaceCommandSuite) TestCreate(c *gc.C) {
+	_, err := testing.RunCommand(c, envcmd.Wrap(&space.CreateCommand{}), "internal", "10.0.0.0/24", "10.0.1.0/24")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.spaces, jc.DeepEquals, map[string][]string{
+		"internal": {"10.0.0.0/24", "10.0.1.0/24"},
+	})
+}
+
+func (s *spaceCommandSuite) TestCreateInitErrors(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{
+		{nil, "no space name specified"},
+		{[]string{"Internal"}, `"Internal" is not a valid space name`},
+		{[]string{"internal", "10.0.0.0"}, `"10.0.0.0" is not a valid CIDR`},
+	} {
+		c.Logf("test %d: %v", i, test.args)
+		err := testing.InitCommand(envcmd.Wrap(&space.CreateCommand{}), test.args)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *spaceCommandSuite) TestCreateBlocked(c *gc.C) {
+	s.mockAPI.err = common.ErrOperationBlocked("TestCreateBlocked")
+	_, err := testing.RunCommand(c, envcmd.Wrap(&space.CreateCommand{}), "internal")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+}
+
+func (s *spaceCommandSuite) TestAddSubnet(c *gc.C) {
+	s.mockAPI.spaces["internal"] = []string{"10.0.0.0/24"}
+	_, err := testing.RunCommand(c, envcmd.Wrap(&space.AddSubnetCommand{}), "internal", "10.0.1.0/24")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.spaces["internal"], jc.DeepEquals, []string{"10.0.0.0/24", "10.0.1.0/24"})
+
+	s.mockAPI.err = errors.New("boom")
+	_, err = testing.RunCommand(c, envcmd.Wrap(&space.AddSubnetCommand{}), "internal", "10.0.2.0/24")
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *spaceCommandSuite) TestAddSubnetInitErrors(c *gc.C) {
+	err := testing.InitCommand(envcmd.Wrap(&space.AddSubnetCommand{}), []string{"internal"})
+	c.Assert(err, gc.ErrorMatches, "no subnets specified")
+}
+
+func (s *spaceCommandSuite) TestListNone(c *gc.C) {
+	context, err := testing.RunCommand(c, envcmd.Wrap(&space.ListCommand{}))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(context), gc.Equals, "no spaces to display\n")
+}
+
+func (s *spaceCommandSuite) TestList(c *gc.C) {
+	s.mockAPI.spaces["internal"] = []string{"10.0.0.0/24"}
+	s.mockAPI.spaces["dmz"] = nil
+	context, err := testing.RunCommand(c, envcmd.Wrap(&space.ListCommand{}))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(context), gc.Equals, ""+
+		"- name: dmz\n"+
+		"- name: internal\n"+
+		"  subnets:\n"+
+		"  - 10.0.0.0/24\n")
+
+	context, err = testing.RunCommand(c, envcmd.Wrap(&space.ListCommand{}), "--format", "json")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(context), gc.Equals,
+		`[{"name":"dmz"},{"name":"internal","subnets":["10.0.0.0/24"]}]`+"\n")
+}
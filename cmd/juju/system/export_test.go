@@ -13,6 +13,7 @@ import (
 var (
 	SetConfigSpecialCaseDefaults = setConfigSpecialCaseDefaults
 	UserCurrent                  = &userCurrent
+	ReadPassword                 = &readPassword
 )
 
 // NewListCommand returns a ListCommand with the configstore provided as specified.
@@ -4,11 +4,14 @@
 package system
 
 import (
+	"fmt"
+
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
 	"github.com/juju/juju/api/usermanager"
 	"github.com/juju/names"
 	"github.com/juju/utils"
+	"github.com/juju/utils/readpass"
 	goyaml "gopkg.in/yaml.v1"
 	"launchpad.net/gnuflag"
 
@@ -40,6 +43,8 @@ type LoginCommand struct {
 	Server       cmd.FileVar
 	Name         string
 	KeepPassword bool
+	External     bool
+	User         string
 }
 
 var loginDoc = `
@@ -66,6 +71,15 @@ credentials for a system, you should use the --keep-password option as it will
 mean that you will still be able to connect to the api server from the
 computer where you ran api-info.
 
+If the system authenticates users with an external identity provider, such
+as an LDAP directory or an OpenID Connect issuer, use the --external option
+to log in with the password known to that provider. The password is read
+from the terminal rather than the server file, and is never changed by
+juju. The --user option logs in as a different user from the one named in
+the server file:
+
+    juju system login --server=~/system.server --external --user=erica test-system
+
 See Also:
     juju help system environments
     juju help system use-environment
@@ -90,6 +104,8 @@ func (c *LoginCommand) Info() *cmd.Info {
 func (c *LoginCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.Var(&c.Server, "server", "path to yaml-formatted server file")
 	f.BoolVar(&c.KeepPassword, "keep-password", false, "do not generate a new random password")
+	f.BoolVar(&c.External, "external", false, "log in with the password of the external identity provider")
+	f.StringVar(&c.User, "user", "", "log in as this user rather than the user in the server file")
 }
 
 // SetFlags implements Command.Init.
@@ -104,6 +120,10 @@ func (c *LoginCommand) Init(args []string) error {
 		return errors.New("no name specified")
 	}
 
+	if c.User != "" && !c.External {
+		return errors.New("--user can only be used with --external")
+	}
+
 	c.Name, args = args[0], args[1:]
 	return cmd.CheckEmpty(args)
 }
@@ -126,6 +146,23 @@ func (c *LoginCommand) Run(ctx *cmd.Context) error {
 		return errors.Trace(err)
 	}
 
+	if c.External {
+		// The password of an external user is held by the identity
+		// provider, so it is read here rather than taken from the
+		// server file, and is never changed.
+		if c.User != "" {
+			serverDetails.Username = c.User
+		}
+		fmt.Fprintf(ctx.Stdout, "password for %s: ", serverDetails.Username)
+		password, err := readPassword()
+		fmt.Fprint(ctx.Stdout, "\n")
+		if err != nil {
+			return errors.Trace(err)
+		}
+		serverDetails.Password = password
+		c.KeepPassword = true
+	}
+
 	// Construct the api.Info struct from the provided values
 	// and attempt to connect to the remote server before we do anything else.
 	if !names.IsValidUser(serverDetails.Username) {
@@ -174,6 +211,8 @@ func (c *LoginCommand) Run(ctx *cmd.Context) error {
 	return errors.Trace(envcmd.SetCurrentSystem(ctx, c.Name))
 }
 
+var readPassword = readpass.ReadPassword
+
 func (c *LoginCommand) cacheConnectionInfo(serverDetails envcmd.ServerFile, apiState APIConnection) (configstore.EnvironInfo, error) {
 	store, err := configstore.Default()
 	if err != nil {
@@ -90,6 +90,9 @@ func (s *LoginSuite) TestInit(c *gc.C) {
 
 	err = testing.InitCommand(loginCommand, []string{"foo", "bar"})
 	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["bar"\]`)
+
+	err = testing.InitCommand(loginCommand, []string{"foo", "--user", "bob"})
+	c.Assert(err, gc.ErrorMatches, "--user can only be used with --external")
 }
 
 func (s *LoginSuite) TestNoSpecifiedServerFile(c *gc.C) {
@@ -182,6 +185,33 @@ func (s *LoginSuite) TestRemoteUsersKeepPassword(c *gc.C) {
 	c.Assert(creds.Password, gc.Equals, "sekrit")
 }
 
+func (s *LoginSuite) TestExternal(c *gc.C) {
+	s.PatchValue(system.ReadPassword, func() (string, error) {
+		return "ldap-secret", nil
+	})
+	ctx, err := s.runServerFile(c, "--external", "--user", "bob")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, "password for bob: \n")
+
+	c.Assert(s.apiConnection.info.Tag.Id(), gc.Equals, "bob")
+	c.Assert(s.apiConnection.info.Password, gc.Equals, "ldap-secret")
+	c.Assert(s.apiConnection.password, gc.Equals, "")
+	info, err := s.store.ReadInfo("foo")
+	c.Assert(err, jc.ErrorIsNil)
+	creds := info.APICredentials()
+	c.Assert(creds.User, gc.Equals, "bob")
+	c.Assert(creds.Password, gc.Equals, "ldap-secret")
+}
+
+func (s *LoginSuite) TestExternalReadPasswordError(c *gc.C) {
+	s.PatchValue(system.ReadPassword, func() (string, error) {
+		return "", errors.New("no terminal")
+	})
+	_, err := s.runServerFile(c, "--external")
+	c.Assert(err, gc.ErrorMatches, "no terminal")
+	c.Assert(s.apiConnection.info, gc.IsNil)
+}
+
 func (s *LoginSuite) TestConnectsUsingServerFileInfo(c *gc.C) {
 	s.username = "valid-user@local"
 	_, err := s.runServerFile(c)
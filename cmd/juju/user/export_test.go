@@ -46,6 +46,14 @@ func NewEnableCommand(api DisenableUserAPI) *EnableCommand {
 	}
 }
 
+// NewUnlockCommand returns an UnlockCommand with the api provided as
+// specified.
+func NewUnlockCommand(api UnlockUserAPI) *UnlockCommand {
+	return &UnlockCommand{
+		api: api,
+	}
+}
+
 // NewInfoCommand returns an InfoCommand with the api provided as specified.
 func NewInfoCommand(api UserInfoAPI) *InfoCommand {
 	return &InfoCommand{
@@ -63,3 +71,54 @@ func NewListCommand(api UserInfoAPI) *ListCommand {
 		},
 	}
 }
+
+// NewTokenCreateCommand returns a TokenCreateCommand with the api
+// provided as specified.
+func NewTokenCreateCommand(api TokenAPI) *TokenCreateCommand {
+	return &TokenCreateCommand{
+		tokenCommandBase: tokenCommandBase{api: api},
+	}
+}
+
+// NewTokenListCommand returns a TokenListCommand with the api provided
+// as specified.
+func NewTokenListCommand(api TokenAPI) *TokenListCommand {
+	return &TokenListCommand{
+		tokenCommandBase: tokenCommandBase{api: api},
+	}
+}
+
+// NewTokenRevokeCommand returns a TokenRevokeCommand with the api
+// provided as specified.
+func NewTokenRevokeCommand(api TokenAPI) *TokenRevokeCommand {
+	return &TokenRevokeCommand{
+		tokenCommandBase: tokenCommandBase{api: api},
+	}
+}
+
+// NewGrantCommand returns a GrantCommand with the api provided as
+// specified.
+func NewGrantCommand(api PermissionAPI) *GrantCommand {
+	return &GrantCommand{
+		grantRevokeCommandBase{
+			permissionCommandBase: permissionCommandBase{api: api},
+		},
+	}
+}
+
+// NewRevokeCommand returns a RevokeCommand with the api provided as
+// specified.
+func NewRevokeCommand(api PermissionAPI) *RevokeCommand {
+	return &RevokeCommand{
+		grantRevokeCommandBase{
+			permissionCommandBase: permissionCommandBase{api: api},
+		},
+	}
+}
+
+// NewCanCommand returns a CanCommand with the api provided as specified.
+func NewCanCommand(api PermissionAPI) *CanCommand {
+	return &CanCommand{
+		permissionCommandBase: permissionCommandBase{api: api},
+	}
+}
@@ -79,6 +79,7 @@ type UserInfo struct {
 	DateCreated    string `yaml:"date-created" json:"date-created"`
 	LastConnection string `yaml:"last-connection" json:"last-connection"`
 	Disabled       bool   `yaml:"disabled,omitempty" json:"disabled,omitempty"`
+	LockedUntil    string `yaml:"locked-until,omitempty" json:"locked-until,omitempty"`
 }
 
 // Info implements Command.Info.
@@ -149,6 +150,9 @@ func (c *InfoCommandBase) apiUsersToUserInfoSlice(users []params.UserInfo) []Use
 			Disabled:       info.Disabled,
 			LastConnection: LastConnection(info.LastConnection, now, c.exactTime),
 		}
+		if info.LockedUntil != nil {
+			outInfo.LockedUntil = info.LockedUntil.String()
+		}
 		if c.exactTime {
 			outInfo.DateCreated = info.DateCreated.String()
 		} else {
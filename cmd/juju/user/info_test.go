@@ -57,6 +57,10 @@ func (*fakeUserInfoAPI) UserInfo(usernames []string, all usermanager.IncludeDisa
 	case "foobar":
 		info.Username = "foobar"
 		info.DisplayName = "Foo Bar"
+	case "carol":
+		info.Username = "carol"
+		lockedUntil := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+		info.LockedUntil = &lockedUntil
 	default:
 		return nil, common.ErrPerm
 	}
@@ -93,6 +97,17 @@ last-connection: 2014-01-01
 `)
 }
 
+func (s *UserInfoCommandSuite) TestUserInfoLockedOut(c *gc.C) {
+	context, err := testing.RunCommand(c, newUserInfoCommand(), "carol")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(context), gc.Equals, `user-name: carol
+display-name: ""
+date-created: 1981-02-27
+last-connection: 2014-01-01
+locked-until: 2030-01-01 00:00:00 +0000 UTC
+`)
+}
+
 func (*UserInfoCommandSuite) TestUserInfoUserDoesNotExist(c *gc.C) {
 	_, err := testing.RunCommand(c, newUserInfoCommand(), "barfoo")
 	c.Assert(err, gc.ErrorMatches, "permission denied")
@@ -87,6 +87,9 @@ func (c *ListCommand) formatTabular(value interface{}) ([]byte, error) {
 		if user.Disabled {
 			conn += " (disabled)"
 		}
+		if user.LockedUntil != "" {
+			conn += " (locked out)"
+		}
 		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", user.Username, user.DisplayName, user.DateCreated, conn)
 	}
 	tw.Flush()
@@ -0,0 +1,276 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package user
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/api/usermanager"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/block"
+)
+
+// PermissionAPI defines the usermanager API methods that the permission
+// commands use.
+type PermissionAPI interface {
+	GrantPermission(permission params.PermissionGrant) error
+	RevokePermission(permission params.PermissionGrant) error
+	CheckPermission(username, operation, service string) (bool, error)
+	Close() error
+}
+
+// permissionCommandBase holds what the permission commands have in
+// common. Permissions are granted in an environment, so unlike the other
+// user commands they connect to the environment rather than the system.
+type permissionCommandBase struct {
+	envcmd.EnvCommandBase
+	api     PermissionAPI
+	Service string
+}
+
+func (c *permissionCommandBase) getPermissionAPI() (PermissionAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return usermanager.NewClient(root), nil
+}
+
+// grantRevokeCommandBase holds what the grant and revoke commands have
+// in common.
+type grantRevokeCommandBase struct {
+	permissionCommandBase
+	Group     bool
+	Subject   string
+	Operation string
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *grantRevokeCommandBase) SetFlags(f *gnuflag.FlagSet) {
+	f.BoolVar(&c.Group, "group", false, "the permission is that of the members of a group")
+	f.StringVar(&c.Service, "service", "", "restrict the permission to the units of a service")
+}
+
+// Init implements Command.Init.
+func (c *grantRevokeCommandBase) Init(args []string) error {
+	switch len(args) {
+	case 0:
+		return errors.New("no user or group supplied")
+	case 1:
+		return errors.New("no role or operation supplied")
+	}
+	c.Subject, c.Operation = args[0], args[1]
+	if !c.Group && !names.IsValidUserName(c.Subject) {
+		return errors.Errorf("%q is not a valid username", c.Subject)
+	}
+	if c.Service != "" && !names.IsValidService(c.Service) {
+		return errors.Errorf("%q is not a valid service name", c.Service)
+	}
+	return cmd.CheckEmpty(args[2:])
+}
+
+func (c *grantRevokeCommandBase) permission() params.PermissionGrant {
+	if c.Group {
+		return usermanager.GroupPermission(c.Subject, c.Operation, c.Service)
+	}
+	return usermanager.UserPermission(c.Subject, c.Operation, c.Service)
+}
+
+const grantCommandDoc = `
+Grants a user, or the members of a group, permission to use a role, or a
+single facade or facade method of the API, in the environment. With the
+--service option, the permission only applies to calls on the service and
+its units.
+
+Users that have never been granted any permissions, directly or through
+their groups, have full access to the environments they have access to.
+Once granted a permission, users are restricted to the operations of the
+permissions granted to them and their groups; other API calls are denied,
+as are uploads and downloads of charms, tools and backups, unless granted
+the admin role. Revoking all the permissions of a user leaves the user
+with none. The owner of an environment is never restricted. Permissions
+take effect immediately, including on open connections.
+
+The roles are:
+    admin     all operations
+    read      viewing status, configuration, actions and storage
+    actions   running and managing actions
+    storage   managing storage
+    operator  configuring, exposing, scaling and resolving services
+
+Groups are those of the external identity provider, if any.
+
+Examples:
+    # Let bob run actions on wordpress, and view the environment.
+    juju user grant bob actions --service wordpress
+    juju user grant bob read
+
+    # Let the members of the dba group manage storage.
+    juju user grant --group dba storage
+
+    # Let alice call a single facade method.
+    juju user grant alice Client.ServiceExpose
+
+See Also:
+    juju help user revoke
+    juju help user can
+`
+
+// GrantCommand grants permissions to users and groups.
+type GrantCommand struct {
+	grantRevokeCommandBase
+}
+
+// Info implements Command.Info.
+func (c *GrantCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "grant",
+		Args:    "<username>|<group> <role>|<Facade[.Method]>",
+		Purpose: "grant a permission to a user or group",
+		Doc:     grantCommandDoc,
+	}
+}
+
+// Run implements Command.Run.
+func (c *GrantCommand) Run(ctx *cmd.Context) error {
+	api, err := c.getPermissionAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	if err := api.GrantPermission(c.permission()); err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	return nil
+}
+
+const revokeCommandDoc = `
+Revokes a permission granted with "juju user grant". The user or group,
+role or operation, and service must be the same as when it was granted.
+Users remain restricted to their other permissions, if any, even once all
+are revoked; the revocation applies to open connections too.
+
+Examples:
+    juju user revoke bob actions --service wordpress
+    juju user revoke --group dba storage
+
+See Also:
+    juju help user grant
+`
+
+// RevokeCommand revokes permissions of users and groups.
+type RevokeCommand struct {
+	grantRevokeCommandBase
+}
+
+// Info implements Command.Info.
+func (c *RevokeCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "revoke",
+		Args:    "<username>|<group> <role>|<Facade[.Method]>",
+		Purpose: "revoke a permission of a user or group",
+		Doc:     revokeCommandDoc,
+	}
+}
+
+// Run implements Command.Run.
+func (c *RevokeCommand) Run(ctx *cmd.Context) error {
+	api, err := c.getPermissionAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	if err := api.RevokePermission(c.permission()); err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	return nil
+}
+
+const canCommandDoc = `
+Checks whether a user may call a facade method of the API in the
+environment, on the units of a service with the --service option, given the
+permissions granted to the user and the user's groups. No call is made.
+
+Examples:
+    juju user can bob Action.Enqueue --service wordpress
+    juju user can bob Client.ServiceDestroy --service wordpress
+
+See Also:
+    juju help user grant
+`
+
+// CanCommand checks whether a user may make an API call.
+type CanCommand struct {
+	permissionCommandBase
+	User      string
+	Operation string
+}
+
+// Info implements Command.Info.
+func (c *CanCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "can",
+		Args:    "<username> <Facade.Method>",
+		Purpose: "check whether a user may make an API call",
+		Doc:     canCommandDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *CanCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.Service, "service", "", "check the call on the units of a service")
+}
+
+// Init implements Command.Init.
+func (c *CanCommand) Init(args []string) error {
+	switch len(args) {
+	case 0:
+		return errors.New("no username supplied")
+	case 1:
+		return errors.New("no operation supplied")
+	}
+	c.User, c.Operation = args[0], args[1]
+	if !names.IsValidUserName(c.User) {
+		return errors.Errorf("%q is not a valid username", c.User)
+	}
+	if c.Service != "" && !names.IsValidService(c.Service) {
+		return errors.Errorf("%q is not a valid service name", c.Service)
+	}
+	return cmd.CheckEmpty(args[2:])
+}
+
+// Run implements Command.Run.
+func (c *CanCommand) Run(ctx *cmd.Context) error {
+	api, err := c.getPermissionAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	allowed, err := api.CheckPermission(c.User, c.Operation, c.Service)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	verdict := "cannot"
+	if allowed {
+		verdict = "can"
+	}
+	call := c.Operation
+	if c.Service != "" {
+		call += " on service " + c.Service
+	}
+	fmt.Fprintf(ctx.Stdout, "%s %s call %s\n", c.User, verdict, call)
+	return nil
+}
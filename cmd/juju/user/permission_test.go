@@ -0,0 +1,181 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package user_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/user"
+	"github.com/juju/juju/testing"
+)
+
+type PermissionCommandSuite struct {
+	BaseSuite
+	mockAPI *mockPermissionAPI
+}
+
+var _ = gc.Suite(&PermissionCommandSuite{})
+
+func (s *PermissionCommandSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.mockAPI = &mockPermissionAPI{}
+}
+
+func (s *PermissionCommandSuite) TestGrantInit(c *gc.C) {
+	for i, test := range []struct {
+		args      []string
+		subject   string
+		operation string
+		group     bool
+		service   string
+		err       string
+	}{{
+		err: "no user or group supplied",
+	}, {
+		args: []string{"bob"},
+		err:  "no role or operation supplied",
+	}, {
+		args:      []string{"bob", "actions", "--service", "wordpress"},
+		subject:   "bob",
+		operation: "actions",
+		service:   "wordpress",
+	}, {
+		args:      []string{"--group", "ops", "Client.FullStatus"},
+		subject:   "ops",
+		operation: "Client.FullStatus",
+		group:     true,
+	}, {
+		args: []string{"not/valid", "read"},
+		err:  `"not/valid" is not a valid username`,
+	}, {
+		args: []string{"bob", "read", "--service", "Wordpress"},
+		err:  `"Wordpress" is not a valid service name`,
+	}, {
+		args: []string{"bob", "read", "storage"},
+		err:  `unrecognized args: \["storage"\]`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		grantCmd := &user.GrantCommand{}
+		err := testing.InitCommand(grantCmd, test.args)
+		if test.err != "" {
+			c.Check(err, gc.ErrorMatches, test.err)
+			continue
+		}
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(grantCmd.Subject, gc.Equals, test.subject)
+		c.Check(grantCmd.Operation, gc.Equals, test.operation)
+		c.Check(grantCmd.Group, gc.Equals, test.group)
+		c.Check(grantCmd.Service, gc.Equals, test.service)
+	}
+}
+
+func (s *PermissionCommandSuite) TestGrant(c *gc.C) {
+	grantCmd := envcmd.Wrap(user.NewGrantCommand(s.mockAPI))
+	_, err := testing.RunCommand(c, grantCmd, "bob", "actions", "--service", "wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.granted, jc.DeepEquals, []params.PermissionGrant{{
+		UserTag:   "user-bob@local",
+		Operation: "actions",
+		Service:   "wordpress",
+	}})
+}
+
+func (s *PermissionCommandSuite) TestGrantGroup(c *gc.C) {
+	grantCmd := envcmd.Wrap(user.NewGrantCommand(s.mockAPI))
+	_, err := testing.RunCommand(c, grantCmd, "--group", "dba", "storage")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.granted, jc.DeepEquals, []params.PermissionGrant{{
+		Group:     "dba",
+		Operation: "storage",
+	}})
+}
+
+func (s *PermissionCommandSuite) TestGrantError(c *gc.C) {
+	s.mockAPI.err = errors.New(`role or operation "destroy" not valid`)
+	grantCmd := envcmd.Wrap(user.NewGrantCommand(s.mockAPI))
+	_, err := testing.RunCommand(c, grantCmd, "bob", "destroy")
+	c.Assert(err, gc.ErrorMatches, `role or operation "destroy" not valid`)
+}
+
+func (s *PermissionCommandSuite) TestRevoke(c *gc.C) {
+	revokeCmd := envcmd.Wrap(user.NewRevokeCommand(s.mockAPI))
+	_, err := testing.RunCommand(c, revokeCmd, "--group", "ops", "read")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.revoked, jc.DeepEquals, []params.PermissionGrant{{
+		Group:     "ops",
+		Operation: "read",
+	}})
+}
+
+func (s *PermissionCommandSuite) TestCanInit(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		err: "no username supplied",
+	}, {
+		args: []string{"bob"},
+		err:  "no operation supplied",
+	}, {
+		args: []string{"bob", "Client.FullStatus", "extra"},
+		err:  `unrecognized args: \["extra"\]`,
+	}, {
+		args: []string{"bob", "Client.FullStatus", "--service", "wordpress"},
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		err := testing.InitCommand(&user.CanCommand{}, test.args)
+		if test.err != "" {
+			c.Check(err, gc.ErrorMatches, test.err)
+		} else {
+			c.Check(err, jc.ErrorIsNil)
+		}
+	}
+}
+
+func (s *PermissionCommandSuite) TestCan(c *gc.C) {
+	s.mockAPI.allowed = true
+	canCmd := envcmd.Wrap(user.NewCanCommand(s.mockAPI))
+	ctx, err := testing.RunCommand(c, canCmd, "bob", "Action.Enqueue", "--service", "wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.checked, jc.DeepEquals, []string{"bob", "Action.Enqueue", "wordpress"})
+	c.Assert(testing.Stdout(ctx), gc.Equals, "bob can call Action.Enqueue on service wordpress\n")
+}
+
+func (s *PermissionCommandSuite) TestCannot(c *gc.C) {
+	canCmd := envcmd.Wrap(user.NewCanCommand(s.mockAPI))
+	ctx, err := testing.RunCommand(c, canCmd, "bob", "Client.ServiceDestroy")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, "bob cannot call Client.ServiceDestroy\n")
+}
+
+type mockPermissionAPI struct {
+	err     error
+	allowed bool
+	granted []params.PermissionGrant
+	revoked []params.PermissionGrant
+	checked []string
+}
+
+func (m *mockPermissionAPI) GrantPermission(permission params.PermissionGrant) error {
+	m.granted = append(m.granted, permission)
+	return m.err
+}
+
+func (m *mockPermissionAPI) RevokePermission(permission params.PermissionGrant) error {
+	m.revoked = append(m.revoked, permission)
+	return m.err
+}
+
+func (m *mockPermissionAPI) CheckPermission(username, operation, service string) (bool, error) {
+	m.checked = []string{username, operation, service}
+	return m.allowed, m.err
+}
+
+func (*mockPermissionAPI) Close() error {
+	return nil
+}
@@ -0,0 +1,378 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package user
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/block"
+)
+
+const tokenCommandDoc = `
+"juju user token" manages API tokens. A token can be used in place of a
+user's password to log in, until it expires or is revoked, so that scripts
+and CI jobs do not need the password itself. A token may also be restricted
+to some facades and methods of the API. Tokens are valid for at most 90
+days, and cannot be used to create further tokens. Connections logged in
+with a token are refused further calls once it expires or is revoked.
+
+Tokens are shown only once, when they are created; the server stores a hash
+of them.
+`
+
+const tokenCommandPurpose = "manage API tokens"
+
+// NewTokenSuperCommand creates the user token super subcommand and
+// registers the subcommands that it supports.
+func NewTokenSuperCommand() cmd.Command {
+	tokenCmd := jujucmd.NewSubSuperCommand(cmd.SuperCommandParams{
+		Name:        "token",
+		Doc:         tokenCommandDoc,
+		UsagePrefix: "juju user",
+		Purpose:     tokenCommandPurpose,
+	})
+	tokenCmd.Register(envcmd.WrapSystem(&TokenCreateCommand{}))
+	tokenCmd.Register(envcmd.WrapSystem(&TokenListCommand{}))
+	tokenCmd.Register(envcmd.WrapSystem(&TokenRevokeCommand{}))
+	return tokenCmd
+}
+
+// TokenAPI defines the usermanager API methods that the token commands
+// use.
+type TokenAPI interface {
+	CreateToken(username, description string, expires time.Time, scope []string) (params.UserToken, string, error)
+	UserTokens(username string) ([]params.UserToken, error)
+	RevokeToken(username, id string) error
+	Close() error
+}
+
+// tokenCommandBase holds what the token commands have in common.
+type tokenCommandBase struct {
+	UserCommandBase
+	api  TokenAPI
+	User string
+}
+
+func (c *tokenCommandBase) getTokenAPI() (TokenAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	return c.NewUserManagerAPIClient()
+}
+
+// username returns the user named on the command line, or else the
+// user the command is connecting as.
+func (c *tokenCommandBase) username() (string, error) {
+	if c.User != "" {
+		return c.User, nil
+	}
+	creds, err := c.ConnectionCredentials()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return creds.User, nil
+}
+
+const tokenCreateDoc = `
+Creates an API token for the user you are currently logged in as or, as an
+admin, for another user, and prints it. Log in with the token as the user's
+password. With the --output option, a server file holding the token is
+written instead, which can be used with "juju system login --keep-password".
+
+Examples:
+    # A token for a CI job that only reads the environment's status.
+    juju user token create --expires 168h --scope Client.FullStatus -d ci
+
+    # A server file for bob, valid for a day.
+    juju user token create bob --output bob-ci.server
+
+See Also:
+    juju help user token list
+    juju help user token revoke
+`
+
+// TokenCreateCommand creates API tokens.
+type TokenCreateCommand struct {
+	tokenCommandBase
+	Description string
+	ExpiresIn   time.Duration
+	Scope       []string
+	OutPath     string
+}
+
+// Info implements Command.Info.
+func (c *TokenCreateCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "create",
+		Args:    "[<username>]",
+		Purpose: "create an API token",
+		Doc:     tokenCreateDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *TokenCreateCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.Description, "d", "", "a description of the token's use")
+	f.StringVar(&c.Description, "description", "", "")
+	f.DurationVar(&c.ExpiresIn, "expires", 24*time.Hour, "how long the token is valid for")
+	f.Var(newScopeValue(&c.Scope), "scope", "comma-separated facades or Facade.Method names the token is restricted to")
+	f.StringVar(&c.OutPath, "o", "", "write a server file holding the token to this path")
+	f.StringVar(&c.OutPath, "output", "", "")
+}
+
+// Init implements Command.Init.
+func (c *TokenCreateCommand) Init(args []string) (err error) {
+	if c.ExpiresIn <= 0 {
+		return errors.New("--expires must be positive")
+	}
+	c.User, err = cmd.ZeroOrOneArgs(args)
+	return err
+}
+
+// Run implements Command.Run.
+func (c *TokenCreateCommand) Run(ctx *cmd.Context) error {
+	username, err := c.username()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	api, err := c.getTokenAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	token, credentials, err := api.CreateToken(username, c.Description, time.Now().Add(c.ExpiresIn), c.Scope)
+	if err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	ctx.Infof("token %s for user %q expires at %s", token.Id, username, token.Expires.Format(time.RFC3339))
+	if c.OutPath != "" {
+		return writeServerFile(c, ctx, username, credentials, c.OutPath)
+	}
+	fmt.Fprintln(ctx.Stdout, credentials)
+	return nil
+}
+
+const tokenListDoc = `
+Lists the API tokens of the user you are currently logged in as or, as an
+admin, of another user, including expired tokens. The tokens themselves are
+not shown.
+
+See Also:
+    juju help user token create
+    juju help user token revoke
+`
+
+// TokenListCommand lists API tokens.
+type TokenListCommand struct {
+	tokenCommandBase
+	out cmd.Output
+}
+
+// TokenInfo defines the serialization behaviour of API tokens.
+type TokenInfo struct {
+	Description string   `yaml:"description,omitempty" json:"description,omitempty"`
+	Created     string   `yaml:"created" json:"created"`
+	Expires     string   `yaml:"expires" json:"expires"`
+	Expired     bool     `yaml:"expired,omitempty" json:"expired,omitempty"`
+	Scope       []string `yaml:"scope,omitempty" json:"scope,omitempty"`
+}
+
+// Info implements Command.Info.
+func (c *TokenListCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "list",
+		Args:    "[<username>]",
+		Purpose: "list API tokens",
+		Doc:     tokenListDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *TokenListCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatTokenListTabular,
+	})
+}
+
+// Init implements Command.Init.
+func (c *TokenListCommand) Init(args []string) (err error) {
+	c.User, err = cmd.ZeroOrOneArgs(args)
+	return err
+}
+
+// Run implements Command.Run.
+func (c *TokenListCommand) Run(ctx *cmd.Context) error {
+	username, err := c.username()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	api, err := c.getTokenAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	tokens, err := api.UserTokens(username)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(tokens) == 0 {
+		return nil
+	}
+	now := time.Now()
+	output := make(map[string]TokenInfo)
+	for _, token := range tokens {
+		output[token.Id] = TokenInfo{
+			Description: token.Description,
+			Created:     token.Created.Format(time.RFC3339),
+			Expires:     token.Expires.Format(time.RFC3339),
+			Expired:     !token.Expires.After(now),
+			Scope:       token.Scope,
+		}
+	}
+	return c.out.Write(ctx, output)
+}
+
+// formatTokenListTabular returns a tabular summary of API tokens, or
+// errors out if value is not a map of TokenInfo.
+func formatTokenListTabular(value interface{}) ([]byte, error) {
+	tokens, ok := value.(map[string]TokenInfo)
+	if !ok {
+		return nil, errors.Errorf("expected value of type %T, got %T", tokens, value)
+	}
+	var out bytes.Buffer
+	const (
+		// To format things into columns.
+		minwidth = 0
+		tabwidth = 1
+		padding  = 2
+		padchar  = ' '
+		flags    = 0
+	)
+	tw := tabwriter.NewWriter(&out, minwidth, tabwidth, padding, padchar, flags)
+	print := func(values ...string) {
+		fmt.Fprintln(tw, strings.Join(values, "\t"))
+	}
+
+	print("ID", "DESCRIPTION", "CREATED", "EXPIRES", "SCOPE")
+	ids := make([]string, 0, len(tokens))
+	for id := range tokens {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		token := tokens[id]
+		expires := token.Expires
+		if token.Expired {
+			expires += " (expired)"
+		}
+		print(id, token.Description, token.Created, expires, strings.Join(token.Scope, ","))
+	}
+	tw.Flush()
+
+	return out.Bytes(), nil
+}
+
+const tokenRevokeDoc = `
+Revokes an API token of the user you are currently logged in as or, as an
+admin, of another user, so that it can no longer be used to log in.
+
+Examples:
+    juju user token revoke 3f2a9c0e1b7d4e66
+    juju user token revoke 3f2a9c0e1b7d4e66 --user bob
+
+See Also:
+    juju help user token list
+`
+
+// TokenRevokeCommand revokes API tokens.
+type TokenRevokeCommand struct {
+	tokenCommandBase
+	Id string
+}
+
+// Info implements Command.Info.
+func (c *TokenRevokeCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "revoke",
+		Args:    "<token id>",
+		Purpose: "revoke an API token",
+		Doc:     tokenRevokeDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *TokenRevokeCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.User, "user", "", "the user the token was issued to")
+}
+
+// Init implements Command.Init.
+func (c *TokenRevokeCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no token id specified")
+	}
+	c.Id = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+// Run implements Command.Run.
+func (c *TokenRevokeCommand) Run(ctx *cmd.Context) error {
+	username, err := c.username()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	api, err := c.getTokenAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	if err := api.RevokeToken(username, c.Id); err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	ctx.Infof("token %s revoked", c.Id)
+	return nil
+}
+
+// scopeValue implements gnuflag.Value for a comma-separated token
+// scope.
+type scopeValue struct {
+	scope *[]string
+}
+
+func newScopeValue(scope *[]string) *scopeValue {
+	return &scopeValue{scope}
+}
+
+// Set implements gnuflag.Value.
+func (v *scopeValue) Set(s string) error {
+	var scope []string
+	for _, entry := range strings.Split(s, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			scope = append(scope, entry)
+		}
+	}
+	*v.scope = scope
+	return nil
+}
+
+// String implements gnuflag.Value.
+func (v *scopeValue) String() string {
+	return strings.Join(*v.scope, ",")
+}
@@ -0,0 +1,172 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package user_test
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/user"
+	"github.com/juju/juju/testing"
+)
+
+type TokenCommandSuite struct {
+	BaseSuite
+	mockAPI *mockTokenAPI
+}
+
+var _ = gc.Suite(&TokenCommandSuite{})
+
+func (s *TokenCommandSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.mockAPI = &mockTokenAPI{}
+}
+
+func (s *TokenCommandSuite) TestCreateInit(c *gc.C) {
+	for i, test := range []struct {
+		args      []string
+		user      string
+		expiresIn time.Duration
+		scope     []string
+		err       string
+	}{{
+		expiresIn: 24 * time.Hour,
+	}, {
+		args:      []string{"bob", "--expires", "1h", "--scope", "Client.FullStatus, Charms"},
+		user:      "bob",
+		expiresIn: time.Hour,
+		scope:     []string{"Client.FullStatus", "Charms"},
+	}, {
+		args: []string{"--expires", "0s"},
+		err:  "--expires must be positive",
+	}, {
+		args: []string{"bob", "carol"},
+		err:  `unrecognized args: \["carol"\]`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		createCmd := &user.TokenCreateCommand{}
+		err := testing.InitCommand(createCmd, test.args)
+		if test.err != "" {
+			c.Check(err, gc.ErrorMatches, test.err)
+			continue
+		}
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(createCmd.User, gc.Equals, test.user)
+		c.Check(createCmd.ExpiresIn, gc.Equals, test.expiresIn)
+		c.Check(createCmd.Scope, jc.DeepEquals, test.scope)
+	}
+}
+
+func (s *TokenCommandSuite) TestCreate(c *gc.C) {
+	createCmd := envcmd.WrapSystem(user.NewTokenCreateCommand(s.mockAPI))
+	ctx, err := testing.RunCommand(c, createCmd, "--scope", "Client", "-d", "ci")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.username, gc.Equals, "user-test")
+	c.Assert(s.mockAPI.description, gc.Equals, "ci")
+	c.Assert(s.mockAPI.scope, jc.DeepEquals, []string{"Client"})
+	expires := time.Now().Add(24 * time.Hour)
+	c.Assert(s.mockAPI.expires.After(expires.Add(-time.Minute)), jc.IsTrue)
+	c.Assert(s.mockAPI.expires.Before(expires), jc.IsTrue)
+	c.Assert(testing.Stdout(ctx), gc.Equals, "token:abc:sekrit\n")
+	c.Assert(testing.Stderr(ctx), jc.Contains, `token abc for user "user-test" expires at`)
+}
+
+func (s *TokenCommandSuite) TestCreateServerFile(c *gc.C) {
+	serverFile := filepath.Join(c.MkDir(), "bob.server")
+	createCmd := envcmd.WrapSystem(user.NewTokenCreateCommand(s.mockAPI))
+	ctx, err := testing.RunCommand(c, createCmd, "bob", "-o", serverFile)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.username, gc.Equals, "bob")
+	c.Assert(testing.Stdout(ctx), gc.Equals, "")
+	s.assertServerFileMatches(c, serverFile, "bob", "token:abc:sekrit")
+}
+
+func (s *TokenCommandSuite) TestCreateError(c *gc.C) {
+	s.mockAPI.err = errors.New("permission denied")
+	createCmd := envcmd.WrapSystem(user.NewTokenCreateCommand(s.mockAPI))
+	_, err := testing.RunCommand(c, createCmd, "bob")
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *TokenCommandSuite) TestList(c *gc.C) {
+	created := time.Date(2015, 6, 1, 12, 0, 0, 0, time.UTC)
+	s.mockAPI.tokens = []params.UserToken{{
+		Id:          "abc",
+		Description: "ci",
+		Created:     created,
+		Expires:     created.Add(time.Hour),
+		Scope:       []string{"Client.FullStatus"},
+	}, {
+		Id:      "def",
+		Created: created,
+		Expires: time.Now().Add(time.Hour).Truncate(time.Second).UTC(),
+	}}
+	listCmd := envcmd.WrapSystem(user.NewTokenListCommand(s.mockAPI))
+	ctx, err := testing.RunCommand(c, listCmd, "bob")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.username, gc.Equals, "bob")
+	c.Assert(testing.Stdout(ctx), gc.Equals, ""+
+		"ID   DESCRIPTION  CREATED               EXPIRES                         SCOPE\n"+
+		"abc  ci           2015-06-01T12:00:00Z  2015-06-01T13:00:00Z (expired)  Client.FullStatus\n"+
+		"def               2015-06-01T12:00:00Z  "+s.mockAPI.tokens[1].Expires.Format(time.RFC3339)+"            \n")
+}
+
+func (s *TokenCommandSuite) TestListNone(c *gc.C) {
+	listCmd := envcmd.WrapSystem(user.NewTokenListCommand(s.mockAPI))
+	ctx, err := testing.RunCommand(c, listCmd)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.username, gc.Equals, "user-test")
+	c.Assert(testing.Stdout(ctx), gc.Equals, "")
+}
+
+func (s *TokenCommandSuite) TestRevoke(c *gc.C) {
+	revokeCmd := envcmd.WrapSystem(user.NewTokenRevokeCommand(s.mockAPI))
+	_, err := testing.RunCommand(c, revokeCmd)
+	c.Assert(err, gc.ErrorMatches, "no token id specified")
+
+	revokeCmd = envcmd.WrapSystem(user.NewTokenRevokeCommand(s.mockAPI))
+	ctx, err := testing.RunCommand(c, revokeCmd, "abc", "--user", "bob")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.username, gc.Equals, "bob")
+	c.Assert(s.mockAPI.revoked, gc.Equals, "abc")
+	c.Assert(testing.Stderr(ctx), gc.Equals, "token abc revoked\n")
+}
+
+type mockTokenAPI struct {
+	err         error
+	tokens      []params.UserToken
+	username    string
+	description string
+	expires     time.Time
+	scope       []string
+	revoked     string
+}
+
+func (m *mockTokenAPI) CreateToken(username, description string, expires time.Time, scope []string) (params.UserToken, string, error) {
+	m.username, m.description, m.expires, m.scope = username, description, expires, scope
+	if m.err != nil {
+		return params.UserToken{}, "", m.err
+	}
+	return params.UserToken{Id: "abc", Expires: expires}, "token:abc:sekrit", nil
+}
+
+func (m *mockTokenAPI) UserTokens(username string) ([]params.UserToken, error) {
+	m.username = username
+	return m.tokens, m.err
+}
+
+func (m *mockTokenAPI) RevokeToken(username, id string) error {
+	m.username, m.revoked = username, id
+	return m.err
+}
+
+func (*mockTokenAPI) Close() error {
+	return nil
+}
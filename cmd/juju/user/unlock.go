@@ -0,0 +1,75 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package user
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/cmd/juju/block"
+)
+
+const unlockUserDoc = `
+Users that fail to log in too many times in a row are locked out for a while,
+if the "login-lockout-attempts" setting of the state server environment is
+set. Unlocking a user ends the lockout early, so that the user can log in
+again straight away. If the user is not locked out, this command succeeds
+silently.
+
+Examples:
+  juju user unlock foobar
+
+See Also:
+  juju help user info
+`
+
+// UnlockUserAPI defines the API methods that the unlock command uses.
+type UnlockUserAPI interface {
+	ClearLockout(username string) error
+	Close() error
+}
+
+// UnlockCommand ends the lockouts of users after too many failed logins.
+type UnlockCommand struct {
+	UserCommandBase
+	api  UnlockUserAPI
+	User string
+}
+
+// Info implements Command.Info.
+func (c *UnlockCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "unlock",
+		Args:    "<username>",
+		Purpose: "unlock a user locked out after failed logins",
+		Doc:     unlockUserDoc,
+	}
+}
+
+// Init implements Command.Init.
+func (c *UnlockCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no username supplied")
+	}
+	c.User = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+// Run implements Command.Run.
+func (c *UnlockCommand) Run(ctx *cmd.Context) error {
+	if c.api == nil {
+		api, err := c.NewUserManagerAPIClient()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		c.api = api
+		defer c.api.Close()
+	}
+
+	if err := c.api.ClearLockout(c.User); err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	ctx.Infof("User %q unlocked", c.User)
+	return nil
+}
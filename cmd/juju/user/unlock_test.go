@@ -0,0 +1,83 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package user_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/user"
+	"github.com/juju/juju/testing"
+)
+
+type UnlockUserSuite struct {
+	BaseSuite
+	mock *mockUnlockUserAPI
+}
+
+var _ = gc.Suite(&UnlockUserSuite{})
+
+func (s *UnlockUserSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.mock = &mockUnlockUserAPI{}
+}
+
+func (s *UnlockUserSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args     []string
+		errMatch string
+		user     string
+	}{
+		{
+			errMatch: "no username supplied",
+		}, {
+			args:     []string{"username", "password"},
+			errMatch: `unrecognized args: \["password"\]`,
+		}, {
+			args: []string{"username"},
+			user: "username",
+		},
+	} {
+		c.Logf("test %d, args %v", i, test.args)
+		unlockCommand := &user.UnlockCommand{}
+		err := testing.InitCommand(unlockCommand, test.args)
+		if test.errMatch == "" {
+			c.Assert(err, jc.ErrorIsNil)
+			c.Assert(unlockCommand.User, gc.Equals, test.user)
+		} else {
+			c.Assert(err, gc.ErrorMatches, test.errMatch)
+		}
+	}
+}
+
+func (s *UnlockUserSuite) TestUnlock(c *gc.C) {
+	unlockCommand := envcmd.WrapSystem(user.NewUnlockCommand(s.mock))
+	ctx, err := testing.RunCommand(c, unlockCommand, "testing")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mock.unlocked, gc.Equals, "testing")
+	c.Assert(testing.Stderr(ctx), gc.Equals, "User \"testing\" unlocked\n")
+}
+
+func (s *UnlockUserSuite) TestUnlockError(c *gc.C) {
+	s.mock.err = errors.New("permission denied")
+	unlockCommand := envcmd.WrapSystem(user.NewUnlockCommand(s.mock))
+	_, err := testing.RunCommand(c, unlockCommand, "testing")
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+type mockUnlockUserAPI struct {
+	err      error
+	unlocked string
+}
+
+func (m *mockUnlockUserAPI) ClearLockout(username string) error {
+	m.unlocked = username
+	return m.err
+}
+
+func (*mockUnlockUserAPI) Close() error {
+	return nil
+}
@@ -38,6 +38,11 @@ func NewSuperCommand() cmd.Command {
 	usercmd.Register(envcmd.WrapSystem(&DisableCommand{}))
 	usercmd.Register(envcmd.WrapSystem(&EnableCommand{}))
 	usercmd.Register(envcmd.WrapSystem(&ListCommand{}))
+	usercmd.Register(envcmd.WrapSystem(&UnlockCommand{}))
+	usercmd.Register(NewTokenSuperCommand())
+	usercmd.Register(envcmd.Wrap(&GrantCommand{}))
+	usercmd.Register(envcmd.Wrap(&RevokeCommand{}))
+	usercmd.Register(envcmd.Wrap(&CanCommand{}))
 	return usercmd
 }
 
@@ -26,13 +26,18 @@ var _ = gc.Suite(&UserCommandSuite{})
 
 var expectedUserCommmandNames = []string{
 	"add",
+	"can",
 	"change-password",
 	"credentials",
 	"disable",
 	"enable",
+	"grant",
 	"help",
 	"info",
 	"list",
+	"revoke",
+	"token",
+	"unlock",
 }
 
 func (s *UserCommandSuite) TestHelp(c *gc.C) {
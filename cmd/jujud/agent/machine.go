@@ -68,8 +68,10 @@ import (
 	"github.com/juju/juju/worker/addresser"
 	"github.com/juju/juju/worker/apiaddressupdater"
 	"github.com/juju/juju/worker/authenticationworker"
+	"github.com/juju/juju/worker/autoscaler"
 	"github.com/juju/juju/worker/certupdater"
 	"github.com/juju/juju/worker/charmrevisionworker"
+	"github.com/juju/juju/worker/charmrollout"
 	"github.com/juju/juju/worker/cleaner"
 	"github.com/juju/juju/worker/conv2state"
 	"github.com/juju/juju/worker/dblogpruner"
@@ -90,6 +92,7 @@ import (
 	"github.com/juju/juju/worker/proxyupdater"
 	rebootworker "github.com/juju/juju/worker/reboot"
 	"github.com/juju/juju/worker/resumer"
+	"github.com/juju/juju/worker/rollingupgrade"
 	"github.com/juju/juju/worker/rsyslog"
 	"github.com/juju/juju/worker/singular"
 	"github.com/juju/juju/worker/statushistorypruner"
@@ -1153,6 +1156,15 @@ func (a *MachineAgent) startEnvWorkers(
 	singularRunner.StartWorker("minunitsworker", func() (worker.Worker, error) {
 		return minunitsworker.NewMinUnitsWorker(st), nil
 	})
+	singularRunner.StartWorker("autoscaler", func() (worker.Worker, error) {
+		return autoscaler.NewAutoscaler(st), nil
+	})
+	singularRunner.StartWorker("charmrollout", func() (worker.Worker, error) {
+		return charmrollout.NewCharmRollout(st), nil
+	})
+	singularRunner.StartWorker("rollingupgrade", func() (worker.Worker, error) {
+		return rollingupgrade.NewRollingUpgrade(st), nil
+	})
 	singularRunner.StartWorker("addresserworker", func() (worker.Worker, error) {
 		return addresser.NewWorker(st)
 	})
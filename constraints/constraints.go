@@ -15,6 +15,7 @@ import (
 
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/juju/arch"
+	"github.com/juju/juju/network"
 )
 
 // The following constants list the supported constraint attribute names, as defined
@@ -29,6 +30,12 @@ const (
 	Tags         = "tags"
 	InstanceType = "instance-type"
 	Networks     = "networks"
+	Spaces       = "spaces"
+	VirtType     = "virt-type"
+	LocalDisks   = "local-disks"
+
+	Accelerators    = "accelerators"
+	AcceleratorType = "accelerator-type"
 )
 
 // Value describes a user's requirements of the hardware on which units
@@ -78,6 +85,29 @@ type Value struct {
 	// negative values are accepted, and the difference is the latter
 	// have a "^" prefix to the name.
 	Networks *[]string `json:"networks,omitempty" yaml:"networks,omitempty"`
+
+	// Spaces, if not nil, holds a list of juju network space names
+	// that the machine must (or must not) have addresses in. As with
+	// Networks, negative values have a "^" prefix to the name.
+	Spaces *[]string `json:"spaces,omitempty" yaml:"spaces,omitempty"`
+
+	// VirtType, if not nil or empty, indicates that a machine must use
+	// the named type of virtualisation (e.g. "hvm" or "pv" on EC2).
+	VirtType *string `json:"virt-type,omitempty" yaml:"virt-type,omitempty"`
+
+	// LocalDisks, if not nil, indicates that a machine must have at
+	// least that many local (instance store) disks attached, in
+	// addition to the root disk.
+	LocalDisks *uint64 `json:"local-disks,omitempty" yaml:"local-disks,omitempty"`
+
+	// Accelerators, if not nil, indicates that a machine must have at
+	// least that many GPU or other accelerator devices attached.
+	Accelerators *uint64 `json:"accelerators,omitempty" yaml:"accelerators,omitempty"`
+
+	// AcceleratorType, if not nil or empty, indicates that the
+	// accelerators attached to a machine must be of the named type
+	// (e.g. "grid-k520"). It implies at least one accelerator.
+	AcceleratorType *string `json:"accelerator-type,omitempty" yaml:"accelerator-type,omitempty"`
 }
 
 // fieldNames records a mapping from the constraint tag to struct field name.
@@ -115,6 +145,32 @@ func (v *Value) HasInstanceType() bool {
 	return v.InstanceType != nil && *v.InstanceType != ""
 }
 
+// HasVirtType returns true if the constraints.Value specifies a
+// virtualisation type.
+func (v *Value) HasVirtType() bool {
+	return v.VirtType != nil && *v.VirtType != ""
+}
+
+// HasAcceleratorType returns true if the constraints.Value specifies
+// an accelerator type.
+func (v *Value) HasAcceleratorType() bool {
+	return v.AcceleratorType != nil && *v.AcceleratorType != ""
+}
+
+// MinAccelerators returns the minimum number of accelerators a machine
+// must have to satisfy the constraints.Value: the accelerators
+// constraint if specified, otherwise one if an accelerator type is
+// specified, otherwise zero.
+func (v *Value) MinAccelerators() uint64 {
+	if v.Accelerators != nil {
+		return *v.Accelerators
+	}
+	if v.HasAcceleratorType() {
+		return 1
+	}
+	return 0
+}
+
 // extractNetworks returns the list of networks to include or exclude
 // (without the "^" prefixes).
 func (v *Value) extractNetworks() (include, exclude []string) {
@@ -151,6 +207,42 @@ func (v *Value) HaveNetworks() bool {
 	return v.Networks != nil && len(*v.Networks) > 0
 }
 
+// extractSpaces returns the list of spaces to include or exclude
+// (without the "^" prefixes).
+func (v *Value) extractSpaces() (include, exclude []string) {
+	if v.Spaces == nil {
+		return nil, nil
+	}
+	for _, name := range *v.Spaces {
+		if strings.HasPrefix(name, "^") {
+			exclude = append(exclude, strings.TrimPrefix(name, "^"))
+		} else {
+			include = append(include, name)
+		}
+	}
+	return include, exclude
+}
+
+// IncludeSpaces returns a list of spaces to include when starting a
+// machine, if specified.
+func (v *Value) IncludeSpaces() []string {
+	include, _ := v.extractSpaces()
+	return include
+}
+
+// ExcludeSpaces returns a list of spaces to exclude when starting a
+// machine, if specified. They are given in the spaces constraint with
+// a "^" prefix to the name, which is stripped before returning.
+func (v *Value) ExcludeSpaces() []string {
+	_, exclude := v.extractSpaces()
+	return exclude
+}
+
+// HaveSpaces returns whether any space constraints were specified.
+func (v *Value) HaveSpaces() bool {
+	return v.Spaces != nil && len(*v.Spaces) > 0
+}
+
 // String expresses a constraints.Value in the language in which it was specified.
 func (v Value) String() string {
 	var strs []string
@@ -191,6 +283,22 @@ func (v Value) String() string {
 		s := strings.Join(*v.Networks, ",")
 		strs = append(strs, "networks="+s)
 	}
+	if v.Spaces != nil {
+		s := strings.Join(*v.Spaces, ",")
+		strs = append(strs, "spaces="+s)
+	}
+	if v.VirtType != nil {
+		strs = append(strs, "virt-type="+*v.VirtType)
+	}
+	if v.LocalDisks != nil {
+		strs = append(strs, "local-disks="+uintStr(*v.LocalDisks))
+	}
+	if v.Accelerators != nil {
+		strs = append(strs, "accelerators="+uintStr(*v.Accelerators))
+	}
+	if v.AcceleratorType != nil {
+		strs = append(strs, "accelerator-type="+*v.AcceleratorType)
+	}
 	return strings.Join(strs, " ")
 }
 
@@ -330,6 +438,16 @@ func (v *Value) setRaw(raw string) error {
 		err = v.setInstanceType(str)
 	case Networks:
 		err = v.setNetworks(str)
+	case Spaces:
+		err = v.setSpaces(str)
+	case VirtType:
+		err = v.setVirtType(str)
+	case LocalDisks:
+		err = v.setLocalDisks(str)
+	case Accelerators:
+		err = v.setAccelerators(str)
+	case AcceleratorType:
+		err = v.setAcceleratorType(str)
 	default:
 		return fmt.Errorf("unknown constraint %q", name)
 	}
@@ -376,6 +494,20 @@ func (v *Value) SetYAML(tag string, value interface{}) bool {
 			if err == nil {
 				err = v.validateNetworks(networks)
 			}
+		case Spaces:
+			var spaces *[]string
+			spaces, err = parseYamlStrings("spaces", val)
+			if err == nil {
+				err = v.validateSpaces(spaces)
+			}
+		case VirtType:
+			v.VirtType = &vstr
+		case LocalDisks:
+			v.LocalDisks, err = parseUint64(vstr)
+		case Accelerators:
+			v.Accelerators, err = parseUint64(vstr)
+		case AcceleratorType:
+			v.AcceleratorType = &vstr
 		default:
 			return false
 		}
@@ -494,6 +626,59 @@ func (v *Value) validateNetworks(networks *[]string) error {
 	return nil
 }
 
+func (v *Value) setSpaces(str string) error {
+	if v.Spaces != nil {
+		return fmt.Errorf("already set")
+	}
+	return v.validateSpaces(parseCommaDelimited(str))
+}
+
+func (v *Value) validateSpaces(spaces *[]string) error {
+	if spaces == nil {
+		return nil
+	}
+	for _, name := range *spaces {
+		name = strings.TrimPrefix(name, "^")
+		if !network.IsValidSpaceName(name) {
+			return fmt.Errorf("%q is not a valid space name", name)
+		}
+	}
+	v.Spaces = spaces
+	return nil
+}
+
+func (v *Value) setVirtType(str string) error {
+	if v.VirtType != nil {
+		return fmt.Errorf("already set")
+	}
+	v.VirtType = &str
+	return nil
+}
+
+func (v *Value) setLocalDisks(str string) (err error) {
+	if v.LocalDisks != nil {
+		return fmt.Errorf("already set")
+	}
+	v.LocalDisks, err = parseUint64(str)
+	return
+}
+
+func (v *Value) setAccelerators(str string) (err error) {
+	if v.Accelerators != nil {
+		return fmt.Errorf("already set")
+	}
+	v.Accelerators, err = parseUint64(str)
+	return
+}
+
+func (v *Value) setAcceleratorType(str string) error {
+	if v.AcceleratorType != nil {
+		return fmt.Errorf("already set")
+	}
+	v.AcceleratorType = &str
+	return nil
+}
+
 func parseUint64(str string) (*uint64, error) {
 	var value uint64
 	if str != "" {
@@ -525,7 +710,8 @@ func parseSize(str string) (*uint64, error) {
 }
 
 // parseCommaDelimited returns the items in the value s. We expect the
-// tags to be comma delimited strings. It is used for tags and networks.
+// tags to be comma delimited strings. It is used for tags, networks
+// and spaces.
 func parseCommaDelimited(s string) *[]string {
 	if s == "" {
 		return &[]string{}
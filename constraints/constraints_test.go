@@ -280,6 +280,77 @@ var parseConstraintsTests = []struct {
 		args:    []string{"networks="},
 	},
 
+	// spaces
+	{
+		summary: "single space",
+		args:    []string{"spaces=internal"},
+	}, {
+		summary: "multiple spaces - positive and negative",
+		args:    []string{"spaces=internal,^dmz,db-2"},
+	}, {
+		summary: "no spaces",
+		args:    []string{"spaces="},
+	}, {
+		summary: "invalid space",
+		args:    []string{"spaces=internal,^Public"},
+		err:     `bad "spaces" constraint: "Public" is not a valid space name`,
+	}, {
+		summary: "double set spaces",
+		args:    []string{"spaces=internal", "spaces=dmz"},
+		err:     `bad "spaces" constraint: already set`,
+	},
+
+	// virt-type
+	{
+		summary: "set virt-type",
+		args:    []string{"virt-type=hvm"},
+	}, {
+		summary: "virt-type empty",
+		args:    []string{"virt-type="},
+	}, {
+		summary: "double set virt-type",
+		args:    []string{"virt-type=hvm", "virt-type=pv"},
+		err:     `bad "virt-type" constraint: already set`,
+	},
+
+	// local-disks
+	{
+		summary: "set local-disks",
+		args:    []string{"local-disks=2"},
+	}, {
+		summary: "local-disks empty",
+		args:    []string{"local-disks="},
+	}, {
+		summary: "set local-disks invalid",
+		args:    []string{"local-disks=-1"},
+		err:     `bad "local-disks" constraint: must be a non-negative integer`,
+	}, {
+		summary: "double set local-disks",
+		args:    []string{"local-disks=1", "local-disks=2"},
+		err:     `bad "local-disks" constraint: already set`,
+	},
+
+	// accelerators
+	{
+		summary: "set accelerators",
+		args:    []string{"accelerators=2"},
+	}, {
+		summary: "set accelerators invalid",
+		args:    []string{"accelerators=two"},
+		err:     `bad "accelerators" constraint: must be a non-negative integer`,
+	}, {
+		summary: "double set accelerators",
+		args:    []string{"accelerators=1", "accelerators=2"},
+		err:     `bad "accelerators" constraint: already set`,
+	}, {
+		summary: "set accelerator-type",
+		args:    []string{"accelerators=1 accelerator-type=grid-k520"},
+	}, {
+		summary: "double set accelerator-type",
+		args:    []string{"accelerator-type=grid-k520", "accelerator-type=tesla-m2050"},
+		err:     `bad "accelerator-type" constraint: already set`,
+	},
+
 	// instance type
 	{
 		summary: "set instance type",
@@ -294,12 +365,12 @@ var parseConstraintsTests = []struct {
 		summary: "kitchen sink together",
 		args: []string{
 			"root-disk=8G mem=2T  arch=i386  cpu-cores=4096 cpu-power=9001 container=lxc " +
-				"tags=foo,bar networks=net1,^net2 instance-type=foo"},
+				"tags=foo,bar networks=net1,^net2 spaces=internal,^dmz instance-type=foo"},
 	}, {
 		summary: "kitchen sink separately",
 		args: []string{
 			"root-disk=8G", "mem=2T", "cpu-cores=4096", "cpu-power=9001", "arch=armhf",
-			"container=lxc", "tags=foo,bar", "networks=net1,^net2", "instance-type=foo"},
+			"container=lxc", "tags=foo,bar", "networks=net1,^net2", "spaces=internal,^dmz", "instance-type=foo"},
 	},
 }
 
@@ -373,6 +444,17 @@ func (s *ConstraintsSuite) TestIncludeExcludeAndHaveNetworks(c *gc.C) {
 	c.Check(con.HaveNetworks(), jc.IsTrue)
 }
 
+func (s *ConstraintsSuite) TestIncludeExcludeAndHaveSpaces(c *gc.C) {
+	con := constraints.MustParse("spaces=internal,^dmz,db,^public")
+	c.Check(con.IncludeSpaces(), jc.SameContents, []string{"internal", "db"})
+	c.Check(con.ExcludeSpaces(), jc.SameContents, []string{"dmz", "public"})
+	c.Check(con.HaveSpaces(), jc.IsTrue)
+	con = constraints.MustParse("mem=4G")
+	c.Check(con.HaveSpaces(), jc.IsFalse)
+	con = constraints.MustParse("spaces=")
+	c.Check(con.HaveSpaces(), jc.IsFalse)
+}
+
 func (s *ConstraintsSuite) TestInvalidNetworks(c *gc.C) {
 	invalidNames := []string{
 		"%ne$t", "^net#2", "+", "tcp:ip",
@@ -459,8 +541,18 @@ var constraintsRoundtripTests = []roundTrip{
 	{"Networks1", constraints.Value{Networks: nil}},
 	{"Networks2", constraints.Value{Networks: &[]string{}}},
 	{"Networks3", constraints.Value{Networks: &[]string{"net1", "^net2"}}},
+	{"Spaces1", constraints.Value{Spaces: nil}},
+	{"Spaces2", constraints.Value{Spaces: &[]string{}}},
+	{"Spaces3", constraints.Value{Spaces: &[]string{"internal", "^dmz"}}},
 	{"InstanceType1", constraints.Value{InstanceType: strp("")}},
 	{"InstanceType2", constraints.Value{InstanceType: strp("foo")}},
+	{"VirtType1", constraints.Value{VirtType: strp("")}},
+	{"VirtType2", constraints.Value{VirtType: strp("hvm")}},
+	{"LocalDisks1", constraints.Value{LocalDisks: uint64p(0)}},
+	{"LocalDisks2", constraints.Value{LocalDisks: uint64p(4)}},
+	{"Accelerators1", constraints.Value{Accelerators: uint64p(0)}},
+	{"Accelerators2", constraints.Value{Accelerators: uint64p(2)}},
+	{"AcceleratorType", constraints.Value{AcceleratorType: strp("grid-k520")}},
 	{"All", constraints.Value{
 		Arch:         strp("i386"),
 		Container:    ctypep("lxc"),
@@ -470,7 +562,13 @@ var constraintsRoundtripTests = []roundTrip{
 		RootDisk:     uint64p(24000000000),
 		Tags:         &[]string{"foo", "bar"},
 		Networks:     &[]string{"net1", "^net2"},
+		Spaces:       &[]string{"internal", "^dmz"},
 		InstanceType: strp("foo"),
+
+		VirtType:        strp("hvm"),
+		LocalDisks:      uint64p(2),
+		Accelerators:    uint64p(1),
+		AcceleratorType: strp("grid-k520"),
 	}},
 }
 
@@ -548,6 +646,32 @@ func (s *ConstraintsSuite) TestHasInstanceType(c *gc.C) {
 	c.Check(cons.HasInstanceType(), jc.IsTrue)
 }
 
+func (s *ConstraintsSuite) TestHasVirtType(c *gc.C) {
+	cons := constraints.MustParse("virt-type=")
+	c.Check(cons.HasVirtType(), jc.IsFalse)
+	cons = constraints.MustParse("virt-type=hvm")
+	c.Check(cons.HasVirtType(), jc.IsTrue)
+}
+
+func (s *ConstraintsSuite) TestMinAccelerators(c *gc.C) {
+	for i, t := range []struct {
+		cons     string
+		hasType  bool
+		expected uint64
+	}{
+		{"", false, 0},
+		{"accelerators=2", false, 2},
+		{"accelerator-type=grid-k520", true, 1},
+		{"accelerators=4 accelerator-type=grid-k520", true, 4},
+		{"accelerators=0 accelerator-type=", false, 0},
+	} {
+		c.Logf("test %d: %s", i, t.cons)
+		cons := constraints.MustParse(t.cons)
+		c.Check(cons.HasAcceleratorType(), gc.Equals, t.hasType)
+		c.Check(cons.MinAccelerators(), gc.Equals, t.expected)
+	}
+}
+
 const initialWithoutCons = "root-disk=8G mem=4G arch=amd64 cpu-power=1000 cpu-cores=4 networks=net1,^net2 tags=foo container=lxc instance-type=bar"
 
 var withoutTests = []struct {
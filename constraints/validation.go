@@ -196,9 +196,21 @@ func (v *validator) Validate(cons Value) ([]string, error) {
 	if err := v.checkValidValues(cons); err != nil {
 		return unsupported, err
 	}
+	if err := checkAccelerators(cons); err != nil {
+		return unsupported, err
+	}
 	return unsupported, nil
 }
 
+// checkAccelerators returns an error if the constraints Value
+// specifies an accelerator type but no accelerators.
+func checkAccelerators(cons Value) error {
+	if cons.HasAcceleratorType() && cons.MinAccelerators() == 0 {
+		return fmt.Errorf("ambiguous constraints: %q requires %q greater than 0", AcceleratorType, Accelerators)
+	}
+	return nil
+}
+
 // Merge is defined on Validator.
 func (v *validator) Merge(consFallback, cons Value) (Value, error) {
 	// First ensure both constraints are valid. We don't care if there
@@ -111,6 +111,18 @@ var validationTests = []struct {
 			"instance-type": {"foo", "bar"},
 			"arch":          {"amd64", "i386"}},
 	},
+	{
+		cons:  "virt-type=pv accelerator-type=grid-k520",
+		vocab: map[string][]interface{}{"virt-type": {"hvm"}},
+		err:   "invalid constraint value: virt-type=pv\nvalid values are:.*",
+	},
+	{
+		cons: "accelerators=2 accelerator-type=grid-k520",
+	},
+	{
+		cons: "accelerators=0 accelerator-type=grid-k520",
+		err:  `ambiguous constraints: "accelerator-type" requires "accelerators" greater than 0`,
+	},
 }
 
 func (s *validationSuite) TestValidation(c *gc.C) {
@@ -49,6 +49,17 @@ type StartInstanceParams struct {
 	// NetworkInfo is an optional list of network interface details,
 	// necessary to configure on the instance.
 	NetworkInfo []network.InterfaceInfo
+
+	// SubnetsToZones is an optional map of provider subnet ids to the
+	// availability zones they are in. When non-empty, the instance
+	// must be started in one of those subnets, in one of their zones.
+	SubnetsToZones map[string][]string
+
+	// ExcludeZones holds the names of availability zones the instance
+	// must not be started in, because the placement policy of a
+	// service with units assigned to the machine forbids it. It is
+	// only consulted when no zone is given by Placement.
+	ExcludeZones []string
 }
 
 // StartInstanceResult holds the result of an
@@ -9,8 +9,10 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
+	"unicode"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
@@ -95,6 +97,21 @@ const (
 	// config setting. Only non-zero, positive integer values will
 	// have effect.
 	DefaultLXCDefaultMTU = 0
+
+	// DefaultProvisionerRetryCount is the default number of times
+	// the provisioner automatically retries starting an instance
+	// after a provider failure. The provisioner cannot tell transient
+	// failures from permanent ones, so automatic retries are opt-in.
+	DefaultProvisionerRetryCount = 0
+
+	// DefaultProvisionerRetryDelay is the default amount of time
+	// before the first automatic retry, in seconds. The delay doubles
+	// with each subsequent attempt.
+	DefaultProvisionerRetryDelay = 30
+
+	// DefaultLoginLockoutDuration is the default amount of time, in
+	// seconds, a user is locked out for after too many failed logins.
+	DefaultLoginLockoutDuration = 900
 )
 
 // TODO(katco-): Please grow this over time.
@@ -171,6 +188,78 @@ const (
 	// interfaces created for LXC containers. See also bug #1442257.
 	LXCDefaultMTU = "lxc-default-mtu"
 
+	// ProvisionerRetryCountKey stores the number of times the
+	// provisioner automatically retries starting an instance after a
+	// provider failure, before waiting for "juju retry-provisioning".
+	ProvisionerRetryCountKey = "provisioner-retry-count"
+
+	// ProvisionerRetryDelayKey stores the number of seconds before
+	// the first automatic provisioning retry.
+	ProvisionerRetryDelayKey = "provisioner-retry-delay"
+
+	// ProvisionerFallbackKey stores whether automatic provisioning
+	// retries may ignore the zone placement and instance-type
+	// constraint of the machine.
+	ProvisionerFallbackKey = "provisioner-fallback"
+
+	// IdentityProviderKey stores the external identity provider that
+	// authenticates users who do not have a local password: "ldap" or
+	// "oidc". Only the setting of the state server environment is used.
+	IdentityProviderKey = "identity-provider"
+
+	// IdentityURLKey stores the address of the external identity
+	// provider: the ldap:// or ldaps:// URL of the LDAP server, or the
+	// issuer URL of the OpenID Connect provider.
+	IdentityURLKey = "identity-url"
+
+	// IdentityGroupAccessKey stores a space-separated list of
+	// group=access pairs, mapping the groups of externally
+	// authenticated users to the access they are given.
+	IdentityGroupAccessKey = "identity-group-access"
+
+	// LDAPUserDNKey stores the template of the distinguished name that
+	// users are bound as, with %s standing for the user name.
+	LDAPUserDNKey = "ldap-user-dn"
+
+	// OIDCClientIDKey and OIDCClientSecretKey store the credentials of
+	// the state server as a client of the OpenID Connect provider.
+	OIDCClientIDKey     = "oidc-client-id"
+	OIDCClientSecretKey = "oidc-client-secret"
+
+	// PasswordMinLengthKey stores the minimum length of user
+	// passwords. Like the other password and login settings, only the
+	// setting of the state server environment is used.
+	PasswordMinLengthKey = "password-min-length"
+
+	// PasswordMinClassesKey stores the number of character classes
+	// (lower case letters, upper case letters, digits and others) user
+	// passwords must contain.
+	PasswordMinClassesKey = "password-min-classes"
+
+	// PasswordMaxAgeKey stores the number of days after which users
+	// must change their passwords.
+	PasswordMaxAgeKey = "password-max-age"
+
+	// LoginLockoutAttemptsKey stores the number of consecutive failed
+	// logins after which a user is locked out.
+	LoginLockoutAttemptsKey = "login-lockout-attempts"
+
+	// LoginLockoutDurationKey stores the number of seconds a user is
+	// locked out for.
+	LoginLockoutDurationKey = "login-lockout-duration"
+
+	// LoginRateLimitKey stores the number of user logins accepted
+	// from a single address each minute.
+	LoginRateLimitKey = "login-rate-limit"
+
+	// APIRateLimitsKey stores a space-separated list of class=limit
+	// pairs giving the number of API calls each user may make each
+	// minute in each class of facade methods. A class is a facade
+	// method ("Client.FullStatus"), a facade ("Client"), or all
+	// other methods ("*"); a limit of zero lifts the limits of the
+	// less specific classes.
+	APIRateLimitsKey = "api-rate-limits"
+
 	//
 	// Deprecated Settings Attributes
 	//
@@ -639,10 +728,114 @@ func Validate(cfg, old *Config) error {
 		return errors.Errorf("%s: expected positive integer, got %v", LXCDefaultMTU, lxcDefaultMTU)
 	}
 
+	for _, key := range []string{
+		ProvisionerRetryCountKey, ProvisionerRetryDelayKey,
+		PasswordMinLengthKey, PasswordMaxAgeKey,
+		LoginLockoutAttemptsKey, LoginLockoutDurationKey, LoginRateLimitKey,
+	} {
+		if v, ok := cfg.defined[key].(int); ok && v < 0 {
+			return errors.Errorf("%s: expected non-negative integer, got %v", key, v)
+		}
+	}
+
+	if v, ok := cfg.defined[PasswordMinClassesKey].(int); ok && (v < 0 || v > 4) {
+		return errors.Errorf("%s: expected integer between 0 and 4, got %v", PasswordMinClassesKey, v)
+	}
+
+	if _, err := ParseAPIRateLimits(cfg.asString(APIRateLimitsKey)); err != nil {
+		return errors.Annotate(err, APIRateLimitsKey)
+	}
+
+	if err := validateExternalIdentity(cfg); err != nil {
+		return errors.Trace(err)
+	}
+
 	cfg.defined = ProcessDeprecatedAttributes(cfg.defined)
 	return nil
 }
 
+func validateExternalIdentity(cfg *Config) error {
+	opts := cfg.ExternalIdentity()
+	switch opts.Provider {
+	case "":
+		return nil
+	case LDAPIdentityProvider:
+		if strings.Count(opts.LDAPUserDN, "%s") != 1 {
+			return errors.Errorf("%s: expected template containing %%s once, got %q", LDAPUserDNKey, opts.LDAPUserDN)
+		}
+	case OIDCIdentityProvider:
+		if opts.OIDCClientID == "" {
+			return errors.Errorf("%s: must be set for identity provider %q", OIDCClientIDKey, opts.Provider)
+		}
+	default:
+		return errors.Errorf("%s: expected %q or %q, got %q",
+			IdentityProviderKey, LDAPIdentityProvider, OIDCIdentityProvider, opts.Provider)
+	}
+	if opts.URL == "" {
+		return errors.Errorf("%s: must be set for identity provider %q", IdentityURLKey, opts.Provider)
+	}
+	if _, err := parseGroupAccess(cfg.asString(IdentityGroupAccessKey)); err != nil {
+		return errors.Annotate(err, IdentityGroupAccessKey)
+	}
+	return nil
+}
+
+// parseGroupAccess parses a space-separated list of group=access pairs.
+func parseGroupAccess(s string) (map[string]string, error) {
+	result := make(map[string]string)
+	for _, pair := range strings.Fields(s) {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, errors.Errorf("expected group=access, got %q", pair)
+		}
+		switch parts[1] {
+		case ExternalAccessLogin, ExternalAccessEnvironment:
+		default:
+			return nil, errors.Errorf("expected access %q or %q for group %q, got %q",
+				ExternalAccessLogin, ExternalAccessEnvironment, parts[0], parts[1])
+		}
+		result[parts[0]] = parts[1]
+	}
+	return result, nil
+}
+
+// ParseAPIRateLimits parses a space-separated list of class=limit
+// pairs, as held by the api-rate-limits setting, into a map from
+// method class to the number of calls allowed each minute.
+func ParseAPIRateLimits(s string) (map[string]int, error) {
+	result := make(map[string]int)
+	for _, pair := range strings.Fields(s) {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 || !isValidMethodClass(parts[0]) {
+			return nil, errors.Errorf("expected class=limit, got %q", pair)
+		}
+		limit, err := strconv.Atoi(parts[1])
+		if err != nil || limit < 0 {
+			return nil, errors.Errorf("expected non-negative integer limit for class %q, got %q", parts[0], parts[1])
+		}
+		result[parts[0]] = limit
+	}
+	return result, nil
+}
+
+// isValidMethodClass returns whether class is "*", a facade name, or a
+// facade name and a method name separated by a dot.
+func isValidMethodClass(class string) bool {
+	if class == "*" {
+		return true
+	}
+	parts := strings.Split(class, ".")
+	if len(parts) > 2 {
+		return false
+	}
+	for _, part := range parts {
+		if part == "" || strings.ContainsAny(part, "*=") {
+			return false
+		}
+	}
+	return true
+}
+
 func isEmpty(val interface{}) bool {
 	switch val := val.(type) {
 	case nil:
@@ -1082,6 +1275,71 @@ func (c *Config) ProvisionerHarvestMode() HarvestMode {
 	}
 }
 
+// ProvisionerRetryOpts returns the automatic retry policy the
+// provisioner applies when starting an instance fails.
+func (c *Config) ProvisionerRetryOpts() ProvisionerRetryOpts {
+	opts := ProvisionerRetryOpts{
+		RetryCount: DefaultProvisionerRetryCount,
+		RetryDelay: time.Duration(DefaultProvisionerRetryDelay) * time.Second,
+	}
+	if v, ok := c.defined[ProvisionerRetryCountKey].(int); ok {
+		opts.RetryCount = v
+	}
+	if v, ok := c.defined[ProvisionerRetryDelayKey].(int); ok && v != 0 {
+		opts.RetryDelay = time.Duration(v) * time.Second
+	}
+	opts.Fallback, _ = c.defined[ProvisionerFallbackKey].(bool)
+	return opts
+}
+
+// PasswordPolicy returns the policy user passwords must follow.
+func (c *Config) PasswordPolicy() PasswordPolicy {
+	var policy PasswordPolicy
+	policy.MinLength, _ = c.defined[PasswordMinLengthKey].(int)
+	policy.MinClasses, _ = c.defined[PasswordMinClassesKey].(int)
+	if v, ok := c.defined[PasswordMaxAgeKey].(int); ok {
+		policy.MaxAge = time.Duration(v) * 24 * time.Hour
+	}
+	return policy
+}
+
+// LoginPolicy returns the limits on failed and repeated user logins.
+func (c *Config) LoginPolicy() LoginPolicy {
+	policy := LoginPolicy{
+		LockoutDuration: time.Duration(DefaultLoginLockoutDuration) * time.Second,
+	}
+	policy.LockoutAttempts, _ = c.defined[LoginLockoutAttemptsKey].(int)
+	if v, ok := c.defined[LoginLockoutDurationKey].(int); ok && v != 0 {
+		policy.LockoutDuration = time.Duration(v) * time.Second
+	}
+	policy.RateLimit, _ = c.defined[LoginRateLimitKey].(int)
+	return policy
+}
+
+// APIRateLimits returns the number of API calls each user may make each
+// minute in each class of facade methods, keyed by class. Classes
+// without a limit are not included.
+func (c *Config) APIRateLimits() map[string]int {
+	// The limits have been checked by Validate.
+	limits, _ := ParseAPIRateLimits(c.asString(APIRateLimitsKey))
+	return limits
+}
+
+// ExternalIdentity returns the settings of the external identity
+// provider that authenticates users, if any.
+func (c *Config) ExternalIdentity() ExternalIdentityOpts {
+	opts := ExternalIdentityOpts{
+		Provider:         c.asString(IdentityProviderKey),
+		URL:              c.asString(IdentityURLKey),
+		LDAPUserDN:       c.asString(LDAPUserDNKey),
+		OIDCClientID:     c.asString(OIDCClientIDKey),
+		OIDCClientSecret: c.asString(OIDCClientSecretKey),
+	}
+	// The group access has been checked by Validate.
+	opts.GroupAccess, _ = parseGroupAccess(c.asString(IdentityGroupAccessKey))
+	return opts
+}
+
 // ImageStream returns the simplestreams stream
 // used to identify which image ids to search
 // when starting an instance.
@@ -1253,6 +1511,22 @@ var alwaysOptional = schema.Defaults{
 	"ca-private-key-path":        schema.Omit,
 	"logging-config":             schema.Omit,
 	ProvisionerHarvestModeKey:    schema.Omit,
+	ProvisionerRetryCountKey:     schema.Omit,
+	ProvisionerRetryDelayKey:     schema.Omit,
+	ProvisionerFallbackKey:       schema.Omit,
+	IdentityProviderKey:          schema.Omit,
+	IdentityURLKey:               schema.Omit,
+	IdentityGroupAccessKey:       schema.Omit,
+	LDAPUserDNKey:                schema.Omit,
+	OIDCClientIDKey:              schema.Omit,
+	OIDCClientSecretKey:          schema.Omit,
+	PasswordMinLengthKey:         schema.Omit,
+	PasswordMinClassesKey:        schema.Omit,
+	PasswordMaxAgeKey:            schema.Omit,
+	LoginLockoutAttemptsKey:      schema.Omit,
+	LoginLockoutDurationKey:      schema.Omit,
+	LoginRateLimitKey:            schema.Omit,
+	APIRateLimitsKey:             schema.Omit,
 	"bootstrap-timeout":          schema.Omit,
 	"bootstrap-retry-delay":      schema.Omit,
 	"bootstrap-addresses-delay":  schema.Omit,
@@ -1468,6 +1742,119 @@ type SSHTimeoutOpts struct {
 	AddressesDelay time.Duration
 }
 
+// ProvisionerRetryOpts holds the policy the provisioner follows when
+// starting an instance fails.
+type ProvisionerRetryOpts struct {
+	// RetryCount is the number of automatic retries after the first
+	// failed attempt. Zero disables automatic retries.
+	RetryCount int
+
+	// RetryDelay is the amount of time before the first retry. The
+	// delay doubles with each subsequent retry.
+	RetryDelay time.Duration
+
+	// Fallback reports whether retries may ignore the machine's zone
+	// placement directive and instance-type constraint.
+	Fallback bool
+}
+
+// PasswordPolicy holds the rules user passwords must follow.
+type PasswordPolicy struct {
+	// MinLength is the minimum length of passwords.
+	MinLength int
+
+	// MinClasses is the number of character classes, out of lower
+	// case letters, upper case letters, digits and others, that
+	// passwords must contain.
+	MinClasses int
+
+	// MaxAge is how long a password may be used before it must be
+	// changed. Zero means passwords never expire.
+	MaxAge time.Duration
+}
+
+// Check returns an error describing how the password breaks the policy,
+// if it does.
+func (p PasswordPolicy) Check(password string) error {
+	if len(password) < p.MinLength {
+		return errors.Errorf("password must be at least %d characters long", p.MinLength)
+	}
+	var lower, upper, digit, other int
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = 1
+		case unicode.IsUpper(r):
+			upper = 1
+		case unicode.IsDigit(r):
+			digit = 1
+		default:
+			other = 1
+		}
+	}
+	if lower+upper+digit+other < p.MinClasses {
+		return errors.Errorf("password must contain %d of lower case letters, upper case letters, digits and other characters", p.MinClasses)
+	}
+	return nil
+}
+
+// LoginPolicy holds the limits on failed and repeated user logins.
+type LoginPolicy struct {
+	// LockoutAttempts is the number of consecutive failed logins
+	// after which a user is locked out. Zero disables lockouts.
+	LockoutAttempts int
+
+	// LockoutDuration is how long a user is locked out for.
+	LockoutDuration time.Duration
+
+	// RateLimit is the number of user logins accepted from a single
+	// address each minute. Zero means there is no limit.
+	RateLimit int
+}
+
+const (
+	// LDAPIdentityProvider and OIDCIdentityProvider are the values
+	// of the identity-provider setting.
+	LDAPIdentityProvider = "ldap"
+	OIDCIdentityProvider = "oidc"
+
+	// ExternalAccessLogin gives externally authenticated users
+	// access to the state server only; they need to be given access
+	// to environments like local users.
+	ExternalAccessLogin = "login"
+
+	// ExternalAccessEnvironment additionally gives externally
+	// authenticated users access to any environment they log in to.
+	ExternalAccessEnvironment = "environment"
+)
+
+// ExternalIdentityOpts holds the settings of the external identity
+// provider that authenticates users.
+type ExternalIdentityOpts struct {
+	// Provider is LDAPIdentityProvider, OIDCIdentityProvider, or
+	// empty if users are only authenticated locally.
+	Provider string
+
+	// URL is the address of the LDAP server, or the issuer URL of
+	// the OpenID Connect provider.
+	URL string
+
+	// LDAPUserDN is the template of the distinguished name users are
+	// bound as, with %s standing for the user name.
+	LDAPUserDN string
+
+	// OIDCClientID and OIDCClientSecret are the credentials of the
+	// state server with the OpenID Connect provider.
+	OIDCClientID     string
+	OIDCClientSecret string
+
+	// GroupAccess maps the groups of externally authenticated users
+	// to the access they are given: ExternalAccessLogin or
+	// ExternalAccessEnvironment. Users in none of the groups cannot
+	// log in.
+	GroupAccess map[string]string
+}
+
 func addIfNotEmpty(settings map[string]interface{}, key, value string) {
 	if value != "" {
 		settings[key] = value
@@ -1552,6 +1939,11 @@ var configSchema = environschema.Fields{
 		Group:       environschema.EnvironGroup,
 		Immutable:   true,
 	},
+	APIRateLimitsKey: {
+		Description: "Space-separated class=limit pairs giving the number of API calls each user may make each minute in each class of facade methods (\"Facade.Method\", \"Facade\" or \"*\")",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
 	AptFtpProxyKey: {
 		// TODO document acceptable format
 		Description: "The APT FTP proxy for the environment",
@@ -1701,6 +2093,22 @@ global or per instance security groups.`,
 		Type:        environschema.Tstring,
 		Group:       environschema.EnvironGroup,
 	},
+	IdentityGroupAccessKey: {
+		Description: "Space-separated group=access pairs giving externally authenticated users in each group \"login\" or \"environment\" access",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	IdentityProviderKey: {
+		Description: "The external identity provider that authenticates users",
+		Type:        environschema.Tstring,
+		Values:      []interface{}{LDAPIdentityProvider, OIDCIdentityProvider},
+		Group:       environschema.EnvironGroup,
+	},
+	IdentityURLKey: {
+		Description: "The URL of the LDAP server, or the issuer URL of the OpenID Connect provider",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
 	"image-metadata-url": {
 		Description: "The URL at which the metadata used to locate OS image ids is located",
 		Type:        environschema.Tstring,
@@ -1735,6 +2143,27 @@ global or per instance security groups.`,
 		Immutable:   true,
 		Group:       environschema.EnvironGroup,
 	},
+	LDAPUserDNKey: {
+		Description: "The distinguished name users are bound as on the LDAP server, with %s standing for the user name",
+		Type:        environschema.Tstring,
+		Example:     "uid=%s,ou=people,dc=example,dc=com",
+		Group:       environschema.EnvironGroup,
+	},
+	LoginLockoutAttemptsKey: {
+		Description: "The number of consecutive failed logins after which a user is locked out (default 0, no lockout)",
+		Type:        environschema.Tint,
+		Group:       environschema.EnvironGroup,
+	},
+	LoginLockoutDurationKey: {
+		Description: "The number of seconds a user is locked out for after too many failed logins (default 900)",
+		Type:        environschema.Tint,
+		Group:       environschema.EnvironGroup,
+	},
+	LoginRateLimitKey: {
+		Description: "The number of user logins accepted from a single address each minute (default 0, no limit)",
+		Type:        environschema.Tint,
+		Group:       environschema.EnvironGroup,
+	},
 	LxcUseClone: {
 		Description: `Whether the LXC provisioner should create a template and use cloning to speed up container provisioning. (deprecated by lxc-clone)`,
 		Type:        environschema.Tbool,
@@ -1751,12 +2180,38 @@ global or per instance security groups.`,
 		Type:        environschema.Tstring,
 		Group:       environschema.EnvironGroup,
 	},
+	PasswordMaxAgeKey: {
+		Description: "The number of days after which users must change their passwords (default 0, passwords do not expire)",
+		Type:        environschema.Tint,
+		Group:       environschema.EnvironGroup,
+	},
+	PasswordMinClassesKey: {
+		Description: "The number of character classes (lower case, upper case, digits, others) user passwords must contain",
+		Type:        environschema.Tint,
+		Group:       environschema.EnvironGroup,
+	},
+	PasswordMinLengthKey: {
+		Description: "The minimum length of user passwords",
+		Type:        environschema.Tint,
+		Group:       environschema.EnvironGroup,
+	},
 	"prefer-ipv6": {
 		Description: `Whether to prefer IPv6 over IPv4 addresses for API endpoints and machines`,
 		Type:        environschema.Tbool,
 		Immutable:   true,
 		Group:       environschema.EnvironGroup,
 	},
+	OIDCClientIDKey: {
+		Description: "The client id of the state server with the OpenID Connect provider",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	OIDCClientSecretKey: {
+		Description: "The client secret of the state server with the OpenID Connect provider",
+		Type:        environschema.Tstring,
+		Secret:      true,
+		Group:       environschema.EnvironGroup,
+	},
 	ProvisionerHarvestModeKey: {
 		// default: destroyed, but also depends on current setting of ProvisionerSafeModeKey
 		Description: "What to do with unknown machines. See https://jujucharms.com/docs/stable/config-general#juju-lifecycle-and-harvesting (default destroyed)",
@@ -1764,6 +2219,21 @@ global or per instance security groups.`,
 		Values:      []interface{}{"all", "none", "unknown", "destroyed"},
 		Group:       environschema.EnvironGroup,
 	},
+	ProvisionerRetryCountKey: {
+		Description: "The number of times to automatically retry starting an instance after a provider failure (default 0, disabled)",
+		Type:        environschema.Tint,
+		Group:       environschema.EnvironGroup,
+	},
+	ProvisionerRetryDelayKey: {
+		Description: "The number of seconds before the first automatic provisioning retry; the delay doubles with each retry (default 30)",
+		Type:        environschema.Tint,
+		Group:       environschema.EnvironGroup,
+	},
+	ProvisionerFallbackKey: {
+		Description: "Whether automatic provisioning retries may ignore the zone placement and instance-type constraint of a machine",
+		Type:        environschema.Tbool,
+		Group:       environschema.EnvironGroup,
+	},
 	ProvisionerSafeModeKey: {
 		Description: `Whether to run the provisioner in "destroyed" harvest mode (deprecated, superceded by provisioner-harvest-mode)`,
 		Type:        environschema.Tbool,
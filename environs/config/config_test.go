@@ -257,6 +257,117 @@ var configTests = []configTest{
 			"lxc-default-mtu": -42,
 		},
 		err: `lxc-default-mtu: expected positive integer, got -42`,
+	}, {
+		about:       "provisioner retry policy set explicitly",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                    "my-type",
+			"name":                    "my-name",
+			"provisioner-retry-count": 3,
+			"provisioner-retry-delay": 60,
+			"provisioner-fallback":    true,
+		},
+	}, {
+		about:       "provisioner retry count invalid (negative)",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                    "my-type",
+			"name":                    "my-name",
+			"provisioner-retry-count": -1,
+		},
+		err: `provisioner-retry-count: expected non-negative integer, got -1`,
+	}, {
+		about:       "LDAP identity provider",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                  "my-type",
+			"name":                  "my-name",
+			"identity-provider":     "ldap",
+			"identity-url":          "ldaps://ldap.example.com",
+			"ldap-user-dn":          "uid=%s,ou=people,dc=example,dc=com",
+			"identity-group-access": "admins=environment staff=login",
+		},
+	}, {
+		about:       "LDAP identity provider without user DN template",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":              "my-type",
+			"name":              "my-name",
+			"identity-provider": "ldap",
+			"identity-url":      "ldaps://ldap.example.com",
+			"ldap-user-dn":      "ou=people,dc=example,dc=com",
+		},
+		err: `ldap-user-dn: expected template containing %s once, got "ou=people,dc=example,dc=com"`,
+	}, {
+		about:       "OIDC identity provider without URL",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":              "my-type",
+			"name":              "my-name",
+			"identity-provider": "oidc",
+			"oidc-client-id":    "juju",
+		},
+		err: `identity-url: must be set for identity provider "oidc"`,
+	}, {
+		about:       "identity group access invalid",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                  "my-type",
+			"name":                  "my-name",
+			"identity-provider":     "oidc",
+			"identity-url":          "https://accounts.example.com",
+			"oidc-client-id":        "juju",
+			"identity-group-access": "admins=root",
+		},
+		err: `identity-group-access: expected access "login" or "environment" for group "admins", got "root"`,
+	}, {
+		about:       "Password and login policy",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                   "my-type",
+			"name":                   "my-name",
+			"password-min-length":    12,
+			"password-min-classes":   3,
+			"password-max-age":       90,
+			"login-lockout-attempts": 5,
+			"login-lockout-duration": 600,
+			"login-rate-limit":       30,
+		},
+	}, {
+		about:       "Password min classes invalid",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                 "my-type",
+			"name":                 "my-name",
+			"password-min-classes": 5,
+		},
+		err: `password-min-classes: expected integer between 0 and 4, got 5`,
+	}, {
+		about:       "Login lockout attempts invalid (negative)",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                   "my-type",
+			"name":                   "my-name",
+			"login-lockout-attempts": -1,
+		},
+		err: `login-lockout-attempts: expected non-negative integer, got -1`,
+	}, {
+		about:       "API rate limits",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":            "my-type",
+			"name":            "my-name",
+			"api-rate-limits": "Client.FullStatus=30 Action=120 *=600",
+		},
+	}, {
+		about:       "API rate limits invalid",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":            "my-type",
+			"name":            "my-name",
+			"api-rate-limits": "Client.FullStatus=often",
+		},
+		err: `api-rate-limits: expected non-negative integer limit for class "Client.FullStatus", got "often"`,
 	}, {
 		about:       "CA cert & key from path",
 		useDefaults: config.UseDefaults,
@@ -1701,6 +1812,150 @@ func (s *ConfigSuite) TestLoggingConfigFromEnvironment(c *gc.C) {
 	c.Assert(config.LoggingConfig(), gc.Equals, "<root>=INFO;unit=DEBUG")
 }
 
+func (s *ConfigSuite) TestProvisionerRetryOpts(c *gc.C) {
+	s.addJujuFiles(c)
+
+	cfg := newTestConfig(c, nil)
+	c.Assert(cfg.ProvisionerRetryOpts(), gc.Equals, config.ProvisionerRetryOpts{
+		RetryCount: 0,
+		RetryDelay: 30 * time.Second,
+	})
+
+	cfg = newTestConfig(c, testing.Attrs{
+		"provisioner-retry-count": 3,
+		"provisioner-retry-delay": 10,
+		"provisioner-fallback":    true,
+	})
+	c.Assert(cfg.ProvisionerRetryOpts(), gc.Equals, config.ProvisionerRetryOpts{
+		RetryCount: 3,
+		RetryDelay: 10 * time.Second,
+		Fallback:   true,
+	})
+}
+
+func (s *ConfigSuite) TestPasswordAndLoginPolicy(c *gc.C) {
+	s.addJujuFiles(c)
+
+	cfg := newTestConfig(c, nil)
+	c.Assert(cfg.PasswordPolicy(), gc.Equals, config.PasswordPolicy{})
+	c.Assert(cfg.LoginPolicy(), gc.Equals, config.LoginPolicy{
+		LockoutDuration: 15 * time.Minute,
+	})
+
+	cfg = newTestConfig(c, testing.Attrs{
+		"password-min-length":    12,
+		"password-min-classes":   3,
+		"password-max-age":       90,
+		"login-lockout-attempts": 5,
+		"login-lockout-duration": 600,
+		"login-rate-limit":       30,
+	})
+	c.Assert(cfg.PasswordPolicy(), gc.Equals, config.PasswordPolicy{
+		MinLength:  12,
+		MinClasses: 3,
+		MaxAge:     90 * 24 * time.Hour,
+	})
+	c.Assert(cfg.LoginPolicy(), gc.Equals, config.LoginPolicy{
+		LockoutAttempts: 5,
+		LockoutDuration: 10 * time.Minute,
+		RateLimit:       30,
+	})
+}
+
+func (s *ConfigSuite) TestAPIRateLimits(c *gc.C) {
+	s.addJujuFiles(c)
+
+	cfg := newTestConfig(c, nil)
+	c.Assert(cfg.APIRateLimits(), gc.HasLen, 0)
+
+	cfg = newTestConfig(c, testing.Attrs{
+		"api-rate-limits": "Client.FullStatus=30 Action=120 *=600",
+	})
+	c.Assert(cfg.APIRateLimits(), jc.DeepEquals, map[string]int{
+		"Client.FullStatus": 30,
+		"Action":            120,
+		"*":                 600,
+	})
+}
+
+func (s *ConfigSuite) TestParseAPIRateLimitsInvalid(c *gc.C) {
+	for i, test := range []struct {
+		value string
+		err   string
+	}{{
+		value: "Client",
+		err:   `expected class=limit, got "Client"`,
+	}, {
+		value: "Client.Full.Status=10",
+		err:   `expected class=limit, got "Client.Full.Status=10"`,
+	}, {
+		value: "Client.*=10",
+		err:   `expected class=limit, got "Client.\*=10"`,
+	}, {
+		value: "Client=-1",
+		err:   `expected non-negative integer limit for class "Client", got "-1"`,
+	}} {
+		c.Logf("test %d: %q", i, test.value)
+		_, err := config.ParseAPIRateLimits(test.value)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *ConfigSuite) TestPasswordPolicyCheck(c *gc.C) {
+	policy := config.PasswordPolicy{MinLength: 8, MinClasses: 3}
+	for i, test := range []struct {
+		password string
+		err      string
+	}{{
+		password: "Sh0rt",
+		err:      "password must be at least 8 characters long",
+	}, {
+		password: "alllowercase",
+		err:      "password must contain 3 of lower case letters, upper case letters, digits and other characters",
+	}, {
+		password: "lower-and-symbols",
+		err:      "password must contain 3 of lower case letters, upper case letters, digits and other characters",
+	}, {
+		password: "Mixed-Case",
+	}, {
+		password: "numb3rs-too",
+	}} {
+		c.Logf("test %d: %q", i, test.password)
+		err := policy.Check(test.password)
+		if test.err == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.err)
+		}
+	}
+	c.Assert(config.PasswordPolicy{}.Check(""), jc.ErrorIsNil)
+}
+
+func (s *ConfigSuite) TestExternalIdentity(c *gc.C) {
+	s.addJujuFiles(c)
+
+	cfg := newTestConfig(c, nil)
+	c.Assert(cfg.ExternalIdentity().Provider, gc.Equals, "")
+
+	cfg = newTestConfig(c, testing.Attrs{
+		"identity-provider":     "oidc",
+		"identity-url":          "https://accounts.example.com",
+		"oidc-client-id":        "juju",
+		"oidc-client-secret":    "sekrit",
+		"identity-group-access": "admins=environment staff=login",
+	})
+	c.Assert(cfg.ExternalIdentity(), jc.DeepEquals, config.ExternalIdentityOpts{
+		Provider:         "oidc",
+		URL:              "https://accounts.example.com",
+		OIDCClientID:     "juju",
+		OIDCClientSecret: "sekrit",
+		GroupAccess: map[string]string{
+			"admins": "environment",
+			"staff":  "login",
+		},
+	})
+}
+
 func (s *ConfigSuite) TestProxyValuesWithFallback(c *gc.C) {
 	s.addJujuFiles(c)
 
@@ -76,6 +76,11 @@ func FindInstanceSpec(possibleImages []Image, ic *InstanceConstraint, allInstanc
 	specs := []*InstanceSpec{}
 	for _, itype := range matchingTypes {
 		for _, image := range possibleImages {
+			// Images of unknown virtualisation type are assumed to
+			// satisfy a virt-type constraint.
+			if ic.Constraints.HasVirtType() && image.VirtType != "" && image.VirtType != *ic.Constraints.VirtType {
+				continue
+			}
 			if image.match(itype) {
 				specs = append(specs, &InstanceSpec{
 					InstanceType: itype,
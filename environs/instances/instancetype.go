@@ -23,6 +23,12 @@ type InstanceType struct {
 	VirtType *string // The type of virtualisation used by the hypervisor, must match the image.
 	CpuPower *uint64
 	Tags     []string
+	// LocalDisks is the number of local (instance store) disks.
+	LocalDisks uint64
+	// Accelerators is the number of GPUs or other accelerators, all of
+	// which are of type AcceleratorType.
+	Accelerators    uint64
+	AcceleratorType string
 }
 
 func CpuPower(power uint64) *uint64 {
@@ -58,6 +64,18 @@ func (itype InstanceType) match(cons constraints.Value) (InstanceType, bool) {
 	if cons.Tags != nil && len(*cons.Tags) > 0 && !tagsMatch(*cons.Tags, itype.Tags) {
 		return nothing, false
 	}
+	if cons.HasVirtType() && itype.VirtType != nil && *itype.VirtType != *cons.VirtType {
+		return nothing, false
+	}
+	if cons.LocalDisks != nil && itype.LocalDisks < *cons.LocalDisks {
+		return nothing, false
+	}
+	if itype.Accelerators < cons.MinAccelerators() {
+		return nothing, false
+	}
+	if cons.HasAcceleratorType() && itype.AcceleratorType != *cons.AcceleratorType {
+		return nothing, false
+	}
 	return itype, true
 }
 
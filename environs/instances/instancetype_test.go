@@ -19,7 +19,10 @@ type instanceTypeSuite struct {
 
 var _ = gc.Suite(&instanceTypeSuite{})
 
-var hvm = "hvm"
+var (
+	hvm         = "hvm"
+	paravirtual = "pv"
+)
 
 // The instance types below do not necessarily reflect reality and are just
 // defined here for ease of testing special cases.
@@ -201,6 +204,46 @@ var getInstanceTypesTest = []struct {
 		},
 		expectedItypes: []string{"it-2"},
 	},
+	{
+		about: "local-disks",
+		cons:  "local-disks=2",
+		itypesToUse: []InstanceType{
+			{Id: "3", Name: "it-3", Arches: []string{"amd64"}, Mem: 4096, LocalDisks: 4},
+			{Id: "2", Name: "it-2", Arches: []string{"amd64"}, Mem: 4096, LocalDisks: 1},
+			{Id: "1", Name: "it-1", Arches: []string{"amd64"}, Mem: 4096, LocalDisks: 2},
+		},
+		expectedItypes: []string{"it-1", "it-3"},
+	},
+	{
+		about: "virt-type",
+		cons:  "virt-type=hvm",
+		itypesToUse: []InstanceType{
+			{Id: "3", Name: "it-3", Arches: []string{"amd64"}, Mem: 4096, VirtType: &hvm},
+			{Id: "2", Name: "it-2", Arches: []string{"amd64"}, Mem: 4096, VirtType: &paravirtual},
+			{Id: "1", Name: "it-1", Arches: []string{"amd64"}, Mem: 4096},
+		},
+		expectedItypes: []string{"it-1", "it-3"},
+	},
+	{
+		about: "accelerators",
+		cons:  "accelerators=2",
+		itypesToUse: []InstanceType{
+			{Id: "3", Name: "it-3", Arches: []string{"amd64"}, Mem: 4096, Accelerators: 2, AcceleratorType: "gpu-a"},
+			{Id: "2", Name: "it-2", Arches: []string{"amd64"}, Mem: 4096, Accelerators: 1, AcceleratorType: "gpu-b"},
+			{Id: "1", Name: "it-1", Arches: []string{"amd64"}, Mem: 4096},
+		},
+		expectedItypes: []string{"it-3"},
+	},
+	{
+		about: "accelerator-type implies one accelerator",
+		cons:  "accelerator-type=gpu-b",
+		itypesToUse: []InstanceType{
+			{Id: "3", Name: "it-3", Arches: []string{"amd64"}, Mem: 4096, Accelerators: 2, AcceleratorType: "gpu-a"},
+			{Id: "2", Name: "it-2", Arches: []string{"amd64"}, Mem: 4096, Accelerators: 1, AcceleratorType: "gpu-b"},
+			{Id: "1", Name: "it-1", Arches: []string{"amd64"}, Mem: 4096},
+		},
+		expectedItypes: []string{"it-2"},
+	},
 }
 
 func (s *instanceTypeSuite) TestGetMatchingInstanceTypes(c *gc.C) {
@@ -260,6 +303,12 @@ var instanceTypeMatchTests = []struct {
 	{"cpu-power=9001", "cc2.8xlarge", nil},
 	{"mem=1G", "t1.micro", nil},
 	{"arch=armhf", "c1.xlarge", nil},
+
+	{"virt-type=hvm", "cc1.4xlarge", []string{"amd64"}},
+	{"virt-type=hvm", "m1.small", []string{"amd64", "armhf"}},
+	{"virt-type=pv", "cc2.8xlarge", nil},
+	{"local-disks=1", "m1.small", nil},
+	{"accelerators=1", "cc2.8xlarge", nil},
 }
 
 func (s *instanceTypeSuite) TestMatch(c *gc.C) {
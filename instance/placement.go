@@ -14,6 +14,11 @@ const (
 	// MachineScope is a special scope name that is used
 	// for machine placement directives (e.g. --to 0).
 	MachineScope = "#"
+
+	// NewMachine is a special directive that is used with a
+	// container scope to place a unit in a container on a new
+	// machine (e.g. --to lxc:new).
+	NewMachine = "new"
 )
 
 var ErrPlacementScopeMissing = fmt.Errorf("placement scope missing")
@@ -31,7 +36,8 @@ type Placement struct {
 	// Directive is a scope-specific placement directive.
 	//
 	// For MachineScope or a container scope, this may be empty or
-	// the ID of an existing machine.
+	// the ID of an existing machine. An empty directive with a
+	// container scope requests a container on a new machine.
 	Directive string
 }
 
@@ -59,6 +65,9 @@ func ParsePlacement(directive string) (*Placement, error) {
 		if scope == "" {
 			return nil, ErrPlacementScopeMissing
 		}
+		if isContainerType(scope) && directive == NewMachine {
+			return &Placement{Scope: scope}, nil
+		}
 		// Sanity check: machine/container scopes require a machine ID as the value.
 		if (scope == MachineScope || isContainerType(scope)) && !names.IsValidMachine(directive) {
 			return nil, fmt.Errorf("invalid value %q for %q scope: expected machine-id", directive, scope)
@@ -45,6 +45,12 @@ func (s *PlacementSuite) TestParsePlacement(c *gc.C) {
 	}, {
 		arg:         "lxc",
 		expectScope: string(instance.LXC),
+	}, {
+		arg:         "kvm:new",
+		expectScope: string(instance.KVM),
+	}, {
+		arg: "#:new",
+		err: `invalid value "new" for "#" scope: expected machine-id`,
 	}, {
 		arg: "non-standard",
 		err: "placement scope missing",
@@ -38,6 +38,9 @@ type DeployServiceParams struct {
 	// Networks holds a list of networks to required to start on boot.
 	Networks []string
 	Storage  map[string]storage.Constraints
+	// EndpointBindings maps charm endpoint names to the spaces they
+	// should be bound to; the empty endpoint name sets the default space.
+	EndpointBindings map[string]string
 }
 
 // DeployService takes a charm and various parameters and deploys it.
@@ -94,6 +97,11 @@ func DeployService(st *state.State, args DeployServiceParams) (*state.Service, e
 			return nil, err
 		}
 	}
+	if len(args.EndpointBindings) > 0 {
+		if err := service.SetEndpointBindings(args.EndpointBindings); err != nil {
+			return nil, err
+		}
+	}
 	if args.Charm.Meta().Subordinate {
 		return service, nil
 	}
@@ -151,6 +159,13 @@ func addMachineForUnit(st *state.State, unit *state.Unit, placement *instance.Pl
 			Constraints:       *unitCons,
 			RequestedNetworks: networks,
 		}
+		if mid == "" {
+			// No parent was given, so create the container
+			// on a new machine which only hosts units.
+			parentTemplate := template
+			parentTemplate.Dirty = false
+			return st.AddMachineInsideNewMachine(template, parentTemplate, containerType)
+		}
 		return st.AddMachineInsideMachine(template, mid, containerType)
 	}
 	// If a placement directive is to be used, do that here.
@@ -246,6 +246,33 @@ func (s *DeployLocalSuite) TestDeployWithPlacement(c *gc.C) {
 	s.assertAssignedUnit(c, units[2], "1/lxc/0", constraints.MustParse("mem=2G cpu-cores=2"))
 }
 
+func (s *DeployLocalSuite) TestDeployWithNewContainerAndZonePlacement(c *gc.C) {
+	service, err := juju.DeployService(s.State,
+		juju.DeployServiceParams{
+			ServiceName: "bob",
+			Charm:       s.charm,
+			NumUnits:    2,
+			Placement: []*instance.Placement{
+				{Scope: "lxc"},
+				{Scope: s.State.EnvironUUID(), Directive: "zone=zone1"},
+			},
+		})
+	c.Assert(err, jc.ErrorIsNil)
+	units, err := service.AllUnits()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, gc.HasLen, 2)
+
+	id, err := units[0].AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(id, gc.Equals, "0/lxc/0")
+	id, err = units[1].AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(id, gc.Equals, "1")
+	machine, err := s.State.Machine(id)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machine.Placement(), gc.Equals, "zone=zone1")
+}
+
 func (s *DeployLocalSuite) TestDeployWithFewerPlacement(c *gc.C) {
 	err := s.State.SetEnvironConstraints(constraints.MustParse("mem=2G"))
 	c.Assert(err, jc.ErrorIsNil)
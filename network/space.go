@@ -0,0 +1,43 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package network
+
+import (
+	"net"
+	"regexp"
+)
+
+// validSpaceName matches valid space names: lowercase letters and
+// digits, optionally separated by single hyphens.
+var validSpaceName = regexp.MustCompile("^[a-z0-9]+(-[a-z0-9]+)*$")
+
+// IsValidSpaceName reports whether name is a valid space name.
+func IsValidSpaceName(name string) bool {
+	return validSpaceName.MatchString(name)
+}
+
+// SelectAddressInSubnets returns the first of the given addresses
+// whose IP address falls within any of the given subnet CIDRs, and
+// whether one was found. Addresses that are not IP addresses and
+// CIDRs that cannot be parsed are ignored.
+func SelectAddressInSubnets(addresses []Address, cidrs []string) (Address, bool) {
+	var nets []*net.IPNet
+	for _, cidr := range cidrs {
+		if _, ipNet, err := net.ParseCIDR(cidr); err == nil {
+			nets = append(nets, ipNet)
+		}
+	}
+	for _, addr := range addresses {
+		ip := net.ParseIP(addr.Value)
+		if ip == nil {
+			continue
+		}
+		for _, ipNet := range nets {
+			if ipNet.Contains(ip) {
+				return addr, true
+			}
+		}
+	}
+	return Address{}, false
+}
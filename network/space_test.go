@@ -0,0 +1,48 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package network_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/testing"
+)
+
+type SpaceSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&SpaceSuite{})
+
+func (s *SpaceSuite) TestIsValidSpaceName(c *gc.C) {
+	for i, test := range []struct {
+		name  string
+		valid bool
+	}{
+		{"internal", true},
+		{"db-2", true},
+		{"0", true},
+		{"", false},
+		{"Internal", false},
+		{"-db", false},
+		{"db-", false},
+		{"db--2", false},
+		{"db_2", false},
+	} {
+		c.Logf("test %d: %q", i, test.name)
+		c.Check(network.IsValidSpaceName(test.name), gc.Equals, test.valid)
+	}
+}
+
+func (s *SpaceSuite) TestSelectAddressInSubnets(c *gc.C) {
+	addresses := network.NewAddresses("example.com", "192.168.1.10", "10.0.0.5", "10.1.0.5")
+	addr, ok := network.SelectAddressInSubnets(addresses, []string{"invalid", "10.0.0.0/16"})
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(addr.Value, gc.Equals, "10.0.0.5")
+
+	_, ok = network.SelectAddressInSubnets(addresses, []string{"172.16.0.0/12"})
+	c.Assert(ok, jc.IsFalse)
+}
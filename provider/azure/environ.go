@@ -567,6 +567,11 @@ func (env *azureEnviron) selectInstanceTypeAndImage(constraint *instances.Instan
 var unsupportedConstraints = []string{
 	constraints.CpuPower,
 	constraints.Tags,
+	constraints.Spaces,
+	constraints.VirtType,
+	constraints.LocalDisks,
+	constraints.Accelerators,
+	constraints.AcceleratorType,
 }
 
 // ConstraintsValidator is defined on the Environs interface.
@@ -597,6 +602,9 @@ func (env *azureEnviron) ConstraintsValidator() (constraints.Validator, error) {
 
 // PrecheckInstance is defined on the state.Prechecker interface.
 func (env *azureEnviron) PrecheckInstance(series string, cons constraints.Value, placement string) error {
+	if zone, ok := common.ZonePlacement(placement); ok {
+		return errors.NotSupportedf("placement in availability zone %q", zone)
+	}
 	if placement != "" {
 		return fmt.Errorf("unknown placement directive: %s", placement)
 	}
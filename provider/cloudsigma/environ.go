@@ -122,6 +122,12 @@ func (env *environ) Destroy() error {
 // guaranteed that the constraints are valid; if a non-nil error is
 // returned, then the constraints are definitely invalid.
 func (env *environ) PrecheckInstance(series string, cons constraints.Value, placement string) error {
+	if zone, ok := common.ZonePlacement(placement); ok {
+		return errors.NotSupportedf("placement in availability zone %q", zone)
+	}
+	if placement != "" {
+		return errors.Errorf("unknown placement directive: %s", placement)
+	}
 	return nil
 }
 
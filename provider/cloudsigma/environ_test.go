@@ -4,6 +4,8 @@
 package cloudsigma
 
 import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/constraints"
@@ -54,6 +56,11 @@ func (s *environSuite) TestBase(c *gc.C) {
 	c.Check(cfg.Name(), gc.Equals, "testname")
 
 	c.Check(env.PrecheckInstance("", constraints.Value{}, ""), gc.IsNil)
+	err = env.PrecheckInstance("", constraints.Value{}, "zone=zone1")
+	c.Check(err, gc.ErrorMatches, `placement in availability zone "zone1" not supported`)
+	c.Check(err, jc.Satisfies, errors.IsNotSupported)
+	err = env.PrecheckInstance("", constraints.Value{}, "node1")
+	c.Check(err, gc.ErrorMatches, "unknown placement directive: node1")
 
 	hasRegion, ok := env.(simplestreams.HasRegion)
 	c.Check(ok, gc.Equals, true)
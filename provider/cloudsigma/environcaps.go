@@ -36,6 +36,11 @@ var unsupportedConstraints = []string{
 	constraints.Container,
 	constraints.InstanceType,
 	constraints.Tags,
+	constraints.Spaces,
+	constraints.VirtType,
+	constraints.LocalDisks,
+	constraints.Accelerators,
+	constraints.AcceleratorType,
 }
 
 // ConstraintsValidator returns a Validator instance which
@@ -5,6 +5,9 @@ package common
 
 import (
 	"sort"
+	"strings"
+
+	"github.com/juju/errors"
 
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/instance"
@@ -131,6 +134,39 @@ func AvailabilityZoneAllocations(env ZonedEnviron, group []instance.Id) ([]Avail
 	return zoneInstances, nil
 }
 
+// ZonePlacement returns the name of the availability zone specified by
+// an environment placement directive of the form "zone=<name>", and
+// whether the directive has that form.
+func ZonePlacement(placement string) (string, bool) {
+	if !strings.HasPrefix(placement, "zone=") {
+		return "", false
+	}
+	return strings.TrimPrefix(placement, "zone="), true
+}
+
+// ExcludeAvailabilityZones returns the given availability zone
+// allocations without those of the named zones, preserving their
+// order. It returns an error if every zone is excluded.
+func ExcludeAvailabilityZones(zoneInstances []AvailabilityZoneInstances, exclude []string) ([]AvailabilityZoneInstances, error) {
+	if len(exclude) == 0 || len(zoneInstances) == 0 {
+		return zoneInstances, nil
+	}
+	excluded := make(map[string]bool)
+	for _, zone := range exclude {
+		excluded[zone] = true
+	}
+	result := make([]AvailabilityZoneInstances, 0, len(zoneInstances))
+	for _, z := range zoneInstances {
+		if !excluded[z.ZoneName] {
+			result = append(result, z)
+		}
+	}
+	if len(result) == 0 {
+		return nil, errors.New("all availability zones are excluded by placement policy")
+	}
+	return result, nil
+}
+
 var internalAvailabilityZoneAllocations = AvailabilityZoneAllocations
 
 // DistributeInstances is a common function for implement the
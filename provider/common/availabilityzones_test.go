@@ -254,3 +254,32 @@ func (s *AvailabilityZoneSuite) TestDistributeInstances(c *gc.C) {
 		c.Assert(eligible, jc.SameContents, test.eligible)
 	}
 }
+
+func (s *AvailabilityZoneSuite) TestExcludeAvailabilityZones(c *gc.C) {
+	zoneInstances := []common.AvailabilityZoneInstances{
+		{ZoneName: "az1"},
+		{ZoneName: "az2", Instances: []instance.Id{"inst0"}},
+		{ZoneName: "az3", Instances: []instance.Id{"inst1", "inst2"}},
+	}
+	result, err := common.ExcludeAvailabilityZones(zoneInstances, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, zoneInstances)
+
+	result, err = common.ExcludeAvailabilityZones(zoneInstances, []string{"az1", "az3"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, zoneInstances[1:2])
+
+	_, err = common.ExcludeAvailabilityZones(zoneInstances, []string{"az1", "az2", "az3"})
+	c.Assert(err, gc.ErrorMatches, "all availability zones are excluded by placement policy")
+}
+
+func (s *AvailabilityZoneSuite) TestZonePlacement(c *gc.C) {
+	zone, ok := common.ZonePlacement("zone=az1")
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(zone, gc.Equals, "az1")
+
+	for _, placement := range []string{"", "az1", "node=az1", "zone"} {
+		_, ok := common.ZonePlacement(placement)
+		c.Check(ok, jc.IsFalse, gc.Commentf("placement %q", placement))
+	}
+}
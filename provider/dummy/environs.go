@@ -676,6 +676,9 @@ func (*environ) SupportedArchitectures() ([]string, error) {
 
 // PrecheckInstance is specified in the state.Prechecker interface.
 func (*environ) PrecheckInstance(series string, cons constraints.Value, placement string) error {
+	if zone, ok := common.ZonePlacement(placement); ok && zone != "" {
+		return nil
+	}
 	if placement != "" && placement != "valid" {
 		return fmt.Errorf("%s placement is invalid", placement)
 	}
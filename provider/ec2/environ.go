@@ -6,6 +6,7 @@ package ec2
 import (
 	"fmt"
 	"net"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -245,7 +246,14 @@ func (e *environ) ConstraintsValidator() (constraints.Validator, error) {
 	validator := constraints.NewValidator()
 	validator.RegisterConflicts(
 		[]string{constraints.InstanceType},
-		[]string{constraints.Mem, constraints.CpuCores, constraints.CpuPower})
+		[]string{
+			constraints.Mem,
+			constraints.CpuCores,
+			constraints.CpuPower,
+			constraints.LocalDisks,
+			constraints.Accelerators,
+			constraints.AcceleratorType,
+		})
 	validator.RegisterUnsupported(unsupportedConstraints)
 	supportedArches, err := e.SupportedArchitectures()
 	if err != nil {
@@ -257,6 +265,14 @@ func (e *environ) ConstraintsValidator() (constraints.Validator, error) {
 		instTypeNames[i] = itype.Name
 	}
 	validator.RegisterVocabulary(constraints.InstanceType, instTypeNames)
+	validator.RegisterVocabulary(constraints.VirtType, []string{paravirtual, hvm})
+	var acceleratorTypes []string
+	for _, itype := range allInstanceTypes {
+		if itype.AcceleratorType != "" {
+			acceleratorTypes = append(acceleratorTypes, itype.AcceleratorType)
+		}
+	}
+	validator.RegisterVocabulary(constraints.AcceleratorType, acceleratorTypes)
 	return validator, nil
 }
 
@@ -473,6 +489,10 @@ func (e *environ) StartInstance(args environs.StartInstanceParams) (_ *environs.
 		if err != nil {
 			return nil, err
 		}
+		zoneInstances, err = common.ExcludeAvailabilityZones(zoneInstances, args.ExcludeZones)
+		if err != nil {
+			return nil, err
+		}
 		for _, z := range zoneInstances {
 			availabilityZones = append(availabilityZones, z.ZoneName)
 		}
@@ -481,6 +501,27 @@ func (e *environ) StartInstance(args environs.StartInstanceParams) (_ *environs.
 		}
 	}
 
+	// An instance that must be started in the subnets of some spaces
+	// can only be started in the zones of those subnets.
+	var zoneSubnets map[string][]string
+	if len(args.SubnetsToZones) > 0 {
+		var err error
+		zoneSubnets, err = e.zoneSubnets(args.SubnetsToZones)
+		if err != nil {
+			return nil, err
+		}
+		var usableZones []string
+		for _, zone := range availabilityZones {
+			if len(zoneSubnets[zone]) > 0 {
+				usableZones = append(usableZones, zone)
+			}
+		}
+		if len(usableZones) == 0 {
+			return nil, errors.Errorf("no required subnet in availability zones %v", availabilityZones)
+		}
+		availabilityZones = usableZones
+	}
+
 	if args.InstanceConfig.HasNetworks() {
 		return nil, errors.New("starting instances with networks is not supported yet")
 	}
@@ -530,8 +571,13 @@ func (e *environ) StartInstance(args environs.StartInstanceParams) (_ *environs.
 	rootDiskSize := uint64(blockDeviceMappings[0].VolumeSize) * 1024
 
 	for _, availZone := range availabilityZones {
+		var subnetId string
+		if subnetIds := zoneSubnets[availZone]; len(subnetIds) > 0 {
+			subnetId = subnetIds[0]
+		}
 		instResp, err = runInstances(e.ec2(), &ec2.RunInstances{
 			AvailZone:           availZone,
+			SubnetId:            subnetId,
 			ImageId:             spec.Image.Id,
 			MinCount:            1,
 			MaxCount:            1,
@@ -588,6 +634,41 @@ func (e *environ) StartInstance(args environs.StartInstanceParams) (_ *environs.
 	}, nil
 }
 
+// zoneSubnets returns the ids of the given subnets, keyed by the
+// availability zone they are in. The zones of subnets not known to
+// be in any are looked up.
+func (e *environ) zoneSubnets(subnetsToZones map[string][]string) (map[string][]string, error) {
+	zoneSubnets := make(map[string][]string)
+	var lookup []string
+	for subnetId, zones := range subnetsToZones {
+		if len(zones) == 0 {
+			if !strings.HasPrefix(subnetId, "subnet-") {
+				// Subnets unknown to EC2 are keyed by CIDR.
+				logger.Debugf("ignoring subnet %q without provider id", subnetId)
+				continue
+			}
+			lookup = append(lookup, subnetId)
+			continue
+		}
+		for _, zone := range zones {
+			zoneSubnets[zone] = append(zoneSubnets[zone], subnetId)
+		}
+	}
+	if len(lookup) > 0 {
+		resp, err := e.ec2().Subnets(lookup, nil)
+		if err != nil {
+			return nil, errors.Annotatef(err, "cannot get subnets %v", lookup)
+		}
+		for _, subnet := range resp.Subnets {
+			zoneSubnets[subnet.AvailZone] = append(zoneSubnets[subnet.AvailZone], subnet.Id)
+		}
+	}
+	for _, subnetIds := range zoneSubnets {
+		sort.Strings(subnetIds)
+	}
+	return zoneSubnets, nil
+}
+
 // tagResources calls ec2.CreateTags, tagging each of the specified resources
 // with the given tags. tagResources will retry for a short period of time
 // if it receives a *.NotFound error response from EC2.
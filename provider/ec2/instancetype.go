@@ -16,6 +16,12 @@ var (
 	hvm         = "hvm"
 )
 
+// Types of GPU accelerator attached to the GPU instance types.
+var (
+	nvidiaGridK520   = "grid-k520"
+	nvidiaTeslaM2050 = "tesla-m2050"
+)
+
 // all instance types can run amd64 images, and some can also run i386 ones.
 var (
 	amd64 = []string{arch.AMD64}
@@ -31,254 +37,291 @@ var allRegions = aws.Regions
 // for amazon will simply cause the root disk to grow to match the constraint
 var allInstanceTypes = []instances.InstanceType{
 	{ // General purpose, 1st generation.
-		Name:     "m1.small",
-		Arches:   both,
-		CpuCores: 1,
-		CpuPower: instances.CpuPower(100),
-		Mem:      1740,
-		VirtType: &paravirtual,
+		Name:       "m1.small",
+		Arches:     both,
+		CpuCores:   1,
+		CpuPower:   instances.CpuPower(100),
+		Mem:        1740,
+		LocalDisks: 1,
+		VirtType:   &paravirtual,
 	}, {
-		Name:     "m1.medium",
-		Arches:   both,
-		CpuCores: 1,
-		CpuPower: instances.CpuPower(200),
-		Mem:      3840,
-		VirtType: &paravirtual,
+		Name:       "m1.medium",
+		Arches:     both,
+		CpuCores:   1,
+		CpuPower:   instances.CpuPower(200),
+		Mem:        3840,
+		LocalDisks: 1,
+		VirtType:   &paravirtual,
 	}, {
-		Name:     "m1.large",
-		Arches:   amd64,
-		CpuCores: 2,
-		CpuPower: instances.CpuPower(400),
-		Mem:      7680,
-		VirtType: &paravirtual,
+		Name:       "m1.large",
+		Arches:     amd64,
+		CpuCores:   2,
+		CpuPower:   instances.CpuPower(400),
+		Mem:        7680,
+		LocalDisks: 2,
+		VirtType:   &paravirtual,
 	}, {
-		Name:     "m1.xlarge",
-		Arches:   amd64,
-		CpuCores: 4,
-		CpuPower: instances.CpuPower(800),
-		Mem:      15360,
-		VirtType: &paravirtual,
+		Name:       "m1.xlarge",
+		Arches:     amd64,
+		CpuCores:   4,
+		CpuPower:   instances.CpuPower(800),
+		Mem:        15360,
+		LocalDisks: 4,
+		VirtType:   &paravirtual,
 	},
 
 	{ // General purpose, 2nd generation.
-		Name:     "m3.medium",
-		Arches:   amd64,
-		CpuCores: 1,
-		CpuPower: instances.CpuPower(300),
-		Mem:      3840,
-		VirtType: &paravirtual,
+		Name:       "m3.medium",
+		Arches:     amd64,
+		CpuCores:   1,
+		CpuPower:   instances.CpuPower(300),
+		Mem:        3840,
+		LocalDisks: 1,
+		VirtType:   &paravirtual,
 	}, {
-		Name:     "m3.large",
-		Arches:   amd64,
-		CpuCores: 2,
-		CpuPower: instances.CpuPower(650),
-		Mem:      7680,
-		VirtType: &paravirtual,
+		Name:       "m3.large",
+		Arches:     amd64,
+		CpuCores:   2,
+		CpuPower:   instances.CpuPower(650),
+		Mem:        7680,
+		LocalDisks: 1,
+		VirtType:   &paravirtual,
 	}, {
-		Name:     "m3.xlarge",
-		Arches:   amd64,
-		CpuCores: 4,
-		CpuPower: instances.CpuPower(1300),
-		Mem:      15360,
-		VirtType: &paravirtual,
+		Name:       "m3.xlarge",
+		Arches:     amd64,
+		CpuCores:   4,
+		CpuPower:   instances.CpuPower(1300),
+		Mem:        15360,
+		LocalDisks: 2,
+		VirtType:   &paravirtual,
 	}, {
-		Name:     "m3.2xlarge",
-		Arches:   amd64,
-		CpuCores: 8,
-		CpuPower: instances.CpuPower(2600),
-		Mem:      30720,
-		VirtType: &paravirtual,
+		Name:       "m3.2xlarge",
+		Arches:     amd64,
+		CpuCores:   8,
+		CpuPower:   instances.CpuPower(2600),
+		Mem:        30720,
+		LocalDisks: 2,
+		VirtType:   &paravirtual,
 	},
 
 	{ // Compute-optimized, 1st generation.
-		Name:     "c1.medium",
-		Arches:   both,
-		CpuCores: 2,
-		CpuPower: instances.CpuPower(500),
-		Mem:      1740,
-		VirtType: &paravirtual,
+		Name:       "c1.medium",
+		Arches:     both,
+		CpuCores:   2,
+		CpuPower:   instances.CpuPower(500),
+		Mem:        1740,
+		LocalDisks: 1,
+		VirtType:   &paravirtual,
 	}, {
-		Name:     "c1.xlarge",
-		Arches:   amd64,
-		CpuCores: 8,
-		CpuPower: instances.CpuPower(2000),
-		Mem:      7168,
-		VirtType: &paravirtual,
+		Name:       "c1.xlarge",
+		Arches:     amd64,
+		CpuCores:   8,
+		CpuPower:   instances.CpuPower(2000),
+		Mem:        7168,
+		LocalDisks: 4,
+		VirtType:   &paravirtual,
 	}, {
-		Name:     "cc2.8xlarge",
-		Arches:   amd64,
-		CpuCores: 16,
-		CpuPower: instances.CpuPower(8800),
-		Mem:      61952,
-		VirtType: &hvm,
+		Name:       "cc2.8xlarge",
+		Arches:     amd64,
+		CpuCores:   16,
+		CpuPower:   instances.CpuPower(8800),
+		Mem:        61952,
+		LocalDisks: 4,
+		VirtType:   &hvm,
 	},
 
 	{ // Compute-optimized, 2nd generation.
-		Name:     "c3.large",
-		Arches:   amd64,
-		CpuCores: 2,
-		CpuPower: instances.CpuPower(700),
-		Mem:      3840,
-		VirtType: &paravirtual,
+		Name:       "c3.large",
+		Arches:     amd64,
+		CpuCores:   2,
+		CpuPower:   instances.CpuPower(700),
+		Mem:        3840,
+		LocalDisks: 2,
+		VirtType:   &paravirtual,
 	}, {
-		Name:     "c3.xlarge",
-		Arches:   amd64,
-		CpuCores: 4,
-		CpuPower: instances.CpuPower(1400),
-		Mem:      7680,
-		VirtType: &paravirtual,
+		Name:       "c3.xlarge",
+		Arches:     amd64,
+		CpuCores:   4,
+		CpuPower:   instances.CpuPower(1400),
+		Mem:        7680,
+		LocalDisks: 2,
+		VirtType:   &paravirtual,
 	}, {
-		Name:     "c3.2xlarge",
-		Arches:   amd64,
-		CpuCores: 8,
-		CpuPower: instances.CpuPower(2800),
-		Mem:      15360,
-		VirtType: &paravirtual,
+		Name:       "c3.2xlarge",
+		Arches:     amd64,
+		CpuCores:   8,
+		CpuPower:   instances.CpuPower(2800),
+		Mem:        15360,
+		LocalDisks: 2,
+		VirtType:   &paravirtual,
 	}, {
-		Name:     "c3.4xlarge",
-		Arches:   amd64,
-		CpuCores: 16,
-		CpuPower: instances.CpuPower(5500),
-		Mem:      30720,
-		VirtType: &paravirtual,
+		Name:       "c3.4xlarge",
+		Arches:     amd64,
+		CpuCores:   16,
+		CpuPower:   instances.CpuPower(5500),
+		Mem:        30720,
+		LocalDisks: 2,
+		VirtType:   &paravirtual,
 	}, {
-		Name:     "c3.8xlarge",
-		Arches:   amd64,
-		CpuCores: 32,
-		CpuPower: instances.CpuPower(10800),
-		Mem:      61440,
-		VirtType: &paravirtual,
+		Name:       "c3.8xlarge",
+		Arches:     amd64,
+		CpuCores:   32,
+		CpuPower:   instances.CpuPower(10800),
+		Mem:        61440,
+		LocalDisks: 2,
+		VirtType:   &paravirtual,
 	},
 
 	{ // GPU instances, 1st generation.
-		Name:     "cg1.4xlarge",
-		Arches:   amd64,
-		CpuCores: 8,
-		CpuPower: instances.CpuPower(3350),
-		Mem:      22528,
-		VirtType: &hvm,
+		Name:            "cg1.4xlarge",
+		Arches:          amd64,
+		CpuCores:        8,
+		CpuPower:        instances.CpuPower(3350),
+		Mem:             22528,
+		LocalDisks:      2,
+		Accelerators:    2,
+		AcceleratorType: nvidiaTeslaM2050,
+		VirtType:        &hvm,
 	},
 
 	{ // GPU instances, 2nd generation.
-		Name:     "g2.2xlarge",
-		Arches:   amd64,
-		CpuCores: 8,
-		CpuPower: instances.CpuPower(2600),
-		Mem:      15360,
-		VirtType: &hvm,
+		Name:            "g2.2xlarge",
+		Arches:          amd64,
+		CpuCores:        8,
+		CpuPower:        instances.CpuPower(2600),
+		Mem:             15360,
+		LocalDisks:      1,
+		Accelerators:    1,
+		AcceleratorType: nvidiaGridK520,
+		VirtType:        &hvm,
 	},
 
 	{ // Memory-optimized, 1st generation.
-		Name:     "m2.xlarge",
-		Arches:   amd64,
-		CpuCores: 2,
-		CpuPower: instances.CpuPower(650),
-		Mem:      17408,
-		VirtType: &paravirtual,
+		Name:       "m2.xlarge",
+		Arches:     amd64,
+		CpuCores:   2,
+		CpuPower:   instances.CpuPower(650),
+		Mem:        17408,
+		LocalDisks: 1,
+		VirtType:   &paravirtual,
 	}, {
-		Name:     "m2.2xlarge",
-		Arches:   amd64,
-		CpuCores: 4,
-		CpuPower: instances.CpuPower(1300),
-		Mem:      34816,
-		VirtType: &paravirtual,
+		Name:       "m2.2xlarge",
+		Arches:     amd64,
+		CpuCores:   4,
+		CpuPower:   instances.CpuPower(1300),
+		Mem:        34816,
+		LocalDisks: 1,
+		VirtType:   &paravirtual,
 	}, {
-		Name:     "m2.4xlarge",
-		Arches:   amd64,
-		CpuCores: 8,
-		CpuPower: instances.CpuPower(2600),
-		Mem:      69632,
-		VirtType: &paravirtual,
+		Name:       "m2.4xlarge",
+		Arches:     amd64,
+		CpuCores:   8,
+		CpuPower:   instances.CpuPower(2600),
+		Mem:        69632,
+		LocalDisks: 2,
+		VirtType:   &paravirtual,
 	}, {
-		Name:     "cr1.8xlarge",
-		Arches:   amd64,
-		CpuCores: 16,
-		CpuPower: instances.CpuPower(8800),
-		Mem:      249856,
-		VirtType: &hvm,
+		Name:       "cr1.8xlarge",
+		Arches:     amd64,
+		CpuCores:   16,
+		CpuPower:   instances.CpuPower(8800),
+		Mem:        249856,
+		LocalDisks: 2,
+		VirtType:   &hvm,
 	},
 
 	{ // Memory-optimized, 2nd generation.
-		Name:     "r3.large",
-		Arches:   amd64,
-		CpuCores: 2,
-		CpuPower: instances.CpuPower(650),
-		Mem:      15616,
-		VirtType: &hvm,
+		Name:       "r3.large",
+		Arches:     amd64,
+		CpuCores:   2,
+		CpuPower:   instances.CpuPower(650),
+		Mem:        15616,
+		LocalDisks: 1,
+		VirtType:   &hvm,
 	}, {
-		Name:     "r3.xlarge",
-		Arches:   amd64,
-		CpuCores: 4,
-		CpuPower: instances.CpuPower(1300),
-		Mem:      31232,
-		VirtType: &hvm,
+		Name:       "r3.xlarge",
+		Arches:     amd64,
+		CpuCores:   4,
+		CpuPower:   instances.CpuPower(1300),
+		Mem:        31232,
+		LocalDisks: 1,
+		VirtType:   &hvm,
 	}, {
-		Name:     "r3.2xlarge",
-		Arches:   amd64,
-		CpuCores: 8,
-		CpuPower: instances.CpuPower(2600),
-		Mem:      62464,
-		VirtType: &hvm,
+		Name:       "r3.2xlarge",
+		Arches:     amd64,
+		CpuCores:   8,
+		CpuPower:   instances.CpuPower(2600),
+		Mem:        62464,
+		LocalDisks: 1,
+		VirtType:   &hvm,
 	}, {
-		Name:     "r3.4xlarge",
-		Arches:   amd64,
-		CpuCores: 16,
-		CpuPower: instances.CpuPower(5200),
-		Mem:      124928,
-		VirtType: &hvm,
+		Name:       "r3.4xlarge",
+		Arches:     amd64,
+		CpuCores:   16,
+		CpuPower:   instances.CpuPower(5200),
+		Mem:        124928,
+		LocalDisks: 1,
+		VirtType:   &hvm,
 	}, {
-		Name:     "r3.8xlarge",
-		Arches:   amd64,
-		CpuCores: 32,
-		CpuPower: instances.CpuPower(10400),
-		Mem:      249856,
-		VirtType: &hvm,
+		Name:       "r3.8xlarge",
+		Arches:     amd64,
+		CpuCores:   32,
+		CpuPower:   instances.CpuPower(10400),
+		Mem:        249856,
+		LocalDisks: 2,
+		VirtType:   &hvm,
 	},
 
 	{ // Storage-optimized, 1st generation.
-		Name:     "hi1.4xlarge",
-		Arches:   amd64,
-		CpuCores: 16,
-		CpuPower: instances.CpuPower(3500),
-		Mem:      61952,
-		VirtType: &paravirtual,
+		Name:       "hi1.4xlarge",
+		Arches:     amd64,
+		CpuCores:   16,
+		CpuPower:   instances.CpuPower(3500),
+		Mem:        61952,
+		LocalDisks: 2,
+		VirtType:   &paravirtual,
 	},
 
 	{ // Storage-optimized, 2nd generation.
-		Name:     "i2.xlarge",
-		Arches:   amd64,
-		CpuCores: 4,
-		CpuPower: instances.CpuPower(1400),
-		Mem:      31232,
-		VirtType: &hvm,
+		Name:       "i2.xlarge",
+		Arches:     amd64,
+		CpuCores:   4,
+		CpuPower:   instances.CpuPower(1400),
+		Mem:        31232,
+		LocalDisks: 1,
+		VirtType:   &hvm,
 	}, {
-		Name:     "i2.2xlarge",
-		Arches:   amd64,
-		CpuCores: 8,
-		CpuPower: instances.CpuPower(2700),
-		Mem:      62464,
-		VirtType: &hvm,
+		Name:       "i2.2xlarge",
+		Arches:     amd64,
+		CpuCores:   8,
+		CpuPower:   instances.CpuPower(2700),
+		Mem:        62464,
+		LocalDisks: 2,
+		VirtType:   &hvm,
 	}, {
-		Name:     "i2.4xlarge",
-		Arches:   amd64,
-		CpuCores: 16,
-		CpuPower: instances.CpuPower(5300),
-		Mem:      124928,
-		VirtType: &hvm,
+		Name:       "i2.4xlarge",
+		Arches:     amd64,
+		CpuCores:   16,
+		CpuPower:   instances.CpuPower(5300),
+		Mem:        124928,
+		LocalDisks: 4,
+		VirtType:   &hvm,
 	}, {
-		Name:     "i2.8xlarge",
-		Arches:   amd64,
-		CpuCores: 32,
-		CpuPower: instances.CpuPower(10400),
-		Mem:      249856,
-		VirtType: &hvm,
+		Name:       "i2.8xlarge",
+		Arches:     amd64,
+		CpuCores:   32,
+		CpuPower:   instances.CpuPower(10400),
+		Mem:        249856,
+		LocalDisks: 8,
+		VirtType:   &hvm,
 	}, {
-		Name:     "hs1.8xlarge",
-		Arches:   amd64,
-		CpuCores: 16,
-		CpuPower: instances.CpuPower(3500),
-		Mem:      119808,
-		VirtType: &paravirtual,
+		Name:       "hs1.8xlarge",
+		Arches:     amd64,
+		CpuCores:   16,
+		CpuPower:   instances.CpuPower(3500),
+		Mem:        119808,
+		LocalDisks: 24,
+		VirtType:   &paravirtual,
 	},
 
 	{ // Tiny-weeny.
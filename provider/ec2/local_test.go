@@ -540,6 +540,42 @@ func (t *localServerSuite) testStartInstanceAvailZoneAllConstrained(c *gc.C, run
 	c.Assert(azArgs, gc.DeepEquals, []string{"az1", "az2"})
 }
 
+func (t *localServerSuite) TestStartInstanceSubnetsToZones(c *gc.C) {
+	env := t.Prepare(c)
+	err := bootstrap.Bootstrap(envtesting.BootstrapContext(c), env, bootstrap.BootstrapParams{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	mock := mockAvailabilityZoneAllocations{
+		result: []common.AvailabilityZoneInstances{
+			{ZoneName: "az1"}, {ZoneName: "az2"}, {ZoneName: "az3"},
+		},
+	}
+	t.PatchValue(ec2.AvailabilityZoneAllocations, mock.AvailabilityZoneAllocations)
+
+	var azArgs, subnetArgs []string
+	t.PatchValue(ec2.RunInstances, func(e *amzec2.EC2, ri *amzec2.RunInstances) (*amzec2.RunInstancesResp, error) {
+		azArgs = append(azArgs, ri.AvailZone)
+		subnetArgs = append(subnetArgs, ri.SubnetId)
+		return nil, azConstrainedErr
+	})
+	params := environs.StartInstanceParams{
+		SubnetsToZones: map[string][]string{
+			"subnet-3": {"az3"},
+			"subnet-2": {"az2"},
+			"subnet-4": {"az4"},
+		},
+	}
+	_, err = testing.StartInstanceWithParams(env, "1", params, nil)
+	c.Assert(err, gc.ErrorMatches, "cannot run instances: .*")
+	c.Assert(azArgs, gc.DeepEquals, []string{"az2", "az3"})
+	c.Assert(subnetArgs, gc.DeepEquals, []string{"subnet-2", "subnet-3"})
+
+	// No zone holds a required subnet.
+	params.SubnetsToZones = map[string][]string{"subnet-4": {"az4"}}
+	_, err = testing.StartInstanceWithParams(env, "1", params, nil)
+	c.Assert(err, gc.ErrorMatches, `no required subnet in availability zones \[az1 az2 az3\]`)
+}
+
 func (t *localServerSuite) TestStartInstanceAvailZoneOneConstrained(c *gc.C) {
 	t.testStartInstanceAvailZoneOneConstrained(c, azConstrainedErr)
 }
@@ -628,6 +664,15 @@ func (t *localServerSuite) TestConstraintsValidatorVocab(c *gc.C) {
 	cons = constraints.MustParse("instance-type=foo")
 	_, err = validator.Validate(cons)
 	c.Assert(err, gc.ErrorMatches, "invalid constraint value: instance-type=foo\nvalid values are:.*")
+	cons = constraints.MustParse("virt-type=kvm")
+	_, err = validator.Validate(cons)
+	c.Assert(err, gc.ErrorMatches, "invalid constraint value: virt-type=kvm\nvalid values are:.*")
+	cons = constraints.MustParse("accelerator-type=foo")
+	_, err = validator.Validate(cons)
+	c.Assert(err, gc.ErrorMatches, "invalid constraint value: accelerator-type=foo\nvalid values are:.*")
+	cons = constraints.MustParse("virt-type=hvm accelerators=2 accelerator-type=tesla-m2050")
+	_, err = validator.Validate(cons)
+	c.Assert(err, jc.ErrorIsNil)
 }
 
 func (t *localServerSuite) TestConstraintsMerge(c *gc.C) {
@@ -111,6 +111,10 @@ func (env *environ) parseAvailabilityZones(args environs.StartInstanceParams) ([
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	zoneInstances, err = common.ExcludeAvailabilityZones(zoneInstances, args.ExcludeZones)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 	logger.Infof("found %d zones: %v", len(zoneInstances), zoneInstances)
 
 	var zoneNames []string
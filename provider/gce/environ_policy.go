@@ -67,6 +67,13 @@ func (env *environ) lookupArchitectures() ([]string, error) {
 var unsupportedConstraints = []string{
 	constraints.Tags,
 	constraints.Networks,
+	constraints.Spaces,
+	// Local SSDs and GPUs are attached to GCE instances separately
+	// from their machine type, so they cannot be matched against the
+	// instance types.
+	constraints.LocalDisks,
+	constraints.Accelerators,
+	constraints.AcceleratorType,
 }
 
 // instanceTypeConstraints defines the fields defined on each of the
@@ -77,6 +84,7 @@ var instanceTypeConstraints = []string{
 	constraints.CpuPower,
 	constraints.Mem,
 	constraints.Container, // VirtType
+	constraints.VirtType,
 }
 
 // ConstraintsValidator returns a Validator value which is used to
@@ -111,6 +119,7 @@ func (env *environ) ConstraintsValidator() (constraints.Validator, error) {
 	validator.RegisterVocabulary(constraints.InstanceType, instTypeNames)
 
 	validator.RegisterVocabulary(constraints.Container, []string{vtype})
+	validator.RegisterVocabulary(constraints.VirtType, []string{vtype})
 
 	return validator, nil
 }
@@ -163,11 +163,11 @@ func (s *environPolSuite) TestConstraintsValidatorUnsupported(c *gc.C) {
 	validator, err := s.Env.ConstraintsValidator()
 	c.Assert(err, jc.ErrorIsNil)
 
-	cons := constraints.MustParse("arch=amd64 tags=foo")
+	cons := constraints.MustParse("arch=amd64 tags=foo local-disks=2 accelerators=1")
 	unsupported, err := validator.Validate(cons)
 	c.Assert(err, jc.ErrorIsNil)
 
-	c.Check(unsupported, jc.DeepEquals, []string{"tags"})
+	c.Check(unsupported, jc.SameContents, []string{"tags", "local-disks", "accelerators"})
 }
 
 func (s *environPolSuite) TestConstraintsValidatorVocabArch(c *gc.C) {
@@ -202,6 +202,16 @@ func (s *environPolSuite) TestConstraintsValidatorVocabContainer(c *gc.C) {
 	c.Check(err, gc.ErrorMatches, "invalid constraint value: container=lxc\nvalid values are:.*")
 }
 
+func (s *environPolSuite) TestConstraintsValidatorVocabVirtType(c *gc.C) {
+	validator, err := s.Env.ConstraintsValidator()
+	c.Assert(err, jc.ErrorIsNil)
+
+	cons := constraints.MustParse("virt-type=hvm")
+	_, err = validator.Validate(cons)
+
+	c.Check(err, gc.ErrorMatches, "invalid constraint value: virt-type=hvm\nvalid values are:.*")
+}
+
 func (s *environPolSuite) TestConstraintsValidatorConflicts(c *gc.C) {
 	s.FakeCommon.Arches = []string{arch.AMD64}
 
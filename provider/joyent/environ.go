@@ -75,6 +75,9 @@ func (*joyentEnviron) Provider() environs.EnvironProvider {
 
 // PrecheckInstance is defined on the state.Prechecker interface.
 func (env *joyentEnviron) PrecheckInstance(series string, cons constraints.Value, placement string) error {
+	if zone, ok := common.ZonePlacement(placement); ok {
+		return errors.NotSupportedf("placement in availability zone %q", zone)
+	}
 	if placement != "" {
 		return fmt.Errorf("unknown placement directive: %s", placement)
 	}
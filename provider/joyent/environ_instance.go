@@ -62,6 +62,11 @@ func (env *joyentEnviron) machineFullName(machineId string) string {
 var unsupportedConstraints = []string{
 	constraints.CpuPower,
 	constraints.Tags,
+	constraints.Spaces,
+	constraints.VirtType,
+	constraints.LocalDisks,
+	constraints.Accelerators,
+	constraints.AcceleratorType,
 }
 
 // ConstraintsValidator is defined on the Environs interface.
@@ -71,6 +71,9 @@ func (*localEnviron) SupportedArchitectures() ([]string, error) {
 }
 
 func (*localEnviron) PrecheckInstance(series string, cons constraints.Value, placement string) error {
+	if zone, ok := common.ZonePlacement(placement); ok {
+		return errors.NotSupportedf("placement in availability zone %q", zone)
+	}
 	if placement != "" {
 		return fmt.Errorf("unknown placement directive: %s", placement)
 	}
@@ -344,6 +347,11 @@ var unsupportedConstraints = []string{
 	constraints.CpuPower,
 	constraints.InstanceType,
 	constraints.Tags,
+	constraints.Spaces,
+	constraints.VirtType,
+	constraints.LocalDisks,
+	constraints.Accelerators,
+	constraints.AcceleratorType,
 }
 
 // ConstraintsValidator is defined on the Environs interface.
@@ -764,6 +764,11 @@ func (environ *maasEnviron) startNode(node gomaasapi.MAASObject, series string,
 var unsupportedConstraints = []string{
 	constraints.CpuPower,
 	constraints.InstanceType,
+	constraints.Spaces,
+	constraints.VirtType,
+	constraints.LocalDisks,
+	constraints.Accelerators,
+	constraints.AcceleratorType,
 }
 
 // ConstraintsValidator is defined on the Environs interface.
@@ -884,6 +889,9 @@ func (environ *maasEnviron) StartInstance(args environs.StartInstanceParams) (
 			}
 		}
 		zoneInstances, err := availabilityZoneAllocations(environ, group)
+		if err == nil {
+			zoneInstances, err = common.ExcludeAvailabilityZones(zoneInstances, args.ExcludeZones)
+		}
 		if errors.IsNotImplemented(err) {
 			// Availability zones are an extension, so we may get a
 			// not implemented error; ignore these.
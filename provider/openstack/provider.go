@@ -576,6 +576,11 @@ func (e *environ) SupportedArchitectures() ([]string, error) {
 var unsupportedConstraints = []string{
 	constraints.Tags,
 	constraints.CpuPower,
+	constraints.Spaces,
+	constraints.VirtType,
+	constraints.LocalDisks,
+	constraints.Accelerators,
+	constraints.AcceleratorType,
 }
 
 // ConstraintsValidator is defined on the Environs interface.
@@ -986,6 +991,9 @@ func (e *environ) StartInstance(args environs.StartInstanceParams) (*environs.St
 			}
 		}
 		zoneInstances, err := availabilityZoneAllocations(e, group)
+		if err == nil {
+			zoneInstances, err = common.ExcludeAvailabilityZones(zoneInstances, args.ExcludeZones)
+		}
 		if errors.IsNotImplemented(err) {
 			// Availability zones are an extension, so we may get a
 			// not implemented error; ignore these.
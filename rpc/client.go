@@ -6,6 +6,7 @@ package rpc
 import (
 	"errors"
 	"strings"
+	"time"
 )
 
 var ErrShutdown = errors.New("connection is shut down")
@@ -23,6 +24,13 @@ type Call struct {
 type RequestError struct {
 	Message string
 	Code    string
+
+	// RetryAfter holds, for requests refused with CodeRateLimited,
+	// how long the server asked the caller to wait before retrying.
+	RetryAfter time.Duration
+
+	// TraceId holds the trace id of the failed request, if any.
+	TraceId string
 }
 
 func (e *RequestError) Error() string {
@@ -55,12 +63,14 @@ func (conn *Conn) send(call *Call) {
 	conn.reqId++
 	reqId := conn.reqId
 	conn.clientPending[reqId] = call
+	traceId := conn.traceId
 	conn.mutex.Unlock()
 
 	// Encode and send the request.
 	hdr := &Header{
 		RequestId: reqId,
 		Request:   call.Request,
+		TraceId:   traceId,
 	}
 	params := call.Params
 	if params == nil {
@@ -109,8 +119,10 @@ func (conn *Conn) handleResponse(hdr *Header) error {
 		// any subsequent requests will get the ReadResponseBody
 		// error if there is one.
 		call.Error = &RequestError{
-			Message: hdr.Error,
-			Code:    hdr.ErrorCode,
+			Message:    hdr.Error,
+			Code:       hdr.ErrorCode,
+			RetryAfter: hdr.RetryAfter,
+			TraceId:    hdr.TraceId,
 		}
 		err = conn.readBody(nil, false)
 		if conn.notifier != nil {
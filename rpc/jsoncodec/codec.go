@@ -7,6 +7,7 @@ import (
 	"io"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/juju/loggo"
 
@@ -62,28 +63,33 @@ func (c *Codec) isLogging() bool {
 // parameters or response yet, so we delay parsing by storing them
 // in a RawMessage.
 type inMsg struct {
-	RequestId uint64
-	Type      string
-	Version   int
-	Id        string
-	Request   string
-	Params    json.RawMessage
-	Error     string
-	ErrorCode string
-	Response  json.RawMessage
+	RequestId  uint64
+	Type       string
+	Version    int
+	Id         string
+	Request    string
+	Params     json.RawMessage
+	Error      string
+	ErrorCode  string
+	RetryAfter float64
+	TraceId    string
+	Response   json.RawMessage
 }
 
-// outMsg holds an outgoing message.
+// outMsg holds an outgoing message. RetryAfter is given in seconds, as
+// clients written in other languages would expect.
 type outMsg struct {
-	RequestId uint64
-	Type      string      `json:",omitempty"`
-	Version   int         `json:",omitempty"`
-	Id        string      `json:",omitempty"`
-	Request   string      `json:",omitempty"`
-	Params    interface{} `json:",omitempty"`
-	Error     string      `json:",omitempty"`
-	ErrorCode string      `json:",omitempty"`
-	Response  interface{} `json:",omitempty"`
+	RequestId  uint64
+	Type       string      `json:",omitempty"`
+	Version    int         `json:",omitempty"`
+	Id         string      `json:",omitempty"`
+	Request    string      `json:",omitempty"`
+	Params     interface{} `json:",omitempty"`
+	Error      string      `json:",omitempty"`
+	ErrorCode  string      `json:",omitempty"`
+	RetryAfter float64     `json:",omitempty"`
+	TraceId    string      `json:",omitempty"`
+	Response   interface{} `json:",omitempty"`
 }
 
 func (c *Codec) Close() error {
@@ -131,6 +137,8 @@ func (c *Codec) ReadHeader(hdr *rpc.Header) error {
 	}
 	hdr.Error = c.msg.Error
 	hdr.ErrorCode = c.msg.ErrorCode
+	hdr.RetryAfter = time.Duration(c.msg.RetryAfter * float64(time.Second))
+	hdr.TraceId = c.msg.TraceId
 	return nil
 }
 
@@ -191,6 +199,8 @@ func (m *outMsg) init(hdr *rpc.Header, body interface{}) {
 	m.Request = hdr.Request.Action
 	m.Error = hdr.Error
 	m.ErrorCode = hdr.ErrorCode
+	m.RetryAfter = hdr.RetryAfter.Seconds()
+	m.TraceId = hdr.TraceId
 	if hdr.IsRequest() {
 		m.Params = body
 	} else {
@@ -7,6 +7,7 @@ import (
 	"reflect"
 	"regexp"
 	stdtesting "testing"
+	"time"
 
 	"github.com/juju/loggo"
 	jc "github.com/juju/testing/checkers"
@@ -46,6 +47,18 @@ var readTests = []struct {
 		},
 	},
 	expectBody: &value{X: "param"},
+}, {
+	msg: `{"RequestId": 1, "Type": "foo", "Id": "id", "Request": "frob", "TraceId": "a-trace", "Params": {"X": "param"}}`,
+	expectHdr: rpc.Header{
+		RequestId: 1,
+		Request: rpc.Request{
+			Type:   "foo",
+			Id:     "id",
+			Action: "frob",
+		},
+		TraceId: "a-trace",
+	},
+	expectBody: &value{X: "param"},
 }, {
 	msg: `{"RequestId": 2, "Error": "an error", "ErrorCode": "a code"}`,
 	expectHdr: rpc.Header{
@@ -54,6 +67,15 @@ var readTests = []struct {
 		ErrorCode: "a code",
 	},
 	expectBody: new(map[string]interface{}),
+}, {
+	msg: `{"RequestId": 2, "Error": "slow down", "ErrorCode": "rate limited", "RetryAfter": 2}`,
+	expectHdr: rpc.Header{
+		RequestId:  2,
+		Error:      "slow down",
+		ErrorCode:  "rate limited",
+		RetryAfter: 2 * time.Second,
+	},
+	expectBody: new(map[string]interface{}),
 }, {
 	msg: `{"RequestId": 3, "Response": {"X": "result"}}`,
 	expectHdr: rpc.Header{
@@ -237,6 +259,21 @@ var writeTests = []struct {
 		ErrorCode: "a code",
 	},
 	expect: `{"RequestId": 2, "Error": "an error", "ErrorCode": "a code"}`,
+}, {
+	hdr: &rpc.Header{
+		RequestId: 2,
+		Error:     "an error",
+		TraceId:   "a-trace",
+	},
+	expect: `{"RequestId": 2, "Error": "an error", "TraceId": "a-trace"}`,
+}, {
+	hdr: &rpc.Header{
+		RequestId:  2,
+		Error:      "slow down",
+		ErrorCode:  "rate limited",
+		RetryAfter: 2 * time.Second,
+	},
+	expect: `{"RequestId": 2, "Error": "slow down", "ErrorCode": "rate limited", "RetryAfter": 2}`,
 }, {
 	hdr: &rpc.Header{
 		RequestId: 3,
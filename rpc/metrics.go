@@ -0,0 +1,100 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package rpc
+
+import (
+	"sync"
+	"time"
+)
+
+// DurationBuckets holds the upper bounds of the request durations
+// counted by RequestStats.Buckets.
+var DurationBuckets = []time.Duration{
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+// RequestKey identifies the method of a request, whatever its version
+// and object id.
+type RequestKey struct {
+	Type   string
+	Action string
+}
+
+// RequestStats holds statistics about the requests to a method.
+type RequestStats struct {
+	// Count is the number of requests served.
+	Count int64
+
+	// Errors is the number of requests that returned an error.
+	Errors int64
+
+	// Duration is the total time spent serving the requests.
+	Duration time.Duration
+
+	// Buckets holds, for each of DurationBuckets, the number of
+	// requests served within that time.
+	Buckets []int64
+}
+
+// RequestMetrics accumulates statistics about the server requests
+// served by any number of Conns. It is safe to use concurrently.
+type RequestMetrics struct {
+	mu    sync.Mutex
+	stats map[RequestKey]*RequestStats
+}
+
+// NewRequestMetrics returns a new RequestMetrics with no requests
+// recorded.
+func NewRequestMetrics() *RequestMetrics {
+	return &RequestMetrics{
+		stats: make(map[RequestKey]*RequestStats),
+	}
+}
+
+// record records a request that took the given time to serve.
+func (m *RequestMetrics) record(req Request, failed bool, duration time.Duration) {
+	key := RequestKey{req.Type, req.Action}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stats, ok := m.stats[key]
+	if !ok {
+		stats = &RequestStats{
+			Buckets: make([]int64, len(DurationBuckets)),
+		}
+		m.stats[key] = stats
+	}
+	stats.Count++
+	if failed {
+		stats.Errors++
+	}
+	stats.Duration += duration
+	for i, bound := range DurationBuckets {
+		if duration <= bound {
+			stats.Buckets[i]++
+		}
+	}
+}
+
+// Snapshot returns the statistics recorded so far, by method.
+func (m *RequestMetrics) Snapshot() map[RequestKey]RequestStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	result := make(map[RequestKey]RequestStats, len(m.stats))
+	for key, stats := range m.stats {
+		snapshot := *stats
+		snapshot.Buckets = append([]int64(nil), stats.Buckets...)
+		result[key] = snapshot
+	}
+	return result
+}
@@ -822,6 +822,106 @@ func (*rpcSuite) TestTransformErrors(c *gc.C) {
 
 }
 
+type fakeRateLimiter struct {
+	mu      sync.Mutex
+	allowed int
+	seen    []rpc.Request
+}
+
+func (l *fakeRateLimiter) Allow(req rpc.Request) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.seen = append(l.seen, req)
+	if len(l.seen) > l.allowed {
+		return 3 * time.Second, false
+	}
+	return 0, true
+}
+
+func (*rpcSuite) TestRateLimiter(c *gc.C) {
+	root := &Root{
+		errorInst: &ErrorMethods{nil},
+	}
+	tfErr := func(err error) error {
+		return fmt.Errorf("transformed: %v", err)
+	}
+	client, srvDone, _, _ := newRPCClientServer(c, root, tfErr, false)
+	defer closeClient(c, client, srvDone)
+	req := rpc.Request{"ErrorMethods", 0, "", "Call"}
+	err := client.Call(req, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	limiter := &fakeRateLimiter{allowed: 1}
+	root.conn.SetRateLimiter(limiter)
+	err = client.Call(req, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	// Refused requests are not transformed, and tell the
+	// client how long to wait.
+	err = client.Call(req, nil, nil)
+	c.Assert(err, gc.DeepEquals, &rpc.RequestError{
+		Message:    "rate limit exceeded for ErrorMethods.Call, retry after 3s",
+		Code:       rpc.CodeRateLimited,
+		RetryAfter: 3 * time.Second,
+	})
+	c.Assert(limiter.seen, gc.DeepEquals, []rpc.Request{req, req})
+
+	root.conn.SetRateLimiter(nil)
+	err = client.Call(req, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (*rpcSuite) TestMetrics(c *gc.C) {
+	root := &Root{
+		errorInst: &ErrorMethods{nil},
+	}
+	client, srvDone, _, _ := newRPCClientServer(c, root, nil, false)
+	defer closeClient(c, client, srvDone)
+	req := rpc.Request{"ErrorMethods", 0, "", "Call"}
+	err := client.Call(req, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	metrics := rpc.NewRequestMetrics()
+	root.conn.SetMetrics(metrics)
+	err = client.Call(req, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	root.errorInst.err = fmt.Errorf("failed")
+	err = client.Call(req, nil, nil)
+	c.Assert(err, gc.ErrorMatches, "request error: failed")
+
+	snapshot := metrics.Snapshot()
+	c.Assert(snapshot, gc.HasLen, 1)
+	stats := snapshot[rpc.RequestKey{"ErrorMethods", "Call"}]
+	c.Check(stats.Count, gc.Equals, int64(2))
+	c.Check(stats.Errors, gc.Equals, int64(1))
+	c.Assert(stats.Buckets, gc.HasLen, len(rpc.DurationBuckets))
+	// The buckets are cumulative, and the last one holds
+	// any request taking up to ten seconds.
+	c.Check(stats.Buckets[len(stats.Buckets)-1], gc.Equals, int64(2))
+}
+
+func (*rpcSuite) TestTraceId(c *gc.C) {
+	root := &Root{
+		errorInst: &ErrorMethods{&codedError{"message", "code"}},
+	}
+	client, srvDone, _, serverNotifier := newRPCClientServer(c, root, nil, false)
+	defer closeClient(c, client, srvDone)
+	client.SetTraceId("a-trace")
+	req := rpc.Request{"ErrorMethods", 0, "", "Call"}
+	err := client.Call(req, nil, nil)
+	// The trace id is echoed in the error.
+	c.Assert(err, gc.DeepEquals, &rpc.RequestError{
+		Message: "message",
+		Code:    "code",
+		TraceId: "a-trace",
+	})
+	serverNotifier.mu.Lock()
+	defer serverNotifier.mu.Unlock()
+	c.Assert(serverNotifier.serverRequests, gc.HasLen, 1)
+	c.Assert(serverNotifier.serverRequests[0].hdr.TraceId, gc.Equals, "a-trace")
+	c.Assert(serverNotifier.serverReplies, gc.HasLen, 1)
+	c.Assert(serverNotifier.serverReplies[0].hdr.TraceId, gc.Equals, "a-trace")
+}
+
 func (*rpcSuite) TestServerWaitsForOutstandingCalls(c *gc.C) {
 	ready := make(chan struct{})
 	start := make(chan string)
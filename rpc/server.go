@@ -15,7 +15,10 @@ import (
 	"github.com/juju/juju/rpc/rpcreflect"
 )
 
-const CodeNotImplemented = "not implemented"
+const (
+	CodeNotImplemented = "not implemented"
+	CodeRateLimited    = "rate limited"
+)
 
 var logger = loggo.GetLogger("juju.rpc")
 
@@ -62,6 +65,15 @@ type Header struct {
 
 	// ErrorCode holds the code of the error, if any.
 	ErrorCode string
+
+	// RetryAfter holds, for requests refused with CodeRateLimited,
+	// how long the caller should wait before retrying.
+	RetryAfter time.Duration
+
+	// TraceId holds an identifier chosen by the client to correlate
+	// its requests with the log messages they cause. Replies echo the
+	// trace id of their request.
+	TraceId string
 }
 
 // Request represents an RPC to be performed, absent its parameters.
@@ -124,6 +136,16 @@ type Conn struct {
 	// transformErrors is used to transform returned errors.
 	transformErrors func(error) error
 
+	// rateLimiter, if not nil, is asked whether each server request
+	// may be served.
+	rateLimiter RateLimiter
+
+	// traceId holds the trace id sent with client requests.
+	traceId string
+
+	// metrics, if not nil, records the server requests served.
+	metrics *RequestMetrics
+
 	// reqId holds the latest client request id.
 	reqId uint64
 
@@ -283,6 +305,33 @@ func (conn *Conn) serve(methodFinder MethodFinder, root interface{}, transformEr
 	conn.transformErrors = transformErrors
 }
 
+// SetRateLimiter sets the RateLimiter that decides whether requests
+// received on the connection are served. Requests it refuses are
+// answered with a RateLimitedError. If limiter is nil, all requests
+// are served.
+func (conn *Conn) SetRateLimiter(limiter RateLimiter) {
+	conn.mutex.Lock()
+	defer conn.mutex.Unlock()
+	conn.rateLimiter = limiter
+}
+
+// SetMetrics sets the RequestMetrics that records the server requests
+// served by the connection. If metrics is nil, requests are not
+// recorded.
+func (conn *Conn) SetMetrics(metrics *RequestMetrics) {
+	conn.mutex.Lock()
+	defer conn.mutex.Unlock()
+	conn.metrics = metrics
+}
+
+// SetTraceId sets the trace id sent with the requests made on the
+// connection from now on.
+func (conn *Conn) SetTraceId(traceId string) {
+	conn.mutex.Lock()
+	defer conn.mutex.Unlock()
+	conn.traceId = traceId
+}
+
 // noopTransform is used when transformErrors is not supplied to Serve.
 func noopTransform(err error) error {
 	return err
@@ -363,6 +412,31 @@ type MethodFinder interface {
 	FindMethod(rootName string, version int, methodName string) (rpcreflect.MethodCaller, error)
 }
 
+// RateLimiter represents a type that can limit the rate at which
+// requests are served. The Allow method may be called concurrently.
+type RateLimiter interface {
+	// Allow reports whether the given request may be served now.
+	// If not, it also returns how long the caller should wait
+	// before retrying.
+	Allow(req Request) (retryAfter time.Duration, ok bool)
+}
+
+// RateLimitedError is the error returned for requests refused by the
+// RateLimiter of a Conn.
+type RateLimitedError struct {
+	Request    Request
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitedError) Error() string {
+	return fmt.Sprintf("rate limit exceeded for %s.%s, retry after %v",
+		e.Request.Type, e.Request.Action, e.RetryAfter)
+}
+
+func (e *RateLimitedError) ErrorCode() string {
+	return CodeRateLimited
+}
+
 // Killer represents a type that can be asked to abort any outstanding
 // requests.  The Kill method should return immediately.
 type Killer interface {
@@ -475,6 +549,19 @@ func (conn *Conn) handleRequest(hdr *Header) error {
 		}
 	}
 	conn.mutex.Lock()
+	rateLimiter := conn.rateLimiter
+	conn.mutex.Unlock()
+	if rateLimiter != nil {
+		if retryAfter, ok := rateLimiter.Allow(hdr.Request); !ok {
+			// As with unimplemented methods, the error is not
+			// transformed, so that it keeps its code.
+			return conn.writeErrorResponse(hdr, &RateLimitedError{
+				Request:    hdr.Request,
+				RetryAfter: retryAfter,
+			}, startTime)
+		}
+	}
+	conn.mutex.Lock()
 	closing := conn.closing
 	if !closing {
 		conn.srvPending.Add(1)
@@ -493,12 +580,16 @@ func (conn *Conn) writeErrorResponse(reqHdr *Header, err error, startTime time.T
 	defer conn.sending.Unlock()
 	hdr := &Header{
 		RequestId: reqHdr.RequestId,
+		TraceId:   reqHdr.TraceId,
 	}
 	if err, ok := err.(ErrorCoder); ok {
 		hdr.ErrorCode = err.ErrorCode()
 	} else {
 		hdr.ErrorCode = ""
 	}
+	if err, ok := err.(*RateLimitedError); ok {
+		hdr.RetryAfter = err.RetryAfter
+	}
 	hdr.Error = err.Error()
 	if conn.notifier != nil {
 		conn.notifier.ServerReply(reqHdr.Request, hdr, struct{}{}, time.Since(startTime))
@@ -550,11 +641,18 @@ func (conn *Conn) bindRequest(hdr *Header) (boundRequest, error) {
 func (conn *Conn) runRequest(req boundRequest, arg reflect.Value, startTime time.Time) {
 	defer conn.srvPending.Done()
 	rv, err := req.Call(req.hdr.Request.Id, arg)
+	conn.mutex.Lock()
+	metrics := conn.metrics
+	conn.mutex.Unlock()
+	if metrics != nil {
+		metrics.record(req.hdr.Request, err != nil, time.Since(startTime))
+	}
 	if err != nil {
 		err = conn.writeErrorResponse(&req.hdr, req.transformErrors(err), startTime)
 	} else {
 		hdr := &Header{
 			RequestId: req.hdr.RequestId,
+			TraceId:   req.hdr.TraceId,
 		}
 		var rvi interface{}
 		if rv.IsValid() {
@@ -90,6 +90,10 @@ type MachineTemplate struct {
 	// principals holds the principal units that will
 	// associated with the machine.
 	principals []string
+
+	// pool holds the name of the spare machine pool
+	// the machine is added to.
+	pool string
 }
 
 // MachineVolumeParams holds the parameters for creating a volume and
@@ -450,6 +454,7 @@ func (st *State) machineDocForTemplate(template MachineTemplate, id string) *mac
 		Addresses:  fromNetworkAddresses(template.Addresses),
 		NoVote:     template.NoVote,
 		Placement:  template.Placement,
+		Pool:       template.pool,
 	}
 }
 
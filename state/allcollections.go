@@ -107,6 +107,30 @@ func allCollections() collectionSchema {
 		// different environments at a time.
 		userenvnameC: {global: true},
 
+		// This collection holds the API tokens issued to users; like the
+		// users themselves, they are not specific to any one environment.
+		userTokensC: {
+			global: true,
+			indexes: []mgo.Index{{
+				Key: []string{"user"},
+			}},
+		},
+
+		// This collection holds the SSH public keys of users; the keys
+		// of the users with access to an environment are authorised on
+		// its machines.
+		userSSHKeysC: {
+			global: true,
+			indexes: []mgo.Index{{
+				Key: []string{"user"},
+			}},
+		},
+
+		// This collection holds a single document whose revision is
+		// bumped whenever a user is disabled or enabled, so that the
+		// SSH keys watcher need not wake for every change to users.
+		usersRevisionC: {global: true},
+
 		// This collection holds workload metrics reported by certain charms
 		// for passing onward to other tools.
 		metricsC: {global: true},
@@ -138,6 +162,20 @@ func allCollections() collectionSchema {
 		// changes from being accepted.
 		blocksC: {},
 
+		// This collection holds the permissions granted to users and
+		// groups, restricting them to certain operations on the
+		// environment or on its services.
+		permissionsC: {
+			indexes: []mgo.Index{{
+				Key: []string{"env-uuid", "subject"},
+			}},
+		},
+
+		// This collection records the users and groups that have been
+		// granted permissions in an environment. They stay restricted
+		// to the permissions granted, even once all are revoked.
+		restrictedSubjectsC: {},
+
 		// This collection is used for internal bookkeeping; certain complex
 		// or tedious state changes are deferred by recording a cleanup doc
 		// for later handling.
@@ -197,6 +235,14 @@ func allCollections() collectionSchema {
 		machinesC:      {},
 		rebootC:        {},
 
+		// This collection holds the spare machine pools of the
+		// environment; the pools' constraints are in constraintsC.
+		machinePoolsC: {},
+
+		// This collection holds the rolling agent upgrade in progress
+		// in the environment, if any.
+		rollingUpgradesC: {},
+
 		// -----
 
 		// These collections hold information associated with storage.
@@ -263,6 +309,7 @@ func allCollections() collectionSchema {
 		},
 		openedPortsC:       {},
 		requestedNetworksC: {},
+		spacesC:            {},
 		subnetsC: {
 			indexes: []mgo.Index{{
 				// TODO(dimitern): make unique per-environment, not globally.
@@ -306,12 +353,31 @@ func allCollections() collectionSchema {
 			}},
 		},
 
+		// This collection holds the deferred events scheduled by each
+		// unit's charm, in a single document per unit.
+		scheduledEventsC: {},
+
+		// This collection holds the debug-hooks session opened on each
+		// unit through the API, in a single document per unit.
+		debugHooksSessionsC: {},
+
 		// ----------------------
 
 		// Raw-access collections
 		// ======================
 
 		// metrics; status-history; logs; ..?
+
+		// This collection holds a rolling window of the hooks, actions
+		// and commands most recently executed by each unit, with their
+		// timings and outcomes. It is written without transactions, and
+		// pruned in bulk.
+		hookExecutionsC: {
+			indexes: []mgo.Index{{
+				Key: []string{"env-uuid", "unit", "seq"},
+			}},
+			rawAccess: true,
+		},
 	}
 }
 
@@ -330,14 +396,17 @@ const (
 	cleanupsC              = "cleanups"
 	constraintsC           = "constraints"
 	containerRefsC         = "containerRefs"
+	debugHooksSessionsC    = "debughookssessions"
 	envUsersC              = "envusers"
 	environmentsC          = "environments"
 	filesystemAttachmentsC = "filesystemAttachments"
 	filesystemsC           = "filesystems"
+	hookExecutionsC        = "hookexecutions"
 	instanceDataC          = "instanceData"
 	ipaddressesC           = "ipaddresses"
 	leaseC                 = "lease"
 	leasesC                = "leases"
+	machinePoolsC          = "machinepools"
 	machinesC              = "machines"
 	meterStatusC           = "meterStatus"
 	metricsC               = "metrics"
@@ -346,15 +415,20 @@ const (
 	networkInterfacesC     = "networkinterfaces"
 	networksC              = "networks"
 	openedPortsC           = "openedPorts"
+	permissionsC           = "permissions"
+	restrictedSubjectsC    = "restrictedsubjects"
 	rebootC                = "reboot"
 	relationScopesC        = "relationscopes"
 	relationsC             = "relations"
 	requestedNetworksC     = "requestednetworks"
+	rollingUpgradesC       = "rollingupgrades"
 	restoreInfoC           = "restoreInfo"
+	scheduledEventsC       = "scheduledevents"
 	sequenceC              = "sequence"
 	servicesC              = "services"
 	settingsC              = "settings"
 	settingsrefsC          = "settingsrefs"
+	spacesC                = "spaces"
 	stateServersC          = "stateServers"
 	statusesC              = "statuses"
 	statusesHistoryC       = "statuseshistory"
@@ -369,6 +443,9 @@ const (
 	upgradeInfoC           = "upgradeInfo"
 	userenvnameC           = "userenvname"
 	usersC                 = "users"
+	usersRevisionC         = "usersrevision"
+	userSSHKeysC           = "usersshkeys"
+	userTokensC            = "usertokens"
 	volumeAttachmentsC     = "volumeattachments"
 	volumesC               = "volumes"
 )
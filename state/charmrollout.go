@@ -0,0 +1,212 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/charm.v5"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// CharmRollout describes a charm upgrade that is applied to the units
+// of a service in batches. Units that have not been released to the
+// rollout keep running the charm the service was upgraded from.
+type CharmRollout struct {
+	// From holds the URL of the charm the service was upgraded from.
+	From *charm.URL
+
+	// To holds the URL of the charm the service is upgraded to.
+	To *charm.URL
+
+	// BatchSize holds the number of units released to the rollout
+	// at a time, once the previously released units are active.
+	BatchSize int
+
+	// Units holds the names of the units released to the rollout,
+	// starting with the canary units.
+	Units []string
+
+	// Paused is true when the rollout has been paused because a
+	// released unit failed; Reason then holds why.
+	Paused bool
+	Reason string
+}
+
+// charmRolloutDoc represents a CharmRollout in MongoDB.
+type charmRolloutDoc struct {
+	From      *charm.URL `bson:"from"`
+	To        *charm.URL `bson:"to"`
+	BatchSize int        `bson:"batchsize"`
+	Units     []string   `bson:"units"`
+	Paused    bool       `bson:"paused"`
+	Reason    string     `bson:"reason,omitempty"`
+}
+
+// charmRolloutOp returns the operation that replaces any charm rollout
+// of the service with the supplied one, or removes it if nil.
+func charmRolloutOp(serviceDocID string, rollout *charmRolloutDoc) txn.Op {
+	update := bson.D{{"$unset", bson.D{{"charmrollout", nil}}}}
+	if rollout != nil {
+		update = bson.D{{"$set", bson.D{{"charmrollout", rollout}}}}
+	}
+	return txn.Op{
+		C:      servicesC,
+		Id:     serviceDocID,
+		Update: update,
+	}
+}
+
+// SetCharmInBatches changes the charm for the service like SetCharm,
+// but only upgrades the given number of canary units at first. The
+// remaining units are released to the upgrade by the charm rollout
+// worker batchSize at a time, once all previously released units are
+// active.
+func (s *Service) SetCharmInBatches(ch *Charm, force bool, canary, batchSize int) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot upgrade service %q in batches", s.doc.Name)
+	if canary < 1 {
+		return errors.NotValidf("canary count %d", canary)
+	}
+	if batchSize < 1 {
+		return errors.NotValidf("batch size %d", batchSize)
+	}
+	if *ch.URL() == *s.doc.CharmURL {
+		return errors.Errorf("already running charm %q", ch.URL())
+	}
+	units, err := s.AllUnits()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	SortUnitsByNumber(units)
+	var canaries []string
+	for _, unit := range units {
+		if len(canaries) == canary {
+			break
+		}
+		if unit.Life() == Alive {
+			canaries = append(canaries, unit.Name())
+		}
+	}
+	return s.setCharm(ch, force, &charmRolloutDoc{
+		From:      s.doc.CharmURL,
+		To:        ch.URL(),
+		BatchSize: batchSize,
+		Units:     canaries,
+	})
+}
+
+// CharmRollout returns the charm rollout in progress for the service,
+// and whether there is one.
+func (s *Service) CharmRollout() (CharmRollout, bool) {
+	doc := s.doc.CharmRollout
+	if doc == nil {
+		return CharmRollout{}, false
+	}
+	return CharmRollout{
+		From:      doc.From,
+		To:        doc.To,
+		BatchSize: doc.BatchSize,
+		Units:     doc.Units,
+		Paused:    doc.Paused,
+		Reason:    doc.Reason,
+	}, true
+}
+
+// UnitCharmURL returns the charm URL the named unit of the service
+// should run, and whether the unit should upgrade to it even if it is
+// in an error state. While a charm rollout is in progress, units that
+// have not been released to it keep the charm the service was upgraded
+// from.
+func (s *Service) UnitCharmURL(unitName string) (*charm.URL, bool) {
+	if rollout := s.doc.CharmRollout; rollout != nil {
+		for _, name := range rollout.Units {
+			if name == unitName {
+				return s.doc.CharmURL, s.doc.ForceCharm
+			}
+		}
+		return rollout.From, false
+	}
+	return s.doc.CharmURL, s.doc.ForceCharm
+}
+
+// PreviousCharmURL returns the URL of the charm the service ran before
+// its charm was last changed, or nil if it has not been changed.
+func (s *Service) PreviousCharmURL() *charm.URL {
+	return s.doc.PreviousCharmURL
+}
+
+// updateCharmRollout applies the update to the service's charm rollout,
+// which must be in progress and upgrading to the service's charm.
+func (s *Service) updateCharmRollout(update bson.D) error {
+	if s.doc.CharmRollout == nil {
+		return errors.New("no charm rollout in progress")
+	}
+	ops := []txn.Op{{
+		C:  servicesC,
+		Id: s.doc.DocID,
+		Assert: bson.D{
+			{"charmurl", s.doc.CharmURL},
+			{"charmrollout.to", s.doc.CharmURL},
+		},
+		Update: update,
+	}}
+	if err := s.st.runTransaction(ops); err == txn.ErrAborted {
+		return errors.New("charm rollout changed; refresh and try again")
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	return s.Refresh()
+}
+
+// ReleaseCharmRolloutUnits releases the named units to the service's
+// charm rollout, so that they upgrade to its charm.
+func (s *Service) ReleaseCharmRolloutUnits(unitNames []string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot release units of service %q", s.doc.Name)
+	return s.updateCharmRollout(bson.D{
+		{"$addToSet", bson.D{{"charmrollout.units", bson.D{{"$each", unitNames}}}}},
+	})
+}
+
+// PauseCharmRollout stops further units of the service from being
+// released to its charm rollout, for the given reason.
+func (s *Service) PauseCharmRollout(reason string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot pause charm rollout of service %q", s.doc.Name)
+	return s.updateCharmRollout(bson.D{
+		{"$set", bson.D{{"charmrollout.paused", true}, {"charmrollout.reason", reason}}},
+	})
+}
+
+// ResumeCharmRollout resumes the service's paused charm rollout.
+func (s *Service) ResumeCharmRollout() (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot resume charm rollout of service %q", s.doc.Name)
+	return s.updateCharmRollout(bson.D{
+		{"$set", bson.D{{"charmrollout.paused", false}}},
+		{"$unset", bson.D{{"charmrollout.reason", nil}}},
+	})
+}
+
+// FinishCharmRollout ends the service's charm rollout, once all its
+// units have been released to it.
+func (s *Service) FinishCharmRollout() (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot finish charm rollout of service %q", s.doc.Name)
+	return s.updateCharmRollout(bson.D{
+		{"$unset", bson.D{{"charmrollout", nil}}},
+	})
+}
+
+// RollbackCharm changes the charm for the service back to the one it
+// ran before its charm was last changed, abandoning any charm rollout
+// in progress. Units are reverted even if they are in an error state,
+// so that units whose upgrade failed revert to the previous charm.
+func (s *Service) RollbackCharm() (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot roll back charm of service %q", s.doc.Name)
+	if s.doc.PreviousCharmURL == nil {
+		return errors.New("no previous charm to roll back to")
+	}
+	ch, err := s.st.Charm(s.doc.PreviousCharmURL)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return s.setCharm(ch, true, nil)
+}
@@ -0,0 +1,131 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type CharmRolloutSuite struct {
+	ConnSuite
+	charm   *state.Charm
+	newer   *state.Charm
+	service *state.Service
+	units   []*state.Unit
+}
+
+var _ = gc.Suite(&CharmRolloutSuite{})
+
+func (s *CharmRolloutSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.charm = s.AddTestingCharm(c, "mysql")
+	s.newer = s.AddMetaCharm(c, "mysql", metaBase, 2)
+	s.service = s.AddTestingService(c, "mysql", s.charm)
+	s.units = nil
+	for i := 0; i < 4; i++ {
+		unit, err := s.service.AddUnit()
+		c.Assert(err, jc.ErrorIsNil)
+		s.units = append(s.units, unit)
+	}
+}
+
+func (s *CharmRolloutSuite) assertUnitCharmURLs(c *gc.C, expect ...*state.Charm) {
+	for i, unit := range s.units {
+		curl, _ := s.service.UnitCharmURL(unit.Name())
+		c.Check(curl, gc.DeepEquals, expect[i].URL(), gc.Commentf("unit %s", unit.Name()))
+	}
+}
+
+func (s *CharmRolloutSuite) TestSetCharmInBatches(c *gc.C) {
+	err := s.service.SetCharmInBatches(s.newer, false, 1, 2)
+	c.Assert(err, jc.ErrorIsNil)
+	curl, _ := s.service.CharmURL()
+	c.Assert(curl, gc.DeepEquals, s.newer.URL())
+	c.Assert(s.service.PreviousCharmURL(), gc.DeepEquals, s.charm.URL())
+
+	rollout, ok := s.service.CharmRollout()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(rollout, jc.DeepEquals, state.CharmRollout{
+		From:      s.charm.URL(),
+		To:        s.newer.URL(),
+		BatchSize: 2,
+		Units:     []string{"mysql/0"},
+	})
+	s.assertUnitCharmURLs(c, s.newer, s.charm, s.charm, s.charm)
+
+	err = s.service.ReleaseCharmRolloutUnits([]string{"mysql/1", "mysql/2"})
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertUnitCharmURLs(c, s.newer, s.newer, s.newer, s.charm)
+
+	err = s.service.FinishCharmRollout()
+	c.Assert(err, jc.ErrorIsNil)
+	_, ok = s.service.CharmRollout()
+	c.Assert(ok, jc.IsFalse)
+	s.assertUnitCharmURLs(c, s.newer, s.newer, s.newer, s.newer)
+}
+
+func (s *CharmRolloutSuite) TestSetCharmInBatchesInvalid(c *gc.C) {
+	err := s.service.SetCharmInBatches(s.newer, false, 0, 1)
+	c.Assert(err, gc.ErrorMatches, `cannot upgrade service "mysql" in batches: canary count 0 not valid`)
+	err = s.service.SetCharmInBatches(s.newer, false, 1, 0)
+	c.Assert(err, gc.ErrorMatches, `cannot upgrade service "mysql" in batches: batch size 0 not valid`)
+	err = s.service.SetCharmInBatches(s.charm, false, 1, 1)
+	c.Assert(err, gc.ErrorMatches, `cannot upgrade service "mysql" in batches: already running charm "local:quantal/quantal-mysql-1"`)
+}
+
+func (s *CharmRolloutSuite) TestPauseAndResume(c *gc.C) {
+	err := s.service.PauseCharmRollout("unit mysql/0 failed")
+	c.Assert(err, gc.ErrorMatches, `cannot pause charm rollout of service "mysql": no charm rollout in progress`)
+
+	err = s.service.SetCharmInBatches(s.newer, false, 1, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.service.PauseCharmRollout("unit mysql/0 failed")
+	c.Assert(err, jc.ErrorIsNil)
+	rollout, _ := s.service.CharmRollout()
+	c.Assert(rollout.Paused, jc.IsTrue)
+	c.Assert(rollout.Reason, gc.Equals, "unit mysql/0 failed")
+
+	err = s.service.ResumeCharmRollout()
+	c.Assert(err, jc.ErrorIsNil)
+	rollout, _ = s.service.CharmRollout()
+	c.Assert(rollout.Paused, jc.IsFalse)
+	c.Assert(rollout.Reason, gc.Equals, "")
+}
+
+func (s *CharmRolloutSuite) TestSetCharmAbandonsRollout(c *gc.C) {
+	err := s.service.SetCharmInBatches(s.newer, false, 1, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	newest := s.AddMetaCharm(c, "mysql", metaBase, 3)
+	err = s.service.SetCharm(newest, false)
+	c.Assert(err, jc.ErrorIsNil)
+
+	service, err := s.State.Service("mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	_, ok := service.CharmRollout()
+	c.Assert(ok, jc.IsFalse)
+	c.Assert(service.PreviousCharmURL(), gc.DeepEquals, s.newer.URL())
+}
+
+func (s *CharmRolloutSuite) TestRollbackCharm(c *gc.C) {
+	err := s.service.RollbackCharm()
+	c.Assert(err, gc.ErrorMatches, `cannot roll back charm of service "mysql": no previous charm to roll back to`)
+
+	err = s.service.SetCharmInBatches(s.newer, false, 2, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.service.RollbackCharm()
+	c.Assert(err, jc.ErrorIsNil)
+
+	service, err := s.State.Service("mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	curl, force := service.CharmURL()
+	c.Assert(curl, gc.DeepEquals, s.charm.URL())
+	c.Assert(force, jc.IsTrue)
+	_, ok := service.CharmRollout()
+	c.Assert(ok, jc.IsFalse)
+	s.service = service
+	s.assertUnitCharmURLs(c, s.charm, s.charm, s.charm, s.charm)
+}
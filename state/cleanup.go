@@ -246,7 +246,7 @@ func (st *State) cleanupRemovedUnit(unitId string) error {
 			return err
 		}
 	}
-	return nil
+	return removeHookExecutions(st, unitId)
 }
 
 // cleanupDyingMachine marks resources owned by the machine as dying, to ensure
@@ -27,6 +27,12 @@ type constraintsDoc struct {
 	Container    *instance.ContainerType
 	Tags         *[]string `bson:",omitempty"`
 	Networks     *[]string `bson:",omitempty"`
+	Spaces       *[]string `bson:",omitempty"`
+
+	VirtType        *string `bson:",omitempty"`
+	LocalDisks      *uint64 `bson:",omitempty"`
+	Accelerators    *uint64 `bson:",omitempty"`
+	AcceleratorType *string `bson:",omitempty"`
 }
 
 func (doc constraintsDoc) value() constraints.Value {
@@ -40,6 +46,12 @@ func (doc constraintsDoc) value() constraints.Value {
 		Container:    doc.Container,
 		Tags:         doc.Tags,
 		Networks:     doc.Networks,
+		Spaces:       doc.Spaces,
+
+		VirtType:        doc.VirtType,
+		LocalDisks:      doc.LocalDisks,
+		Accelerators:    doc.Accelerators,
+		AcceleratorType: doc.AcceleratorType,
 	}
 }
 
@@ -55,6 +67,12 @@ func newConstraintsDoc(st *State, cons constraints.Value) constraintsDoc {
 		Container:    cons.Container,
 		Tags:         cons.Tags,
 		Networks:     cons.Networks,
+		Spaces:       cons.Spaces,
+
+		VirtType:        cons.VirtType,
+		LocalDisks:      cons.LocalDisks,
+		Accelerators:    cons.Accelerators,
+		AcceleratorType: cons.AcceleratorType,
 	}
 }
 
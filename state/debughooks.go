@@ -0,0 +1,153 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// DebugHooksSession describes a debug-hooks session opened on a unit
+// through the API. The unit's matching hooks and actions run as
+// usual, with their output streamed to the client through the debug
+// log, until the session expires.
+type DebugHooksSession struct {
+	// Hooks holds the names of the hooks to stream.
+	Hooks []string
+
+	// Actions holds the names of the actions to stream.
+	Actions []string
+
+	// RelationIds restricts the session to relation hooks
+	// executing for the specified relations.
+	RelationIds []int
+
+	// Expires holds the time at which the session ends, unless
+	// the client renews it.
+	Expires time.Time
+}
+
+type debugHooksSessionDoc struct {
+	DocID       string    `bson:"_id"`
+	EnvUUID     string    `bson:"env-uuid"`
+	Unit        string    `bson:"unit"`
+	Hooks       []string  `bson:"hooks,omitempty"`
+	Actions     []string  `bson:"actions,omitempty"`
+	RelationIds []int     `bson:"relation-ids,omitempty"`
+	Expires     time.Time `bson:"expires"`
+}
+
+// SetDebugHooksSession opens or renews the unit's debug-hooks session.
+func (u *Unit) SetDebugHooksSession(session DebugHooksSession) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set debug-hooks session for unit %q", u.Name())
+	if session.Expires.IsZero() {
+		return errors.NotValidf("debug-hooks session without expiry")
+	}
+	doc := debugHooksSessionDoc{
+		EnvUUID:     u.st.EnvironUUID(),
+		Unit:        u.Name(),
+		Hooks:       session.Hooks,
+		Actions:     session.Actions,
+		RelationIds: session.RelationIds,
+		Expires:     session.Expires.UTC(),
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			alive, err := isAlive(u.st, unitsC, u.doc.DocID)
+			if err != nil {
+				return nil, errors.Trace(err)
+			} else if !alive {
+				return nil, errNotAlive
+			}
+		}
+		ops := []txn.Op{{
+			C:      unitsC,
+			Id:     u.doc.DocID,
+			Assert: isAliveDoc,
+		}}
+		if _, err := u.debugHooksSessionDoc(); errors.IsNotFound(err) {
+			return append(ops, txn.Op{
+				C:      debugHooksSessionsC,
+				Id:     u.st.docID(u.globalKey()),
+				Assert: txn.DocMissing,
+				Insert: &doc,
+			}), nil
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return append(ops, txn.Op{
+			C:      debugHooksSessionsC,
+			Id:     u.st.docID(u.globalKey()),
+			Assert: txn.DocExists,
+			Update: bson.D{{"$set", bson.D{
+				{"hooks", doc.Hooks},
+				{"actions", doc.Actions},
+				{"relation-ids", doc.RelationIds},
+				{"expires", doc.Expires},
+			}}},
+		}), nil
+	}
+	if err := u.st.run(buildTxn); err != nil {
+		if err == errNotAlive {
+			return errors.New("unit " + err.Error())
+		}
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// ClearDebugHooksSession closes the unit's debug-hooks session, if
+// it has one.
+func (u *Unit) ClearDebugHooksSession() error {
+	ops := []txn.Op{removeDebugHooksSessionOp(u.st, u.globalKey())}
+	err := u.st.runTransaction(ops)
+	return errors.Annotatef(err, "cannot clear debug-hooks session for unit %q", u.Name())
+}
+
+// DebugHooksSession returns the unit's debug-hooks session. It
+// returns a not found error if the unit has no session, or if the
+// session has expired.
+func (u *Unit) DebugHooksSession() (*DebugHooksSession, error) {
+	doc, err := u.debugHooksSessionDoc()
+	if err != nil {
+		return nil, err
+	}
+	if !doc.Expires.After(time.Now()) {
+		return nil, errors.NotFoundf("debug-hooks session for unit %q", u.Name())
+	}
+	return &DebugHooksSession{
+		Hooks:       doc.Hooks,
+		Actions:     doc.Actions,
+		RelationIds: doc.RelationIds,
+		Expires:     doc.Expires.UTC(),
+	}, nil
+}
+
+func (u *Unit) debugHooksSessionDoc() (*debugHooksSessionDoc, error) {
+	sessions, closer := u.st.getCollection(debugHooksSessionsC)
+	defer closer()
+
+	var doc debugHooksSessionDoc
+	err := sessions.FindId(u.st.docID(u.globalKey())).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("debug-hooks session for unit %q", u.Name())
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &doc, nil
+}
+
+// removeDebugHooksSessionOp returns the operation needed to remove
+// the debug-hooks session of the unit with the given global key.
+func removeDebugHooksSessionOp(st *State, globalKey string) txn.Op {
+	return txn.Op{
+		C:      debugHooksSessionsC,
+		Id:     st.docID(globalKey),
+		Remove: true,
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
+)
+
+type DebugHooksSessionSuite struct {
+	ConnSuite
+	unit *state.Unit
+}
+
+var _ = gc.Suite(&DebugHooksSessionSuite{})
+
+func (s *DebugHooksSessionSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.unit = factory.NewFactory(s.State).MakeUnit(c, nil)
+}
+
+func (s *DebugHooksSessionSuite) TestSetDebugHooksSession(c *gc.C) {
+	_, err := s.unit.DebugHooksSession()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	expires := time.Now().Add(time.Minute).Round(time.Second).UTC()
+	session := state.DebugHooksSession{
+		Hooks:       []string{"install"},
+		RelationIds: []int{3},
+		Expires:     expires,
+	}
+	err = s.unit.SetDebugHooksSession(session)
+	c.Assert(err, jc.ErrorIsNil)
+	got, err := s.unit.DebugHooksSession()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(*got, jc.DeepEquals, session)
+
+	// Setting the session again renews it.
+	session = state.DebugHooksSession{
+		Actions: []string{"backup"},
+		Expires: expires.Add(time.Minute),
+	}
+	err = s.unit.SetDebugHooksSession(session)
+	c.Assert(err, jc.ErrorIsNil)
+	got, err = s.unit.DebugHooksSession()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(*got, jc.DeepEquals, session)
+
+	err = s.unit.ClearDebugHooksSession()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.unit.DebugHooksSession()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *DebugHooksSessionSuite) TestDebugHooksSessionExpires(c *gc.C) {
+	err := s.unit.SetDebugHooksSession(state.DebugHooksSession{
+		Hooks:   []string{"install"},
+		Expires: time.Now().Add(-time.Second),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.unit.DebugHooksSession()
+	c.Assert(err, gc.ErrorMatches, `debug-hooks session for unit "mysql/0" not found`)
+}
+
+func (s *DebugHooksSessionSuite) TestDebugHooksSessionRemovedWithUnit(c *gc.C) {
+	err := s.unit.SetDebugHooksSession(state.DebugHooksSession{Expires: time.Now().Add(time.Minute)})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.unit.DebugHooksSession()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
@@ -53,6 +53,7 @@ var (
 	AddVolumeOp            = (*State).addVolumeOp
 	CombineMeterStatus     = combineMeterStatus
 	NewStatusNotFound      = newStatusNotFound
+	MaxHookExecutions      = &maxHookExecutionsPerUnit
 )
 
 type (
@@ -244,6 +245,20 @@ func GetUserPasswordSaltAndHash(u *User) (string, string) {
 	return u.doc.PasswordSalt, u.doc.PasswordHash
 }
 
+// SetUserPasswordChanged sets when the password of the user was last
+// changed, so that tests can age it.
+func SetUserPasswordChanged(c *gc.C, u *User, when time.Time) {
+	ops := []txn.Op{{
+		C:      usersC,
+		Id:     u.doc.DocID,
+		Update: bson.D{{"$set", bson.D{{"passwordchanged", when}}}},
+	}}
+	err := u.st.runTransaction(ops)
+	c.Assert(err, jc.ErrorIsNil)
+	err = u.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 func CheckUserExists(st *State, name string) (bool, error) {
 	return st.checkUserExists(name)
 }
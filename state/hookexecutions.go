@@ -0,0 +1,170 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// HookExecutionKind identifies what a unit executed.
+type HookExecutionKind string
+
+const (
+	HookExecutionHook    HookExecutionKind = "hook"
+	HookExecutionAction  HookExecutionKind = "action"
+	HookExecutionCommand HookExecutionKind = "command"
+)
+
+// maxHookExecutionsPerUnit is the size of the rolling window of
+// hook executions kept for each unit.
+var maxHookExecutionsPerUnit = 100
+
+// HookExecution records the timing and outcome of a single hook,
+// action or juju-run command executed by a unit.
+type HookExecution struct {
+	// Kind records whether a hook, action or command was executed.
+	Kind HookExecutionKind
+
+	// Name holds the name of the hook or action; it is empty
+	// for commands.
+	Name string
+
+	// Started and Finished record when the execution began and ended.
+	Started  time.Time
+	Finished time.Time
+
+	// ExitCode holds the exit code of the executed process; it is
+	// -1 if the process could not be run or was killed.
+	ExitCode int
+
+	// ToolCalls holds the number of hook tool invocations made
+	// during the execution.
+	ToolCalls int
+}
+
+// Duration returns the time taken by the execution.
+func (e HookExecution) Duration() time.Duration {
+	return e.Finished.Sub(e.Started)
+}
+
+// Validate returns an error if the execution is not valid.
+func (e HookExecution) Validate() error {
+	switch e.Kind {
+	case HookExecutionHook, HookExecutionAction:
+		if e.Name == "" {
+			return errors.NotValidf("%s execution without name", e.Kind)
+		}
+	case HookExecutionCommand:
+	default:
+		return errors.NotValidf("hook execution kind %q", e.Kind)
+	}
+	if e.Finished.Before(e.Started) {
+		return errors.NotValidf("hook execution finishing before it started")
+	}
+	return nil
+}
+
+// hookExecutionDoc represents a HookExecution in MongoDB. Seq
+// orders the executions of a unit, oldest first.
+type hookExecutionDoc struct {
+	DocID     string            `bson:"_id"`
+	EnvUUID   string            `bson:"env-uuid"`
+	Unit      string            `bson:"unit"`
+	Seq       int               `bson:"seq"`
+	Kind      HookExecutionKind `bson:"kind"`
+	Name      string            `bson:"name"`
+	Started   time.Time         `bson:"started"`
+	Finished  time.Time         `bson:"finished"`
+	ExitCode  int               `bson:"exitcode"`
+	ToolCalls int               `bson:"toolcalls"`
+}
+
+func (doc hookExecutionDoc) execution() HookExecution {
+	return HookExecution{
+		Kind:      doc.Kind,
+		Name:      doc.Name,
+		Started:   doc.Started.UTC(),
+		Finished:  doc.Finished.UTC(),
+		ExitCode:  doc.ExitCode,
+		ToolCalls: doc.ToolCalls,
+	}
+}
+
+// RecordHookExecution adds the supplied execution to the unit's
+// rolling window of hook executions, discarding the oldest entries
+// once the window is full.
+func (u *Unit) RecordHookExecution(execution HookExecution) error {
+	if err := execution.Validate(); err != nil {
+		return errors.Annotatef(err, "cannot record hook execution for unit %q", u.Name())
+	}
+	seq, err := u.st.sequence("hookexecutions")
+	if err != nil {
+		return errors.Annotatef(err, "cannot record hook execution for unit %q", u.Name())
+	}
+	doc := hookExecutionDoc{
+		DocID:     u.st.docID(fmt.Sprintf("%s#%d", u.Name(), seq)),
+		EnvUUID:   u.st.EnvironUUID(),
+		Unit:      u.Name(),
+		Seq:       seq,
+		Kind:      execution.Kind,
+		Name:      execution.Name,
+		Started:   execution.Started.UTC(),
+		Finished:  execution.Finished.UTC(),
+		ExitCode:  execution.ExitCode,
+		ToolCalls: execution.ToolCalls,
+	}
+	executions, closer := u.st.getCollection(hookExecutionsC)
+	defer closer()
+	executionsW := executions.Writeable()
+	if err := executionsW.Insert(&doc); err != nil {
+		return errors.Annotatef(err, "cannot record hook execution for unit %q", u.Name())
+	}
+
+	// Trim the window down to size.
+	var oldest hookExecutionDoc
+	err = executionsW.Find(bson.D{{"unit", u.Name()}}).Sort("-seq").Skip(maxHookExecutionsPerUnit - 1).One(&oldest)
+	if err == mgo.ErrNotFound {
+		return nil
+	} else if err != nil {
+		return errors.Annotatef(err, "cannot prune hook executions for unit %q", u.Name())
+	}
+	_, err = executionsW.RemoveAll(bson.D{
+		{"unit", u.Name()},
+		{"seq", bson.M{"$lt": oldest.Seq}},
+	})
+	return errors.Annotatef(err, "cannot prune hook executions for unit %q", u.Name())
+}
+
+// HookExecutions returns at most size of the unit's most recent
+// hook executions, most recent first.
+func (u *Unit) HookExecutions(size int) ([]HookExecution, error) {
+	executions, closer := u.st.getCollection(hookExecutionsC)
+	defer closer()
+
+	var docs []hookExecutionDoc
+	err := executions.Find(bson.D{{"unit", u.Name()}}).Sort("-seq").Limit(size).All(&docs)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get hook executions for unit %q", u.Name())
+	}
+	results := make([]HookExecution, len(docs))
+	for i, doc := range docs {
+		results[i] = doc.execution()
+	}
+	return results, nil
+}
+
+// removeHookExecutions removes all recorded hook executions for the
+// named unit. It is called by the cleanup of a removed unit, as the
+// executions are not written in transactions.
+func removeHookExecutions(st *State, unitName string) error {
+	executions, closer := st.getCollection(hookExecutionsC)
+	defer closer()
+	_, err := executions.Writeable().RemoveAll(bson.D{{"unit", unitName}})
+	return errors.Annotatef(err, "cannot remove hook executions for unit %q", unitName)
+}
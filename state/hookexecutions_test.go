@@ -0,0 +1,109 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"fmt"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
+)
+
+type HookExecutionsSuite struct {
+	ConnSuite
+	unit *state.Unit
+}
+
+var _ = gc.Suite(&HookExecutionsSuite{})
+
+func (s *HookExecutionsSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.unit = factory.NewFactory(s.State).MakeUnit(c, nil)
+}
+
+func (s *HookExecutionsSuite) execution(name string, exitCode int) state.HookExecution {
+	started := time.Date(2015, 7, 1, 12, 0, 0, 0, time.UTC)
+	return state.HookExecution{
+		Kind:      state.HookExecutionHook,
+		Name:      name,
+		Started:   started,
+		Finished:  started.Add(3 * time.Second),
+		ExitCode:  exitCode,
+		ToolCalls: 7,
+	}
+}
+
+func (s *HookExecutionsSuite) TestRecordHookExecution(c *gc.C) {
+	executions, err := s.unit.HookExecutions(10)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(executions, gc.HasLen, 0)
+
+	install := s.execution("install", 0)
+	err = s.unit.RecordHookExecution(install)
+	c.Assert(err, jc.ErrorIsNil)
+	start := s.execution("start", 1)
+	err = s.unit.RecordHookExecution(start)
+	c.Assert(err, jc.ErrorIsNil)
+	command := state.HookExecution{
+		Kind:     state.HookExecutionCommand,
+		Started:  start.Started,
+		Finished: start.Finished,
+	}
+	err = s.unit.RecordHookExecution(command)
+	c.Assert(err, jc.ErrorIsNil)
+
+	executions, err = s.unit.HookExecutions(10)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(executions, jc.DeepEquals, []state.HookExecution{command, start, install})
+	c.Assert(executions[1].Duration(), gc.Equals, 3*time.Second)
+
+	executions, err = s.unit.HookExecutions(1)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(executions, jc.DeepEquals, []state.HookExecution{command})
+}
+
+func (s *HookExecutionsSuite) TestRecordHookExecutionInvalid(c *gc.C) {
+	err := s.unit.RecordHookExecution(state.HookExecution{Kind: "bogus"})
+	c.Assert(err, gc.ErrorMatches, `cannot record hook execution for unit "mysql/0": hook execution kind "bogus" not valid`)
+
+	err = s.unit.RecordHookExecution(state.HookExecution{Kind: state.HookExecutionAction})
+	c.Assert(err, gc.ErrorMatches, `cannot record hook execution for unit "mysql/0": action execution without name not valid`)
+
+	execution := s.execution("install", 0)
+	execution.Finished = execution.Started.Add(-time.Second)
+	err = s.unit.RecordHookExecution(execution)
+	c.Assert(err, gc.ErrorMatches, `cannot record hook execution for unit "mysql/0": hook execution finishing before it started not valid`)
+}
+
+func (s *HookExecutionsSuite) TestRecordHookExecutionRollingWindow(c *gc.C) {
+	s.PatchValue(state.MaxHookExecutions, 3)
+	for i := 0; i < 5; i++ {
+		err := s.unit.RecordHookExecution(s.execution(fmt.Sprintf("hook-%d", i), 0))
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	executions, err := s.unit.HookExecutions(10)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(executions, gc.HasLen, 3)
+	c.Assert(executions[0].Name, gc.Equals, "hook-4")
+	c.Assert(executions[2].Name, gc.Equals, "hook-2")
+}
+
+func (s *HookExecutionsSuite) TestHookExecutionsRemovedWithUnit(c *gc.C) {
+	err := s.unit.RecordHookExecution(s.execution("install", 0))
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.Cleanup()
+	c.Assert(err, jc.ErrorIsNil)
+
+	executions, err := s.unit.HookExecutions(10)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(executions, gc.HasLen, 0)
+}
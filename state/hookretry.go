@@ -0,0 +1,113 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// HookRetryPolicy describes how the units of a service retry failed
+// hooks without operator intervention. The zero value disables
+// automatic retries.
+type HookRetryPolicy struct {
+	// MaxAttempts holds the number of times a failed hook will be
+	// retried before the unit waits to be resolved.
+	MaxAttempts int
+
+	// Delay holds the time to wait before the first retry; it is
+	// doubled after each subsequent failure.
+	Delay time.Duration
+
+	// MaxDelay caps the time to wait between retries. If it is
+	// zero, the delay grows without bound.
+	MaxDelay time.Duration
+}
+
+// Validate returns an error if the policy is not valid.
+func (p HookRetryPolicy) Validate() error {
+	if p.MaxAttempts < 0 {
+		return errors.NotValidf("negative hook retry attempts")
+	}
+	if p.Delay < 0 || p.MaxDelay < 0 {
+		return errors.NotValidf("negative hook retry delay")
+	}
+	if p.MaxAttempts > 0 && p.Delay == 0 {
+		return errors.NotValidf("hook retry policy without delay")
+	}
+	if p.MaxDelay != 0 && p.MaxDelay < p.Delay {
+		return errors.NotValidf("hook retry maximum delay less than delay")
+	}
+	return nil
+}
+
+// hookRetryPolicyDoc represents a HookRetryPolicy in MongoDB.
+type hookRetryPolicyDoc struct {
+	MaxAttempts int           `bson:"maxattempts"`
+	Delay       time.Duration `bson:"delay"`
+	MaxDelay    time.Duration `bson:"maxdelay"`
+}
+
+// HookRetryPolicy returns the policy used by the service's units to
+// retry failed hooks.
+func (s *Service) HookRetryPolicy() HookRetryPolicy {
+	doc := s.doc.HookRetryPolicy
+	if doc == nil {
+		return HookRetryPolicy{}
+	}
+	return HookRetryPolicy{
+		MaxAttempts: doc.MaxAttempts,
+		Delay:       doc.Delay,
+		MaxDelay:    doc.MaxDelay,
+	}
+}
+
+// SetHookRetryPolicy changes the policy used by the service's units
+// to retry failed hooks. Setting the zero policy disables automatic
+// retries.
+func (s *Service) SetHookRetryPolicy(policy HookRetryPolicy) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set hook retry policy for service %q", s.doc.Name)
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+	var doc *hookRetryPolicyDoc
+	var update bson.D
+	if policy == (HookRetryPolicy{}) {
+		update = bson.D{{"$unset", bson.D{{"hookretrypolicy", nil}}}}
+	} else {
+		doc = &hookRetryPolicyDoc{
+			MaxAttempts: policy.MaxAttempts,
+			Delay:       policy.Delay,
+			MaxDelay:    policy.MaxDelay,
+		}
+		update = bson.D{{"$set", bson.D{{"hookretrypolicy", doc}}}}
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			alive, err := isAlive(s.st, servicesC, s.doc.DocID)
+			if err != nil {
+				return nil, errors.Trace(err)
+			} else if !alive {
+				return nil, errNotAlive
+			}
+		}
+		return []txn.Op{{
+			C:      servicesC,
+			Id:     s.doc.DocID,
+			Assert: isAliveDoc,
+			Update: update,
+		}}, nil
+	}
+	if err := s.st.run(buildTxn); err != nil {
+		if err == errNotAlive {
+			return errors.New("service " + err.Error())
+		}
+		return errors.Trace(err)
+	}
+	s.doc.HookRetryPolicy = doc
+	return nil
+}
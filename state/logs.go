@@ -144,7 +144,10 @@ type LogTailerParams struct {
 	ExcludeEntity []string
 	IncludeModule []string
 	ExcludeModule []string
-	Oplog         *mgo.Collection // For testing only
+	// Trace, if set, restricts the logs to those whose message
+	// holds the given trace id of API requests.
+	Trace string
+	Oplog *mgo.Collection // For testing only
 }
 
 // oplogOverlap is used to decide on the initial oplog timestamp to
@@ -337,6 +340,10 @@ func (t *logTailer) paramsToSelector(params *LogTailerParams, prefix string) bso
 		sel = append(sel,
 			bson.DocElem{"m", bson.M{"$not": bson.RegEx{Pattern: makeModulePattern(params.ExcludeModule)}}})
 	}
+	if params.Trace != "" {
+		sel = append(sel,
+			bson.DocElem{"x", bson.RegEx{Pattern: regexp.QuoteMeta(params.Trace)}})
+	}
 
 	if prefix != "" {
 		for i, elem := range sel {
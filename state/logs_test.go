@@ -473,6 +473,25 @@ func (s *LogTailerSuite) TestIncludeExcludeModule(c *gc.C) {
 	s.checkLogTailerFiltering(params, writeLogs, assert)
 }
 
+func (s *LogTailerSuite) TestTrace(c *gc.C) {
+	untraced := logTemplate{Message: "no trace here"}
+	traced := logTemplate{Message: `<- {"TraceId":"a.trace"}`}
+	other := logTemplate{Message: `<- {"TraceId":"a-trace"}`}
+	writeLogs := func() {
+		s.writeLogs(c, 1, untraced)
+		s.writeLogs(c, 1, traced)
+		s.writeLogs(c, 1, other)
+	}
+	params := &state.LogTailerParams{
+		Trace: "a.trace",
+	}
+	assert := func(tailer state.LogTailer) {
+		// The trace id is matched literally.
+		s.assertTailer(c, tailer, 1, traced)
+	}
+	s.checkLogTailerFiltering(params, writeLogs, assert)
+}
+
 func (s *LogTailerSuite) checkLogTailerFiltering(
 	params *state.LogTailerParams,
 	writeLogs func(),
@@ -130,6 +130,9 @@ type machineDoc struct {
 	// Placement is the placement directive that should be used when provisioning
 	// an instance for the machine.
 	Placement string `bson:",omitempty"`
+	// Pool holds the name of the spare machine pool the machine
+	// was added to, if any.
+	Pool string `bson:"pool,omitempty"`
 }
 
 func newMachine(st *State, doc *machineDoc) *Machine {
@@ -1399,6 +1402,12 @@ func (m *Machine) Placement() string {
 	return m.doc.Placement
 }
 
+// Pool returns the name of the spare machine pool the machine was
+// added to, or the empty string if it was not added to a pool.
+func (m *Machine) Pool() string {
+	return m.doc.Pool
+}
+
 // Constraints returns the exact constraints that should apply when provisioning
 // an instance for the machine.
 func (m *Machine) Constraints() (constraints.Value, error) {
@@ -1464,11 +1473,24 @@ func (m *Machine) Status() (StatusInfo, error) {
 	}, nil
 }
 
+// StatusHistory returns a slice of at most <size> StatusInfo items
+// representing past statuses for this machine.
+func (m *Machine) StatusHistory(size int) ([]StatusInfo, error) {
+	return statusHistory(size, m.globalKey(), m.st)
+}
+
 // SetStatus sets the status of the machine.
 func (m *Machine) SetStatus(status Status, info string, data map[string]interface{}) error {
+	oldDoc, err := getStatus(m.st, m.globalKey())
+	if IsStatusNotFound(err) {
+		logger.Debugf("there is no state for %q yet", m.globalKey())
+	} else if err != nil {
+		logger.Debugf("cannot get state for %q yet", m.globalKey())
+	}
+
 	// If a machine is not yet provisioned, we allow its status
 	// to be set back to pending (when a retry is to occur).
-	_, err := m.InstanceId()
+	_, err = m.InstanceId()
 	allowPending := errors.IsNotProvisioned(err)
 	doc, err := newMachineStatusDoc(status, info, data, allowPending)
 	if err != nil {
@@ -1484,6 +1506,12 @@ func (m *Machine) SetStatus(status Status, info string, data map[string]interfac
 	if err = m.st.runTransaction(ops); err != nil {
 		return fmt.Errorf("cannot set status of machine %q: %v", m, onAbort(err, errNotAlive))
 	}
+
+	if oldDoc.Status != "" {
+		if err := updateStatusHistory(oldDoc, m.globalKey(), m.st); err != nil {
+			logger.Errorf("could not record status history before change to %q: %v", status, err)
+		}
+	}
 	return nil
 }
 
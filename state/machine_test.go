@@ -1739,6 +1739,34 @@ func (s *MachineSuite) TestSetStatusPending(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `cannot set status "pending"`)
 }
 
+func (s *MachineSuite) TestStatusHistory(c *gc.C) {
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.SetStatus(state.StatusError, "capacity exceeded", map[string]interface{}{
+		"transient": true,
+		"attempt":   1,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.SetStatus(state.StatusPending, "retrying provisioning (attempt 2 of 2)", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	history, err := machine.StatusHistory(10)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 2)
+	c.Assert(history[0].Status, gc.Equals, state.StatusError)
+	c.Assert(history[0].Message, gc.Equals, "capacity exceeded")
+	c.Assert(history[0].Data, jc.DeepEquals, map[string]interface{}{
+		"transient": true,
+		"attempt":   1,
+	})
+	c.Assert(history[1].Status, gc.Equals, state.StatusPending)
+
+	history, err = machine.StatusHistory(1)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 1)
+	c.Assert(history[0].Status, gc.Equals, state.StatusError)
+}
+
 func (s *MachineSuite) TestGetSetStatusWhileNotAlive(c *gc.C) {
 	// When Dying set/get should work.
 	err := s.machine.Destroy()
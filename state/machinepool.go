@@ -0,0 +1,269 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"regexp"
+	"sort"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/constraints"
+)
+
+// MachinePool describes a pool of spare machines that the provisioner
+// keeps ready for units to be assigned to. A spare machine is a clean
+// machine added on behalf of the pool; it stops being a spare as soon
+// as a unit is assigned to it.
+type MachinePool struct {
+	// Name identifies the pool within the environment.
+	Name string
+
+	// Size holds the number of spare machines to keep in the pool.
+	Size int
+
+	// Series holds the series of the pool's machines.
+	Series string
+
+	// Constraints holds the constraints used to provision the pool's
+	// machines.
+	Constraints constraints.Value
+}
+
+var validMachinePoolName = regexp.MustCompile("^[a-z][a-z0-9-]*$")
+
+// Validate returns an error if the pool is not valid.
+func (p MachinePool) Validate() error {
+	if !validMachinePoolName.MatchString(p.Name) {
+		return errors.NotValidf("machine pool name %q", p.Name)
+	}
+	if p.Size < 0 {
+		return errors.NotValidf("negative machine pool size")
+	}
+	if p.Series == "" {
+		return errors.NotValidf("machine pool without series")
+	}
+	if p.Constraints.HasContainer() {
+		return errors.NotValidf("machine pool with container constraint")
+	}
+	return nil
+}
+
+type machinePoolDoc struct {
+	DocID   string `bson:"_id"`
+	EnvUUID string `bson:"env-uuid"`
+	Name    string `bson:"name"`
+	Size    int    `bson:"size"`
+	Series  string `bson:"series"`
+}
+
+// machinePoolGlobalKey returns the global database key for the named
+// machine pool.
+func machinePoolGlobalKey(name string) string {
+	return "p#" + name
+}
+
+// SetMachinePool creates the supplied machine pool, or replaces the
+// size, series and constraints of the existing pool with the same name.
+// Spare machines already added to the pool are not changed.
+func (st *State) SetMachinePool(pool MachinePool) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set machine pool %q", pool.Name)
+	if err := pool.Validate(); err != nil {
+		return err
+	}
+	key := machinePoolGlobalKey(pool.Name)
+	doc := &machinePoolDoc{
+		DocID:   st.docID(key),
+		EnvUUID: st.EnvironUUID(),
+		Name:    pool.Name,
+		Size:    pool.Size,
+		Series:  pool.Series,
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if _, err := st.machinePoolDoc(pool.Name); errors.IsNotFound(err) {
+			return []txn.Op{{
+				C:      machinePoolsC,
+				Id:     doc.DocID,
+				Assert: txn.DocMissing,
+				Insert: doc,
+			}, createConstraintsOp(st, key, pool.Constraints)}, nil
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return []txn.Op{{
+			C:      machinePoolsC,
+			Id:     doc.DocID,
+			Assert: txn.DocExists,
+			Update: bson.D{{"$set", bson.D{
+				{"size", pool.Size},
+				{"series", pool.Series},
+			}}},
+		}, setConstraintsOp(st, key, pool.Constraints)}, nil
+	}
+	return errors.Trace(st.run(buildTxn))
+}
+
+// RemoveMachinePool removes the named machine pool, and destroys the
+// pool's remaining spare machines.
+func (st *State) RemoveMachinePool(name string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot remove machine pool %q", name)
+	key := machinePoolGlobalKey(name)
+	ops := []txn.Op{{
+		C:      machinePoolsC,
+		Id:     st.docID(key),
+		Assert: txn.DocExists,
+		Remove: true,
+	}, removeConstraintsOp(st, key)}
+	if err := st.runTransaction(ops); err == txn.ErrAborted {
+		return errors.NotFoundf("machine pool %q", name)
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	spares, err := st.SpareMachines(name)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(destroySpareMachines(spares))
+}
+
+// MachinePools returns all the machine pools in the environment,
+// sorted by name.
+func (st *State) MachinePools() ([]MachinePool, error) {
+	pools, closer := st.getCollection(machinePoolsC)
+	defer closer()
+
+	var docs []machinePoolDoc
+	if err := pools.Find(nil).Sort("name").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get machine pools")
+	}
+	result := make([]MachinePool, len(docs))
+	for i, doc := range docs {
+		pool, err := st.machinePoolFromDoc(doc)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		result[i] = pool
+	}
+	return result, nil
+}
+
+// MachinePool returns the named machine pool.
+func (st *State) MachinePool(name string) (MachinePool, error) {
+	doc, err := st.machinePoolDoc(name)
+	if err != nil {
+		return MachinePool{}, errors.Trace(err)
+	}
+	return st.machinePoolFromDoc(*doc)
+}
+
+func (st *State) machinePoolDoc(name string) (*machinePoolDoc, error) {
+	pools, closer := st.getCollection(machinePoolsC)
+	defer closer()
+
+	var doc machinePoolDoc
+	err := pools.FindId(machinePoolGlobalKey(name)).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("machine pool %q", name)
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot get machine pool %q", name)
+	}
+	return &doc, nil
+}
+
+func (st *State) machinePoolFromDoc(doc machinePoolDoc) (MachinePool, error) {
+	cons, err := readConstraints(st, machinePoolGlobalKey(doc.Name))
+	if err != nil {
+		return MachinePool{}, errors.Annotatef(err, "cannot get constraints of machine pool %q", doc.Name)
+	}
+	return MachinePool{
+		Name:        doc.Name,
+		Size:        doc.Size,
+		Series:      doc.Series,
+		Constraints: cons,
+	}, nil
+}
+
+// SpareMachines returns the alive machines of the named pool that no
+// unit has been assigned to, sorted by id.
+func (st *State) SpareMachines(pool string) ([]*Machine, error) {
+	machines, closer := st.getCollection(machinesC)
+	defer closer()
+
+	var docs []*machineDoc
+	err := machines.Find(bson.D{
+		{"pool", pool},
+		{"life", Alive},
+		{"clean", true},
+	}).All(&docs)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get spare machines of pool %q", pool)
+	}
+	result := make([]*Machine, len(docs))
+	for i, doc := range docs {
+		result[i] = newMachine(st, doc)
+	}
+	sort.Sort(machinesById(result))
+	return result, nil
+}
+
+// EnsureMachinePools adds spare machines to every machine pool that
+// has fewer than its size, and destroys the most recently added spares
+// of pools that have more. It returns the machines added.
+func (st *State) EnsureMachinePools() (_ []*Machine, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot ensure machine pools")
+	pools, err := st.MachinePools()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var added []*Machine
+	for _, pool := range pools {
+		spares, err := st.SpareMachines(pool.Name)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if len(spares) > pool.Size {
+			if err := destroySpareMachines(spares[pool.Size:]); err != nil {
+				return nil, errors.Trace(err)
+			}
+			continue
+		}
+		for i := len(spares); i < pool.Size; i++ {
+			m, err := st.AddOneMachine(MachineTemplate{
+				Series:      pool.Series,
+				Constraints: pool.Constraints,
+				Jobs:        []MachineJob{JobHostUnits},
+				pool:        pool.Name,
+			})
+			if err != nil {
+				return nil, errors.Annotatef(err, "cannot add spare machine to pool %q", pool.Name)
+			}
+			logger.Infof("added spare machine %v to pool %q", m, pool.Name)
+			added = append(added, m)
+		}
+	}
+	return added, nil
+}
+
+// destroySpareMachines destroys the supplied spare machines, ignoring
+// those that have had units assigned to them in the meantime.
+func destroySpareMachines(spares []*Machine) error {
+	for _, m := range spares {
+		if err := m.Destroy(); err != nil && !IsHasAssignedUnitsError(err) {
+			return errors.Annotatef(err, "cannot destroy spare machine %v", m)
+		}
+	}
+	return nil
+}
+
+// machinesById sorts machines by id, numerically where possible.
+type machinesById []*Machine
+
+func (s machinesById) Len() int      { return len(s) }
+func (s machinesById) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s machinesById) Less(i, j int) bool {
+	return machineIdLessThan(s[i].Id(), s[j].Id())
+}
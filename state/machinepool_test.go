@@ -0,0 +1,236 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/state"
+)
+
+type MachinePoolSuite struct {
+	ConnSuite
+	wordpress *state.Service
+}
+
+var _ = gc.Suite(&MachinePoolSuite{})
+
+func (s *MachinePoolSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.wordpress = s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+}
+
+func (s *MachinePoolSuite) setPool(c *gc.C, name string, size int, cons string) {
+	err := s.State.SetMachinePool(state.MachinePool{
+		Name:        name,
+		Size:        size,
+		Series:      "quantal",
+		Constraints: constraints.MustParse(cons),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *MachinePoolSuite) ensurePools(c *gc.C) []string {
+	added, err := s.State.EnsureMachinePools()
+	c.Assert(err, jc.ErrorIsNil)
+	ids := make([]string, len(added))
+	for i, m := range added {
+		ids[i] = m.Id()
+	}
+	return ids
+}
+
+func (s *MachinePoolSuite) spareIds(c *gc.C, pool string) []string {
+	spares, err := s.State.SpareMachines(pool)
+	c.Assert(err, jc.ErrorIsNil)
+	ids := make([]string, len(spares))
+	for i, m := range spares {
+		ids[i] = m.Id()
+	}
+	return ids
+}
+
+func (s *MachinePoolSuite) TestSetMachinePool(c *gc.C) {
+	pools, err := s.State.MachinePools()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pools, gc.HasLen, 0)
+
+	s.setPool(c, "web", 2, "mem=4G")
+	s.setPool(c, "db", 1, "")
+	pools, err = s.State.MachinePools()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pools, jc.DeepEquals, []state.MachinePool{{
+		Name:   "db",
+		Size:   1,
+		Series: "quantal",
+	}, {
+		Name:        "web",
+		Size:        2,
+		Series:      "quantal",
+		Constraints: constraints.MustParse("mem=4G"),
+	}})
+
+	s.setPool(c, "web", 3, "cpu-cores=2")
+	pool, err := s.State.MachinePool("web")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pool, jc.DeepEquals, state.MachinePool{
+		Name:        "web",
+		Size:        3,
+		Series:      "quantal",
+		Constraints: constraints.MustParse("cpu-cores=2"),
+	})
+}
+
+func (s *MachinePoolSuite) TestSetMachinePoolInvalid(c *gc.C) {
+	for i, test := range []struct {
+		pool state.MachinePool
+		err  string
+	}{{
+		pool: state.MachinePool{Name: "Web", Series: "quantal"},
+		err:  `machine pool name "Web" not valid`,
+	}, {
+		pool: state.MachinePool{Name: "web", Series: "quantal", Size: -1},
+		err:  "negative machine pool size not valid",
+	}, {
+		pool: state.MachinePool{Name: "web"},
+		err:  "machine pool without series not valid",
+	}, {
+		pool: state.MachinePool{Name: "web", Series: "quantal", Constraints: constraints.MustParse("container=lxc")},
+		err:  "machine pool with container constraint not valid",
+	}} {
+		c.Logf("test %d: %+v", i, test.pool)
+		err := s.State.SetMachinePool(test.pool)
+		c.Check(err, gc.ErrorMatches, `cannot set machine pool "`+test.pool.Name+`": `+test.err)
+		c.Check(err, jc.Satisfies, errors.IsNotValid)
+	}
+}
+
+func (s *MachinePoolSuite) TestEnsureMachinePools(c *gc.C) {
+	s.setPool(c, "web", 2, "mem=4G")
+	c.Assert(s.ensurePools(c), jc.DeepEquals, []string{"0", "1"})
+	c.Assert(s.ensurePools(c), gc.HasLen, 0)
+	c.Assert(s.spareIds(c, "web"), jc.DeepEquals, []string{"0", "1"})
+
+	m, err := s.State.Machine("1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.Pool(), gc.Equals, "web")
+	c.Assert(m.Series(), gc.Equals, "quantal")
+	c.Assert(m.Jobs(), jc.DeepEquals, []state.MachineJob{state.JobHostUnits})
+	cons, err := m.Constraints()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cons, jc.DeepEquals, constraints.MustParse("mem=4G"))
+
+	// Shrinking the pool destroys the most recently added spares.
+	s.setPool(c, "web", 1, "mem=4G")
+	c.Assert(s.ensurePools(c), gc.HasLen, 0)
+	c.Assert(s.spareIds(c, "web"), jc.DeepEquals, []string{"0"})
+	err = m.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.Life(), gc.Equals, state.Dying)
+}
+
+func (s *MachinePoolSuite) TestRemoveMachinePool(c *gc.C) {
+	s.setPool(c, "web", 1, "")
+	c.Assert(s.ensurePools(c), jc.DeepEquals, []string{"0"})
+
+	err := s.State.RemoveMachinePool("web")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.MachinePool("web")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(s.spareIds(c, "web"), gc.HasLen, 0)
+
+	err = s.State.RemoveMachinePool("web")
+	c.Assert(err, gc.ErrorMatches, `cannot remove machine pool "web": machine pool "web" not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *MachinePoolSuite) TestAssignUnitPrefersSpareMachine(c *gc.C) {
+	_, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	s.setPool(c, "web", 1, "")
+	c.Assert(s.ensurePools(c), jc.DeepEquals, []string{"1"})
+
+	for i, policy := range []state.AssignmentPolicy{
+		state.AssignCleanEmpty, state.AssignClean, state.AssignNew,
+	} {
+		c.Logf("test %d: %s", i, policy)
+		unit, err := s.wordpress.AddUnit()
+		c.Assert(err, jc.ErrorIsNil)
+		spares := s.spareIds(c, "web")
+		c.Assert(spares, gc.HasLen, 1)
+		err = s.State.AssignUnit(unit, policy)
+		c.Assert(err, jc.ErrorIsNil)
+		machineId, err := unit.AssignedMachineId()
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(machineId, gc.Equals, spares[0])
+
+		// The assigned machine is no longer a spare, so the pool
+		// is replenished.
+		c.Assert(s.spareIds(c, "web"), gc.HasLen, 0)
+		c.Assert(s.ensurePools(c), gc.HasLen, 1)
+	}
+}
+
+func (s *MachinePoolSuite) TestAssignUnitSpareMachineConstraints(c *gc.C) {
+	s.setPool(c, "small", 1, "")
+	c.Assert(s.ensurePools(c), jc.DeepEquals, []string{"0"})
+	m, err := s.State.Machine("0")
+	c.Assert(err, jc.ErrorIsNil)
+	mem := uint64(1024)
+	err = m.SetProvisioned("inst-0", "fake_nonce", &instance.HardwareCharacteristics{Mem: &mem})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.wordpress.SetConstraints(constraints.MustParse("mem=4G"))
+	c.Assert(err, jc.ErrorIsNil)
+	unit, err := s.wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AssignUnit(unit, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+	machineId, err := unit.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machineId, gc.Not(gc.Equals), "0")
+	c.Assert(s.spareIds(c, "small"), jc.DeepEquals, []string{"0"})
+}
+
+func (s *MachinePoolSuite) TestAssignUnitUnprovisionedSpareMachineConstraints(c *gc.C) {
+	s.setPool(c, "small", 1, "mem=1G")
+	s.setPool(c, "large", 1, "mem=8G")
+	c.Assert(s.ensurePools(c), gc.HasLen, 2)
+	large := s.spareIds(c, "large")
+	c.Assert(large, gc.HasLen, 1)
+
+	// The unprovisioned spare of the large pool will be started
+	// with enough memory for the unit.
+	err := s.wordpress.SetConstraints(constraints.MustParse("mem=4G"))
+	c.Assert(err, jc.ErrorIsNil)
+	unit, err := s.wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AssignUnit(unit, state.AssignNew)
+	c.Assert(err, jc.ErrorIsNil)
+	machineId, err := unit.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machineId, gc.Equals, large[0])
+}
+
+func (s *MachinePoolSuite) TestAssignUnitSpareMachineUncheckedConstraints(c *gc.C) {
+	s.setPool(c, "web", 1, "")
+	c.Assert(s.ensurePools(c), jc.DeepEquals, []string{"0"})
+
+	// Spares cannot be checked against an instance type, so the
+	// unit gets a new machine.
+	err := s.wordpress.SetConstraints(constraints.MustParse("instance-type=m1.small"))
+	c.Assert(err, jc.ErrorIsNil)
+	unit, err := s.wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AssignUnit(unit, state.AssignNew)
+	c.Assert(err, jc.ErrorIsNil)
+	machineId, err := unit.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machineId, gc.Not(gc.Equals), "0")
+	c.Assert(s.spareIds(c, "web"), jc.DeepEquals, []string{"0"})
+}
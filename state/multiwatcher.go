@@ -19,6 +19,9 @@ import (
 type Multiwatcher struct {
 	all *storeManager
 
+	// filter selects the changes sent by the watcher.
+	filter multiwatcher.Filter
+
 	// The following fields are maintained by the storeManager
 	// goroutine.
 	revno   int64
@@ -28,8 +31,15 @@ type Multiwatcher struct {
 // NewMultiwatcher creates a new watcher that can observe
 // changes to an underlying store manager.
 func NewMultiwatcher(all *storeManager) *Multiwatcher {
+	return NewFilteredMultiwatcher(all, multiwatcher.Filter{})
+}
+
+// NewFilteredMultiwatcher creates a new watcher that observes the
+// changes to an underlying store manager selected by the given filter.
+func NewFilteredMultiwatcher(all *storeManager, filter multiwatcher.Filter) *Multiwatcher {
 	return &Multiwatcher{
-		all: all,
+		all:    all,
+		filter: filter,
 	}
 }
 
@@ -234,8 +244,17 @@ func (sm *storeManager) respond() {
 		if len(changes) == 0 {
 			continue
 		}
-		req.changes = changes
 		w.revno = sm.all.latestRevno
+		// Filter the changes before replying, so that the watcher
+		// is only woken, and only sends to its client, the changes
+		// it is interested in. The store still holds every entity.
+		if changes = w.filter.Apply(changes); len(changes) == 0 {
+			// The watcher has still seen the changes, and keeps
+			// waiting for some it is interested in.
+			sm.seen(revno)
+			continue
+		}
+		req.changes = changes
 		req.reply <- true
 		if req := req.next; req == nil {
 			// Last request for this watcher.
@@ -0,0 +1,198 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package multiwatcher
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/juju/names"
+)
+
+// idFields holds, for each entity kind, the name of the info field
+// that identifies the entity. It is kept by every projection.
+var idFields = map[string]string{
+	"machine":    "Id",
+	"service":    "Name",
+	"unit":       "Name",
+	"action":     "Id",
+	"relation":   "Key",
+	"annotation": "Tag",
+	"block":      "Id",
+}
+
+// infoTypes holds, for each entity kind, the type of its info.
+var infoTypes = map[string]reflect.Type{
+	"machine":    reflect.TypeOf(MachineInfo{}),
+	"service":    reflect.TypeOf(ServiceInfo{}),
+	"unit":       reflect.TypeOf(UnitInfo{}),
+	"action":     reflect.TypeOf(ActionInfo{}),
+	"relation":   reflect.TypeOf(RelationInfo{}),
+	"annotation": reflect.TypeOf(AnnotationInfo{}),
+	"block":      reflect.TypeOf(BlockInfo{}),
+}
+
+// Filter selects the deltas sent to a watcher, and the fields of the
+// entities they hold. The zero Filter selects everything.
+type Filter struct {
+	// Kinds holds the entity kinds to send, such as "service" or
+	// "unit". If it is empty, entities of all kinds are sent.
+	Kinds []string
+
+	// Services holds the names of the services whose entities are
+	// sent: the services themselves, their units, the actions
+	// and annotations of those, and the relations they take part
+	// in. If it is empty, the entities of all services are sent.
+	// Entities not belonging to a service are only filtered by
+	// kind: all machines and blocks are sent, including machines
+	// that host no units of the selected services, as are the
+	// annotations of machines and of the environment.
+	Services []string
+
+	// Fields holds the names of the info fields to send, such as
+	// "Status" or "Life". The field identifying the entity is always
+	// sent. If it is empty, all fields are sent.
+	Fields []string
+}
+
+// Validate returns an error if the filter names an unknown entity
+// kind, or a field that the info of none of the selected kinds has.
+func (f Filter) Validate() error {
+	for _, kind := range f.Kinds {
+		if _, ok := idFields[kind]; !ok {
+			return fmt.Errorf("unknown entity kind %q", kind)
+		}
+	}
+	kinds := f.Kinds
+	if len(kinds) == 0 {
+		for kind := range infoTypes {
+			kinds = append(kinds, kind)
+		}
+	}
+	for _, field := range f.Fields {
+		if !hasField(kinds, field) {
+			return fmt.Errorf("unknown field %q", field)
+		}
+	}
+	return nil
+}
+
+// hasField reports whether the info of any of the given entity kinds
+// has the named field.
+func hasField(kinds []string, field string) bool {
+	for _, kind := range kinds {
+		if _, ok := infoTypes[kind].FieldByName(field); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply returns the given deltas that are selected by the filter,
+// holding only the selected fields of their entities.
+func (f Filter) Apply(deltas []Delta) []Delta {
+	if len(f.Kinds) == 0 && len(f.Services) == 0 && len(f.Fields) == 0 {
+		return deltas
+	}
+	selected := make([]Delta, 0, len(deltas))
+	for _, delta := range deltas {
+		if !f.Match(delta.Entity) {
+			continue
+		}
+		selected = append(selected, Delta{
+			Removed: delta.Removed,
+			Entity:  f.Project(delta.Entity),
+		})
+	}
+	return selected
+}
+
+// Match reports whether the given entity is selected by the filter.
+func (f Filter) Match(info EntityInfo) bool {
+	if len(f.Kinds) > 0 && !contains(f.Kinds, info.EntityId().Kind) {
+		return false
+	}
+	if len(f.Services) == 0 {
+		return true
+	}
+	services, ok := entityServices(info)
+	if !ok {
+		return true
+	}
+	for _, service := range services {
+		if contains(f.Services, service) {
+			return true
+		}
+	}
+	return false
+}
+
+// Project returns a copy of the given entity holding only the fields
+// selected by the filter.
+func (f Filter) Project(info EntityInfo) EntityInfo {
+	if len(f.Fields) == 0 {
+		return info
+	}
+	idField := idFields[info.EntityId().Kind]
+	v := reflect.ValueOf(info).Elem()
+	projected := reflect.New(v.Type())
+	for i := 0; i < v.NumField(); i++ {
+		name := v.Type().Field(i).Name
+		if name == idField || contains(f.Fields, name) {
+			projected.Elem().Field(i).Set(v.Field(i))
+		}
+	}
+	return projected.Interface().(EntityInfo)
+}
+
+// entityServices returns the names of the services the given entity
+// belongs to. It returns false if the entity does not belong to
+// services.
+func entityServices(info EntityInfo) ([]string, bool) {
+	switch info := info.(type) {
+	case *ServiceInfo:
+		return []string{info.Name}, true
+	case *UnitInfo:
+		return []string{info.Service}, true
+	case *RelationInfo:
+		services := make([]string, len(info.Endpoints))
+		for i, ep := range info.Endpoints {
+			services[i] = ep.ServiceName
+		}
+		return services, true
+	case *ActionInfo:
+		return unitService(info.Receiver)
+	case *AnnotationInfo:
+		tag, err := names.ParseTag(info.Tag)
+		if err != nil {
+			return nil, false
+		}
+		switch tag := tag.(type) {
+		case names.ServiceTag:
+			return []string{tag.Id()}, true
+		case names.UnitTag:
+			return unitService(tag.Id())
+		}
+	}
+	return nil, false
+}
+
+// unitService returns the name of the named unit's service. It
+// returns false if the name is not a unit name.
+func unitService(unitName string) ([]string, bool) {
+	service, err := names.UnitService(unitName)
+	if err != nil {
+		return nil, false
+	}
+	return []string{service}, true
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
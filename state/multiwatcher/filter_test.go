@@ -0,0 +1,108 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package multiwatcher_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state/multiwatcher"
+)
+
+type filterSuite struct{}
+
+var _ = gc.Suite(&filterSuite{})
+
+var filterDeltas = []multiwatcher.Delta{{
+	Entity: &multiwatcher.MachineInfo{Id: "0", Series: "trusty"},
+}, {
+	Entity: &multiwatcher.ServiceInfo{Name: "wordpress", Life: "alive", Exposed: true},
+}, {
+	Entity: &multiwatcher.ServiceInfo{Name: "mysql", Life: "alive"},
+}, {
+	Entity: &multiwatcher.UnitInfo{Name: "wordpress/0", Service: "wordpress", MachineId: "0"},
+}, {
+	Removed: true,
+	Entity:  &multiwatcher.UnitInfo{Name: "mysql/0", Service: "mysql"},
+}, {
+	Entity: &multiwatcher.RelationInfo{Key: "wordpress:db mysql:server", Endpoints: []multiwatcher.Endpoint{
+		{ServiceName: "wordpress"}, {ServiceName: "mysql"},
+	}},
+}, {
+	Entity: &multiwatcher.AnnotationInfo{Tag: "unit-mysql-0", Annotations: map[string]string{"a": "b"}},
+}, {
+	Entity: &multiwatcher.ActionInfo{Id: "1", Receiver: "wordpress/0", Name: "backup"},
+}, {
+	Entity: &multiwatcher.BlockInfo{Id: "1", Type: multiwatcher.BlockChange},
+}}
+
+var filterTests = []struct {
+	about  string
+	filter multiwatcher.Filter
+	expect []multiwatcher.Delta
+}{{
+	about:  "the zero filter selects everything",
+	expect: filterDeltas,
+}, {
+	about:  "filter by kind",
+	filter: multiwatcher.Filter{Kinds: []string{"service", "block"}},
+	expect: []multiwatcher.Delta{filterDeltas[1], filterDeltas[2], filterDeltas[8]},
+}, {
+	about:  "filter by service, which does not restrict machines and blocks",
+	filter: multiwatcher.Filter{Services: []string{"mysql"}},
+	expect: []multiwatcher.Delta{
+		filterDeltas[0], filterDeltas[2], filterDeltas[4], filterDeltas[5], filterDeltas[6], filterDeltas[8],
+	},
+}, {
+	about: "filter by kind and service",
+	filter: multiwatcher.Filter{
+		Kinds:    []string{"unit", "action"},
+		Services: []string{"wordpress"},
+	},
+	expect: []multiwatcher.Delta{filterDeltas[3], filterDeltas[7]},
+}, {
+	about: "project fields",
+	filter: multiwatcher.Filter{
+		Kinds:  []string{"service", "unit"},
+		Fields: []string{"Life", "Service"},
+	},
+	expect: []multiwatcher.Delta{{
+		Entity: &multiwatcher.ServiceInfo{Name: "wordpress", Life: "alive"},
+	}, {
+		Entity: &multiwatcher.ServiceInfo{Name: "mysql", Life: "alive"},
+	}, {
+		Entity: &multiwatcher.UnitInfo{Name: "wordpress/0", Service: "wordpress"},
+	}, {
+		Removed: true,
+		Entity:  &multiwatcher.UnitInfo{Name: "mysql/0", Service: "mysql"},
+	}},
+}}
+
+func (*filterSuite) TestApply(c *gc.C) {
+	for i, test := range filterTests {
+		c.Logf("test %d: %s", i, test.about)
+		c.Check(test.filter.Apply(filterDeltas), jc.DeepEquals, test.expect)
+	}
+}
+
+func (*filterSuite) TestProjectDoesNotChangeEntity(c *gc.C) {
+	info := &multiwatcher.ServiceInfo{Name: "wordpress", Exposed: true}
+	filter := multiwatcher.Filter{Fields: []string{"Life"}}
+	c.Assert(filter.Project(info), jc.DeepEquals, &multiwatcher.ServiceInfo{Name: "wordpress"})
+	c.Assert(info.Exposed, jc.IsTrue)
+}
+
+func (*filterSuite) TestValidate(c *gc.C) {
+	err := multiwatcher.Filter{Kinds: []string{"service", "unit"}}.Validate()
+	c.Assert(err, jc.ErrorIsNil)
+	err = multiwatcher.Filter{Kinds: []string{"service", "units"}}.Validate()
+	c.Assert(err, gc.ErrorMatches, `unknown entity kind "units"`)
+
+	err = multiwatcher.Filter{Fields: []string{"Status", "Exposed"}}.Validate()
+	c.Assert(err, jc.ErrorIsNil)
+	err = multiwatcher.Filter{Fields: []string{"Exposd"}}.Validate()
+	c.Assert(err, gc.ErrorMatches, `unknown field "Exposd"`)
+	err = multiwatcher.Filter{Kinds: []string{"unit"}, Fields: []string{"Exposed"}}.Validate()
+	c.Assert(err, gc.ErrorMatches, `unknown field "Exposed"`)
+}
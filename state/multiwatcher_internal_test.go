@@ -603,6 +603,40 @@ func (*storeManagerSuite) TestRespondMultiple(c *gc.C) {
 	c.Assert(req1.changes, gc.DeepEquals, deltas)
 }
 
+func (*storeManagerSuite) TestRespondFiltered(c *gc.C) {
+	sm := newStoreManager(newTestBacking(nil))
+	sm.all.Update(&multiwatcher.MachineInfo{Id: "0"})
+
+	// The watcher is not interested in machines, so its request
+	// is not replied to, although it has seen the change.
+	w := &Multiwatcher{all: sm, filter: multiwatcher.Filter{
+		Kinds:  []string{"service"},
+		Fields: []string{"Exposed"},
+	}}
+	req := &request{
+		w:     w,
+		reply: make(chan bool, 1),
+	}
+	sm.handle(req)
+	sm.respond()
+	assertNotReplied(c, req)
+	c.Assert(w.revno, gc.Equals, sm.all.latestRevno)
+
+	// A change it is interested in is replied to, holding
+	// only the selected fields.
+	sm.all.Update(&multiwatcher.ServiceInfo{
+		Name:     "wordpress",
+		Exposed:  true,
+		CharmURL: "local:quantal/wordpress-3",
+	})
+	sm.respond()
+	assertReplied(c, true, req)
+	c.Assert(req.changes, gc.DeepEquals, []multiwatcher.Delta{{
+		Entity: &multiwatcher.ServiceInfo{Name: "wordpress", Exposed: true},
+	}})
+	assertWaitingRequests(c, sm, nil)
+}
+
 func (*storeManagerSuite) TestRunStop(c *gc.C) {
 	sm := newStoreManager(newTestBacking(nil))
 	w := &Multiwatcher{all: sm}
@@ -0,0 +1,376 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// PermissionRoles maps the names of the built-in roles to the API
+// operations they allow, as "Facade" or "Facade.Method"; "*" allows
+// all operations. Any operation not named by a role can also be
+// granted on its own.
+var PermissionRoles = map[string][]string{
+	"admin": {"*"},
+	"read": {
+		"Action.Actions",
+		"Action.FindActionTagsByPrefix",
+		"Action.ListAll",
+		"Action.ListCompleted",
+		"Action.ListPending",
+		"Action.ListRunning",
+		"Action.ServicesCharmActions",
+		"Client.APIHostPorts",
+		"Client.AgentVersion",
+		"Client.CharmInfo",
+		"Client.EnvironmentGet",
+		"Client.EnvironmentInfo",
+		"Client.FullStatus",
+		"Client.GetAnnotations",
+		"Client.GetEnvironmentConstraints",
+		"Client.GetServiceConstraints",
+		"Client.HookStats",
+		"Client.MachinePools",
+		"Client.PrivateAddress",
+		"Client.PublicAddress",
+		"Client.ServiceCharmRelations",
+		"Client.ServiceGet",
+		"Client.ServiceGetCharmURL",
+		"Client.Status",
+		"Client.UnitStatusHistory",
+		"Client.WatchAll",
+		"Storage.List",
+		"Storage.ListPools",
+		"Storage.ListVolumes",
+		"Storage.Show",
+	},
+	"actions": {"Action"},
+	"storage": {"Storage"},
+	"operator": {
+		"Client.AddServiceUnits",
+		"Client.AddServiceUnitsWithPlacement",
+		"Client.DestroyServiceUnits",
+		"Client.Resolved",
+		"Client.ServiceExpose",
+		"Client.ServiceSet",
+		"Client.ServiceSetYAML",
+		"Client.ServiceUnexpose",
+		"Client.ServiceUnset",
+		"Client.ServiceUpdate",
+		"Client.SetServiceConstraints",
+	},
+}
+
+// Permission grants a user, or the members of a group, the API
+// operations of a role in an environment, optionally restricted to the
+// entities of a single service.
+type Permission struct {
+	doc permissionDoc
+}
+
+type permissionDoc struct {
+	DocID     string `bson:"_id"`
+	EnvUUID   string `bson:"env-uuid"`
+	Subject   string `bson:"subject"`
+	Operation string `bson:"operation"`
+	Service   string `bson:"service,omitempty"`
+}
+
+type restrictedSubjectDoc struct {
+	DocID   string `bson:"_id"`
+	EnvUUID string `bson:"env-uuid"`
+	Subject string `bson:"subject"`
+}
+
+// UserPermissionSubject returns the subject of the permissions granted
+// to the given user.
+func UserPermissionSubject(user names.UserTag) string {
+	return "user:" + strings.ToLower(user.Username())
+}
+
+// GroupPermissionSubject returns the subject of the permissions granted
+// to the members of the given group.
+func GroupPermissionSubject(group string) string {
+	return "group:" + group
+}
+
+// IsValidPermissionGroup reports whether the given name is a valid
+// group name. Group names may come from external identity providers,
+// so few restrictions apply.
+func IsValidPermissionGroup(name string) bool {
+	return name != "" && strings.TrimSpace(name) == name
+}
+
+func isValidPermissionSubject(subject string) bool {
+	switch {
+	case strings.HasPrefix(subject, "user:"):
+		return names.IsValidUser(strings.TrimPrefix(subject, "user:"))
+	case strings.HasPrefix(subject, "group:"):
+		return IsValidPermissionGroup(strings.TrimPrefix(subject, "group:"))
+	}
+	return false
+}
+
+// isValidPermissionOperation reports whether the operation is the name
+// of a role, or of a facade or facade method.
+func isValidPermissionOperation(operation string) bool {
+	if _, ok := PermissionRoles[operation]; ok {
+		return true
+	}
+	return validTokenScope.MatchString(operation)
+}
+
+// permissionID returns the local id of the permission document.
+func permissionID(subject, operation, service string) string {
+	return subject + "#" + operation + "#" + service
+}
+
+// Subject returns the subject the permission is granted to; see
+// UserPermissionSubject and GroupPermissionSubject.
+func (p *Permission) Subject() string {
+	return p.doc.Subject
+}
+
+// Operation returns the role, or the facade or facade method, the
+// permission grants.
+func (p *Permission) Operation() string {
+	return p.doc.Operation
+}
+
+// Service returns the name of the service whose entities the
+// permission is restricted to, or "" if it is not restricted.
+func (p *Permission) Service() string {
+	return p.doc.Service
+}
+
+// Allows reports whether the permission's operation includes calls to
+// the given facade method.
+func (p *Permission) Allows(facade, method string) bool {
+	operations, ok := PermissionRoles[p.doc.Operation]
+	if !ok {
+		operations = []string{p.doc.Operation}
+	}
+	for _, operation := range operations {
+		if operation == "*" || operation == facade || operation == facade+"."+method {
+			return true
+		}
+	}
+	return false
+}
+
+// GrantPermission grants the given subject the operations of a role, or
+// a single facade or facade method, in the environment. If service is
+// not empty, the permission only applies to calls on the entities of
+// that service. From then on, the subject is restricted to the
+// operations granted: revoking all its permissions leaves it with
+// none, rather than restoring its unrestricted access.
+func (st *State) GrantPermission(subject, operation, service string) (*Permission, error) {
+	if !isValidPermissionSubject(subject) {
+		return nil, errors.NotValidf("permission subject %q", subject)
+	}
+	if !isValidPermissionOperation(operation) {
+		return nil, errors.NotValidf("operation %q", operation)
+	}
+	if service != "" && !names.IsValidService(service) {
+		return nil, errors.NotValidf("service name %q", service)
+	}
+	p := &Permission{
+		doc: permissionDoc{
+			DocID:     st.docID(permissionID(subject, operation, service)),
+			EnvUUID:   st.EnvironUUID(),
+			Subject:   subject,
+			Operation: operation,
+			Service:   service,
+		},
+	}
+	ops := []txn.Op{{
+		C:      permissionsC,
+		Id:     p.doc.DocID,
+		Assert: txn.DocMissing,
+		Insert: &p.doc,
+	}, {
+		// The insert is ignored if the subject is already restricted.
+		C:  restrictedSubjectsC,
+		Id: st.docID(subject),
+		Insert: &restrictedSubjectDoc{
+			DocID:   st.docID(subject),
+			EnvUUID: st.EnvironUUID(),
+			Subject: subject,
+		},
+	}}
+	if err := st.runTransaction(ops); err == txn.ErrAborted {
+		return nil, errors.AlreadyExistsf("permission %s", p)
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot grant permission %s", p)
+	}
+	return p, nil
+}
+
+// RevokePermission revokes a permission granted by GrantPermission.
+func (st *State) RevokePermission(subject, operation, service string) error {
+	p := &Permission{
+		doc: permissionDoc{
+			Subject:   subject,
+			Operation: operation,
+			Service:   service,
+		},
+	}
+	ops := []txn.Op{{
+		C:      permissionsC,
+		Id:     st.docID(permissionID(subject, operation, service)),
+		Assert: txn.DocExists,
+		Remove: true,
+	}}
+	if err := st.runTransaction(ops); err == txn.ErrAborted {
+		return errors.NotFoundf("permission %s", p)
+	} else if err != nil {
+		return errors.Annotatef(err, "cannot revoke permission %s", p)
+	}
+	return nil
+}
+
+// String returns a description of the permission.
+func (p *Permission) String() string {
+	s := p.doc.Operation + " for " + p.doc.Subject
+	if p.doc.Service != "" {
+		s += " on service " + p.doc.Service
+	}
+	return s
+}
+
+// Permissions returns the permissions granted in the environment to
+// any of the given subjects, or to all subjects if none are given.
+func (st *State) Permissions(subjects ...string) ([]*Permission, error) {
+	permissions, closer := st.getCollection(permissionsC)
+	defer closer()
+
+	var query bson.D
+	if len(subjects) > 0 {
+		query = bson.D{{"subject", bson.D{{"$in", subjects}}}}
+	}
+	var docs []permissionDoc
+	if err := permissions.Find(query).Sort("_id").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get permissions")
+	}
+	result := make([]*Permission, len(docs))
+	for i, doc := range docs {
+		result[i] = &Permission{doc: doc}
+	}
+	return result, nil
+}
+
+// UserPermissions holds the permissions of a user in an environment.
+type UserPermissions struct {
+	restricted bool
+	grants     []*Permission
+}
+
+// UserPermissions returns the permissions granted in the environment
+// to the given user, directly or through the user's groups. Users
+// never granted any permissions keep the unrestricted access
+// environment users have always had; once the user or one of the
+// user's groups has been granted a permission, the user is restricted
+// to the operations currently granted, if any. The environment owner
+// is never restricted.
+func (st *State) UserPermissions(user *User) (*UserPermissions, error) {
+	env, err := st.Environment()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if strings.EqualFold(env.Owner().Username(), user.UserTag().Username()) {
+		return &UserPermissions{}, nil
+	}
+	subjects := []string{UserPermissionSubject(user.UserTag())}
+	for _, group := range user.Groups() {
+		subjects = append(subjects, GroupPermissionSubject(group))
+	}
+	restrictedSubjects, closer := st.getCollection(restrictedSubjectsC)
+	defer closer()
+	restricted, err := restrictedSubjects.Find(bson.D{{"subject", bson.D{{"$in", subjects}}}}).Count()
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot get restricted subjects")
+	}
+	grants, err := st.Permissions(subjects...)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &UserPermissions{
+		restricted: restricted > 0 || len(grants) > 0,
+		grants:     grants,
+	}, nil
+}
+
+// Restricted reports whether the user is restricted to the operations
+// of the permissions granted.
+func (p *UserPermissions) Restricted() bool {
+	return p.restricted
+}
+
+// Allows reports whether the user may call the given facade method on
+// the entities of the given services. The services are empty for calls
+// that identify no entities of services, which only permissions not
+// restricted to a service allow.
+func (p *UserPermissions) Allows(facade, method string, services []string) bool {
+	if !p.restricted {
+		return true
+	}
+	allowed := make(map[string]bool)
+	for _, grant := range p.grants {
+		if !grant.Allows(facade, method) {
+			continue
+		}
+		if grant.Service() == "" {
+			return true
+		}
+		allowed[grant.Service()] = true
+	}
+	if len(services) == 0 {
+		return false
+	}
+	for _, service := range services {
+		if !allowed[service] {
+			return false
+		}
+	}
+	return true
+}
+
+// AllowsAll reports whether the user may make any call on any entity:
+// the user is not restricted, or has been granted a role allowing all
+// operations, such as "admin", on the whole environment.
+func (p *UserPermissions) AllowsAll() bool {
+	if !p.restricted {
+		return true
+	}
+	for _, grant := range p.grants {
+		if grant.Service() != "" {
+			continue
+		}
+		for _, operation := range PermissionRoles[grant.Operation()] {
+			if operation == "*" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// AllowsForSomeService reports whether the user may call the given
+// facade method on the entities of at least one service.
+func (p *UserPermissions) AllowsForSomeService(facade, method string) bool {
+	if !p.restricted {
+		return true
+	}
+	for _, grant := range p.grants {
+		if grant.Allows(facade, method) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,174 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
+)
+
+type PermissionSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&PermissionSuite{})
+
+func (s *PermissionSuite) TestGrantPermission(c *gc.C) {
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	subject := state.UserPermissionSubject(bob.UserTag())
+	c.Assert(subject, gc.Equals, "user:bob@local")
+
+	p, err := s.State.GrantPermission(subject, "actions", "wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(p.Subject(), gc.Equals, subject)
+	c.Assert(p.Operation(), gc.Equals, "actions")
+	c.Assert(p.Service(), gc.Equals, "wordpress")
+
+	_, err = s.State.GrantPermission(subject, "actions", "wordpress")
+	c.Assert(err, gc.ErrorMatches, `permission actions for user:bob@local on service wordpress already exists`)
+	c.Assert(errors.IsAlreadyExists(err), jc.IsTrue)
+
+	_, err = s.State.GrantPermission(subject, "Client.FullStatus", "")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.GrantPermission(state.GroupPermissionSubject("ops"), "storage", "")
+	c.Assert(err, jc.ErrorIsNil)
+
+	permissions, err := s.State.Permissions(subject)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(permissions, gc.HasLen, 2)
+	c.Assert(permissions[0].String(), gc.Equals, "Client.FullStatus for user:bob@local")
+	c.Assert(permissions[1].String(), gc.Equals, "actions for user:bob@local on service wordpress")
+
+	permissions, err = s.State.Permissions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(permissions, gc.HasLen, 3)
+}
+
+func (s *PermissionSuite) TestGrantPermissionInvalid(c *gc.C) {
+	_, err := s.State.GrantPermission("bob", "actions", "")
+	c.Assert(err, gc.ErrorMatches, `permission subject "bob" not valid`)
+	_, err = s.State.GrantPermission("user:bob", "Client.Full.Status", "")
+	c.Assert(err, gc.ErrorMatches, `operation "Client.Full.Status" not valid`)
+	_, err = s.State.GrantPermission("user:bob", "actions", "Wordpress")
+	c.Assert(err, gc.ErrorMatches, `service name "Wordpress" not valid`)
+}
+
+func (s *PermissionSuite) TestRevokePermission(c *gc.C) {
+	_, err := s.State.GrantPermission("user:bob@local", "actions", "wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.RevokePermission("user:bob@local", "actions", "")
+	c.Assert(err, gc.ErrorMatches, `permission actions for user:bob@local not found`)
+	c.Assert(errors.IsNotFound(err), jc.IsTrue)
+
+	err = s.State.RevokePermission("user:bob@local", "actions", "wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	permissions, err := s.State.Permissions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(permissions, gc.HasLen, 0)
+}
+
+func (s *PermissionSuite) TestUserPermissionsUnrestricted(c *gc.C) {
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	perms, err := s.State.UserPermissions(bob)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(perms.Restricted(), jc.IsFalse)
+	c.Assert(perms.Allows("Client", "ServiceDestroy", nil), jc.IsTrue)
+
+	// The environment owner is never restricted.
+	owner, err := s.State.User(s.Owner)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.GrantPermission(state.UserPermissionSubject(s.Owner), "read", "")
+	c.Assert(err, jc.ErrorIsNil)
+	perms, err = s.State.UserPermissions(owner)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(perms.Restricted(), jc.IsFalse)
+}
+
+func (s *PermissionSuite) TestUserPermissions(c *gc.C) {
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	err := bob.SetGroups([]string{"ops"})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.GrantPermission(state.UserPermissionSubject(bob.UserTag()), "actions", "wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.GrantPermission(state.GroupPermissionSubject("ops"), "read", "")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.GrantPermission(state.GroupPermissionSubject("dba"), "admin", "")
+	c.Assert(err, jc.ErrorIsNil)
+
+	perms, err := s.State.UserPermissions(bob)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(perms.Restricted(), jc.IsTrue)
+	for i, test := range []struct {
+		facade   string
+		method   string
+		services []string
+		allowed  bool
+	}{
+		{"Client", "FullStatus", nil, true},
+		{"Client", "FullStatus", []string{"mysql"}, true},
+		{"Client", "ServiceDestroy", []string{"wordpress"}, false},
+		{"Action", "Enqueue", []string{"wordpress"}, true},
+		{"Action", "Enqueue", []string{"wordpress", "mysql"}, false},
+		{"Action", "Enqueue", nil, false},
+		{"Action", "Cancel", []string{"wordpress"}, true},
+		{"Storage", "CreatePool", nil, false},
+	} {
+		c.Logf("test %d: %s.%s %v", i, test.facade, test.method, test.services)
+		c.Check(perms.Allows(test.facade, test.method, test.services), gc.Equals, test.allowed)
+	}
+	c.Assert(perms.AllowsForSomeService("Action", "Enqueue"), jc.IsTrue)
+	c.Assert(perms.AllowsForSomeService("Client", "ServiceDestroy"), jc.IsFalse)
+	c.Assert(perms.AllowsAll(), jc.IsFalse)
+
+	err = bob.SetGroups([]string{"ops", "dba"})
+	c.Assert(err, jc.ErrorIsNil)
+	perms, err = s.State.UserPermissions(bob)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(perms.AllowsAll(), jc.IsTrue)
+}
+
+func (s *PermissionSuite) TestUserPermissionsAfterRevokingAll(c *gc.C) {
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	alice := s.Factory.MakeUser(c, &factory.UserParams{Name: "alice"})
+	err := alice.SetGroups([]string{"ops"})
+	c.Assert(err, jc.ErrorIsNil)
+	for i, test := range []struct {
+		user    *state.User
+		subject string
+	}{
+		{bob, state.UserPermissionSubject(bob.UserTag())},
+		{alice, state.GroupPermissionSubject("ops")},
+	} {
+		c.Logf("test %d: %s", i, test.subject)
+		_, err = s.State.GrantPermission(test.subject, "read", "")
+		c.Assert(err, jc.ErrorIsNil)
+		err = s.State.RevokePermission(test.subject, "read", "")
+		c.Assert(err, jc.ErrorIsNil)
+
+		perms, err := s.State.UserPermissions(test.user)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(perms.Restricted(), jc.IsTrue)
+		c.Check(perms.Allows("Client", "FullStatus", nil), jc.IsFalse)
+		c.Check(perms.Allows("Client", "ServiceDestroy", nil), jc.IsFalse)
+	}
+}
+
+func (s *PermissionSuite) TestSetGroups(c *gc.C) {
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	c.Assert(bob.Groups(), gc.HasLen, 0)
+	err := bob.SetGroups([]string{"ops", "dba", "ops"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(bob.Groups(), jc.DeepEquals, []string{"dba", "ops"})
+	err = bob.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(bob.Groups(), jc.DeepEquals, []string{"dba", "ops"})
+
+	err = bob.SetGroups([]string{" ops"})
+	c.Assert(err, gc.ErrorMatches, `group name " ops" not valid`)
+}
@@ -0,0 +1,448 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	stderrors "errors"
+	"fmt"
+	"sort"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// PlacementPolicy describes where the units of a service may be
+// placed relative to the units of other services. The zero value
+// places no restrictions on the service's units.
+type PlacementPolicy struct {
+	// AntiAffinity holds the names of services whose units must
+	// never share a machine with the service's units.
+	AntiAffinity []string
+
+	// Affinity holds the names of services that must have a unit
+	// on every machine hosting the service's units.
+	Affinity []string
+
+	// MaxUnitsPerZone limits the number of the service's units in
+	// any one availability zone. If it is zero, there is no limit.
+	MaxUnitsPerZone int
+}
+
+// IsZero reports whether the policy places no restrictions on the
+// service's units.
+func (p PlacementPolicy) IsZero() bool {
+	return len(p.AntiAffinity) == 0 && len(p.Affinity) == 0 && p.MaxUnitsPerZone == 0
+}
+
+// validate returns an error if the policy is not valid for the named
+// service.
+func (p PlacementPolicy) validate(serviceName string) error {
+	if p.MaxUnitsPerZone < 0 {
+		return errors.NotValidf("negative maximum units per zone")
+	}
+	antiAffinity := make(map[string]bool)
+	for _, name := range p.AntiAffinity {
+		if !names.IsValidService(name) {
+			return errors.NotValidf("service name %q", name)
+		}
+		if name == serviceName {
+			return errors.NotValidf("anti-affinity with service itself")
+		}
+		antiAffinity[name] = true
+	}
+	for _, name := range p.Affinity {
+		if !names.IsValidService(name) {
+			return errors.NotValidf("service name %q", name)
+		}
+		if name == serviceName {
+			return errors.NotValidf("affinity with service itself")
+		}
+		if antiAffinity[name] {
+			return errors.NotValidf("both affinity and anti-affinity with service %q", name)
+		}
+	}
+	return nil
+}
+
+// placementPolicyDoc represents a PlacementPolicy in MongoDB.
+type placementPolicyDoc struct {
+	AntiAffinity    []string `bson:"antiaffinity,omitempty"`
+	Affinity        []string `bson:"affinity,omitempty"`
+	MaxUnitsPerZone int      `bson:"maxunitsperzone,omitempty"`
+}
+
+// PlacementPolicy returns the policy restricting where the service's
+// units may be placed.
+func (s *Service) PlacementPolicy() PlacementPolicy {
+	doc := s.doc.PlacementPolicy
+	if doc == nil {
+		return PlacementPolicy{}
+	}
+	return PlacementPolicy{
+		AntiAffinity:    doc.AntiAffinity,
+		Affinity:        doc.Affinity,
+		MaxUnitsPerZone: doc.MaxUnitsPerZone,
+	}
+}
+
+// SetPlacementPolicy changes the policy restricting where the
+// service's units may be placed. The policy applies to units assigned
+// from then on; units already violating it are reported by
+// PlacementViolations. Setting the zero policy removes all
+// restrictions.
+func (s *Service) SetPlacementPolicy(policy PlacementPolicy) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set placement policy for service %q", s.doc.Name)
+	if err := policy.validate(s.doc.Name); err != nil {
+		return err
+	}
+	var doc *placementPolicyDoc
+	var update bson.D
+	if policy.IsZero() {
+		update = bson.D{{"$unset", bson.D{{"placementpolicy", nil}}}}
+	} else {
+		doc = &placementPolicyDoc{
+			AntiAffinity:    policy.AntiAffinity,
+			Affinity:        policy.Affinity,
+			MaxUnitsPerZone: policy.MaxUnitsPerZone,
+		}
+		update = bson.D{{"$set", bson.D{{"placementpolicy", doc}}}}
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			alive, err := isAlive(s.st, servicesC, s.doc.DocID)
+			if err != nil {
+				return nil, errors.Trace(err)
+			} else if !alive {
+				return nil, errNotAlive
+			}
+		}
+		return []txn.Op{{
+			C:      servicesC,
+			Id:     s.doc.DocID,
+			Assert: isAliveDoc,
+			Update: update,
+		}}, nil
+	}
+	if err := s.st.run(buildTxn); err != nil {
+		if err == errNotAlive {
+			return errors.New("service " + err.Error())
+		}
+		return errors.Trace(err)
+	}
+	s.doc.PlacementPolicy = doc
+	return nil
+}
+
+// PlacementViolations returns a description of each way in which the
+// current placement of the service's units violates its placement
+// policy.
+func (s *Service) PlacementViolations() ([]string, error) {
+	policy := s.PlacementPolicy()
+	if policy.IsZero() || !s.IsPrincipal() {
+		return nil, nil
+	}
+	units, err := s.AllUnits()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var violations []string
+	for _, unit := range units {
+		machineId, err := unit.AssignedMachineId()
+		if errors.IsNotAssigned(err) {
+			continue
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		m, err := s.st.Machine(machineId)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		violation := machinePlacementViolation(s.doc.Name, policy, unit.Name(), m)
+		if violation != "" {
+			violations = append(violations, fmt.Sprintf("unit %s: %s", unit.Name(), violation))
+		}
+	}
+	if policy.MaxUnitsPerZone > 0 {
+		zoneUnits, err := serviceZoneUnits(s.st, s.doc.Name)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for zone, count := range zoneUnits {
+			if count > policy.MaxUnitsPerZone {
+				violations = append(violations, fmt.Sprintf(
+					"%d units in zone %q exceed maximum of %d",
+					count, zone, policy.MaxUnitsPerZone,
+				))
+			}
+		}
+	}
+	sort.Strings(violations)
+	return violations, nil
+}
+
+// placementViolationError is returned when assigning a unit to a
+// machine would violate a placement policy.
+type placementViolationError struct {
+	unit    string
+	machine string
+	reason  string
+}
+
+func (e *placementViolationError) Error() string {
+	machine := "a new machine"
+	if e.machine != "" {
+		machine = "machine " + e.machine
+	}
+	return fmt.Sprintf("placing unit %s on %s would violate placement policy: %s", e.unit, machine, e.reason)
+}
+
+// isPlacementViolation reports whether err was returned because of a
+// placement policy violation.
+func isPlacementViolation(err error) bool {
+	_, ok := errors.Cause(err).(*placementViolationError)
+	return ok
+}
+
+// checkPlacementPolicy returns a *placementViolationError if assigning
+// the unit to the given machine would violate the placement policy of
+// the unit's service, or the anti-affinity of a service whose units are
+// already on the machine. As the result depends on the units assigned
+// to the machine, the assignment must assert that they are unchanged.
+func (u *Unit) checkPlacementPolicy(m *Machine) error {
+	service, err := u.Service()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	policy := service.PlacementPolicy()
+	violation := func(reason string) error {
+		return &placementViolationError{unit: u.doc.Name, machine: m.Id(), reason: reason}
+	}
+	if reason := machinePlacementViolation(service.Name(), policy, u.doc.Name, m); reason != "" {
+		return violation(reason)
+	}
+	checked := make(map[string]bool)
+	for _, serviceName := range machineServices(m, u.doc.Name) {
+		if serviceName == service.Name() || checked[serviceName] {
+			continue
+		}
+		checked[serviceName] = true
+		other, err := u.st.Service(serviceName)
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+		for _, name := range other.PlacementPolicy().AntiAffinity {
+			if name == service.Name() {
+				return violation(fmt.Sprintf("service %q has anti-affinity with service %q", serviceName, name))
+			}
+		}
+	}
+	if policy.MaxUnitsPerZone > 0 {
+		zone, err := m.AvailabilityZone()
+		if errors.IsNotProvisioned(err) || zone == "" {
+			// The provisioner honours the limit when choosing
+			// the zone of the instance.
+			return nil
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+		zoneUnits, err := serviceZoneUnits(u.st, service.Name())
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if zoneUnits[zone] >= policy.MaxUnitsPerZone {
+			return violation(fmt.Sprintf(
+				"zone %q already has %d units of service %q",
+				zone, zoneUnits[zone], service.Name(),
+			))
+		}
+	}
+	return nil
+}
+
+// checkNewMachinePlacementPolicy returns a *placementViolationError if
+// assigning the unit to a new machine, which hosts no other units,
+// would violate the placement policy of the unit's service.
+func (u *Unit) checkNewMachinePlacementPolicy() error {
+	service, err := u.Service()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if reason := machinePlacementViolation(service.Name(), service.PlacementPolicy(), u.doc.Name, &Machine{}); reason != "" {
+		return &placementViolationError{unit: u.doc.Name, reason: reason}
+	}
+	return nil
+}
+
+var noAffineMachines = stderrors.New("no machines satisfy the affinity of the unit's service")
+
+// assignToAffineMachine assigns the unit to an existing machine hosting
+// units of the services its service has affinity with, if any such
+// machine satisfies the service's placement policy. It returns
+// noAffineMachines if there is none.
+func (u *Unit) assignToAffineMachine() (*Machine, error) {
+	service, err := u.Service()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	policy := service.PlacementPolicy()
+	if len(policy.Affinity) == 0 {
+		return nil, noAffineMachines
+	}
+	// Every suitable machine hosts a unit of the first service the
+	// unit's service has affinity with.
+	units, err := allUnits(u.st, policy.Affinity[0])
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	tried := make(map[string]bool)
+	for _, unit := range units {
+		machineId, err := unit.AssignedMachineId()
+		if errors.IsNotAssigned(err) {
+			continue
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if tried[machineId] {
+			continue
+		}
+		tried[machineId] = true
+		m, err := u.st.Machine(machineId)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		err = u.assignToMachine(m, false)
+		switch {
+		case err == nil:
+			return m, nil
+		case err == unitNotAliveErr, err == alreadyAssignedErr:
+			return nil, err
+		}
+		logger.Debugf("cannot assign unit %q to machine %s: %v", u, m, err)
+	}
+	return nil, noAffineMachines
+}
+
+// principalsUnchangedDoc returns an assertion that the principal units
+// assigned to a machine are exactly those given.
+func principalsUnchangedDoc(principals []string) bson.D {
+	if len(principals) == 0 {
+		return bson.D{{"principals.0", bson.D{{"$exists", false}}}}
+	}
+	return bson.D{{"principals", bson.D{
+		{"$size", len(principals)},
+		{"$all", principals},
+	}}}
+}
+
+// machinePlacementViolation returns a description of how the named
+// unit being on the given machine violates the affinity rules of the
+// policy of the named service, or the empty string if it does not.
+func machinePlacementViolation(serviceName string, policy PlacementPolicy, unitName string, m *Machine) string {
+	hosted := make(map[string]bool)
+	for _, name := range machineServices(m, unitName) {
+		hosted[name] = true
+	}
+	for _, name := range policy.AntiAffinity {
+		if hosted[name] {
+			return fmt.Sprintf("service %q has anti-affinity with service %q", serviceName, name)
+		}
+	}
+	for _, name := range policy.Affinity {
+		if !hosted[name] {
+			return fmt.Sprintf("service %q requires a unit of service %q on the same machine", serviceName, name)
+		}
+	}
+	return ""
+}
+
+// machineServices returns the names of the services of the principal
+// units assigned to the machine, ignoring the named unit.
+func machineServices(m *Machine, ignoreUnit string) []string {
+	var serviceNames []string
+	for _, unitName := range m.doc.Principals {
+		if unitName == ignoreUnit {
+			continue
+		}
+		if serviceName, err := names.UnitService(unitName); err == nil {
+			serviceNames = append(serviceNames, serviceName)
+		}
+	}
+	return serviceNames
+}
+
+// serviceZoneUnits returns the number of units of the named service in
+// each availability zone. Units on unprovisioned machines are not
+// counted.
+func serviceZoneUnits(st *State, serviceName string) (map[string]int, error) {
+	units, err := allUnits(st, serviceName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	zoneUnits := make(map[string]int)
+	for _, unit := range units {
+		machineId, err := unit.AssignedMachineId()
+		if errors.IsNotAssigned(err) {
+			continue
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		machine, err := st.Machine(machineId)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		zone, err := machine.AvailabilityZone()
+		if errors.IsNotProvisioned(err) {
+			continue
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if zone != "" {
+			zoneUnits[zone]++
+		}
+	}
+	return zoneUnits, nil
+}
+
+// PlacementExcludedZones returns the availability zones the machine
+// must not be provisioned in because one of the services with units
+// assigned to it already has its maximum number of units there.
+func (m *Machine) PlacementExcludedZones() ([]string, error) {
+	excluded := make(map[string]bool)
+	checked := make(map[string]bool)
+	for _, serviceName := range machineServices(m, "") {
+		if checked[serviceName] {
+			continue
+		}
+		checked[serviceName] = true
+		service, err := m.st.Service(serviceName)
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		maxUnits := service.PlacementPolicy().MaxUnitsPerZone
+		if maxUnits == 0 {
+			continue
+		}
+		zoneUnits, err := serviceZoneUnits(m.st, serviceName)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for zone, count := range zoneUnits {
+			if count >= maxUnits {
+				excluded[zone] = true
+			}
+		}
+	}
+	var zones []string
+	for zone := range excluded {
+		zones = append(zones, zone)
+	}
+	sort.Strings(zones)
+	return zones, nil
+}
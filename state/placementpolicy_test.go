@@ -0,0 +1,206 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/state"
+)
+
+type PlacementPolicySuite struct {
+	ConnSuite
+	mysql     *state.Service
+	wordpress *state.Service
+}
+
+var _ = gc.Suite(&PlacementPolicySuite{})
+
+func (s *PlacementPolicySuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.mysql = s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	s.wordpress = s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+}
+
+func (s *PlacementPolicySuite) addMachine(c *gc.C, zone string) *state.Machine {
+	m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	if zone != "" {
+		hwc := &instance.HardwareCharacteristics{AvailabilityZone: &zone}
+		err = m.SetProvisioned(instance.Id("inst-"+m.Id()), "fake_nonce", hwc)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	return m
+}
+
+func (s *PlacementPolicySuite) addUnit(c *gc.C, svc *state.Service, m *state.Machine) *state.Unit {
+	u, err := svc.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	if m != nil {
+		err = u.AssignToMachine(m)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	return u
+}
+
+func (s *PlacementPolicySuite) setPolicy(c *gc.C, svc *state.Service, policy state.PlacementPolicy) {
+	err := svc.SetPlacementPolicy(policy)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *PlacementPolicySuite) TestSetPlacementPolicy(c *gc.C) {
+	c.Assert(s.mysql.PlacementPolicy().IsZero(), jc.IsTrue)
+	policy := state.PlacementPolicy{
+		AntiAffinity:    []string{"wordpress"},
+		Affinity:        []string{"logging"},
+		MaxUnitsPerZone: 2,
+	}
+	s.setPolicy(c, s.mysql, policy)
+	c.Assert(s.mysql.PlacementPolicy(), jc.DeepEquals, policy)
+
+	service, err := s.State.Service(s.mysql.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(service.PlacementPolicy(), jc.DeepEquals, policy)
+
+	s.setPolicy(c, service, state.PlacementPolicy{})
+	err = s.mysql.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mysql.PlacementPolicy().IsZero(), jc.IsTrue)
+}
+
+func (s *PlacementPolicySuite) TestSetPlacementPolicyInvalid(c *gc.C) {
+	for i, test := range []struct {
+		policy state.PlacementPolicy
+		err    string
+	}{{
+		policy: state.PlacementPolicy{MaxUnitsPerZone: -1},
+		err:    "negative maximum units per zone not valid",
+	}, {
+		policy: state.PlacementPolicy{AntiAffinity: []string{"Wordpress"}},
+		err:    `service name "Wordpress" not valid`,
+	}, {
+		policy: state.PlacementPolicy{AntiAffinity: []string{"mysql"}},
+		err:    "anti-affinity with service itself not valid",
+	}, {
+		policy: state.PlacementPolicy{Affinity: []string{"mysql"}},
+		err:    "affinity with service itself not valid",
+	}, {
+		policy: state.PlacementPolicy{
+			AntiAffinity: []string{"wordpress"},
+			Affinity:     []string{"wordpress"},
+		},
+		err: `both affinity and anti-affinity with service "wordpress" not valid`,
+	}} {
+		c.Logf("test %d: %+v", i, test.policy)
+		err := s.mysql.SetPlacementPolicy(test.policy)
+		c.Check(err, gc.ErrorMatches, `cannot set placement policy for service "mysql": `+test.err)
+		c.Check(err, jc.Satisfies, errors.IsNotValid)
+	}
+}
+
+func (s *PlacementPolicySuite) TestAssignToMachineAntiAffinity(c *gc.C) {
+	s.setPolicy(c, s.wordpress, state.PlacementPolicy{AntiAffinity: []string{"mysql"}})
+	m := s.addMachine(c, "")
+	s.addUnit(c, s.mysql, m)
+
+	u, err := s.wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = u.AssignToMachine(m)
+	c.Assert(err, gc.ErrorMatches, `cannot assign unit "wordpress/0" to machine 0: `+
+		`placing unit wordpress/0 on machine 0 would violate placement policy: `+
+		`service "wordpress" has anti-affinity with service "mysql"`)
+
+	// The policy also prevents mysql units joining wordpress units.
+	other := s.addMachine(c, "")
+	err = u.AssignToMachine(other)
+	c.Assert(err, jc.ErrorIsNil)
+	u, err = s.mysql.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = u.AssignToMachine(other)
+	c.Assert(err, gc.ErrorMatches, `.*service "wordpress" has anti-affinity with service "mysql"`)
+}
+
+func (s *PlacementPolicySuite) TestAssignUnitAffinity(c *gc.C) {
+	s.addMachine(c, "")
+	m := s.addMachine(c, "")
+	s.addUnit(c, s.mysql, m)
+	s.setPolicy(c, s.wordpress, state.PlacementPolicy{Affinity: []string{"mysql"}})
+
+	u, err := s.wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AssignUnit(u, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+	machineId, err := u.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machineId, gc.Equals, m.Id())
+}
+
+func (s *PlacementPolicySuite) TestAssignUnitAffinityNewMachine(c *gc.C) {
+	s.setPolicy(c, s.wordpress, state.PlacementPolicy{Affinity: []string{"mysql"}})
+	u, err := s.wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	for _, policy := range []state.AssignmentPolicy{state.AssignNew, state.AssignClean} {
+		c.Logf("policy %q", policy)
+		err = s.State.AssignUnit(u, policy)
+		c.Check(err, gc.ErrorMatches, `cannot assign unit "wordpress/0" to machine: `+
+			`cannot assign unit "wordpress/0" to new machine.*: `+
+			`placing unit wordpress/0 on a new machine would violate placement policy: `+
+			`service "wordpress" requires a unit of service "mysql" on the same machine`)
+	}
+	_, err = u.AssignedMachineId()
+	c.Assert(err, jc.Satisfies, errors.IsNotAssigned)
+}
+
+func (s *PlacementPolicySuite) TestMaxUnitsPerZone(c *gc.C) {
+	s.setPolicy(c, s.mysql, state.PlacementPolicy{MaxUnitsPerZone: 1})
+	m0 := s.addMachine(c, "zone-a")
+	m1 := s.addMachine(c, "zone-a")
+	s.addUnit(c, s.mysql, m0)
+
+	u, err := s.mysql.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = u.AssignToMachine(m1)
+	c.Assert(err, gc.ErrorMatches, `.*zone "zone-a" already has 1 units of service "mysql"`)
+
+	// Unprovisioned machines hosting mysql units must avoid zone-a.
+	m2 := s.addMachine(c, "")
+	err = u.AssignToMachine(m2)
+	c.Assert(err, jc.ErrorIsNil)
+	err = m2.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	zones, err := m2.PlacementExcludedZones()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(zones, jc.DeepEquals, []string{"zone-a"})
+
+	zones, err = s.addMachine(c, "").PlacementExcludedZones()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(zones, gc.HasLen, 0)
+}
+
+func (s *PlacementPolicySuite) TestPlacementViolations(c *gc.C) {
+	m0 := s.addMachine(c, "zone-a")
+	m1 := s.addMachine(c, "zone-a")
+	s.addUnit(c, s.mysql, m0)
+	s.addUnit(c, s.wordpress, m0)
+	s.addUnit(c, s.wordpress, m1)
+	s.addUnit(c, s.wordpress, nil)
+
+	violations, err := s.wordpress.PlacementViolations()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(violations, gc.HasLen, 0)
+
+	s.setPolicy(c, s.wordpress, state.PlacementPolicy{
+		Affinity:        []string{"mysql"},
+		MaxUnitsPerZone: 1,
+	})
+	violations, err = s.wordpress.PlacementViolations()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(violations, jc.DeepEquals, []string{
+		`2 units in zone "zone-a" exceed maximum of 1`,
+		`unit wordpress/1: service "wordpress" requires a unit of service "mysql" on the same machine`,
+	})
+}
@@ -0,0 +1,224 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/version"
+)
+
+// currentRollingUpgradeId is the id of the document holding the
+// rolling upgrade in progress in an environment.
+const currentRollingUpgradeId = "current"
+
+// RollingUpgrade describes an agent upgrade that is applied to the
+// machines of an environment in batches. State server machines are
+// always upgraded first; other machines keep running the version the
+// environment was upgraded from until they are released to the upgrade.
+type RollingUpgrade struct {
+	// From holds the agent version the environment was upgraded from.
+	From version.Number
+
+	// To holds the agent version the environment is upgraded to.
+	To version.Number
+
+	// BatchSize holds the number of machines released to the upgrade
+	// at a time, once the previously released machines are healthy.
+	BatchSize int
+
+	// Machines holds the ids of the machines released to the upgrade.
+	Machines []string
+
+	// Paused is true when the upgrade has been paused, either by the
+	// user or because a released machine failed; Reason then holds
+	// why.
+	Paused bool
+	Reason string
+}
+
+// Released reports whether the machine with the given id has been
+// released to the upgrade.
+func (u RollingUpgrade) Released(machineId string) bool {
+	for _, id := range u.Machines {
+		if id == machineId {
+			return true
+		}
+	}
+	return false
+}
+
+// rollingUpgradeDoc represents a RollingUpgrade in MongoDB.
+type rollingUpgradeDoc struct {
+	DocID     string   `bson:"_id"`
+	EnvUUID   string   `bson:"env-uuid"`
+	From      string   `bson:"from"`
+	To        string   `bson:"to"`
+	BatchSize int      `bson:"batchsize"`
+	Machines  []string `bson:"machines"`
+	Paused    bool     `bson:"paused"`
+	Reason    string   `bson:"reason,omitempty"`
+}
+
+// rollingUpgradeOps returns the operations that start a rolling upgrade
+// from one agent version to another, replacing any rolling upgrade in
+// progress. If batchSize is not positive, the operations only remove
+// the rolling upgrade in progress.
+func (st *State) rollingUpgradeOps(from string, to version.Number, batchSize int) ([]txn.Op, error) {
+	id := st.docID(currentRollingUpgradeId)
+	if batchSize <= 0 {
+		return []txn.Op{{
+			C:      rollingUpgradesC,
+			Id:     id,
+			Remove: true,
+		}}, nil
+	}
+	doc := rollingUpgradeDoc{
+		DocID:     id,
+		EnvUUID:   st.EnvironUUID(),
+		From:      from,
+		To:        to.String(),
+		BatchSize: batchSize,
+		Machines:  []string{},
+	}
+	if _, err := st.rollingUpgradeDoc(); errors.IsNotFound(err) {
+		return []txn.Op{{
+			C:      rollingUpgradesC,
+			Id:     id,
+			Assert: txn.DocMissing,
+			Insert: doc,
+		}}, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return []txn.Op{{
+		C:      rollingUpgradesC,
+		Id:     id,
+		Assert: txn.DocExists,
+		Update: bson.D{{"$set", bson.D{
+			{"from", doc.From},
+			{"to", doc.To},
+			{"batchsize", doc.BatchSize},
+			{"machines", doc.Machines},
+			{"paused", false},
+			{"reason", ""},
+		}}},
+	}}, nil
+}
+
+// SetEnvironAgentVersionRolling changes the agent version for the
+// environment like SetEnvironAgentVersion, but upgrades the agents of
+// machines that are not state servers batchSize machines at a time.
+// The rolling upgrade worker releases each batch once the agents of
+// the state servers and of the previously released machines run the
+// new version.
+func (st *State) SetEnvironAgentVersionRolling(newVersion version.Number, batchSize int) error {
+	if batchSize < 1 {
+		return errors.NotValidf("batch size %d", batchSize)
+	}
+	return st.setEnvironAgentVersion(newVersion, batchSize)
+}
+
+func (st *State) rollingUpgradeDoc() (*rollingUpgradeDoc, error) {
+	rollingUpgrades, closer := st.getCollection(rollingUpgradesC)
+	defer closer()
+
+	var doc rollingUpgradeDoc
+	err := rollingUpgrades.FindId(currentRollingUpgradeId).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("rolling upgrade")
+	} else if err != nil {
+		return nil, errors.Annotate(err, "cannot get rolling upgrade")
+	}
+	return &doc, nil
+}
+
+// RollingUpgrade returns the rolling upgrade in progress in the
+// environment. It returns an error satisfying errors.IsNotFound if
+// there is none.
+func (st *State) RollingUpgrade() (RollingUpgrade, error) {
+	doc, err := st.rollingUpgradeDoc()
+	if err != nil {
+		return RollingUpgrade{}, err
+	}
+	from, err := version.Parse(doc.From)
+	if err != nil {
+		return RollingUpgrade{}, errors.Trace(err)
+	}
+	to, err := version.Parse(doc.To)
+	if err != nil {
+		return RollingUpgrade{}, errors.Trace(err)
+	}
+	return RollingUpgrade{
+		From:      from,
+		To:        to,
+		BatchSize: doc.BatchSize,
+		Machines:  doc.Machines,
+		Paused:    doc.Paused,
+		Reason:    doc.Reason,
+	}, nil
+}
+
+// updateRollingUpgrade applies the update to the rolling upgrade in
+// progress.
+func (st *State) updateRollingUpgrade(update bson.D) error {
+	ops := []txn.Op{{
+		C:      rollingUpgradesC,
+		Id:     st.docID(currentRollingUpgradeId),
+		Assert: txn.DocExists,
+		Update: update,
+	}}
+	if err := st.runTransaction(ops); err == txn.ErrAborted {
+		return errors.New("no rolling upgrade in progress")
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// ReleaseRollingUpgradeMachines releases the machines with the given
+// ids to the rolling upgrade in progress, so that their agents upgrade.
+func (st *State) ReleaseRollingUpgradeMachines(machineIds []string) error {
+	err := st.updateRollingUpgrade(bson.D{
+		{"$addToSet", bson.D{{"machines", bson.D{{"$each", machineIds}}}}},
+	})
+	return errors.Annotate(err, "cannot release machines to rolling upgrade")
+}
+
+// PauseRollingUpgrade stops further machines from being released to
+// the rolling upgrade in progress, for the given reason.
+func (st *State) PauseRollingUpgrade(reason string) error {
+	err := st.updateRollingUpgrade(bson.D{
+		{"$set", bson.D{{"paused", true}, {"reason", reason}}},
+	})
+	return errors.Annotate(err, "cannot pause rolling upgrade")
+}
+
+// ResumeRollingUpgrade resumes the paused rolling upgrade.
+func (st *State) ResumeRollingUpgrade() error {
+	err := st.updateRollingUpgrade(bson.D{
+		{"$set", bson.D{{"paused", false}, {"reason", ""}}},
+	})
+	return errors.Annotate(err, "cannot resume rolling upgrade")
+}
+
+// FinishRollingUpgrade ends the rolling upgrade in progress, once all
+// machines have been released to it.
+func (st *State) FinishRollingUpgrade() error {
+	ops := []txn.Op{{
+		C:      rollingUpgradesC,
+		Id:     st.docID(currentRollingUpgradeId),
+		Assert: txn.DocExists,
+		Remove: true,
+	}}
+	if err := st.runTransaction(ops); err == txn.ErrAborted {
+		return errors.New("cannot finish rolling upgrade: no rolling upgrade in progress")
+	} else if err != nil {
+		return errors.Annotate(err, "cannot finish rolling upgrade")
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/version"
+)
+
+type RollingUpgradeSuite struct {
+	ConnSuite
+	from version.Number
+}
+
+var _ = gc.Suite(&RollingUpgradeSuite{})
+
+func (s *RollingUpgradeSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	envConfig, err := s.State.EnvironConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	var ok bool
+	s.from, ok = envConfig.AgentVersion()
+	c.Assert(ok, jc.IsTrue)
+}
+
+func (s *RollingUpgradeSuite) TestSetEnvironAgentVersionRolling(c *gc.C) {
+	_, err := s.State.RollingUpgrade()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = s.State.SetEnvironAgentVersionRolling(version.MustParse("4.5.6"), 0)
+	c.Assert(err, gc.ErrorMatches, "batch size 0 not valid")
+
+	err = s.State.SetEnvironAgentVersionRolling(version.MustParse("4.5.6"), 2)
+	c.Assert(err, jc.ErrorIsNil)
+	assertAgentVersion(c, s.State, "4.5.6")
+	rolling, err := s.State.RollingUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rolling, jc.DeepEquals, state.RollingUpgrade{
+		From:      s.from,
+		To:        version.MustParse("4.5.6"),
+		BatchSize: 2,
+		Machines:  []string{},
+	})
+
+	// A plain upgrade abandons the rolling upgrade.
+	err = s.State.SetEnvironAgentVersion(version.MustParse("4.5.7"))
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.RollingUpgrade()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *RollingUpgradeSuite) TestReleasePauseResumeFinish(c *gc.C) {
+	err := s.State.PauseRollingUpgrade("machine 1 failed")
+	c.Assert(err, gc.ErrorMatches, "cannot pause rolling upgrade: no rolling upgrade in progress")
+
+	err = s.State.SetEnvironAgentVersionRolling(version.MustParse("4.5.6"), 1)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.ReleaseRollingUpgradeMachines([]string{"1", "2"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.ReleaseRollingUpgradeMachines([]string{"2", "3"})
+	c.Assert(err, jc.ErrorIsNil)
+	rolling, err := s.State.RollingUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rolling.Machines, jc.DeepEquals, []string{"1", "2", "3"})
+	c.Assert(rolling.Released("2"), jc.IsTrue)
+	c.Assert(rolling.Released("4"), jc.IsFalse)
+
+	err = s.State.PauseRollingUpgrade("machine 1 failed")
+	c.Assert(err, jc.ErrorIsNil)
+	rolling, err = s.State.RollingUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rolling.Paused, jc.IsTrue)
+	c.Assert(rolling.Reason, gc.Equals, "machine 1 failed")
+
+	err = s.State.ResumeRollingUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	rolling, err = s.State.RollingUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rolling.Paused, jc.IsFalse)
+	c.Assert(rolling.Reason, gc.Equals, "")
+
+	err = s.State.FinishRollingUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.RollingUpgrade()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	err = s.State.FinishRollingUpgrade()
+	c.Assert(err, gc.ErrorMatches, "cannot finish rolling upgrade: no rolling upgrade in progress")
+}
@@ -0,0 +1,265 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// ScalingPolicy describes how the number of units of a service is
+// adjusted, one unit at a time, according to a metric reported by
+// the service's units. The zero value disables autoscaling.
+type ScalingPolicy struct {
+	// Metric holds the name of the charm metric that drives
+	// scaling decisions.
+	Metric string
+
+	// ScaleUpAbove holds the average metric value above which a
+	// unit is added to the service.
+	ScaleUpAbove float64
+
+	// ScaleDownBelow holds the average metric value below which a
+	// unit is removed from the service.
+	ScaleDownBelow float64
+
+	// Window holds the period over which the metric is averaged.
+	Window time.Duration
+
+	// MinUnits and MaxUnits bound the number of units the service
+	// is scaled to.
+	MinUnits int
+	MaxUnits int
+
+	// Cooldown holds the minimum time between two scaling decisions.
+	Cooldown time.Duration
+}
+
+// IsZero reports whether the policy disables autoscaling.
+func (p ScalingPolicy) IsZero() bool {
+	return p == ScalingPolicy{}
+}
+
+// Validate returns an error if the policy is not valid.
+func (p ScalingPolicy) Validate() error {
+	if p.IsZero() {
+		return nil
+	}
+	if p.Metric == "" {
+		return errors.NotValidf("scaling policy without metric")
+	}
+	if p.ScaleDownBelow >= p.ScaleUpAbove {
+		return errors.NotValidf("scale-down threshold not below scale-up threshold")
+	}
+	if p.Window <= 0 {
+		return errors.NotValidf("non-positive scaling window")
+	}
+	if p.Window > CleanupAge {
+		// Metrics are not kept for longer.
+		return errors.NotValidf("scaling window longer than %v", CleanupAge)
+	}
+	if p.Cooldown < 0 {
+		return errors.NotValidf("negative scaling cooldown")
+	}
+	if p.MinUnits < 0 {
+		return errors.NotValidf("negative minimum units")
+	}
+	if p.MaxUnits < 1 || p.MaxUnits < p.MinUnits {
+		return errors.NotValidf("maximum units %d with minimum units %d", p.MaxUnits, p.MinUnits)
+	}
+	return nil
+}
+
+// scalingPolicyDoc represents a ScalingPolicy in MongoDB, along with
+// the time of the last scaling decision taken under it.
+type scalingPolicyDoc struct {
+	Metric         string        `bson:"metric"`
+	ScaleUpAbove   float64       `bson:"scaleupabove"`
+	ScaleDownBelow float64       `bson:"scaledownbelow"`
+	Window         time.Duration `bson:"window"`
+	MinUnits       int           `bson:"minunits"`
+	MaxUnits       int           `bson:"maxunits"`
+	Cooldown       time.Duration `bson:"cooldown"`
+	ScaledAt       time.Time     `bson:"scaledat,omitempty"`
+}
+
+// ScalingPolicy returns the policy used to scale the service.
+func (s *Service) ScalingPolicy() ScalingPolicy {
+	doc := s.doc.ScalingPolicy
+	if doc == nil {
+		return ScalingPolicy{}
+	}
+	return ScalingPolicy{
+		Metric:         doc.Metric,
+		ScaleUpAbove:   doc.ScaleUpAbove,
+		ScaleDownBelow: doc.ScaleDownBelow,
+		Window:         doc.Window,
+		MinUnits:       doc.MinUnits,
+		MaxUnits:       doc.MaxUnits,
+		Cooldown:       doc.Cooldown,
+	}
+}
+
+// ScaledAt returns the time the service was last scaled under its
+// scaling policy, or the zero time if it has not been.
+func (s *Service) ScaledAt() time.Time {
+	if s.doc.ScalingPolicy == nil {
+		return time.Time{}
+	}
+	return s.doc.ScalingPolicy.ScaledAt
+}
+
+// SetScalingPolicy changes the policy used to scale the service. The
+// service must not be a subordinate, and the policy's metric must be
+// declared by the service's charm. Setting the zero policy disables
+// autoscaling.
+func (s *Service) SetScalingPolicy(policy ScalingPolicy) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set scaling policy for service %q", s.doc.Name)
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+	var doc *scalingPolicyDoc
+	var update bson.D
+	if policy.IsZero() {
+		update = bson.D{{"$unset", bson.D{{"scalingpolicy", nil}}}}
+	} else {
+		if !s.IsPrincipal() {
+			// Subordinate units come and go with their principals.
+			return errors.NotValidf("scaling policy for subordinate service")
+		}
+		ch, _, err := s.Charm()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if metrics := ch.Metrics(); metrics == nil {
+			return errors.NotValidf("metric %q not declared by charm", policy.Metric)
+		} else if _, ok := metrics.Metrics[policy.Metric]; !ok {
+			return errors.NotValidf("metric %q not declared by charm", policy.Metric)
+		}
+		doc = &scalingPolicyDoc{
+			Metric:         policy.Metric,
+			ScaleUpAbove:   policy.ScaleUpAbove,
+			ScaleDownBelow: policy.ScaleDownBelow,
+			Window:         policy.Window,
+			MinUnits:       policy.MinUnits,
+			MaxUnits:       policy.MaxUnits,
+			Cooldown:       policy.Cooldown,
+		}
+		update = bson.D{{"$set", bson.D{{"scalingpolicy", doc}}}}
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			alive, err := isAlive(s.st, servicesC, s.doc.DocID)
+			if err != nil {
+				return nil, errors.Trace(err)
+			} else if !alive {
+				return nil, errNotAlive
+			}
+		}
+		return []txn.Op{{
+			C:      servicesC,
+			Id:     s.doc.DocID,
+			Assert: isAliveDoc,
+			Update: update,
+		}}, nil
+	}
+	if err := s.st.run(buildTxn); err != nil {
+		if err == errNotAlive {
+			return errors.New("service " + err.Error())
+		}
+		return errors.Trace(err)
+	}
+	s.doc.ScalingPolicy = doc
+	return nil
+}
+
+// MetricAverage returns the average of the values of the named metric
+// reported by the service's units since the given time, and the number
+// of values averaged. Values that are not numbers are ignored.
+func (s *Service) MetricAverage(key string, since time.Time) (average float64, count int, err error) {
+	metrics, closer := s.st.getCollection(metricsC)
+	defer closer()
+
+	var docs []metricBatchDoc
+	err = metrics.Find(bson.D{
+		{"env-uuid", s.st.EnvironUUID()},
+		{"unit", bson.D{{"$regex", "^" + regexp.QuoteMeta(s.doc.Name) + "/"}}},
+		{"created", bson.D{{"$gte", since}}},
+	}).All(&docs)
+	if err != nil {
+		return 0, 0, errors.Annotatef(err, "cannot get metrics of service %q", s.doc.Name)
+	}
+	var sum float64
+	for _, doc := range docs {
+		for _, metric := range doc.Metrics {
+			if metric.Key != key || metric.Time.Before(since) {
+				continue
+			}
+			value, err := strconv.ParseFloat(metric.Value, 64)
+			if err != nil {
+				continue
+			}
+			sum += value
+			count++
+		}
+	}
+	if count == 0 {
+		return 0, 0, nil
+	}
+	return sum / float64(count), count, nil
+}
+
+// RecordScaling records that the service has been scaled from one
+// number of units to another under its scaling policy, for the given
+// reason. The decision is added to the service's status history, and
+// starts the policy's cooldown period.
+func (s *Service) RecordScaling(from, to int, reason string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot record scaling of service %q", s.doc.Name)
+	now := nowToTheSecond()
+	ops := []txn.Op{{
+		C:      servicesC,
+		Id:     s.doc.DocID,
+		Assert: bson.D{{"scalingpolicy", bson.D{{"$exists", true}}}},
+		Update: bson.D{{"$set", bson.D{{"scalingpolicy.scaledat", now}}}},
+	}}
+	if err := s.st.runTransaction(ops); err == txn.ErrAborted {
+		return errors.New("service has no scaling policy")
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	s.doc.ScalingPolicy.ScaledAt = now
+
+	current, err := s.Status()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	verb := "up"
+	if to < from {
+		verb = "down"
+	}
+	return updateStatusHistory(statusDoc{
+		EnvUUID:    s.st.EnvironUUID(),
+		Status:     current.Status,
+		StatusInfo: fmt.Sprintf("scaled %s from %d to %d units: %s", verb, from, to, reason),
+		StatusData: map[string]interface{}{
+			"scaling": true,
+			"from":    from,
+			"to":      to,
+		},
+		Updated: &now,
+	}, s.globalKey(), s.st)
+}
+
+// StatusHistory returns a slice of at most size StatusInfo items
+// representing past statuses of the service, including its scaling
+// decisions, most recent first.
+func (s *Service) StatusHistory(size int) ([]StatusInfo, error) {
+	return statusHistory(size, s.globalKey(), s.st)
+}
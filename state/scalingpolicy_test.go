@@ -0,0 +1,151 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
+)
+
+type ScalingPolicySuite struct {
+	ConnSuite
+	service *state.Service
+	unit    *state.Unit
+}
+
+var _ = gc.Suite(&ScalingPolicySuite{})
+
+var testScalingPolicy = state.ScalingPolicy{
+	Metric:         "pings",
+	ScaleUpAbove:   80,
+	ScaleDownBelow: 20,
+	Window:         5 * time.Minute,
+	MinUnits:       1,
+	MaxUnits:       5,
+	Cooldown:       10 * time.Minute,
+}
+
+func (s *ScalingPolicySuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	meteredCharm := s.Factory.MakeCharm(c, &factory.CharmParams{Name: "metered", URL: "cs:quantal/metered"})
+	s.service = s.Factory.MakeService(c, &factory.ServiceParams{Charm: meteredCharm})
+	s.unit = s.Factory.MakeUnit(c, &factory.UnitParams{Service: s.service, SetCharmURL: true})
+}
+
+func (s *ScalingPolicySuite) TestSetScalingPolicy(c *gc.C) {
+	c.Assert(s.service.ScalingPolicy().IsZero(), jc.IsTrue)
+	err := s.service.SetScalingPolicy(testScalingPolicy)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.service.ScalingPolicy(), jc.DeepEquals, testScalingPolicy)
+
+	service, err := s.State.Service(s.service.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(service.ScalingPolicy(), jc.DeepEquals, testScalingPolicy)
+	c.Assert(service.ScaledAt().IsZero(), jc.IsTrue)
+
+	err = service.SetScalingPolicy(state.ScalingPolicy{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.service.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.service.ScalingPolicy().IsZero(), jc.IsTrue)
+}
+
+func (s *ScalingPolicySuite) TestSetScalingPolicyInvalid(c *gc.C) {
+	for i, test := range []struct {
+		change func(*state.ScalingPolicy)
+		err    string
+	}{{
+		change: func(p *state.ScalingPolicy) { p.Metric = "" },
+		err:    "scaling policy without metric not valid",
+	}, {
+		change: func(p *state.ScalingPolicy) { p.Metric = "bytes" },
+		err:    `metric "bytes" not declared by charm not valid`,
+	}, {
+		change: func(p *state.ScalingPolicy) { p.ScaleDownBelow = 80 },
+		err:    "scale-down threshold not below scale-up threshold not valid",
+	}, {
+		change: func(p *state.ScalingPolicy) { p.Window = 0 },
+		err:    "non-positive scaling window not valid",
+	}, {
+		change: func(p *state.ScalingPolicy) { p.Window = 48 * time.Hour },
+		err:    "scaling window longer than 24h0m0s not valid",
+	}, {
+		change: func(p *state.ScalingPolicy) { p.Cooldown = -time.Second },
+		err:    "negative scaling cooldown not valid",
+	}, {
+		change: func(p *state.ScalingPolicy) { p.MaxUnits = 0; p.MinUnits = 0 },
+		err:    "maximum units 0 with minimum units 0 not valid",
+	}, {
+		change: func(p *state.ScalingPolicy) { p.MinUnits = 6 },
+		err:    "maximum units 5 with minimum units 6 not valid",
+	}} {
+		c.Logf("test %d", i)
+		policy := testScalingPolicy
+		test.change(&policy)
+		err := s.service.SetScalingPolicy(policy)
+		c.Check(err, gc.ErrorMatches, `cannot set scaling policy for service "metered": `+test.err)
+		c.Check(err, jc.Satisfies, errors.IsNotValid)
+	}
+}
+
+func (s *ScalingPolicySuite) TestSetScalingPolicySubordinate(c *gc.C) {
+	logging := s.AddTestingService(c, "logging", s.AddTestingCharm(c, "logging"))
+	err := logging.SetScalingPolicy(testScalingPolicy)
+	c.Assert(err, gc.ErrorMatches, `cannot set scaling policy for service "logging": scaling policy for subordinate service not valid`)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *ScalingPolicySuite) addMetric(c *gc.C, unit *state.Unit, value string, t time.Time) {
+	metrics := []state.Metric{{Key: "pings", Value: value, Time: t}}
+	_, err := unit.AddMetrics(utils.MustNewUUID().String(), t, "", metrics)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *ScalingPolicySuite) TestMetricAverage(c *gc.C) {
+	now := state.NowToTheSecond()
+	other := s.Factory.MakeUnit(c, &factory.UnitParams{Service: s.service, SetCharmURL: true})
+	s.addMetric(c, s.unit, "10", now.Add(-time.Hour))
+	s.addMetric(c, s.unit, "20", now.Add(-time.Minute))
+	s.addMetric(c, other, "40", now)
+
+	average, count, err := s.service.MetricAverage("pings", now.Add(-5*time.Minute))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(count, gc.Equals, 2)
+	c.Assert(average, gc.Equals, 30.0)
+
+	_, count, err = s.service.MetricAverage("juju-unit-time", now.Add(-5*time.Minute))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(count, gc.Equals, 0)
+}
+
+func (s *ScalingPolicySuite) TestRecordScaling(c *gc.C) {
+	err := s.service.RecordScaling(1, 2, "no reason")
+	c.Assert(err, gc.ErrorMatches, `cannot record scaling of service "metered": service has no scaling policy`)
+
+	err = s.service.SetScalingPolicy(testScalingPolicy)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.service.RecordScaling(1, 2, "average pings 90 above 80")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.service.RecordScaling(2, 1, "average pings 10 below 20")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.service.ScaledAt().IsZero(), jc.IsFalse)
+
+	history, err := s.service.StatusHistory(10)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 2)
+	c.Assert(history[0].Message, gc.Equals, "scaled down from 2 to 1 units: average pings 10 below 20")
+	c.Assert(history[1].Message, gc.Equals, "scaled up from 1 to 2 units: average pings 90 above 80")
+	c.Assert(history[1].Data, jc.DeepEquals, map[string]interface{}{
+		"scaling": true,
+		"from":    1,
+		"to":      2,
+	})
+}
@@ -0,0 +1,259 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/juju/errors"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/utils/cron"
+)
+
+// ScheduledEvent describes a deferred event requested by a unit's
+// charm. When the event becomes due, the unit runs its
+// scheduled-event hook.
+type ScheduledEvent struct {
+	// Name identifies the event among those scheduled by the unit.
+	Name string
+
+	// Due holds the time at which the event next fires.
+	Due time.Time
+
+	// Cron holds the specification used to reschedule the event
+	// each time it fires. If it is empty, the event fires once.
+	Cron string
+}
+
+var validScheduledEventName = regexp.MustCompile("^[a-z][a-z0-9-]*$")
+
+// Validate returns an error if the event is not valid.
+func (e ScheduledEvent) Validate() error {
+	if !validScheduledEventName.MatchString(e.Name) {
+		return errors.NotValidf("scheduled event name %q", e.Name)
+	}
+	if e.Due.IsZero() {
+		return errors.NotValidf("scheduled event %q without due time", e.Name)
+	}
+	if e.Cron != "" {
+		if _, err := cron.Parse(e.Cron); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// scheduledEventsDoc holds all the events scheduled by a unit, keyed
+// on event name, so that the unit agent can watch a single document.
+type scheduledEventsDoc struct {
+	DocID   string                       `bson:"_id"`
+	EnvUUID string                       `bson:"env-uuid"`
+	Unit    string                       `bson:"unit"`
+	Events  map[string]scheduledEventDoc `bson:"events"`
+}
+
+type scheduledEventDoc struct {
+	Due  time.Time `bson:"due"`
+	Cron string    `bson:"cron,omitempty"`
+}
+
+func (u *Unit) scheduledEventsDoc() (*scheduledEventsDoc, error) {
+	events, closer := u.st.getCollection(scheduledEventsC)
+	defer closer()
+
+	var doc scheduledEventsDoc
+	err := events.FindId(u.st.docID(u.globalKey())).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &doc, nil
+}
+
+// ScheduleEvent schedules the supplied event for the unit, replacing
+// any existing event with the same name.
+func (u *Unit) ScheduleEvent(event ScheduledEvent) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot schedule event for unit %q", u.Name())
+	if err := event.Validate(); err != nil {
+		return err
+	}
+	eventDoc := scheduledEventDoc{
+		Due:  event.Due.UTC(),
+		Cron: event.Cron,
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			alive, err := isAlive(u.st, unitsC, u.doc.DocID)
+			if err != nil {
+				return nil, errors.Trace(err)
+			} else if !alive {
+				return nil, errNotAlive
+			}
+		}
+		doc, err := u.scheduledEventsDoc()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops := []txn.Op{{
+			C:      unitsC,
+			Id:     u.doc.DocID,
+			Assert: isAliveDoc,
+		}}
+		if doc == nil {
+			return append(ops, txn.Op{
+				C:      scheduledEventsC,
+				Id:     u.st.docID(u.globalKey()),
+				Assert: txn.DocMissing,
+				Insert: &scheduledEventsDoc{
+					EnvUUID: u.st.EnvironUUID(),
+					Unit:    u.Name(),
+					Events:  map[string]scheduledEventDoc{event.Name: eventDoc},
+				},
+			}), nil
+		}
+		return append(ops, txn.Op{
+			C:      scheduledEventsC,
+			Id:     doc.DocID,
+			Assert: txn.DocExists,
+			Update: bson.D{{"$set", bson.D{{"events." + event.Name, eventDoc}}}},
+		}), nil
+	}
+	if err := u.st.run(buildTxn); err != nil {
+		if err == errNotAlive {
+			return errors.New("unit " + err.Error())
+		}
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// CancelScheduledEvent removes the named event from the unit's
+// scheduled events.
+func (u *Unit) CancelScheduledEvent(name string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot cancel scheduled event %q for unit %q", name, u.Name())
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		doc, err := u.scheduledEventsDoc()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if doc == nil {
+			return nil, errors.NotFoundf("scheduled event %q", name)
+		}
+		if _, ok := doc.Events[name]; !ok {
+			return nil, errors.NotFoundf("scheduled event %q", name)
+		}
+		field := "events." + name
+		return []txn.Op{{
+			C:      scheduledEventsC,
+			Id:     doc.DocID,
+			Assert: bson.D{{field, bson.D{{"$exists", true}}}},
+			Update: bson.D{{"$unset", bson.D{{field, nil}}}},
+		}}, nil
+	}
+	return u.st.run(buildTxn)
+}
+
+// FireScheduledEvent records that the named event, due at the given
+// time, has been delivered to the unit. A one-shot event is removed; a
+// recurring event is rescheduled to the next time its cron
+// specification matches. If the event has been rescheduled since it
+// became due, as it may be by the very hook it triggered, it is left
+// alone.
+func (u *Unit) FireScheduledEvent(name string, due time.Time) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot fire scheduled event %q for unit %q", name, u.Name())
+	due = due.UTC()
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		doc, err := u.scheduledEventsDoc()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if doc == nil {
+			return nil, errors.NotFoundf("scheduled event %q", name)
+		}
+		eventDoc, ok := doc.Events[name]
+		if !ok {
+			return nil, errors.NotFoundf("scheduled event %q", name)
+		}
+		if !eventDoc.Due.Equal(due) {
+			return nil, jujutxn.ErrNoOperations
+		}
+		field := "events." + name
+		op := txn.Op{
+			C:      scheduledEventsC,
+			Id:     doc.DocID,
+			Assert: bson.D{{field + ".due", due}},
+		}
+		var next time.Time
+		if eventDoc.Cron != "" {
+			schedule, err := cron.Parse(eventDoc.Cron)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			next = schedule.Next(time.Now().UTC())
+		}
+		if next.IsZero() {
+			op.Update = bson.D{{"$unset", bson.D{{field, nil}}}}
+		} else {
+			op.Update = bson.D{{"$set", bson.D{{field + ".due", next}}}}
+		}
+		return []txn.Op{op}, nil
+	}
+	return u.st.run(buildTxn)
+}
+
+// ScheduledEvents returns the events scheduled by the unit, ordered
+// by due time.
+func (u *Unit) ScheduledEvents() ([]ScheduledEvent, error) {
+	doc, err := u.scheduledEventsDoc()
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get scheduled events for unit %q", u.Name())
+	}
+	if doc == nil {
+		return nil, nil
+	}
+	events := make([]ScheduledEvent, 0, len(doc.Events))
+	for name, eventDoc := range doc.Events {
+		events = append(events, ScheduledEvent{
+			Name: name,
+			Due:  eventDoc.Due.UTC(),
+			Cron: eventDoc.Cron,
+		})
+	}
+	sort.Sort(byDue(events))
+	return events, nil
+}
+
+// WatchScheduledEvents returns a watcher that notifies when the
+// unit's scheduled events change.
+func (u *Unit) WatchScheduledEvents() NotifyWatcher {
+	return newEntityWatcher(u.st, scheduledEventsC, u.st.docID(u.globalKey()))
+}
+
+// removeScheduledEventsOp returns the operation needed to remove the
+// scheduled events of the unit with the given global key.
+func removeScheduledEventsOp(st *State, globalKey string) txn.Op {
+	return txn.Op{
+		C:      scheduledEventsC,
+		Id:     st.docID(globalKey),
+		Remove: true,
+	}
+}
+
+type byDue []ScheduledEvent
+
+func (b byDue) Len() int      { return len(b) }
+func (b byDue) Swap(i, j int) { b[i], b[j] = b[j], b[i] }
+func (b byDue) Less(i, j int) bool {
+	if b[i].Due.Equal(b[j].Due) {
+		return b[i].Name < b[j].Name
+	}
+	return b[i].Due.Before(b[j].Due)
+}
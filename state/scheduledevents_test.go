@@ -0,0 +1,196 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+	"github.com/juju/juju/testing/factory"
+)
+
+type ScheduledEventsSuite struct {
+	ConnSuite
+	unit *state.Unit
+}
+
+var _ = gc.Suite(&ScheduledEventsSuite{})
+
+func (s *ScheduledEventsSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.unit = factory.NewFactory(s.State).MakeUnit(c, nil)
+}
+
+func (s *ScheduledEventsSuite) TestScheduleEvent(c *gc.C) {
+	events, err := s.unit.ScheduledEvents()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(events, gc.HasLen, 0)
+
+	due := time.Date(2015, 7, 1, 12, 0, 0, 0, time.UTC)
+	rotate := state.ScheduledEvent{Name: "rotate-certs", Due: due.Add(time.Hour)}
+	nightly := state.ScheduledEvent{Name: "nightly", Due: due, Cron: "0 3 * * *"}
+	err = s.unit.ScheduleEvent(rotate)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.ScheduleEvent(nightly)
+	c.Assert(err, jc.ErrorIsNil)
+
+	events, err = s.unit.ScheduledEvents()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(events, jc.DeepEquals, []state.ScheduledEvent{nightly, rotate})
+
+	// Scheduling an event with the same name replaces it.
+	rotate.Due = due.Add(-time.Hour)
+	err = s.unit.ScheduleEvent(rotate)
+	c.Assert(err, jc.ErrorIsNil)
+	events, err = s.unit.ScheduledEvents()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(events, jc.DeepEquals, []state.ScheduledEvent{rotate, nightly})
+}
+
+func (s *ScheduledEventsSuite) TestScheduleEventInvalid(c *gc.C) {
+	due := time.Date(2015, 7, 1, 12, 0, 0, 0, time.UTC)
+	for i, test := range []struct {
+		event state.ScheduledEvent
+		err   string
+	}{{
+		event: state.ScheduledEvent{Name: "Bad.Name", Due: due},
+		err:   `scheduled event name "Bad.Name" not valid`,
+	}, {
+		event: state.ScheduledEvent{Name: "no-due"},
+		err:   `scheduled event "no-due" without due time not valid`,
+	}, {
+		event: state.ScheduledEvent{Name: "bad-cron", Due: due, Cron: "every day"},
+		err:   `cron specification "every day" \(expected 5 fields\) not valid`,
+	}} {
+		c.Logf("test %d", i)
+		err := s.unit.ScheduleEvent(test.event)
+		c.Check(err, gc.ErrorMatches, `cannot schedule event for unit "mysql/0": `+test.err)
+		c.Check(err, jc.Satisfies, errors.IsNotValid)
+	}
+}
+
+func (s *ScheduledEventsSuite) TestScheduleEventDeadUnit(c *gc.C) {
+	err := s.unit.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.ScheduleEvent(state.ScheduledEvent{Name: "later", Due: time.Now()})
+	c.Assert(err, gc.ErrorMatches, `cannot schedule event for unit "mysql/0": unit not found or not alive`)
+}
+
+func (s *ScheduledEventsSuite) TestCancelScheduledEvent(c *gc.C) {
+	err := s.unit.CancelScheduledEvent("later")
+	c.Assert(err, gc.ErrorMatches, `cannot cancel scheduled event "later" for unit "mysql/0": scheduled event "later" not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = s.unit.ScheduleEvent(state.ScheduledEvent{Name: "later", Due: time.Now()})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.CancelScheduledEvent("later")
+	c.Assert(err, jc.ErrorIsNil)
+	events, err := s.unit.ScheduledEvents()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(events, gc.HasLen, 0)
+}
+
+func (s *ScheduledEventsSuite) TestFireScheduledEvent(c *gc.C) {
+	due := time.Date(2015, 7, 1, 12, 0, 0, 0, time.UTC)
+	err := s.unit.ScheduleEvent(state.ScheduledEvent{Name: "once", Due: due})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.ScheduleEvent(state.ScheduledEvent{Name: "hourly", Due: due, Cron: "@hourly"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	before := time.Now()
+	err = s.unit.FireScheduledEvent("once", due)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.FireScheduledEvent("hourly", due)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The one-shot event is gone; the recurring one has been
+	// rescheduled to the next hour.
+	events, err := s.unit.ScheduledEvents()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(events, gc.HasLen, 1)
+	c.Assert(events[0].Name, gc.Equals, "hourly")
+	c.Assert(events[0].Cron, gc.Equals, "@hourly")
+	c.Assert(events[0].Due.After(before), jc.IsTrue)
+	c.Assert(events[0].Due.Sub(before) <= time.Hour, jc.IsTrue)
+	c.Assert(events[0].Due.Minute(), gc.Equals, 0)
+
+	err = s.unit.FireScheduledEvent("once", due)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *ScheduledEventsSuite) TestFireScheduledEventRescheduledByHook(c *gc.C) {
+	due := time.Date(2015, 7, 1, 12, 0, 0, 0, time.UTC)
+	err := s.unit.ScheduleEvent(state.ScheduledEvent{Name: "once", Due: due})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.ScheduleEvent(state.ScheduledEvent{Name: "hourly", Due: due, Cron: "@hourly"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The hooks triggered by the events schedule them again before
+	// they are fired.
+	later := due.Add(24 * time.Hour)
+	err = s.unit.ScheduleEvent(state.ScheduledEvent{Name: "once", Due: later})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.ScheduleEvent(state.ScheduledEvent{Name: "hourly", Due: later, Cron: "@daily"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.unit.FireScheduledEvent("once", due)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.FireScheduledEvent("hourly", due)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Both keep the schedules set by their hooks.
+	events, err := s.unit.ScheduledEvents()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(events, jc.DeepEquals, []state.ScheduledEvent{
+		{Name: "hourly", Due: later, Cron: "@daily"},
+		{Name: "once", Due: later},
+	})
+}
+
+func (s *ScheduledEventsSuite) TestScheduledEventsRemovedWithUnit(c *gc.C) {
+	err := s.unit.ScheduleEvent(state.ScheduledEvent{Name: "later", Due: time.Now()})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+
+	events, err := s.unit.ScheduledEvents()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(events, gc.HasLen, 0)
+}
+
+func (s *ScheduledEventsSuite) TestWatchScheduledEvents(c *gc.C) {
+	w := s.unit.WatchScheduledEvents()
+	defer statetesting.AssertStop(c, w)
+
+	// Initial event.
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	due := time.Date(2015, 7, 1, 12, 0, 0, 0, time.UTC)
+	err := s.unit.ScheduleEvent(state.ScheduledEvent{Name: "later", Due: due})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	err = s.unit.ScheduleEvent(state.ScheduledEvent{Name: "sooner", Due: due})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	err = s.unit.FireScheduledEvent("later", due)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	err = s.unit.CancelScheduledEvent("sooner")
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	statetesting.AssertStop(c, w)
+	wc.AssertClosed()
+}
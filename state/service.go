@@ -46,6 +46,13 @@ type serviceDoc struct {
 	OwnerTag          string     `bson:"ownertag"`
 	TxnRevno          int64      `bson:"txn-revno"`
 	MetricCredentials []byte     `bson:"metric-credentials"`
+
+	HookRetryPolicy  *hookRetryPolicyDoc `bson:"hookretrypolicy,omitempty"`
+	EndpointBindings map[string]string   `bson:"endpointbindings,omitempty"`
+	PlacementPolicy  *placementPolicyDoc `bson:"placementpolicy,omitempty"`
+	ScalingPolicy    *scalingPolicyDoc   `bson:"scalingpolicy,omitempty"`
+	PreviousCharmURL *charm.URL          `bson:"previouscharmurl,omitempty"`
+	CharmRollout     *charmRolloutDoc    `bson:"charmrollout,omitempty"`
 }
 
 func newService(st *State, doc *serviceDoc) *Service {
@@ -455,8 +462,9 @@ func (s *Service) checkStorageUpgrade(newMeta *charm.Meta) (err error) {
 }
 
 // changeCharmOps returns the operations necessary to set a service's
-// charm URL to a new value.
-func (s *Service) changeCharmOps(ch *Charm, force bool) ([]txn.Op, error) {
+// charm URL to a new value, replacing any charm rollout in progress
+// with the supplied one.
+func (s *Service) changeCharmOps(ch *Charm, force bool, rollout *charmRolloutDoc) ([]txn.Op, error) {
 	// Build the new service config from what can be used of the old one.
 	var newSettings charm.Settings
 	oldSettings, err := readSettings(s.st, s.settingsKey())
@@ -512,13 +520,19 @@ func (s *Service) changeCharmOps(ch *Charm, force bool) ([]txn.Op, error) {
 		settingsOp,
 		// Increment the ref count.
 		incOp,
-		// Update the charm URL and force flag (if relevant).
+		// Update the charm URL and force flag (if relevant),
+		// remembering the current charm URL for rollbacks.
 		{
 			C:      servicesC,
 			Id:     s.doc.DocID,
 			Assert: append(notDeadDoc, differentCharm...),
-			Update: bson.D{{"$set", bson.D{{"charmurl", ch.URL()}, {"forcecharm", force}}}},
+			Update: bson.D{{"$set", bson.D{
+				{"charmurl", ch.URL()},
+				{"forcecharm", force},
+				{"previouscharmurl", s.doc.CharmURL},
+			}}},
 		},
+		charmRolloutOp(s.doc.DocID, rollout),
 	}...)
 	// Add any extra peer relations that need creation.
 	newPeers := s.extraPeerRelations(ch.Meta())
@@ -562,8 +576,15 @@ func (s *Service) changeCharmOps(ch *Charm, force bool) ([]txn.Op, error) {
 
 // SetCharm changes the charm for the service. New units will be started with
 // this charm, and existing units will be upgraded to use it. If force is true,
-// units will be upgraded even if they are in an error state.
+// units will be upgraded even if they are in an error state. Any charm
+// rollout in progress is abandoned.
 func (s *Service) SetCharm(ch *Charm, force bool) error {
+	return s.setCharm(ch, force, nil)
+}
+
+// setCharm changes the charm for the service, starting the supplied
+// charm rollout if it is not nil.
+func (s *Service) setCharm(ch *Charm, force bool, rollout *charmRolloutDoc) error {
 	if ch.Meta().Subordinate != s.doc.Subordinate {
 		return errors.Errorf("cannot change a service's subordinacy")
 	}
@@ -603,7 +624,7 @@ func (s *Service) SetCharm(ch *Charm, force bool) error {
 			}}
 		} else {
 			// Change the charm URL.
-			ops, err = s.changeCharmOps(ch, force)
+			ops, err = s.changeCharmOps(ch, force, rollout)
 			if err != nil {
 				return nil, errors.Trace(err)
 			}
@@ -611,6 +632,10 @@ func (s *Service) SetCharm(ch *Charm, force bool) error {
 		return ops, nil
 	}
 	err := s.st.run(buildTxn)
+	if err == nil && *s.doc.CharmURL != *ch.URL() {
+		s.doc.PreviousCharmURL = s.doc.CharmURL
+		s.doc.CharmRollout = rollout
+	}
 	if err == nil {
 		s.doc.CharmURL = ch.URL()
 		s.doc.ForceCharm = force
@@ -838,6 +863,8 @@ func (s *Service) removeUnitOps(u *Unit, asserts bson.D) ([]txn.Op, error) {
 			Remove: true,
 		},
 		removeMeterStatusOp(s.st, u.globalMeterStatusKey()),
+		removeScheduledEventsOp(s.st, u.globalKey()),
+		removeDebugHooksSessionOp(s.st, u.globalKey()),
 		removeStatusOp(s.st, u.globalAgentKey()),
 		removeStatusOp(s.st, u.globalKey()),
 		removeConstraintsOp(s.st, u.globalAgentKey()),
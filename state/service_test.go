@@ -6,6 +6,7 @@ package state_test
 import (
 	"fmt"
 	"sort"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
@@ -1778,6 +1779,63 @@ func (s *ServiceSuite) TestMetricCredentialsOnDying(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "cannot update metric credentials: service not found or not alive")
 }
 
+func (s *ServiceSuite) TestHookRetryPolicy(c *gc.C) {
+	c.Assert(s.mysql.HookRetryPolicy(), gc.Equals, state.HookRetryPolicy{})
+
+	policy := state.HookRetryPolicy{
+		MaxAttempts: 3,
+		Delay:       10 * time.Second,
+		MaxDelay:    time.Minute,
+	}
+	err := s.mysql.SetHookRetryPolicy(policy)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mysql.HookRetryPolicy(), gc.Equals, policy)
+
+	service, err := s.State.Service(s.mysql.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(service.HookRetryPolicy(), gc.Equals, policy)
+
+	err = service.SetHookRetryPolicy(state.HookRetryPolicy{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mysql.HookRetryPolicy(), gc.Equals, state.HookRetryPolicy{})
+}
+
+func (s *ServiceSuite) TestSetHookRetryPolicyInvalid(c *gc.C) {
+	for i, test := range []struct {
+		policy state.HookRetryPolicy
+		err    string
+	}{{
+		policy: state.HookRetryPolicy{MaxAttempts: -1},
+		err:    "negative hook retry attempts not valid",
+	}, {
+		policy: state.HookRetryPolicy{MaxAttempts: 1, Delay: -time.Second},
+		err:    "negative hook retry delay not valid",
+	}, {
+		policy: state.HookRetryPolicy{MaxAttempts: 1},
+		err:    "hook retry policy without delay not valid",
+	}, {
+		policy: state.HookRetryPolicy{MaxAttempts: 1, Delay: time.Minute, MaxDelay: time.Second},
+		err:    "hook retry maximum delay less than delay not valid",
+	}} {
+		c.Logf("test %d: %+v", i, test.policy)
+		err := s.mysql.SetHookRetryPolicy(test.policy)
+		c.Check(err, gc.ErrorMatches, `cannot set hook retry policy for service "mysql": `+test.err)
+		c.Check(err, jc.Satisfies, errors.IsNotValid)
+	}
+}
+
+func (s *ServiceSuite) TestSetHookRetryPolicyOnDying(c *gc.C) {
+	_, err := s.mysql.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	assertLife(c, s.mysql, state.Dying)
+	err = s.mysql.SetHookRetryPolicy(state.HookRetryPolicy{MaxAttempts: 1, Delay: time.Second})
+	c.Assert(err, gc.ErrorMatches, `cannot set hook retry policy for service "mysql": service not found or not alive`)
+}
+
 func (s *ServiceSuite) testStatus(c *gc.C, status1, status2, expected state.Status) {
 	u1, err := s.mysql.AddUnit()
 	c.Assert(err, jc.ErrorIsNil)
@@ -0,0 +1,349 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"net"
+	"sort"
+
+	"github.com/juju/errors"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/network"
+)
+
+// Space represents a named group of subnets. Service endpoints can be
+// bound to a space, and machines can be constrained to have addresses
+// in particular spaces.
+type Space struct {
+	st  *State
+	doc spaceDoc
+}
+
+type spaceDoc struct {
+	DocID   string `bson:"_id"`
+	EnvUUID string `bson:"env-uuid"`
+	Name    string `bson:"name"`
+}
+
+// Name returns the name of the space.
+func (s *Space) Name() string {
+	return s.doc.Name
+}
+
+// String implements fmt.Stringer.
+func (s *Space) String() string {
+	return s.doc.Name
+}
+
+// Subnets returns the subnets in the space, ordered by CIDR.
+func (s *Space) Subnets() ([]*Subnet, error) {
+	subnetsCollection, closer := s.st.getCollection(subnetsC)
+	defer closer()
+
+	var docs []subnetDoc
+	err := subnetsCollection.Find(bson.D{{"space-name", s.doc.Name}}).Sort("cidr").All(&docs)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get subnets in space %q", s.doc.Name)
+	}
+	subnets := make([]*Subnet, len(docs))
+	for i, doc := range docs {
+		subnets[i] = &Subnet{s.st, doc}
+	}
+	return subnets, nil
+}
+
+// CIDRs returns the CIDRs of the subnets in the space.
+func (s *Space) CIDRs() ([]string, error) {
+	subnets, err := s.Subnets()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	cidrs := make([]string, len(subnets))
+	for i, subnet := range subnets {
+		cidrs[i] = subnet.CIDR()
+	}
+	return cidrs, nil
+}
+
+// AddSubnets adds the subnets with the given CIDRs to the space.
+// Subnets not yet known are added to state; a subnet already in
+// another space cannot be added.
+func (s *Space) AddSubnets(cidrs []string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot add subnets to space %q", s.doc.Name)
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if _, err := s.st.Space(s.doc.Name); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		ops := []txn.Op{{
+			C:      spacesC,
+			Id:     s.doc.DocID,
+			Assert: txn.DocExists,
+		}}
+		subnetOps, err := addSubnetsToSpaceOps(s.st, s.doc.Name, cidrs)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if len(subnetOps) == 0 {
+			return nil, jujutxn.ErrNoOperations
+		}
+		return append(ops, subnetOps...), nil
+	}
+	return s.st.run(buildTxn)
+}
+
+// addSubnetsToSpaceOps returns the operations that put the subnets
+// with the given CIDRs into the named space, adding any that are not
+// yet known.
+func addSubnetsToSpaceOps(st *State, spaceName string, cidrs []string) ([]txn.Op, error) {
+	var ops []txn.Op
+	seen := make(map[string]bool)
+	for _, cidr := range cidrs {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return nil, errors.NotValidf("subnet CIDR %q", cidr)
+		}
+		if seen[cidr] {
+			continue
+		}
+		seen[cidr] = true
+		subnet, err := st.Subnet(cidr)
+		if errors.IsNotFound(err) {
+			ops = append(ops, txn.Op{
+				C:      subnetsC,
+				Id:     st.docID(cidr),
+				Assert: txn.DocMissing,
+				Insert: subnetDoc{
+					DocID:     st.docID(cidr),
+					EnvUUID:   st.EnvironUUID(),
+					Life:      Alive,
+					CIDR:      cidr,
+					SpaceName: spaceName,
+				},
+			})
+			continue
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		switch subnet.SpaceName() {
+		case spaceName:
+			continue
+		case "":
+		default:
+			return nil, errors.Errorf("subnet %q already in space %q", cidr, subnet.SpaceName())
+		}
+		ops = append(ops, txn.Op{
+			C:      subnetsC,
+			Id:     subnet.doc.DocID,
+			Assert: bson.D{{"space-name", bson.D{{"$exists", false}}}},
+			Update: bson.D{{"$set", bson.D{{"space-name", spaceName}}}},
+		})
+	}
+	return ops, nil
+}
+
+// AddSpace creates and returns a new space containing the subnets
+// with the given CIDRs. Subnets not yet known are added to state.
+func (st *State) AddSpace(name string, cidrs []string) (space *Space, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot add space %q", name)
+	if !network.IsValidSpaceName(name) {
+		return nil, errors.NotValidf("space name %q", name)
+	}
+	doc := spaceDoc{
+		DocID:   st.docID(name),
+		EnvUUID: st.EnvironUUID(),
+		Name:    name,
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if _, err := st.Space(name); err == nil {
+			return nil, errors.AlreadyExistsf("space %q", name)
+		} else if !errors.IsNotFound(err) {
+			return nil, errors.Trace(err)
+		}
+		ops := []txn.Op{{
+			C:      spacesC,
+			Id:     doc.DocID,
+			Assert: txn.DocMissing,
+			Insert: doc,
+		}}
+		subnetOps, err := addSubnetsToSpaceOps(st, name, cidrs)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return append(ops, subnetOps...), nil
+	}
+	if err := st.run(buildTxn); err != nil {
+		return nil, err
+	}
+	return &Space{st, doc}, nil
+}
+
+// Space returns the space with the given name.
+func (st *State) Space(name string) (*Space, error) {
+	spaces, closer := st.getCollection(spacesC)
+	defer closer()
+
+	var doc spaceDoc
+	err := spaces.FindId(name).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("space %q", name)
+	}
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get space %q", name)
+	}
+	return &Space{st, doc}, nil
+}
+
+// AllSpaces returns all the spaces in the environment, ordered by name.
+func (st *State) AllSpaces() ([]*Space, error) {
+	spacesCollection, closer := st.getCollection(spacesC)
+	defer closer()
+
+	var docs []spaceDoc
+	if err := spacesCollection.Find(nil).Sort("name").All(&docs); err != nil {
+		return nil, errors.Annotatef(err, "cannot get all spaces")
+	}
+	spaces := make([]*Space, len(docs))
+	for i, doc := range docs {
+		spaces[i] = &Space{st, doc}
+	}
+	return spaces, nil
+}
+
+// SpaceAddress returns the address of the unit's machine in the named
+// space. It returns an error satisfying errors.IsNotFound if the
+// machine has no address in the space.
+func (u *Unit) SpaceAddress(spaceName string) (network.Address, error) {
+	space, err := u.st.Space(spaceName)
+	if err != nil {
+		return network.Address{}, errors.Trace(err)
+	}
+	cidrs, err := space.CIDRs()
+	if err != nil {
+		return network.Address{}, errors.Trace(err)
+	}
+	m, err := u.machine()
+	if err != nil {
+		return network.Address{}, errors.Trace(err)
+	}
+	addr, ok := network.SelectAddressInSubnets(m.Addresses(), cidrs)
+	if !ok {
+		return network.Address{}, errors.NotFoundf("address of unit %q in space %q", u.Name(), spaceName)
+	}
+	return addr, nil
+}
+
+// EndpointAddress returns the address of the unit's machine in the
+// space the named endpoint of the unit's service is bound to; the
+// empty endpoint name refers to the service's default space. It returns
+// an error satisfying errors.IsNotFound if the endpoint is not bound to
+// a space, or the machine has no address in it.
+func (u *Unit) EndpointAddress(endpoint string) (network.Address, error) {
+	service, err := u.Service()
+	if err != nil {
+		return network.Address{}, errors.Trace(err)
+	}
+	spaceName := service.EndpointBinding(endpoint)
+	if spaceName == "" {
+		return network.Address{}, errors.NotFoundf("space binding for endpoint %q", endpoint)
+	}
+	return u.SpaceAddress(spaceName)
+}
+
+// EndpointBindings returns the spaces the service's endpoints are
+// bound to, keyed by endpoint name. The empty endpoint name holds the
+// default space, used for endpoints without an explicit binding and
+// for the unit's private address.
+func (s *Service) EndpointBindings() map[string]string {
+	bindings := make(map[string]string, len(s.doc.EndpointBindings))
+	for endpoint, space := range s.doc.EndpointBindings {
+		bindings[endpoint] = space
+	}
+	return bindings
+}
+
+// EndpointBinding returns the space the named endpoint is bound to,
+// falling back to the service's default space. It returns the empty
+// string if the endpoint is not bound to any space.
+func (s *Service) EndpointBinding(endpoint string) string {
+	if space, ok := s.doc.EndpointBindings[endpoint]; ok {
+		return space
+	}
+	return s.doc.EndpointBindings[""]
+}
+
+// SetEndpointBindings replaces the service's endpoint bindings. Each
+// key must be the name of an endpoint of the service's charm, or empty
+// to set the default space, and each value must name an existing space.
+func (s *Service) SetEndpointBindings(bindings map[string]string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set endpoint bindings for service %q", s.doc.Name)
+	ch, _, err := s.Charm()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	meta := ch.Meta()
+	var spaceNames []string
+	for endpoint, spaceName := range bindings {
+		if endpoint != "" {
+			_, provides := meta.Provides[endpoint]
+			_, requires := meta.Requires[endpoint]
+			_, peers := meta.Peers[endpoint]
+			if !provides && !requires && !peers {
+				return errors.NotFoundf("endpoint %q", endpoint)
+			}
+		}
+		spaceNames = append(spaceNames, spaceName)
+	}
+	sort.Strings(spaceNames)
+	update := bson.D{{"$set", bson.D{{"endpointbindings", bindings}}}}
+	if len(bindings) == 0 {
+		update = bson.D{{"$unset", bson.D{{"endpointbindings", nil}}}}
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			alive, err := isAlive(s.st, servicesC, s.doc.DocID)
+			if err != nil {
+				return nil, errors.Trace(err)
+			} else if !alive {
+				return nil, errNotAlive
+			}
+		}
+		ops := []txn.Op{{
+			C:      servicesC,
+			Id:     s.doc.DocID,
+			Assert: isAliveDoc,
+			Update: update,
+		}}
+		for _, spaceName := range spaceNames {
+			if _, err := s.st.Space(spaceName); err != nil {
+				return nil, errors.Trace(err)
+			}
+			ops = append(ops, txn.Op{
+				C:      spacesC,
+				Id:     s.st.docID(spaceName),
+				Assert: txn.DocExists,
+			})
+		}
+		return ops, nil
+	}
+	if err := s.st.run(buildTxn); err != nil {
+		if err == errNotAlive {
+			return errors.New("service " + err.Error())
+		}
+		return errors.Trace(err)
+	}
+	if len(bindings) == 0 {
+		s.doc.EndpointBindings = nil
+	} else {
+		s.doc.EndpointBindings = make(map[string]string, len(bindings))
+		for endpoint, space := range bindings {
+			s.doc.EndpointBindings[endpoint] = space
+		}
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+)
+
+type SpaceSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&SpaceSuite{})
+
+func (s *SpaceSuite) assertSpaceCIDRs(c *gc.C, name string, expected ...string) {
+	space, err := s.State.Space(name)
+	c.Assert(err, jc.ErrorIsNil)
+	cidrs, err := space.CIDRs()
+	c.Assert(err, jc.ErrorIsNil)
+	if len(expected) == 0 {
+		c.Assert(cidrs, gc.HasLen, 0)
+		return
+	}
+	c.Assert(cidrs, jc.DeepEquals, expected)
+}
+
+func (s *SpaceSuite) TestAddSpace(c *gc.C) {
+	_, err := s.State.AddSubnet(state.SubnetInfo{
+		CIDR:       "10.0.1.0/24",
+		ProviderId: "subnet-1",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	space, err := s.State.AddSpace("internal", []string{"10.0.1.0/24", "10.0.0.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(space.Name(), gc.Equals, "internal")
+	c.Assert(space.String(), gc.Equals, "internal")
+	s.assertSpaceCIDRs(c, "internal", "10.0.0.0/24", "10.0.1.0/24")
+
+	subnet, err := s.State.Subnet("10.0.1.0/24")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(subnet.SpaceName(), gc.Equals, "internal")
+	c.Assert(subnet.ProviderId(), gc.Equals, "subnet-1")
+
+	subnet, err = s.State.Subnet("10.0.0.0/24")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(subnet.SpaceName(), gc.Equals, "internal")
+	c.Assert(subnet.Life(), gc.Equals, state.Alive)
+}
+
+func (s *SpaceSuite) TestAddSpaceErrors(c *gc.C) {
+	_, err := s.State.AddSpace("Bad_Name", nil)
+	c.Assert(err, gc.ErrorMatches, `cannot add space "Bad_Name": space name "Bad_Name" not valid`)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+
+	_, err = s.State.AddSpace("internal", []string{"10.0.0.0"})
+	c.Assert(err, gc.ErrorMatches, `cannot add space "internal": subnet CIDR "10.0.0.0" not valid`)
+
+	_, err = s.State.AddSpace("internal", []string{"10.0.0.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.State.AddSpace("internal", nil)
+	c.Assert(err, gc.ErrorMatches, `cannot add space "internal": space "internal" already exists`)
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+
+	_, err = s.State.AddSpace("dmz", []string{"10.0.0.0/24"})
+	c.Assert(err, gc.ErrorMatches, `cannot add space "dmz": subnet "10.0.0.0/24" already in space "internal"`)
+	_, err = s.State.Space("dmz")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *SpaceSuite) TestSpaceNotFound(c *gc.C) {
+	_, err := s.State.Space("missing")
+	c.Assert(err, gc.ErrorMatches, `space "missing" not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *SpaceSuite) TestAllSpaces(c *gc.C) {
+	spaces, err := s.State.AllSpaces()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(spaces, gc.HasLen, 0)
+
+	for _, name := range []string{"public", "dmz", "internal"} {
+		_, err := s.State.AddSpace(name, nil)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	spaces, err = s.State.AllSpaces()
+	c.Assert(err, jc.ErrorIsNil)
+	var names []string
+	for _, space := range spaces {
+		names = append(names, space.Name())
+	}
+	c.Assert(names, jc.DeepEquals, []string{"dmz", "internal", "public"})
+}
+
+func (s *SpaceSuite) TestAddSubnets(c *gc.C) {
+	space, err := s.State.AddSpace("internal", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertSpaceCIDRs(c, "internal")
+
+	err = space.AddSubnets([]string{"10.0.1.0/24", "10.0.0.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertSpaceCIDRs(c, "internal", "10.0.0.0/24", "10.0.1.0/24")
+
+	// Adding subnets already in the space is a no-op.
+	err = space.AddSubnets([]string{"10.0.1.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.State.AddSpace("dmz", []string{"192.168.0.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = space.AddSubnets([]string{"10.0.2.0/24", "192.168.0.0/24"})
+	c.Assert(err, gc.ErrorMatches, `cannot add subnets to space "internal": subnet "192.168.0.0/24" already in space "dmz"`)
+	s.assertSpaceCIDRs(c, "internal", "10.0.0.0/24", "10.0.1.0/24")
+}
+
+func (s *SpaceSuite) TestEndpointBindings(c *gc.C) {
+	service := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	c.Assert(service.EndpointBindings(), gc.HasLen, 0)
+	c.Assert(service.EndpointBinding("db"), gc.Equals, "")
+
+	err := service.SetEndpointBindings(map[string]string{"db": "internal"})
+	c.Assert(err, gc.ErrorMatches, `cannot set endpoint bindings for service "wordpress": space "internal" not found`)
+
+	_, err = s.State.AddSpace("internal", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddSpace("public", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = service.SetEndpointBindings(map[string]string{"bad": "internal"})
+	c.Assert(err, gc.ErrorMatches, `cannot set endpoint bindings for service "wordpress": endpoint "bad" not found`)
+
+	bindings := map[string]string{"db": "internal", "": "public"}
+	err = service.SetEndpointBindings(bindings)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(service.EndpointBindings(), jc.DeepEquals, bindings)
+	c.Assert(service.EndpointBinding("db"), gc.Equals, "internal")
+	c.Assert(service.EndpointBinding("url"), gc.Equals, "public")
+
+	service, err = s.State.Service("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(service.EndpointBindings(), jc.DeepEquals, bindings)
+
+	err = service.SetEndpointBindings(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(service.EndpointBindings(), gc.HasLen, 0)
+}
+
+func (s *SpaceSuite) TestUnitAddresses(c *gc.C) {
+	service := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	unit, err := service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToMachine(machine)
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.SetProviderAddresses(
+		network.NewScopedAddress("8.8.8.8", network.ScopePublic),
+		network.NewScopedAddress("10.0.0.5", network.ScopeCloudLocal),
+		network.NewScopedAddress("192.168.0.5", network.ScopeCloudLocal),
+	)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.State.AddSpace("internal", []string{"192.168.0.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddSpace("empty", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	addr, err := unit.SpaceAddress("internal")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(addr.Value, gc.Equals, "192.168.0.5")
+	_, err = unit.SpaceAddress("empty")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	_, err = unit.EndpointAddress("db")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	address, ok := unit.PrivateAddress()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(address, gc.Equals, "10.0.0.5")
+
+	// Only the default binding affects the private address.
+	err = service.SetEndpointBindings(map[string]string{"db": "internal"})
+	c.Assert(err, jc.ErrorIsNil)
+	address, ok = unit.PrivateAddress()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(address, gc.Equals, "10.0.0.5")
+
+	err = service.SetEndpointBindings(map[string]string{"": "internal"})
+	c.Assert(err, jc.ErrorIsNil)
+	addr, err = unit.EndpointAddress("db")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(addr.Value, gc.Equals, "192.168.0.5")
+	address, ok = unit.PrivateAddress()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(address, gc.Equals, "192.168.0.5")
+}
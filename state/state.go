@@ -31,6 +31,7 @@ import (
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/state/leadership"
 	"github.com/juju/juju/state/lease"
+	"github.com/juju/juju/state/multiwatcher"
 	"github.com/juju/juju/state/presence"
 	"github.com/juju/juju/state/watcher"
 	"github.com/juju/juju/version"
@@ -292,12 +293,18 @@ func (st *State) MongoSession() *mgo.Session {
 type closeFunc func()
 
 func (st *State) Watch() *Multiwatcher {
+	return st.WatchFiltered(multiwatcher.Filter{})
+}
+
+// WatchFiltered returns a watcher of the environment changes selected
+// by the given filter.
+func (st *State) WatchFiltered(filter multiwatcher.Filter) *Multiwatcher {
 	st.mu.Lock()
 	if st.allManager == nil {
 		st.allManager = newStoreManager(newAllWatcherStateBacking(st))
 	}
 	st.mu.Unlock()
-	return NewMultiwatcher(st.allManager)
+	return NewFilteredMultiwatcher(st.allManager, filter)
 }
 
 func (st *State) EnvironConfig() (*config.Config, error) {
@@ -406,6 +413,13 @@ func IsUpgradeInProgressError(err error) bool {
 // running the current version). If this is a hosted environment, newVersion
 // cannot be higher than the state server version.
 func (st *State) SetEnvironAgentVersion(newVersion version.Number) (err error) {
+	return st.setEnvironAgentVersion(newVersion, 0)
+}
+
+// setEnvironAgentVersion changes the agent version for the environment.
+// If batchSize is positive, a rolling upgrade is started, otherwise any
+// rolling upgrade in progress is abandoned.
+func (st *State) setEnvironAgentVersion(newVersion version.Number, batchSize int) (err error) {
 	if newVersion.Compare(version.Current.Number) > 0 && !st.IsStateServer() {
 		return errors.Errorf("a hosted environment cannot have a higher version than the server environment: %s > %s",
 			newVersion.String(),
@@ -450,7 +464,11 @@ func (st *State) SetEnvironAgentVersion(newVersion version.Number) (err error) {
 				},
 			},
 		}
-		return ops, nil
+		rollingOps, err := st.rollingUpgradeOps(currentVersion, newVersion, batchSize)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return append(ops, rollingOps...), nil
 	}
 	if err = st.run(buildTxn); err == jujutxn.ErrExcessiveContention {
 		// Although there is a small chance of a race here, try to
@@ -1217,6 +1235,7 @@ func (st *State) AddSubnet(args SubnetInfo) (subnet *Subnet, err error) {
 		AllocatableIPHigh: args.AllocatableIPHigh,
 		AllocatableIPLow:  args.AllocatableIPLow,
 		AvailabilityZone:  args.AvailabilityZone,
+		SpaceName:         args.SpaceName,
 	}
 	subnet = &Subnet{doc: subDoc, st: st}
 	err = subnet.Validate()
@@ -1229,6 +1248,16 @@ func (st *State) AddSubnet(args SubnetInfo) (subnet *Subnet, err error) {
 		Assert: txn.DocMissing,
 		Insert: subDoc,
 	}}
+	if args.SpaceName != "" {
+		if _, err := st.Space(args.SpaceName); err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops = append(ops, txn.Op{
+			C:      spacesC,
+			Id:     st.docID(args.SpaceName),
+			Assert: txn.DocExists,
+		})
+	}
 
 	err = st.runTransaction(ops)
 	switch err {
@@ -1265,6 +1294,23 @@ func (st *State) Subnet(cidr string) (*Subnet, error) {
 	return &Subnet{st, *doc}, nil
 }
 
+// AllSubnets returns all the subnets in the environment, ordered by
+// CIDR.
+func (st *State) AllSubnets() ([]*Subnet, error) {
+	subnetsCollection, closer := st.getCollection(subnetsC)
+	defer closer()
+
+	var docs []subnetDoc
+	if err := subnetsCollection.Find(nil).Sort("cidr").All(&docs); err != nil {
+		return nil, errors.Annotatef(err, "cannot get all subnets")
+	}
+	subnets := make([]*Subnet, len(docs))
+	for i, doc := range docs {
+		subnets[i] = &Subnet{st, doc}
+	}
+	return subnets, nil
+}
+
 // AddNetwork creates a new network with the given params. If a
 // network with the same name or provider id already exists in state,
 // an error satisfying errors.IsAlreadyExists is returned.
@@ -1752,7 +1798,9 @@ func (st *State) UnitsFor(machineId string) ([]*Unit, error) {
 
 // AssignUnit places the unit on a machine. Depending on the policy, and the
 // state of the environment, this may lead to new instances being launched
-// within the environment.
+// within the environment. Except under AssignLocal, a spare machine of a
+// machine pool that satisfies the unit's constraints is preferred over
+// any other clean or new machine.
 func (st *State) AssignUnit(u *Unit, policy AssignmentPolicy) (err error) {
 	if !u.IsPrincipal() {
 		return errors.Errorf("subordinate unit %q cannot be assigned directly to a machine", u)
@@ -1767,16 +1815,33 @@ func (st *State) AssignUnit(u *Unit, policy AssignmentPolicy) (err error) {
 		}
 		return u.AssignToMachine(m)
 	case AssignClean:
+		if _, err = u.assignToAffineMachine(); err != noAffineMachines {
+			return errors.Trace(err)
+		}
+		if _, err = u.assignToSpareMachine(); err != noCleanMachines {
+			return errors.Trace(err)
+		}
 		if _, err = u.AssignToCleanMachine(); err != noCleanMachines {
 			return errors.Trace(err)
 		}
 		return u.AssignToNewMachineOrContainer()
 	case AssignCleanEmpty:
+		if _, err = u.assignToAffineMachine(); err != noAffineMachines {
+			return errors.Trace(err)
+		}
+		if _, err = u.assignToSpareMachine(); err != noCleanMachines {
+			return errors.Trace(err)
+		}
 		if _, err = u.AssignToCleanEmptyMachine(); err != noCleanMachines {
 			return errors.Trace(err)
 		}
 		return u.AssignToNewMachineOrContainer()
 	case AssignNew:
+		// A spare machine is as good as a new one, and
+		// already running.
+		if _, err = u.assignToSpareMachine(); err != noCleanMachines {
+			return errors.Trace(err)
+		}
 		return errors.Trace(u.AssignToNewMachine())
 	}
 	return errors.Errorf("unknown unit assignment policy: %q", policy)
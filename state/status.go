@@ -287,14 +287,14 @@ func newHistoricalStatusDoc(id int, s statusDoc, entityId string) *historicalSta
 func updateStatusHistory(oldDoc statusDoc, globalKey string, st *State) error {
 	id, err := st.sequence("statushistory")
 	if err != nil {
-		errors.Annotatef(err, "cannot make id updating status history of unit agent %q", globalKey)
+		return errors.Annotatef(err, "cannot make id updating status history of %q", globalKey)
 	}
 	hDoc := newHistoricalStatusDoc(id, oldDoc, globalKey)
 	history, closer := st.getCollection(statusesHistoryC)
 	defer closer()
 	historyW := history.Writeable()
 	err = historyW.Insert(hDoc)
-	return errors.Annotatef(err, "cannot update status history of unit agent %q", globalKey)
+	return errors.Annotatef(err, "cannot update status history of %q", globalKey)
 }
 
 func statusHistory(size int, globalKey string, st *State) ([]StatusInfo, error) {
@@ -53,6 +53,18 @@ func (s *SubnetSuite) TestAddSubnet(c *gc.C) {
 	assertSubnet(subnetFromDB)
 }
 
+func (s *SubnetSuite) TestAllSubnets(c *gc.C) {
+	for _, cidr := range []string{"192.168.2.0/24", "192.168.1.0/24"} {
+		_, err := s.State.AddSubnet(state.SubnetInfo{CIDR: cidr})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	subnets, err := s.State.AllSubnets()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(subnets, gc.HasLen, 2)
+	c.Assert(subnets[0].CIDR(), gc.Equals, "192.168.1.0/24")
+	c.Assert(subnets[1].CIDR(), gc.Equals, "192.168.2.0/24")
+}
+
 func (s *SubnetSuite) TestAddSubnetErrors(c *gc.C) {
 	subnetInfo := state.SubnetInfo{}
 	_, err := s.State.AddSubnet(subnetInfo)
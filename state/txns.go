@@ -5,6 +5,7 @@ package state
 
 import (
 	"reflect"
+	"sync/atomic"
 
 	"github.com/juju/errors"
 	jujutxn "github.com/juju/txn"
@@ -17,11 +18,52 @@ const (
 	txnAssertEnvIsNotAlive = false
 )
 
+// TxnStats holds counts of the transactions run by all the States of
+// the process.
+type TxnStats struct {
+	// Runs is the number of transactions run, not counting retries.
+	Runs int64
+
+	// Retries is the number of times transactions were rebuilt and
+	// run again after their assertions failed.
+	Retries int64
+
+	// Aborted is the number of transactions that failed their
+	// assertions without being retried, or that gave up after too
+	// many retries.
+	Aborted int64
+}
+
+// txnStats holds the counts returned by GetTxnStats. Its fields must be
+// accessed atomically.
+var txnStats TxnStats
+
+// GetTxnStats returns counts of the transactions run so far by all the
+// States of the process.
+func GetTxnStats() TxnStats {
+	return TxnStats{
+		Runs:    atomic.LoadInt64(&txnStats.Runs),
+		Retries: atomic.LoadInt64(&txnStats.Retries),
+		Aborted: atomic.LoadInt64(&txnStats.Aborted),
+	}
+}
+
+// countTxn adds a transaction to the counts returned by GetTxnStats,
+// given the error it returned.
+func countTxn(err error) {
+	atomic.AddInt64(&txnStats.Runs, 1)
+	if err == txn.ErrAborted || err == jujutxn.ErrExcessiveContention {
+		atomic.AddInt64(&txnStats.Aborted, 1)
+	}
+}
+
 // runTransaction is a convenience method delegating to the state's Database.
 func (st *State) runTransaction(ops []txn.Op) error {
 	runner, closer := st.database.TransactionRunner()
 	defer closer()
-	return runner.RunTransaction(ops)
+	err := runner.RunTransaction(ops)
+	countTxn(err)
+	return err
 }
 
 // runRawTransaction is a convenience method that will run a single
@@ -33,14 +75,23 @@ func (st *State) runRawTransaction(ops []txn.Op) error {
 	if multiRunner, ok := runner.(*multiEnvRunner); ok {
 		runner = multiRunner.rawRunner
 	}
-	return runner.RunTransaction(ops)
+	err := runner.RunTransaction(ops)
+	countTxn(err)
+	return err
 }
 
 // run is a convenience method delegating to the state's Database.
 func (st *State) run(transactions jujutxn.TransactionSource) error {
 	runner, closer := st.database.TransactionRunner()
 	defer closer()
-	return runner.Run(transactions)
+	err := runner.Run(func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			atomic.AddInt64(&txnStats.Retries, 1)
+		}
+		return transactions(attempt)
+	})
+	countTxn(err)
+	return err
 }
 
 // ResumeTransactions resumes all pending transactions.
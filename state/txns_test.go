@@ -5,6 +5,7 @@ package state
 
 import (
 	"errors"
+	"strings"
 
 	jc "github.com/juju/testing/checkers"
 	jujutxn "github.com/juju/txn"
@@ -533,3 +534,33 @@ func (r *recordingRunner) MaybePruneTransactions(float32) error {
 	r.pruneTransactionsCalled = true
 	return r.pruneTransactionsErr
 }
+
+type txnStatsSuite struct {
+	internalStateSuite
+}
+
+var _ = gc.Suite(&txnStatsSuite{})
+
+func (s *txnStatsSuite) TestTxnStats(c *gc.C) {
+	owner := strings.ToLower(s.owner.Name())
+	before := GetTxnStats()
+	// The first attempt fails its assertion and is retried.
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		assert := txn.DocMissing
+		if attempt > 0 {
+			assert = txn.DocExists
+		}
+		return []txn.Op{{C: usersC, Id: owner, Assert: assert}}, nil
+	}
+	err := s.state.run(buildTxn)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.state.runTransaction([]txn.Op{{C: usersC, Id: owner, Assert: txn.DocMissing}})
+	c.Assert(err, gc.Equals, txn.ErrAborted)
+	after := GetTxnStats()
+
+	// The counts are shared by all the States of the process, some of
+	// which may be running transactions of their own.
+	c.Check(after.Runs-before.Runs >= 2, jc.IsTrue)
+	c.Check(after.Retries-before.Retries >= 1, jc.IsTrue)
+	c.Check(after.Aborted-before.Aborted >= 1, jc.IsTrue)
+}
@@ -6,6 +6,9 @@ package state
 import (
 	stderrors "errors"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/juju/errors"
@@ -170,6 +173,26 @@ func (u *Unit) Name() string {
 	return u.doc.Name
 }
 
+// SortUnitsByNumber sorts the units by the numbers in their names,
+// that is, in the order they were added to their services.
+func SortUnitsByNumber(units []*Unit) {
+	sort.Sort(unitsByNumber(units))
+}
+
+type unitsByNumber []*Unit
+
+func (u unitsByNumber) Len() int      { return len(u) }
+func (u unitsByNumber) Swap(i, j int) { u[i], u[j] = u[j], u[i] }
+func (u unitsByNumber) Less(i, j int) bool {
+	return unitNumber(u[i].Name()) < unitNumber(u[j].Name())
+}
+
+// unitNumber returns the number of the named unit.
+func unitNumber(unitName string) int {
+	number, _ := strconv.Atoi(unitName[strings.Index(unitName, "/")+1:])
+	return number
+}
+
 // unitGlobalKey returns the global database key for the named unit.
 func unitGlobalKey(name string) string {
 	return "u#" + name + "#charm"
@@ -735,7 +758,9 @@ func (u *Unit) machine() (*Machine, error) {
 // addressesOfMachine returns Addresses of the related machine if present.
 func (u *Unit) addressesOfMachine() []network.Address {
 	m, err := u.machine()
-	if err != nil {
+	if errors.IsNotAssigned(errors.Cause(err)) {
+		return nil
+	} else if err != nil {
 		unitLogger.Errorf("%v", err)
 		return nil
 	}
@@ -753,15 +778,68 @@ func (u *Unit) PublicAddress() (string, bool) {
 }
 
 // PrivateAddress returns the private address of the unit and whether it is valid.
+// If the unit's service has a default space binding, the address in that
+// space is preferred.
 func (u *Unit) PrivateAddress() (string, bool) {
-	var privateAddress string
 	addresses := u.addressesOfMachine()
-	if len(addresses) > 0 {
-		privateAddress = network.SelectInternalAddress(addresses, false)
+	if len(addresses) == 0 {
+		return "", false
+	}
+	if addr, ok := u.defaultSpaceAddress(addresses); ok {
+		return addr.Value, true
 	}
+	privateAddress := network.SelectInternalAddress(addresses, false)
 	return privateAddress, privateAddress != ""
 }
 
+// defaultSpaceAddress returns the one of the given addresses that is
+// in the default space of the unit's service, and whether there is
+// one. Services without a default space binding have none.
+func (u *Unit) defaultSpaceAddress(addresses []network.Address) (network.Address, bool) {
+	spaceName, err := u.defaultSpace()
+	if err != nil {
+		unitLogger.Warningf("cannot get default space of unit %q: %v", u.Name(), err)
+		return network.Address{}, false
+	}
+	if spaceName == "" {
+		return network.Address{}, false
+	}
+	space, err := u.st.Space(spaceName)
+	if err != nil {
+		unitLogger.Warningf("cannot get space %q of unit %q: %v", spaceName, u.Name(), err)
+		return network.Address{}, false
+	}
+	cidrs, err := space.CIDRs()
+	if err != nil {
+		unitLogger.Warningf("cannot get subnets of space %q: %v", spaceName, err)
+		return network.Address{}, false
+	}
+	return network.SelectAddressInSubnets(addresses, cidrs)
+}
+
+// defaultSpace returns the default space of the unit's service, or
+// the empty string if it has none. PrivateAddress is called often, so
+// only the endpoint bindings of services that have them are read.
+func (u *Unit) defaultSpace() (string, error) {
+	services, closer := u.st.getCollection(servicesC)
+	defer closer()
+
+	var doc struct {
+		EndpointBindings map[string]string `bson:"endpointbindings"`
+	}
+	sel := bson.D{
+		{"_id", u.doc.Service},
+		{"endpointbindings", bson.D{{"$exists", true}}},
+	}
+	err := services.Find(sel).Select(bson.D{{"endpointbindings", 1}}).One(&doc)
+	if err == mgo.ErrNotFound {
+		return "", nil
+	} else if err != nil {
+		return "", errors.Trace(err)
+	}
+	return doc.EndpointBindings[""], nil
+}
+
 // AvailabilityZone returns the name of the availability zone into which
 // the unit's machine instance was provisioned.
 func (u *Unit) AvailabilityZone() (string, error) {
@@ -1145,6 +1223,7 @@ var (
 // - unitNotAliveErr when the unit is not alive.
 // - alreadyAssignedErr when the unit has already been assigned
 // - inUseErr when the machine already has a unit assigned (if unused is true)
+// - *placementViolationError when the assignment would violate a placement policy
 func (u *Unit) assignToMachine(m *Machine, unused bool) (err error) {
 	originalm := m
 	buildTxn := func(attempt int) ([]txn.Op, error) {
@@ -1198,6 +1277,9 @@ func (u *Unit) assignToMachineOps(m *Machine, unused bool) ([]txn.Op, error) {
 	if !canHost {
 		return nil, fmt.Errorf("machine %q cannot host units", m)
 	}
+	if err := u.checkPlacementPolicy(m); err != nil {
+		return nil, err
+	}
 	// assignToMachine implies assignment to an existing machine,
 	// which is only permitted if unit placement is supported.
 	if err := u.st.supportsUnitPlacement(); err != nil {
@@ -1237,6 +1319,9 @@ func (u *Unit) assignToMachineOps(m *Machine, unused bool) ([]txn.Op, error) {
 	if unused {
 		massert = append(massert, bson.D{{"clean", bson.D{{"$ne", false}}}}...)
 	}
+	// Placement policies were checked against the machine's current
+	// principals.
+	massert = append(massert, principalsUnchangedDoc(m.doc.Principals)...)
 	ops := []txn.Op{{
 		C:      unitsC,
 		Id:     u.doc.DocID,
@@ -1354,6 +1439,11 @@ func (u *Unit) AssignToMachine(m *Machine) (err error) {
 // assignToNewMachine assigns the unit to a machine created according to
 // the supplied params, with the supplied constraints.
 func (u *Unit) assignToNewMachine(template MachineTemplate, parentId string, containerType instance.ContainerType) error {
+	// A new machine hosts no units of the services the unit's
+	// service may have affinity with.
+	if err := u.checkNewMachinePlacementPolicy(); err != nil {
+		return err
+	}
 	template.principals = []string{u.doc.Name}
 	template.Dirty = true
 
@@ -1484,7 +1574,7 @@ func (u *Unit) AssignToNewMachineOrContainer() (err error) {
 	hostCons := *cons
 	noContainer := instance.NONE
 	hostCons.Container = &noContainer
-	query, err := u.findCleanMachineQuery(true, &hostCons)
+	query, err := u.findCleanMachineQuery(true, false, &hostCons)
 	if err != nil {
 		return err
 	}
@@ -1733,7 +1823,7 @@ var noCleanMachines = stderrors.New("all eligible machines in use")
 // This method does not take constraints into consideration when choosing a
 // machine (lp:1161919).
 func (u *Unit) AssignToCleanMachine() (m *Machine, err error) {
-	return u.assignToCleanMaybeEmptyMachine(false)
+	return u.assignToCleanMaybeEmptyMachine(false, false)
 }
 
 // AssignToCleanEmptyMachine assigns u to a machine which is marked as clean and is also
@@ -1743,7 +1833,16 @@ func (u *Unit) AssignToCleanMachine() (m *Machine, err error) {
 // This method does not take constraints into consideration when choosing a
 // machine (lp:1161919).
 func (u *Unit) AssignToCleanEmptyMachine() (m *Machine, err error) {
-	return u.assignToCleanMaybeEmptyMachine(true)
+	return u.assignToCleanMaybeEmptyMachine(true, false)
+}
+
+// assignToSpareMachine assigns u to a spare machine of any machine
+// pool that satisfies the unit's constraints, preferring provisioned
+// machines. Units with constraints that cannot be checked against a
+// spare, such as instance-type, are never assigned to one. If there is
+// no suitable machine, noCleanMachines is returned.
+func (u *Unit) assignToSpareMachine() (m *Machine, err error) {
+	return u.assignToCleanMaybeEmptyMachine(true, true)
 }
 
 var hasContainerTerm = bson.DocElem{
@@ -1758,9 +1857,21 @@ var hasNoContainersTerm = bson.DocElem{
 		{{"children", bson.D{{"$exists", false}}}},
 	}}
 
+// hasUncheckedConstraints reports whether cons holds constraints that
+// findCleanMachineQuery cannot check against existing machines.
+func hasUncheckedConstraints(cons *constraints.Value) bool {
+	return cons.HasInstanceType() ||
+		cons.HasVirtType() ||
+		cons.MinAccelerators() > 0 ||
+		(cons.LocalDisks != nil && *cons.LocalDisks > 0) ||
+		cons.HaveNetworks() ||
+		cons.HaveSpaces()
+}
+
 // findCleanMachineQuery returns a Mongo query to find clean (and possibly empty) machines with
-// characteristics matching the specified constraints.
-func (u *Unit) findCleanMachineQuery(requireEmpty bool, cons *constraints.Value) (bson.D, error) {
+// characteristics matching the specified constraints. If spare is true, only the spare machines
+// of machine pools are found.
+func (u *Unit) findCleanMachineQuery(requireEmpty, spare bool, cons *constraints.Value) (bson.D, error) {
 	db, closer := u.st.newDB()
 	defer closer()
 	containerRefsCollection, closer := db.GetCollection(containerRefsC)
@@ -1836,19 +1947,47 @@ func (u *Unit) findCleanMachineQuery(requireEmpty bool, cons *constraints.Value)
 		for i, m := range suitableInstanceData {
 			suitableIds[i] = m.DocID
 		}
+		if spare {
+			// Spare machines are started with the constraints of
+			// their pool, so unprovisioned spares are suitable if
+			// those constraints satisfy the unit's.
+			constraintsCollection, closer := db.GetCollection(constraintsC)
+			defer closer()
+			var suitableConstraints []struct {
+				DocID string `bson:"_id"`
+			}
+			err := constraintsCollection.Find(suitableTerms).Select(bson.M{"_id": 1}).All(&suitableConstraints)
+			if err != nil {
+				return nil, err
+			}
+			for _, doc := range suitableConstraints {
+				globalKey := u.st.localID(doc.DocID)
+				if strings.HasPrefix(globalKey, machineGlobalKey("")) {
+					suitableIds = append(suitableIds, u.st.docID(strings.TrimPrefix(globalKey, machineGlobalKey(""))))
+				}
+			}
+		}
 		terms = append(terms, bson.DocElem{"_id", bson.D{{"$in", suitableIds}}})
 	}
+	if spare {
+		terms = append(terms, bson.DocElem{"pool", bson.D{{"$exists", true}}})
+	}
 	return terms, nil
 }
 
-// assignToCleanMaybeEmptyMachine implements AssignToCleanMachine and AssignToCleanEmptyMachine.
-// A 'machine' may be a machine instance or container depending on the service constraints.
-func (u *Unit) assignToCleanMaybeEmptyMachine(requireEmpty bool) (m *Machine, err error) {
+// assignToCleanMaybeEmptyMachine implements AssignToCleanMachine, AssignToCleanEmptyMachine
+// and assignToSpareMachine; if spare is true, only the spare machines of machine pools are
+// considered. A 'machine' may be a machine instance or container depending on the service
+// constraints.
+func (u *Unit) assignToCleanMaybeEmptyMachine(requireEmpty, spare bool) (m *Machine, err error) {
 	context := "clean"
 	if requireEmpty {
 		context += ", empty"
 	}
 	context += " machine"
+	if spare {
+		context = "spare machine"
+	}
 
 	if u.doc.Principal != "" {
 		err = fmt.Errorf("unit is a subordinate")
@@ -1877,7 +2016,11 @@ func (u *Unit) assignToCleanMaybeEmptyMachine(requireEmpty bool) (m *Machine, er
 		assignContextf(&err, u, context)
 		return nil, err
 	}
-	query, err := u.findCleanMachineQuery(requireEmpty, cons)
+	if spare && hasUncheckedConstraints(cons) {
+		// The unit may need a machine unlike any spare.
+		return nil, noCleanMachines
+	}
+	query, err := u.findCleanMachineQuery(requireEmpty, spare, cons)
 	if err != nil {
 		assignContextf(&err, u, context)
 		return nil, err
@@ -1955,7 +2098,7 @@ func (u *Unit) assignToCleanMaybeEmptyMachine(requireEmpty bool) (m *Machine, er
 		if err == nil {
 			return m, nil
 		}
-		if err != inUseErr && err != machineNotAliveErr {
+		if err != inUseErr && err != machineNotAliveErr && !isPlacementViolation(err) {
 			assignContextf(&err, u, context)
 			return nil, err
 		}
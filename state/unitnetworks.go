@@ -0,0 +1,217 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"net"
+	"sort"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/juju/juju/network"
+)
+
+// UnitNetworkInterface describes a network interface of a unit's
+// machine, and the addresses on it.
+type UnitNetworkInterface struct {
+	// InterfaceName is the OS-specific name of the interface (e.g.
+	// "eth0"). It is empty when the address is not known to be on
+	// any particular interface.
+	InterfaceName string
+
+	// MACAddress is the hardware address of the interface, if known.
+	MACAddress string
+
+	// Addresses holds the addresses on the interface.
+	Addresses []UnitNetworkAddress
+}
+
+// UnitNetworkAddress describes an address of a unit's machine, and the
+// subnet it is in.
+type UnitNetworkAddress struct {
+	// Value is the address itself (e.g. "10.0.0.5").
+	Value string
+
+	// CIDR is the CIDR of the subnet the address is in. It is empty
+	// when the subnet is not known.
+	CIDR string
+}
+
+// NetworkInfo returns the network interfaces and addresses of the
+// unit's machine on the network the named endpoint uses; the empty
+// endpoint name refers to the service's default network. If the
+// endpoint is bound to a space, only addresses in the space's subnets
+// are returned; otherwise all but machine-local addresses are.
+func (u *Unit) NetworkInfo(endpoint string) ([]UnitNetworkInterface, error) {
+	service, err := u.Service()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if endpoint != "" {
+		if _, err := service.Endpoint(endpoint); err != nil {
+			return nil, errors.NotFoundf("endpoint %q of service %q", endpoint, service.Name())
+		}
+	}
+	var spaceCIDRs []string
+	spaceName := service.EndpointBinding(endpoint)
+	if spaceName != "" {
+		space, err := u.st.Space(spaceName)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if spaceCIDRs, err = space.CIDRs(); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	m, err := u.machine()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	knownCIDRs, err := allSubnetCIDRs(u.st)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	var result []UnitNetworkInterface
+	byMAC := make(map[string]int)
+	// byNetworkCIDR holds the interface on each network with a known
+	// CIDR, used to place machine addresses not allocated by Juju.
+	byNetworkCIDR := make(map[string]int)
+	var networkCIDRs []string
+	ifaces, err := m.NetworkInterfaces()
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get network interfaces of machine %q", m.Id())
+	}
+	for _, iface := range ifaces {
+		if iface.IsDisabled() {
+			continue
+		}
+		// Addresses are matched to interfaces by MAC address, which
+		// virtual interfaces share with their physical interface.
+		if _, ok := byMAC[iface.MACAddress()]; !ok || !iface.IsVirtual() {
+			byMAC[iface.MACAddress()] = len(result)
+		}
+		if nw, err := u.st.Network(iface.NetworkName()); err == nil && nw.CIDR() != "" {
+			byNetworkCIDR[nw.CIDR()] = len(result)
+			networkCIDRs = append(networkCIDRs, nw.CIDR())
+		}
+		result = append(result, UnitNetworkInterface{
+			InterfaceName: iface.InterfaceName(),
+			MACAddress:    iface.MACAddress(),
+		})
+	}
+
+	knownCIDRs = append(knownCIDRs, networkCIDRs...)
+
+	include := func(addr network.Address) bool {
+		if spaceName != "" {
+			return cidrContaining(addr.Value, spaceCIDRs) != ""
+		}
+		return addr.Scope != network.ScopeMachineLocal
+	}
+	seen := make(map[string]bool)
+	add := func(i int, addr network.Address) {
+		seen[addr.Value] = true
+		result[i].Addresses = append(result[i].Addresses, UnitNetworkAddress{
+			Value: addr.Value,
+			CIDR:  cidrContaining(addr.Value, knownCIDRs),
+		})
+	}
+	unknown := -1
+	addUnknown := func(addr network.Address) {
+		if unknown < 0 {
+			unknown = len(result)
+			result = append(result, UnitNetworkInterface{})
+		}
+		add(unknown, addr)
+	}
+
+	ipAddresses, err := u.st.AllocatedIPAddresses(m.Id())
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get IP addresses of machine %q", m.Id())
+	}
+	for _, ipAddress := range ipAddresses {
+		addr := ipAddress.Address()
+		if !include(addr) || seen[addr.Value] {
+			continue
+		}
+		i, ok := byMAC[ipAddress.MACAddress()]
+		if !ok {
+			i = len(result)
+			byMAC[ipAddress.MACAddress()] = i
+			result = append(result, UnitNetworkInterface{
+				MACAddress: ipAddress.MACAddress(),
+			})
+		}
+		add(i, addr)
+	}
+	for _, addr := range m.Addresses() {
+		if !include(addr) || seen[addr.Value] {
+			continue
+		}
+		if i, ok := byNetworkCIDR[cidrContaining(addr.Value, networkCIDRs)]; ok {
+			add(i, addr)
+		} else {
+			addUnknown(addr)
+		}
+	}
+
+	// Only report interfaces with addresses on the endpoint's network.
+	interfaces := make([]UnitNetworkInterface, 0, len(result))
+	for _, iface := range result {
+		if len(iface.Addresses) > 0 {
+			interfaces = append(interfaces, iface)
+		}
+	}
+	sort.Sort(unitNetworkInterfaces(interfaces))
+	return interfaces, nil
+}
+
+// allSubnetCIDRs returns the CIDRs of all the subnets known to state.
+func allSubnetCIDRs(st *State) ([]string, error) {
+	subnets, closer := st.getCollection(subnetsC)
+	defer closer()
+
+	var docs []struct {
+		CIDR string `bson:"cidr"`
+	}
+	if err := subnets.Find(nil).Select(bson.D{{"cidr", 1}}).All(&docs); err != nil {
+		return nil, errors.Annotatef(err, "cannot get subnets")
+	}
+	cidrs := make([]string, len(docs))
+	for i, doc := range docs {
+		cidrs[i] = doc.CIDR
+	}
+	return cidrs, nil
+}
+
+// cidrContaining returns the first of the given CIDRs that contains
+// the given IP address, or the empty string if none does.
+func cidrContaining(value string, cidrs []string) string {
+	ip := net.ParseIP(value)
+	if ip == nil {
+		return ""
+	}
+	for _, cidr := range cidrs {
+		if _, ipNet, err := net.ParseCIDR(cidr); err == nil && ipNet.Contains(ip) {
+			return cidr
+		}
+	}
+	return ""
+}
+
+// unitNetworkInterfaces sorts interfaces by name, with unnamed
+// interfaces last.
+type unitNetworkInterfaces []UnitNetworkInterface
+
+func (s unitNetworkInterfaces) Len() int      { return len(s) }
+func (s unitNetworkInterfaces) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s unitNetworkInterfaces) Less(i, j int) bool {
+	a, b := s[i].InterfaceName, s[j].InterfaceName
+	if a == "" || b == "" {
+		return a != ""
+	}
+	return a < b
+}
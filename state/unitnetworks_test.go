@@ -0,0 +1,85 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+)
+
+type UnitNetworksSuite struct {
+	ConnSuite
+	service *state.Service
+	unit    *state.Unit
+}
+
+var _ = gc.Suite(&UnitNetworksSuite{})
+
+func (s *UnitNetworksSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.service = s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	var err error
+	s.unit, err = s.service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.AssignToMachine(machine)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.State.AddNetwork(state.NetworkInfo{"net1", "net1", "10.0.0.0/24", 0})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = machine.AddNetworkInterface(state.NetworkInterfaceInfo{
+		MACAddress:    "aa:bb:cc:dd:ee:ff",
+		InterfaceName: "eth0",
+		NetworkName:   "net1",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddSpace("internal", []string{"192.168.0.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = machine.SetProviderAddresses(
+		network.NewScopedAddress("10.0.0.5", network.ScopeCloudLocal),
+		network.NewScopedAddress("192.168.0.5", network.ScopeCloudLocal),
+		network.NewScopedAddress("127.0.0.1", network.ScopeMachineLocal),
+	)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *UnitNetworksSuite) TestNetworkInfoUnbound(c *gc.C) {
+	info, err := s.unit.NetworkInfo("db")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info, jc.DeepEquals, []state.UnitNetworkInterface{{
+		InterfaceName: "eth0",
+		MACAddress:    "aa:bb:cc:dd:ee:ff",
+		Addresses:     []state.UnitNetworkAddress{{"10.0.0.5", "10.0.0.0/24"}},
+	}, {
+		Addresses: []state.UnitNetworkAddress{{"192.168.0.5", "192.168.0.0/24"}},
+	}})
+}
+
+func (s *UnitNetworksSuite) TestNetworkInfoBound(c *gc.C) {
+	err := s.service.SetEndpointBindings(map[string]string{"db": "internal"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	info, err := s.unit.NetworkInfo("db")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info, jc.DeepEquals, []state.UnitNetworkInterface{{
+		Addresses: []state.UnitNetworkAddress{{"192.168.0.5", "192.168.0.0/24"}},
+	}})
+
+	// Other endpoints are unaffected by the binding.
+	info, err = s.unit.NetworkInfo("url")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info, gc.HasLen, 2)
+}
+
+func (s *UnitNetworksSuite) TestNetworkInfoUnknownEndpoint(c *gc.C) {
+	_, err := s.unit.NetworkInfo("missing")
+	c.Assert(err, gc.ErrorMatches, `endpoint "missing" of service "wordpress" not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
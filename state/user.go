@@ -4,7 +4,10 @@
 // NOTE: the users that are being stored in the database here are only
 // the local users, like "admin" or "bob" (@local).  In the  world
 // where we have external user providers hooked up, there are no records
-// in the databse for users that are authenticated elsewhere.
+// in the databse for users that are authenticated elsewhere. Local users
+// that authenticate against an external identity provider configured
+// for the state server (LDAP or OpenID Connect) do have records, added
+// on their first login and marked as external.
 
 package state
 
@@ -17,9 +20,12 @@ import (
 	"github.com/juju/errors"
 	"github.com/juju/names"
 	"github.com/juju/utils"
+	"github.com/juju/utils/set"
 	"gopkg.in/mgo.v2"
 	"gopkg.in/mgo.v2/bson"
 	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/environs/config"
 )
 
 const (
@@ -40,33 +46,46 @@ func (st *State) checkUserExists(name string) (bool, error) {
 
 // AddUser adds a user to the database.
 func (st *State) AddUser(name, displayName, password, creator string) (*User, error) {
-	if !names.IsValidUserName(name) {
-		return nil, errors.Errorf("invalid user name %q", name)
-	}
 	salt, err := utils.RandomSalt()
 	if err != nil {
 		return nil, err
 	}
-	nameToLower := strings.ToLower(name)
-	user := &User{
-		st: st,
-		doc: userDoc{
-			DocID:        nameToLower,
-			Name:         name,
-			DisplayName:  displayName,
-			PasswordHash: utils.UserPasswordHash(password, salt),
-			PasswordSalt: salt,
-			CreatedBy:    creator,
-			DateCreated:  nowToTheSecond(),
-		},
+	return st.addUser(userDoc{
+		Name:         name,
+		DisplayName:  displayName,
+		PasswordHash: utils.UserPasswordHash(password, salt),
+		PasswordSalt: salt,
+		CreatedBy:    creator,
+	})
+}
+
+// AddExternalUser adds a user authenticated by an external identity
+// provider to the database. External users have no password stored in
+// Juju, so they cannot log in with a local password.
+func (st *State) AddExternalUser(name, displayName, creator string) (*User, error) {
+	return st.addUser(userDoc{
+		Name:        name,
+		DisplayName: displayName,
+		CreatedBy:   creator,
+		External:    true,
+	})
+}
+
+func (st *State) addUser(doc userDoc) (*User, error) {
+	if !names.IsValidUserName(doc.Name) {
+		return nil, errors.Errorf("invalid user name %q", doc.Name)
 	}
+	nameToLower := strings.ToLower(doc.Name)
+	doc.DocID = nameToLower
+	doc.DateCreated = nowToTheSecond()
+	user := &User{st: st, doc: doc}
 	ops := []txn.Op{{
 		C:      usersC,
 		Id:     nameToLower,
 		Assert: txn.DocMissing,
 		Insert: &user.doc,
 	}}
-	err = st.runTransaction(ops)
+	err := st.runTransaction(ops)
 	if err == txn.ErrAborted {
 		err = errors.AlreadyExistsf("user")
 	}
@@ -173,6 +192,21 @@ type userDoc struct {
 	// It is really informational only as far as everyone except the
 	// api server is concerned.
 	LastLogin *time.Time `bson:"lastlogin"`
+	// External is true for users authenticated by an external
+	// identity provider rather than by a password stored here.
+	External bool `bson:"external,omitempty"`
+	// PasswordChanged is when the password was last set. Users
+	// added before it was recorded use DateCreated instead.
+	PasswordChanged *time.Time `bson:"passwordchanged,omitempty"`
+	// FailedLogins counts the consecutive failed logins since the
+	// last successful login or lockout.
+	FailedLogins int `bson:"failedlogins,omitempty"`
+	// LockedUntil is when the user's lockout after too many failed
+	// logins ends.
+	LockedUntil *time.Time `bson:"lockeduntil,omitempty"`
+	// Groups holds the names of the groups the user is a member
+	// of. Permissions granted to a group apply to its members.
+	Groups []string `bson:"groups,omitempty"`
 }
 
 // String returns "<name>@local" where <name> is the Name of the user.
@@ -200,6 +234,12 @@ func (u *User) DateCreated() time.Time {
 	return u.doc.DateCreated.UTC()
 }
 
+// IsExternal returns whether the User is authenticated by an external
+// identity provider.
+func (u *User) IsExternal() bool {
+	return u.doc.External
+}
+
 // Tag returns the Tag for the User.
 func (u *User) Tag() names.Tag {
 	return u.UserTag()
@@ -275,20 +315,64 @@ func (u *User) SetPassword(password string) error {
 
 // SetPasswordHash stores the hash and the salt of the password.
 func (u *User) SetPasswordHash(pwHash string, pwSalt string) error {
+	return u.setPasswordHash(pwHash, pwSalt, true)
+}
+
+// setPasswordHash stores the hash and the salt of the password and, if
+// changed is true, records that the password was changed now.
+func (u *User) setPasswordHash(pwHash string, pwSalt string, changed bool) error {
+	if u.doc.External {
+		return errors.Errorf("cannot set password of user %q: user is authenticated externally", u.Name())
+	}
+	update := bson.D{{"passwordhash", pwHash}, {"passwordsalt", pwSalt}}
+	timestamp := nowToTheSecond()
+	if changed {
+		update = append(update, bson.DocElem{"passwordchanged", timestamp})
+	}
 	ops := []txn.Op{{
 		C:      usersC,
 		Id:     u.Name(),
 		Assert: txn.DocExists,
-		Update: bson.D{{"$set", bson.D{{"passwordhash", pwHash}, {"passwordsalt", pwSalt}}}},
+		Update: bson.D{{"$set", update}},
 	}}
 	if err := u.st.runTransaction(ops); err != nil {
 		return errors.Annotatef(err, "cannot set password of user %q", u.Name())
 	}
 	u.doc.PasswordHash = pwHash
 	u.doc.PasswordSalt = pwSalt
+	if changed {
+		u.doc.PasswordChanged = &timestamp
+	}
 	return nil
 }
 
+// PasswordChanged returns when the password of the User was last set,
+// in UTC.
+func (u *User) PasswordChanged() time.Time {
+	if u.doc.PasswordChanged == nil {
+		return u.DateCreated()
+	}
+	return u.doc.PasswordChanged.UTC()
+}
+
+// PasswordExpired returns whether the password of the User is older than
+// the maximum age allowed by the password policy of the state server,
+// so that it must be changed. The passwords of external users never
+// expire here.
+func (u *User) PasswordExpired() (bool, error) {
+	if u.doc.External {
+		return false, nil
+	}
+	policy, err := u.st.PasswordPolicy()
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	if policy.MaxAge == 0 {
+		return false, nil
+	}
+	return u.PasswordChanged().Add(policy.MaxAge).Before(nowToTheSecond()), nil
+}
+
 // PasswordValid returns whether the given password is valid for the User.
 func (u *User) PasswordValid(password string) bool {
 	// If the User is deactivated, no point in carrying on. Since any
@@ -296,7 +380,7 @@ func (u *User) PasswordValid(password string) bool {
 	// from the database, there is a very small timeframe where an user
 	// could be disabled after it has been read but prior to being checked,
 	// but in practice, this isn't a problem.
-	if u.IsDisabled() {
+	if u.IsDisabled() || u.doc.External {
 		return false
 	}
 	if u.doc.PasswordSalt != "" {
@@ -310,7 +394,11 @@ func (u *User) PasswordValid(password string) bool {
 		// fails because we will try again at the next request
 		logger.Debugf("User %s logged in with CompatSalt resetting password for new salt",
 			u.Name())
-		err := u.SetPassword(password)
+		salt, err := utils.RandomSalt()
+		if err == nil {
+			// The password itself is unchanged, so its age is kept.
+			err = u.setPasswordHash(utils.UserPasswordHash(password, salt), salt, false)
+		}
 		if err != nil {
 			logger.Errorf("Cannot set resalted password for user %q", u.Name())
 		}
@@ -341,28 +429,214 @@ func (u *User) Disable() error {
 	return errors.Annotatef(u.setDeactivated(true), "cannot disable user %q", u.Name())
 }
 
+// Groups returns the names of the groups the user is a member of.
+func (u *User) Groups() []string {
+	return u.doc.Groups
+}
+
+// SetGroups sets the names of the groups the user is a member of.
+func (u *User) SetGroups(groups []string) error {
+	for _, group := range groups {
+		if !IsValidPermissionGroup(group) {
+			return errors.NotValidf("group name %q", group)
+		}
+	}
+	groups = set.NewStrings(groups...).SortedValues()
+	ops := []txn.Op{{
+		C:      usersC,
+		Id:     strings.ToLower(u.Name()),
+		Assert: txn.DocExists,
+		Update: bson.D{{"$set", bson.D{{"groups", groups}}}},
+	}}
+	if err := u.st.runTransaction(ops); err != nil {
+		if err == txn.ErrAborted {
+			err = fmt.Errorf("user no longer exists")
+		}
+		return errors.Annotatef(err, "cannot set groups of user %q", u.Name())
+	}
+	u.doc.Groups = groups
+	return nil
+}
+
 // Enable reactivates the user, setting disabled to false.
 func (u *User) Enable() error {
 	return errors.Annotatef(u.setDeactivated(false), "cannot enable user %q", u.Name())
 }
 
 func (u *User) setDeactivated(value bool) error {
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := u.Refresh(); errors.IsNotFound(err) {
+				return nil, fmt.Errorf("user no longer exists")
+			} else if err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		revisionOp, err := u.st.bumpUsersRevisionOp()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return []txn.Op{{
+			C:      usersC,
+			Id:     u.Name(),
+			Assert: txn.DocExists,
+			Update: bson.D{{"$set", bson.D{{"deactivated", value}}}},
+		}, revisionOp}, nil
+	}
+	if err := u.st.run(buildTxn); err != nil {
+		return err
+	}
+	u.doc.Deactivated = value
+	return nil
+}
+
+// FailedLogins returns the number of consecutive failed logins of the
+// User since its last successful login or lockout.
+func (u *User) FailedLogins() int {
+	return u.doc.FailedLogins
+}
+
+// LockedUntil returns when the User's lockout after too many failed
+// logins ends, in UTC, or nil if the User is not locked out.
+func (u *User) LockedUntil() *time.Time {
+	when := u.doc.LockedUntil
+	if when == nil || !when.After(nowToTheSecond()) {
+		return nil
+	}
+	result := when.UTC()
+	return &result
+}
+
+// IsLockedOut returns whether the User is locked out after too many
+// failed logins.
+func (u *User) IsLockedOut() bool {
+	return u.LockedUntil() != nil
+}
+
+// RecordFailedLogin counts a failed login of the User and, once the
+// number of consecutive failures reaches the limit of the login policy
+// of the state server, locks the User out for the lockout duration.
+func (u *User) RecordFailedLogin() error {
+	policy, err := u.st.LoginPolicy()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if policy.LockoutAttempts == 0 {
+		return nil
+	}
 	ops := []txn.Op{{
 		C:      usersC,
-		Id:     u.Name(),
+		Id:     u.doc.DocID,
+		Assert: txn.DocExists,
+		Update: bson.D{{"$inc", bson.D{{"failedlogins", 1}}}},
+	}}
+	if err := u.st.runTransaction(ops); err != nil {
+		return errors.Annotatef(err, "cannot record failed login of user %q", u.Name())
+	}
+	if err := u.Refresh(); err != nil {
+		return errors.Trace(err)
+	}
+	if u.doc.FailedLogins < policy.LockoutAttempts {
+		return nil
+	}
+	lockedUntil := nowToTheSecond().Add(policy.LockoutDuration)
+	ops = []txn.Op{{
+		C:  usersC,
+		Id: u.doc.DocID,
+		// Concurrent failures may have locked the user out already.
+		Assert: bson.D{{"failedlogins", bson.D{{"$gte", policy.LockoutAttempts}}}},
+		Update: bson.D{
+			{"$set", bson.D{{"lockeduntil", lockedUntil}}},
+			{"$unset", bson.D{{"failedlogins", nil}}},
+		},
+	}}
+	if err := u.st.runTransaction(ops); err != nil && err != txn.ErrAborted {
+		return errors.Annotatef(err, "cannot lock out user %q", u.Name())
+	}
+	logger.Infof("user %q locked out until %v after %d failed logins", u.Name(), lockedUntil, policy.LockoutAttempts)
+	return u.Refresh()
+}
+
+// ResetFailedLogins clears the count of failed logins of the User, as
+// done after a successful login.
+func (u *User) ResetFailedLogins() error {
+	if u.doc.FailedLogins == 0 {
+		return nil
+	}
+	ops := []txn.Op{{
+		C:      usersC,
+		Id:     u.doc.DocID,
 		Assert: txn.DocExists,
-		Update: bson.D{{"$set", bson.D{{"deactivated", value}}}},
+		Update: bson.D{{"$unset", bson.D{{"failedlogins", nil}}}},
+	}}
+	if err := u.st.runTransaction(ops); err != nil {
+		return errors.Annotatef(err, "cannot reset failed logins of user %q", u.Name())
+	}
+	u.doc.FailedLogins = 0
+	return nil
+}
+
+// ClearLockout ends any lockout of the User and clears its count of
+// failed logins.
+func (u *User) ClearLockout() error {
+	ops := []txn.Op{{
+		C:      usersC,
+		Id:     u.doc.DocID,
+		Assert: txn.DocExists,
+		Update: bson.D{{"$unset", bson.D{{"failedlogins", nil}, {"lockeduntil", nil}}}},
 	}}
 	if err := u.st.runTransaction(ops); err != nil {
 		if err == txn.ErrAborted {
 			err = fmt.Errorf("user no longer exists")
 		}
-		return err
+		return errors.Annotatef(err, "cannot clear lockout of user %q", u.Name())
 	}
-	u.doc.Deactivated = value
+	u.doc.FailedLogins = 0
+	u.doc.LockedUntil = nil
 	return nil
 }
 
+// stateServerConfig returns the config of the state server environment,
+// which holds the settings that apply to all users.
+func (st *State) stateServerConfig() (*config.Config, error) {
+	env, err := st.StateServerEnvironment()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return env.Config()
+}
+
+// PasswordPolicy returns the policy user passwords must follow, as
+// configured for the state server.
+func (st *State) PasswordPolicy() (config.PasswordPolicy, error) {
+	cfg, err := st.stateServerConfig()
+	if err != nil {
+		return config.PasswordPolicy{}, errors.Annotate(err, "cannot get password policy")
+	}
+	return cfg.PasswordPolicy(), nil
+}
+
+// LoginPolicy returns the limits on failed and repeated user logins, as
+// configured for the state server.
+func (st *State) LoginPolicy() (config.LoginPolicy, error) {
+	cfg, err := st.stateServerConfig()
+	if err != nil {
+		return config.LoginPolicy{}, errors.Annotate(err, "cannot get login policy")
+	}
+	return cfg.LoginPolicy(), nil
+}
+
+// APIRateLimits returns the number of API calls each user may make each
+// minute in each class of facade methods, as configured for the state
+// server.
+func (st *State) APIRateLimits() (map[string]int, error) {
+	cfg, err := st.stateServerConfig()
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot get API rate limits")
+	}
+	return cfg.APIRateLimits(), nil
+}
+
 // IsDisabled returns whether the user is currently enabled.
 func (u *User) IsDisabled() bool {
 	// Yes, this is a cached value, but in practice the user object is
@@ -71,6 +71,27 @@ func (s *UserSuite) TestAddUser(c *gc.C) {
 	c.Assert(user.LastLogin(), gc.IsNil)
 }
 
+func (s *UserSuite) TestAddExternalUser(c *gc.C) {
+	user, err := s.State.AddExternalUser("bob", "Bob Brown", "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(user.IsExternal(), jc.IsTrue)
+	c.Assert(user.PasswordValid(""), jc.IsFalse)
+
+	user, err = s.State.User(user.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(user.Name(), gc.Equals, "bob")
+	c.Assert(user.DisplayName(), gc.Equals, "Bob Brown")
+	c.Assert(user.CreatedBy(), gc.Equals, "admin")
+	c.Assert(user.IsExternal(), jc.IsTrue)
+
+	err = user.SetPassword("sekrit")
+	c.Assert(err, gc.ErrorMatches, `cannot set password of user "bob": user is authenticated externally`)
+	c.Assert(user.PasswordValid("sekrit"), jc.IsFalse)
+
+	_, err = s.State.AddExternalUser("bob", "", "admin")
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+}
+
 func (s *UserSuite) TestCheckUserExists(c *gc.C) {
 	user := s.Factory.MakeUser(c, nil)
 	exists, err := state.CheckUserExists(s.State, user.Name())
@@ -195,6 +216,115 @@ func (s *UserSuite) TestPasswordValidUpdatesSalt(c *gc.C) {
 	c.Assert(lastHash, gc.Equals, afterHash)
 }
 
+func (s *UserSuite) TestPasswordChanged(c *gc.C) {
+	user := s.Factory.MakeUser(c, nil)
+	c.Assert(user.PasswordChanged(), gc.Equals, user.DateCreated())
+
+	now := state.NowToTheSecond()
+	err := user.SetPassword("a-password")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(user.PasswordChanged().Before(now), jc.IsFalse)
+	err = user.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(user.PasswordChanged().Before(now), jc.IsFalse)
+}
+
+func (s *UserSuite) TestPasswordExpired(c *gc.C) {
+	user := s.Factory.MakeUser(c, nil)
+	state.SetUserPasswordChanged(c, user, time.Now().Add(-72*time.Hour))
+
+	expired, err := user.PasswordExpired()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(expired, jc.IsFalse)
+
+	err = s.State.UpdateEnvironConfig(map[string]interface{}{"password-max-age": 7}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	expired, err = user.PasswordExpired()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(expired, jc.IsFalse)
+
+	err = s.State.UpdateEnvironConfig(map[string]interface{}{"password-max-age": 2}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	expired, err = user.PasswordExpired()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(expired, jc.IsTrue)
+
+	err = user.SetPassword("a-new-password")
+	c.Assert(err, jc.ErrorIsNil)
+	expired, err = user.PasswordExpired()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(expired, jc.IsFalse)
+}
+
+func (s *UserSuite) TestRecordFailedLoginWithoutLockout(c *gc.C) {
+	user := s.Factory.MakeUser(c, nil)
+	for i := 0; i < 5; i++ {
+		err := user.RecordFailedLogin()
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	c.Assert(user.FailedLogins(), gc.Equals, 0)
+	c.Assert(user.IsLockedOut(), jc.IsFalse)
+}
+
+func (s *UserSuite) TestRecordFailedLoginLocksOut(c *gc.C) {
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{
+		"login-lockout-attempts": 3,
+		"login-lockout-duration": 600,
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	user := s.Factory.MakeUser(c, nil)
+
+	for i := 1; i < 3; i++ {
+		err := user.RecordFailedLogin()
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(user.FailedLogins(), gc.Equals, i)
+		c.Assert(user.IsLockedOut(), jc.IsFalse)
+	}
+	now := state.NowToTheSecond()
+	err = user.RecordFailedLogin()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(user.FailedLogins(), gc.Equals, 0)
+	c.Assert(user.IsLockedOut(), jc.IsTrue)
+	lockedUntil := user.LockedUntil()
+	c.Assert(lockedUntil, gc.NotNil)
+	c.Assert(lockedUntil.Before(now.Add(600*time.Second)), jc.IsFalse)
+	c.Assert(lockedUntil.After(now.Add(601*time.Second)), jc.IsFalse)
+
+	user, err = s.State.User(user.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(user.IsLockedOut(), jc.IsTrue)
+
+	err = user.ClearLockout()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(user.IsLockedOut(), jc.IsFalse)
+	user, err = s.State.User(user.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(user.IsLockedOut(), jc.IsFalse)
+	c.Assert(user.LockedUntil(), gc.IsNil)
+}
+
+func (s *UserSuite) TestResetFailedLogins(c *gc.C) {
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{"login-lockout-attempts": 3}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	user := s.Factory.MakeUser(c, nil)
+
+	for i := 0; i < 2; i++ {
+		err := user.RecordFailedLogin()
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	err = user.ResetFailedLogins()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(user.FailedLogins(), gc.Equals, 0)
+
+	// The count starts again, so two more failures do not lock the
+	// user out.
+	for i := 0; i < 2; i++ {
+		err := user.RecordFailedLogin()
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	c.Assert(user.IsLockedOut(), jc.IsFalse)
+}
+
 func (s *UserSuite) TestCantDisableAdmin(c *gc.C) {
 	user, err := s.State.User(s.Owner)
 	c.Assert(err, jc.ErrorIsNil)
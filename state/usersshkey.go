@@ -0,0 +1,251 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"github.com/juju/utils/set"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/utils/ssh"
+)
+
+// usersRevisionKey is the id of the document in usersRevisionC.
+const usersRevisionKey = "users"
+
+// bumpUsersRevisionOp returns the operation that bumps the revision of
+// the document in usersRevisionC, creating it if needed. It must be
+// part of every transaction that disables or enables a user, which
+// changes the keys returned by EnvironSSHKeys.
+func (st *State) bumpUsersRevisionOp() (txn.Op, error) {
+	revisions, closer := st.getCollection(usersRevisionC)
+	defer closer()
+	count, err := revisions.FindId(usersRevisionKey).Count()
+	if err != nil {
+		return txn.Op{}, errors.Annotate(err, "cannot read users revision")
+	}
+	if count == 0 {
+		return txn.Op{
+			C:      usersRevisionC,
+			Id:     usersRevisionKey,
+			Assert: txn.DocMissing,
+			Insert: bson.D{{"revision", 1}},
+		}, nil
+	}
+	return txn.Op{
+		C:      usersRevisionC,
+		Id:     usersRevisionKey,
+		Assert: txn.DocExists,
+		Update: bson.D{{"$inc", bson.D{{"revision", 1}}}},
+	}, nil
+}
+
+// UserSSHKey is an SSH public key of a user. The keys of the enabled
+// users with access to an environment are authorised on the
+// environment's machines.
+type UserSSHKey struct {
+	doc userSSHKeyDoc
+}
+
+type userSSHKeyDoc struct {
+	DocID       string `bson:"_id"`
+	User        string `bson:"user"`
+	Fingerprint string `bson:"fingerprint"`
+	Comment     string `bson:"comment"`
+	Key         string `bson:"key"`
+}
+
+// userSSHKeyID returns the document id of the key with the given
+// fingerprint of the given user.
+func userSSHKeyID(user, fingerprint string) string {
+	return strings.ToLower(user) + ":" + fingerprint
+}
+
+// UserTag returns the tag of the user the key belongs to.
+func (k *UserSSHKey) UserTag() names.UserTag {
+	return names.NewLocalUserTag(k.doc.User)
+}
+
+// Fingerprint returns the fingerprint of the key.
+func (k *UserSSHKey) Fingerprint() string {
+	return k.doc.Fingerprint
+}
+
+// Comment returns the comment of the key, which is often of the form
+// user@host.
+func (k *UserSSHKey) Comment() string {
+	return k.doc.Comment
+}
+
+// Key returns the key in authorized_keys format.
+func (k *UserSSHKey) Key() string {
+	return k.doc.Key
+}
+
+// AddSSHKey adds an SSH public key, in authorized_keys format, to the
+// user. It returns an error satisfying errors.IsAlreadyExists if the
+// user already has the key.
+func (u *User) AddSSHKey(key string) (*UserSSHKey, error) {
+	fingerprint, comment, err := ssh.KeyFingerprint(key)
+	if err != nil {
+		return nil, errors.NotValidf("ssh key %q", key)
+	}
+	user := strings.ToLower(u.Name())
+	sshKey := &UserSSHKey{
+		doc: userSSHKeyDoc{
+			DocID:       userSSHKeyID(user, fingerprint),
+			User:        user,
+			Fingerprint: fingerprint,
+			Comment:     comment,
+			Key:         key,
+		},
+	}
+	ops := []txn.Op{{
+		C:      usersC,
+		Id:     user,
+		Assert: bson.D{{"deactivated", false}},
+	}, {
+		C:      userSSHKeysC,
+		Id:     sshKey.doc.DocID,
+		Assert: txn.DocMissing,
+		Insert: &sshKey.doc,
+	}}
+	if err := u.st.runTransaction(ops); err == txn.ErrAborted {
+		if err := u.Refresh(); err != nil {
+			return nil, errors.Trace(err)
+		}
+		if u.IsDisabled() {
+			return nil, errors.Errorf("cannot add ssh key for user %q: user disabled", u.Name())
+		}
+		return nil, errors.AlreadyExistsf("ssh key %s for user %q", fingerprint, u.Name())
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot add ssh key for user %q", u.Name())
+	}
+	return sshKey, nil
+}
+
+// SSHKeys returns the SSH public keys of the user, ordered by
+// fingerprint.
+func (u *User) SSHKeys() ([]*UserSSHKey, error) {
+	sshKeys, closer := u.st.getCollection(userSSHKeysC)
+	defer closer()
+
+	var docs []userSSHKeyDoc
+	err := sshKeys.Find(bson.D{{"user", strings.ToLower(u.Name())}}).Sort("fingerprint").All(&docs)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get ssh keys for user %q", u.Name())
+	}
+	result := make([]*UserSSHKey, len(docs))
+	for i, doc := range docs {
+		result[i] = &UserSSHKey{doc: doc}
+	}
+	return result, nil
+}
+
+// RemoveSSHKey removes the SSH public key of the user identified by
+// the given fingerprint or comment.
+func (u *User) RemoveSSHKey(keyId string) error {
+	keys, err := u.SSHKeys()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var found *UserSSHKey
+	for _, key := range keys {
+		if key.Fingerprint() == keyId || key.Comment() == keyId {
+			found = key
+			break
+		}
+	}
+	if found == nil {
+		return errors.NotFoundf("ssh key %q for user %q", keyId, u.Name())
+	}
+	ops := []txn.Op{{
+		C:      userSSHKeysC,
+		Id:     found.doc.DocID,
+		Assert: txn.DocExists,
+		Remove: true,
+	}}
+	if err := u.st.runTransaction(ops); err == txn.ErrAborted {
+		return errors.NotFoundf("ssh key %q for user %q", keyId, u.Name())
+	} else if err != nil {
+		return errors.Annotatef(err, "cannot remove ssh key %q for user %q", keyId, u.Name())
+	}
+	return nil
+}
+
+// EnvironSSHKeys returns the SSH public keys to authorise on the
+// machines of the environment: the keys in the environment config,
+// followed by the keys of the enabled local users with access to the
+// environment. Keys of disabled users, and of users the environment is
+// no longer shared with, are left out.
+func (st *State) EnvironSSHKeys() ([]string, error) {
+	cfg, err := st.EnvironConfig()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	keys := ssh.SplitAuthorisedKeys(cfg.AuthorizedKeys())
+	fingerprints := make(set.Strings)
+	for _, key := range keys {
+		if fingerprint, _, err := ssh.KeyFingerprint(key); err == nil {
+			fingerprints.Add(fingerprint)
+		}
+	}
+
+	env, err := st.Environment()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	envUsers, err := env.Users()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var userNames []string
+	for _, envUser := range envUsers {
+		if tag := envUser.UserTag(); tag.IsLocal() {
+			userNames = append(userNames, strings.ToLower(tag.Name()))
+		}
+	}
+	if len(userNames) == 0 {
+		return keys, nil
+	}
+
+	users, closer := st.getCollection(usersC)
+	defer closer()
+	var userDocs []struct {
+		DocID string `bson:"_id"`
+	}
+	err = users.Find(bson.D{
+		{"_id", bson.D{{"$in", userNames}}},
+		{"deactivated", false},
+	}).Select(bson.D{{"_id", 1}}).All(&userDocs)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot get environment users")
+	}
+	enabled := make([]string, len(userDocs))
+	for i, doc := range userDocs {
+		enabled[i] = doc.DocID
+	}
+
+	sshKeys, closer := st.getCollection(userSSHKeysC)
+	defer closer()
+	var keyDocs []userSSHKeyDoc
+	err = sshKeys.Find(bson.D{{"user", bson.D{{"$in", enabled}}}}).Sort("_id").All(&keyDocs)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot get user ssh keys")
+	}
+	for _, doc := range keyDocs {
+		// Users may share keys, with each other and with the
+		// environment config; each key is authorised once.
+		if fingerprints.Contains(doc.Fingerprint) {
+			continue
+		}
+		fingerprints.Add(doc.Fingerprint)
+		keys = append(keys, doc.Key)
+	}
+	return keys, nil
+}
@@ -0,0 +1,179 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state/testing"
+	"github.com/juju/juju/testing/factory"
+	sshtesting "github.com/juju/juju/utils/ssh/testing"
+)
+
+type UserSSHKeySuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&UserSSHKeySuite{})
+
+func (s *UserSSHKeySuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{
+		"authorized-keys": sshtesting.ValidKeyOne.Key + " admin@host",
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *UserSSHKeySuite) TestAddSSHKey(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	key := sshtesting.ValidKeyTwo.Key + " bob@laptop"
+
+	sshKey, err := user.AddSSHKey(key)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(sshKey.UserTag(), gc.Equals, user.UserTag())
+	c.Assert(sshKey.Fingerprint(), gc.Equals, sshtesting.ValidKeyTwo.Fingerprint)
+	c.Assert(sshKey.Comment(), gc.Equals, "bob@laptop")
+	c.Assert(sshKey.Key(), gc.Equals, key)
+
+	_, err = user.AddSSHKey(key)
+	c.Assert(err, gc.ErrorMatches, `ssh key .* for user "bob" already exists`)
+	c.Assert(errors.IsAlreadyExists(err), jc.IsTrue)
+
+	_, err = user.AddSSHKey("invalid-key")
+	c.Assert(err, gc.ErrorMatches, `ssh key "invalid-key" not valid`)
+	c.Assert(errors.IsNotValid(err), jc.IsTrue)
+}
+
+func (s *UserSSHKeySuite) TestAddSSHKeyDisabledUser(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob", Disabled: true})
+	_, err := user.AddSSHKey(sshtesting.ValidKeyTwo.Key)
+	c.Assert(err, gc.ErrorMatches, `cannot add ssh key for user "bob": user disabled`)
+}
+
+func (s *UserSSHKeySuite) TestSSHKeys(c *gc.C) {
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	mary := s.Factory.MakeUser(c, &factory.UserParams{Name: "mary"})
+	_, err := bob.AddSSHKey(sshtesting.ValidKeyTwo.Key + " bob@laptop")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = bob.AddSSHKey(sshtesting.ValidKeyThree.Key + " bob@desktop")
+	c.Assert(err, jc.ErrorIsNil)
+	// Users may have the same keys.
+	_, err = mary.AddSSHKey(sshtesting.ValidKeyTwo.Key + " mary@laptop")
+	c.Assert(err, jc.ErrorIsNil)
+
+	keys, err := bob.SSHKeys()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(keys, gc.HasLen, 2)
+	c.Assert(keys[0].Fingerprint(), gc.Equals, sshtesting.ValidKeyThree.Fingerprint)
+	c.Assert(keys[1].Fingerprint(), gc.Equals, sshtesting.ValidKeyTwo.Fingerprint)
+
+	keys, err = mary.SSHKeys()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(keys, gc.HasLen, 1)
+	c.Assert(keys[0].Comment(), gc.Equals, "mary@laptop")
+}
+
+func (s *UserSSHKeySuite) TestRemoveSSHKey(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	_, err := user.AddSSHKey(sshtesting.ValidKeyTwo.Key + " bob@laptop")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = user.AddSSHKey(sshtesting.ValidKeyThree.Key + " bob@desktop")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = user.RemoveSSHKey(sshtesting.ValidKeyTwo.Fingerprint)
+	c.Assert(err, jc.ErrorIsNil)
+	err = user.RemoveSSHKey("bob@desktop")
+	c.Assert(err, jc.ErrorIsNil)
+	keys, err := user.SSHKeys()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(keys, gc.HasLen, 0)
+
+	err = user.RemoveSSHKey("bob@laptop")
+	c.Assert(err, gc.ErrorMatches, `ssh key "bob@laptop" for user "bob" not found`)
+	c.Assert(errors.IsNotFound(err), jc.IsTrue)
+}
+
+func (s *UserSSHKeySuite) TestEnvironSSHKeys(c *gc.C) {
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	mary := s.Factory.MakeUser(c, &factory.UserParams{Name: "mary"})
+	outsider := s.Factory.MakeUser(c, &factory.UserParams{Name: "outsider", NoEnvUser: true})
+	_, err := bob.AddSSHKey(sshtesting.ValidKeyTwo.Key + " bob@laptop")
+	c.Assert(err, jc.ErrorIsNil)
+	// The admin key is in the environment config already, and is
+	// authorised only once.
+	_, err = bob.AddSSHKey(sshtesting.ValidKeyOne.Key + " bob@shared")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = mary.AddSSHKey(sshtesting.ValidKeyThree.Key + " mary@laptop")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = outsider.AddSSHKey(sshtesting.ValidKeyFour.Key + " outsider@laptop")
+	c.Assert(err, jc.ErrorIsNil)
+
+	keys, err := s.State.EnvironSSHKeys()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(keys, jc.DeepEquals, []string{
+		sshtesting.ValidKeyOne.Key + " admin@host",
+		sshtesting.ValidKeyTwo.Key + " bob@laptop",
+		sshtesting.ValidKeyThree.Key + " mary@laptop",
+	})
+
+	// Keys are revoked when users are disabled...
+	err = bob.Disable()
+	c.Assert(err, jc.ErrorIsNil)
+	keys, err = s.State.EnvironSSHKeys()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(keys, jc.DeepEquals, []string{
+		sshtesting.ValidKeyOne.Key + " admin@host",
+		sshtesting.ValidKeyThree.Key + " mary@laptop",
+	})
+
+	// ...or no longer have access to the environment.
+	err = s.State.RemoveEnvironmentUser(mary.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	keys, err = s.State.EnvironSSHKeys()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(keys, jc.DeepEquals, []string{
+		sshtesting.ValidKeyOne.Key + " admin@host",
+	})
+}
+
+func (s *UserSSHKeySuite) TestWatchSSHKeys(c *gc.C) {
+	w := s.State.WatchSSHKeys()
+	defer testing.AssertStop(c, w)
+	wc := testing.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	wc.AssertOneChange()
+
+	_, err := user.AddSSHKey(sshtesting.ValidKeyTwo.Key + " bob@laptop")
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	// Other changes to users do not affect their keys.
+	err = user.SetPassword("new-password")
+	c.Assert(err, jc.ErrorIsNil)
+	err = user.SetGroups([]string{"ops"})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+
+	err = user.Disable()
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	err = user.Enable()
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	err = s.State.RemoveEnvironmentUser(user.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	err = s.State.UpdateEnvironConfig(map[string]interface{}{
+		"authorized-keys": sshtesting.ValidKeyThree.Key,
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+}
@@ -0,0 +1,224 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"github.com/juju/utils"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// UserTokenPrefix prefixes the credentials of users logging in with an
+// API token rather than a password.
+const UserTokenPrefix = "token:"
+
+// UserToken is an API token issued to a user, which can be used in
+// place of the user's password until it expires or is revoked.
+type UserToken struct {
+	st  *State
+	doc userTokenDoc
+}
+
+type userTokenDoc struct {
+	DocID        string    `bson:"_id"`
+	User         string    `bson:"user"`
+	Description  string    `bson:"description"`
+	PasswordHash string    `bson:"passwordhash"`
+	PasswordSalt string    `bson:"passwordsalt"`
+	Created      time.Time `bson:"created"`
+	Expires      time.Time `bson:"expires"`
+	Scope        []string  `bson:"scope,omitempty"`
+}
+
+// Id returns the id of the token.
+func (t *UserToken) Id() string {
+	return t.doc.DocID
+}
+
+// UserTag returns the tag of the user the token was issued to.
+func (t *UserToken) UserTag() names.UserTag {
+	return names.NewLocalUserTag(t.doc.User)
+}
+
+// Description returns the description given to the token when it was
+// created.
+func (t *UserToken) Description() string {
+	return t.doc.Description
+}
+
+// Created returns when the token was created in UTC.
+func (t *UserToken) Created() time.Time {
+	return t.doc.Created.UTC()
+}
+
+// Expires returns when the token expires in UTC.
+func (t *UserToken) Expires() time.Time {
+	return t.doc.Expires.UTC()
+}
+
+// Scope returns the facades and methods the token restricts its user
+// to, as "Facade" or "Facade.Method". An empty scope does not restrict
+// the user.
+func (t *UserToken) Scope() []string {
+	return t.doc.Scope
+}
+
+// SecretValid returns whether the given secret is the token's secret.
+// It does not check whether the token has expired.
+func (t *UserToken) SecretValid(secret string) bool {
+	return utils.UserPasswordHash(secret, t.doc.PasswordSalt) == t.doc.PasswordHash
+}
+
+// ParseUserTokenCredentials returns the id and secret of the token in
+// the given credentials, as returned by User.AddToken. It returns false
+// if the credentials do not hold a token.
+func ParseUserTokenCredentials(credentials string) (id, secret string, ok bool) {
+	if !strings.HasPrefix(credentials, UserTokenPrefix) {
+		return "", "", false
+	}
+	parts := strings.SplitN(strings.TrimPrefix(credentials, UserTokenPrefix), ":", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
+
+// MaxUserTokenLifetime is the longest time an API token may be valid
+// for, from its creation.
+const MaxUserTokenLifetime = 90 * 24 * time.Hour
+
+var validTokenScope = regexp.MustCompile(`^[A-Z][A-Za-z0-9]*(\.[A-Z][A-Za-z0-9]*)?$`)
+
+// AddToken issues a new API token to the user, expiring at the given
+// time, which must be no more than MaxUserTokenLifetime away. If scope
+// is not empty, logins with the token are restricted to the given
+// facades and methods; see UserToken.Scope. It returns the token and
+// the credentials to log in with, which are not stored and cannot be
+// retrieved later.
+func (u *User) AddToken(description string, expires time.Time, scope []string) (*UserToken, string, error) {
+	if expires.IsZero() {
+		return nil, "", errors.NotValidf("token without expiry time")
+	}
+	if expires.After(time.Now().Add(MaxUserTokenLifetime)) {
+		return nil, "", errors.NotValidf("token expiry more than %v from now", MaxUserTokenLifetime)
+	}
+	for _, s := range scope {
+		if !validTokenScope.MatchString(s) {
+			return nil, "", errors.NotValidf("token scope %q", s)
+		}
+	}
+	idBytes, err := utils.RandomBytes(8)
+	if err != nil {
+		return nil, "", errors.Trace(err)
+	}
+	secret, err := utils.RandomPassword()
+	if err != nil {
+		return nil, "", errors.Trace(err)
+	}
+	salt, err := utils.RandomSalt()
+	if err != nil {
+		return nil, "", errors.Trace(err)
+	}
+	token := &UserToken{
+		st: u.st,
+		doc: userTokenDoc{
+			DocID:        fmt.Sprintf("%x", idBytes),
+			User:         strings.ToLower(u.Name()),
+			Description:  description,
+			PasswordHash: utils.UserPasswordHash(secret, salt),
+			PasswordSalt: salt,
+			Created:      nowToTheSecond(),
+			Expires:      expires.UTC(),
+			Scope:        scope,
+		},
+	}
+	ops := []txn.Op{{
+		C:      usersC,
+		Id:     token.doc.User,
+		Assert: bson.D{{"deactivated", false}},
+	}, {
+		C:      userTokensC,
+		Id:     token.doc.DocID,
+		Assert: txn.DocMissing,
+		Insert: &token.doc,
+	}}
+	if err := u.st.runTransaction(ops); err == txn.ErrAborted {
+		return nil, "", errors.Errorf("cannot add token for user %q: user disabled or removed", u.Name())
+	} else if err != nil {
+		return nil, "", errors.Annotatef(err, "cannot add token for user %q", u.Name())
+	}
+	return token, UserTokenPrefix + token.doc.DocID + ":" + secret, nil
+}
+
+// Tokens returns the API tokens issued to the user, including expired
+// ones, ordered by creation time.
+func (u *User) Tokens() ([]*UserToken, error) {
+	tokens, closer := u.st.getCollection(userTokensC)
+	defer closer()
+
+	var docs []userTokenDoc
+	err := tokens.Find(bson.D{{"user", strings.ToLower(u.Name())}}).All(&docs)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get tokens for user %q", u.Name())
+	}
+	result := make([]*UserToken, len(docs))
+	for i, doc := range docs {
+		result[i] = &UserToken{st: u.st, doc: doc}
+	}
+	sort.Sort(userTokenList(result))
+	return result, nil
+}
+
+// RevokeToken removes the user's API token with the given id, so that
+// it can no longer be used to log in.
+func (u *User) RevokeToken(id string) error {
+	ops := []txn.Op{{
+		C:      userTokensC,
+		Id:     id,
+		Assert: bson.D{{"user", strings.ToLower(u.Name())}},
+		Remove: true,
+	}}
+	if err := u.st.runTransaction(ops); err == txn.ErrAborted {
+		return errors.NotFoundf("token %q for user %q", id, u.Name())
+	} else if err != nil {
+		return errors.Annotatef(err, "cannot revoke token %q", id)
+	}
+	return nil
+}
+
+// UserToken returns the API token with the given id.
+func (st *State) UserToken(id string) (*UserToken, error) {
+	tokens, closer := st.getCollection(userTokensC)
+	defer closer()
+
+	token := &UserToken{st: st}
+	err := tokens.FindId(id).One(&token.doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("token %q", id)
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot get token %q", id)
+	}
+	return token, nil
+}
+
+// userTokenList type is used to provide the methods for sorting.
+type userTokenList []*UserToken
+
+func (l userTokenList) Len() int      { return len(l) }
+func (l userTokenList) Swap(i, j int) { l[i], l[j] = l[j], l[i] }
+func (l userTokenList) Less(i, j int) bool {
+	if !l[i].doc.Created.Equal(l[j].doc.Created) {
+		return l[i].doc.Created.Before(l[j].doc.Created)
+	}
+	return l[i].doc.DocID < l[j].doc.DocID
+}
@@ -0,0 +1,115 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
+)
+
+type UserTokenSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&UserTokenSuite{})
+
+func (s *UserTokenSuite) TestAddToken(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	expires := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	token, credentials, err := user.AddToken("ci", expires, []string{"Client.FullStatus", "Charms"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(strings.HasPrefix(credentials, state.UserTokenPrefix+token.Id()+":"), jc.IsTrue)
+	id, secret, ok := state.ParseUserTokenCredentials(credentials)
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(id, gc.Equals, token.Id())
+
+	token, err = s.State.UserToken(id)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(token.UserTag(), gc.Equals, user.UserTag())
+	c.Assert(token.Description(), gc.Equals, "ci")
+	c.Assert(token.Expires(), gc.DeepEquals, expires)
+	c.Assert(token.Scope(), jc.DeepEquals, []string{"Client.FullStatus", "Charms"})
+	c.Assert(token.SecretValid(secret), jc.IsTrue)
+	c.Assert(token.SecretValid("wrong"), jc.IsFalse)
+	c.Assert(token.SecretValid(credentials), jc.IsFalse)
+}
+
+func (s *UserTokenSuite) TestAddTokenInvalid(c *gc.C) {
+	user := s.Factory.MakeUser(c, nil)
+	_, _, err := user.AddToken("", time.Time{}, nil)
+	c.Assert(err, gc.ErrorMatches, "token without expiry time not valid")
+	_, _, err = user.AddToken("", time.Now().Add(time.Hour), []string{"Client.Full.Status"})
+	c.Assert(err, gc.ErrorMatches, `token scope "Client.Full.Status" not valid`)
+	_, _, err = user.AddToken("", time.Now().Add(state.MaxUserTokenLifetime+time.Hour), nil)
+	c.Assert(err, gc.ErrorMatches, `token expiry more than 2160h0m0s from now not valid`)
+
+	err = user.Disable()
+	c.Assert(err, jc.ErrorIsNil)
+	_, _, err = user.AddToken("", time.Now().Add(time.Hour), nil)
+	c.Assert(err, gc.ErrorMatches, `cannot add token for user ".*": user disabled or removed`)
+}
+
+func (s *UserTokenSuite) TestTokensAndRevoke(c *gc.C) {
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	carol := s.Factory.MakeUser(c, &factory.UserParams{Name: "carol"})
+	expires := time.Now().Add(time.Hour)
+	token1, _, err := bob.AddToken("first", expires, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	token2, _, err := bob.AddToken("second", expires, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, _, err = carol.AddToken("carol's", expires, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	tokens, err := bob.Tokens()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(tokens, gc.HasLen, 2)
+	ids := []string{tokens[0].Id(), tokens[1].Id()}
+	c.Assert(ids, jc.SameContents, []string{token1.Id(), token2.Id()})
+
+	// Users cannot revoke each other's tokens.
+	err = carol.RevokeToken(token1.Id())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = bob.RevokeToken(token1.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.UserToken(token1.Id())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	tokens, err = bob.Tokens()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(tokens, gc.HasLen, 1)
+	c.Assert(tokens[0].Id(), gc.Equals, token2.Id())
+
+	err = bob.RevokeToken(token1.Id())
+	c.Assert(err, gc.ErrorMatches, `token ".*" for user "bob" not found`)
+}
+
+func (s *UserTokenSuite) TestParseUserTokenCredentials(c *gc.C) {
+	for i, test := range []struct {
+		credentials string
+		id          string
+		secret      string
+		ok          bool
+	}{
+		{credentials: "sekrit"},
+		{credentials: "token:"},
+		{credentials: "token:abc"},
+		{credentials: "token::sekrit"},
+		{credentials: "token:abc:sekrit", id: "abc", secret: "sekrit", ok: true},
+		{credentials: "token:abc:sek:rit", id: "abc", secret: "sek:rit", ok: true},
+	} {
+		c.Logf("test %d: %q", i, test.credentials)
+		id, secret, ok := state.ParseUserTokenCredentials(test.credentials)
+		c.Check(id, gc.Equals, test.id)
+		c.Check(secret, gc.Equals, test.secret)
+		c.Check(ok, gc.Equals, test.ok)
+	}
+}
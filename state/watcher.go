@@ -1405,6 +1405,16 @@ func (st *State) WatchForEnvironConfigChanges() NotifyWatcher {
 	return newEntityWatcher(st, settingsC, st.docID(environGlobalKey))
 }
 
+// WatchForAgentVersionChanges returns a NotifyWatcher that notifies when
+// the agent version a machine agent should run may have changed: when the
+// environ config changes, or the rolling upgrade in progress changes.
+func (st *State) WatchForAgentVersionChanges() NotifyWatcher {
+	return newDocWatcher(st, []docKey{
+		{settingsC, st.docID(environGlobalKey)},
+		{rollingUpgradesC, st.docID(currentRollingUpgradeId)},
+	})
+}
+
 // WatchAPIHostPorts returns a NotifyWatcher that notifies
 // when the set of API addresses changes.
 func (st *State) WatchAPIHostPorts() NotifyWatcher {
@@ -1827,6 +1837,78 @@ func (w *cleanupWatcher) loop() (err error) {
 	}
 }
 
+// sshKeysWatcher notifies when the SSH keys to authorise on the
+// machines of an environment may have changed.
+type sshKeysWatcher struct {
+	commonWatcher
+	out chan struct{}
+}
+
+var _ Watcher = (*sshKeysWatcher)(nil)
+
+// WatchSSHKeys returns a NotifyWatcher that notifies when the SSH keys
+// returned by EnvironSSHKeys may have changed: when the environment
+// config changes, when users are added to or removed from the
+// environment, disabled or enabled, or when their keys change.
+func (st *State) WatchSSHKeys() NotifyWatcher {
+	return newSSHKeysWatcher(st)
+}
+
+func newSSHKeysWatcher(st *State) NotifyWatcher {
+	w := &sshKeysWatcher{
+		commonWatcher: commonWatcher{st: st},
+		out:           make(chan struct{}),
+	}
+	go func() {
+		defer w.tomb.Done()
+		defer close(w.out)
+		w.tomb.Kill(w.loop())
+	}()
+	return w
+}
+
+// Changes returns the event channel for w.
+func (w *sshKeysWatcher) Changes() <-chan struct{} {
+	return w.out
+}
+
+func (w *sshKeysWatcher) loop() (err error) {
+	in := make(chan watcher.Change)
+	settingsKey := w.st.docID(environGlobalKey)
+	isEnvironSettings := func(id interface{}) bool {
+		return id == settingsKey
+	}
+	w.st.watcher.WatchCollectionWithFilter(settingsC, in, isEnvironSettings)
+	defer w.st.watcher.UnwatchCollection(settingsC, in)
+	// Environment users are only changed by transactions when they
+	// are added or removed. Users, on the other hand, change on every
+	// failed login; only their disabling and enabling is of interest,
+	// and bumps the users revision.
+	w.st.watcher.WatchCollectionWithFilter(envUsersC, in, w.st.isForStateEnv)
+	defer w.st.watcher.UnwatchCollection(envUsersC, in)
+	w.st.watcher.WatchCollection(usersRevisionC, in)
+	defer w.st.watcher.UnwatchCollection(usersRevisionC, in)
+	w.st.watcher.WatchCollection(userSSHKeysC, in)
+	defer w.st.watcher.UnwatchCollection(userSSHKeysC, in)
+
+	out := w.out
+	for {
+		select {
+		case <-w.tomb.Dying():
+			return tomb.ErrDying
+		case <-w.st.watcher.Dead():
+			return stateWatcherDeadError(w.st.watcher.Err())
+		case ch := <-in:
+			if _, ok := collect(ch, in, w.tomb.Dying()); !ok {
+				return tomb.ErrDying
+			}
+			out = w.out
+		case out <- struct{}{}:
+			out = nil
+		}
+	}
+}
+
 // actionStatusWatcher is a StringsWatcher that filters notifications
 // to Action Id's that match the ActionReceiver and ActionStatus set
 // provided.
@@ -0,0 +1,210 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package cron parses cron-style schedule specifications and
+// calculates when they next activate.
+//
+// A specification holds five whitespace-separated fields: minute
+// (0-59), hour (0-23), day of month (1-31), month (1-12) and day of
+// week (0-6, with 0 or 7 for Sunday). Each field is a comma-separated
+// list of "*", a single value or an inclusive range "a-b", optionally
+// followed by "/step". The shorthands @hourly, @daily, @weekly,
+// @monthly and @yearly are also accepted.
+//
+// As in cron(8), when both the day of month and the day of week are
+// restricted, a time matches if either of them does.
+package cron
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+)
+
+// Schedule holds a parsed cron specification.
+type Schedule struct {
+	spec   string
+	minute uint64
+	hour   uint64
+	dom    uint64
+	month  uint64
+	dow    uint64
+
+	// domStar and dowStar record whether the day of month and
+	// the day of week fields are unrestricted.
+	domStar bool
+	dowStar bool
+}
+
+// maxYears bounds the search for the next activation of a schedule
+// that never matches, such as "0 0 31 2 *".
+const maxYears = 5
+
+var shorthands = map[string]string{
+	"@hourly":  "0 * * * *",
+	"@daily":   "0 0 * * *",
+	"@weekly":  "0 0 * * 0",
+	"@monthly": "0 0 1 * *",
+	"@yearly":  "0 0 1 1 *",
+}
+
+type bounds struct {
+	name     string
+	min, max int
+}
+
+var fieldBounds = []bounds{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7},
+}
+
+// Parse parses the supplied cron specification.
+func Parse(spec string) (*Schedule, error) {
+	expanded := spec
+	if full, ok := shorthands[strings.TrimSpace(spec)]; ok {
+		expanded = full
+	}
+	fields := strings.Fields(expanded)
+	if len(fields) != len(fieldBounds) {
+		return nil, errors.NotValidf("cron specification %q (expected %d fields)", spec, len(fieldBounds))
+	}
+	bits := make([]uint64, len(fields))
+	for i, field := range fields {
+		var err error
+		bits[i], err = parseField(field, fieldBounds[i])
+		if err != nil {
+			return nil, errors.Annotatef(err, "cannot parse cron specification %q", spec)
+		}
+	}
+	// Sunday can be written as either 0 or 7.
+	dow := bits[4]
+	if dow&(1<<7) != 0 {
+		dow |= 1
+		dow &^= 1 << 7
+	}
+	return &Schedule{
+		spec:    spec,
+		minute:  bits[0],
+		hour:    bits[1],
+		dom:     bits[2],
+		month:   bits[3],
+		dow:     dow,
+		domStar: strings.HasPrefix(fields[2], "*"),
+		dowStar: strings.HasPrefix(fields[4], "*"),
+	}, nil
+}
+
+// parseField returns the set of values matched by a single field,
+// as a bit set.
+func parseField(field string, b bounds) (uint64, error) {
+	var bits uint64
+	for _, item := range strings.Split(field, ",") {
+		rangePart, step := item, 1
+		if i := strings.Index(item, "/"); i >= 0 {
+			var err error
+			rangePart = item[:i]
+			step, err = strconv.Atoi(item[i+1:])
+			if err != nil || step < 1 {
+				return 0, errors.NotValidf("%s step %q", b.name, item[i+1:])
+			}
+		}
+		start, end := b.min, b.max
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			parts := strings.SplitN(rangePart, "-", 2)
+			var err error
+			if start, err = parseValue(parts[0], b); err != nil {
+				return 0, err
+			}
+			if end, err = parseValue(parts[1], b); err != nil {
+				return 0, err
+			}
+			if end < start {
+				return 0, errors.NotValidf("%s range %q", b.name, rangePart)
+			}
+		default:
+			var err error
+			if start, err = parseValue(rangePart, b); err != nil {
+				return 0, err
+			}
+			if step == 1 {
+				end = start
+			}
+		}
+		for v := start; v <= end; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+func parseValue(s string, b bounds) (int, error) {
+	v, err := strconv.Atoi(s)
+	if err != nil || v < b.min || v > b.max {
+		return 0, errors.NotValidf("%s %q", b.name, s)
+	}
+	return v, nil
+}
+
+// String returns the specification the schedule was parsed from.
+func (s *Schedule) String() string {
+	return s.spec
+}
+
+// Next returns the first time after t, in t's location and truncated
+// to the minute, at which the schedule activates. It returns the zero
+// time if the schedule does not activate within the next few years.
+func (s *Schedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.Year() + maxYears
+
+wrap:
+	for t.Year() <= limit {
+		for !has(s.month, int(t.Month())) {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			if t.Month() == time.January {
+				continue wrap
+			}
+		}
+		for !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			if t.Day() == 1 {
+				continue wrap
+			}
+		}
+		for !has(s.hour, t.Hour()) {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			if t.Hour() == 0 {
+				continue wrap
+			}
+		}
+		for !has(s.minute, t.Minute()) {
+			t = t.Add(time.Minute)
+			if t.Minute() == 0 {
+				continue wrap
+			}
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+func (s *Schedule) dayMatches(t time.Time) bool {
+	domMatch := has(s.dom, t.Day())
+	dowMatch := has(s.dow, int(t.Weekday()))
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+func has(bits uint64, v int) bool {
+	return bits&(1<<uint(v)) != 0
+}
@@ -0,0 +1,100 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package cron_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/utils/cron"
+)
+
+type CronSuite struct{}
+
+var _ = gc.Suite(&CronSuite{})
+
+var nextTests = []struct {
+	spec string
+	next time.Time
+}{{
+	spec: "* * * * *",
+	next: time.Date(2015, 7, 1, 12, 35, 0, 0, time.UTC),
+}, {
+	spec: "0 * * * *",
+	next: time.Date(2015, 7, 1, 13, 0, 0, 0, time.UTC),
+}, {
+	spec: "@daily",
+	next: time.Date(2015, 7, 2, 0, 0, 0, 0, time.UTC),
+}, {
+	spec: "*/15 * * * *",
+	next: time.Date(2015, 7, 1, 12, 45, 0, 0, time.UTC),
+}, {
+	spec: "5/20 10-11 * * *",
+	next: time.Date(2015, 7, 2, 10, 5, 0, 0, time.UTC),
+}, {
+	spec: "30 3 * * 0",
+	next: time.Date(2015, 7, 5, 3, 30, 0, 0, time.UTC),
+}, {
+	spec: "0 0 * * 7",
+	next: time.Date(2015, 7, 5, 0, 0, 0, 0, time.UTC),
+}, {
+	spec: "0 0 1 1 *",
+	next: time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC),
+}, {
+	spec: "0 0 29 2 *",
+	next: time.Date(2016, 2, 29, 0, 0, 0, 0, time.UTC),
+}, {
+	// Day of month and day of week are alternatives.
+	spec: "0 9 13 * 5",
+	next: time.Date(2015, 7, 3, 9, 0, 0, 0, time.UTC),
+}, {
+	spec: "0 0 31 2 *",
+}}
+
+func (*CronSuite) TestNext(c *gc.C) {
+	now := time.Date(2015, 7, 1, 12, 34, 56, 0, time.UTC)
+	for i, test := range nextTests {
+		c.Logf("test %d: %q", i, test.spec)
+		schedule, err := cron.Parse(test.spec)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(schedule.String(), gc.Equals, test.spec)
+		c.Check(schedule.Next(now), gc.DeepEquals, test.next)
+	}
+}
+
+var parseErrorTests = []struct {
+	spec string
+	err  string
+}{{
+	spec: "",
+	err:  `cron specification "" \(expected 5 fields\) not valid`,
+}, {
+	spec: "* * * *",
+	err:  `cron specification "\* \* \* \*" \(expected 5 fields\) not valid`,
+}, {
+	spec: "60 * * * *",
+	err:  `cannot parse cron specification .*: minute "60" not valid`,
+}, {
+	spec: "* * 0 * *",
+	err:  `cannot parse cron specification .*: day of month "0" not valid`,
+}, {
+	spec: "*/0 * * * *",
+	err:  `cannot parse cron specification .*: minute step "0" not valid`,
+}, {
+	spec: "5-2 * * * *",
+	err:  `cannot parse cron specification .*: minute range "5-2" not valid`,
+}, {
+	spec: "* * * jan *",
+	err:  `cannot parse cron specification .*: month "jan" not valid`,
+}}
+
+func (*CronSuite) TestParseErrors(c *gc.C) {
+	for i, test := range parseErrorTests {
+		c.Logf("test %d: %q", i, test.spec)
+		_, err := cron.Parse(test.spec)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package autoscaler implements a worker that adds and removes units
+// of services according to their scaling policies, which are driven
+// by the metrics the services' units report.
+package autoscaler
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+
+	"github.com/juju/juju/juju"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/worker"
+)
+
+var logger = loggo.GetLogger("juju.worker.autoscaler")
+
+// period is the interval at which the scaling policies are evaluated.
+var period = time.Minute
+
+// NewAutoscaler returns a worker that periodically evaluates the
+// scaling policy of every service in the environment, and adds or
+// destroys one unit of a service at a time as the policy requires.
+// Each scaling decision is recorded in the service's status history.
+func NewAutoscaler(st *state.State) worker.Worker {
+	a := &autoscaler{st: st}
+	return worker.NewPeriodicWorker(a.scaleAll, period)
+}
+
+type autoscaler struct {
+	st *state.State
+}
+
+func (a *autoscaler) scaleAll(stop <-chan struct{}) error {
+	services, err := a.st.AllServices()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, service := range services {
+		if service.Life() != state.Alive || !service.IsPrincipal() || service.ScalingPolicy().IsZero() {
+			continue
+		}
+		if err := a.scale(service); err != nil {
+			// A service that cannot be scaled must not prevent
+			// the others from being scaled.
+			logger.Errorf("cannot scale service %q: %v", service.Name(), err)
+		}
+	}
+	return nil
+}
+
+func (a *autoscaler) scale(service *state.Service) error {
+	policy := service.ScalingPolicy()
+	units, err := aliveUnits(service)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	now := time.Now()
+	average, count, err := service.MetricAverage(policy.Metric, now.Add(-policy.Window))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	coolingDown := now.Before(service.ScaledAt().Add(policy.Cooldown))
+	target, reason := decide(policy, service.MinUnits(), len(units), average, count, coolingDown)
+	if target == len(units) {
+		return nil
+	}
+	logger.Infof("scaling service %q from %d to %d units: %s", service.Name(), len(units), target, reason)
+	if target > len(units) {
+		if _, err := juju.AddUnits(a.st, service, target-len(units), ""); err != nil {
+			return errors.Trace(err)
+		}
+	} else {
+		// Remove the most recently added units first.
+		for _, unit := range units[target:] {
+			if err := unit.Destroy(); err != nil {
+				return errors.Trace(err)
+			}
+		}
+	}
+	return service.RecordScaling(len(units), target, reason)
+}
+
+// decide returns the number of units a service with the given number
+// of units should be scaled to under the policy, and why, given the
+// service's own minimum number of units, the average of the policy's
+// metric over its window and the number of values averaged. The larger
+// of the two minimums applies, and the bounds are enforced even while
+// cooling down.
+func decide(policy state.ScalingPolicy, serviceMinUnits, units int, average float64, count int, coolingDown bool) (int, string) {
+	minUnits, maxUnits := policy.MinUnits, policy.MaxUnits
+	if serviceMinUnits > minUnits {
+		minUnits = serviceMinUnits
+	}
+	if minUnits > maxUnits {
+		maxUnits = minUnits
+	}
+	switch {
+	case units < minUnits:
+		return minUnits, fmt.Sprintf("fewer than minimum of %d units", minUnits)
+	case units > maxUnits:
+		return maxUnits, fmt.Sprintf("more than maximum of %d units", maxUnits)
+	case coolingDown || count == 0:
+		return units, ""
+	case average > policy.ScaleUpAbove && units < maxUnits:
+		return units + 1, fmt.Sprintf("average %s %g above %g over %v",
+			policy.Metric, average, policy.ScaleUpAbove, policy.Window)
+	case average < policy.ScaleDownBelow && units > minUnits:
+		return units - 1, fmt.Sprintf("average %s %g below %g over %v",
+			policy.Metric, average, policy.ScaleDownBelow, policy.Window)
+	}
+	return units, ""
+}
+
+// aliveUnits returns the alive units of the service, in the order
+// they were added.
+func aliveUnits(service *state.Service) ([]*state.Unit, error) {
+	all, err := service.AllUnits()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var units []*state.Unit
+	for _, unit := range all {
+		if unit.Life() == state.Alive {
+			units = append(units, unit)
+		}
+	}
+	state.SortUnitsByNumber(units)
+	return units, nil
+}
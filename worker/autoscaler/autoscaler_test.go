@@ -0,0 +1,155 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package autoscaler_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/testing/factory"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/autoscaler"
+)
+
+type autoscalerSuite struct {
+	testing.JujuConnSuite
+}
+
+var _ = gc.Suite(&autoscalerSuite{})
+
+var testPolicy = state.ScalingPolicy{
+	Metric:         "pings",
+	ScaleUpAbove:   80,
+	ScaleDownBelow: 20,
+	Window:         5 * time.Minute,
+	MinUnits:       1,
+	MaxUnits:       3,
+}
+
+func (s *autoscalerSuite) TestDecide(c *gc.C) {
+	for i, test := range []struct {
+		minUnits    int
+		units       int
+		average     float64
+		count       int
+		coolingDown bool
+		target      int
+		reason      string
+	}{{
+		units:  0,
+		target: 1,
+		reason: "fewer than minimum of 1 units",
+	}, {
+		units:  4,
+		target: 3,
+		reason: "more than maximum of 3 units",
+	}, {
+		units:  2,
+		target: 2,
+	}, {
+		units:   2,
+		average: 90,
+		count:   3,
+		target:  3,
+		reason:  "average pings 90 above 80 over 5m0s",
+	}, {
+		units:   3,
+		average: 90,
+		count:   3,
+		target:  3,
+	}, {
+		units:   2,
+		average: 10.5,
+		count:   3,
+		target:  1,
+		reason:  "average pings 10.5 below 20 over 5m0s",
+	}, {
+		units:   1,
+		average: 10,
+		count:   3,
+		target:  1,
+	}, {
+		units:       2,
+		average:     90,
+		count:       3,
+		coolingDown: true,
+		target:      2,
+	}, {
+		units:   2,
+		average: 50,
+		count:   3,
+		target:  2,
+	}, {
+		minUnits: 2,
+		units:    1,
+		target:   2,
+		reason:   "fewer than minimum of 2 units",
+	}, {
+		minUnits: 2,
+		units:    2,
+		average:  10,
+		count:    3,
+		target:   2,
+	}, {
+		minUnits: 5,
+		units:    5,
+		average:  90,
+		count:    3,
+		target:   5,
+	}} {
+		c.Logf("test %d", i)
+		target, reason := autoscaler.Decide(testPolicy, test.minUnits, test.units, test.average, test.count, test.coolingDown)
+		c.Check(target, gc.Equals, test.target)
+		c.Check(reason, gc.Equals, test.reason)
+	}
+}
+
+func (s *autoscalerSuite) assertUnits(c *gc.C, service *state.Service, expect int) {
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		units, err := service.AllUnits()
+		c.Assert(err, jc.ErrorIsNil)
+		alive := 0
+		for _, unit := range units {
+			if unit.Life() == state.Alive {
+				alive++
+			}
+		}
+		if alive == expect || !a.HasNext() {
+			c.Assert(alive, gc.Equals, expect)
+			return
+		}
+	}
+}
+
+func (s *autoscalerSuite) TestAutoscaler(c *gc.C) {
+	s.PatchValue(autoscaler.Period, coretesting.ShortWait)
+	meteredCharm := s.Factory.MakeCharm(c, &factory.CharmParams{Name: "metered", URL: "cs:quantal/metered"})
+	service := s.Factory.MakeService(c, &factory.ServiceParams{Charm: meteredCharm})
+	unit := s.Factory.MakeUnit(c, &factory.UnitParams{Service: service, SetCharmURL: true})
+	err := service.SetScalingPolicy(testPolicy)
+	c.Assert(err, jc.ErrorIsNil)
+
+	now := time.Now().Round(time.Second).UTC()
+	metrics := []state.Metric{{Key: "pings", Value: "90", Time: now}}
+	_, err = unit.AddMetrics(utils.MustNewUUID().String(), now, "", metrics)
+	c.Assert(err, jc.ErrorIsNil)
+
+	w := autoscaler.NewAutoscaler(s.State)
+	defer func() { c.Assert(worker.Stop(w), jc.ErrorIsNil) }()
+
+	// Without a cooldown the service is scaled up to its maximum
+	// one unit at a time.
+	s.assertUnits(c, service, 3)
+	history, err := service.StatusHistory(10)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 2)
+	c.Assert(history[0].Message, gc.Equals, "scaled up from 2 to 3 units: average pings 90 above 80 over 5m0s")
+	c.Assert(history[1].Message, gc.Equals, "scaled up from 1 to 2 units: average pings 90 above 80 over 5m0s")
+}
@@ -0,0 +1,119 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package charmrollout implements a worker that drives charm upgrades
+// started with a canary, releasing the remaining units of a service to
+// the upgrade in batches.
+package charmrollout
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/worker"
+)
+
+var logger = loggo.GetLogger("juju.worker.charmrollout")
+
+// period is the interval at which charm rollouts are checked.
+var period = 10 * time.Second
+
+// NewCharmRollout returns a worker that periodically checks the charm
+// rollout of every service in the environment. Once all the units
+// released to a rollout run the new charm and are active, the next
+// batch of units is released; when a released unit fails, the rollout
+// is paused.
+func NewCharmRollout(st *state.State) worker.Worker {
+	r := &charmRollout{st: st}
+	return worker.NewPeriodicWorker(r.checkAll, period)
+}
+
+type charmRollout struct {
+	st *state.State
+}
+
+func (r *charmRollout) checkAll(stop <-chan struct{}) error {
+	services, err := r.st.AllServices()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, service := range services {
+		rollout, ok := service.CharmRollout()
+		if !ok || rollout.Paused {
+			continue
+		}
+		if err := r.check(service, rollout); err != nil {
+			// A service whose rollout cannot be checked must not
+			// prevent the others from progressing.
+			logger.Errorf("cannot check charm rollout of service %q: %v", service.Name(), err)
+		}
+	}
+	return nil
+}
+
+func (r *charmRollout) check(service *state.Service, rollout state.CharmRollout) error {
+	units, err := service.AllUnits()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	state.SortUnitsByNumber(units)
+	released := make(map[string]bool)
+	for _, name := range rollout.Units {
+		released[name] = true
+	}
+	var pending []*state.Unit
+	for _, unit := range units {
+		if unit.Life() != state.Alive {
+			continue
+		}
+		if !released[unit.Name()] {
+			pending = append(pending, unit)
+			continue
+		}
+		ready, reason, err := upgraded(unit, rollout)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if reason != "" {
+			logger.Warningf("pausing charm rollout of service %q: %s", service.Name(), reason)
+			return service.PauseCharmRollout(reason)
+		}
+		if !ready {
+			return nil
+		}
+	}
+	if len(pending) == 0 {
+		logger.Infof("charm rollout of service %q to %q finished", service.Name(), rollout.To)
+		return service.FinishCharmRollout()
+	}
+	if len(pending) > rollout.BatchSize {
+		pending = pending[:rollout.BatchSize]
+	}
+	names := make([]string, len(pending))
+	for i, unit := range pending {
+		names[i] = unit.Name()
+	}
+	logger.Infof("releasing units %v of service %q to charm %q", names, service.Name(), rollout.To)
+	return service.ReleaseCharmRolloutUnits(names)
+}
+
+// upgraded reports whether the unit runs the charm the rollout upgrades
+// to and is active. If the unit has failed, it returns why.
+func upgraded(unit *state.Unit, rollout state.CharmRollout) (bool, string, error) {
+	status, err := unit.Status()
+	if err != nil {
+		return false, "", errors.Trace(err)
+	}
+	if status.Status == state.StatusError {
+		return false, fmt.Sprintf("unit %s failed: %s", unit.Name(), status.Message), nil
+	}
+	curl, _ := unit.CharmURL()
+	if curl == nil || *curl != *rollout.To {
+		return false, "", nil
+	}
+	return status.Status == state.StatusActive, "", nil
+}
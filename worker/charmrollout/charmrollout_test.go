@@ -0,0 +1,107 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package charmrollout_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/testing/factory"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/charmrollout"
+)
+
+type charmRolloutSuite struct {
+	testing.JujuConnSuite
+	oldCharm *state.Charm
+	newCharm *state.Charm
+	service  *state.Service
+	units    []*state.Unit
+}
+
+var _ = gc.Suite(&charmRolloutSuite{})
+
+func (s *charmRolloutSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.PatchValue(charmrollout.Period, coretesting.ShortWait)
+	s.oldCharm = s.Factory.MakeCharm(c, &factory.CharmParams{Name: "wordpress", URL: "cs:quantal/wordpress-3"})
+	s.newCharm = s.Factory.MakeCharm(c, &factory.CharmParams{Name: "wordpress", URL: "cs:quantal/wordpress-4"})
+	s.service = s.Factory.MakeService(c, &factory.ServiceParams{Charm: s.oldCharm})
+	s.units = nil
+	for i := 0; i < 3; i++ {
+		unit := s.Factory.MakeUnit(c, &factory.UnitParams{Service: s.service, SetCharmURL: true})
+		s.units = append(s.units, unit)
+	}
+}
+
+// upgrade simulates the upgrade of the unit to the new charm.
+func (s *charmRolloutSuite) upgrade(c *gc.C, unit *state.Unit) {
+	err := unit.SetCharmURL(s.newCharm.URL())
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.SetStatus(state.StatusActive, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+// waitRollout waits until the service's charm rollout satisfies the
+// check, and returns it.
+func (s *charmRolloutSuite) waitRollout(c *gc.C, check func(state.CharmRollout, bool) bool) state.CharmRollout {
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		err := s.service.Refresh()
+		c.Assert(err, jc.ErrorIsNil)
+		rollout, ok := s.service.CharmRollout()
+		if check(rollout, ok) {
+			return rollout
+		}
+	}
+	c.Fatalf("charm rollout not changed as expected")
+	panic("unreachable")
+}
+
+func (s *charmRolloutSuite) TestReleasesBatchesAndFinishes(c *gc.C) {
+	err := s.service.SetCharmInBatches(s.newCharm, false, 1, 2)
+	c.Assert(err, jc.ErrorIsNil)
+
+	w := charmrollout.NewCharmRollout(s.State)
+	defer func() { c.Assert(worker.Stop(w), jc.ErrorIsNil) }()
+
+	// Nothing is released until the canary is upgraded and active.
+	time.Sleep(3 * coretesting.ShortWait)
+	err = s.service.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	rollout, _ := s.service.CharmRollout()
+	c.Assert(rollout.Units, jc.DeepEquals, []string{"wordpress/0"})
+
+	s.upgrade(c, s.units[0])
+	rollout = s.waitRollout(c, func(rollout state.CharmRollout, _ bool) bool {
+		return len(rollout.Units) == 3
+	})
+	c.Assert(rollout.Units, jc.DeepEquals, []string{"wordpress/0", "wordpress/1", "wordpress/2"})
+
+	s.upgrade(c, s.units[1])
+	s.upgrade(c, s.units[2])
+	s.waitRollout(c, func(_ state.CharmRollout, ok bool) bool {
+		return !ok
+	})
+}
+
+func (s *charmRolloutSuite) TestPausesOnError(c *gc.C) {
+	err := s.service.SetCharmInBatches(s.newCharm, false, 1, 1)
+	c.Assert(err, jc.ErrorIsNil)
+
+	w := charmrollout.NewCharmRollout(s.State)
+	defer func() { c.Assert(worker.Stop(w), jc.ErrorIsNil) }()
+
+	err = s.units[0].SetAgentStatus(state.StatusError, `hook failed: "upgrade-charm"`, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	rollout := s.waitRollout(c, func(rollout state.CharmRollout, _ bool) bool {
+		return rollout.Paused
+	})
+	c.Assert(rollout.Reason, gc.Equals, `unit wordpress/0 failed: hook failed: "upgrade-charm"`)
+	c.Assert(rollout.Units, jc.DeepEquals, []string{"wordpress/0"})
+}
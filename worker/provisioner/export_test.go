@@ -30,6 +30,7 @@ func GetRetryWatcher(p Provisioner) (watcher.NotifyWatcher, error) {
 
 var (
 	ContainerManagerConfig     = containerManagerConfig
+	MachinePoolInterval        = &machinePoolInterval
 	GetToolsFinder             = &getToolsFinder
 	SysctlConfig               = &sysctlConfig
 	ResolvConf                 = &resolvConf
@@ -5,6 +5,7 @@ package provisioner
 
 import (
 	"sync"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
@@ -14,6 +15,7 @@ import (
 	"github.com/juju/juju/agent"
 	apiprovisioner "github.com/juju/juju/api/provisioner"
 	apiwatcher "github.com/juju/juju/api/watcher"
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/environmentserver/authentication"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
@@ -103,6 +105,10 @@ func (p *provisioner) Stop() error {
 	return p.tomb.Wait()
 }
 
+// machinePoolInterval is the interval at which the environ provisioner
+// replenishes the environment's spare machine pools.
+var machinePoolInterval = time.Minute
+
 // getToolsFinder returns a ToolsFinder for the provided State.
 // This exists for mocking.
 var getToolsFinder = func(st *apiprovisioner.State) ToolsFinder {
@@ -151,6 +157,7 @@ func (p *provisioner) getStartTask(harvestMode config.HarvestMode) (ProvisionerT
 		auth,
 		envCfg.ImageStream(),
 		secureServerConnection,
+		envCfg.ProvisionerRetryOpts(),
 	)
 	return task, nil
 }
@@ -197,6 +204,9 @@ func (p *environProvisioner) loop() error {
 	}
 	defer watcher.Stop(task, &p.tomb)
 
+	// Spare machines are added to the machine pools as ordinary
+	// machines, which the task then provisions.
+	machinePoolTimer := time.After(0)
 	for {
 		select {
 		case <-p.tomb.Dying():
@@ -205,6 +215,14 @@ func (p *environProvisioner) loop() error {
 			err := task.Err()
 			logger.Errorf("environ provisioner died: %v", err)
 			return err
+		case <-machinePoolTimer:
+			machinePoolTimer = time.After(machinePoolInterval)
+			if err := p.st.EnsureMachinePools(); params.IsCodeNotImplemented(err) {
+				logger.Debugf("API server does not support machine pools")
+				machinePoolTimer = nil
+			} else if err != nil {
+				logger.Errorf("cannot ensure machine pools: %v", err)
+			}
 		case _, ok := <-environConfigChanges:
 			if !ok {
 				return watcher.EnsureErr(environWatcher)
@@ -6,6 +6,7 @@ package provisioner
 import (
 	"fmt"
 	"regexp"
+	"strings"
 	"time"
 
 	"github.com/juju/errors"
@@ -70,6 +71,7 @@ func NewProvisionerTask(
 	auth authentication.AuthenticationProvider,
 	imageStream string,
 	secureServerConnection bool,
+	retryOpts config.ProvisionerRetryOpts,
 ) ProvisionerTask {
 	task := &provisionerTask{
 		machineTag:             machineTag,
@@ -84,6 +86,8 @@ func NewProvisionerTask(
 		machines:               make(map[string]*apiprovisioner.Machine),
 		imageStream:            imageStream,
 		secureServerConnection: secureServerConnection,
+		retryOpts:              retryOpts,
+		attempts:               make(map[string]int),
 	}
 	go func() {
 		defer task.tomb.Done()
@@ -105,6 +109,10 @@ type provisionerTask struct {
 	secureServerConnection bool
 	harvestMode            config.HarvestMode
 	harvestModeChan        chan config.HarvestMode
+	retryOpts              config.ProvisionerRetryOpts
+	// machine id -> failed provisioning attempts, for machines being
+	// retried automatically.
+	attempts map[string]int
 	// instance id -> instance
 	instances map[instance.Id]instance.Instance
 	// machine id -> machine
@@ -205,6 +213,7 @@ func (task *provisionerTask) processMachinesWithTransientErrors() error {
 		return nil
 	}
 	logger.Tracef("processMachinesWithTransientErrors(%v)", statusResults)
+	now := time.Now()
 	var pending []*apiprovisioner.Machine
 	for i, status := range statusResults {
 		if status.Error != nil {
@@ -212,7 +221,21 @@ func (task *provisionerTask) processMachinesWithTransientErrors() error {
 			continue
 		}
 		machine := machines[i]
-		if err := machine.SetStatus(params.StatusPending, "", nil); err != nil {
+		// Machines retried with "juju retry-provisioning" start a
+		// new sequence of attempts; automatic retries wait for the
+		// back-off delay to elapse.
+		attempt, retryAfter, ok := automaticRetry(status.Data)
+		if ok && now.Before(retryAfter) {
+			continue
+		}
+		info := ""
+		if ok {
+			task.attempts[machine.Id()] = attempt
+			info = fmt.Sprintf("retrying provisioning (attempt %d of %d)", attempt+1, task.retryOpts.RetryCount+1)
+		} else {
+			delete(task.attempts, machine.Id())
+		}
+		if err := machine.SetStatus(params.StatusPending, info, nil); err != nil {
 			logger.Errorf("cannot reset status of machine %q: %v", status.Id, err)
 			continue
 		}
@@ -222,6 +245,50 @@ func (task *provisionerTask) processMachinesWithTransientErrors() error {
 	return task.startMachines(pending)
 }
 
+// automaticRetry returns the number of failed provisioning attempts
+// and the time of the next attempt recorded in the status data of a
+// machine scheduled for an automatic retry. It returns false if no
+// automatic retry is scheduled.
+func automaticRetry(data map[string]interface{}) (int, time.Time, bool) {
+	value, ok := data["retry-after"].(string)
+	if !ok {
+		return 0, time.Time{}, false
+	}
+	retryAfter, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return 0, time.Time{}, false
+	}
+	// Status data sent over the API is decoded from JSON, so
+	// numbers are float64.
+	var attempt int
+	switch v := data["attempt"].(type) {
+	case int:
+		attempt = v
+	case float64:
+		attempt = int(v)
+	default:
+		return 0, time.Time{}, false
+	}
+	return attempt, retryAfter, true
+}
+
+// maxRetryDelay is the longest the provisioner waits between
+// automatic attempts to start an instance.
+var maxRetryDelay = 30 * time.Minute
+
+// retryDelay returns the amount of time to wait before the given
+// automatic retry, doubling the initial delay for each previous retry.
+func retryDelay(initial time.Duration, attempt int) time.Duration {
+	delay := initial
+	for i := 1; i < attempt && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxRetryDelay {
+		delay = maxRetryDelay
+	}
+	return delay
+}
+
 func (task *provisionerTask) processMachines(ids []string) error {
 	logger.Tracef("processMachines(%v)", ids)
 
@@ -569,6 +636,8 @@ func constructStartInstanceParams(
 		Placement:         provisioningInfo.Placement,
 		DistributionGroup: machine.DistributionGroup,
 		Volumes:           volumes,
+		SubnetsToZones:    provisioningInfo.SubnetsToZones,
+		ExcludeZones:      provisioningInfo.ExcludeZones,
 	}, nil
 }
 
@@ -599,6 +668,9 @@ func (task *provisionerTask) startMachines(machines []*apiprovisioner.Machine) e
 		}
 
 		assocProvInfoAndMachCfg(pInfo, instanceCfg)
+		if task.attempts[m.Id()] > 0 && task.retryOpts.Fallback {
+			fallbackProvisioningInfo(m, pInfo)
+		}
 
 		possibleTools, err := task.toolsFinder.FindTools(
 			version.Current.Number,
@@ -626,6 +698,45 @@ func (task *provisionerTask) startMachines(machines []*apiprovisioner.Machine) e
 	return nil
 }
 
+// fallbackProvisioningInfo relaxes the provisioning info of a machine
+// being retried, so the provider may choose any availability zone and
+// any instance type satisfying the remaining constraints.
+func fallbackProvisioningInfo(machine *apiprovisioner.Machine, pInfo *params.ProvisioningInfo) {
+	if strings.HasPrefix(pInfo.Placement, "zone=") {
+		logger.Infof("ignoring placement %q of machine %q on retry", pInfo.Placement, machine)
+		pInfo.Placement = ""
+	}
+	if pInfo.Constraints.HasInstanceType() {
+		logger.Infof("ignoring instance type %q of machine %q on retry", *pInfo.Constraints.InstanceType, machine)
+		pInfo.Constraints.InstanceType = nil
+	}
+}
+
+// setStartInstanceErrorStatus sets the error status of a machine whose
+// instance could not be started. If the retry policy allows, the
+// machine is scheduled for an automatic retry after a back-off delay.
+func (task *provisionerTask) setStartInstanceErrorStatus(message string, machine *apiprovisioner.Machine, err error) error {
+	attempt := task.attempts[machine.Id()] + 1
+	delete(task.attempts, machine.Id())
+	if attempt > task.retryOpts.RetryCount {
+		return task.setErrorStatus(message, machine, err)
+	}
+	logger.Errorf(message, machine, err)
+	delay := retryDelay(task.retryOpts.RetryDelay, attempt)
+	logger.Infof("retrying provisioning of machine %q in %v (attempt %d of %d)",
+		machine, delay, attempt+1, task.retryOpts.RetryCount+1)
+	data := map[string]interface{}{
+		"transient":   true,
+		"attempt":     attempt,
+		"retry-after": time.Now().Add(delay).UTC().Format(time.RFC3339),
+	}
+	if err1 := machine.SetStatus(params.StatusError, err.Error(), data); err1 != nil {
+		// Something is wrong with this machine, better report it back.
+		return errors.Annotatef(err1, "cannot set error status for machine %q", machine)
+	}
+	return nil
+}
+
 func (task *provisionerTask) setErrorStatus(message string, machine *apiprovisioner.Machine, err error) error {
 	logger.Errorf(message, machine, err)
 	if err1 := machine.SetStatus(params.StatusError, err.Error(), nil); err1 != nil {
@@ -679,15 +790,16 @@ func (task *provisionerTask) startMachine(
 			logger.Infof("retryable error received on start instance - retrying instance creation")
 			result, err = task.broker.StartInstance(startInstanceParams)
 			if err != nil {
-				return task.setErrorStatus("cannot start instance for machine after a retry %q: %v", machine, err)
+				return task.setStartInstanceErrorStatus("cannot start instance for machine after a retry %q: %v", machine, err)
 			}
 		} else {
 			// Set the state to error, so the machine will be skipped next
-			// time until the error is resolved, but don't return an
-			// error; just keep going with the other machines.
-			return task.setErrorStatus("cannot start instance for machine %q: %v", machine, err)
+			// time until it is retried, but don't return an error; just
+			// keep going with the other machines.
+			return task.setStartInstanceErrorStatus("cannot start instance for machine %q: %v", machine, err)
 		}
 	}
+	delete(task.attempts, machine.Id())
 
 	inst := result.Instance
 	hardware := result.Hardware
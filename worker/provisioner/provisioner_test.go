@@ -496,6 +496,31 @@ func (s *ProvisionerSuite) TestConstraints(c *gc.C) {
 	s.checkStartInstanceCustom(c, m, "pork", cons, nil, nil, nil, false, nil, true)
 }
 
+func (s *ProvisionerSuite) TestProvisionerEnsuresMachinePools(c *gc.C) {
+	s.PatchValue(provisioner.MachinePoolInterval, coretesting.ShortWait)
+	err := s.State.SetMachinePool(state.MachinePool{
+		Name:        "web",
+		Size:        1,
+		Series:      coretesting.FakeDefaultSeries,
+		Constraints: s.defaultConstraints,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	p := s.newEnvironProvisioner(c)
+	defer stop(c, p)
+
+	var spares []*state.Machine
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		spares, err = s.State.SpareMachines("web")
+		c.Assert(err, jc.ErrorIsNil)
+		if len(spares) > 0 {
+			break
+		}
+	}
+	c.Assert(spares, gc.HasLen, 1)
+	s.checkStartInstanceNoSecureConnection(c, spares[0])
+}
+
 func (s *ProvisionerSuite) TestPossibleTools(c *gc.C) {
 
 	storageDir := c.MkDir()
@@ -1358,6 +1383,17 @@ func (s *ProvisionerSuite) newProvisionerTask(
 	machineGetter provisioner.MachineGetter,
 	toolsFinder provisioner.ToolsFinder,
 ) provisioner.ProvisionerTask {
+	return s.newProvisionerTaskWithRetryOpts(c, harvestingMethod, broker, machineGetter, toolsFinder, config.ProvisionerRetryOpts{})
+}
+
+func (s *ProvisionerSuite) newProvisionerTaskWithRetryOpts(
+	c *gc.C,
+	harvestingMethod config.HarvestMode,
+	broker environs.InstanceBroker,
+	machineGetter provisioner.MachineGetter,
+	toolsFinder provisioner.ToolsFinder,
+	retryOpts config.ProvisionerRetryOpts,
+) provisioner.ProvisionerTask {
 
 	machineWatcher, err := s.provisioner.WatchEnvironMachines()
 	c.Assert(err, jc.ErrorIsNil)
@@ -1377,6 +1413,7 @@ func (s *ProvisionerSuite) newProvisionerTask(
 		auth,
 		imagemetadata.ReleasedStream,
 		true,
+		retryOpts,
 	)
 }
 
@@ -1521,6 +1558,69 @@ func (s *ProvisionerSuite) TestProvisionerRetriesTransientErrors(c *gc.C) {
 	c.Assert(err, jc.Satisfies, errors.IsNotProvisioned)
 }
 
+func (s *ProvisionerSuite) TestProvisionerRetriesAutomatically(c *gc.C) {
+	s.PatchValue(&apiserverprovisioner.ErrorRetryWaitDelay, 5*time.Millisecond)
+	e := &mockBroker{Environ: s.Environ, retryCount: make(map[string]int)}
+	retryOpts := config.ProvisionerRetryOpts{RetryCount: 3, RetryDelay: time.Millisecond}
+	task := s.newProvisionerTaskWithRetryOpts(c, config.HarvestAll, e, s.provisioner, mockToolsFinder{}, retryOpts)
+	defer stop(c, task)
+
+	m1, err := s.addMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkStartInstance(c, m1)
+	m2, err := s.addMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkStartInstance(c, m2)
+
+	// mockBroker fails to start machine-3 three times; the
+	// provisioner retries it without "juju retry-provisioning".
+	m3, err := s.addMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkStartInstance(c, m3)
+
+	history, err := m3.StatusHistory(20)
+	c.Assert(err, jc.ErrorIsNil)
+	var attempts []interface{}
+	for _, statusInfo := range history {
+		if statusInfo.Status == state.StatusError {
+			c.Check(statusInfo.Message, gc.Equals, "error: some error")
+			c.Check(statusInfo.Data["transient"], jc.IsTrue)
+			attempts = append(attempts, statusInfo.Data["attempt"])
+		}
+	}
+	c.Assert(attempts, gc.HasLen, 3)
+}
+
+func (s *ProvisionerSuite) TestProvisionerStopsRetryingAutomatically(c *gc.C) {
+	s.PatchValue(&apiserverprovisioner.ErrorRetryWaitDelay, 5*time.Millisecond)
+	e := &mockBroker{Environ: s.Environ, retryCount: make(map[string]int)}
+	retryOpts := config.ProvisionerRetryOpts{RetryCount: 1, RetryDelay: time.Millisecond}
+	task := s.newProvisionerTaskWithRetryOpts(c, config.HarvestAll, e, s.provisioner, mockToolsFinder{}, retryOpts)
+	defer stop(c, task)
+
+	m1, err := s.addMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkStartInstance(c, m1)
+	m2, err := s.addMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkStartInstance(c, m2)
+	m3, err := s.addMachine()
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Machine 3 fails twice, after which it waits for a manual retry.
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		statusInfo, err := m3.Status()
+		c.Assert(err, jc.ErrorIsNil)
+		if statusInfo.Status != state.StatusError || statusInfo.Data["transient"] == true {
+			continue
+		}
+		c.Check(statusInfo.Message, gc.Equals, "error: some error")
+		c.Check(e.retryCount["3"], gc.Equals, 2)
+		return
+	}
+	c.Fatalf("machine 3 still being retried")
+}
+
 func (s *ProvisionerSuite) TestProvisionerObservesMachineJobs(c *gc.C) {
 	s.PatchValue(&apiserverprovisioner.ErrorRetryWaitDelay, 5*time.Millisecond)
 	broker := &mockBroker{Environ: s.Environ, retryCount: make(map[string]int)}
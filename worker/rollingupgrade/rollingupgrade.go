@@ -0,0 +1,161 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package rollingupgrade implements a worker that drives rolling agent
+// upgrades, releasing the machines of an environment to the upgrade in
+// batches once the state servers have been upgraded.
+package rollingupgrade
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/worker"
+)
+
+var logger = loggo.GetLogger("juju.worker.rollingupgrade")
+
+// period is the interval at which the rolling upgrade is checked.
+var period = 10 * time.Second
+
+// NewRollingUpgrade returns a worker that periodically checks the
+// rolling agent upgrade in progress in the environment, if any. Once
+// the agents of the state servers and of all the machines released to
+// the upgrade run the new version, the next batch of machines is
+// released; when a released machine fails, the upgrade is paused.
+func NewRollingUpgrade(st *state.State) worker.Worker {
+	r := &rollingUpgrade{st: st}
+	return worker.NewPeriodicWorker(r.check, period)
+}
+
+type rollingUpgrade struct {
+	st *state.State
+}
+
+func (r *rollingUpgrade) check(stop <-chan struct{}) error {
+	rolling, err := r.st.RollingUpgrade()
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	if rolling.Paused {
+		return nil
+	}
+	machines, err := r.st.AllMachines()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	sort.Sort(byMachineId(machines))
+
+	// State servers are always upgraded first.
+	for _, machine := range machines {
+		if machine.Life() != state.Alive || !machine.IsManager() {
+			continue
+		}
+		ready, _, err := upgraded(machine, rolling)
+		if err != nil || !ready {
+			return errors.Trace(err)
+		}
+	}
+
+	var pending []string
+	for _, machine := range machines {
+		if machine.Life() != state.Alive || machine.IsManager() {
+			continue
+		}
+		if !rolling.Released(machine.Id()) {
+			// Machines whose agents have not yet started are left
+			// out; they will start with the new version anyway.
+			// Agents of machines provisioned during the upgrade
+			// already run the new version, and are told to keep it
+			// without being released.
+			tools, err := machine.AgentTools()
+			if errors.IsNotFound(err) {
+				continue
+			} else if err != nil {
+				return errors.Trace(err)
+			}
+			if tools.Version.Number != rolling.To {
+				pending = append(pending, machine.Id())
+				continue
+			}
+		}
+		ready, reason, err := upgraded(machine, rolling)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if reason != "" {
+			logger.Warningf("pausing rolling upgrade to %s: %s", rolling.To, reason)
+			return r.st.PauseRollingUpgrade(reason)
+		}
+		if !ready {
+			return nil
+		}
+	}
+	if len(pending) == 0 {
+		logger.Infof("rolling upgrade to %s finished", rolling.To)
+		return r.st.FinishRollingUpgrade()
+	}
+	if len(pending) > rolling.BatchSize {
+		pending = pending[:rolling.BatchSize]
+	}
+	logger.Infof("releasing machines %v to rolling upgrade to %s", pending, rolling.To)
+	return r.st.ReleaseRollingUpgradeMachines(pending)
+}
+
+// upgraded reports whether the machine's agent is alive and runs the
+// version the rolling upgrade upgrades to. If the machine has failed,
+// it returns why.
+func upgraded(machine *state.Machine, rolling state.RollingUpgrade) (bool, string, error) {
+	status, err := machine.Status()
+	if err != nil {
+		return false, "", errors.Trace(err)
+	}
+	if status.Status == state.StatusError {
+		return false, fmt.Sprintf("machine %s failed: %s", machine.Id(), status.Message), nil
+	}
+	tools, err := machine.AgentTools()
+	if errors.IsNotFound(err) {
+		return false, "", nil
+	} else if err != nil {
+		return false, "", errors.Trace(err)
+	}
+	if tools.Version.Number != rolling.To {
+		return false, "", nil
+	}
+	alive, err := machine.AgentPresence()
+	if err != nil {
+		return false, "", errors.Trace(err)
+	}
+	return alive, "", nil
+}
+
+// byMachineId sorts machines by the number of their host machine, and
+// then by id, so that containers follow the machines hosting them.
+type byMachineId []*state.Machine
+
+func (s byMachineId) Len() int      { return len(s) }
+func (s byMachineId) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s byMachineId) Less(i, j int) bool {
+	hi, hj := hostNumber(s[i].Id()), hostNumber(s[j].Id())
+	if hi != hj {
+		return hi < hj
+	}
+	return s[i].Id() < s[j].Id()
+}
+
+func hostNumber(id string) int {
+	if i := strings.Index(id, "/"); i >= 0 {
+		id = id[:i]
+	}
+	number, _ := strconv.Atoi(id)
+	return number
+}
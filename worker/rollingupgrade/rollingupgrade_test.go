@@ -0,0 +1,131 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package rollingupgrade_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/presence"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/testing/factory"
+	"github.com/juju/juju/version"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/rollingupgrade"
+)
+
+type rollingUpgradeSuite struct {
+	testing.JujuConnSuite
+	machines []*state.Machine
+	pingers  []*presence.Pinger
+	newer    version.Binary
+}
+
+var _ = gc.Suite(&rollingUpgradeSuite{})
+
+func (s *rollingUpgradeSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.PatchValue(rollingupgrade.Period, coretesting.ShortWait)
+	s.machines = []*state.Machine{
+		s.Factory.MakeMachine(c, &factory.MachineParams{Jobs: []state.MachineJob{state.JobManageEnviron}}),
+	}
+	for i := 0; i < 3; i++ {
+		s.machines = append(s.machines, s.Factory.MakeMachine(c, nil))
+	}
+	for _, machine := range s.machines {
+		err := machine.SetAgentVersion(version.Current)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	s.pingers = nil
+	s.newer = version.Current
+	s.newer.Patch++
+}
+
+func (s *rollingUpgradeSuite) TearDownTest(c *gc.C) {
+	for _, pinger := range s.pingers {
+		c.Check(pinger.Kill(), jc.ErrorIsNil)
+	}
+	s.JujuConnSuite.TearDownTest(c)
+}
+
+// upgrade simulates the upgrade of the machine's agent to the newer
+// version.
+func (s *rollingUpgradeSuite) upgrade(c *gc.C, machine *state.Machine) {
+	err := machine.SetAgentVersion(s.newer)
+	c.Assert(err, jc.ErrorIsNil)
+	pinger, err := machine.SetAgentPresence()
+	c.Assert(err, jc.ErrorIsNil)
+	s.pingers = append(s.pingers, pinger)
+	s.State.StartSync()
+	err = machine.WaitAgentPresence(coretesting.LongWait)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+// waitRolling waits until the rolling upgrade satisfies the check, and
+// returns it.
+func (s *rollingUpgradeSuite) waitRolling(c *gc.C, check func(state.RollingUpgrade, error) bool) state.RollingUpgrade {
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		s.State.StartSync()
+		rolling, err := s.State.RollingUpgrade()
+		if check(rolling, err) {
+			return rolling
+		}
+	}
+	c.Fatalf("rolling upgrade not changed as expected")
+	panic("unreachable")
+}
+
+func (s *rollingUpgradeSuite) TestRollingUpgrade(c *gc.C) {
+	err := s.State.SetEnvironAgentVersionRolling(s.newer.Number, 2)
+	c.Assert(err, jc.ErrorIsNil)
+
+	w := rollingupgrade.NewRollingUpgrade(s.State)
+	defer func() { c.Assert(worker.Stop(w), jc.ErrorIsNil) }()
+
+	// Nothing is released until the state server is upgraded.
+	time.Sleep(3 * coretesting.ShortWait)
+	rolling, err := s.State.RollingUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rolling.Machines, gc.HasLen, 0)
+
+	s.upgrade(c, s.machines[0])
+	rolling = s.waitRolling(c, func(rolling state.RollingUpgrade, err error) bool {
+		c.Assert(err, jc.ErrorIsNil)
+		return len(rolling.Machines) > 0
+	})
+	c.Assert(rolling.Machines, jc.DeepEquals, []string{"1", "2"})
+
+	s.upgrade(c, s.machines[1])
+	s.upgrade(c, s.machines[2])
+	rolling = s.waitRolling(c, func(rolling state.RollingUpgrade, err error) bool {
+		c.Assert(err, jc.ErrorIsNil)
+		return len(rolling.Machines) > 2
+	})
+	c.Assert(rolling.Machines, jc.DeepEquals, []string{"1", "2", "3"})
+
+	// A released machine that fails pauses the upgrade.
+	err = s.machines[3].SetStatus(state.StatusError, "disk full", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	rolling = s.waitRolling(c, func(rolling state.RollingUpgrade, err error) bool {
+		c.Assert(err, jc.ErrorIsNil)
+		return rolling.Paused
+	})
+	c.Assert(rolling.Reason, gc.Equals, "machine 3 failed: disk full")
+
+	// Once resumed, the upgrade finishes when the last machine has
+	// been upgraded.
+	err = s.machines[3].SetStatus(state.StatusStarted, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.ResumeRollingUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	s.upgrade(c, s.machines[3])
+	s.waitRolling(c, func(rolling state.RollingUpgrade, err error) bool {
+		return errors.IsNotFound(err)
+	})
+}
@@ -19,6 +19,7 @@ var (
 	ActiveCollectMetricsTimer = &activeCollectMetricsTimer
 	ActiveSendMetricsTimer    = &activeSendMetricsTimer
 	IdleWaitTime              = &idleWaitTime
+	HookRetryDelay            = hookRetryDelay
 	LeadershipGuarantee       = &leadershipGuarantee
 )
 
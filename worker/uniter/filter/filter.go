@@ -4,6 +4,8 @@
 package filter
 
 import (
+	"time"
+
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/names"
@@ -50,6 +52,8 @@ type filter struct {
 	outMeterStatusOn    chan struct{}
 	outStorage          chan []names.StorageTag
 	outStorageOn        chan []names.StorageTag
+	outScheduledEvent   chan params.ScheduledEvent
+	outScheduledEventOn chan params.ScheduledEvent
 	// The want* chans are used to indicate that the filter should send
 	// events if it has them available.
 	wantForcedUpgrade  chan bool
@@ -101,6 +105,11 @@ type filter struct {
 	storage          []names.StorageTag
 	actionsPending   []string
 	nextAction       string
+	scheduledEvent   params.ScheduledEvent
+
+	// firingEvents holds the due times of the scheduled events that
+	// have been sent, but not yet fired by the uniter.
+	firingEvents map[string]time.Time
 
 	// meterStatusCode and meterStatusInfo reflect the meter status values of the unit.
 	meterStatusCode string
@@ -121,6 +130,8 @@ func NewFilter(st *uniter.State, unitTag names.UnitTag) (Filter, error) {
 		outRelationsOn:        make(chan []int),
 		outMeterStatusOn:      make(chan struct{}),
 		outStorageOn:          make(chan []names.StorageTag),
+		outScheduledEventOn:   make(chan params.ScheduledEvent),
+		firingEvents:          make(map[string]time.Time),
 		wantForcedUpgrade:     make(chan bool),
 		wantResolved:          make(chan struct{}),
 		wantLeaderSettings:    make(chan bool),
@@ -183,6 +194,12 @@ func (f *filter) MeterStatusEvents() <-chan struct{} {
 	return f.outMeterStatusOn
 }
 
+// ScheduledEvents returns a channel that will receive each event
+// scheduled by the unit when it becomes due.
+func (f *filter) ScheduledEvents() <-chan params.ScheduledEvent {
+	return f.outScheduledEventOn
+}
+
 // ConfigEvents returns a channel that will receive a signal whenever the service's
 // configuration changes, or when an event is explicitly requested.
 func (f *filter) ConfigEvents() <-chan struct{} {
@@ -400,6 +417,22 @@ func (f *filter) loop(unitTag names.UnitTag) (err error) {
 		return err
 	}
 	defer watcher.Stop(leaderSettingsw, &f.tomb)
+	// Scheduled events are not supported by older API servers, in
+	// which case the unit will never see any.
+	var scheduledEventsw apiwatcher.NotifyWatcher
+	var scheduledEventsChanges <-chan struct{}
+	scheduledEventsw, err = f.unit.WatchScheduledEvents()
+	if errors.IsNotImplemented(err) {
+		filterLogger.Debugf("scheduled events not supported by the API server")
+	} else if err != nil {
+		return err
+	} else {
+		scheduledEventsChanges = scheduledEventsw.Changes()
+	}
+	defer f.maybeStopWatcher(scheduledEventsw)
+	// scheduledEventDue delivers a value when the next scheduled
+	// event becomes due.
+	var scheduledEventDue <-chan time.Time
 
 	// Ignore external requests for leader settings behaviour until we see the first change.
 	var discardLeaderSettings <-chan struct{}
@@ -523,6 +556,18 @@ func (f *filter) loop(unitTag names.UnitTag) (err error) {
 			}
 			discardLeaderSettings = f.discardLeaderSettings
 			wantLeaderSettings = f.wantLeaderSettings
+		case _, ok = <-scheduledEventsChanges:
+			filterLogger.Debugf("got scheduled events change")
+			if !ok {
+				return watcher.EnsureErr(scheduledEventsw)
+			}
+			if scheduledEventDue, err = f.scheduledEventsChanged(); err != nil {
+				return errors.Trace(err)
+			}
+		case <-scheduledEventDue:
+			filterLogger.Debugf("scheduled event %q is due", f.scheduledEvent.Name)
+			scheduledEventDue = nil
+			f.outScheduledEvent = f.outScheduledEventOn
 
 		// Send events on active out chans.
 		case f.outUpgrade <- f.upgrade:
@@ -551,6 +596,16 @@ func (f *filter) loop(unitTag names.UnitTag) (err error) {
 			filterLogger.Debugf("sent storage event")
 			f.outStorage = nil
 			f.storage = nil
+		case f.outScheduledEvent <- f.scheduledEvent:
+			filterLogger.Debugf("sent scheduled event %q", f.scheduledEvent.Name)
+			f.outScheduledEvent = nil
+			// The uniter fires the event, so rescheduling or
+			// removing it, when it commits the hook the event
+			// triggers; until then the event is not sent again.
+			f.firingEvents[f.scheduledEvent.Name] = f.scheduledEvent.Due
+			if scheduledEventDue, err = f.scheduledEventsChanged(); err != nil {
+				return err
+			}
 
 		// Handle explicit requests.
 		case curl := <-f.setCharm:
@@ -653,6 +708,38 @@ func (f *filter) meterStatusChanged() error {
 	return nil
 }
 
+// scheduledEventsChanged responds to changes in the unit's scheduled
+// events. It returns a channel that will deliver a value when the
+// earliest scheduled event becomes due, or nil if none are scheduled.
+func (f *filter) scheduledEventsChanged() (<-chan time.Time, error) {
+	// Any pending event may have been cancelled or rescheduled.
+	f.outScheduledEvent = nil
+	f.scheduledEvent = params.ScheduledEvent{}
+	events, err := f.unit.ScheduledEvents()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	// Events that have been sent are skipped until they are fired,
+	// at which point they are removed or get a new due time.
+	firing := make(map[string]time.Time)
+	var next *params.ScheduledEvent
+	for i, event := range events {
+		if due, ok := f.firingEvents[event.Name]; ok && due.Equal(event.Due) {
+			firing[event.Name] = due
+			continue
+		}
+		if next == nil {
+			next = &events[i]
+		}
+	}
+	f.firingEvents = firing
+	if next == nil {
+		return nil, nil
+	}
+	f.scheduledEvent = *next
+	return time.After(next.Due.Sub(time.Now())), nil
+}
+
 // unitChanged responds to changes in the unit.
 func (f *filter) unitChanged() error {
 	if err := f.unit.Refresh(); err != nil {
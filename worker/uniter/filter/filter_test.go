@@ -601,6 +601,54 @@ func (s *FilterSuite) TestMeterStatusEvents(c *gc.C) {
 	meterC.AssertOneReceive()
 }
 
+func (s *FilterSuite) TestScheduledEvents(c *gc.C) {
+	f, err := filter.NewFilter(s.uniter, s.unit.Tag().(names.UnitTag))
+	c.Assert(err, jc.ErrorIsNil)
+	defer statetesting.AssertStop(c, f)
+	eventC := s.contentAsserterC(c, f.ScheduledEvents())
+	eventC.AssertNoReceive()
+
+	// Events not yet due are not delivered.
+	err = s.unit.ScheduleEvent(state.ScheduledEvent{Name: "later", Due: time.Now().Add(time.Hour)})
+	c.Assert(err, jc.ErrorIsNil)
+	eventC.AssertNoReceive()
+
+	// Due events are delivered once, earliest first. They are
+	// left in state for the uniter to fire.
+	past := time.Now().Add(-time.Minute).Truncate(time.Second)
+	err = s.unit.ScheduleEvent(state.ScheduledEvent{Name: "second", Due: past})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.ScheduleEvent(state.ScheduledEvent{Name: "first", Due: past.Add(-time.Minute)})
+	c.Assert(err, jc.ErrorIsNil)
+	first := eventC.AssertOneReceive().(params.ScheduledEvent)
+	c.Assert(first.Name, gc.Equals, "first")
+	c.Assert(first.Due.Equal(past.Add(-time.Minute)), jc.IsTrue)
+	second := eventC.AssertOneReceive().(params.ScheduledEvent)
+	c.Assert(second.Name, gc.Equals, "second")
+	c.Assert(second.Due.Equal(past), jc.IsTrue)
+	eventC.AssertNoReceive()
+
+	events, err := s.unit.ScheduledEvents()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(events, gc.HasLen, 3)
+
+	// Firing the events does not deliver them again.
+	err = s.unit.FireScheduledEvent(first.Name, first.Due)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.FireScheduledEvent(second.Name, second.Due)
+	c.Assert(err, jc.ErrorIsNil)
+	eventC.AssertNoReceive()
+	events, err = s.unit.ScheduledEvents()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(events, gc.HasLen, 1)
+	c.Assert(events[0].Name, gc.Equals, "later")
+
+	// Cancelled events are not delivered.
+	err = s.unit.CancelScheduledEvent("later")
+	c.Assert(err, jc.ErrorIsNil)
+	eventC.AssertNoReceive()
+}
+
 func (s *FilterSuite) TestStorageEvents(c *gc.C) {
 	storageCharm := s.AddTestingCharm(c, "storage-block2")
 	svc := s.AddTestingServiceWithStorage(c, "storage-block2", storageCharm, map[string]state.StorageConstraints{
@@ -45,6 +45,10 @@ type Filter interface {
 	// meter status changes.
 	MeterStatusEvents() <-chan struct{}
 
+	// ScheduledEvents returns a channel that will receive each event
+	// scheduled by the unit when it becomes due.
+	ScheduledEvents() <-chan params.ScheduledEvent
+
 	// ConfigEvents returns a channel that will receive a signal whenever the service's
 	// configuration changes, or when an event is explicitly requested.
 	ConfigEvents() <-chan struct{}
@@ -16,6 +16,10 @@ const (
 	LeaderElected         hooks.Kind = "leader-elected"
 	LeaderDeposed         hooks.Kind = "leader-deposed"
 	LeaderSettingsChanged hooks.Kind = "leader-settings-changed"
+
+	// ScheduledEvent is run when an event scheduled by the charm,
+	// using the schedule-hook tool, becomes due.
+	ScheduledEvent hooks.Kind = "scheduled-event"
 )
 
 // Info holds details required to execute a hook. Not all fields are
@@ -37,6 +41,15 @@ type Info struct {
 
 	// StorageId is the ID of the storage instance relevant to the hook.
 	StorageId string `yaml:"storage-id,omitempty"`
+
+	// EventName is the name of the scheduled event that triggered the
+	// hook. It is only set when Kind is ScheduledEvent.
+	EventName string `yaml:"event-name,omitempty"`
+
+	// EventDue is the time, in nanoseconds since the Unix epoch, at
+	// which the scheduled event that triggered the hook was due. It is
+	// only set when Kind is ScheduledEvent.
+	EventDue int64 `yaml:"event-due,omitempty"`
 }
 
 // Validate returns an error if the info is not valid.
@@ -59,6 +72,11 @@ func (hi Info) Validate() error {
 	// TODO(fwereade): define these in charm/hooks...
 	case LeaderElected, LeaderDeposed, LeaderSettingsChanged:
 		return nil
+	case ScheduledEvent:
+		if hi.EventName == "" {
+			return fmt.Errorf("%q hook requires an event name", hi.Kind)
+		}
+		return nil
 	}
 	return fmt.Errorf("unknown hook kind %q", hi.Kind)
 }
@@ -50,6 +50,8 @@ var validateTests = []struct {
 	{hook.Info{Kind: hooks.StorageAttached}, `invalid storage ID ""`},
 	{hook.Info{Kind: hooks.StorageAttached, StorageId: "data/0"}, ""},
 	{hook.Info{Kind: hooks.StorageDetaching, StorageId: "data/0"}, ""},
+	{hook.Info{Kind: hook.ScheduledEvent}, `"scheduled-event" hook requires an event name`},
+	{hook.Info{Kind: hook.ScheduledEvent, EventName: "nightly"}, ""},
 }
 
 func (s *InfoSuite) TestValidate(c *gc.C) {
@@ -335,6 +335,12 @@ func modeAbideAliveLoop(u *Uniter) (Mode, error) {
 			creator = newSimpleRunHookOp(hooks.ConfigChanged)
 		case <-u.f.MeterStatusEvents():
 			creator = newSimpleRunHookOp(hooks.MeterStatusChanged)
+		case event := <-u.f.ScheduledEvents():
+			creator = newRunHookOp(hook.Info{
+				Kind:      hook.ScheduledEvent,
+				EventName: event.Name,
+				EventDue:  event.Due.UnixNano(),
+			})
 		case <-collectMetricsSignal:
 			creator = newSimpleRunHookOp(hooks.CollectMetrics)
 		case <-sendMetricsSignal:
@@ -470,6 +476,30 @@ func ModeHookError(u *Uniter) (next Mode, err error) {
 	statusData["hook"] = hookName
 	statusMessage := fmt.Sprintf("hook failed: %q", hookName)
 
+	// Retry the hook without waiting for the user to resolve the error,
+	// if the service's hook retry policy allows it. The number of retries
+	// already made is held in the operation state, so that it survives
+	// restarts of the uniter.
+	var retryHook <-chan time.Time
+	scheduleRetry := func() (string, error) {
+		retryHook = nil
+		policy, err := hookRetryPolicy(u)
+		if err != nil {
+			return "", errors.Trace(err)
+		}
+		attempts := u.operationState().HookRetries
+		if attempts >= policy.MaxAttempts {
+			return statusMessage, nil
+		}
+		delay := hookRetryDelay(policy, attempts)
+		retryHook = time.After(delay)
+		return fmt.Sprintf("%s, retry %d of %d in %s", statusMessage, attempts+1, policy.MaxAttempts, delay), nil
+	}
+	message, err := scheduleRetry()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
 	// Run the select loop.
 	u.f.WantResolvedEvent()
 	u.f.WantUpgradeEvent(true)
@@ -482,7 +512,7 @@ func ModeHookError(u *Uniter) (next Mode, err error) {
 		// It's the agent itself that should be in Error state. So we'll ensure the model is
 		// correct and translate before the user sees the data.
 		// ie a charm hook error results in agent error status, but is presented as a workload error.
-		if err = setAgentStatus(u, params.StatusError, statusMessage, statusData); err != nil {
+		if err = setAgentStatus(u, params.StatusError, message, statusData); err != nil {
 			return nil, errors.Trace(err)
 		}
 		select {
@@ -490,6 +520,30 @@ func ModeHookError(u *Uniter) (next Mode, err error) {
 			return nil, tomb.ErrDying
 		case curl := <-u.f.UpgradeEvents():
 			return ModeUpgrading(curl), nil
+		case <-retryHook:
+			// The policy may have changed while waiting to retry.
+			policy, err := hookRetryPolicy(u)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			attempt := u.operationState().HookRetries + 1
+			if attempt > policy.MaxAttempts {
+				if message, err = scheduleRetry(); err != nil {
+					return nil, errors.Trace(err)
+				}
+				continue
+			}
+			logger.Infof("retrying hook %q (attempt %d of %d)", hookName, attempt, policy.MaxAttempts)
+			err = u.runOperation(newAutoRetryHookOp(hookInfo))
+			if errors.Cause(err) == operation.ErrHookFailed {
+				if message, err = scheduleRetry(); err != nil {
+					return nil, errors.Trace(err)
+				}
+				continue
+			} else if err != nil {
+				return nil, errors.Trace(err)
+			}
+			return ModeContinue, nil
 		case rm := <-u.f.ResolvedEvents():
 			var creator creator
 			switch rm {
@@ -502,6 +556,11 @@ func ModeHookError(u *Uniter) (next Mode, err error) {
 			}
 			err := u.runOperation(creator)
 			if errors.Cause(err) == operation.ErrHookFailed {
+				// The user's retry started counting automatic
+				// retries again.
+				if message, err = scheduleRetry(); err != nil {
+					return nil, errors.Trace(err)
+				}
 				continue
 			} else if err != nil {
 				return nil, errors.Trace(err)
@@ -522,6 +581,35 @@ func ModeHookError(u *Uniter) (next Mode, err error) {
 	}
 }
 
+// hookRetryPolicy returns the current policy of the unit's service
+// for retrying failed hooks. If the API server does not support
+// automatic retries, it returns the zero policy, which disables them.
+func hookRetryPolicy(u *Uniter) (params.HookRetryPolicy, error) {
+	policy, err := u.unit.HookRetryPolicy()
+	if errors.IsNotImplemented(err) {
+		logger.Debugf("hook retry policy not supported by the API server")
+		return params.HookRetryPolicy{}, nil
+	}
+	return policy, errors.Trace(err)
+}
+
+// hookRetryDelay returns the time to wait before retrying a failed
+// hook, given the number of retries already attempted.
+func hookRetryDelay(policy params.HookRetryPolicy, attempts int) time.Duration {
+	delay := policy.Delay
+	for i := 0; i < attempts; i++ {
+		if policy.MaxDelay > 0 && delay*2 >= policy.MaxDelay {
+			return policy.MaxDelay
+		}
+		if delay*2 < delay {
+			// Don't let the delay overflow.
+			break
+		}
+		delay *= 2
+	}
+	return delay
+}
+
 // ModeConflicted is responsible for watching and responding to:
 // * user resolution of charm upgrade conflicts
 // * forced charm upgrade requests
@@ -5,6 +5,7 @@ package uniter
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/names"
@@ -63,6 +64,17 @@ func (opc *operationCallbacks) CommitHook(hi hook.Info) error {
 		opc.u.ranConfigChanged = true
 	case hi.Kind == hook.LeaderSettingsChanged:
 		opc.u.ranLeaderSettingsChanged = true
+	case hi.Kind == hook.ScheduledEvent:
+		// Firing the event reschedules or removes it, so that it
+		// is not delivered again once the hook has run; unless the
+		// hook has scheduled it again itself.
+		due := time.Unix(0, hi.EventDue)
+		err := opc.u.unit.FireScheduledEvent(hi.EventName, due)
+		if params.IsCodeNotFound(err) {
+			// The event was cancelled while the hook ran.
+			return nil
+		}
+		return errors.Trace(err)
 	}
 	return nil
 }
@@ -115,6 +115,19 @@ func (f *factory) NewRetryHook(hookInfo hook.Info) (Operation, error) {
 	return f.newResolved(hookOp)
 }
 
+// NewAutoRetryHook is part of the Factory interface.
+func (f *factory) NewAutoRetryHook(hookInfo hook.Info) (Operation, error) {
+	if err := hookInfo.Validate(); err != nil {
+		return nil, err
+	}
+	return &runHook{
+		info:          hookInfo,
+		autoRetry:     true,
+		callbacks:     f.config.Callbacks,
+		runnerFactory: f.config.RunnerFactory,
+	}, nil
+}
+
 // NewSkipHook is part of the Factory interface.
 func (f *factory) NewSkipHook(hookInfo hook.Info) (Operation, error) {
 	hookOp, err := f.NewRunHook(hookInfo)
@@ -183,6 +183,10 @@ func (s *FactorySuite) TestNewHookError_Retry(c *gc.C) {
 	s.testNewHookError(c, (operation.Factory).NewRetryHook)
 }
 
+func (s *FactorySuite) TestNewHookError_AutoRetry(c *gc.C) {
+	s.testNewHookError(c, (operation.Factory).NewAutoRetryHook)
+}
+
 func (s *FactorySuite) TestNewHookError_Skip(c *gc.C) {
 	s.testNewHookError(c, (operation.Factory).NewSkipHook)
 }
@@ -87,6 +87,11 @@ type Factory interface {
 	// re-execute the supplied hook.
 	NewRetryHook(hookInfo hook.Info) (Operation, error)
 
+	// NewAutoRetryHook creates an operation to re-execute the supplied hook
+	// without the user resolving its failure, and to count the retry in the
+	// operation state.
+	NewAutoRetryHook(hookInfo hook.Info) (Operation, error)
+
 	// NewSkipHook creates an operation to clear the unit's resolved flag, and
 	// mark the supplied hook as completed successfully.
 	NewSkipHook(hookInfo hook.Info) (Operation, error)
@@ -19,6 +19,10 @@ import (
 type runHook struct {
 	info hook.Info
 
+	// autoRetry is true if the hook is being retried automatically
+	// after it failed.
+	autoRetry bool
+
 	callbacks     Callbacks
 	runnerFactory runner.Factory
 
@@ -58,11 +62,17 @@ func (rh *runHook) Prepare(state State) (*State, error) {
 	rh.name = name
 	rh.runner = rnr
 
-	return stateChange{
+	newState := stateChange{
 		Kind: RunHook,
 		Step: Pending,
 		Hook: &rh.info,
-	}.apply(state), nil
+	}.apply(state)
+	if rh.autoRetry {
+		newState.HookRetries++
+	} else {
+		newState.HookRetries = 0
+	}
+	return newState, nil
 }
 
 // RunningHookMessage returns the info message to print when running a hook.
@@ -207,6 +217,7 @@ func (rh *runHook) Commit(state State) (*State, error) {
 	}
 
 	newState := change.apply(state)
+	newState.HookRetries = 0
 
 	switch rh.info.Kind {
 	case hooks.Start:
@@ -173,6 +173,29 @@ func (s *RunHookSuite) TestPrepareSuccess_Preserve(c *gc.C) {
 	}
 }
 
+func (s *RunHookSuite) TestPrepareSuccess_HookRetries(c *gc.C) {
+	failed := operation.State{
+		Kind:        operation.RunHook,
+		Step:        operation.Pending,
+		Hook:        &hook.Info{Kind: hooks.ConfigChanged},
+		HookRetries: 2,
+	}
+	retried := failed
+	retried.HookRetries = 3
+	s.testPrepareSuccess(c, (operation.Factory).NewAutoRetryHook, failed, retried)
+
+	// Running the hook in any other way starts counting again.
+	for i, newHook := range []newHook{
+		(operation.Factory).NewRunHook,
+		(operation.Factory).NewRetryHook,
+	} {
+		c.Logf("variant %d", i)
+		reset := failed
+		reset.HookRetries = 0
+		s.testPrepareSuccess(c, newHook, failed, reset)
+	}
+}
+
 func (s *RunHookSuite) getExecuteRunnerTest(c *gc.C, newHook newHook, kind hooks.Kind, runErr error) (operation.Operation, *ExecuteHookCallbacks, *MockRunnerFactory) {
 	runnerFactory := NewRunHookRunnerFactory(runErr)
 	callbacks := &ExecuteHookCallbacks{
@@ -514,6 +537,31 @@ func (s *RunHookSuite) testCommitSuccess(c *gc.C, newHook newHook, hookInfo hook
 	c.Assert(newState, gc.DeepEquals, &after)
 }
 
+func (s *RunHookSuite) TestCommitSuccess_ResetsHookRetries(c *gc.C) {
+	for i, newHook := range []newHook{
+		(operation.Factory).NewRunHook,
+		(operation.Factory).NewRetryHook,
+		(operation.Factory).NewAutoRetryHook,
+		(operation.Factory).NewSkipHook,
+	} {
+		c.Logf("variant %d", i)
+		s.testCommitSuccess(c,
+			newHook,
+			hook.Info{Kind: hooks.Install},
+			operation.State{
+				Kind:        operation.RunHook,
+				Step:        operation.Pending,
+				Hook:        &hook.Info{Kind: hooks.Install},
+				HookRetries: 2,
+			},
+			operation.State{
+				Kind: operation.Continue,
+				Step: operation.Pending,
+			},
+		)
+	}
+}
+
 func (s *RunHookSuite) TestCommitSuccess_ConfigChanged_QueueStartHook(c *gc.C) {
 	for i, newHook := range []newHook{
 		(operation.Factory).NewRunHook,
@@ -83,6 +83,11 @@ type State struct {
 	// upgrade is complete (instead of running an upgrade-charm hook).
 	Hook *hook.Info `yaml:"hook,omitempty"`
 
+	// HookRetries holds the number of times the hook in Hook has been
+	// retried automatically, following the service's hook retry policy,
+	// since it last failed other than in such a retry.
+	HookRetries int `yaml:"hook-retries,omitempty"`
+
 	// ActionId holds action information relevant to the current operation. If
 	// Kind is Continue, it holds the last action that was executed; if Kind is
 	// RunAction, it holds the running action.
@@ -154,6 +154,10 @@ type HookContext struct {
 	// storageId is the tag of the storage instance associated with the running hook.
 	storageTag names.StorageTag
 
+	// scheduledEvent is the name of the scheduled event that triggered
+	// the running hook, if any.
+	scheduledEvent string
+
 	// hasRunSetStatus is true if a call to the status-set was made during the
 	// invocation of a hook.
 	// This attribute is persisted to local uniter state at the end of the hook
@@ -368,6 +372,18 @@ func (ctx *HookContext) ClosePorts(protocol string, fromPort, toPort int) error
 	)
 }
 
+// ScheduleEvent schedules the named event for the unit. Unlike most
+// hook context changes, this takes effect immediately rather than when
+// the hook completes.
+func (ctx *HookContext) ScheduleEvent(name string, due time.Time, cron string) error {
+	return ctx.unit.ScheduleEvent(name, due, cron)
+}
+
+// CancelScheduledEvent cancels the named event scheduled by the unit.
+func (ctx *HookContext) CancelScheduledEvent(name string) error {
+	return ctx.unit.CancelScheduledEvent(name)
+}
+
 func (ctx *HookContext) OpenedPorts() []network.PortRange {
 	var unitRanges []network.PortRange
 	for portRange, relUnit := range ctx.machinePorts {
@@ -379,6 +395,12 @@ func (ctx *HookContext) OpenedPorts() []network.PortRange {
 	return unitRanges
 }
 
+// NetworkInfo returns the network interfaces and addresses the unit
+// uses for the given binding.
+func (ctx *HookContext) NetworkInfo(binding string) ([]params.NetworkInfo, error) {
+	return ctx.unit.NetworkInfo(binding)
+}
+
 func (ctx *HookContext) OwnerTag() string {
 	return ctx.serviceOwner.String()
 }
@@ -526,6 +548,9 @@ func (context *HookContext) HookVars(paths Paths) []string {
 			"JUJU_ACTION_TAG="+context.actionData.ActionTag.String(),
 		)
 	}
+	if context.scheduledEvent != "" {
+		vars = append(vars, "JUJU_SCHEDULED_EVENT="+context.scheduledEvent)
+	}
 	return append(vars, osDependentEnvVars(paths)...)
 }
 
@@ -566,6 +591,18 @@ func (ctx *HookContext) addJujuUnitsMetric() error {
 	return nil
 }
 
+// RecordHookExecution records the timing and outcome of a hook,
+// action or command executed in the context.
+func (ctx *HookContext) RecordHookExecution(execution params.HookExecution) error {
+	return ctx.unit.RecordHookExecutions([]params.HookExecution{execution})
+}
+
+// DebugHooksSession returns the debug-hooks session opened on the
+// context's unit through the API, or nil if it has none.
+func (ctx *HookContext) DebugHooksSession() (*params.UnitDebugHooksSession, error) {
+	return ctx.unit.DebugHooksSession()
+}
+
 // FlushContext implements the Context interface.
 func (ctx *HookContext) FlushContext(process string, ctxErr error) (err error) {
 	// A non-existant metricsRecorder simply means that metrics were disabled
@@ -11,34 +11,67 @@ import (
 )
 
 type hookArgs struct {
-	Hooks []string `yaml:"hooks,omitempty"`
+	Hooks       []string `yaml:"hooks,omitempty"`
+	Actions     []string `yaml:"actions,omitempty"`
+	RelationIds []int    `yaml:"relation-ids,omitempty"`
 }
 
 // ClientScript returns a bash script suitable for executing
 // on the unit system to intercept hooks via tmux shell.
 func ClientScript(c *HooksContext, hooks []string) string {
-	// If any hook is "*", then the client is interested in all.
-	for _, hook := range hooks {
-		if hook == "*" {
-			hooks = nil
-			break
-		}
-	}
+	return ClientScriptWithArgs(c, ClientArgs{Hooks: hooks})
+}
 
+// ClientScriptWithArgs returns a bash script suitable for executing
+// on the unit system to intercept the hooks and actions selected by
+// args.
+func ClientScriptWithArgs(c *HooksContext, args ClientArgs) string {
+	hooks, actions := expandArgs(args)
 	s := strings.Replace(debugHooksClientScript, "{unit_name}", c.Unit, -1)
 	s = strings.Replace(s, "{tmux_conf}", tmuxConf, 1)
 	s = strings.Replace(s, "{entry_flock}", c.ClientFileLock(), -1)
 	s = strings.Replace(s, "{exit_flock}", c.ClientExitFileLock(), -1)
 
-	yamlArgs := encodeArgs(hooks)
+	yamlArgs := encodeArgs(hookArgs{
+		Hooks:       hooks,
+		Actions:     actions,
+		RelationIds: args.RelationIds,
+	})
 	base64Args := base64.StdEncoding.EncodeToString(yamlArgs)
 	s = strings.Replace(s, "{hook_args}", base64Args, 1)
 	return s
 }
 
-func encodeArgs(hooks []string) []byte {
+// expandArgs returns the hooks and actions selected by args.
+func expandArgs(args ClientArgs) (hooks, actions []string) {
+	// If any hook or action is "*", then the client is interested
+	// in all of them. If no actions were named, "*" means everything.
+	hooks = expandWildcard(args.Hooks)
+	actions = expandWildcard(args.Actions)
+	if len(actions) == 0 && isWildcard(hooks) {
+		hooks = nil
+	}
+	return hooks, actions
+}
+
+// expandWildcard returns names, or a list holding only "*"
+// if any of the names is "*".
+func expandWildcard(names []string) []string {
+	for _, name := range names {
+		if name == "*" {
+			return []string{"*"}
+		}
+	}
+	return names
+}
+
+func isWildcard(names []string) bool {
+	return len(names) == 1 && names[0] == "*"
+}
+
+func encodeArgs(args hookArgs) []byte {
 	// Marshal to YAML, then encode in base64 to avoid shell escapes.
-	yamlArgs, err := goyaml.Marshal(hookArgs{Hooks: hooks})
+	yamlArgs, err := goyaml.Marshal(args)
 	if err != nil {
 		// This should not happen: we're in full control.
 		panic(err)
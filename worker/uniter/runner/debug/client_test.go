@@ -4,6 +4,7 @@
 package debug_test
 
 import (
+	"encoding/base64"
 	"fmt"
 	"regexp"
 
@@ -48,3 +49,27 @@ func (*DebugHooksClientSuite) TestClientScript(c *gc.C) {
 	)
 	c.Assert(debug.ClientScript(ctx, []string{"something somethingelse"}), gc.Matches, expected)
 }
+
+func (*DebugHooksClientSuite) TestClientScriptWithArgs(c *gc.C) {
+	ctx := debug.NewHooksContext("foo/8")
+
+	// Without actions, the result is the same as ClientScript.
+	c.Assert(
+		debug.ClientScriptWithArgs(ctx, debug.ClientArgs{Hooks: []string{"*", "start"}}),
+		gc.Equals,
+		debug.ClientScript(ctx, nil),
+	)
+
+	// Actions and relation ids are passed to the server.
+	args := debug.ClientArgs{
+		Hooks:       []string{"start"},
+		Actions:     []string{"backup"},
+		RelationIds: []int{3},
+	}
+	expected := fmt.Sprintf(
+		`(.|\n)*echo "%s" \| base64 -d > %s(.|\n)*`,
+		regexp.QuoteMeta(base64.StdEncoding.EncodeToString([]byte("hooks:\n- start\nactions:\n- backup\nrelation-ids:\n- 3\n"))),
+		regexp.QuoteMeta(ctx.ClientFileLock()),
+	)
+	c.Assert(debug.ClientScriptWithArgs(ctx, args), gc.Matches, expected)
+}
@@ -30,6 +30,20 @@ func (c *HooksContext) ClientExitFileLock() string {
 	return c.ClientFileLock() + "-exit"
 }
 
+// ClientArgs holds the parameters of a debug-hooks client session,
+// as used to select the hooks and actions the session intercepts.
+type ClientArgs struct {
+	// Hooks holds the names of the hooks to intercept.
+	Hooks []string
+
+	// Actions holds the names of the actions to intercept.
+	Actions []string
+
+	// RelationIds restricts the session to relation hooks
+	// executing for the specified relations.
+	RelationIds []int
+}
+
 func (c *HooksContext) tmuxSessionName() string {
 	return c.Unit
 }
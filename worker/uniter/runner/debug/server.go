@@ -17,13 +17,55 @@ import (
 // ServerSession represents a "juju debug-hooks" session.
 type ServerSession struct {
 	*HooksContext
-	hooks set.Strings
+	hooks       set.Strings
+	actions     set.Strings
+	relationIds []int
+	stream      bool
 }
 
 // MatchHook returns true if the specified hook name matches
 // the hook specified by the debug-hooks client.
 func (s *ServerSession) MatchHook(hookName string) bool {
-	return s.hooks.IsEmpty() || s.hooks.Contains(hookName)
+	if s.hooks.IsEmpty() {
+		// A session naming only actions intercepts no hooks.
+		return s.actions.IsEmpty()
+	}
+	return s.hooks.Contains("*") || s.hooks.Contains(hookName)
+}
+
+// MatchAction returns true if the specified action name matches
+// the action specified by the debug-hooks client. If the client
+// did not name any actions, the action name is matched against
+// the hooks instead.
+func (s *ServerSession) MatchAction(actionName string) bool {
+	if s.actions.IsEmpty() {
+		return s.MatchHook(actionName)
+	}
+	return s.actions.Contains("*") || s.actions.Contains(actionName)
+}
+
+// MatchRelation returns true if a hook executing for the relation
+// with the specified id matches the relations specified by the
+// debug-hooks client. Hooks not associated with a relation should
+// pass a negative id; they only match if the client did not
+// restrict the session to any relations.
+func (s *ServerSession) MatchRelation(relationId int) bool {
+	if len(s.relationIds) == 0 {
+		return true
+	}
+	for _, id := range s.relationIds {
+		if id == relationId {
+			return true
+		}
+	}
+	return false
+}
+
+// Stream returns true if matching hooks run unattended, with their
+// output streamed to the debug-hooks client through the debug log,
+// instead of running in a tmux session.
+func (s *ServerSession) Stream() bool {
+	return s.stream
 }
 
 // waitClientExit executes flock, waiting for the SSH client to exit.
@@ -65,19 +107,45 @@ func (c *HooksContext) FindSession() (*ServerSession, error) {
 			return nil, err
 		}
 	}
-	// Parse the debug-hooks file for an optional hook name.
-	data, err := ioutil.ReadFile(c.ClientFileLock())
+	args, err := c.readArgs()
 	if err != nil {
 		return nil, err
 	}
+	return newServerSession(c, args), nil
+}
+
+// NewStreamSession returns a ServerSession for a streaming debug-hooks
+// session opened through the API, intercepting the hooks and actions
+// selected by args.
+func (c *HooksContext) NewStreamSession(args ClientArgs) *ServerSession {
+	hooks, actions := expandArgs(args)
+	session := newServerSession(c, hookArgs{
+		Hooks:       hooks,
+		Actions:     actions,
+		RelationIds: args.RelationIds,
+	})
+	session.stream = true
+	return session
+}
+
+// readArgs parses the debug-hooks file for the client's arguments.
+func (c *HooksContext) readArgs() (hookArgs, error) {
 	var args hookArgs
-	err = goyaml.Unmarshal(data, &args)
+	data, err := ioutil.ReadFile(c.ClientFileLock())
 	if err != nil {
-		return nil, err
+		return args, err
+	}
+	err = goyaml.Unmarshal(data, &args)
+	return args, err
+}
+
+func newServerSession(c *HooksContext, args hookArgs) *ServerSession {
+	return &ServerSession{
+		HooksContext: c,
+		hooks:        set.NewStrings(args.Hooks...),
+		actions:      set.NewStrings(args.Actions...),
+		relationIds:  args.RelationIds,
 	}
-	hooks := set.NewStrings(args.Hooks...)
-	session := &ServerSession{c, hooks}
-	return session, nil
 }
 
 const debugHooksServerScript = `set -e
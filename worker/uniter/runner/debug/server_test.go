@@ -95,6 +95,59 @@ func (s *DebugHooksServerSuite) TestFindSession(c *gc.C) {
 	c.Assert(session.MatchHook("foo bar baz"), jc.IsFalse)
 }
 
+func (s *DebugHooksServerSuite) TestMatchActionsAndRelations(c *gc.C) {
+	// Only actions named: no hooks match.
+	err := ioutil.WriteFile(s.ctx.ClientFileLock(), []byte(`actions: [backup]`), 0777)
+	c.Assert(err, jc.ErrorIsNil)
+	session, err := s.ctx.FindSession()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(session.MatchHook("install"), jc.IsFalse)
+	c.Assert(session.MatchAction("backup"), jc.IsTrue)
+	c.Assert(session.MatchAction("restore"), jc.IsFalse)
+
+	// No actions named: actions are matched against hooks.
+	err = ioutil.WriteFile(s.ctx.ClientFileLock(), []byte(`hooks: [install, backup]`), 0777)
+	c.Assert(err, jc.ErrorIsNil)
+	session, err = s.ctx.FindSession()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(session.MatchHook("install"), jc.IsTrue)
+	c.Assert(session.MatchAction("backup"), jc.IsTrue)
+	c.Assert(session.MatchAction("restore"), jc.IsFalse)
+
+	// Wildcards for both hooks and actions.
+	err = ioutil.WriteFile(s.ctx.ClientFileLock(), []byte(`{hooks: ["*"], actions: ["*"]}`), 0777)
+	c.Assert(err, jc.ErrorIsNil)
+	session, err = s.ctx.FindSession()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(session.MatchHook("install"), jc.IsTrue)
+	c.Assert(session.MatchAction("restore"), jc.IsTrue)
+	c.Assert(session.MatchRelation(-1), jc.IsTrue)
+
+	// Relation ids restrict matching hooks to those relations.
+	err = ioutil.WriteFile(s.ctx.ClientFileLock(), []byte(`relation-ids: [1, 3]`), 0777)
+	c.Assert(err, jc.ErrorIsNil)
+	session, err = s.ctx.FindSession()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(session.MatchRelation(-1), jc.IsFalse)
+	c.Assert(session.MatchRelation(1), jc.IsTrue)
+	c.Assert(session.MatchRelation(2), jc.IsFalse)
+	c.Assert(session.MatchRelation(3), jc.IsTrue)
+	c.Assert(session.Stream(), jc.IsFalse)
+}
+
+func (s *DebugHooksServerSuite) TestNewStreamSession(c *gc.C) {
+	session := s.ctx.NewStreamSession(ClientArgs{Hooks: []string{"install"}})
+	c.Assert(session.Stream(), jc.IsTrue)
+	c.Assert(session.MatchHook("install"), jc.IsTrue)
+	c.Assert(session.MatchHook("start"), jc.IsFalse)
+	c.Assert(session.MatchAction("backup"), jc.IsFalse)
+
+	// Wildcards are expanded as for tmux sessions.
+	session = s.ctx.NewStreamSession(ClientArgs{Hooks: []string{"start", "*"}})
+	c.Assert(session.MatchHook("start"), jc.IsTrue)
+	c.Assert(session.MatchAction("backup"), jc.IsTrue)
+}
+
 func (s *DebugHooksServerSuite) TestRunHookExceptional(c *gc.C) {
 	err := ioutil.WriteFile(s.ctx.ClientFileLock(), []byte{}, 0777)
 	c.Assert(err, jc.ErrorIsNil)
@@ -187,6 +187,9 @@ func (f *factory) NewHookRunner(hookInfo hook.Info) (Runner, error) {
 		}
 		hookName = fmt.Sprintf("%s-%s", storageName, hookName)
 	}
+	if hookInfo.Kind == hook.ScheduledEvent {
+		ctx.scheduledEvent = hookInfo.EventName
+	}
 	// Metrics are only sent from the collect-metrics hook.
 	if hookInfo.Kind == hooks.CollectMetrics {
 		ch, err := f.getCharm()
@@ -273,6 +273,20 @@ func (s *FactorySuite) TestNewHookRunner(c *gc.C) {
 	s.AssertNotStorageContext(c, ctx)
 }
 
+func (s *FactorySuite) TestNewHookRunnerWithScheduledEvent(c *gc.C) {
+	rnr, err := s.factory.NewHookRunner(hook.Info{
+		Kind:      hook.ScheduledEvent,
+		EventName: "nightly",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.AssertPaths(c, rnr)
+	ctx := rnr.Context()
+	s.AssertCoreContext(c, ctx)
+	s.AssertNotRelationContext(c, ctx)
+	combined := strings.Join(ctx.HookVars(s.paths), "|")
+	c.Assert(combined, gc.Matches, `(^|.*\|)JUJU_SCHEDULED_EVENT=nightly(\|.*|$)`)
+}
+
 func (s *FactorySuite) TestNewHookRunnerWithBadHook(c *gc.C) {
 	rnr, err := s.factory.NewHookRunner(hook.Info{})
 	c.Assert(rnr, gc.IsNil)
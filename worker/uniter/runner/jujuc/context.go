@@ -51,6 +51,7 @@ type HookContext interface {
 	ContextMetrics
 	ContextStorage
 	ContextRelations
+	ContextScheduling
 }
 
 // UnitHookContext is the context for a unit hook.
@@ -156,6 +157,10 @@ type ContextNetworking interface {
 	// unit on its assigned machine. The result is sorted first by
 	// protocol, then by number.
 	OpenedPorts() []network.PortRange
+
+	// NetworkInfo returns the network interfaces and addresses the
+	// executing unit uses for the given binding (an endpoint name).
+	NetworkInfo(binding string) ([]params.NetworkInfo, error)
 }
 
 // ContextLeadership is the part of a hook context related to the
@@ -196,6 +201,19 @@ type ContextStorage interface {
 	AddUnitStorage(map[string]params.StorageConstraints)
 }
 
+// ContextScheduling is the part of a hook context related to deferred
+// events scheduled by the unit.
+type ContextScheduling interface {
+	// ScheduleEvent schedules the named event to be delivered to the
+	// unit at the supplied time and, if cron is not empty, whenever
+	// the cron schedule next activates after each delivery. Any event
+	// previously scheduled with the same name is replaced.
+	ScheduleEvent(name string, due time.Time, cron string) error
+
+	// CancelScheduledEvent cancels the named scheduled event.
+	CancelScheduledEvent(name string) error
+}
+
 // ContextRelations exposes the relations associated with the unit.
 type ContextRelations interface {
 	// Relation returns the relation with the supplied id if it was found, and
@@ -0,0 +1,107 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+)
+
+// NetworkGetCommand implements the network-get command.
+type NetworkGetCommand struct {
+	cmd.CommandBase
+	ctx            Context
+	binding        string
+	primaryAddress bool
+	out            cmd.Output
+}
+
+// NewNetworkGetCommand returns a new NetworkGetCommand with the given context.
+func NewNetworkGetCommand(ctx Context) cmd.Command {
+	return &NetworkGetCommand{ctx: ctx}
+}
+
+// Info is part of the cmd.Command interface.
+func (c *NetworkGetCommand) Info() *cmd.Info {
+	doc := `
+network-get prints the network interfaces and addresses the unit uses for
+the given binding, which is the name of one of the charm's relation
+endpoints. If the endpoint is bound to a network space, only the addresses
+in that space are printed. The CIDR of each address's subnet is included
+when known.
+
+With --primary-address, only the first address is printed; it is the
+address other units should use to reach this unit over the binding.
+`
+	return &cmd.Info{
+		Name:    "network-get",
+		Args:    "<binding>",
+		Purpose: "print network information for a binding",
+		Doc:     doc,
+	}
+}
+
+// SetFlags is part of the cmd.Command interface.
+func (c *NetworkGetCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
+	f.BoolVar(&c.primaryAddress, "primary-address", false, "print only the primary address")
+}
+
+// Init is part of the cmd.Command interface.
+func (c *NetworkGetCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no binding specified")
+	}
+	c.binding = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+// networkInterface defines the serialization behaviour of a network
+// interface.
+type networkInterface struct {
+	InterfaceName string           `yaml:"interface-name,omitempty" json:"interface-name,omitempty"`
+	MACAddress    string           `yaml:"mac-address,omitempty" json:"mac-address,omitempty"`
+	Addresses     []networkAddress `yaml:"addresses" json:"addresses"`
+}
+
+// networkAddress defines the serialization behaviour of an address.
+type networkAddress struct {
+	Address string `yaml:"address" json:"address"`
+	CIDR    string `yaml:"cidr,omitempty" json:"cidr,omitempty"`
+}
+
+// Run is part of the cmd.Command interface.
+func (c *NetworkGetCommand) Run(ctx *cmd.Context) error {
+	info, err := c.ctx.NetworkInfo(c.binding)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if c.primaryAddress {
+		for _, iface := range info {
+			if len(iface.Addresses) > 0 {
+				return c.out.Write(ctx, iface.Addresses[0].Address)
+			}
+		}
+		return errors.Errorf("no addresses for binding %q", c.binding)
+	}
+	interfaces := make([]networkInterface, len(info))
+	for i, iface := range info {
+		interfaces[i] = networkInterface{
+			InterfaceName: iface.InterfaceName,
+			MACAddress:    iface.MACAddress,
+			Addresses:     make([]networkAddress, len(iface.Addresses)),
+		}
+		for j, addr := range iface.Addresses {
+			interfaces[i].Addresses[j] = networkAddress{
+				Address: addr.Address,
+				CIDR:    addr.CIDR,
+			}
+		}
+	}
+	return c.out.Write(ctx, interfaces)
+}
@@ -0,0 +1,106 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+	jujuctesting "github.com/juju/juju/worker/uniter/runner/jujuc/testing"
+)
+
+type NetworkGetSuite struct {
+	ContextSuite
+}
+
+var _ = gc.Suite(&NetworkGetSuite{})
+
+func (s *NetworkGetSuite) newCommand(c *gc.C) (cmd.Command, *jujuctesting.ContextInfo) {
+	hctx, info := s.NewHookContext()
+	info.NetworkInterface.NetworkInfo = map[string][]params.NetworkInfo{
+		"db": {{
+			InterfaceName: "eth1",
+			MACAddress:    "aa:bb:cc:dd:ee:ff",
+			Addresses: []params.NetworkInfoAddress{
+				{Address: "10.0.1.5", CIDR: "10.0.1.0/24"},
+				{Address: "10.0.1.6", CIDR: "10.0.1.0/24"},
+			},
+		}, {
+			Addresses: []params.NetworkInfoAddress{{Address: "192.168.0.5"}},
+		}},
+		"empty": nil,
+	}
+	com, err := jujuc.NewCommand(hctx, cmdString("network-get"))
+	c.Assert(err, jc.ErrorIsNil)
+	return com, info
+}
+
+func (s *NetworkGetSuite) TestInitErrors(c *gc.C) {
+	com, _ := s.newCommand(c)
+	err := testing.InitCommand(com, nil)
+	c.Assert(err, gc.ErrorMatches, "no binding specified")
+
+	com, _ = s.newCommand(c)
+	err = testing.InitCommand(com, []string{"db", "extra"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["extra"\]`)
+}
+
+var networkGetTests = []struct {
+	args []string
+	out  string
+}{{
+	args: []string{"db"},
+	out: `
+- interface-name: eth1
+  mac-address: aa:bb:cc:dd:ee:ff
+  addresses:
+  - address: 10.0.1.5
+    cidr: 10.0.1.0/24
+  - address: 10.0.1.6
+    cidr: 10.0.1.0/24
+- addresses:
+  - address: 192.168.0.5
+`[1:],
+}, {
+	args: []string{"db", "--format", "json"},
+	out: `[{"interface-name":"eth1","mac-address":"aa:bb:cc:dd:ee:ff","addresses":[` +
+		`{"address":"10.0.1.5","cidr":"10.0.1.0/24"},{"address":"10.0.1.6","cidr":"10.0.1.0/24"}]},` +
+		`{"addresses":[{"address":"192.168.0.5"}]}]` + "\n",
+}, {
+	args: []string{"db", "--primary-address"},
+	out:  "10.0.1.5\n",
+}, {
+	args: []string{"empty", "--format", "json"},
+	out:  "[]\n",
+}}
+
+func (s *NetworkGetSuite) TestOutput(c *gc.C) {
+	for i, t := range networkGetTests {
+		c.Logf("test %d: %v", i, t.args)
+		com, _ := s.newCommand(c)
+		ctx := testing.Context(c)
+		code := cmd.Main(com, ctx, t.args)
+		c.Check(code, gc.Equals, 0)
+		c.Check(bufferString(ctx.Stderr), gc.Equals, "")
+		c.Check(bufferString(ctx.Stdout), gc.Equals, t.out)
+	}
+}
+
+func (s *NetworkGetSuite) TestErrors(c *gc.C) {
+	com, _ := s.newCommand(c)
+	ctx := testing.Context(c)
+	code := cmd.Main(com, ctx, []string{"missing"})
+	c.Assert(code, gc.Equals, 1)
+	c.Assert(bufferString(ctx.Stderr), gc.Equals, `error: endpoint "missing" not found`+"\n")
+
+	com, _ = s.newCommand(c)
+	ctx = testing.Context(c)
+	code = cmd.Main(com, ctx, []string{"empty", "--primary-address"})
+	c.Assert(code, gc.Equals, 1)
+	c.Assert(bufferString(ctx.Stderr), gc.Equals, `error: no addresses for binding "empty"`+"\n")
+}
@@ -0,0 +1,135 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc
+
+import (
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/utils/cron"
+)
+
+// scheduleHookCommand implements the schedule-hook command.
+type scheduleHookCommand struct {
+	cmd.CommandBase
+	ctx    Context
+	name   string
+	in     time.Duration
+	at     string
+	cron   string
+	cancel bool
+
+	due      time.Time
+	schedule *cron.Schedule
+}
+
+// NewScheduleHookCommand returns a new scheduleHookCommand with the given context.
+func NewScheduleHookCommand(ctx Context) cmd.Command {
+	return &scheduleHookCommand{ctx: ctx}
+}
+
+// Info is part of the cmd.Command interface.
+func (c *scheduleHookCommand) Info() *cmd.Info {
+	doc := `
+schedule-hook arranges for the scheduled-event hook to run at a later time,
+with JUJU_SCHEDULED_EVENT set to the supplied event name. Events are kept
+by the state server, so they are delivered even if the unit agent restarts
+in the meantime; an event that became due while the agent was down is
+delivered as soon as the agent is running again.
+
+Exactly one of --in, --at or --cron must be given. With --in or --at the
+event is delivered once; with --cron it is delivered whenever the standard
+five-field cron schedule (minute, hour, day of month, month, day of week,
+all in UTC) activates, until it is cancelled.
+
+Scheduling an event with the name of an existing event replaces it, even
+from within the scheduled-event hook that the event triggered. The
+--cancel flag cancels the named event.
+`
+	return &cmd.Info{
+		Name:    "schedule-hook",
+		Args:    "<event name>",
+		Purpose: "schedule a deferred hook",
+		Doc:     doc,
+	}
+}
+
+// SetFlags is part of the cmd.Command interface.
+func (c *scheduleHookCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.DurationVar(&c.in, "in", 0, "deliver the event once, after this duration")
+	f.StringVar(&c.at, "at", "", "deliver the event once, at this RFC3339 time")
+	f.StringVar(&c.cron, "cron", "", "deliver the event according to this cron schedule")
+	f.BoolVar(&c.cancel, "cancel", false, "cancel the event")
+}
+
+// Init is part of the cmd.Command interface.
+func (c *scheduleHookCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no event name specified")
+	}
+	c.name = args[0]
+	if err := cmd.CheckEmpty(args[1:]); err != nil {
+		return err
+	}
+	specified := 0
+	if c.in != 0 {
+		specified++
+	}
+	if c.at != "" {
+		specified++
+	}
+	if c.cron != "" {
+		specified++
+	}
+	if c.cancel {
+		if specified != 0 {
+			return errors.New("--cancel cannot be used with --in, --at or --cron")
+		}
+		return nil
+	}
+	if specified != 1 {
+		return errors.New("exactly one of --in, --at or --cron must be specified")
+	}
+	switch {
+	case c.in < 0:
+		return errors.Errorf("negative duration %v", c.in)
+	case c.at != "":
+		at, err := time.Parse(time.RFC3339, c.at)
+		if err != nil {
+			return errors.Annotate(err, "invalid --at value")
+		}
+		c.due = at.UTC()
+	case c.cron != "":
+		schedule, err := cron.Parse(c.cron)
+		if err != nil {
+			return errors.Annotate(err, "invalid --cron value")
+		}
+		c.schedule = schedule
+	}
+	return nil
+}
+
+// Run is part of the cmd.Command interface.
+func (c *scheduleHookCommand) Run(ctx *cmd.Context) error {
+	if c.cancel {
+		err := c.ctx.CancelScheduledEvent(c.name)
+		return errors.Annotatef(err, "cannot cancel scheduled event %q", c.name)
+	}
+	now := time.Now().UTC()
+	due := c.due
+	switch {
+	case c.in != 0:
+		due = now.Add(c.in)
+	case c.schedule != nil:
+		due = c.schedule.Next(now)
+		if due.IsZero() {
+			return errors.Errorf("cron schedule %q never activates", c.cron)
+		}
+	}
+	err := c.ctx.ScheduleEvent(c.name, due, c.cron)
+	return errors.Annotatef(err, "cannot schedule event %q", c.name)
+}
@@ -0,0 +1,129 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+	jujuctesting "github.com/juju/juju/worker/uniter/runner/jujuc/testing"
+)
+
+type ScheduleHookSuite struct {
+	ContextSuite
+}
+
+var _ = gc.Suite(&ScheduleHookSuite{})
+
+var scheduleHookInitErrorTests = []struct {
+	args []string
+	err  string
+}{{
+	args: nil,
+	err:  "no event name specified",
+}, {
+	args: []string{"nightly"},
+	err:  "exactly one of --in, --at or --cron must be specified",
+}, {
+	args: []string{"nightly", "--in", "1h", "--cron", "@daily"},
+	err:  "exactly one of --in, --at or --cron must be specified",
+}, {
+	args: []string{"nightly", "--cancel", "--in", "1h"},
+	err:  "--cancel cannot be used with --in, --at or --cron",
+}, {
+	args: []string{"nightly", "--in=-1h"},
+	err:  "negative duration -1h0m0s",
+}, {
+	args: []string{"nightly", "--at", "tomorrow"},
+	err:  `invalid --at value: parsing time "tomorrow".*`,
+}, {
+	args: []string{"nightly", "--cron", "0 3 * *"},
+	err:  `invalid --cron value: cron specification "0 3 \* \*" \(expected 5 fields\) not valid`,
+}, {
+	args: []string{"nightly", "extra", "--in", "1h"},
+	err:  `unrecognized args: \["extra"\]`,
+}}
+
+func (s *ScheduleHookSuite) newCommand(c *gc.C) (cmd.Command, *jujuctesting.ContextInfo) {
+	hctx, info := s.NewHookContext()
+	com, err := jujuc.NewCommand(hctx, cmdString("schedule-hook"))
+	c.Assert(err, jc.ErrorIsNil)
+	return com, info
+}
+
+func (s *ScheduleHookSuite) TestInitErrors(c *gc.C) {
+	for i, t := range scheduleHookInitErrorTests {
+		c.Logf("test %d: %v", i, t.args)
+		com, _ := s.newCommand(c)
+		err := testing.InitCommand(com, t.args)
+		c.Check(err, gc.ErrorMatches, t.err)
+	}
+}
+
+func (s *ScheduleHookSuite) TestScheduleAt(c *gc.C) {
+	com, info := s.newCommand(c)
+	ctx := testing.Context(c)
+	code := cmd.Main(com, ctx, []string{"rotate-certs", "--at", "2015-07-01T14:00:00+02:00"})
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(bufferString(ctx.Stderr), gc.Equals, "")
+	c.Assert(info.ScheduledEvents, jc.DeepEquals, map[string]jujuctesting.ScheduledEvent{
+		"rotate-certs": {Due: time.Date(2015, 7, 1, 12, 0, 0, 0, time.UTC)},
+	})
+}
+
+func (s *ScheduleHookSuite) TestScheduleIn(c *gc.C) {
+	com, info := s.newCommand(c)
+	before := time.Now()
+	code := cmd.Main(com, testing.Context(c), []string{"retry", "--in", "10m"})
+	c.Assert(code, gc.Equals, 0)
+	event := info.ScheduledEvents["retry"]
+	c.Assert(event.Cron, gc.Equals, "")
+	c.Assert(event.Due.Before(before.Add(10*time.Minute)), jc.IsFalse)
+	c.Assert(event.Due.After(time.Now().Add(10*time.Minute)), jc.IsFalse)
+}
+
+func (s *ScheduleHookSuite) TestScheduleCron(c *gc.C) {
+	com, info := s.newCommand(c)
+	before := time.Now().UTC()
+	code := cmd.Main(com, testing.Context(c), []string{"nightly", "--cron", "0 3 * * *"})
+	c.Assert(code, gc.Equals, 0)
+	event := info.ScheduledEvents["nightly"]
+	c.Assert(event.Cron, gc.Equals, "0 3 * * *")
+	c.Assert(event.Due.After(before), jc.IsTrue)
+	c.Assert(event.Due.Sub(before) <= 24*time.Hour, jc.IsTrue)
+	c.Assert(event.Due.Hour(), gc.Equals, 3)
+	c.Assert(event.Due.Minute(), gc.Equals, 0)
+}
+
+func (s *ScheduleHookSuite) TestScheduleError(c *gc.C) {
+	com, _ := s.newCommand(c)
+	s.Stub.SetErrors(errors.New("boom"))
+	ctx := testing.Context(c)
+	code := cmd.Main(com, ctx, []string{"retry", "--in", "10m"})
+	c.Assert(code, gc.Equals, 1)
+	c.Assert(bufferString(ctx.Stderr), gc.Equals, `error: cannot schedule event "retry": boom`+"\n")
+}
+
+func (s *ScheduleHookSuite) TestCancel(c *gc.C) {
+	com, info := s.newCommand(c)
+	info.ScheduledEvents = map[string]jujuctesting.ScheduledEvent{
+		"retry": {Due: time.Now()},
+	}
+	code := cmd.Main(com, testing.Context(c), []string{"retry", "--cancel"})
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(info.ScheduledEvents, gc.HasLen, 0)
+	s.Stub.CheckCallNames(c, "CancelScheduledEvent")
+
+	com, _ = s.newCommand(c)
+	ctx := testing.Context(c)
+	code = cmd.Main(com, ctx, []string{"retry", "--cancel"})
+	c.Assert(code, gc.Equals, 1)
+	c.Assert(bufferString(ctx.Stderr), gc.Equals, `error: cannot cancel scheduled event "retry": scheduled event "retry" not found`+"\n")
+}
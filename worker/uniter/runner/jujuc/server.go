@@ -52,6 +52,8 @@ var baseCommands = map[string]creator{
 	"juju-reboot" + cmdSuffix:   NewJujuRebootCommand,
 	"status-get" + cmdSuffix:    NewStatusGetCommand,
 	"status-set" + cmdSuffix:    NewStatusSetCommand,
+	"schedule-hook" + cmdSuffix: NewScheduleHookCommand,
+	"network-get" + cmdSuffix:   NewNetworkGetCommand,
 }
 
 var storageCommands = map[string]creator{
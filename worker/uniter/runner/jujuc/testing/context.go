@@ -19,6 +19,7 @@ type ContextInfo struct {
 	Metrics
 	Storage
 	Relations
+	Scheduling
 	RelationHook
 	ActionHook
 }
@@ -57,6 +58,7 @@ type Context struct {
 	ContextMetrics
 	ContextStorage
 	ContextRelations
+	ContextScheduling
 	ContextRelationHook
 	ContextActionHook
 }
@@ -80,6 +82,8 @@ func NewContext(stub *testing.Stub, info *ContextInfo) *Context {
 	ctx.ContextStorage.info = &info.Storage
 	ctx.ContextRelations.stub = stub
 	ctx.ContextRelations.info = &info.Relations
+	ctx.ContextScheduling.stub = stub
+	ctx.ContextScheduling.info = &info.Scheduling
 	ctx.ContextRelationHook.stub = stub
 	ctx.ContextRelationHook.info = &info.RelationHook
 	ctx.ContextActionHook.stub = stub
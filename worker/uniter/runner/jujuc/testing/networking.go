@@ -8,6 +8,7 @@ import (
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/network"
 )
 
@@ -16,6 +17,9 @@ type NetworkInterface struct {
 	PublicAddress  string
 	PrivateAddress string
 	Ports          []network.PortRange
+
+	// NetworkInfo holds the network information of each binding.
+	NetworkInfo map[string][]params.NetworkInfo
 }
 
 // CheckPorts checks the current ports.
@@ -106,3 +110,17 @@ func (c *ContextNetworking) OpenedPorts() []network.PortRange {
 
 	return c.info.Ports
 }
+
+// NetworkInfo implements jujuc.ContextNetworking.
+func (c *ContextNetworking) NetworkInfo(binding string) ([]params.NetworkInfo, error) {
+	c.stub.AddCall("NetworkInfo", binding)
+	if err := c.stub.NextErr(); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	info, ok := c.info.NetworkInfo[binding]
+	if !ok {
+		return nil, errors.NotFoundf("endpoint %q", binding)
+	}
+	return info, nil
+}
@@ -0,0 +1,55 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package testing
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+)
+
+// ScheduledEvent holds the values for a single scheduled event.
+type ScheduledEvent struct {
+	Due  time.Time
+	Cron string
+}
+
+// Scheduling holds the values for the hook context.
+type Scheduling struct {
+	ScheduledEvents map[string]ScheduledEvent
+}
+
+// ContextScheduling is a test double for jujuc.ContextScheduling.
+type ContextScheduling struct {
+	contextBase
+	info *Scheduling
+}
+
+// ScheduleEvent implements jujuc.ContextScheduling.
+func (c *ContextScheduling) ScheduleEvent(name string, due time.Time, cron string) error {
+	c.stub.AddCall("ScheduleEvent", name, due, cron)
+	if err := c.stub.NextErr(); err != nil {
+		return errors.Trace(err)
+	}
+
+	if c.info.ScheduledEvents == nil {
+		c.info.ScheduledEvents = make(map[string]ScheduledEvent)
+	}
+	c.info.ScheduledEvents[name] = ScheduledEvent{Due: due, Cron: cron}
+	return nil
+}
+
+// CancelScheduledEvent implements jujuc.ContextScheduling.
+func (c *ContextScheduling) CancelScheduledEvent(name string) error {
+	c.stub.AddCall("CancelScheduledEvent", name)
+	if err := c.stub.NextErr(); err != nil {
+		return errors.Trace(err)
+	}
+
+	if _, ok := c.info.ScheduledEvents[name]; !ok {
+		return errors.NotFoundf("scheduled event %q", name)
+	}
+	delete(c.info.ScheduledEvents, name)
+	return nil
+}
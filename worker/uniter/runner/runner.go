@@ -8,12 +8,16 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sync/atomic"
+	"syscall"
+	"time"
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	utilexec "github.com/juju/utils/exec"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/version"
 	"github.com/juju/juju/worker/uniter/runner/debug"
 	"github.com/juju/juju/worker/uniter/runner/jujuc"
@@ -45,6 +49,8 @@ type Context interface {
 	FlushContext(badge string, failure error) error
 	HasExecutionSetUnitStatus() bool
 	ResetExecutionSetUnitStatus()
+	RecordHookExecution(execution params.HookExecution) error
+	DebugHooksSession() (*params.UnitDebugHooksSession, error)
 }
 
 // Paths exposes the paths needed by Runner.
@@ -70,15 +76,26 @@ type Paths interface {
 
 // NewRunner returns a Runner backed by the supplied context and paths.
 func NewRunner(context Context, paths Paths) Runner {
-	return &runner{context, paths}
+	return &runner{context: context, paths: paths}
 }
 
 // runner implements Runner.
 type runner struct {
 	context Context
 	paths   Paths
+
+	// toolCalls counts the hook tool invocations made
+	// through the runner's jujuc server.
+	toolCalls int32
 }
 
+// The kinds of execution recorded by a runner.
+const (
+	executionHook    = "hook"
+	executionAction  = "action"
+	executionCommand = "command"
+)
+
 func (runner *runner) Context() Context {
 	return runner.context
 }
@@ -97,6 +114,7 @@ func (runner *runner) RunCommands(commands string) (*utilexec.ExecResponse, erro
 		WorkingDir:  runner.paths.GetCharmDir(),
 		Environment: env,
 	}
+	started := time.Now()
 	err = command.Run()
 	if err != nil {
 		return nil, err
@@ -105,6 +123,11 @@ func (runner *runner) RunCommands(commands string) (*utilexec.ExecResponse, erro
 
 	// Block and wait for process to finish
 	result, err := command.Wait()
+	exitCode := -1
+	if result != nil {
+		exitCode = result.Code
+	}
+	runner.recordExecution(executionCommand, "", started, exitCode)
 	return result, runner.context.FlushContext("run commands", err)
 }
 
@@ -136,16 +159,114 @@ func (runner *runner) runCharmHookWithLocation(hookName, charmLocation string) e
 		env = mergeWindowsEnvironment(env, os.Environ())
 	}
 
-	debugctx := debug.NewHooksContext(runner.context.UnitName())
-	if session, _ := debugctx.FindSession(); session != nil && session.MatchHook(hookName) {
+	started := time.Now()
+	session := runner.findDebugSession()
+	switch {
+	case session == nil || !runner.matchDebugSession(session, hookName, charmLocation):
+		err = runner.runCharmHook(hookName, env, charmLocation)
+	case session.Stream():
+		logger.Infof("executing %s with debug-hooks streaming", hookName)
+		err = runner.runStreamingCharmHook(hookName, env, charmLocation)
+	default:
 		logger.Infof("executing %s via debug-hooks", hookName)
 		err = session.RunHook(hookName, runner.paths.GetCharmDir(), env)
-	} else {
-		err = runner.runCharmHook(hookName, env, charmLocation)
+	}
+	if !IsMissingHookError(err) {
+		kind := executionHook
+		if charmLocation == "actions" {
+			kind = executionAction
+		}
+		runner.recordExecution(kind, hookName, started, exitCode(err))
 	}
 	return runner.context.FlushContext(hookName, err)
 }
 
+// recordExecution records the timing and outcome of an execution with
+// the context. Failures are logged, but do not affect the execution.
+func (runner *runner) recordExecution(kind, name string, started time.Time, exitCode int) {
+	err := runner.context.RecordHookExecution(params.HookExecution{
+		Kind:      kind,
+		Name:      name,
+		Started:   started,
+		Finished:  time.Now(),
+		ExitCode:  exitCode,
+		ToolCalls: int(atomic.LoadInt32(&runner.toolCalls)),
+	})
+	if errors.IsNotImplemented(err) {
+		logger.Debugf("cannot record %s execution: %v", kind, err)
+	} else if err != nil {
+		logger.Warningf("cannot record %s execution: %v", kind, err)
+	}
+}
+
+// exitCode returns the exit code of a process that finished with the
+// supplied error, or -1 if the process did not exit normally.
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	if exitErr, ok := errors.Cause(err).(*exec.ExitError); ok {
+		if status, ok := exitErr.Sys().(syscall.WaitStatus); ok {
+			return status.ExitStatus()
+		}
+	}
+	return -1
+}
+
+// findDebugSession returns the unit's debug-hooks session: a tmux
+// session started over SSH or, failing that, a streaming session
+// opened through the API. It returns nil if the unit has neither.
+func (runner *runner) findDebugSession() *debug.ServerSession {
+	debugctx := debug.NewHooksContext(runner.context.UnitName())
+	if session, err := debugctx.FindSession(); err == nil {
+		return session
+	}
+	session, err := runner.context.DebugHooksSession()
+	if errors.IsNotImplemented(err) {
+		logger.Debugf("cannot get debug-hooks session: %v", err)
+		return nil
+	} else if err != nil {
+		logger.Warningf("cannot get debug-hooks session: %v", err)
+		return nil
+	}
+	if session == nil {
+		return nil
+	}
+	return debugctx.NewStreamSession(debug.ClientArgs{
+		Hooks:       session.Hooks,
+		Actions:     session.Actions,
+		RelationIds: session.RelationIds,
+	})
+}
+
+// matchDebugSession returns true if the supplied debug-hooks session
+// should intercept the execution of the named hook or action.
+func (runner *runner) matchDebugSession(session *debug.ServerSession, hookName, charmLocation string) bool {
+	if charmLocation == "actions" {
+		return session.MatchAction(hookName)
+	}
+	relationId := -1
+	if relation, found := runner.context.HookRelation(); found {
+		relationId = relation.Id()
+	}
+	return session.MatchHook(hookName) && session.MatchRelation(relationId)
+}
+
+// runStreamingCharmHook runs the named hook as usual, but logs its start
+// and exit status alongside its output; this allows a streaming
+// debug-hooks client to follow its execution through the debug log.
+func (runner *runner) runStreamingCharmHook(hookName string, env []string, charmLocation string) error {
+	hookLogger := runner.getLogger(hookName)
+	hookLogger.Infof("%s started", hookName)
+	err := runner.runCharmHook(hookName, env, charmLocation)
+	if err != nil {
+		hookLogger.Infof("%s failed: %v", hookName, err)
+	} else {
+		hookLogger.Infof("%s completed", hookName)
+	}
+	return err
+}
+
 func (runner *runner) runCharmHook(hookName string, env []string, charmLocation string) error {
 	charmDir := runner.paths.GetCharmDir()
 	hook, err := searchHook(charmDir, filepath.Join(charmLocation, hookName))
@@ -190,6 +311,7 @@ func (runner *runner) startJujucServer() (*jujuc.Server, error) {
 		if ctxId != runner.context.Id() {
 			return nil, errors.Errorf("expected context id %q, got %q", runner.context.Id(), ctxId)
 		}
+		atomic.AddInt32(&runner.toolCalls, 1)
 		return jujuc.NewCommand(runner.context, cmdName)
 	}
 	srv, err := jujuc.NewServer(getCmd, runner.paths.GetJujucSocket())
@@ -18,6 +18,7 @@ import (
 	"github.com/juju/utils"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/worker/uniter/runner"
 )
 
@@ -151,6 +152,26 @@ type MockContext struct {
 	flushBadge   string
 	flushFailure error
 	flushResult  error
+	executions   []params.HookExecution
+}
+
+func (ctx *MockContext) RecordHookExecution(execution params.HookExecution) error {
+	ctx.executions = append(ctx.executions, execution)
+	return nil
+}
+
+func (ctx *MockContext) DebugHooksSession() (*params.UnitDebugHooksSession, error) {
+	return nil, nil
+}
+
+func (ctx *MockContext) assertExecution(c *gc.C, kind, name string, exitCode int) {
+	c.Assert(ctx.executions, gc.HasLen, 1)
+	execution := ctx.executions[0]
+	c.Assert(execution.Kind, gc.Equals, kind)
+	c.Assert(execution.Name, gc.Equals, name)
+	c.Assert(execution.ExitCode, gc.Equals, exitCode)
+	c.Assert(execution.ToolCalls, gc.Equals, 0)
+	c.Assert(execution.Finished.Before(execution.Started), jc.IsFalse)
 }
 
 func (ctx *MockContext) UnitName() string {
@@ -213,6 +234,7 @@ func (s *RunMockContextSuite) TestRunHookFlushSuccess(c *gc.C) {
 	c.Assert(ctx.flushBadge, gc.Equals, "something-happened")
 	c.Assert(ctx.flushFailure, gc.IsNil)
 	s.assertRecordedPid(c, ctx.expectPid)
+	ctx.assertExecution(c, "hook", "something-happened", 0)
 }
 
 func (s *RunMockContextSuite) TestRunHookFlushFailure(c *gc.C) {
@@ -231,6 +253,7 @@ func (s *RunMockContextSuite) TestRunHookFlushFailure(c *gc.C) {
 	c.Assert(ctx.flushBadge, gc.Equals, "something-happened")
 	c.Assert(ctx.flushFailure, gc.ErrorMatches, "exit status 123")
 	s.assertRecordedPid(c, ctx.expectPid)
+	ctx.assertExecution(c, "hook", "something-happened", 123)
 }
 
 func (s *RunMockContextSuite) TestRunActionFlushSuccess(c *gc.C) {
@@ -249,6 +272,7 @@ func (s *RunMockContextSuite) TestRunActionFlushSuccess(c *gc.C) {
 	c.Assert(ctx.flushBadge, gc.Equals, "something-happened")
 	c.Assert(ctx.flushFailure, gc.IsNil)
 	s.assertRecordedPid(c, ctx.expectPid)
+	ctx.assertExecution(c, "action", "something-happened", 0)
 }
 
 func (s *RunMockContextSuite) TestRunActionFlushFailure(c *gc.C) {
@@ -268,6 +292,7 @@ func (s *RunMockContextSuite) TestRunActionFlushFailure(c *gc.C) {
 	c.Assert(ctx.flushBadge, gc.Equals, "something-happened")
 	c.Assert(ctx.flushFailure, gc.ErrorMatches, "exit status 123")
 	s.assertRecordedPid(c, ctx.expectPid)
+	ctx.assertExecution(c, "action", "something-happened", 123)
 }
 
 func (s *RunMockContextSuite) TestRunCommandsFlushSuccess(c *gc.C) {
@@ -280,6 +305,7 @@ func (s *RunMockContextSuite) TestRunCommandsFlushSuccess(c *gc.C) {
 	c.Assert(ctx.flushBadge, gc.Equals, "run commands")
 	c.Assert(ctx.flushFailure, gc.IsNil)
 	s.assertRecordedPid(c, ctx.expectPid)
+	ctx.assertExecution(c, "command", "", 0)
 }
 
 func (s *RunMockContextSuite) TestRunCommandsFlushFailure(c *gc.C) {
@@ -292,4 +318,5 @@ func (s *RunMockContextSuite) TestRunCommandsFlushFailure(c *gc.C) {
 	c.Assert(ctx.flushBadge, gc.Equals, "run commands")
 	c.Assert(ctx.flushFailure, gc.IsNil) // exit code in _ result, as tested elsewhere
 	s.assertRecordedPid(c, ctx.expectPid)
+	ctx.assertExecution(c, "command", "", 123)
 }
@@ -8,6 +8,7 @@ import (
 
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/apiserver/params"
 	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/worker/uniter"
 )
@@ -68,3 +69,27 @@ func (*TimerSuite) testTimer(c *gc.C, s uniter.TimedSignal) {
 		}
 	}
 }
+
+func (*TimerSuite) TestHookRetryDelay(c *gc.C) {
+	policy := params.HookRetryPolicy{
+		MaxAttempts: 10,
+		Delay:       time.Second,
+		MaxDelay:    10 * time.Second,
+	}
+	expected := []time.Duration{
+		time.Second,
+		2 * time.Second,
+		4 * time.Second,
+		8 * time.Second,
+		10 * time.Second,
+		10 * time.Second,
+	}
+	for attempts, delay := range expected {
+		c.Check(uniter.HookRetryDelay(policy, attempts), gc.Equals, delay)
+	}
+
+	// Without a maximum delay the back-off is unbounded.
+	policy.MaxDelay = 0
+	c.Check(uniter.HookRetryDelay(policy, 5), gc.Equals, 32*time.Second)
+	c.Check(uniter.HookRetryDelay(policy, 100) > 0, gc.Equals, true)
+}
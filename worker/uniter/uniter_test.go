@@ -273,6 +273,82 @@ func (s *UniterSuite) TestUniterStartHook(c *gc.C) {
 	})
 }
 
+func (s *UniterSuite) TestUniterHookRetryPolicy(c *gc.C) {
+	s.runUniterTests(c, []uniterTest{
+		ut(
+			"install hook fail retried automatically",
+			createCharm{badHooks: []string{"install"}},
+			serveCharm{},
+			ensureStateWorker{},
+			createServiceAndUnit{},
+			setHookRetryPolicy{
+				MaxAttempts: 2,
+				Delay:       time.Millisecond,
+			},
+			startUniter{},
+			waitAddresses{},
+			waitHooks{"fail-install", "fail-install", "fail-install"},
+			waitUnitAgent{
+				statusGetter: unitStatusGetter,
+				status:       params.StatusError,
+				info:         `hook failed: "install"`,
+				data: map[string]interface{}{
+					"hook": "install",
+				},
+			},
+			waitHooks{},
+
+			fixHook{"install"},
+			resolveError{state.ResolvedRetryHooks},
+			waitUnitAgent{
+				status: params.StatusIdle,
+			},
+			waitHooks(startupHooks(false)),
+			verifyRunning{},
+		), ut(
+			"automatic hook retries counted across restarts",
+			createCharm{badHooks: []string{"install"}},
+			serveCharm{},
+			ensureStateWorker{},
+			createServiceAndUnit{},
+			setHookRetryPolicy{
+				MaxAttempts: 1,
+				Delay:       time.Millisecond,
+			},
+			startUniter{},
+			waitAddresses{},
+			waitHooks{"fail-install", "fail-install"},
+			waitUnitAgent{
+				statusGetter: unitStatusGetter,
+				status:       params.StatusError,
+				info:         `hook failed: "install"`,
+				data: map[string]interface{}{
+					"hook": "install",
+				},
+			},
+			waitHooks{},
+
+			// The retry already made counts against the new policy.
+			stopUniter{},
+			setHookRetryPolicy{
+				MaxAttempts: 3,
+				Delay:       time.Millisecond,
+			},
+			startUniter{},
+			waitHooks{"fail-install", "fail-install"},
+			waitUnitAgent{
+				statusGetter: unitStatusGetter,
+				status:       params.StatusError,
+				info:         `hook failed: "install"`,
+				data: map[string]interface{}{
+					"hook": "install",
+				},
+			},
+			waitHooks{},
+		),
+	})
+}
+
 func (s *UniterSuite) TestUniterMultipleErrors(c *gc.C) {
 	s.runUniterTests(c, []uniterTest{
 		ut(
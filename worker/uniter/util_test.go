@@ -877,6 +877,13 @@ func (s verifyNoActionResults) step(c *gc.C, ctx *context) {
 	c.Assert(result, gc.HasLen, 0)
 }
 
+type setHookRetryPolicy state.HookRetryPolicy
+
+func (s setHookRetryPolicy) step(c *gc.C, ctx *context) {
+	err := ctx.svc.SetHookRetryPolicy(state.HookRetryPolicy(s))
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 type fixHook struct {
 	name string
 }